@@ -118,6 +118,8 @@ var cli struct {
 			Percentage uint8         `default:"10" help:"Experimental: percentage of documents to cleanup."`
 		} `embed:"" prefix:"capped-cleanup-"`
 
+		TTLCleanupInterval time.Duration `default:"1m" help:"Experimental: TTL indexes cleanup interval."`
+
 		EnableNewAuth bool `default:"false" help:"Experimental: enable new authentication."`
 
 		BatchSize            int `default:"100" help:"Experimental: maximum insertion batch size."`
@@ -530,6 +532,7 @@ func run() {
 			EnableNestedPushdown:    cli.Test.EnableNestedPushdown,
 			CappedCleanupInterval:   cli.Test.CappedCleanup.Interval,
 			CappedCleanupPercentage: cli.Test.CappedCleanup.Percentage,
+			TTLCleanupInterval:      cli.Test.TTLCleanupInterval,
 			EnableNewAuth:           cli.Test.EnableNewAuth,
 			BatchSize:               cli.Test.BatchSize,
 			MaxBsonObjectSizeBytes:  cli.Test.MaxBsonObjectSizeMiB * 1024 * 1024,
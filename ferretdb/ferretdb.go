@@ -24,6 +24,7 @@ import (
 	"fmt"
 	"log/slog"
 	"net/url"
+	"time"
 
 	"github.com/FerretDB/FerretDB/build/version"
 	"github.com/FerretDB/FerretDB/internal/clientconn"
@@ -131,6 +132,7 @@ func New(config *Config) (*FerretDB, error) {
 
 		TestOpts: registry.TestOpts{
 			CappedCleanupPercentage: 10,
+			TTLCleanupInterval:      time.Minute,
 			BatchSize:               100,
 		},
 	})
@@ -0,0 +1,186 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/FerretDB/FerretDB/integration/shareddata"
+)
+
+func TestAggregateCompatArithmetic(t *testing.T) {
+	t.Parallel()
+
+	providers := []shareddata.Provider{
+		shareddata.Int32s,
+		shareddata.Int64s,
+		shareddata.Doubles,
+		shareddata.SmallDoubles,
+		shareddata.Nulls,
+		shareddata.Unsets,
+	}
+
+	testCases := map[string]aggregateStagesCompatTestCase{
+		"Add": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$add", bson.A{"$v", int32(1)}}}},
+			}}}},
+		},
+		"AddLong": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$add", bson.A{"$v", int64(1)}}}},
+			}}}},
+		},
+		"AddDouble": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$add", bson.A{"$v", 0.5}}}},
+			}}}},
+		},
+		"AddSelf": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$add", bson.A{"$v", "$v"}}}},
+			}}}},
+		},
+		"AddNull": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$add", bson.A{"$v", nil}}}},
+			}}}},
+		},
+		"AddMissing": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$add", bson.A{"$v", "$missing"}}}},
+			}}}},
+		},
+		"AddNested": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$add", bson.A{"$v", bson.D{{"$multiply", bson.A{"$v", int32(2)}}}}}}},
+			}}}},
+		},
+		"Subtract": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$subtract", bson.A{"$v", int32(1)}}}},
+			}}}},
+		},
+		"SubtractFrom": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$subtract", bson.A{int32(1), "$v"}}}},
+			}}}},
+		},
+		"Multiply": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$multiply", bson.A{"$v", int32(2)}}}},
+			}}}},
+		},
+		"MultiplyDouble": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$multiply", bson.A{"$v", 0.5}}}},
+			}}}},
+		},
+		"Divide": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$divide", bson.A{"$v", int32(2)}}}},
+			}}}},
+		},
+		"Mod": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$mod", bson.A{"$v", int32(5)}}}},
+			}}}},
+		},
+		"ModDouble": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$mod", bson.A{"$v", 2.5}}}},
+			}}}},
+		},
+		"Pow": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$pow", bson.A{"$v", int32(2)}}}},
+			}}}},
+		},
+		"PowZero": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$pow", bson.A{"$v", int32(0)}}}},
+			}}}},
+		},
+		"Abs": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$abs", "$v"}}},
+			}}}},
+		},
+		"Ceil": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$ceil", "$v"}}},
+			}}}},
+		},
+		"Floor": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$floor", "$v"}}},
+			}}}},
+		},
+		"Exp": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$exp", bson.D{{"$divide", bson.A{"$v", 1e300}}}}}},
+			}}}},
+		},
+		"Sqrt": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$sqrt", "$v"}}},
+			}}}},
+		},
+		"Ln": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$ln", "$v"}}},
+			}}}},
+		},
+		"Log": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$log", bson.A{"$v", int32(2)}}}},
+			}}}},
+		},
+		"Log10": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$log10", "$v"}}},
+			}}}},
+		},
+		"Round": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$round", "$v"}}},
+			}}}},
+		},
+		"RoundPlace": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$round", bson.A{"$v", int32(1)}}}},
+			}}}},
+		},
+		"RoundNegativePlace": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$round", bson.A{"$v", int32(-2)}}}},
+			}}}},
+		},
+		"Trunc": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$trunc", "$v"}}},
+			}}}},
+		},
+		"TruncNegativePlace": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$trunc", bson.A{"$v", int32(-1)}}}},
+			}}}},
+		},
+	}
+
+	testAggregateStagesCompatWithProviders(t, providers, testCases)
+}
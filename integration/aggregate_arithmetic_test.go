@@ -0,0 +1,237 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/FerretDB/FerretDB/integration/setup"
+)
+
+func TestAggregateArithmetic(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	date := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	_, err := collection.InsertOne(ctx, bson.D{
+		{"_id", "v"},
+		{"int", int32(7)},
+		{"long", int64(3)},
+		{"double", 2.5},
+		{"decimal", decimal("1.5")},
+		{"date", primitive.NewDateTimeFromTime(date)},
+	})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		expression any
+		expected   any
+	}{
+		"AddIntOverflow":        {bson.D{{"$add", bson.A{int32(math.MaxInt32), int32(1)}}}, int64(math.MaxInt32 + 1)},
+		"AddDecimal":            {bson.D{{"$add", bson.A{"$int", "$decimal"}}}, decimal("8.5")},
+		"AddDate":               {bson.D{{"$add", bson.A{"$date", int32(1000)}}}, primitive.NewDateTimeFromTime(date.Add(time.Second))},
+		"AddEmpty":              {bson.D{{"$add", bson.A{}}}, int32(0)},
+		"SubtractDates":         {bson.D{{"$subtract", bson.A{"$date", "$date"}}}, int64(0)},
+		"SubtractDateNumber":    {bson.D{{"$subtract", bson.A{"$date", int64(1000)}}}, primitive.NewDateTimeFromTime(date.Add(-time.Second))},
+		"MultiplyDecimal":       {bson.D{{"$multiply", bson.A{"$long", "$decimal"}}}, decimal("4.5")},
+		"DivideDecimal":         {bson.D{{"$divide", bson.A{"$decimal", int32(3)}}}, decimal("0.5")},
+		"ModNegative":           {bson.D{{"$mod", bson.A{int32(-7), int32(3)}}}, int32(-1)},
+		"PowIntOverflow":        {bson.D{{"$pow", bson.A{int32(2), int32(40)}}}, int64(1 << 40)},
+		"PowNegativeExponent":   {bson.D{{"$pow", bson.A{int32(2), int32(-1)}}}, 0.5},
+		"AbsIntMin":             {bson.D{{"$abs", int32(math.MinInt32)}}, int64(-math.MinInt32)},
+		"FloorDecimal":          {bson.D{{"$floor", "$decimal"}}, decimal("1")},
+		"RoundDouble":           {bson.D{{"$round", bson.A{1.2345, int32(2)}}}, 1.23},
+		"RoundHalfEven":         {bson.D{{"$round", 2.5}}, 2.0},
+		"RoundIntNegativePlace": {bson.D{{"$round", bson.A{int32(1250), int32(-2)}}}, int32(1200)},
+		"RoundDecimal":          {bson.D{{"$round", "$decimal"}}, decimal("2")},
+		"RoundNullPlace":        {bson.D{{"$round", bson.A{"$double", nil}}}, nil},
+		"TruncIntNegativePlace": {bson.D{{"$trunc", bson.A{int64(1299), int32(-2)}}}, int64(1200)},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			pipeline := bson.A{bson.D{{"$project", bson.D{{"_id", 0}, {"res", tc.expression}}}}}
+
+			cursor, err := collection.Aggregate(ctx, pipeline)
+			require.NoError(t, err)
+
+			var res []bson.D
+			require.NoError(t, cursor.All(ctx, &res))
+			require.Len(t, res, 1)
+
+			assert.Equal(t, bson.D{{"res", tc.expected}}, res[0])
+		})
+	}
+}
+
+func TestAggregateArithmeticErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertOne(ctx, bson.D{
+		{"_id", "v"},
+		{"string", "foo"},
+		{"date", primitive.NewDateTimeFromTime(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))},
+	})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		expression any
+		err        *mongo.CommandError
+	}{
+		"AddString": {
+			expression: bson.D{{"$add", bson.A{int32(1), "$string"}}},
+			err: &mongo.CommandError{
+				Code:    14,
+				Name:    "TypeMismatch",
+				Message: "$add only supports numeric or date types, not string",
+			},
+		},
+		"AddDates": {
+			expression: bson.D{{"$add", bson.A{"$date", "$date"}}},
+			err: &mongo.CommandError{
+				Code:    16612,
+				Name:    "Location16612",
+				Message: "only one date allowed in an $add expression",
+			},
+		},
+		"SubtractDateFromNumber": {
+			expression: bson.D{{"$subtract", bson.A{int32(1), "$date"}}},
+			err: &mongo.CommandError{
+				Code:    14,
+				Name:    "TypeMismatch",
+				Message: "can't $subtract date from int",
+			},
+		},
+		"SubtractArgs": {
+			expression: bson.D{{"$subtract", bson.A{int32(1)}}},
+			err: &mongo.CommandError{
+				Code:    16020,
+				Name:    "Location16020",
+				Message: "Invalid $project :: caused by :: Expression $subtract takes exactly 2 arguments. 1 were passed in.",
+			},
+		},
+		"DivideByZero": {
+			expression: bson.D{{"$divide", bson.A{int32(1), int32(0)}}},
+			err: &mongo.CommandError{
+				Code:    16608,
+				Name:    "Location16608",
+				Message: "can't $divide by zero",
+			},
+		},
+		"DivideString": {
+			expression: bson.D{{"$divide", bson.A{"$string", int32(2)}}},
+			err: &mongo.CommandError{
+				Code:    16609,
+				Name:    "Location16609",
+				Message: "$divide only supports numeric types, not string and int",
+			},
+		},
+		"ModByZero": {
+			expression: bson.D{{"$mod", bson.A{int32(1), 0.0}}},
+			err: &mongo.CommandError{
+				Code:    16610,
+				Name:    "Location16610",
+				Message: "can't $mod by zero",
+			},
+		},
+		"PowZeroNegative": {
+			expression: bson.D{{"$pow", bson.A{int32(0), int32(-1)}}},
+			err: &mongo.CommandError{
+				Code:    28764,
+				Name:    "Location28764",
+				Message: "$pow cannot take a base of 0 and a negative exponent",
+			},
+		},
+		"AbsLongMin": {
+			expression: bson.D{{"$abs", int64(math.MinInt64)}},
+			err: &mongo.CommandError{
+				Code:    28680,
+				Name:    "Location28680",
+				Message: "can't take $abs of long long min",
+			},
+		},
+		"SqrtNegative": {
+			expression: bson.D{{"$sqrt", int32(-1)}},
+			err: &mongo.CommandError{
+				Code:    28714,
+				Name:    "Location28714",
+				Message: "$sqrt's argument must be greater than or equal to 0",
+			},
+		},
+		"LnZero": {
+			expression: bson.D{{"$ln", int32(0)}},
+			err: &mongo.CommandError{
+				Code:    28766,
+				Name:    "Location28766",
+				Message: "$ln's argument must be a positive number, but is 0",
+			},
+		},
+		"LogBaseOne": {
+			expression: bson.D{{"$log", bson.A{int32(2), int32(1)}}},
+			err: &mongo.CommandError{
+				Code:    28759,
+				Name:    "Location28759",
+				Message: "$log's base must be a positive number not equal to 1, but is 1",
+			},
+		},
+		"CeilString": {
+			expression: bson.D{{"$ceil", "$string"}},
+			err: &mongo.CommandError{
+				Code:    28765,
+				Name:    "Location28765",
+				Message: "$ceil only supports numeric types, not string",
+			},
+		},
+		"RoundPlaceOutOfRange": {
+			expression: bson.D{{"$round", bson.A{1.5, int32(101)}}},
+			err: &mongo.CommandError{
+				Code:    51083,
+				Name:    "Location51083",
+				Message: "cannot apply $round with precision value 101 value must be in [-20, 100]",
+			},
+		},
+		"TruncPlaceNotIntegral": {
+			expression: bson.D{{"$trunc", bson.A{1.5, 1.5}}},
+			err: &mongo.CommandError{
+				Code:    51082,
+				Name:    "Location51082",
+				Message: "precision argument to  $trunc must be a integral value",
+			},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			pipeline := bson.A{bson.D{{"$project", bson.D{{"res", tc.expression}}}}}
+
+			_, err := collection.Aggregate(ctx, pipeline)
+			AssertEqualCommandError(t, *tc.err, err)
+		})
+	}
+}
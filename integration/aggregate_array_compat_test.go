@@ -0,0 +1,184 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/FerretDB/FerretDB/integration/shareddata"
+)
+
+func TestAggregateCompatArray(t *testing.T) {
+	t.Parallel()
+
+	providers := []shareddata.Provider{
+		shareddata.ArrayStrings,
+		shareddata.ArrayDoubles,
+		shareddata.ArrayInt32s,
+		shareddata.ArrayDocuments,
+		shareddata.Mixed,
+		shareddata.Nulls,
+	}
+
+	testCases := map[string]aggregateStagesCompatTestCase{
+		"ArrayElemAt": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"first", bson.D{{"$arrayElemAt", bson.A{"$v", int32(0)}}}},
+				{"last", bson.D{{"$arrayElemAt", bson.A{"$v", int64(-1)}}}},
+			}}}},
+		},
+		"ConcatArrays": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$concatArrays", bson.A{"$v", bson.A{"a"}, "$v"}}}},
+			}}}},
+		},
+		"Size": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$size", bson.D{{"$ifNull", bson.A{"$v", bson.A{}}}}}}},
+			}}}},
+		},
+		"IsArray": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$isArray", "$v"}}},
+			}}}},
+		},
+		"ReverseArray": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$reverseArray", "$v"}}},
+			}}}},
+		},
+		"In": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$in", bson.A{int32(42), bson.D{{"$ifNull", bson.A{"$v", bson.A{}}}}}}}},
+			}}}},
+		},
+		"InNull": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$in", bson.A{nil, bson.D{{"$ifNull", bson.A{"$v", bson.A{}}}}}}}},
+			}}}},
+		},
+		"IndexOfArray": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$indexOfArray", bson.A{"$v", int32(42)}}}},
+			}}}},
+		},
+		"IndexOfArrayStart": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$indexOfArray", bson.A{"$v", "b", int32(3)}}}},
+			}}}},
+		},
+		"Slice": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$slice", bson.A{"$v", int32(2)}}}},
+			}}}},
+		},
+		"SliceNegative": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$slice", bson.A{"$v", int32(-2)}}}},
+			}}}},
+		},
+		"SlicePosition": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$slice", bson.A{"$v", int32(1), int32(2)}}}},
+			}}}},
+		},
+		"SortArray": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$sortArray", bson.D{{"input", "$v"}, {"sortBy", int32(1)}}}}},
+			}}}},
+		},
+		"SortArrayDesc": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$sortArray", bson.D{{"input", "$v"}, {"sortBy", int32(-1)}}}}},
+			}}}},
+		},
+		"Zip": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$zip", bson.D{{"inputs", bson.A{"$v", bson.A{"a", "b"}}}}}}},
+			}}}},
+		},
+		"ZipLongest": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$zip", bson.D{
+					{"inputs", bson.A{"$v", bson.A{"a", "b"}}},
+					{"useLongestLength", true},
+				}}}},
+			}}}},
+		},
+		"Map": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$map", bson.D{{"input", "$v"}, {"in", bson.D{{"$type", "$$this"}}}}}}},
+			}}}},
+		},
+		"MapAs": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$map", bson.D{
+					{"input", "$v"},
+					{"as", "elem"},
+					{"in", bson.A{"$$elem", "$_id"}},
+				}}}},
+			}}}},
+		},
+		"Filter": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$filter", bson.D{
+					{"input", "$v"},
+					{"cond", bson.D{{"$ne", bson.A{"$$this", nil}}}},
+				}}}},
+			}}}},
+		},
+		"FilterLimit": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$filter", bson.D{
+					{"input", "$v"},
+					{"as", "elem"},
+					{"cond", bson.D{{"$gte", bson.A{"$$elem", int32(0)}}}},
+					{"limit", int32(1)},
+				}}}},
+			}}}},
+		},
+		"Reduce": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$reduce", bson.D{
+					{"input", "$v"},
+					{"initialValue", bson.A{}},
+					{"in", bson.D{{"$concatArrays", bson.A{bson.A{"$$this"}, "$$value"}}}},
+				}}}},
+			}}}},
+		},
+		"Let": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$let", bson.D{
+					{"vars", bson.D{{"first", bson.D{{"$arrayElemAt", bson.A{"$v", int32(0)}}}}}},
+					{"in", bson.A{"$$first", "$$CURRENT._id"}},
+				}}}},
+			}}}},
+		},
+		"ObjectToArrayRoot": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$objectToArray", "$$ROOT"}}},
+			}}}},
+		},
+		"ArrayToObjectRoundTrip": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$arrayToObject", bson.D{{"$objectToArray", "$$ROOT"}}}}},
+			}}}},
+		},
+	}
+
+	testAggregateStagesCompatWithProviders(t, providers, testCases)
+}
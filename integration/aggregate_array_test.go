@@ -0,0 +1,449 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/FerretDB/FerretDB/integration/setup"
+)
+
+func TestAggregateArray(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertOne(ctx, bson.D{
+		{"_id", "v"},
+		{"arr", bson.A{int32(3), int32(1), int32(2)}},
+		{"docs", bson.A{
+			bson.D{{"name", "b"}, {"n", int32(2)}},
+			bson.D{{"name", "a"}, {"n", int32(1)}},
+			bson.D{{"name", "c"}, {"n", int32(2)}},
+		}},
+		{"obj", bson.D{{"a", int32(1)}, {"b", "x"}}},
+		{"int", int32(42)},
+		{"null", nil},
+	})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		expression any
+		expected   any
+	}{
+		"ArrayElemAtDouble": {bson.D{{"$arrayElemAt", bson.A{"$arr", 2.0}}}, int32(2)},
+		"ArrayElemAtOutside": {
+			bson.D{{"$ifNull", bson.A{bson.D{{"$arrayElemAt", bson.A{"$arr", int32(3)}}}, "missing"}}},
+			"missing",
+		},
+		"ConcatArraysEmpty":  {bson.D{{"$concatArrays", bson.A{}}}, bson.A{}},
+		"IsArrayLiteral":     {bson.D{{"$isArray", bson.A{bson.A{int32(1)}}}}, true},
+		"IsArrayMissing":     {bson.D{{"$isArray", "$missing"}}, false},
+		"InMissing":          {bson.D{{"$in", bson.A{"$missing", bson.A{nil}}}}, false},
+		"IndexOfArrayStart":  {bson.D{{"$indexOfArray", bson.A{bson.A{"a", "b", "a"}, "a", int32(1)}}}, int32(2)},
+		"IndexOfArrayEnd":    {bson.D{{"$indexOfArray", bson.A{bson.A{"a", "b", "a"}, "a", int32(1), int32(2)}}}, int32(-1)},
+		"SliceLarge":         {bson.D{{"$slice", bson.A{"$arr", int32(-10)}}}, bson.A{int32(3), int32(1), int32(2)}},
+		"SliceNegPosition":   {bson.D{{"$slice", bson.A{"$arr", int32(-2), int32(1)}}}, bson.A{int32(1)}},
+		"SlicePositionAfter": {bson.D{{"$slice", bson.A{"$arr", int32(5), int32(1)}}}, bson.A{}},
+		"SliceNull":          {bson.D{{"$slice", bson.A{"$arr", "$null"}}}, nil},
+		"Range": {
+			bson.D{{"$range", bson.A{int32(0), int32(5)}}},
+			bson.A{int32(0), int32(1), int32(2), int32(3), int32(4)},
+		},
+		"RangeStep":         {bson.D{{"$range", bson.A{int32(0), int64(5), 2.0}}}, bson.A{int32(0), int32(2), int32(4)}},
+		"RangeNegativeStep": {bson.D{{"$range", bson.A{int32(5), int32(0), int32(-2)}}}, bson.A{int32(5), int32(3), int32(1)}},
+		"RangeEmpty":        {bson.D{{"$range", bson.A{int32(5), int32(0)}}}, bson.A{}},
+		"SortArrayMixed": {
+			bson.D{{"$sortArray", bson.D{{"input", bson.A{"a", int32(2), nil, 1.5}}, {"sortBy", int32(1)}}}},
+			bson.A{nil, 1.5, int32(2), "a"},
+		},
+		"SortArrayPattern": {
+			bson.D{{"$sortArray", bson.D{{"input", "$docs"}, {"sortBy", bson.D{{"n", int32(-1)}, {"name", int32(1)}}}}}},
+			bson.A{
+				bson.D{{"name", "b"}, {"n", int32(2)}},
+				bson.D{{"name", "c"}, {"n", int32(2)}},
+				bson.D{{"name", "a"}, {"n", int32(1)}},
+			},
+		},
+		"ZipDefaults": {
+			bson.D{{"$zip", bson.D{
+				{"inputs", bson.A{"$arr", bson.A{"a"}}},
+				{"useLongestLength", true},
+				{"defaults", bson.A{int32(0), "z"}},
+			}}},
+			bson.A{bson.A{int32(3), "a"}, bson.A{int32(1), "z"}, bson.A{int32(2), "z"}},
+		},
+		"ZipNull": {bson.D{{"$zip", bson.D{{"inputs", bson.A{"$arr", "$null"}}}}}, nil},
+		"ArrayToObjectPairs": {
+			bson.D{{"$arrayToObject", bson.A{bson.A{bson.A{"a", int32(1)}, bson.A{"b", int32(2)}, bson.A{"a", int32(3)}}}}},
+			bson.D{{"a", int32(3)}, {"b", int32(2)}},
+		},
+		"ArrayToObjectDocuments": {
+			bson.D{{"$arrayToObject", bson.A{bson.A{bson.D{{"k", "a"}, {"v", int32(1)}}, bson.D{{"v", "x"}, {"k", "b"}}}}}},
+			bson.D{{"a", int32(1)}, {"b", "x"}},
+		},
+		"ArrayToObjectEmpty": {bson.D{{"$arrayToObject", bson.A{bson.A{}}}}, bson.D{}},
+		"ArrayToObjectNull":  {bson.D{{"$arrayToObject", "$null"}}, nil},
+		"ObjectToArrayNull":  {bson.D{{"$objectToArray", "$missing"}}, nil},
+		"MapMissing": {
+			bson.D{{"$map", bson.D{{"input", "$docs"}, {"in", "$$this.missing"}}}},
+			bson.A{nil, nil, nil},
+		},
+		"MapNested": {
+			bson.D{{"$map", bson.D{
+				{"input", bson.A{int32(1), int32(2)}},
+				{"as", "x"},
+				{"in", bson.D{{"$map", bson.D{
+					{"input", bson.A{int32(10), int32(20)}},
+					{"in", bson.D{{"$add", bson.A{"$$x", "$$this"}}}},
+				}}}},
+			}}},
+			bson.A{bson.A{int32(11), int32(21)}, bson.A{int32(12), int32(22)}},
+		},
+		"FilterNull": {bson.D{{"$filter", bson.D{{"input", "$missing"}, {"cond", true}}}}, nil},
+		"ReduceEmpty": {
+			bson.D{{"$reduce", bson.D{{"input", bson.A{}}, {"initialValue", "init"}, {"in", "$$this"}}}},
+			"init",
+		},
+		"ReduceNull": {
+			bson.D{{"$reduce", bson.D{{"input", "$null"}, {"initialValue", int32(0)}, {"in", "$$this"}}}},
+			nil,
+		},
+		"LetShadow": {
+			bson.D{{"$let", bson.D{
+				{"vars", bson.D{{"a", int32(1)}}},
+				{"in", bson.D{{"$let", bson.D{
+					{"vars", bson.D{{"a", bson.D{{"$add", bson.A{"$$a", int32(1)}}}}}},
+					{"in", "$$a"},
+				}}}},
+			}}},
+			int32(2),
+		},
+		"LetCurrent": {
+			bson.D{{"$let", bson.D{{"vars", bson.D{{"CURRENT", "$obj"}}}, {"in", "$b"}}}},
+			"x",
+		},
+		"RootSize": {bson.D{{"$size", bson.D{{"$objectToArray", "$$ROOT"}}}}, int32(6)},
+		"Remove": {
+			bson.D{{"$ifNull", bson.A{"$$REMOVE", "removed"}}},
+			"removed",
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			pipeline := bson.A{bson.D{{"$project", bson.D{{"_id", 0}, {"res", tc.expression}}}}}
+
+			cursor, err := collection.Aggregate(ctx, pipeline)
+			require.NoError(t, err)
+
+			var res []bson.D
+			require.NoError(t, cursor.All(ctx, &res))
+			require.Len(t, res, 1)
+
+			assert.Equal(t, bson.D{{"res", tc.expected}}, res[0])
+		})
+	}
+}
+
+func TestAggregateArrayNow(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	docs := make([]any, 1000)
+	for i := range docs {
+		docs[i] = bson.D{{"_id", int32(i)}}
+	}
+
+	_, err := collection.InsertMany(ctx, docs)
+	require.NoError(t, err)
+
+	// $$NOW is the same for all documents and stages
+	pipeline := bson.A{
+		bson.D{{"$set", bson.D{{"a", "$$NOW"}}}},
+		bson.D{{"$sort", bson.D{{"_id", -1}}}},
+		bson.D{{"$set", bson.D{{"b", "$$NOW"}}}},
+		bson.D{{"$group", bson.D{{"_id", bson.D{{"a", "$a"}, {"b", "$b"}}}}}},
+		bson.D{{"$project", bson.D{{"_id", 0}, {"same", bson.D{{"$eq", bson.A{"$_id.a", "$_id.b"}}}}}}},
+	}
+
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	require.NoError(t, err)
+
+	var res []bson.D
+	require.NoError(t, cursor.All(ctx, &res))
+	assert.Equal(t, []bson.D{{{"same", true}}}, res)
+}
+
+func TestAggregateArrayErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertOne(ctx, bson.D{
+		{"_id", "v"},
+		{"arr", bson.A{int32(1), int32(2)}},
+		{"string", "foo"},
+	})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		expression any
+		err        *mongo.CommandError
+	}{
+		"ArrayElemAtNotArray": {
+			expression: bson.D{{"$arrayElemAt", bson.A{"$string", int32(0)}}},
+			err: &mongo.CommandError{
+				Code:    28689,
+				Name:    "Location28689",
+				Message: "$arrayElemAt's first argument must be an array, but is string",
+			},
+		},
+		"ArrayElemAtNotInt": {
+			expression: bson.D{{"$arrayElemAt", bson.A{"$arr", 1.5}}},
+			err: &mongo.CommandError{
+				Code:    28691,
+				Name:    "Location28691",
+				Message: "$arrayElemAt's second argument must be representable as a 32-bit integer: 1.5",
+			},
+		},
+		"ConcatArraysNotArray": {
+			expression: bson.D{{"$concatArrays", bson.A{"$arr", "$string"}}},
+			err: &mongo.CommandError{
+				Code:    28664,
+				Name:    "Location28664",
+				Message: "$concatArrays only supports arrays, not string",
+			},
+		},
+		"SizeMissing": {
+			expression: bson.D{{"$size", "$missing"}},
+			err: &mongo.CommandError{
+				Code:    17124,
+				Name:    "Location17124",
+				Message: "The argument to $size must be an array. Type of argument: missing",
+			},
+		},
+		"InNotArray": {
+			expression: bson.D{{"$in", bson.A{int32(1), "$string"}}},
+			err: &mongo.CommandError{
+				Code:    40081,
+				Name:    "Location40081",
+				Message: "$in requires an array as a second argument, found: string",
+			},
+		},
+		"IndexOfArrayNegativeStart": {
+			expression: bson.D{{"$indexOfArray", bson.A{"$arr", int32(1), int32(-1)}}},
+			err: &mongo.CommandError{
+				Code:    40097,
+				Name:    "Location40097",
+				Message: "$indexOfArray requires a nonnegative starting index, found: -1",
+			},
+		},
+		"SliceNotArray": {
+			expression: bson.D{{"$slice", bson.A{"$string", int32(1)}}},
+			err: &mongo.CommandError{
+				Code:    28724,
+				Name:    "Location28724",
+				Message: "First argument to $slice must be an array, but is of type: string",
+			},
+		},
+		"SliceZeroCount": {
+			expression: bson.D{{"$slice", bson.A{"$arr", int32(0), int32(0)}}},
+			err: &mongo.CommandError{
+				Code:    28729,
+				Name:    "Location28729",
+				Message: "Third argument to $slice must be positive: 0",
+			},
+		},
+		"RangeStartString": {
+			expression: bson.D{{"$range", bson.A{"$string", int32(1)}}},
+			err: &mongo.CommandError{
+				Code:    34443,
+				Name:    "Location34443",
+				Message: "$range requires a numeric starting value, found value of type: string",
+			},
+		},
+		"RangeZeroStep": {
+			expression: bson.D{{"$range", bson.A{int32(0), int32(1), int32(0)}}},
+			err: &mongo.CommandError{
+				Code:    34449,
+				Name:    "Location34449",
+				Message: "$range requires a non-zero step value",
+			},
+		},
+		"ReverseArrayNotArray": {
+			expression: bson.D{{"$reverseArray", "$string"}},
+			err: &mongo.CommandError{
+				Code:    34435,
+				Name:    "Location34435",
+				Message: "The argument to $reverseArray must be an array, but was of type: string",
+			},
+		},
+		"SortArrayMissingSortBy": {
+			expression: bson.D{{"$sortArray", bson.D{{"input", "$arr"}}}},
+			err: &mongo.CommandError{
+				Code:    2942503,
+				Name:    "Location2942503",
+				Message: "Invalid $project :: caused by :: $sortArray requires 'sortBy' to be specified",
+			},
+		},
+		"SortArrayNotArray": {
+			expression: bson.D{{"$sortArray", bson.D{{"input", "$string"}, {"sortBy", int32(1)}}}},
+			err: &mongo.CommandError{
+				Code:    2942504,
+				Name:    "Location2942504",
+				Message: "The input argument to $sortArray must be an array, but was of type: string",
+			},
+		},
+		"ZipDefaultsWithoutLongest": {
+			expression: bson.D{{"$zip", bson.D{{"inputs", bson.A{"$arr"}}, {"defaults", bson.A{int32(0)}}}}},
+			err: &mongo.CommandError{
+				Code:    34466,
+				Name:    "Location34466",
+				Message: "Invalid $project :: caused by :: cannot specify defaults unless useLongestLength is true",
+			},
+		},
+		"ZipInputNotArray": {
+			expression: bson.D{{"$zip", bson.D{{"inputs", bson.A{"$arr", "$string"}}}}},
+			err: &mongo.CommandError{
+				Code:    34468,
+				Name:    "Location34468",
+				Message: `$zip found a non-array expression in input: "foo"`,
+			},
+		},
+		"ArrayToObjectPairSize": {
+			expression: bson.D{{"$arrayToObject", bson.A{bson.A{bson.A{"a", int32(1), int32(2)}}}}},
+			err: &mongo.CommandError{
+				Code:    40397,
+				Name:    "Location40397",
+				Message: "$arrayToObject requires an array of size 2 arrays,found array of size: 3",
+			},
+		},
+		"ArrayToObjectInconsistent": {
+			expression: bson.D{{"$arrayToObject", bson.A{bson.A{bson.A{"a", int32(1)}, bson.D{{"k", "b"}, {"v", int32(2)}}}}}},
+			err: &mongo.CommandError{
+				Code: 40396,
+				Name: "Location40396",
+				Message: "$arrayToObject requires a consistent input format. " +
+					"Elements must all be arrays or all be objects. Array was detected, now found: object",
+			},
+		},
+		"ObjectToArrayNotObject": {
+			expression: bson.D{{"$objectToArray", "$arr"}},
+			err: &mongo.CommandError{
+				Code:    40390,
+				Name:    "Location40390",
+				Message: "$objectToArray requires a document input, found: array",
+			},
+		},
+		"MapMissingIn": {
+			expression: bson.D{{"$map", bson.D{{"input", "$arr"}}}},
+			err: &mongo.CommandError{
+				Code:    16882,
+				Name:    "Location16882",
+				Message: "Invalid $project :: caused by :: Missing 'in' parameter to $map",
+			},
+		},
+		"MapInvalidAs": {
+			expression: bson.D{{"$map", bson.D{{"input", "$arr"}, {"as", "Foo"}, {"in", "$$Foo"}}}},
+			err: &mongo.CommandError{
+				Code:    9,
+				Name:    "FailedToParse",
+				Message: "Invalid $project :: caused by :: 'Foo' starts with an invalid character for a user variable name",
+			},
+		},
+		"MapNotArray": {
+			expression: bson.D{{"$map", bson.D{{"input", "$string"}, {"in", "$$this"}}}},
+			err: &mongo.CommandError{
+				Code:    16883,
+				Name:    "Location16883",
+				Message: "input to $map must be an array not string",
+			},
+		},
+		"FilterLimitZero": {
+			expression: bson.D{{"$filter", bson.D{{"input", "$arr"}, {"cond", true}, {"limit", int32(0)}}}},
+			err: &mongo.CommandError{
+				Code:    327392,
+				Name:    "Location327392",
+				Message: "$filter: limit must be greater than 0: 0",
+			},
+		},
+		"ReduceMissingInitialValue": {
+			expression: bson.D{{"$reduce", bson.D{{"input", "$arr"}, {"in", "$$this"}}}},
+			err: &mongo.CommandError{
+				Code:    40078,
+				Name:    "Location40078",
+				Message: "Invalid $project :: caused by :: $reduce requires 'initialValue' to be specified",
+			},
+		},
+		"ReduceNotArray": {
+			expression: bson.D{{"$reduce", bson.D{{"input", "$string"}, {"initialValue", int32(0)}, {"in", "$$this"}}}},
+			err: &mongo.CommandError{
+				Code:    40080,
+				Name:    "Location40080",
+				Message: `$reduce requires that 'input' be an array, found: "foo"`,
+			},
+		},
+		"LetMissingIn": {
+			expression: bson.D{{"$let", bson.D{{"vars", bson.D{}}}}},
+			err: &mongo.CommandError{
+				Code:    16877,
+				Name:    "Location16877",
+				Message: "Invalid $project :: caused by :: Missing 'in' parameter to $let",
+			},
+		},
+		"LetInvalidName": {
+			expression: bson.D{{"$let", bson.D{{"vars", bson.D{{"a-b", int32(1)}}}, {"in", int32(1)}}}},
+			err: &mongo.CommandError{
+				Code:    9,
+				Name:    "FailedToParse",
+				Message: "Invalid $project :: caused by :: 'a-b' contains an invalid character for a variable name: '-'",
+			},
+		},
+		"UndefinedVariable": {
+			expression: bson.D{{"$add", bson.A{"$$foo", int32(1)}}},
+			err: &mongo.CommandError{
+				Code:    17276,
+				Name:    "Location17276",
+				Message: "Use of undefined variable: foo",
+			},
+		},
+		"UndefinedSystemVariable": {
+			expression: bson.D{{"$add", bson.A{"$$FOO", int32(1)}}},
+			err: &mongo.CommandError{
+				Code:    17276,
+				Name:    "Location17276",
+				Message: "Invalid $project :: caused by :: Use of undefined variable: FOO",
+			},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			pipeline := bson.A{bson.D{{"$project", bson.D{{"res", tc.expression}}}}}
+
+			_, err := collection.Aggregate(ctx, pipeline)
+			AssertEqualCommandError(t, *tc.err, err)
+		})
+	}
+}
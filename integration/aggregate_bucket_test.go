@@ -0,0 +1,395 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/FerretDB/FerretDB/integration/setup"
+)
+
+func TestAggregateBucket(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", int32(1)}, {"price", int32(1)}},
+		bson.D{{"_id", int32(2)}, {"price", 5.5}},
+		bson.D{{"_id", int32(3)}, {"price", int64(10)}},
+		bson.D{{"_id", int32(4)}, {"price", int32(15)}},
+		bson.D{{"_id", int32(5)}, {"price", int32(25)}},
+		bson.D{{"_id", int32(6)}, {"price", int32(30)}},
+		bson.D{{"_id", int32(7)}, {"price", "foo"}},
+		bson.D{{"_id", int32(8)}},
+	})
+	require.NoError(t, err)
+
+	boundaries := bson.A{int32(0), int32(10), int32(20), int32(30)}
+
+	for name, tc := range map[string]struct {
+		spec     bson.D // required
+		expected []bson.D
+		err      *mongo.CommandError
+	}{
+		"Default": {
+			spec: bson.D{{"groupBy", "$price"}, {"boundaries", boundaries}, {"default", "other"}},
+			expected: []bson.D{
+				{{"_id", int32(0)}, {"count", int32(2)}},
+				{{"_id", int32(10)}, {"count", int32(2)}},
+				{{"_id", int32(20)}, {"count", int32(1)}},
+				{{"_id", "other"}, {"count", int32(3)}},
+			},
+		},
+		"Output": {
+			spec: bson.D{
+				{"groupBy", "$price"},
+				{"boundaries", bson.A{int32(0), int32(10), int32(20)}},
+				{"default", int32(100)},
+				{"output", bson.D{
+					{"total", bson.D{{"$sum", "$price"}}},
+					{"ids", bson.D{{"$push", "$_id"}}},
+				}},
+			},
+			expected: []bson.D{
+				{{"_id", int32(0)}, {"total", 6.5}, {"ids", bson.A{int32(1), int32(2)}}},
+				{{"_id", int32(10)}, {"total", int64(25)}, {"ids", bson.A{int32(3), int32(4)}}},
+				{
+					{"_id", int32(100)},
+					{"total", int32(55)},
+					{"ids", bson.A{int32(5), int32(6), int32(7), int32(8)}},
+				},
+			},
+		},
+		"Expression": {
+			spec: bson.D{
+				{"groupBy", bson.D{{"$multiply", bson.A{"$_id", int32(10)}}}},
+				{"boundaries", bson.A{int32(0), int32(50), int32(100)}},
+			},
+			expected: []bson.D{
+				{{"_id", int32(0)}, {"count", int32(4)}},
+				{{"_id", int32(50)}, {"count", int32(4)}},
+			},
+		},
+		"NoMatchingBucket": {
+			spec: bson.D{{"groupBy", "$price"}, {"boundaries", boundaries}},
+			err: &mongo.CommandError{
+				Code:    40066,
+				Name:    "Location40066",
+				Message: "$switch could not find a matching branch for an input, and no default was specified.",
+			},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cursor, err := collection.Aggregate(ctx, bson.A{bson.D{{"$bucket", tc.spec}}})
+			if tc.err != nil {
+				AssertEqualCommandError(t, *tc.err, err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			var res []bson.D
+			require.NoError(t, cursor.All(ctx, &res))
+			assert.Equal(t, tc.expected, res)
+		})
+	}
+}
+
+func TestAggregateBucketAuto(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	docs := make([]any, 10)
+	for i := range docs {
+		docs[i] = bson.D{{"_id", int32(i + 1)}, {"v", int32(i + 1)}}
+	}
+
+	_, err := collection.InsertMany(ctx, docs)
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		spec     bson.D // required
+		expected []bson.D
+	}{
+		"Evenly": {
+			spec: bson.D{{"groupBy", "$v"}, {"buckets", int32(3)}},
+			expected: []bson.D{
+				{{"_id", bson.D{{"min", int32(1)}, {"max", int32(4)}}}, {"count", int32(3)}},
+				{{"_id", bson.D{{"min", int32(4)}, {"max", int32(7)}}}, {"count", int32(3)}},
+				{{"_id", bson.D{{"min", int32(7)}, {"max", int32(10)}}}, {"count", int32(4)}},
+			},
+		},
+		"SameValues": {
+			spec: bson.D{
+				{"groupBy", bson.D{{"$cond", bson.A{bson.D{{"$lte", bson.A{"$v", int32(4)}}}, int32(1), "$v"}}}},
+				{"buckets", int32(4)},
+			},
+			expected: []bson.D{
+				{{"_id", bson.D{{"min", int32(1)}, {"max", int32(5)}}}, {"count", int32(4)}},
+				{{"_id", bson.D{{"min", int32(5)}, {"max", int32(8)}}}, {"count", int32(3)}},
+				{{"_id", bson.D{{"min", int32(8)}, {"max", int32(10)}}}, {"count", int32(3)}},
+			},
+		},
+		"MoreBucketsThanDocuments": {
+			spec: bson.D{{"groupBy", bson.D{{"$mod", bson.A{"$v", int32(2)}}}}, {"buckets", int32(5)}},
+			expected: []bson.D{
+				{{"_id", bson.D{{"min", int32(0)}, {"max", int32(1)}}}, {"count", int32(5)}},
+				{{"_id", bson.D{{"min", int32(1)}, {"max", int32(1)}}}, {"count", int32(5)}},
+			},
+		},
+		"Output": {
+			spec: bson.D{
+				{"groupBy", "$v"},
+				{"buckets", int32(2)},
+				{"output", bson.D{{"avg", bson.D{{"$avg", "$v"}}}}},
+			},
+			expected: []bson.D{
+				{{"_id", bson.D{{"min", int32(1)}, {"max", int32(6)}}}, {"avg", 3.0}},
+				{{"_id", bson.D{{"min", int32(6)}, {"max", int32(10)}}}, {"avg", 8.0}},
+			},
+		},
+		"GranularityR5": {
+			spec: bson.D{{"groupBy", "$v"}, {"buckets", int32(3)}, {"granularity", "R5"}},
+			expected: []bson.D{
+				{{"_id", bson.D{{"min", 0.63}, {"max", 4.0}}}, {"count", int32(3)}},
+				{{"_id", bson.D{{"min", 4.0}, {"max", 6.3}}}, {"count", int32(3)}},
+				{{"_id", bson.D{{"min", 6.3}, {"max", 16.0}}}, {"count", int32(4)}},
+			},
+		},
+		"GranularityE6": {
+			spec: bson.D{{"groupBy", "$v"}, {"buckets", int32(2)}, {"granularity", "E6"}},
+			expected: []bson.D{
+				{{"_id", bson.D{{"min", 0.68}, {"max", 6.8}}}, {"count", int32(6)}},
+				{{"_id", bson.D{{"min", 6.8}, {"max", 15.0}}}, {"count", int32(4)}},
+			},
+		},
+		"GranularityPowersOf2": {
+			spec: bson.D{{"groupBy", "$v"}, {"buckets", int32(3)}, {"granularity", "POWERSOF2"}},
+			expected: []bson.D{
+				{{"_id", bson.D{{"min", 0.5}, {"max", int32(4)}}}, {"count", int32(3)}},
+				{{"_id", bson.D{{"min", int32(4)}, {"max", int32(8)}}}, {"count", int32(4)}},
+				{{"_id", bson.D{{"min", int32(8)}, {"max", int32(16)}}}, {"count", int32(3)}},
+			},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cursor, err := collection.Aggregate(ctx, bson.A{bson.D{{"$bucketAuto", tc.spec}}})
+			require.NoError(t, err)
+
+			var res []bson.D
+			require.NoError(t, cursor.All(ctx, &res))
+			assert.Equal(t, tc.expected, res)
+		})
+	}
+}
+
+func TestAggregateSortByCount(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", int32(1)}, {"tag", "a"}},
+		bson.D{{"_id", int32(2)}, {"tag", "b"}},
+		bson.D{{"_id", int32(3)}, {"tag", "a"}},
+		bson.D{{"_id", int32(4)}, {"tag", "c"}},
+		bson.D{{"_id", int32(5)}, {"tag", "a"}},
+		bson.D{{"_id", int32(6)}, {"tag", "b"}},
+	})
+	require.NoError(t, err)
+
+	cursor, err := collection.Aggregate(ctx, bson.A{bson.D{{"$sortByCount", "$tag"}}})
+	require.NoError(t, err)
+
+	var res []bson.D
+	require.NoError(t, cursor.All(ctx, &res))
+
+	expected := []bson.D{
+		{{"_id", "a"}, {"count", int32(3)}},
+		{{"_id", "b"}, {"count", int32(2)}},
+		{{"_id", "c"}, {"count", int32(1)}},
+	}
+	assert.Equal(t, expected, res)
+
+	cursor, err = collection.Aggregate(ctx, bson.A{
+		bson.D{{"$sortByCount", bson.D{{"$mod", bson.A{"$_id", int32(3)}}}}},
+		bson.D{{"$sort", bson.D{{"count", int32(-1)}, {"_id", int32(1)}}}},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, cursor.All(ctx, &res))
+
+	expected = []bson.D{
+		{{"_id", int32(0)}, {"count", int32(2)}},
+		{{"_id", int32(1)}, {"count", int32(2)}},
+		{{"_id", int32(2)}, {"count", int32(2)}},
+	}
+	assert.Equal(t, expected, res)
+}
+
+func TestAggregateBucketErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertOne(ctx, bson.D{{"_id", int32(1)}, {"v", "foo"}})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		stage bson.D // required
+		err   *mongo.CommandError
+	}{
+		"BucketNotObject": {
+			stage: bson.D{{"$bucket", int32(1)}},
+			err: &mongo.CommandError{
+				Code:    40201,
+				Name:    "Location40201",
+				Message: "Argument to $bucket stage must be an object, but found type: int.",
+			},
+		},
+		"BucketUnknownOption": {
+			stage: bson.D{{"$bucket", bson.D{{"foo", int32(1)}}}},
+			err: &mongo.CommandError{
+				Code:    40197,
+				Name:    "Location40197",
+				Message: "Unrecognized option to $bucket: foo.",
+			},
+		},
+		"BucketMissingBoundaries": {
+			stage: bson.D{{"$bucket", bson.D{{"groupBy", "$v"}}}},
+			err: &mongo.CommandError{
+				Code:    40202,
+				Name:    "Location40202",
+				Message: "$bucket requires 'groupBy' and 'boundaries' to be specified.",
+			},
+		},
+		"BucketTooFewBoundaries": {
+			stage: bson.D{{"$bucket", bson.D{{"groupBy", "$v"}, {"boundaries", bson.A{int32(1)}}}}},
+			err: &mongo.CommandError{
+				Code:    40192,
+				Name:    "Location40192",
+				Message: "The $bucket 'boundaries' field must have at least 2 values, but found 1 value(s).",
+			},
+		},
+		"BucketMixedBoundaries": {
+			stage: bson.D{{"$bucket", bson.D{{"groupBy", "$v"}, {"boundaries", bson.A{int32(1), "a"}}}}},
+			err: &mongo.CommandError{
+				Code: 40193,
+				Name: "Location40193",
+				Message: "All values in the the 'boundaries' option to $bucket must have the same type. " +
+					"Found conflicting types int and string.",
+			},
+		},
+		"BucketUnsortedBoundaries": {
+			stage: bson.D{{"$bucket", bson.D{{"groupBy", "$v"}, {"boundaries", bson.A{int32(2), 1.5}}}}},
+			err: &mongo.CommandError{
+				Code: 40194,
+				Name: "Location40194",
+				Message: "The 'boundaries' option to $bucket must be sorted, but elements 0 and 1 are not in " +
+					"ascending order (2 is not less than 1.5).",
+			},
+		},
+		"BucketDefaultInRange": {
+			stage: bson.D{{"$bucket", bson.D{
+				{"groupBy", "$v"},
+				{"boundaries", bson.A{int32(1), int32(3)}},
+				{"default", int32(2)},
+			}}},
+			err: &mongo.CommandError{
+				Code: 40199,
+				Name: "Location40199",
+				Message: "The $bucket 'default' field must be less than the lowest boundary or " +
+					"greater than or equal to the highest boundary.",
+			},
+		},
+		"BucketAutoNotObject": {
+			stage: bson.D{{"$bucketAuto", "foo"}},
+			err: &mongo.CommandError{
+				Code:    40240,
+				Name:    "Location40240",
+				Message: "The argument to $bucketAuto must be an object, but found type: string.",
+			},
+		},
+		"BucketAutoMissingBuckets": {
+			stage: bson.D{{"$bucketAuto", bson.D{{"groupBy", "$v"}}}},
+			err: &mongo.CommandError{
+				Code:    40246,
+				Name:    "Location40246",
+				Message: "$bucketAuto requires 'groupBy' and 'buckets' to be specified",
+			},
+		},
+		"BucketAutoNegativeBuckets": {
+			stage: bson.D{{"$bucketAuto", bson.D{{"groupBy", "$v"}, {"buckets", int32(-1)}}}},
+			err: &mongo.CommandError{
+				Code:    40243,
+				Name:    "Location40243",
+				Message: "The $bucketAuto 'buckets' field must be greater than 0, but found: -1.",
+			},
+		},
+		"BucketAutoUnknownGranularity": {
+			stage: bson.D{{"$bucketAuto", bson.D{{"groupBy", "$v"}, {"buckets", int32(1)}, {"granularity", "foo"}}}},
+			err: &mongo.CommandError{
+				Code:    40257,
+				Name:    "Location40257",
+				Message: "Unknown rounding granularity 'foo'",
+			},
+		},
+		"BucketAutoGranularityNotNumber": {
+			stage: bson.D{{"$bucketAuto", bson.D{{"groupBy", "$v"}, {"buckets", int32(1)}, {"granularity", "R5"}}}},
+			err: &mongo.CommandError{
+				Code:    40258,
+				Name:    "Location40258",
+				Message: "$bucketAuto can specify a 'granularity' with numeric boundaries only, but found type: string",
+			},
+		},
+		"SortByCountNotPath": {
+			stage: bson.D{{"$sortByCount", "v"}},
+			err: &mongo.CommandError{
+				Code:    40148,
+				Name:    "Location40148",
+				Message: "the sortByCount field must be defined as a $-prefixed path or an expression inside an object",
+			},
+		},
+		"SortByCountInvalidType": {
+			stage: bson.D{{"$sortByCount", int32(1)}},
+			err: &mongo.CommandError{
+				Code:    40149,
+				Name:    "Location40149",
+				Message: "the sortByCount field must be specified as a string or as an object",
+			},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := collection.Aggregate(ctx, bson.A{tc.stage})
+			AssertEqualCommandError(t, *tc.err, err)
+		})
+	}
+}
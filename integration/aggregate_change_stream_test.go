@@ -0,0 +1,287 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/FerretDB/FerretDB/integration/setup"
+)
+
+// setupOpLog creates OpLog collection if it does not exist yet.
+func setupOpLog(t testing.TB, ctx context.Context, client *mongo.Client) {
+	t.Helper()
+
+	opts := options.CreateCollection().SetCapped(true).SetSizeInBytes(512 * 1024 * 1024)
+
+	err := client.Database("local").CreateCollection(ctx, "oplog.rs", opts)
+	if err != nil {
+		var ce mongo.CommandError
+		require.ErrorAs(t, err, &ce)
+		require.Equal(t, int32(48), ce.Code, "%v", err) // NamespaceExists
+	}
+}
+
+// changeEvent represents the subset of the change event fields checked by tests.
+type changeEvent struct {
+	OperationType string `bson:"operationType"`
+	NS            struct {
+		DB   string `bson:"db"`
+		Coll string `bson:"coll"`
+	} `bson:"ns"`
+	DocumentKey       bson.D `bson:"documentKey"`
+	FullDocument      bson.D `bson:"fullDocument"`
+	UpdateDescription struct {
+		UpdatedFields bson.D `bson:"updatedFields"`
+	} `bson:"updateDescription"`
+}
+
+// nextChangeEvent returns the next event of the change stream.
+func nextChangeEvent(t testing.TB, ctx context.Context, stream *mongo.ChangeStream) changeEvent {
+	t.Helper()
+
+	require.True(t, stream.Next(ctx), "%v", stream.Err())
+
+	var event changeEvent
+	require.NoError(t, stream.Decode(&event))
+
+	return event
+}
+
+func TestAggregateChangeStream(t *testing.T) {
+	t.Parallel()
+
+	if setup.IsHana(t) {
+		t.Skip("OpLog is not supported by that backend")
+	}
+
+	t.Run("Collection", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, collection := setup.Setup(t)
+		setupOpLog(t, ctx, collection.Database().Client())
+
+		stream, err := collection.Watch(ctx, mongo.Pipeline{})
+		require.NoError(t, err)
+
+		defer stream.Close(ctx)
+
+		// other collections are not watched
+		_, err = collection.Database().Collection("other").InsertOne(ctx, bson.D{{"_id", "other"}})
+		require.NoError(t, err)
+
+		_, err = collection.InsertOne(ctx, bson.D{{"_id", "one"}, {"v", int32(1)}})
+		require.NoError(t, err)
+
+		_, err = collection.UpdateOne(ctx, bson.D{{"_id", "one"}}, bson.D{{"$set", bson.D{{"v", int32(2)}}}})
+		require.NoError(t, err)
+
+		_, err = collection.DeleteOne(ctx, bson.D{{"_id", "one"}})
+		require.NoError(t, err)
+
+		event := nextChangeEvent(t, ctx, stream)
+		assert.Equal(t, "insert", event.OperationType)
+		assert.Equal(t, collection.Database().Name(), event.NS.DB)
+		assert.Equal(t, collection.Name(), event.NS.Coll)
+		assert.Equal(t, bson.D{{"_id", "one"}}, event.DocumentKey)
+		assert.Equal(t, bson.D{{"_id", "one"}, {"v", int32(1)}}, event.FullDocument)
+
+		event = nextChangeEvent(t, ctx, stream)
+		assert.Equal(t, "update", event.OperationType)
+		assert.Equal(t, bson.D{{"_id", "one"}}, event.DocumentKey)
+		assert.Equal(t, bson.D{{"v", int32(2)}}, event.UpdateDescription.UpdatedFields)
+		assert.Nil(t, event.FullDocument)
+
+		event = nextChangeEvent(t, ctx, stream)
+		assert.Equal(t, "delete", event.OperationType)
+		assert.Equal(t, bson.D{{"_id", "one"}}, event.DocumentKey)
+	})
+
+	t.Run("UpdateLookup", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, collection := setup.Setup(t)
+		setupOpLog(t, ctx, collection.Database().Client())
+
+		opts := options.ChangeStream().SetFullDocument(options.UpdateLookup)
+		stream, err := collection.Watch(ctx, mongo.Pipeline{}, opts)
+		require.NoError(t, err)
+
+		defer stream.Close(ctx)
+
+		_, err = collection.InsertOne(ctx, bson.D{{"_id", "one"}, {"v", int32(1)}})
+		require.NoError(t, err)
+
+		_, err = collection.UpdateOne(ctx, bson.D{{"_id", "one"}}, bson.D{{"$set", bson.D{{"v", int32(2)}}}})
+		require.NoError(t, err)
+
+		event := nextChangeEvent(t, ctx, stream)
+		assert.Equal(t, "insert", event.OperationType)
+
+		event = nextChangeEvent(t, ctx, stream)
+		assert.Equal(t, "update", event.OperationType)
+		assert.Equal(t, bson.D{{"_id", "one"}, {"v", int32(2)}}, event.FullDocument)
+	})
+
+	t.Run("ResumeAfter", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, collection := setup.Setup(t)
+		setupOpLog(t, ctx, collection.Database().Client())
+
+		stream, err := collection.Watch(ctx, mongo.Pipeline{})
+		require.NoError(t, err)
+
+		_, err = collection.InsertMany(ctx, []any{bson.D{{"_id", "one"}}, bson.D{{"_id", "two"}}})
+		require.NoError(t, err)
+
+		event := nextChangeEvent(t, ctx, stream)
+		assert.Equal(t, bson.D{{"_id", "one"}}, event.DocumentKey)
+
+		token := stream.ResumeToken()
+		require.NotNil(t, token)
+		require.NoError(t, stream.Close(ctx))
+
+		for name, opts := range map[string]*options.ChangeStreamOptions{
+			"ResumeAfter": options.ChangeStream().SetResumeAfter(token),
+			"StartAfter":  options.ChangeStream().SetStartAfter(token),
+		} {
+			stream, err = collection.Watch(ctx, mongo.Pipeline{}, opts)
+			require.NoError(t, err, name)
+
+			event = nextChangeEvent(t, ctx, stream)
+			assert.Equal(t, bson.D{{"_id", "two"}}, event.DocumentKey, name)
+
+			require.NoError(t, stream.Close(ctx))
+		}
+	})
+
+	t.Run("Database", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, collection := setup.Setup(t)
+		setupOpLog(t, ctx, collection.Database().Client())
+
+		stream, err := collection.Database().Watch(ctx, mongo.Pipeline{})
+		require.NoError(t, err)
+
+		defer stream.Close(ctx)
+
+		_, err = collection.InsertOne(ctx, bson.D{{"_id", "one"}})
+		require.NoError(t, err)
+
+		_, err = collection.Database().Collection("other").InsertOne(ctx, bson.D{{"_id", "two"}})
+		require.NoError(t, err)
+
+		event := nextChangeEvent(t, ctx, stream)
+		assert.Equal(t, collection.Name(), event.NS.Coll)
+		assert.Equal(t, bson.D{{"_id", "one"}}, event.DocumentKey)
+
+		event = nextChangeEvent(t, ctx, stream)
+		assert.Equal(t, "other", event.NS.Coll)
+		assert.Equal(t, bson.D{{"_id", "two"}}, event.DocumentKey)
+	})
+
+	t.Run("Cluster", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, collection := setup.Setup(t)
+		client := collection.Database().Client()
+		setupOpLog(t, ctx, client)
+
+		// filter out changes made by other tests
+		pipeline := mongo.Pipeline{{{"$match", bson.D{{"ns.db", collection.Database().Name()}}}}}
+
+		stream, err := client.Watch(ctx, pipeline)
+		require.NoError(t, err)
+
+		defer stream.Close(ctx)
+
+		_, err = collection.InsertOne(ctx, bson.D{{"_id", "one"}})
+		require.NoError(t, err)
+
+		event := nextChangeEvent(t, ctx, stream)
+		assert.Equal(t, "insert", event.OperationType)
+		assert.Equal(t, collection.Name(), event.NS.Coll)
+		assert.Equal(t, bson.D{{"_id", "one"}}, event.DocumentKey)
+	})
+}
+
+func TestAggregateChangeStreamErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	for name, tc := range map[string]struct {
+		pipeline bson.A
+		err      *mongo.CommandError
+	}{
+		"NotFirstStage": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{}}},
+				bson.D{{"$changeStream", bson.D{}}},
+			},
+			err: &mongo.CommandError{
+				Code:    40602,
+				Name:    "Location40602",
+				Message: "$changeStream is only valid as the first stage in a pipeline",
+			},
+		},
+		"InvalidResumeToken": {
+			pipeline: bson.A{bson.D{{"$changeStream", bson.D{{"resumeAfter", bson.D{{"_data", "foo"}}}}}}},
+			err: &mongo.CommandError{
+				Code:    260,
+				Name:    "InvalidResumeToken",
+				Message: `Invalid resume token: { _data: "foo" }`,
+			},
+		},
+		"UnknownField": {
+			pipeline: bson.A{bson.D{{"$changeStream", bson.D{{"foo", "bar"}}}}},
+			err: &mongo.CommandError{
+				Code:    40415,
+				Name:    "Location40415",
+				Message: "BSON field '$changeStream.foo' is an unknown field.",
+			},
+		},
+		"AllChangesForCluster": {
+			pipeline: bson.A{bson.D{{"$changeStream", bson.D{{"allChangesForCluster", true}}}}},
+			err: &mongo.CommandError{
+				Code: 73,
+				Name: "InvalidNamespace",
+				Message: "A $changeStream with 'allChangesForCluster:true' may only be opened " +
+					"on the 'admin' database, and with no collection name",
+			},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			res := collection.Database().RunCommand(ctx, bson.D{
+				{"aggregate", collection.Name()},
+				{"pipeline", tc.pipeline},
+				{"cursor", bson.D{}},
+			})
+			AssertEqualCommandError(t, *tc.err, res.Err())
+		})
+	}
+}
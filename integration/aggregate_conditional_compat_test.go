@@ -0,0 +1,164 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestAggregateCompatConditional(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]aggregateStagesCompatTestCase{
+		"CondDocument": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$cond", bson.D{
+					{"if", bson.D{{"$gt", bson.A{"$v", int32(40)}}}},
+					{"then", "big"},
+					{"else", "small"},
+				}}}},
+			}}}},
+		},
+		"CondArray": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$cond", bson.A{"$v", "yes", "no"}}}},
+			}}}},
+		},
+		"CondFieldBranch": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$cond", bson.A{"$v", "$v", "$missing"}}}},
+			}}}},
+		},
+		"Switch": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$switch", bson.D{
+					{"branches", bson.A{
+						bson.D{{"case", bson.D{{"$eq", bson.A{"$v", "foo"}}}}, {"then", int32(1)}},
+						bson.D{{"case", bson.D{{"$eq", bson.A{"$v", int32(42)}}}}, {"then", int32(2)}},
+					}},
+					{"default", "$v"},
+				}}}},
+			}}}},
+		},
+		"IfNull": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$ifNull", bson.A{"$v", "replacement"}}}},
+			}}}},
+		},
+		"IfNullMultiple": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$ifNull", bson.A{"$missing", "$v", nil, "replacement"}}}},
+			}}}},
+		},
+		"Eq": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$eq", bson.A{"$v", int32(42)}}}},
+			}}}},
+		},
+		"EqNull": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$eq", bson.A{"$v", nil}}}},
+			}}}},
+		},
+		"EqArray": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$eq", bson.A{"$v", bson.A{int32(42)}}}}},
+			}}}},
+		},
+		"Ne": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$ne", bson.A{"$v", "foo"}}}},
+			}}}},
+		},
+		"Gt": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$gt", bson.A{"$v", int32(42)}}}},
+			}}}},
+		},
+		"Gte": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$gte", bson.A{"$v", int64(42)}}}},
+			}}}},
+		},
+		"Lt": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$lt", bson.A{"$v", "foo"}}}},
+			}}}},
+		},
+		"Lte": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$lte", bson.A{"$v", 42.13}}}},
+			}}}},
+		},
+		"Cmp": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$cmp", bson.A{"$v", int32(42)}}}},
+			}}}},
+		},
+		"CmpNull": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$cmp", bson.A{"$v", nil}}}},
+			}}}},
+		},
+		"And": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$and", bson.A{"$v", true}}}},
+			}}}},
+		},
+		"Or": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$or", bson.A{"$v", false}}}},
+			}}}},
+		},
+		"Not": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$not", bson.A{"$v"}}}},
+			}}}},
+		},
+		"MatchExprCond": {
+			pipeline: bson.A{bson.D{{"$match", bson.D{
+				{"$expr", bson.D{{"$cond", bson.A{
+					bson.D{{"$ifNull", bson.A{"$v", false}}},
+					bson.D{{"$lt", bson.A{"$v", int32(42)}}},
+					true,
+				}}}},
+			}}}},
+		},
+		"AddFields": {
+			pipeline: bson.A{bson.D{{"$addFields", bson.D{
+				{"big", bson.D{{"$gt", bson.A{"$v", int32(42)}}}},
+			}}}},
+		},
+		"GroupSwitch": {
+			pipeline: bson.A{
+				bson.D{{"$group", bson.D{
+					{"_id", bson.D{{"$switch", bson.D{
+						{"branches", bson.A{
+							bson.D{{"case", bson.D{{"$lt", bson.A{"$v", int32(0)}}}}, {"then", "low"}},
+							bson.D{{"case", bson.D{{"$lt", bson.A{"$v", int32(100)}}}}, {"then", "medium"}},
+						}},
+						{"default", "high"},
+					}}}},
+					{"count", bson.D{{"$sum", int32(1)}}},
+				}}},
+				bson.D{{"$sort", bson.D{{"_id", 1}}}},
+			},
+		},
+	}
+
+	testAggregateStagesCompat(t, testCases)
+}
@@ -0,0 +1,243 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/FerretDB/FerretDB/integration/setup"
+)
+
+func TestAggregateConditional(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertOne(ctx, bson.D{
+		{"_id", "v"},
+		{"int", int32(42)},
+		{"string", "foo"},
+		{"null", nil},
+		{"array", bson.A{int32(1), int32(2)}},
+	})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		expression any
+		expected   bson.D
+	}{
+		"CondMissingBranch": {
+			expression: bson.D{{"$cond", bson.A{true, "$missing", "no"}}},
+			expected:   bson.D{},
+		},
+		"EqArrayElement": {
+			expression: bson.D{{"$eq", bson.A{"$array", int32(1)}}},
+			expected:   bson.D{{"res", false}},
+		},
+		"EqMissingNull": {
+			expression: bson.D{{"$eq", bson.A{"$missing", nil}}},
+			expected:   bson.D{{"res", false}},
+		},
+		"GtTypes": {
+			expression: bson.D{{"$gt", bson.A{"$string", "$int"}}},
+			expected:   bson.D{{"res", true}},
+		},
+		"LtNull": {
+			expression: bson.D{{"$lt", bson.A{"$null", int32(0)}}},
+			expected:   bson.D{{"res", true}},
+		},
+		"AndEmpty": {
+			expression: bson.D{{"$and", bson.A{}}},
+			expected:   bson.D{{"res", true}},
+		},
+		"OrEmpty": {
+			expression: bson.D{{"$or", bson.A{}}},
+			expected:   bson.D{{"res", false}},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			pipeline := bson.A{bson.D{{"$project", bson.D{{"_id", 0}, {"res", tc.expression}}}}}
+
+			cursor, err := collection.Aggregate(ctx, pipeline)
+			require.NoError(t, err)
+
+			var res []bson.D
+			require.NoError(t, cursor.All(ctx, &res))
+			require.Len(t, res, 1)
+
+			assert.Equal(t, tc.expected, res[0])
+		})
+	}
+}
+
+func TestAggregateConditionalStages(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", int32(1)}, {"v", int32(10)}},
+		bson.D{{"_id", int32(2)}, {"v", int32(20)}},
+		bson.D{{"_id", int32(3)}, {"v", int32(30)}},
+		bson.D{{"_id", int32(4)}},
+	})
+	require.NoError(t, err)
+
+	t.Run("FindExpr", func(t *testing.T) {
+		t.Parallel()
+
+		filter := bson.D{{"$expr", bson.D{{"$and", bson.A{
+			bson.D{{"$gte", bson.A{"$v", int32(20)}}},
+			bson.D{{"$ne", bson.A{"$_id", int32(3)}}},
+		}}}}}
+
+		cursor, err := collection.Find(ctx, filter)
+		require.NoError(t, err)
+
+		var res []bson.D
+		require.NoError(t, cursor.All(ctx, &res))
+		assert.Equal(t, []bson.D{{{"_id", int32(2)}, {"v", int32(20)}}}, res)
+	})
+
+	t.Run("SwitchNoMatch", func(t *testing.T) {
+		t.Parallel()
+
+		pipeline := bson.A{
+			bson.D{{"$project", bson.D{{"res", bson.D{{"$switch", bson.D{
+				{"branches", bson.A{bson.D{{"case", bson.D{{"$eq", bson.A{"$v", int32(10)}}}}, {"then", int32(1)}}}},
+			}}}}}}},
+		}
+
+		_, err := collection.Aggregate(ctx, pipeline)
+		AssertEqualCommandError(t, mongo.CommandError{
+			Code:    40066,
+			Name:    "Location40066",
+			Message: "$switch could not find a matching branch for an input, and no default was specified.",
+		}, err)
+	})
+}
+
+func TestAggregateConditionalErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertOne(ctx, bson.D{{"_id", "v"}})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		expression any
+		err        *mongo.CommandError
+	}{
+		"CondArgs": {
+			expression: bson.D{{"$cond", bson.A{true, int32(1)}}},
+			err: &mongo.CommandError{
+				Code:    16020,
+				Name:    "Location16020",
+				Message: "Invalid $project :: caused by :: Expression $cond takes exactly 3 arguments. 2 were passed in.",
+			},
+		},
+		"CondMissingIf": {
+			expression: bson.D{{"$cond", bson.D{{"then", int32(1)}, {"else", int32(2)}}}},
+			err: &mongo.CommandError{
+				Code:    17080,
+				Name:    "Location17080",
+				Message: "Invalid $project :: caused by :: Missing 'if' parameter to $cond",
+			},
+		},
+		"CondUnknownParameter": {
+			expression: bson.D{{"$cond", bson.D{{"if", true}, {"foo", int32(1)}}}},
+			err: &mongo.CommandError{
+				Code:    17083,
+				Name:    "Location17083",
+				Message: "Invalid $project :: caused by :: Unrecognized parameter to $cond: foo",
+			},
+		},
+		"SwitchNotObject": {
+			expression: bson.D{{"$switch", "foo"}},
+			err: &mongo.CommandError{
+				Code:    40060,
+				Name:    "Location40060",
+				Message: "Invalid $project :: caused by :: $switch requires an object as an argument, found: string",
+			},
+		},
+		"SwitchNoBranches": {
+			expression: bson.D{{"$switch", bson.D{{"branches", bson.A{}}}}},
+			err: &mongo.CommandError{
+				Code:    40068,
+				Name:    "Location40068",
+				Message: "Invalid $project :: caused by :: $switch requires at least one branch.",
+			},
+		},
+		"SwitchMissingCase": {
+			expression: bson.D{{"$switch", bson.D{{"branches", bson.A{bson.D{{"then", int32(1)}}}}}}},
+			err: &mongo.CommandError{
+				Code:    40064,
+				Name:    "Location40064",
+				Message: "Invalid $project :: caused by :: $switch requires each branch have a 'case' expression",
+			},
+		},
+		"SwitchUnknownArgument": {
+			expression: bson.D{{"$switch", bson.D{{"foo", int32(1)}}}},
+			err: &mongo.CommandError{
+				Code:    40067,
+				Name:    "Location40067",
+				Message: "Invalid $project :: caused by :: $switch found an unknown argument: foo",
+			},
+		},
+		"IfNullArgs": {
+			expression: bson.D{{"$ifNull", bson.A{int32(1)}}},
+			err: &mongo.CommandError{
+				Code:    1257300,
+				Name:    "Location1257300",
+				Message: "Invalid $project :: caused by :: $ifNull needs at least two arguments, had: 1",
+			},
+		},
+		"EqArgs": {
+			expression: bson.D{{"$eq", bson.A{int32(1)}}},
+			err: &mongo.CommandError{
+				Code:    16020,
+				Name:    "Location16020",
+				Message: "Invalid $project :: caused by :: Expression $eq takes exactly 2 arguments. 1 were passed in.",
+			},
+		},
+		"NotArgs": {
+			expression: bson.D{{"$not", bson.A{}}},
+			err: &mongo.CommandError{
+				Code:    16020,
+				Name:    "Location16020",
+				Message: "Invalid $project :: caused by :: Expression $not takes exactly 1 arguments. 0 were passed in.",
+			},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			pipeline := bson.A{bson.D{{"$project", bson.D{{"res", tc.expression}}}}}
+
+			_, err := collection.Aggregate(ctx, pipeline)
+			AssertEqualCommandError(t, *tc.err, err)
+		})
+	}
+}
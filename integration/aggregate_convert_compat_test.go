@@ -0,0 +1,120 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/FerretDB/FerretDB/integration/shareddata"
+)
+
+func TestAggregateCompatConvert(t *testing.T) {
+	t.Parallel()
+
+	// convert returns `onError` value instead of conversion errors, so all providers could be used
+	convert := func(to string) bson.A {
+		return bson.A{bson.D{{"$project", bson.D{
+			{"res", bson.D{{"$convert", bson.D{
+				{"input", "$v"},
+				{"to", to},
+				{"onError", "error"},
+				{"onNull", "null"},
+			}}}},
+		}}}}
+	}
+
+	testCases := map[string]aggregateStagesCompatTestCase{
+		"ConvertDouble":   {pipeline: convert("double")},
+		"ConvertString":   {pipeline: convert("string")},
+		"ConvertObjectID": {pipeline: convert("objectId")},
+		"ConvertBool":     {pipeline: convert("bool")},
+		"ConvertDate":     {pipeline: convert("date")},
+		"ConvertInt":      {pipeline: convert("int")},
+		"ConvertLong":     {pipeline: convert("long")},
+		"ConvertDecimal":  {pipeline: convert("decimal")},
+		"ConvertTypeCode": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$convert", bson.D{{"input", "$v"}, {"to", int32(8)}, {"onError", "error"}}}}},
+			}}}},
+		},
+		"ConvertNoOnNull": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$convert", bson.D{{"input", "$v"}, {"to", "bool"}, {"onError", "error"}}}}},
+			}}}},
+		},
+		"IsNumber": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$isNumber", "$v"}}},
+			}}}},
+		},
+	}
+
+	testAggregateStagesCompat(t, testCases)
+}
+
+func TestAggregateCompatConvertShorthand(t *testing.T) {
+	t.Parallel()
+
+	providers := []shareddata.Provider{
+		shareddata.Int32s,
+		shareddata.Int64s,
+		shareddata.SmallDoubles,
+		shareddata.Bools,
+		shareddata.Nulls,
+		shareddata.Unsets,
+	}
+
+	testCases := map[string]aggregateStagesCompatTestCase{
+		"ToBool": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$toBool", "$v"}}},
+			}}}},
+		},
+		"ToDouble": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$toDouble", "$v"}}},
+			}}}},
+		},
+		"ToLong": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$toLong", "$v"}}},
+			}}}},
+		},
+		"ToDecimal": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$toDecimal", "$v"}}},
+			}}}},
+		},
+		"ToString": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$toString", "$v"}}},
+			}}}},
+		},
+		"ToInt": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$toInt", "$v"}}},
+			}}}},
+		},
+		"ToDate": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$toDate", "$v"}}},
+			}}}},
+		},
+	}
+
+	testAggregateStagesCompatWithProviders(t, providers, testCases)
+}
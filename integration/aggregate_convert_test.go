@@ -0,0 +1,290 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/FerretDB/FerretDB/integration/setup"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+func TestAggregateConvert(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	date := time.Date(2024, 1, 2, 3, 4, 5, 6_000_000, time.UTC)
+	id := primitive.ObjectID{0x65, 0x93, 0x7d, 0x9d, 0x0b, 0xad, 0xc0, 0xff, 0xee, 0x00, 0x00, 0x01}
+
+	_, err := collection.InsertOne(ctx, bson.D{
+		{"_id", "v"},
+		{"int", int32(42)},
+		{"decimal", must.NotFail(primitive.ParseDecimal128("12.5"))},
+		{"numString", "42"},
+		{"oid", id},
+	})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		expression any
+		expected   any
+	}{
+		"ConvertIntToStringNum": {bson.D{{"$convert", bson.D{{"input", "$int"}, {"to", int32(2)}}}}, "42"},
+		"ConvertToDouble":       {bson.D{{"$convert", bson.D{{"input", "$int"}, {"to", 1.0}}}}, float64(42)},
+		"ConvertNullTo":         {bson.D{{"$convert", bson.D{{"input", "$int"}, {"to", "$missing"}}}}, nil},
+		"ConvertOnErrorUnsupported": {
+			bson.D{{"$convert", bson.D{{"input", "$oid"}, {"to", "int"}, {"onError", int32(-1)}}}},
+			int32(-1),
+		},
+		"ConvertToSameType": {bson.D{{"$convert", bson.D{{"input", "$oid"}, {"to", "objectId"}}}}, id},
+
+		"ToBoolDecimal":  {bson.D{{"$toBool", must.NotFail(primitive.ParseDecimal128("0.0"))}}, false},
+		"ToBoolArray":    {bson.D{{"$toBool", bson.A{bson.A{}}}}, true},
+		"ToDateDouble":   {bson.D{{"$toDate", 1704164645006.9}}, primitive.NewDateTimeFromTime(date)},
+		"ToDateString":   {bson.D{{"$toDate", "2024-01-02T03:04:05.006Z"}}, primitive.NewDateTimeFromTime(date)},
+		"ToDateOffset":   {bson.D{{"$toDate", "2024-01-02T05:04:05.006+02:00"}}, primitive.NewDateTimeFromTime(date)},
+		"ToDecimalDbl":   {bson.D{{"$toDecimal", 2.5}}, must.NotFail(primitive.ParseDecimal128("2.50000000000000"))},
+		"ToDecimalStr":   {bson.D{{"$toDecimal", "1.23"}}, must.NotFail(primitive.ParseDecimal128("1.23"))},
+		"ToDoubleDec":    {bson.D{{"$toDouble", "$decimal"}}, 12.5},
+		"ToDoubleString": {bson.D{{"$toDouble", "-1.5e3"}}, -1500.0},
+		"ToIntDecimal":   {bson.D{{"$toInt", "$decimal"}}, int32(12)},
+		"ToIntString":    {bson.D{{"$toInt", "-17"}}, int32(-17)},
+		"ToIntArrayArg":  {bson.D{{"$toInt", bson.A{"$numString"}}}, int32(42)},
+		"ToLongString":   {bson.D{{"$toLong", "9007199254740993"}}, int64(9007199254740993)},
+		"ToObjectId":     {bson.D{{"$toObjectId", "65937d9d0badc0ffee000001"}}, id},
+		"ToObjectIdUp":   {bson.D{{"$toObjectId", "65937D9D0BADC0FFEE000001"}}, id},
+		"ToStringLarge":  {bson.D{{"$toString", 123456789.0}}, "1.23457e+08"},
+		"ToStringDec":    {bson.D{{"$toString", "$decimal"}}, "12.5"},
+
+		"IsNumberDecimal": {bson.D{{"$isNumber", "$decimal"}}, true},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			pipeline := bson.A{bson.D{{"$project", bson.D{{"_id", 0}, {"res", tc.expression}}}}}
+
+			cursor, err := collection.Aggregate(ctx, pipeline)
+			require.NoError(t, err)
+
+			var res []bson.D
+			require.NoError(t, cursor.All(ctx, &res))
+			require.Len(t, res, 1)
+
+			assert.Equal(t, bson.D{{"res", tc.expected}}, res[0])
+		})
+	}
+}
+
+func TestAggregateConvertErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertOne(ctx, bson.D{
+		{"_id", "v"},
+		{"int", int32(42)},
+		{"string", "foo"},
+		{"date", primitive.NewDateTimeFromTime(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))},
+	})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		expression any
+		err        *mongo.CommandError
+	}{
+		"ConvertNotObject": {
+			expression: bson.D{{"$convert", "$int"}},
+			err: &mongo.CommandError{
+				Code:    9,
+				Name:    "FailedToParse",
+				Message: "Invalid $project :: caused by :: $convert expects an object of named arguments but found: string",
+			},
+		},
+		"ConvertUnknownArgument": {
+			expression: bson.D{{"$convert", bson.D{{"input", "$int"}, {"to", "int"}, {"foo", 1}}}},
+			err: &mongo.CommandError{
+				Code:    9,
+				Name:    "FailedToParse",
+				Message: "Invalid $project :: caused by :: $convert found an unknown argument: foo",
+			},
+		},
+		"ConvertMissingTo": {
+			expression: bson.D{{"$convert", bson.D{{"input", "$int"}}}},
+			err: &mongo.CommandError{
+				Code:    9,
+				Name:    "FailedToParse",
+				Message: "Invalid $project :: caused by :: Missing 'to' parameter to $convert",
+			},
+		},
+		"ConvertUnknownType": {
+			expression: bson.D{{"$convert", bson.D{{"input", "$int"}, {"to", "foo"}}}},
+			err: &mongo.CommandError{
+				Code:    2,
+				Name:    "BadValue",
+				Message: "Unknown type name: foo",
+			},
+		},
+		"ConvertInvalidTypeCode": {
+			expression: bson.D{{"$convert", bson.D{{"input", "$int"}, {"to", int32(100)}}}},
+			err: &mongo.CommandError{
+				Code:    9,
+				Name:    "FailedToParse",
+				Message: "In $convert, numeric value for 'to' does not correspond to a BSON type: 100",
+			},
+		},
+		"ConvertNonIntegerTypeCode": {
+			expression: bson.D{{"$convert", bson.D{{"input", "$int"}, {"to", 1.5}}}},
+			err: &mongo.CommandError{
+				Code:    9,
+				Name:    "FailedToParse",
+				Message: "In $convert, numeric 'to' argument is not an integer",
+			},
+		},
+		"ConvertInvalidTo": {
+			expression: bson.D{{"$convert", bson.D{{"input", "$int"}, {"to", true}}}},
+			err: &mongo.CommandError{
+				Code:    9,
+				Name:    "FailedToParse",
+				Message: "$convert's 'to' argument must be a string or number, but is bool",
+			},
+		},
+		"ConvertUnsupported": {
+			expression: bson.D{{"$convert", bson.D{{"input", "$int"}, {"to", "array"}}}},
+			err: &mongo.CommandError{
+				Code:    241,
+				Name:    "ConversionFailure",
+				Message: "Unsupported conversion from int to array in $convert with no onError value",
+			},
+		},
+		"ToIntString": {
+			expression: bson.D{{"$toInt", "$string"}},
+			err: &mongo.CommandError{
+				Code:    241,
+				Name:    "ConversionFailure",
+				Message: "Failed to parse number 'foo' in $convert with no onError value: Did not consume whole string.",
+			},
+		},
+		"ToIntOverflow": {
+			expression: bson.D{{"$toInt", int64(math.MaxInt32) + 1}},
+			err: &mongo.CommandError{
+				Code:    241,
+				Name:    "ConversionFailure",
+				Message: "Conversion would overflow target type in $convert with no onError value: 2147483648",
+			},
+		},
+		"ToIntStringOverflow": {
+			expression: bson.D{{"$toInt", "2147483648"}},
+			err: &mongo.CommandError{
+				Code:    241,
+				Name:    "ConversionFailure",
+				Message: "Failed to parse number '2147483648' in $convert with no onError value: Overflow",
+			},
+		},
+		"ToIntDate": {
+			expression: bson.D{{"$toInt", "$date"}},
+			err: &mongo.CommandError{
+				Code:    241,
+				Name:    "ConversionFailure",
+				Message: "Unsupported conversion from date to int in $convert with no onError value",
+			},
+		},
+		"ToLongInfinity": {
+			expression: bson.D{{"$toLong", math.Inf(-1)}},
+			err: &mongo.CommandError{
+				Code:    241,
+				Name:    "ConversionFailure",
+				Message: "Attempt to convert infinity value to integer type in $convert with no onError value",
+			},
+		},
+		"ToDateInt": {
+			expression: bson.D{{"$toDate", "$int"}},
+			err: &mongo.CommandError{
+				Code:    241,
+				Name:    "ConversionFailure",
+				Message: "Unsupported conversion from int to date in $convert with no onError value",
+			},
+		},
+		"ToDateString": {
+			expression: bson.D{{"$toDate", "$string"}},
+			err: &mongo.CommandError{
+				Code:    241,
+				Name:    "ConversionFailure",
+				Message: "Error parsing date string 'foo'",
+			},
+		},
+		"ToDecimalString": {
+			expression: bson.D{{"$toDecimal", "$string"}},
+			err: &mongo.CommandError{
+				Code:    241,
+				Name:    "ConversionFailure",
+				Message: "Failed to parse number 'foo' in $convert with no onError value: Failed to parse string to decimal",
+			},
+		},
+		"ToObjectIdLength": {
+			expression: bson.D{{"$toObjectId", "$string"}},
+			err: &mongo.CommandError{
+				Code: 241,
+				Name: "ConversionFailure",
+				Message: "Failed to parse objectId 'foo' in $convert with no onError value: " +
+					"Invalid string length for parsing to OID, expected 24 but found 3",
+			},
+		},
+		"ToObjectIdChar": {
+			expression: bson.D{{"$toObjectId", "65937d9d0badc0ffee00000g"}},
+			err: &mongo.CommandError{
+				Code: 241,
+				Name: "ConversionFailure",
+				Message: "Failed to parse objectId '65937d9d0badc0ffee00000g' in $convert with no onError value: " +
+					"Invalid character found in hex string: 'g'",
+			},
+		},
+		"ToStringArray": {
+			expression: bson.D{{"$toString", bson.A{bson.A{}}}},
+			err: &mongo.CommandError{
+				Code:    241,
+				Name:    "ConversionFailure",
+				Message: "Unsupported conversion from array to string in $convert with no onError value",
+			},
+		},
+		"ToStringTooManyArgs": {
+			expression: bson.D{{"$toString", bson.A{"$int", "$int"}}},
+			err: &mongo.CommandError{
+				Code:    16020,
+				Name:    "Location16020",
+				Message: "Invalid $project :: caused by :: Expression $toString takes exactly 1 arguments. 2 were passed in.",
+			},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			pipeline := bson.A{bson.D{{"$project", bson.D{{"res", tc.expression}}}}}
+
+			_, err := collection.Aggregate(ctx, pipeline)
+			AssertEqualCommandError(t, *tc.err, err)
+		})
+	}
+}
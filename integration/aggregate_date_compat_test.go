@@ -0,0 +1,160 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/FerretDB/FerretDB/integration/shareddata"
+)
+
+func TestAggregateCompatDate(t *testing.T) {
+	t.Parallel()
+
+	providers := []shareddata.Provider{
+		shareddata.DateTimes,
+		shareddata.Nulls,
+		shareddata.Unsets,
+	}
+
+	start := primitive.NewDateTimeFromTime(time.Date(2000, 1, 31, 0, 0, 0, 0, time.UTC))
+
+	testCases := map[string]aggregateStagesCompatTestCase{
+		"DateParts": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"year", bson.D{{"$year", "$v"}}},
+				{"month", bson.D{{"$month", "$v"}}},
+				{"dayOfMonth", bson.D{{"$dayOfMonth", "$v"}}},
+				{"dayOfWeek", bson.D{{"$dayOfWeek", "$v"}}},
+				{"dayOfYear", bson.D{{"$dayOfYear", "$v"}}},
+				{"hour", bson.D{{"$hour", "$v"}}},
+				{"minute", bson.D{{"$minute", "$v"}}},
+				{"second", bson.D{{"$second", "$v"}}},
+				{"millisecond", bson.D{{"$millisecond", "$v"}}},
+			}}}},
+		},
+		"WeekParts": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"week", bson.D{{"$week", "$v"}}},
+				{"isoWeek", bson.D{{"$isoWeek", "$v"}}},
+				{"isoWeekYear", bson.D{{"$isoWeekYear", "$v"}}},
+				{"isoDayOfWeek", bson.D{{"$isoDayOfWeek", "$v"}}},
+			}}}},
+		},
+		"HourTimezone": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$hour", bson.D{{"date", "$v"}, {"timezone", "America/New_York"}}}}},
+			}}}},
+		},
+		"HourOffset": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$hour", bson.D{{"date", "$v"}, {"timezone", "+05:30"}}}}},
+			}}}},
+		},
+		"DateToString": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$dateToString", bson.D{{"date", "$v"}}}}},
+			}}}},
+		},
+		"DateToStringFormat": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$dateToString", bson.D{
+					{"date", "$v"},
+					{"format", "%Y/%m/%d %H:%M:%S.%L %z"},
+					{"timezone", "Asia/Kolkata"},
+				}}}},
+			}}}},
+		},
+		"DateToStringOnNull": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$dateToString", bson.D{{"date", "$v"}, {"onNull", "none"}}}}},
+			}}}},
+		},
+		"DateToParts": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$dateToParts", bson.D{{"date", "$v"}}}}},
+			}}}},
+		},
+		"DateToPartsISO": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$dateToParts", bson.D{
+					{"date", "$v"},
+					{"timezone", "America/New_York"},
+					{"iso8601", true},
+				}}}},
+			}}}},
+		},
+		"DateTruncMonth": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$dateTrunc", bson.D{{"date", "$v"}, {"unit", "month"}}}}},
+			}}}},
+		},
+		"DateTruncWeek": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$dateTrunc", bson.D{{"date", "$v"}, {"unit", "week"}}}}},
+			}}}},
+		},
+		"DateTruncDayTimezone": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$dateTrunc", bson.D{
+					{"date", "$v"},
+					{"unit", "day"},
+					{"timezone", "America/New_York"},
+				}}}},
+			}}}},
+		},
+		"DateTruncHourBin": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$dateTrunc", bson.D{{"date", "$v"}, {"unit", "hour"}, {"binSize", int32(6)}}}}},
+			}}}},
+		},
+		"DateAddMonth": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$dateAdd", bson.D{{"startDate", "$v"}, {"unit", "month"}, {"amount", int32(-1)}}}}},
+			}}}},
+		},
+		"DateAddHour": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$dateAdd", bson.D{{"startDate", "$v"}, {"unit", "hour"}, {"amount", int64(2)}}}}},
+			}}}},
+		},
+		"DateSubtractDay": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$dateSubtract", bson.D{{"startDate", "$v"}, {"unit", "day"}, {"amount", int32(10)}}}}},
+			}}}},
+		},
+		"DateDiffMonth": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$dateDiff", bson.D{{"startDate", start}, {"endDate", "$v"}, {"unit", "month"}}}}},
+			}}}},
+		},
+		"DateDiffDay": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$dateDiff", bson.D{{"startDate", start}, {"endDate", "$v"}, {"unit", "day"}}}}},
+			}}}},
+		},
+		"DateDiffWeek": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$dateDiff", bson.D{{"startDate", "$v"}, {"endDate", start}, {"unit", "week"}}}}},
+			}}}},
+		},
+	}
+
+	testAggregateStagesCompatWithProviders(t, providers, testCases)
+}
@@ -0,0 +1,302 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/FerretDB/FerretDB/integration/setup"
+)
+
+func TestAggregateDate(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	// Sunday, the day when daylight saving time starts in America/New_York
+	date := time.Date(2024, 3, 10, 15, 4, 5, 678_000_000, time.UTC)
+
+	dt := func(t time.Time) primitive.DateTime {
+		return primitive.NewDateTimeFromTime(t)
+	}
+
+	_, err := collection.InsertOne(ctx, bson.D{
+		{"_id", "v"},
+		{"date", dt(date)},
+		{"start", dt(time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC))},
+		{"ts", primitive.Timestamp{T: 1_700_000_000, I: 1}},
+		{"tz", "America/New_York"},
+		{"null", nil},
+	})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		expression any
+		expected   any
+	}{
+		"YearArray":          {bson.D{{"$year", bson.A{"$date"}}}, int32(2024)},
+		"YearTimestamp":      {bson.D{{"$year", "$ts"}}, int32(2023)},
+		"YearNull":           {bson.D{{"$year", "$null"}}, nil},
+		"HourNegativeOffset": {bson.D{{"$hour", bson.D{{"date", "$date"}, {"timezone", "-0800"}}}}, int32(7)},
+		"HourTimezoneNull":   {bson.D{{"$hour", bson.D{{"date", "$date"}, {"timezone", "$null"}}}}, nil},
+		"DateToStringTimestamp": {
+			bson.D{{"$dateToString", bson.D{{"date", "$ts"}}}},
+			"2023-11-14T22:13:20.000Z",
+		},
+		"DateToStringSpecifiers": {
+			bson.D{{"$dateToString", bson.D{{"date", "$date"}, {"format", "%j %U %V %G %u %w %Z %%"}}}},
+			"070 10 10 2024 7 1 +0 %",
+		},
+		"DateFromString": {
+			bson.D{{"$dateFromString", bson.D{{"dateString", "2024-03-10T15:04:05.678Z"}}}},
+			dt(date),
+		},
+		"DateFromStringOffset": {
+			bson.D{{"$dateFromString", bson.D{{"dateString", "2024-03-10T12:00:00+02:00"}}}},
+			dt(time.Date(2024, 3, 10, 10, 0, 0, 0, time.UTC)),
+		},
+		"DateFromStringTimezone": {
+			bson.D{{"$dateFromString", bson.D{{"dateString", "2024-03-10"}, {"timezone", "$tz"}}}},
+			dt(time.Date(2024, 3, 10, 5, 0, 0, 0, time.UTC)),
+		},
+		"DateFromStringFormat": {
+			bson.D{{"$dateFromString", bson.D{{"dateString", "10/03/2024"}, {"format", "%d/%m/%Y"}}}},
+			dt(time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)),
+		},
+		"DateFromStringOnError": {
+			bson.D{{"$dateFromString", bson.D{{"dateString", "foo"}, {"onError", "bad"}}}},
+			"bad",
+		},
+		"DateFromStringOnNull": {
+			bson.D{{"$dateFromString", bson.D{{"dateString", "$null"}, {"onNull", "none"}}}},
+			"none",
+		},
+		"DateFromParts": {
+			bson.D{{"$dateFromParts", bson.D{{"year", int32(2024)}, {"month", int32(2)}, {"day", int32(30)}}}},
+			dt(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)),
+		},
+		"DateFromPartsTimezone": {
+			bson.D{{"$dateFromParts", bson.D{
+				{"year", int32(2024)}, {"month", int32(3)}, {"day", int32(10)}, {"hour", int32(12)}, {"timezone", "+02"},
+			}}},
+			dt(time.Date(2024, 3, 10, 10, 0, 0, 0, time.UTC)),
+		},
+		"DateFromPartsISO": {
+			bson.D{{"$dateFromParts", bson.D{{"isoWeekYear", int32(2024)}, {"isoWeek", int32(10)}, {"isoDayOfWeek", int32(7)}}}},
+			dt(time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)),
+		},
+		"DateTruncQuarter": {
+			bson.D{{"$dateTrunc", bson.D{{"date", "$date"}, {"unit", "quarter"}}}},
+			dt(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)),
+		},
+		"DateTruncYearBin": {
+			bson.D{{"$dateTrunc", bson.D{{"date", "$date"}, {"unit", "year"}, {"binSize", int32(5)}}}},
+			dt(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)),
+		},
+		"DateTruncWeekMonday": {
+			bson.D{{"$dateTrunc", bson.D{{"date", "$date"}, {"unit", "week"}, {"startOfWeek", "Monday"}}}},
+			dt(time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)),
+		},
+		"DateTruncMinuteBin": {
+			bson.D{{"$dateTrunc", bson.D{{"date", "$date"}, {"unit", "minute"}, {"binSize", int64(15)}}}},
+			dt(time.Date(2024, 3, 10, 15, 0, 0, 0, time.UTC)),
+		},
+		"DateAddMonthClamp": {
+			bson.D{{"$dateAdd", bson.D{{"startDate", "$start"}, {"unit", "month"}, {"amount", int32(1)}}}},
+			dt(time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)),
+		},
+		"DateAddDayTimezone": {
+			bson.D{{"$dateAdd", bson.D{
+				{"startDate", dt(time.Date(2024, 3, 9, 12, 0, 0, 0, time.UTC))},
+				{"unit", "day"},
+				{"amount", 1.0},
+				{"timezone", "$tz"},
+			}}},
+			dt(time.Date(2024, 3, 10, 11, 0, 0, 0, time.UTC)),
+		},
+		"DateAddNull": {
+			bson.D{{"$dateAdd", bson.D{{"startDate", "$date"}, {"unit", "hour"}, {"amount", "$null"}}}},
+			nil,
+		},
+		"DateDiffYearNegative": {
+			bson.D{{"$dateDiff", bson.D{{"startDate", "$date"}, {"endDate", "$ts"}, {"unit", "year"}}}},
+			int64(-1),
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			pipeline := bson.A{bson.D{{"$project", bson.D{{"_id", 0}, {"res", tc.expression}}}}}
+
+			cursor, err := collection.Aggregate(ctx, pipeline)
+			require.NoError(t, err)
+
+			var res []bson.D
+			require.NoError(t, cursor.All(ctx, &res))
+			require.Len(t, res, 1)
+
+			assert.Equal(t, bson.D{{"res", tc.expected}}, res[0])
+		})
+	}
+}
+
+func TestAggregateDateErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertOne(ctx, bson.D{
+		{"_id", "v"},
+		{"date", primitive.NewDateTimeFromTime(time.Date(2024, 3, 10, 15, 4, 5, 0, time.UTC))},
+		{"string", "foo"},
+	})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		expression any
+		err        *mongo.CommandError
+	}{
+		"YearString": {
+			expression: bson.D{{"$year", "$string"}},
+			err: &mongo.CommandError{
+				Code:    16006,
+				Name:    "Location16006",
+				Message: "can't convert from BSON type string to Date",
+			},
+		},
+		"YearUnknownOption": {
+			expression: bson.D{{"$year", bson.D{{"date", "$date"}, {"foo", int32(1)}}}},
+			err: &mongo.CommandError{
+				Code:    40535,
+				Name:    "Location40535",
+				Message: `Invalid $project :: caused by :: unrecognized option to $year: "foo"`,
+			},
+		},
+		"TimezoneUnknown": {
+			expression: bson.D{{"$hour", bson.D{{"date", "$date"}, {"timezone", "Mars/Base"}}}},
+			err: &mongo.CommandError{
+				Code:    40485,
+				Name:    "Location40485",
+				Message: `unrecognized time zone identifier: "Mars/Base"`,
+			},
+		},
+		"TimezoneInt": {
+			expression: bson.D{{"$hour", bson.D{{"date", "$date"}, {"timezone", int32(5)}}}},
+			err: &mongo.CommandError{
+				Code:    40533,
+				Name:    "Location40533",
+				Message: "$hour requires a string for the timezone argument, but was given a int (5)",
+			},
+		},
+		"DateToStringInvalidFormat": {
+			expression: bson.D{{"$dateToString", bson.D{{"date", "$date"}, {"format", "%Y-%q"}}}},
+			err: &mongo.CommandError{
+				Code:    18536,
+				Name:    "Location18536",
+				Message: "Invalid format character '%q' in format string",
+			},
+		},
+		"DateToStringUnmatchedPercent": {
+			expression: bson.D{{"$dateToString", bson.D{{"date", "$date"}, {"format", "%Y%"}}}},
+			err: &mongo.CommandError{
+				Code:    18535,
+				Name:    "Location18535",
+				Message: "Unmatched '%' at end of format string",
+			},
+		},
+		"DateFromStringInvalid": {
+			expression: bson.D{{"$dateFromString", bson.D{{"dateString", "$string"}}}},
+			err: &mongo.CommandError{
+				Code:    241,
+				Name:    "ConversionFailure",
+				Message: "Error parsing date string 'foo'",
+			},
+		},
+		"DateToPartsUnknownArgument": {
+			expression: bson.D{{"$dateToParts", bson.D{{"date", "$date"}, {"foo", int32(1)}}}},
+			err: &mongo.CommandError{
+				Code:    40520,
+				Name:    "Location40520",
+				Message: "Invalid $project :: caused by :: Unrecognized argument to $dateToParts: foo",
+			},
+		},
+		"DateFromPartsMixed": {
+			expression: bson.D{{"$dateFromParts", bson.D{{"year", int32(2024)}, {"isoWeek", int32(1)}}}},
+			err: &mongo.CommandError{
+				Code:    40489,
+				Name:    "Location40489",
+				Message: "Invalid $project :: caused by :: $dateFromParts does not allow mixing natural dates with ISO dates",
+			},
+		},
+		"DateFromPartsOutOfRange": {
+			expression: bson.D{{"$dateFromParts", bson.D{{"year", int32(10000)}}}},
+			err: &mongo.CommandError{
+				Code:    40523,
+				Name:    "Location40523",
+				Message: "'year' must evaluate to an integer in the range 1 to 9999, found 10000",
+			},
+		},
+		"DateTruncInvalidUnit": {
+			expression: bson.D{{"$dateTrunc", bson.D{{"date", "$date"}, {"unit", "century"}}}},
+			err: &mongo.CommandError{
+				Code:    9,
+				Name:    "FailedToParse",
+				Message: "$dateTrunc parameter 'unit' value cannot be recognized as a time unit: century",
+			},
+		},
+		"DateTruncBinSizeZero": {
+			expression: bson.D{{"$dateTrunc", bson.D{{"date", "$date"}, {"unit", "day"}, {"binSize", int32(0)}}}},
+			err: &mongo.CommandError{
+				Code:    5439018,
+				Name:    "Location5439018",
+				Message: "$dateTrunc requires 'binSize' to be greater than 0, but got value 0",
+			},
+		},
+		"DateAddAmountNotIntegral": {
+			expression: bson.D{{"$dateAdd", bson.D{{"startDate", "$date"}, {"unit", "day"}, {"amount", 1.5}}}},
+			err: &mongo.CommandError{
+				Code:    5166405,
+				Name:    "Location5166405",
+				Message: "$dateAdd expects integer amount of time units",
+			},
+		},
+		"DateDiffMissingUnit": {
+			expression: bson.D{{"$dateDiff", bson.D{{"startDate", "$date"}, {"endDate", "$date"}}}},
+			err: &mongo.CommandError{
+				Code:    5166305,
+				Name:    "Location5166305",
+				Message: "Invalid $project :: caused by :: Missing 'unit' parameter to $dateDiff",
+			},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			pipeline := bson.A{bson.D{{"$project", bson.D{{"res", tc.expression}}}}}
+
+			_, err := collection.Aggregate(ctx, pipeline)
+			AssertEqualCommandError(t, *tc.err, err)
+		})
+	}
+}
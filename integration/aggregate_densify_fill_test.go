@@ -0,0 +1,329 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/FerretDB/FerretDB/integration/setup"
+)
+
+func TestAggregateDensify(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	day := func(d int) primitive.DateTime {
+		return primitive.NewDateTimeFromTime(time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC))
+	}
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", int32(1)}, {"s", "a"}, {"v", int32(1)}, {"t", day(1)}},
+		bson.D{{"_id", int32(2)}, {"s", "a"}, {"v", int32(5)}, {"t", day(4)}},
+		bson.D{{"_id", int32(3)}, {"s", "b"}, {"v", int32(2)}, {"t", day(2)}},
+		bson.D{{"_id", int32(4)}, {"s", "b"}, {"v", int32(4)}, {"t", day(3)}},
+	})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		pipeline bson.A // required
+		expected []bson.D
+	}{
+		"Full": {
+			pipeline: bson.A{
+				bson.D{{"$project", bson.D{{"_id", int32(0)}, {"s", int32(1)}, {"v", int32(1)}}}},
+				bson.D{{"$densify", bson.D{
+					{"field", "v"},
+					{"partitionByFields", bson.A{"s"}},
+					{"range", bson.D{{"step", int32(2)}, {"bounds", "full"}}},
+				}}},
+			},
+			expected: []bson.D{
+				{{"s", "a"}, {"v", int32(1)}},
+				{{"v", int32(3)}, {"s", "a"}},
+				{{"s", "a"}, {"v", int32(5)}},
+				{{"v", int32(1)}, {"s", "b"}},
+				{{"s", "b"}, {"v", int32(2)}},
+				{{"v", int32(3)}, {"s", "b"}},
+				{{"s", "b"}, {"v", int32(4)}},
+				{{"v", int32(5)}, {"s", "b"}},
+			},
+		},
+		"Partition": {
+			pipeline: bson.A{
+				bson.D{{"$project", bson.D{{"_id", int32(0)}, {"s", int32(1)}, {"v", int32(1)}}}},
+				bson.D{{"$densify", bson.D{
+					{"field", "v"},
+					{"partitionByFields", bson.A{"s"}},
+					{"range", bson.D{{"step", int32(1)}, {"bounds", "partition"}}},
+				}}},
+			},
+			expected: []bson.D{
+				{{"s", "a"}, {"v", int32(1)}},
+				{{"v", int32(2)}, {"s", "a"}},
+				{{"v", int32(3)}, {"s", "a"}},
+				{{"v", int32(4)}, {"s", "a"}},
+				{{"s", "a"}, {"v", int32(5)}},
+				{{"s", "b"}, {"v", int32(2)}},
+				{{"v", int32(3)}, {"s", "b"}},
+				{{"s", "b"}, {"v", int32(4)}},
+			},
+		},
+		"ExplicitBounds": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"s", "a"}}}},
+				bson.D{{"$project", bson.D{{"_id", int32(0)}, {"v", int32(1)}}}},
+				bson.D{{"$densify", bson.D{
+					{"field", "v"},
+					{"range", bson.D{{"step", int32(2)}, {"bounds", bson.A{int32(0), int32(6)}}}},
+				}}},
+			},
+			expected: []bson.D{
+				{{"v", int32(0)}},
+				{{"v", int32(1)}},
+				{{"v", int32(2)}},
+				{{"v", int32(4)}},
+				{{"v", int32(5)}},
+			},
+		},
+		"Dates": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"s", "a"}}}},
+				bson.D{{"$project", bson.D{{"_id", int32(0)}, {"t", int32(1)}}}},
+				bson.D{{"$densify", bson.D{
+					{"field", "t"},
+					{"range", bson.D{{"step", int32(1)}, {"unit", "day"}, {"bounds", "full"}}},
+				}}},
+			},
+			expected: []bson.D{
+				{{"t", day(1)}},
+				{{"t", day(2)}},
+				{{"t", day(3)}},
+				{{"t", day(4)}},
+			},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cursor, err := collection.Aggregate(ctx, tc.pipeline)
+			require.NoError(t, err)
+
+			var res []bson.D
+			require.NoError(t, cursor.All(ctx, &res))
+			assert.Equal(t, tc.expected, res)
+		})
+	}
+}
+
+func TestAggregateFill(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", int32(1)}, {"s", "a"}, {"v", int32(10)}, {"c", "x"}},
+		bson.D{{"_id", int32(2)}, {"s", "a"}},
+		bson.D{{"_id", int32(3)}, {"s", "a"}, {"v", int32(30)}, {"c", nil}},
+		bson.D{{"_id", int32(4)}, {"s", "b"}, {"c", "y"}},
+		bson.D{{"_id", int32(5)}, {"s", "b"}, {"v", int32(50)}},
+	})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		pipeline bson.A // required
+		expected []bson.D
+	}{
+		"Value": {
+			pipeline: bson.A{
+				bson.D{{"$sort", bson.D{{"_id", int32(1)}}}},
+				bson.D{{"$fill", bson.D{{"output", bson.D{{"c", bson.D{{"value", "none"}}}}}}}},
+				bson.D{{"$project", bson.D{{"c", int32(1)}}}},
+			},
+			expected: []bson.D{
+				{{"_id", int32(1)}, {"c", "x"}},
+				{{"_id", int32(2)}, {"c", "none"}},
+				{{"_id", int32(3)}, {"c", "none"}},
+				{{"_id", int32(4)}, {"c", "y"}},
+				{{"_id", int32(5)}, {"c", "none"}},
+			},
+		},
+		"Locf": {
+			pipeline: bson.A{
+				bson.D{{"$fill", bson.D{
+					{"partitionByFields", bson.A{"s"}},
+					{"sortBy", bson.D{{"_id", int32(1)}}},
+					{"output", bson.D{{"c", bson.D{{"method", "locf"}}}}},
+				}}},
+				bson.D{{"$project", bson.D{{"c", int32(1)}}}},
+			},
+			expected: []bson.D{
+				{{"_id", int32(1)}, {"c", "x"}},
+				{{"_id", int32(2)}, {"c", "x"}},
+				{{"_id", int32(3)}, {"c", "x"}},
+				{{"_id", int32(4)}, {"c", "y"}},
+				{{"_id", int32(5)}, {"c", "y"}},
+			},
+		},
+		"Linear": {
+			pipeline: bson.A{
+				bson.D{{"$fill", bson.D{
+					{"partitionBy", "$s"},
+					{"sortBy", bson.D{{"_id", int32(1)}}},
+					{"output", bson.D{{"v", bson.D{{"method", "linear"}}}}},
+				}}},
+				bson.D{{"$project", bson.D{{"v", int32(1)}}}},
+			},
+			expected: []bson.D{
+				{{"_id", int32(1)}, {"v", int32(10)}},
+				{{"_id", int32(2)}, {"v", float64(20)}},
+				{{"_id", int32(3)}, {"v", int32(30)}},
+				{{"_id", int32(4)}, {"v", nil}},
+				{{"_id", int32(5)}, {"v", int32(50)}},
+			},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cursor, err := collection.Aggregate(ctx, tc.pipeline)
+			require.NoError(t, err)
+
+			var res []bson.D
+			require.NoError(t, cursor.All(ctx, &res))
+			assert.Equal(t, tc.expected, res)
+		})
+	}
+}
+
+func TestAggregateDensifyFillErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertOne(ctx, bson.D{{"_id", int32(1)}, {"v", "foo"}})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		stage bson.D // required
+		err   *mongo.CommandError
+	}{
+		"DensifyMissingField": {
+			stage: bson.D{{"$densify", bson.D{{"range", bson.D{{"step", int32(1)}, {"bounds", "full"}}}}}},
+			err: &mongo.CommandError{
+				Code:    40414,
+				Name:    "Location40414",
+				Message: "BSON field '$densify.field' is missing but a required field",
+			},
+		},
+		"DensifyUnknownField": {
+			stage: bson.D{{"$densify", bson.D{{"foo", int32(1)}}}},
+			err: &mongo.CommandError{
+				Code:    40415,
+				Name:    "Location40415",
+				Message: "BSON field '$densify.foo' is an unknown field.",
+			},
+		},
+		"DensifyStepNegative": {
+			stage: bson.D{{"$densify", bson.D{
+				{"field", "v"},
+				{"range", bson.D{{"step", int32(-1)}, {"bounds", "full"}}},
+			}}},
+			err: &mongo.CommandError{
+				Code:    2,
+				Name:    "BadValue",
+				Message: "The step parameter in a range statement must be a strictly positive numeric value",
+			},
+		},
+		"DensifyBoundsString": {
+			stage: bson.D{{"$densify", bson.D{
+				{"field", "v"},
+				{"range", bson.D{{"step", int32(1)}, {"bounds", "foo"}}},
+			}}},
+			err: &mongo.CommandError{
+				Code:    2,
+				Name:    "BadValue",
+				Message: "Bounds string must either be 'full' or 'partition'",
+			},
+		},
+		"DensifyFieldNotNumeric": {
+			stage: bson.D{{"$densify", bson.D{
+				{"field", "v"},
+				{"range", bson.D{{"step", int32(1)}, {"bounds", "full"}}},
+			}}},
+			err: &mongo.CommandError{
+				Code:    14,
+				Name:    "TypeMismatch",
+				Message: "Densify field type must be numeric",
+			},
+		},
+		"FillMissingOutput": {
+			stage: bson.D{{"$fill", bson.D{}}},
+			err: &mongo.CommandError{
+				Code:    40414,
+				Name:    "Location40414",
+				Message: "BSON field '$fill.output' is missing but a required field",
+			},
+		},
+		"FillBothPartitions": {
+			stage: bson.D{{"$fill", bson.D{
+				{"partitionBy", "$s"},
+				{"partitionByFields", bson.A{"s"}},
+				{"output", bson.D{{"v", bson.D{{"value", int32(0)}}}}},
+			}}},
+			err: &mongo.CommandError{
+				Code:    9,
+				Name:    "FailedToParse",
+				Message: "Only one of 'partitionBy' and 'partitionByFields' is allowed in '$fill'",
+			},
+		},
+		"FillUnknownMethod": {
+			stage: bson.D{{"$fill", bson.D{
+				{"sortBy", bson.D{{"_id", int32(1)}}},
+				{"output", bson.D{{"v", bson.D{{"method", "foo"}}}}},
+			}}},
+			err: &mongo.CommandError{
+				Code:    9,
+				Name:    "FailedToParse",
+				Message: "Method must be either locf or linear",
+			},
+		},
+		"FillMethodWithoutSortBy": {
+			stage: bson.D{{"$fill", bson.D{{"output", bson.D{{"v", bson.D{{"method", "locf"}}}}}}}},
+			err: &mongo.CommandError{
+				Code:    9,
+				Name:    "FailedToParse",
+				Message: "sortBy required if any output field specifies a 'method'",
+			},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := collection.Aggregate(ctx, bson.A{tc.stage})
+			AssertEqualCommandError(t, *tc.err, err)
+		})
+	}
+}
@@ -437,7 +437,6 @@ func TestAggregateCompatGroupDeterministicCollections(t *testing.T) {
 				// because _id of group can be an array
 				bson.D{{"$sort", bson.D{{"unique", 1}}}},
 			},
-			skip: "https://github.com/FerretDB/FerretDB/issues/2185",
 		},
 		"CountValue": {
 			pipeline: bson.A{
@@ -731,7 +730,6 @@ func TestAggregateCompatGroup(t *testing.T) {
 				{"v", bson.D{{"invalid", "v"}}},
 			}}}},
 			resultType: emptyResult,
-			skip:       "https://github.com/FerretDB/FerretDB/issues/2123",
 		},
 		"IDType": {
 			pipeline: bson.A{bson.D{{"$group", bson.D{
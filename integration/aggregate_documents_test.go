@@ -15,6 +15,7 @@
 package integration
 
 import (
+	"fmt"
 	"math"
 	"testing"
 
@@ -23,6 +24,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"github.com/FerretDB/FerretDB/integration/setup"
 	"github.com/FerretDB/FerretDB/integration/shareddata"
@@ -267,7 +269,6 @@ func TestAggregateGroupErrors(t *testing.T) {
 				Name:    "Location17276",
 				Message: "Use of undefined variable: s",
 			},
-			skip: "https://github.com/FerretDB/FerretDB/issues/2275",
 		},
 	} {
 		t.Run(name, func(t *testing.T) {
@@ -1275,3 +1276,858 @@ func TestAggregateCommandCursor(t *testing.T) {
 		})
 	}
 }
+
+func TestAggregateLookup(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	foreign := collection.Database().Collection(collection.Name() + "_foreign")
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", "order1"}, {"item", "almonds"}, {"qty", int32(2)}},
+		bson.D{{"_id", "order2"}, {"item", "pecans"}, {"qty", int32(1)}},
+		bson.D{{"_id", "order3"}, {"items", bson.A{"almonds", "cashews"}}},
+		bson.D{{"_id", "order4"}},
+		bson.D{{"_id", "order5"}, {"item", "$sku"}},
+	})
+	require.NoError(t, err)
+
+	_, err = foreign.InsertMany(ctx, []any{
+		bson.D{{"_id", "inventory1"}, {"sku", "almonds"}, {"stock", int32(120)}},
+		bson.D{{"_id", "inventory2"}, {"sku", "cashews"}, {"stock", int32(60)}},
+		bson.D{{"_id", "inventory3"}, {"sku", "pecans"}, {"stock", int32(0)}},
+		bson.D{{"_id", "inventory4"}},
+	})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct { //nolint:vet // used for testing only
+		pipeline bson.A // required, aggregation pipeline stages
+
+		res  []bson.D // required, expected response
+		skip string   // optional, skip test with a specified reason
+	}{
+		"Equality": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"_id", bson.D{{"$in", bson.A{"order1", "order2"}}}}}}},
+				bson.D{{"$lookup", bson.D{
+					{"from", foreign.Name()},
+					{"localField", "item"},
+					{"foreignField", "sku"},
+					{"as", "inventory"},
+				}}},
+				bson.D{{"$sort", bson.D{{"_id", 1}}}},
+			},
+			res: []bson.D{
+				{
+					{"_id", "order1"}, {"item", "almonds"}, {"qty", int32(2)},
+					{"inventory", bson.A{bson.D{{"_id", "inventory1"}, {"sku", "almonds"}, {"stock", int32(120)}}}},
+				},
+				{
+					{"_id", "order2"}, {"item", "pecans"}, {"qty", int32(1)},
+					{"inventory", bson.A{bson.D{{"_id", "inventory3"}, {"sku", "pecans"}, {"stock", int32(0)}}}},
+				},
+			},
+		},
+		"EqualityArray": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"_id", "order3"}}}},
+				bson.D{{"$lookup", bson.D{
+					{"from", foreign.Name()},
+					{"localField", "items"},
+					{"foreignField", "sku"},
+					{"as", "inventory"},
+				}}},
+				bson.D{{"$project", bson.D{{"inventory._id", 1}}}},
+			},
+			res: []bson.D{{
+				{"_id", "order3"},
+				{"inventory", bson.A{bson.D{{"_id", "inventory1"}}, bson.D{{"_id", "inventory2"}}}},
+			}},
+		},
+		"EqualityMissing": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"_id", "order4"}}}},
+				bson.D{{"$lookup", bson.D{
+					{"from", foreign.Name()},
+					{"localField", "item"},
+					{"foreignField", "sku"},
+					{"as", "inventory"},
+				}}},
+			},
+			res: []bson.D{{
+				{"_id", "order4"},
+				{"inventory", bson.A{bson.D{{"_id", "inventory4"}}}},
+			}},
+		},
+		"NonExistentCollection": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"_id", "order1"}}}},
+				bson.D{{"$lookup", bson.D{
+					{"from", "non-existent"},
+					{"localField", "item"},
+					{"foreignField", "sku"},
+					{"as", "inventory"},
+				}}},
+			},
+			res: []bson.D{{
+				{"_id", "order1"}, {"item", "almonds"}, {"qty", int32(2)},
+				{"inventory", bson.A{}},
+			}},
+		},
+		"Pipeline": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"_id", "order1"}}}},
+				bson.D{{"$lookup", bson.D{
+					{"from", foreign.Name()},
+					{"pipeline", bson.A{
+						bson.D{{"$match", bson.D{{"stock", bson.D{{"$gt", int32(0)}}}}}},
+						bson.D{{"$project", bson.D{{"_id", 0}, {"sku", 1}}}},
+						bson.D{{"$sort", bson.D{{"sku", -1}}}},
+					}},
+					{"as", "inStock"},
+				}}},
+			},
+			res: []bson.D{{
+				{"_id", "order1"}, {"item", "almonds"}, {"qty", int32(2)},
+				{"inStock", bson.A{bson.D{{"sku", "cashews"}}, bson.D{{"sku", "almonds"}}}},
+			}},
+		},
+		"PipelineLet": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"_id", bson.D{{"$in", bson.A{"order1", "order2"}}}}}}},
+				bson.D{{"$lookup", bson.D{
+					{"from", foreign.Name()},
+					{"let", bson.D{{"orderItem", "$item"}}},
+					{"pipeline", bson.A{
+						bson.D{{"$match", bson.D{{"$expr", bson.D{{"$eq", bson.A{"$sku", "$$orderItem"}}}}}}},
+						bson.D{{"$project", bson.D{{"_id", 0}, {"stock", 1}}}},
+					}},
+					{"as", "stock"},
+				}}},
+				bson.D{{"$project", bson.D{{"stock", 1}}}},
+				bson.D{{"$sort", bson.D{{"_id", 1}}}},
+			},
+			res: []bson.D{
+				{{"_id", "order1"}, {"stock", bson.A{bson.D{{"stock", int32(120)}}}}},
+				{{"_id", "order2"}, {"stock", bson.A{bson.D{{"stock", int32(0)}}}}},
+			},
+		},
+		"PipelineLetValue": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"_id", "order5"}}}},
+				bson.D{{"$lookup", bson.D{
+					{"from", foreign.Name()},
+					{"let", bson.D{{"orderItem", "$item"}}},
+					{"pipeline", bson.A{
+						bson.D{{"$match", bson.D{{"$expr", bson.D{{"$eq", bson.A{"$sku", "$$orderItem"}}}}}}},
+					}},
+					{"as", "matched"},
+				}}},
+				bson.D{{"$lookup", bson.D{
+					{"from", foreign.Name()},
+					{"let", bson.D{{"orderItem", "$item"}}},
+					{"pipeline", bson.A{
+						bson.D{{"$match", bson.D{{"_id", "inventory1"}}}},
+						bson.D{{"$project", bson.D{{"_id", 0}, {"sku", 1}, {"orderItem", "$$orderItem"}}}},
+					}},
+					{"as", "projected"},
+				}}},
+			},
+			res: []bson.D{{
+				{"_id", "order5"}, {"item", "$sku"},
+				{"matched", bson.A{}},
+				{"projected", bson.A{bson.D{{"sku", "almonds"}, {"orderItem", "$sku"}}}},
+			}},
+		},
+		"EqualityAndPipeline": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"_id", "order3"}}}},
+				bson.D{{"$lookup", bson.D{
+					{"from", foreign.Name()},
+					{"localField", "items"},
+					{"foreignField", "sku"},
+					{"pipeline", bson.A{
+						bson.D{{"$match", bson.D{{"stock", bson.D{{"$lt", int32(100)}}}}}},
+					}},
+					{"as", "lowStock"},
+				}}},
+				bson.D{{"$project", bson.D{{"lowStock.sku", 1}}}},
+			},
+			res: []bson.D{{
+				{"_id", "order3"},
+				{"lowStock", bson.A{bson.D{{"sku", "cashews"}}}},
+			}},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if tc.skip != "" {
+				t.Skip(tc.skip)
+			}
+
+			require.NotNil(t, tc.pipeline, "pipeline must not be nil")
+			require.NotNil(t, tc.res, "res must not be nil")
+
+			cursor, err := collection.Aggregate(ctx, tc.pipeline)
+			require.NoError(t, err)
+			defer cursor.Close(ctx)
+
+			var res []bson.D
+			err = cursor.All(ctx, &res)
+			require.NoError(t, err)
+			require.Equal(t, tc.res, res)
+		})
+	}
+}
+
+func TestAggregateLookupBatches(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	foreign := collection.Database().Collection(collection.Name() + "_foreign")
+
+	// more documents than a single batch of local values
+	docs := make([]any, 250)
+	for i := range docs {
+		docs[i] = bson.D{{"_id", int32(i)}, {"v", int32(i % 5)}}
+	}
+
+	_, err := collection.InsertMany(ctx, docs)
+	require.NoError(t, err)
+
+	_, err = foreign.InsertMany(ctx, []any{
+		bson.D{{"_id", int32(0)}},
+		bson.D{{"_id", int32(2)}},
+		bson.D{{"_id", int32(4)}},
+	})
+	require.NoError(t, err)
+
+	cursor, err := collection.Aggregate(ctx, bson.A{
+		bson.D{{"$lookup", bson.D{
+			{"from", foreign.Name()},
+			{"localField", "v"},
+			{"foreignField", "_id"},
+			{"as", "joined"},
+		}}},
+		bson.D{{"$sort", bson.D{{"_id", 1}}}},
+	})
+	require.NoError(t, err)
+
+	var res []bson.D
+	require.NoError(t, cursor.All(ctx, &res))
+	require.Len(t, res, len(docs))
+
+	for i, doc := range res {
+		expected := bson.A{}
+		if i%5%2 == 0 {
+			expected = bson.A{bson.D{{"_id", int32(i % 5)}}}
+		}
+
+		assert.Equal(t, bson.D{{"_id", int32(i)}, {"v", int32(i % 5)}, {"joined", expected}}, doc)
+	}
+}
+
+func TestAggregateLookupErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	for name, tc := range map[string]struct { //nolint:vet // used for testing only
+		pipeline bson.A // required, aggregation pipeline stages
+
+		err        *mongo.CommandError // required
+		altMessage string              // optional, alternative error message
+		skip       string              // optional, skip test with a specified reason
+	}{
+		"NotDocument": {
+			pipeline: bson.A{bson.D{{"$lookup", "foo"}}},
+			err: &mongo.CommandError{
+				Code:    9,
+				Name:    "FailedToParse",
+				Message: "the $lookup stage specification must be an object, but found string",
+			},
+		},
+		"MissingAs": {
+			pipeline: bson.A{bson.D{{"$lookup", bson.D{
+				{"from", "foo"},
+				{"localField", "a"},
+				{"foreignField", "b"},
+			}}}},
+			err: &mongo.CommandError{
+				Code:    9,
+				Name:    "FailedToParse",
+				Message: "must specify 'as' field for a $lookup",
+			},
+		},
+		"UnknownArgument": {
+			pipeline: bson.A{bson.D{{"$lookup", bson.D{
+				{"from", "foo"},
+				{"as", "bar"},
+				{"unknown", "baz"},
+			}}}},
+			err: &mongo.CommandError{
+				Code:    9,
+				Name:    "FailedToParse",
+				Message: "unknown argument to $lookup: unknown",
+			},
+		},
+		"OnlyLocalField": {
+			pipeline: bson.A{bson.D{{"$lookup", bson.D{
+				{"from", "foo"},
+				{"localField", "a"},
+				{"as", "bar"},
+			}}}},
+			err: &mongo.CommandError{
+				Code:    9,
+				Name:    "FailedToParse",
+				Message: "$lookup requires both or neither of 'localField' and 'foreignField' to be specified",
+			},
+		},
+		"InvalidPipelineStage": {
+			pipeline: bson.A{bson.D{{"$lookup", bson.D{
+				{"from", "foo"},
+				{"pipeline", bson.A{"not-document"}},
+				{"as", "bar"},
+			}}}},
+			err: &mongo.CommandError{
+				Code:    14,
+				Name:    "TypeMismatch",
+				Message: "Each element of the 'pipeline' array must be an object",
+			},
+		},
+		"InvalidVariableName": {
+			pipeline: bson.A{bson.D{{"$lookup", bson.D{
+				{"from", "foo"},
+				{"let", bson.D{{"Invalid", "$a"}}},
+				{"pipeline", bson.A{}},
+				{"as", "bar"},
+			}}}},
+			err: &mongo.CommandError{
+				Code:    9,
+				Name:    "FailedToParse",
+				Message: "'Invalid' starts with an invalid character for a user variable name",
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if tc.skip != "" {
+				t.Skip(tc.skip)
+			}
+
+			require.NotNil(t, tc.pipeline, "pipeline must not be nil")
+			require.NotNil(t, tc.err, "err must not be nil")
+
+			_, err := collection.Aggregate(ctx, tc.pipeline)
+			AssertEqualAltCommandError(t, *tc.err, tc.altMessage, err)
+		})
+	}
+}
+
+func TestAggregateFacet(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", "a"}, {"category", "books"}, {"price", int32(10)}},
+		bson.D{{"_id", "b"}, {"category", "books"}, {"price", int32(25)}},
+		bson.D{{"_id", "c"}, {"category", "games"}, {"price", int32(40)}},
+		bson.D{{"_id", "d"}, {"category", "music"}, {"price", int32(5)}},
+	})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct { //nolint:vet // used for testing only
+		pipeline bson.A // required, aggregation pipeline stages
+
+		res  []bson.D // required, expected response
+		skip string   // optional, skip test with a specified reason
+	}{
+		"Facets": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"price", bson.D{{"$gt", int32(5)}}}}}},
+				bson.D{{"$facet", bson.D{
+					{"byCategory", bson.A{
+						bson.D{{"$group", bson.D{{"_id", "$category"}, {"count", bson.D{{"$count", bson.D{}}}}}}},
+						bson.D{{"$sort", bson.D{{"_id", 1}}}},
+					}},
+					{"total", bson.A{
+						bson.D{{"$count", "count"}},
+					}},
+					{"expensive", bson.A{
+						bson.D{{"$match", bson.D{{"price", bson.D{{"$gte", int32(25)}}}}}},
+						bson.D{{"$sort", bson.D{{"price", -1}}}},
+						bson.D{{"$project", bson.D{{"price", 1}}}},
+					}},
+				}}},
+			},
+			res: []bson.D{{
+				{"byCategory", bson.A{
+					bson.D{{"_id", "books"}, {"count", int32(2)}},
+					bson.D{{"_id", "games"}, {"count", int32(1)}},
+				}},
+				{"total", bson.A{bson.D{{"count", int32(3)}}}},
+				{"expensive", bson.A{
+					bson.D{{"_id", "c"}, {"price", int32(40)}},
+					bson.D{{"_id", "b"}, {"price", int32(25)}},
+				}},
+			}},
+		},
+		"ModifyingSubPipelines": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"_id", "a"}}}},
+				bson.D{{"$facet", bson.D{
+					{"set", bson.A{bson.D{{"$set", bson.D{{"price", int32(0)}}}}}},
+					{"unset", bson.A{bson.D{{"$unset", "price"}}}},
+					{"unchanged", bson.A{}},
+				}}},
+			},
+			res: []bson.D{{
+				{"set", bson.A{bson.D{{"_id", "a"}, {"category", "books"}, {"price", int32(0)}}}},
+				{"unset", bson.A{bson.D{{"_id", "a"}, {"category", "books"}}}},
+				{"unchanged", bson.A{bson.D{{"_id", "a"}, {"category", "books"}, {"price", int32(10)}}}},
+			}},
+		},
+		"NoDocuments": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"_id", "none"}}}},
+				bson.D{{"$facet", bson.D{
+					{"all", bson.A{}},
+					{"total", bson.A{bson.D{{"$count", "count"}}}},
+				}}},
+			},
+			res: []bson.D{{
+				{"all", bson.A{}},
+				{"total", bson.A{}},
+			}},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if tc.skip != "" {
+				t.Skip(tc.skip)
+			}
+
+			require.NotNil(t, tc.pipeline, "pipeline must not be nil")
+			require.NotNil(t, tc.res, "res must not be nil")
+
+			cursor, err := collection.Aggregate(ctx, tc.pipeline)
+			require.NoError(t, err)
+			defer cursor.Close(ctx)
+
+			var res []bson.D
+			err = cursor.All(ctx, &res)
+			require.NoError(t, err)
+			require.Equal(t, tc.res, res)
+		})
+	}
+}
+
+func TestAggregateFacetErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	for name, tc := range map[string]struct { //nolint:vet // used for testing only
+		pipeline bson.A // required, aggregation pipeline stages
+
+		err        *mongo.CommandError // required
+		altMessage string              // optional, alternative error message
+		skip       string              // optional, skip test with a specified reason
+	}{
+		"Empty": {
+			pipeline: bson.A{bson.D{{"$facet", bson.D{}}}},
+			err: &mongo.CommandError{
+				Code:    40169,
+				Name:    "Location40169",
+				Message: "the $facet specification must be a non-empty object, but found: {}",
+			},
+			altMessage: "the $facet specification must be a non-empty object, but found: object",
+		},
+		"NotArray": {
+			pipeline: bson.A{bson.D{{"$facet", bson.D{{"foo", "bar"}}}}},
+			err: &mongo.CommandError{
+				Code:    40170,
+				Name:    "Location40170",
+				Message: "arguments to $facet must be arrays, foo is type string",
+			},
+		},
+		"NotDocumentStage": {
+			pipeline: bson.A{bson.D{{"$facet", bson.D{{"foo", bson.A{int32(1)}}}}}},
+			err: &mongo.CommandError{
+				Code:    40171,
+				Name:    "Location40171",
+				Message: "elements of arrays in $facet spec must be non-empty objects, foo argument contained an element of type int: 1",
+			},
+			altMessage: "elements of arrays in $facet spec must be non-empty objects, foo argument contained an element of type int",
+		},
+		"NestedFacet": {
+			pipeline: bson.A{bson.D{{"$facet", bson.D{{"foo", bson.A{
+				bson.D{{"$facet", bson.D{{"bar", bson.A{}}}}},
+			}}}}}},
+			err: &mongo.CommandError{
+				Code:    40600,
+				Name:    "Location40600",
+				Message: "$facet is not allowed to be used within a $facet stage",
+			},
+		},
+		"InvalidSubPipeline": {
+			pipeline: bson.A{bson.D{{"$facet", bson.D{{"foo", bson.A{
+				bson.D{{"$limit", int32(0)}},
+			}}}}}},
+			err: &mongo.CommandError{
+				Code:    15958,
+				Name:    "Location15958",
+				Message: "The limit must be positive",
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if tc.skip != "" {
+				t.Skip(tc.skip)
+			}
+
+			require.NotNil(t, tc.pipeline, "pipeline must not be nil")
+			require.NotNil(t, tc.err, "err must not be nil")
+
+			_, err := collection.Aggregate(ctx, tc.pipeline)
+			AssertEqualAltCommandError(t, *tc.err, tc.altMessage, err)
+		})
+	}
+}
+
+func TestAggregateOut(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", "a"}, {"category", "books"}, {"price", int32(10)}},
+		bson.D{{"_id", "b"}, {"category", "books"}, {"price", int32(25)}},
+		bson.D{{"_id", "c"}, {"category", "games"}, {"price", int32(40)}},
+	})
+	require.NoError(t, err)
+
+	out := collection.Database().Collection(collection.Name() + "_out")
+
+	_, err = out.InsertOne(ctx, bson.D{{"_id", "old"}})
+	require.NoError(t, err)
+
+	_, err = out.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{"total", 1}}})
+	require.NoError(t, err)
+
+	cursor, err := collection.Aggregate(ctx, bson.A{
+		bson.D{{"$group", bson.D{{"_id", "$category"}, {"total", bson.D{{"$sum", "$price"}}}}}},
+		bson.D{{"$out", out.Name()}},
+	})
+	require.NoError(t, err)
+
+	var res []bson.D
+	require.NoError(t, cursor.All(ctx, &res))
+	assert.Empty(t, res)
+
+	cursor, err = out.Find(ctx, bson.D{}, options.Find().SetSort(bson.D{{"_id", 1}}))
+	require.NoError(t, err)
+
+	require.NoError(t, cursor.All(ctx, &res))
+	expected := []bson.D{
+		{{"_id", "books"}, {"total", int32(35)}},
+		{{"_id", "games"}, {"total", int32(40)}},
+	}
+	assert.Equal(t, expected, res)
+
+	cursor, err = out.Indexes().List(ctx)
+	require.NoError(t, err)
+
+	var indexes []bson.D
+	require.NoError(t, cursor.All(ctx, &indexes))
+
+	var names []string
+	for _, index := range indexes {
+		names = append(names, index.Map()["name"].(string))
+	}
+
+	assert.ElementsMatch(t, []string{"_id_", "total_1"}, names)
+
+	t.Run("DocumentsWithoutID", func(t *testing.T) {
+		cursor, err := collection.Aggregate(ctx, bson.A{
+			bson.D{{"$match", bson.D{{"_id", "a"}}}},
+			bson.D{{"$project", bson.D{{"_id", 0}, {"price", 1}}}},
+			bson.D{{"$out", bson.D{{"db", out.Database().Name()}, {"coll", out.Name()}}}},
+		})
+		require.NoError(t, err)
+		require.NoError(t, cursor.Close(ctx))
+
+		var doc bson.D
+		require.NoError(t, out.FindOne(ctx, bson.D{}).Decode(&doc))
+		require.Len(t, doc, 2)
+		assert.Equal(t, "_id", doc[0].Key)
+		assert.IsType(t, primitive.ObjectID{}, doc[0].Value)
+		assert.Equal(t, bson.E{"price", int32(10)}, doc[1])
+	})
+
+	t.Run("CappedCollection", func(t *testing.T) {
+		capped := collection.Name() + "_capped"
+
+		opts := options.CreateCollection().SetCapped(true).SetSizeInBytes(1000)
+		require.NoError(t, collection.Database().CreateCollection(ctx, capped, opts))
+
+		_, err := collection.Aggregate(ctx, bson.A{bson.D{{"$out", capped}}})
+		AssertEqualCommandError(t, mongo.CommandError{
+			Code: 17152,
+			Name: "Location17152",
+			Message: fmt.Sprintf(
+				"namespace '%s.%s' is capped so it can't be used for $out",
+				collection.Database().Name(), capped,
+			),
+		}, err)
+	})
+}
+
+func TestAggregateMerge(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct { //nolint:vet // used for testing only
+		merge bson.D // required, $merge stage arguments
+
+		res  []bson.D            // expected content of the target collection
+		err  *mongo.CommandError // expected error
+		skip string              // optional, skip test with a specified reason
+	}{
+		"Default": {
+			merge: bson.D{{"into", "target"}},
+			res: []bson.D{
+				{{"_id", "a"}, {"v", int32(1)}, {"old", true}},
+				{{"_id", "b"}, {"v", int32(2)}},
+				{{"_id", "c"}, {"old", true}},
+			},
+		},
+		"Replace": {
+			merge: bson.D{{"into", "target"}, {"whenMatched", "replace"}},
+			res: []bson.D{
+				{{"_id", "a"}, {"v", int32(1)}},
+				{{"_id", "b"}, {"v", int32(2)}},
+				{{"_id", "c"}, {"old", true}},
+			},
+		},
+		"KeepExisting": {
+			merge: bson.D{{"into", "target"}, {"whenMatched", "keepExisting"}},
+			res: []bson.D{
+				{{"_id", "a"}, {"v", int32(0)}, {"old", true}},
+				{{"_id", "b"}, {"v", int32(2)}},
+				{{"_id", "c"}, {"old", true}},
+			},
+		},
+		"FailMatched": {
+			merge: bson.D{{"into", "target"}, {"whenMatched", "fail"}},
+			err: &mongo.CommandError{
+				Code:    11000,
+				Name:    "DuplicateKey",
+				Message: "$merge write error: E11000 duplicate key error",
+			},
+		},
+		"Pipeline": {
+			merge: bson.D{
+				{"into", "target"},
+				{"whenMatched", bson.A{bson.D{{"$set", bson.D{{"merged", true}, {"v", "$$new.v"}}}}}},
+			},
+			res: []bson.D{
+				{{"_id", "a"}, {"v", int32(1)}, {"old", true}, {"merged", true}},
+				{{"_id", "b"}, {"v", int32(2)}},
+				{{"_id", "c"}, {"old", true}},
+			},
+		},
+		"PipelineLet": {
+			merge: bson.D{
+				{"into", "target"},
+				{"let", bson.D{{"value", "$v"}}},
+				{"whenMatched", bson.A{bson.D{{"$set", bson.D{{"v", "$$value"}}}}}},
+			},
+			res: []bson.D{
+				{{"_id", "a"}, {"v", int32(1)}, {"old", true}},
+				{{"_id", "b"}, {"v", int32(2)}},
+				{{"_id", "c"}, {"old", true}},
+			},
+		},
+		"PipelineLetString": {
+			merge: bson.D{
+				{"into", "target"},
+				{"let", bson.D{{"value", bson.D{{"$substrBytes", bson.A{"_$old", 1, 4}}}}}},
+				{"whenMatched", bson.A{bson.D{{"$set", bson.D{{"v", "$$value"}}}}}},
+			},
+			res: []bson.D{
+				{{"_id", "a"}, {"v", "$old"}, {"old", true}},
+				{{"_id", "b"}, {"v", int32(2)}},
+				{{"_id", "c"}, {"old", true}},
+			},
+		},
+		"Discard": {
+			merge: bson.D{{"into", "target"}, {"whenNotMatched", "discard"}},
+			res: []bson.D{
+				{{"_id", "a"}, {"v", int32(1)}, {"old", true}},
+				{{"_id", "c"}, {"old", true}},
+			},
+		},
+		"FailNotMatched": {
+			merge: bson.D{{"into", "target"}, {"whenNotMatched", "fail"}},
+			err: &mongo.CommandError{
+				Code: 13113,
+				Name: "MergeStageNoMatchingDocument",
+				Message: "$merge could not find a matching document in the target collection " +
+					"for at least one document in the source collection",
+			},
+		},
+		"OnWithoutUniqueIndex": {
+			merge: bson.D{{"into", "target"}, {"on", "v"}},
+			err: &mongo.CommandError{
+				Code:    51183,
+				Name:    "Location51183",
+				Message: "Cannot find index to verify that join fields will be unique",
+			},
+		},
+		"InvalidWhenMatched": {
+			merge: bson.D{{"into", "target"}, {"whenMatched", "foo"}},
+			err: &mongo.CommandError{
+				Code:    2,
+				Name:    "BadValue",
+				Message: "Enumeration value 'foo' for field '$merge.whenMatched' is not a valid value.",
+			},
+		},
+		"MissingInto": {
+			merge: bson.D{{"on", "_id"}},
+			err: &mongo.CommandError{
+				Code:    40414,
+				Name:    "Location40414",
+				Message: "BSON field '$merge.into' is missing but a required field",
+			},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			if tc.skip != "" {
+				t.Skip(tc.skip)
+			}
+
+			t.Parallel()
+
+			require.NotNil(t, tc.merge, "merge must not be nil")
+
+			ctx, collection := setup.Setup(t)
+
+			_, err := collection.InsertMany(ctx, []any{
+				bson.D{{"_id", "a"}, {"v", int32(1)}},
+				bson.D{{"_id", "b"}, {"v", int32(2)}},
+			})
+			require.NoError(t, err)
+
+			target := collection.Database().Collection("target")
+
+			_, err = target.InsertMany(ctx, []any{
+				bson.D{{"_id", "a"}, {"v", int32(0)}, {"old", true}},
+				bson.D{{"_id", "c"}, {"old", true}},
+			})
+			require.NoError(t, err)
+
+			_, err = collection.Aggregate(ctx, bson.A{bson.D{{"$merge", tc.merge}}})
+			if tc.err != nil {
+				AssertEqualCommandError(t, *tc.err, err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			cursor, err := target.Find(ctx, bson.D{}, options.Find().SetSort(bson.D{{"_id", 1}}))
+			require.NoError(t, err)
+
+			var res []bson.D
+			require.NoError(t, cursor.All(ctx, &res))
+			assert.Equal(t, tc.res, res)
+		})
+	}
+}
+
+func TestAggregateOutMergeErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	for name, tc := range map[string]struct { //nolint:vet // used for testing only
+		pipeline bson.A // required, aggregation pipeline stages
+
+		err        *mongo.CommandError // required
+		altMessage string              // optional, alternative error message
+		skip       string              // optional, skip test with a specified reason
+	}{
+		"OutNotLast": {
+			pipeline: bson.A{bson.D{{"$out", "foo"}}, bson.D{{"$match", bson.D{}}}},
+			err: &mongo.CommandError{
+				Code:    40601,
+				Name:    "Location40601",
+				Message: "$out can only be the final stage in the pipeline",
+			},
+		},
+		"MergeNotLast": {
+			pipeline: bson.A{bson.D{{"$merge", "foo"}}, bson.D{{"$match", bson.D{}}}},
+			err: &mongo.CommandError{
+				Code:    40601,
+				Name:    "Location40601",
+				Message: "$merge can only be the final stage in the pipeline",
+			},
+		},
+		"OutInvalidType": {
+			pipeline: bson.A{bson.D{{"$out", int32(1)}}},
+			err: &mongo.CommandError{
+				Code:    16990,
+				Name:    "Location16990",
+				Message: "$out only supports a string or object argument, not int",
+			},
+		},
+		"OutMissingColl": {
+			pipeline: bson.A{bson.D{{"$out", bson.D{{"db", "foo"}}}}},
+			err: &mongo.CommandError{
+				Code:    40414,
+				Name:    "Location40414",
+				Message: "BSON field '$out.coll' is missing but a required field",
+			},
+		},
+		"MergeInvalidType": {
+			pipeline: bson.A{bson.D{{"$merge", int32(1)}}},
+			err: &mongo.CommandError{
+				Code:    51182,
+				Name:    "Location51182",
+				Message: "$merge only supports a string or object argument, not int",
+			},
+		},
+		"OutInFacet": {
+			pipeline: bson.A{bson.D{{"$facet", bson.D{{"foo", bson.A{bson.D{{"$out", "foo"}}}}}}}},
+			err: &mongo.CommandError{
+				Code:    40600,
+				Name:    "Location40600",
+				Message: "$out is not allowed to be used within a $facet stage",
+			},
+		},
+		"MergeInLookup": {
+			pipeline: bson.A{bson.D{{"$lookup", bson.D{
+				{"from", "foo"},
+				{"pipeline", bson.A{bson.D{{"$merge", "foo"}}}},
+				{"as", "bar"},
+			}}}},
+			err: &mongo.CommandError{
+				Code:    51047,
+				Name:    "Location51047",
+				Message: "$merge is not allowed to be used within a $lookup stage",
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if tc.skip != "" {
+				t.Skip(tc.skip)
+			}
+
+			require.NotNil(t, tc.pipeline, "pipeline must not be nil")
+			require.NotNil(t, tc.err, "err must not be nil")
+
+			_, err := collection.Aggregate(ctx, tc.pipeline)
+			AssertEqualAltCommandError(t, *tc.err, tc.altMessage, err)
+		})
+	}
+}
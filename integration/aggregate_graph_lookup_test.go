@@ -0,0 +1,210 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/FerretDB/FerretDB/integration/setup"
+)
+
+func TestAggregateGraphLookup(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", "andrew"}, {"dept", "board"}},
+		bson.D{{"_id", "ron"}, {"reportsTo", "andrew"}, {"dept", "eng"}},
+		bson.D{{"_id", "eliot"}, {"reportsTo", "ron"}, {"dept", "eng"}},
+		bson.D{{"_id", "dev"}, {"reportsTo", "eliot"}, {"dept", "eng"}},
+		bson.D{{"_id", "a"}, {"reportsTo", bson.A{"b", "c"}}},
+		bson.D{{"_id", "b"}, {"reportsTo", "a"}},
+		bson.D{{"_id", "c"}, {"reportsTo", "a"}},
+	})
+	require.NoError(t, err)
+
+	// graphLookup returns the found chain of the given document as separate documents sorted by _id
+	graphLookup := func(id string, spec bson.D) bson.A {
+		return bson.A{
+			bson.D{{"$match", bson.D{{"_id", id}}}},
+			bson.D{{"$graphLookup", append(bson.D{
+				{"from", collection.Name()},
+				{"startWith", "$reportsTo"},
+				{"connectFromField", "reportsTo"},
+				{"connectToField", "_id"},
+				{"as", "chain"},
+			}, spec...)}},
+			bson.D{{"$unwind", "$chain"}},
+			bson.D{{"$replaceRoot", bson.D{{"newRoot", "$chain"}}}},
+			bson.D{{"$project", bson.D{{"reportsTo", int32(0)}}}},
+			bson.D{{"$sort", bson.D{{"_id", int32(1)}}}},
+		}
+	}
+
+	for name, tc := range map[string]struct {
+		pipeline bson.A // required
+		expected []bson.D
+	}{
+		"Chain": {
+			pipeline: graphLookup("dev", nil),
+			expected: []bson.D{
+				{{"_id", "andrew"}, {"dept", "board"}},
+				{{"_id", "eliot"}, {"dept", "eng"}},
+				{{"_id", "ron"}, {"dept", "eng"}},
+			},
+		},
+		"MaxDepth": {
+			pipeline: graphLookup("dev", bson.D{{"maxDepth", int32(1)}}),
+			expected: []bson.D{
+				{{"_id", "eliot"}, {"dept", "eng"}},
+				{{"_id", "ron"}, {"dept", "eng"}},
+			},
+		},
+		"DepthField": {
+			pipeline: graphLookup("dev", bson.D{{"depthField", "depth"}}),
+			expected: []bson.D{
+				{{"_id", "andrew"}, {"dept", "board"}, {"depth", int64(2)}},
+				{{"_id", "eliot"}, {"dept", "eng"}, {"depth", int64(0)}},
+				{{"_id", "ron"}, {"dept", "eng"}, {"depth", int64(1)}},
+			},
+		},
+		"RestrictSearchWithMatch": {
+			pipeline: graphLookup("dev", bson.D{{"restrictSearchWithMatch", bson.D{{"dept", "eng"}}}}),
+			expected: []bson.D{
+				{{"_id", "eliot"}, {"dept", "eng"}},
+				{{"_id", "ron"}, {"dept", "eng"}},
+			},
+		},
+		"Cycle": {
+			pipeline: graphLookup("b", nil),
+			expected: []bson.D{
+				{{"_id", "a"}},
+				{{"_id", "b"}},
+				{{"_id", "c"}},
+			},
+		},
+		"NoMatches": {
+			pipeline: graphLookup("andrew", nil),
+			expected: nil,
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cursor, err := collection.Aggregate(ctx, tc.pipeline)
+			require.NoError(t, err)
+
+			var res []bson.D
+			require.NoError(t, cursor.All(ctx, &res))
+			assert.Equal(t, tc.expected, res)
+		})
+	}
+}
+
+func TestAggregateGraphLookupErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertOne(ctx, bson.D{{"_id", int32(1)}})
+	require.NoError(t, err)
+
+	spec := func(extra ...bson.E) bson.D {
+		return append(bson.D{
+			{"from", collection.Name()},
+			{"startWith", "$_id"},
+			{"connectFromField", "v"},
+			{"connectToField", "_id"},
+			{"as", "res"},
+		}, extra...)
+	}
+
+	for name, tc := range map[string]struct {
+		stage bson.D // required
+		err   *mongo.CommandError
+	}{
+		"MissingField": {
+			stage: bson.D{{"$graphLookup", bson.D{{"from", collection.Name()}}}},
+			err: &mongo.CommandError{
+				Code:    40105,
+				Name:    "Location40105",
+				Message: "$graphLookup requires 'from', 'as', 'startWith', 'connectFromField', and 'connectToField' to be specified.",
+			},
+		},
+		"NotString": {
+			stage: bson.D{{"$graphLookup", bson.D{{"as", int32(1)}}}},
+			err: &mongo.CommandError{
+				Code:    40103,
+				Name:    "Location40103",
+				Message: "expected string as argument for as, found: 1",
+			},
+		},
+		"UnknownArgument": {
+			stage: bson.D{{"$graphLookup", spec(bson.E{"foo", int32(1)})}},
+			err: &mongo.CommandError{
+				Code:    40104,
+				Name:    "Location40104",
+				Message: "Unknown argument to $graphLookup: foo",
+			},
+		},
+		"MaxDepthNotNumber": {
+			stage: bson.D{{"$graphLookup", spec(bson.E{"maxDepth", "1"})}},
+			err: &mongo.CommandError{
+				Code:    40100,
+				Name:    "Location40100",
+				Message: "maxDepth must be numeric, found type: string",
+			},
+		},
+		"MaxDepthNegative": {
+			stage: bson.D{{"$graphLookup", spec(bson.E{"maxDepth", int32(-1)})}},
+			err: &mongo.CommandError{
+				Code:    40101,
+				Name:    "Location40101",
+				Message: "maxDepth requires a nonnegative argument, found: -1",
+			},
+		},
+		"MaxDepthNotInteger": {
+			stage: bson.D{{"$graphLookup", spec(bson.E{"maxDepth", 1.5})}},
+			err: &mongo.CommandError{
+				Code:    40102,
+				Name:    "Location40102",
+				Message: "maxDepth could not be represented as a long long: 1",
+			},
+		},
+		"RestrictNotObject": {
+			stage: bson.D{{"$graphLookup", spec(bson.E{"restrictSearchWithMatch", int32(1)})}},
+			err: &mongo.CommandError{
+				Code:    40185,
+				Name:    "Location40185",
+				Message: "restrictSearchWithMatch must be an object, found int",
+			},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := collection.Aggregate(ctx, bson.A{tc.stage})
+			AssertEqualCommandError(t, *tc.err, err)
+		})
+	}
+}
@@ -0,0 +1,362 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/FerretDB/FerretDB/integration/setup"
+)
+
+func TestAggregateGroupAccumulators(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", int32(1)}, {"v", int32(1)}, {"w", int32(1)}, {"s", "x"}, {"d", bson.D{{"a", int32(1)}}}},
+		bson.D{{"_id", int32(2)}, {"v", 2.0}, {"w", int64(3)}, {"s", "y"}, {"d", bson.D{{"b", int32(2)}}}},
+		bson.D{{"_id", int32(3)}, {"v", nil}, {"w", "x"}, {"s", "x"}, {"d", nil}},
+		bson.D{{"_id", int32(4)}, {"v", int64(6)}, {"s", "z"}, {"d", bson.D{{"a", int32(3)}}}},
+		bson.D{{"_id", int32(5)}},
+	})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		accumulator bson.D
+		expected    any
+	}{
+		"Count":        {bson.D{{"$count", bson.D{}}}, int32(5)},
+		"Sum":          {bson.D{{"$sum", "$v"}}, 9.0},
+		"Avg":          {bson.D{{"$avg", "$v"}}, 3.0},
+		"AvgNull":      {bson.D{{"$avg", "$s"}}, nil},
+		"Min":          {bson.D{{"$min", "$v"}}, int32(1)},
+		"MinTypes":     {bson.D{{"$min", "$w"}}, int32(1)},
+		"Max":          {bson.D{{"$max", "$v"}}, int64(6)},
+		"MaxTypes":     {bson.D{{"$max", "$w"}}, "x"},
+		"MaxMissing":   {bson.D{{"$max", "$missing"}}, nil},
+		"First":        {bson.D{{"$first", "$v"}}, int32(1)},
+		"Last":         {bson.D{{"$last", "$v"}}, nil},
+		"Push":         {bson.D{{"$push", "$v"}}, bson.A{int32(1), 2.0, nil, int64(6)}},
+		"PushExpr":     {bson.D{{"$push", bson.D{{"$ifNull", bson.A{"$s", "none"}}}}}, bson.A{"x", "y", "x", "z", "none"}},
+		"AddToSet":     {bson.D{{"$addToSet", "$s"}}, bson.A{"x", "y", "z"}},
+		"StdDevPop":    {bson.D{{"$stdDevPop", "$w"}}, 1.0},
+		"StdDevSamp":   {bson.D{{"$stdDevSamp", "$w"}}, math.Sqrt2},
+		"StdDevSampID": {bson.D{{"$stdDevSamp", "$_id"}}, 1.5811388300841898},
+		"MergeObjects": {
+			bson.D{{"$mergeObjects", "$d"}},
+			bson.D{{"a", int32(3)}, {"b", int32(2)}},
+		},
+		"FirstN": {bson.D{{"$firstN", bson.D{{"input", "$v"}, {"n", int32(2)}}}}, bson.A{int32(1), 2.0}},
+		"FirstNMore": {
+			bson.D{{"$firstN", bson.D{{"input", "$_id"}, {"n", 10.0}}}},
+			bson.A{int32(1), int32(2), int32(3), int32(4), int32(5)},
+		},
+		"LastN": {bson.D{{"$lastN", bson.D{{"input", "$v"}, {"n", int64(2)}}}}, bson.A{int64(6), nil}},
+		"MinN":  {bson.D{{"$minN", bson.D{{"input", "$v"}, {"n", int32(2)}}}}, bson.A{int32(1), 2.0}},
+		"MaxN":  {bson.D{{"$maxN", bson.D{{"input", "$v"}, {"n", int32(2)}}}}, bson.A{int64(6), 2.0}},
+		"Top": {
+			bson.D{{"$top", bson.D{{"sortBy", bson.D{{"v", int32(-1)}}}, {"output", "$_id"}}}},
+			int32(4),
+		},
+		"Bottom": {
+			bson.D{{"$bottom", bson.D{{"sortBy", bson.D{{"v", int32(-1)}, {"_id", int32(1)}}}, {"output", "$_id"}}}},
+			int32(5),
+		},
+		"TopN": {
+			bson.D{{"$topN", bson.D{
+				{"n", int32(2)},
+				{"sortBy", bson.D{{"s", int32(1)}, {"_id", int32(1)}}},
+				{"output", "$_id"},
+			}}},
+			bson.A{int32(5), int32(1)},
+		},
+		"BottomN": {
+			bson.D{{"$bottomN", bson.D{
+				{"n", int32(2)},
+				{"sortBy", bson.D{{"s", int32(1)}, {"_id", int32(1)}}},
+				{"output", bson.A{"$s", "$_id"}},
+			}}},
+			bson.A{bson.A{"y", int32(2)}, bson.A{"z", int32(4)}},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			pipeline := bson.A{
+				bson.D{{"$sort", bson.D{{"_id", 1}}}},
+				bson.D{{"$group", bson.D{{"_id", nil}, {"res", tc.accumulator}}}},
+			}
+
+			cursor, err := collection.Aggregate(ctx, pipeline)
+			require.NoError(t, err)
+
+			var res []bson.D
+			require.NoError(t, cursor.All(ctx, &res))
+			require.Len(t, res, 1)
+
+			assert.Equal(t, bson.D{{"_id", nil}, {"res", tc.expected}}, res[0])
+		})
+	}
+}
+
+func TestAggregateGroupManyKeys(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	docs := make([]any, 1000)
+	for i := range docs {
+		docs[i] = bson.D{{"_id", int32(i)}, {"k", int32(i % 500)}, {"v", int64(i)}}
+	}
+
+	_, err := collection.InsertMany(ctx, docs)
+	require.NoError(t, err)
+
+	pipeline := bson.A{
+		bson.D{{"$group", bson.D{{"_id", "$k"}, {"sum", bson.D{{"$sum", "$v"}}}, {"count", bson.D{{"$count", bson.D{}}}}}}},
+		bson.D{{"$sort", bson.D{{"_id", 1}}}},
+	}
+
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	require.NoError(t, err)
+
+	var res []bson.D
+	require.NoError(t, cursor.All(ctx, &res))
+	require.Len(t, res, 500)
+
+	for i, doc := range res {
+		expected := bson.D{{"_id", int32(i)}, {"sum", int64(2*i + 500)}, {"count", int32(2)}}
+		assert.Equal(t, expected, doc)
+	}
+}
+
+func TestAggregateGroupMemoryLimit(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertOne(ctx, bson.D{{"_id", int32(1)}, {"v", strings.Repeat("x", 8*1024*1024)}})
+	require.NoError(t, err)
+
+	// the same large value is kept many times
+	large := bson.D{}
+	for i := 0; i < 7; i++ {
+		large = append(large, bson.E{strconv.Itoa(i), "$v"})
+	}
+
+	expected := mongo.CommandError{
+		Code:    146,
+		Name:    "ExceededMemoryLimit",
+		Message: "$group used too much memory and cannot spill to disk. Memory limit: 104857600 bytes",
+	}
+
+	for name, pipeline := range map[string]bson.A{
+		"Keys": {bson.D{{"$group", bson.D{
+			{"_id", bson.D{{"a", large}, {"b", large}}},
+		}}}},
+		"Accumulations": {bson.D{{"$group", bson.D{
+			{"_id", nil},
+			{"a", bson.D{{"$push", large}}},
+			{"b", bson.D{{"$push", large}}},
+		}}}},
+	} {
+		name, pipeline := name, pipeline
+		t.Run(name, func(tt *testing.T) {
+			t := setup.FailsForMongoDB(tt, "MongoDB spills $group to disk")
+
+			_, err := collection.Aggregate(ctx, pipeline)
+			AssertEqualCommandError(t, expected, err)
+		})
+	}
+}
+
+func TestAggregateGroupAccumulatorsErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertOne(ctx, bson.D{{"_id", "v"}, {"v", int32(1)}})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		accumulator bson.D
+		err         *mongo.CommandError
+	}{
+		"Unknown": {
+			accumulator: bson.D{{"$foo", "$v"}},
+			err: &mongo.CommandError{
+				Code:    15952,
+				Name:    "Location15952",
+				Message: "unknown group operator '$foo'",
+			},
+		},
+		"UnaryAvg": {
+			accumulator: bson.D{{"$avg", bson.A{"$v"}}},
+			err: &mongo.CommandError{
+				Code:    40237,
+				Name:    "Location40237",
+				Message: "The $avg accumulator is a unary operator",
+			},
+		},
+		"CountArgument": {
+			accumulator: bson.D{{"$count", int32(1)}},
+			err: &mongo.CommandError{
+				Code:    14,
+				Name:    "TypeMismatch",
+				Message: "$count takes no arguments, i.e. $count:{}",
+			},
+		},
+		"MergeObjectsNotObject": {
+			accumulator: bson.D{{"$mergeObjects", "$v"}},
+			err: &mongo.CommandError{
+				Code:    40400,
+				Name:    "Location40400",
+				Message: "$mergeObjects requires object inputs, but input 1 is of type int",
+			},
+		},
+		"FirstNNotObject": {
+			accumulator: bson.D{{"$firstN", int32(1)}},
+			err: &mongo.CommandError{
+				Code:    5787801,
+				Name:    "Location5787801",
+				Message: "specification must be an object; found $firstN: 1",
+			},
+		},
+		"MaxNNotObject": {
+			accumulator: bson.D{{"$maxN", "$v"}},
+			err: &mongo.CommandError{
+				Code:    5787900,
+				Name:    "Location5787900",
+				Message: `specification must be an object; found $maxN: "$v"`,
+			},
+		},
+		"FirstNUnknownField": {
+			accumulator: bson.D{{"$firstN", bson.D{{"input", "$v"}, {"n", int32(1)}, {"foo", int32(1)}}}},
+			err: &mongo.CommandError{
+				Code:    5787901,
+				Name:    "Location5787901",
+				Message: "Unknown argument for 'n' operator: foo",
+			},
+		},
+		"LastNNonNumeric": {
+			accumulator: bson.D{{"$lastN", bson.D{{"input", "$v"}, {"n", "a"}}}},
+			err: &mongo.CommandError{
+				Code:    5787902,
+				Name:    "Location5787902",
+				Message: `Value for 'n' must be of integral type, but found "a"`,
+			},
+		},
+		"MinNNonIntegral": {
+			accumulator: bson.D{{"$minN", bson.D{{"input", "$v"}, {"n", 1.5}}}},
+			err: &mongo.CommandError{
+				Code:    5787903,
+				Name:    "Location5787903",
+				Message: "Value for 'n' must be of integral type, but found 1.5",
+			},
+		},
+		"MaxNMissingN": {
+			accumulator: bson.D{{"$maxN", bson.D{{"input", "$v"}}}},
+			err: &mongo.CommandError{
+				Code:    5787906,
+				Name:    "Location5787906",
+				Message: "Missing value for 'n'",
+			},
+		},
+		"FirstNMissingInput": {
+			accumulator: bson.D{{"$firstN", bson.D{{"n", int32(1)}}}},
+			err: &mongo.CommandError{
+				Code:    5787907,
+				Name:    "Location5787907",
+				Message: "Missing value for 'input'",
+			},
+		},
+		"LastNZero": {
+			accumulator: bson.D{{"$lastN", bson.D{{"input", "$v"}, {"n", int32(0)}}}},
+			err: &mongo.CommandError{
+				Code:    5787908,
+				Name:    "Location5787908",
+				Message: "'n' must be greater than 0, found 0",
+			},
+		},
+		"TopNotObject": {
+			accumulator: bson.D{{"$top", int32(1)}},
+			err: &mongo.CommandError{
+				Code:    5788001,
+				Name:    "Location5788001",
+				Message: "specification must be an object; found $top: 1",
+			},
+		},
+		"TopUnknownField": {
+			accumulator: bson.D{{"$top", bson.D{{"n", int32(1)}, {"sortBy", bson.D{{"v", 1}}}, {"output", "$v"}}}},
+			err: &mongo.CommandError{
+				Code:    5788002,
+				Name:    "Location5788002",
+				Message: "Unknown argument to $top 'n'",
+			},
+		},
+		"TopNMissingN": {
+			accumulator: bson.D{{"$topN", bson.D{{"sortBy", bson.D{{"v", 1}}}, {"output", "$v"}}}},
+			err: &mongo.CommandError{
+				Code:    5788003,
+				Name:    "Location5788003",
+				Message: "Missing value for 'n'",
+			},
+		},
+		"BottomMissingOutput": {
+			accumulator: bson.D{{"$bottom", bson.D{{"sortBy", bson.D{{"v", 1}}}}}},
+			err: &mongo.CommandError{
+				Code:    5788004,
+				Name:    "Location5788004",
+				Message: "Missing value for 'output'",
+			},
+		},
+		"BottomNMissingSortBy": {
+			accumulator: bson.D{{"$bottomN", bson.D{{"n", int32(1)}, {"output", "$v"}}}},
+			err: &mongo.CommandError{
+				Code:    5788005,
+				Name:    "Location5788005",
+				Message: "Missing value for 'sortBy'",
+			},
+		},
+		"TopSortByNotObject": {
+			accumulator: bson.D{{"$top", bson.D{{"sortBy", int32(1)}, {"output", "$v"}}}},
+			err: &mongo.CommandError{
+				Code:    5788604,
+				Name:    "Location5788604",
+				Message: "expected 'sortBy' to already be an object in the arguments to $top",
+			},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			pipeline := bson.A{bson.D{{"$group", bson.D{{"_id", nil}, {"res", tc.accumulator}}}}}
+
+			_, err := collection.Aggregate(ctx, pipeline)
+			AssertEqualCommandError(t, *tc.err, err)
+		})
+	}
+}
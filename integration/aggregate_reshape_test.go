@@ -0,0 +1,367 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/FerretDB/FerretDB/integration/setup"
+)
+
+func TestAggregateReplaceRoot(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", int32(1)}, {"name", "foo"}, {"address", bson.D{{"city", "Paris"}, {"zip", "75001"}}}},
+		bson.D{{"_id", int32(2)}, {"name", "bar"}, {"address", bson.D{{"city", "Rome"}}}},
+	})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		stage    bson.D // required
+		expected []bson.D
+	}{
+		"ReplaceRoot": {
+			stage: bson.D{{"$replaceRoot", bson.D{{"newRoot", "$address"}}}},
+			expected: []bson.D{
+				{{"city", "Paris"}, {"zip", "75001"}},
+				{{"city", "Rome"}},
+			},
+		},
+		"ReplaceRootOperator": {
+			stage: bson.D{{"$replaceRoot", bson.D{{"newRoot", bson.D{
+				{"$ifNull", bson.A{"$foo", "$address"}},
+			}}}}},
+			expected: []bson.D{
+				{{"city", "Paris"}, {"zip", "75001"}},
+				{{"city", "Rome"}},
+			},
+		},
+		"ReplaceWith": {
+			stage: bson.D{{"$replaceWith", "$address"}},
+			expected: []bson.D{
+				{{"city", "Paris"}, {"zip", "75001"}},
+				{{"city", "Rome"}},
+			},
+		},
+		"ReplaceWithExpressionObject": {
+			stage: bson.D{{"$replaceWith", bson.D{{"id", "$_id"}, {"zip", "$address.zip"}}}},
+			expected: []bson.D{
+				{{"id", int32(1)}, {"zip", "75001"}},
+				{{"id", int32(2)}},
+			},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cursor, err := collection.Aggregate(ctx, bson.A{
+				bson.D{{"$sort", bson.D{{"_id", int32(1)}}}},
+				tc.stage,
+			})
+			require.NoError(t, err)
+
+			var res []bson.D
+			require.NoError(t, cursor.All(ctx, &res))
+			assert.Equal(t, tc.expected, res)
+		})
+	}
+}
+
+func TestAggregateRedact(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{
+			{"_id", int32(1)},
+			{"level", int32(1)},
+			{"title", "public"},
+			{"details", bson.D{{"level", int32(3)}, {"secret", "foo"}}},
+			{"sections", bson.A{
+				bson.D{{"level", int32(1)}, {"text", "a"}},
+				bson.D{{"level", int32(2)}, {"text", "b"}},
+				"c",
+			}},
+		},
+		bson.D{{"_id", int32(2)}, {"level", int32(3)}, {"title", "secret"}},
+	})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		expression any // required
+		expected   []bson.D
+	}{
+		"Descend": {
+			expression: bson.D{{"$cond", bson.D{
+				{"if", bson.D{{"$lte", bson.A{"$level", int32(2)}}}},
+				{"then", "$$DESCEND"},
+				{"else", "$$PRUNE"},
+			}}},
+			expected: []bson.D{{
+				{"_id", int32(1)},
+				{"level", int32(1)},
+				{"title", "public"},
+				{"sections", bson.A{
+					bson.D{{"level", int32(1)}, {"text", "a"}},
+					bson.D{{"level", int32(2)}, {"text", "b"}},
+					"c",
+				}},
+			}},
+		},
+		"Keep": {
+			expression: bson.D{{"$cond", bson.A{
+				bson.D{{"$eq", bson.A{"$level", int32(1)}}}, "$$KEEP", "$$PRUNE",
+			}}},
+			expected: []bson.D{{
+				{"_id", int32(1)},
+				{"level", int32(1)},
+				{"title", "public"},
+				{"details", bson.D{{"level", int32(3)}, {"secret", "foo"}}},
+				{"sections", bson.A{
+					bson.D{{"level", int32(1)}, {"text", "a"}},
+					bson.D{{"level", int32(2)}, {"text", "b"}},
+					"c",
+				}},
+			}},
+		},
+		"Root": {
+			expression: bson.D{{"$cond", bson.A{
+				bson.D{{"$eq", bson.A{"$level", "$$ROOT.level"}}}, "$$DESCEND", "$$PRUNE",
+			}}},
+			expected: []bson.D{
+				{
+					{"_id", int32(1)},
+					{"level", int32(1)},
+					{"title", "public"},
+					{"sections", bson.A{bson.D{{"level", int32(1)}, {"text", "a"}}, "c"}},
+				},
+				{{"_id", int32(2)}, {"level", int32(3)}, {"title", "secret"}},
+			},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cursor, err := collection.Aggregate(ctx, bson.A{
+				bson.D{{"$sort", bson.D{{"_id", int32(1)}}}},
+				bson.D{{"$redact", tc.expression}},
+			})
+			require.NoError(t, err)
+
+			var res []bson.D
+			require.NoError(t, cursor.All(ctx, &res))
+			assert.Equal(t, tc.expected, res)
+		})
+	}
+}
+
+func TestAggregateUnionWith(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	archive := collection.Database().Collection(collection.Name() + "_archive")
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", int32(1)}, {"v", "live"}},
+		bson.D{{"_id", int32(2)}, {"v", "live"}},
+	})
+	require.NoError(t, err)
+
+	_, err = archive.InsertMany(ctx, []any{
+		bson.D{{"_id", int32(3)}, {"v", "archive"}},
+		bson.D{{"_id", int32(4)}, {"v", "archive"}},
+	})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		pipeline bson.A // required
+		expected []bson.D
+	}{
+		"String": {
+			pipeline: bson.A{
+				bson.D{{"$unionWith", archive.Name()}},
+				bson.D{{"$sort", bson.D{{"_id", int32(1)}}}},
+			},
+			expected: []bson.D{
+				{{"_id", int32(1)}, {"v", "live"}},
+				{{"_id", int32(2)}, {"v", "live"}},
+				{{"_id", int32(3)}, {"v", "archive"}},
+				{{"_id", int32(4)}, {"v", "archive"}},
+			},
+		},
+		"InputFirst": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"_id", int32(2)}}}},
+				bson.D{{"$unionWith", bson.D{
+					{"coll", archive.Name()},
+					{"pipeline", bson.A{bson.D{{"$match", bson.D{{"_id", int32(3)}}}}}},
+				}}},
+			},
+			expected: []bson.D{
+				{{"_id", int32(2)}, {"v", "live"}},
+				{{"_id", int32(3)}, {"v", "archive"}},
+			},
+		},
+		"Pipeline": {
+			pipeline: bson.A{
+				bson.D{{"$unionWith", bson.D{
+					{"coll", archive.Name()},
+					{"pipeline", bson.A{bson.D{{"$set", bson.D{{"archived", true}}}}}},
+				}}},
+				bson.D{{"$group", bson.D{{"_id", "$v"}, {"count", bson.D{{"$sum", int32(1)}}}}}},
+				bson.D{{"$sort", bson.D{{"_id", int32(1)}}}},
+			},
+			expected: []bson.D{
+				{{"_id", "archive"}, {"count", int32(2)}},
+				{{"_id", "live"}, {"count", int32(2)}},
+			},
+		},
+		"NonExistentCollection": {
+			pipeline: bson.A{
+				bson.D{{"$unionWith", collection.Name() + "_none"}},
+				bson.D{{"$sort", bson.D{{"_id", int32(1)}}}},
+			},
+			expected: []bson.D{
+				{{"_id", int32(1)}, {"v", "live"}},
+				{{"_id", int32(2)}, {"v", "live"}},
+			},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cursor, err := collection.Aggregate(ctx, tc.pipeline)
+			require.NoError(t, err)
+
+			var res []bson.D
+			require.NoError(t, cursor.All(ctx, &res))
+			assert.Equal(t, tc.expected, res)
+		})
+	}
+}
+
+func TestAggregateReshapeErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertOne(ctx, bson.D{{"_id", int32(1)}, {"v", "foo"}})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		stage bson.D // required
+		err   *mongo.CommandError
+	}{
+		"ReplaceRootUnknownField": {
+			stage: bson.D{{"$replaceRoot", bson.D{{"foo", "$v"}}}},
+			err: &mongo.CommandError{
+				Code:    40415,
+				Name:    "Location40415",
+				Message: "BSON field '$replaceRoot.foo' is an unknown field.",
+			},
+		},
+		"ReplaceRootMissingNewRoot": {
+			stage: bson.D{{"$replaceRoot", bson.D{}}},
+			err: &mongo.CommandError{
+				Code:    40414,
+				Name:    "Location40414",
+				Message: "BSON field '$replaceRoot.newRoot' is missing but a required field",
+			},
+		},
+		"ReplaceRootNotDocument": {
+			stage: bson.D{{"$replaceRoot", bson.D{{"newRoot", "$v"}}}},
+			err: &mongo.CommandError{
+				Code: 40228,
+				Name: "Location40228",
+				Message: "'newRoot' expression  must evaluate to an object, but resulting value was: \"foo\". " +
+					"Type of resulting value: 'string'. Input document: { _id: 1, v: \"foo\" }",
+			},
+		},
+		"ReplaceWithMissing": {
+			stage: bson.D{{"$replaceWith", "$foo"}},
+			err: &mongo.CommandError{
+				Code: 40228,
+				Name: "Location40228",
+				Message: "'replacement document' must evaluate to an object, but resulting value was: MISSING. " +
+					"Type of resulting value: 'missing'. Input document: { _id: 1, v: \"foo\" }",
+			},
+		},
+		"RedactInvalidResult": {
+			stage: bson.D{{"$redact", "$v"}},
+			err: &mongo.CommandError{
+				Code: 17053,
+				Name: "Location17053",
+				Message: "$redact's expression should not return anything aside from the variables " +
+					"$$KEEP, $$DESCEND, and $$PRUNE, but returned \"foo\"",
+			},
+		},
+		"UnionWithInvalidType": {
+			stage: bson.D{{"$unionWith", int32(1)}},
+			err: &mongo.CommandError{
+				Code:    9,
+				Name:    "FailedToParse",
+				Message: "the $unionWith stage specification must be an object or string, but found int",
+			},
+		},
+		"UnionWithMissingColl": {
+			stage: bson.D{{"$unionWith", bson.D{{"pipeline", bson.A{}}}}},
+			err: &mongo.CommandError{
+				Code:    40414,
+				Name:    "Location40414",
+				Message: "BSON field '$unionWith.coll' is missing but a required field",
+			},
+		},
+		"UnionWithCollNotString": {
+			stage: bson.D{{"$unionWith", bson.D{{"coll", int32(1)}}}},
+			err: &mongo.CommandError{
+				Code:    14,
+				Name:    "TypeMismatch",
+				Message: "BSON field '$unionWith.coll' is the wrong type 'int', expected type 'string'",
+			},
+		},
+		"UnionWithOut": {
+			stage: bson.D{{"$unionWith", bson.D{
+				{"coll", "foo"},
+				{"pipeline", bson.A{bson.D{{"$out", "bar"}}}},
+			}}},
+			err: &mongo.CommandError{
+				Code:    31441,
+				Name:    "Location31441",
+				Message: "$out is not allowed within a $unionWith's sub-pipeline",
+			},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := collection.Aggregate(ctx, bson.A{tc.stage})
+			AssertEqualCommandError(t, *tc.err, err)
+		})
+	}
+}
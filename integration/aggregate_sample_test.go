@@ -0,0 +1,182 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/FerretDB/FerretDB/integration/setup"
+)
+
+func TestAggregateSample(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	docs := make([]any, 20)
+	for i := range docs {
+		docs[i] = bson.D{{"_id", int32(i)}, {"v", int32(i % 2)}}
+	}
+
+	_, err := collection.InsertMany(ctx, docs)
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		pipeline bson.A // required
+		expected int    // expected number of documents
+		odd      bool   // if true, only documents with odd _id are expected
+	}{
+		"First": {
+			pipeline: bson.A{bson.D{{"$sample", bson.D{{"size", int32(5)}}}}},
+			expected: 5,
+		},
+		"Long": {
+			pipeline: bson.A{bson.D{{"$sample", bson.D{{"size", int64(5)}}}}},
+			expected: 5,
+		},
+		"Double": {
+			pipeline: bson.A{bson.D{{"$sample", bson.D{{"size", 5.9}}}}},
+			expected: 5,
+		},
+		"Zero": {
+			pipeline: bson.A{bson.D{{"$sample", bson.D{{"size", int32(0)}}}}},
+			expected: 0,
+		},
+		"LargerThanCollection": {
+			pipeline: bson.A{bson.D{{"$sample", bson.D{{"size", int32(100)}}}}},
+			expected: 20,
+		},
+		"AfterMatch": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"v", int32(1)}}}},
+				bson.D{{"$sample", bson.D{{"size", int32(4)}}}},
+			},
+			expected: 4,
+			odd:      true,
+		},
+		"AfterMatchLargerThanResult": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"v", int32(1)}}}},
+				bson.D{{"$sample", bson.D{{"size", int32(15)}}}},
+			},
+			expected: 10,
+			odd:      true,
+		},
+		"BeforeMatch": {
+			pipeline: bson.A{
+				bson.D{{"$sample", bson.D{{"size", int32(20)}}}},
+				bson.D{{"$match", bson.D{{"v", int32(1)}}}},
+			},
+			expected: 10,
+			odd:      true,
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cursor, err := collection.Aggregate(ctx, tc.pipeline)
+			require.NoError(t, err)
+
+			var res []bson.D
+			require.NoError(t, cursor.All(ctx, &res))
+			require.Len(t, res, tc.expected)
+
+			ids := make(map[int32]struct{}, len(res))
+
+			for _, doc := range res {
+				id := doc.Map()["_id"].(int32)
+				assert.GreaterOrEqual(t, id, int32(0))
+				assert.Less(t, id, int32(len(docs)))
+
+				if tc.odd {
+					assert.Equal(t, int32(1), id%2)
+				}
+
+				ids[id] = struct{}{}
+			}
+
+			assert.Len(t, ids, tc.expected, "documents should be distinct")
+		})
+	}
+}
+
+func TestAggregateSampleErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertOne(ctx, bson.D{{"_id", int32(1)}})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		stage bson.D // required
+		err   *mongo.CommandError
+	}{
+		"NotDocument": {
+			stage: bson.D{{"$sample", int32(1)}},
+			err: &mongo.CommandError{
+				Code:    28745,
+				Name:    "Location28745",
+				Message: "the $sample stage specification must be an object",
+			},
+		},
+		"SizeNotNumber": {
+			stage: bson.D{{"$sample", bson.D{{"size", "1"}}}},
+			err: &mongo.CommandError{
+				Code:    28746,
+				Name:    "Location28746",
+				Message: "size argument to $sample must be a number",
+			},
+		},
+		"SizeNegative": {
+			stage: bson.D{{"$sample", bson.D{{"size", int32(-1)}}}},
+			err: &mongo.CommandError{
+				Code:    28747,
+				Name:    "Location28747",
+				Message: "size argument to $sample must not be negative",
+			},
+		},
+		"UnknownOption": {
+			stage: bson.D{{"$sample", bson.D{{"size", int32(1)}, {"foo", int32(1)}}}},
+			err: &mongo.CommandError{
+				Code:    28748,
+				Name:    "Location28748",
+				Message: "unrecognized option to $sample: foo",
+			},
+		},
+		"MissingSize": {
+			stage: bson.D{{"$sample", bson.D{}}},
+			err: &mongo.CommandError{
+				Code:    28749,
+				Name:    "Location28749",
+				Message: "$sample stage must specify a size",
+			},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := collection.Aggregate(ctx, bson.A{tc.stage})
+			AssertEqualCommandError(t, *tc.err, err)
+		})
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/FerretDB/FerretDB/integration/shareddata"
+)
+
+func TestAggregateCompatString(t *testing.T) {
+	t.Parallel()
+
+	providers := []shareddata.Provider{
+		shareddata.Strings,
+		shareddata.Nulls,
+		shareddata.Unsets,
+	}
+
+	testCases := map[string]aggregateStagesCompatTestCase{
+		"Concat": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$concat", bson.A{"$v", "!"}}}},
+			}}}},
+		},
+		"ToLower": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$toLower", "$v"}}},
+			}}}},
+		},
+		"ToUpper": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$toUpper", "$v"}}},
+			}}}},
+		},
+		"Strcasecmp": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$strcasecmp", bson.A{"$v", "FOO"}}}},
+			}}}},
+		},
+		"StrLenBytes": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$strLenBytes", bson.D{{"$ifNull", bson.A{"$v", ""}}}}}},
+			}}}},
+		},
+		"StrLenCP": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$strLenCP", bson.D{{"$ifNull", bson.A{"$v", ""}}}}}},
+			}}}},
+		},
+		"Substr": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$substr", bson.A{"$v", int32(1), int32(2)}}}},
+			}}}},
+		},
+		"SubstrBytes": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$substrBytes", bson.A{"$v", int32(1), int32(-1)}}}},
+			}}}},
+		},
+		"SubstrCP": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$substrCP", bson.A{"$v", int32(0), int32(2)}}}},
+			}}}},
+		},
+		"Trim": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$trim", bson.D{{"input", "$v"}}}}},
+			}}}},
+		},
+		"TrimChars": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$trim", bson.D{{"input", "$v"}, {"chars", "f4"}}}}},
+			}}}},
+		},
+		"LTrim": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$ltrim", bson.D{{"input", "$v"}, {"chars", "f"}}}}},
+			}}}},
+		},
+		"RTrim": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$rtrim", bson.D{{"input", "$v"}, {"chars", "o"}}}}},
+			}}}},
+		},
+		"Split": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$split", bson.A{"$v", "o"}}}},
+			}}}},
+		},
+		"SplitNotFound": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$split", bson.A{"$v", "-"}}}},
+			}}}},
+		},
+		"IndexOfBytes": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$indexOfBytes", bson.A{"$v", "o"}}}},
+			}}}},
+		},
+		"IndexOfBytesStart": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$indexOfBytes", bson.A{"$v", "o", int32(2)}}}},
+			}}}},
+		},
+		"IndexOfBytesEnd": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$indexOfBytes", bson.A{"$v", "2", int32(0), int32(1)}}}},
+			}}}},
+		},
+		"IndexOfCP": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$indexOfCP", bson.A{"$v", "2"}}}},
+			}}}},
+		},
+		"ReplaceOne": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$replaceOne", bson.D{{"input", "$v"}, {"find", "o"}, {"replacement", "0"}}}}},
+			}}}},
+		},
+		"ReplaceAll": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$replaceAll", bson.D{{"input", "$v"}, {"find", "o"}, {"replacement", "0"}}}}},
+			}}}},
+		},
+		"RegexMatch": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$regexMatch", bson.D{{"input", "$v"}, {"regex", "^F"}, {"options", "i"}}}}},
+			}}}},
+		},
+		"RegexMatchRegex": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$regexMatch", bson.D{
+					{"input", "$v"},
+					{"regex", primitive.Regex{Pattern: `\d+$`}},
+				}}}},
+			}}}},
+		},
+		"RegexFind": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$regexFind", bson.D{{"input", "$v"}, {"regex", `(\d)(\.)?`}}}}},
+			}}}},
+		},
+		"RegexFindAll": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{
+				{"res", bson.D{{"$regexFindAll", bson.D{{"input", "$v"}, {"regex", "o"}}}}},
+			}}}},
+		},
+	}
+
+	testAggregateStagesCompatWithProviders(t, providers, testCases)
+}
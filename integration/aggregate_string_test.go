@@ -0,0 +1,258 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/FerretDB/FerretDB/integration/setup"
+)
+
+func TestAggregateString(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertOne(ctx, bson.D{
+		{"_id", "v"},
+		{"string", "Hello, World"},
+		{"unicode", "café ☕"},
+		{"int", int32(42)},
+		{"null", nil},
+	})
+	require.NoError(t, err)
+
+	match := func(m string, idx int32, captures ...any) bson.D {
+		if captures == nil {
+			captures = bson.A{}
+		}
+
+		return bson.D{{"match", m}, {"idx", idx}, {"captures", bson.A(captures)}}
+	}
+
+	for name, tc := range map[string]struct {
+		expression any
+		expected   any
+	}{
+		"ConcatMissing":       {bson.D{{"$concat", bson.A{"$string", "$missing"}}}, nil},
+		"ToUpperInt":          {bson.D{{"$toUpper", "$int"}}, "42"},
+		"ToUpperNull":         {bson.D{{"$toUpper", "$null"}}, ""},
+		"ToUpperUnicode":      {bson.D{{"$toUpper", "$unicode"}}, "CAFé ☕"},
+		"StrLenBytes":         {bson.D{{"$strLenBytes", "$unicode"}}, int32(9)},
+		"StrLenCP":            {bson.D{{"$strLenCP", "$unicode"}}, int32(6)},
+		"SubstrBytesNegative": {bson.D{{"$substrBytes", bson.A{"$string", int32(7), int32(-1)}}}, "World"},
+		"SubstrBytesOutside":  {bson.D{{"$substrBytes", bson.A{"$string", int32(100), int32(1)}}}, ""},
+		"SubstrCP":            {bson.D{{"$substrCP", bson.A{"$unicode", int32(3), int32(3)}}}, "é ☕"},
+		"SubstrCPLong":        {bson.D{{"$substrCP", bson.A{"$unicode", int32(5), int32(100)}}}, "☕"},
+		"IndexOfCP":           {bson.D{{"$indexOfCP", bson.A{"$unicode", "☕"}}}, int32(5)},
+		"IndexOfBytesUnicode": {bson.D{{"$indexOfBytes", bson.A{"$unicode", "☕"}}}, int32(6)},
+		"RegexFind": {
+			bson.D{{"$regexFind", bson.D{{"input", "$unicode"}, {"regex", "(é) (x)?"}}}},
+			match("é ", 3, "é", nil),
+		},
+		"RegexFindAllNull": {bson.D{{"$regexFindAll", bson.D{{"input", "$missing"}, {"regex", "o"}}}}, bson.A{}},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			pipeline := bson.A{bson.D{{"$project", bson.D{{"_id", 0}, {"res", tc.expression}}}}}
+
+			cursor, err := collection.Aggregate(ctx, pipeline)
+			require.NoError(t, err)
+
+			var res []bson.D
+			require.NoError(t, cursor.All(ctx, &res))
+			require.Len(t, res, 1)
+
+			assert.Equal(t, bson.D{{"res", tc.expected}}, res[0])
+		})
+	}
+}
+
+func TestAggregateStringErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertOne(ctx, bson.D{
+		{"_id", "v"},
+		{"string", "café"},
+		{"int", int32(42)},
+	})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		expression any
+		err        *mongo.CommandError
+	}{
+		"ConcatInt": {
+			expression: bson.D{{"$concat", bson.A{"$string", "$int"}}},
+			err: &mongo.CommandError{
+				Code:    16702,
+				Name:    "Location16702",
+				Message: "$concat only supports strings, not int",
+			},
+		},
+		"ToLowerArray": {
+			expression: bson.D{{"$toLower", bson.A{bson.A{"a"}}}},
+			err: &mongo.CommandError{
+				Code:    16007,
+				Name:    "Location16007",
+				Message: "can't convert from BSON type array to String",
+			},
+		},
+		"StrLenCPInt": {
+			expression: bson.D{{"$strLenCP", "$int"}},
+			err: &mongo.CommandError{
+				Code:    34471,
+				Name:    "Location34471",
+				Message: "$strLenCP requires a string argument, found: int",
+			},
+		},
+		"StrLenBytesMissing": {
+			expression: bson.D{{"$strLenBytes", "$missing"}},
+			err: &mongo.CommandError{
+				Code:    34473,
+				Name:    "Location34473",
+				Message: "$strLenBytes requires a string argument, found: missing",
+			},
+		},
+		"SubstrBytesContinuation": {
+			expression: bson.D{{"$substrBytes", bson.A{"$string", int32(4), int32(1)}}},
+			err: &mongo.CommandError{
+				Code:    28656,
+				Name:    "Location28656",
+				Message: "$substrBytes:  Invalid range, starting index is a UTF-8 continuation byte.",
+			},
+		},
+		"SubstrBytesStartString": {
+			expression: bson.D{{"$substrBytes", bson.A{"$string", "a", int32(1)}}},
+			err: &mongo.CommandError{
+				Code:    16034,
+				Name:    "Location16034",
+				Message: "$substrBytes: starting index must be a numeric type (is BSON type string)",
+			},
+		},
+		"SubstrCPNegativeLength": {
+			expression: bson.D{{"$substrCP", bson.A{"$string", int32(0), int32(-1)}}},
+			err: &mongo.CommandError{
+				Code:    34454,
+				Name:    "Location34454",
+				Message: "$substrCP: length must be a nonnegative integer.",
+			},
+		},
+		"TrimNotObject": {
+			expression: bson.D{{"$trim", "$string"}},
+			err: &mongo.CommandError{
+				Code:    50696,
+				Name:    "Location50696",
+				Message: "Invalid $project :: caused by :: $trim only supports an object as an argument, found: string",
+			},
+		},
+		"TrimMissingInput": {
+			expression: bson.D{{"$trim", bson.D{{"chars", "a"}}}},
+			err: &mongo.CommandError{
+				Code:    50695,
+				Name:    "Location50695",
+				Message: "Invalid $project :: caused by :: $trim requires an 'input' field",
+			},
+		},
+		"TrimInt": {
+			expression: bson.D{{"$trim", bson.D{{"input", "$int"}}}},
+			err: &mongo.CommandError{
+				Code:    50699,
+				Name:    "Location50699",
+				Message: "$trim requires its input to be a string, got 42 (of type int) instead.",
+			},
+		},
+		"SplitEmptyDelimiter": {
+			expression: bson.D{{"$split", bson.A{"$string", ""}}},
+			err: &mongo.CommandError{
+				Code:    40087,
+				Name:    "Location40087",
+				Message: "$split requires a non-empty separator",
+			},
+		},
+		"IndexOfCPNegativeStart": {
+			expression: bson.D{{"$indexOfCP", bson.A{"$string", "a", int32(-1)}}},
+			err: &mongo.CommandError{
+				Code:    40097,
+				Name:    "Location40097",
+				Message: "$indexOfCP requires a nonnegative start index, found: -1",
+			},
+		},
+		"ReplaceMissingFind": {
+			expression: bson.D{{"$replaceOne", bson.D{{"input", "$string"}, {"replacement", "a"}}}},
+			err: &mongo.CommandError{
+				Code:    51748,
+				Name:    "Location51748",
+				Message: "Invalid $project :: caused by :: $replaceOne requires 'find' to be specified",
+			},
+		},
+		"RegexMissingRegex": {
+			expression: bson.D{{"$regexMatch", bson.D{{"input", "$string"}}}},
+			err: &mongo.CommandError{
+				Code:    31023,
+				Name:    "Location31023",
+				Message: "Invalid $project :: caused by :: $regexMatch requires 'regex' parameter",
+			},
+		},
+		"RegexInputInt": {
+			expression: bson.D{{"$regexFind", bson.D{{"input", "$int"}, {"regex", "a"}}}},
+			err: &mongo.CommandError{
+				Code:    51104,
+				Name:    "Location51104",
+				Message: "$regexFind needs 'input' to be of type string",
+			},
+		},
+		"RegexOptionsConflict": {
+			expression: bson.D{{"$regexMatch", bson.D{
+				{"input", "$string"},
+				{"regex", primitive.Regex{Pattern: "a", Options: "i"}},
+				{"options", "m"},
+			}}},
+			err: &mongo.CommandError{
+				Code:    51107,
+				Name:    "Location51107",
+				Message: "$regexMatch found regex option(s) specified in both 'regex' and 'option' fields",
+			},
+		},
+		"RegexInvalidFlag": {
+			expression: bson.D{{"$regexMatch", bson.D{{"input", "$string"}, {"regex", "a"}, {"options", "z"}}}},
+			err: &mongo.CommandError{
+				Code:    51108,
+				Name:    "Location51108",
+				Message: "$regexMatch invalid flag in regex options: z",
+			},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			pipeline := bson.A{bson.D{{"$project", bson.D{{"res", tc.expression}}}}}
+
+			_, err := collection.Aggregate(ctx, pipeline)
+			AssertEqualCommandError(t, *tc.err, err)
+		})
+	}
+}
@@ -69,7 +69,6 @@ func TestAggregateVariablesCompatRoot(t *testing.T) {
 				}}},
 				bson.D{{"$sort", bson.D{{"unique", 1}}}},
 			},
-			skip: "https://github.com/FerretDB/FerretDB/issues/2185",
 		},
 		"GroupIDFieldDotNotation": {
 			pipeline: bson.A{
@@ -0,0 +1,368 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/FerretDB/FerretDB/integration/setup"
+)
+
+func TestAggregateSetWindowFields(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", int32(1)}, {"team", "a"}, {"score", int32(10)}},
+		bson.D{{"_id", int32(2)}, {"team", "a"}, {"score", int32(20)}},
+		bson.D{{"_id", int32(3)}, {"team", "a"}, {"score", int32(20)}},
+		bson.D{{"_id", int32(4)}, {"team", "b"}, {"score", int32(5)}},
+		bson.D{{"_id", int32(5)}, {"team", "b"}, {"score", int32(15)}},
+	})
+	require.NoError(t, err)
+
+	byScore := bson.D{{"score", int32(1)}}
+	byScoreDesc := bson.D{{"score", int32(-1)}}
+	byID := bson.D{{"_id", int32(1)}}
+
+	for name, tc := range map[string]struct {
+		sortBy   bson.D // optional
+		output   bson.D // required
+		expected bson.A // required, values of output field sorted by team, then by sortBy
+	}{
+		"Sum": {
+			sortBy:   byScore,
+			output:   bson.D{{"$sum", "$score"}},
+			expected: bson.A{int32(50), int32(50), int32(50), int32(20), int32(20)},
+		},
+		"SumWithoutSortBy": {
+			output:   bson.D{{"$sum", "$score"}},
+			expected: bson.A{int32(50), int32(50), int32(50), int32(20), int32(20)},
+		},
+		"RunningSum": {
+			sortBy:   byScore,
+			output:   bson.D{{"$sum", "$score"}, {"window", bson.D{{"documents", bson.A{"unbounded", "current"}}}}},
+			expected: bson.A{int32(10), int32(30), int32(50), int32(5), int32(20)},
+		},
+		"MovingAvg": {
+			sortBy:   byScore,
+			output:   bson.D{{"$avg", "$score"}, {"window", bson.D{{"documents", bson.A{int32(-1), int32(0)}}}}},
+			expected: bson.A{10.0, 15.0, 20.0, 5.0, 10.0},
+		},
+		"RangeSum": {
+			sortBy:   byScore,
+			output:   bson.D{{"$sum", "$score"}, {"window", bson.D{{"range", bson.A{int32(-10), "current"}}}}},
+			expected: bson.A{int32(10), int32(50), int32(50), int32(5), int32(20)},
+		},
+		"Push": {
+			sortBy: byScore,
+			output: bson.D{{"$push", "$score"}, {"window", bson.D{{"documents", bson.A{"current", int32(1)}}}}},
+			expected: bson.A{
+				bson.A{int32(10), int32(20)}, bson.A{int32(20), int32(20)}, bson.A{int32(20)},
+				bson.A{int32(5), int32(15)}, bson.A{int32(15)},
+			},
+		},
+		"Count": {
+			sortBy:   byScore,
+			output:   bson.D{{"$count", bson.D{}}, {"window", bson.D{{"documents", bson.A{int32(1), "unbounded"}}}}},
+			expected: bson.A{int32(2), int32(1), int32(0), int32(1), int32(0)},
+		},
+		"Rank": {
+			sortBy:   byScoreDesc,
+			output:   bson.D{{"$rank", bson.D{}}},
+			expected: bson.A{int32(1), int32(1), int32(3), int32(1), int32(2)},
+		},
+		"DenseRank": {
+			sortBy:   byScoreDesc,
+			output:   bson.D{{"$denseRank", bson.D{}}},
+			expected: bson.A{int32(1), int32(1), int32(2), int32(1), int32(2)},
+		},
+		"DocumentNumber": {
+			sortBy:   byScoreDesc,
+			output:   bson.D{{"$documentNumber", bson.D{}}},
+			expected: bson.A{int32(1), int32(2), int32(3), int32(1), int32(2)},
+		},
+		"Shift": {
+			sortBy:   byScore,
+			output:   bson.D{{"$shift", bson.D{{"output", "$score"}, {"by", int32(1)}, {"default", int32(-1)}}}},
+			expected: bson.A{int32(20), int32(20), int32(-1), int32(15), int32(-1)},
+		},
+		"ShiftBack": {
+			sortBy:   byScore,
+			output:   bson.D{{"$shift", bson.D{{"output", "$score"}, {"by", int32(-1)}}}},
+			expected: bson.A{nil, int32(10), int32(20), nil, int32(5)},
+		},
+		"ExpMovingAvg": {
+			sortBy:   byScore,
+			output:   bson.D{{"$expMovingAvg", bson.D{{"input", "$score"}, {"alpha", 0.5}}}},
+			expected: bson.A{10.0, 15.0, 17.5, 5.0, 10.0},
+		},
+		"Derivative": {
+			sortBy: byID,
+			output: bson.D{
+				{"$derivative", bson.D{{"input", "$score"}}},
+				{"window", bson.D{{"documents", bson.A{int32(-1), int32(0)}}}},
+			},
+			expected: bson.A{nil, 10.0, 0.0, nil, 10.0},
+		},
+		"Integral": {
+			sortBy: byID,
+			output: bson.D{
+				{"$integral", bson.D{{"input", "$score"}}},
+				{"window", bson.D{{"documents", bson.A{"unbounded", "current"}}}},
+			},
+			expected: bson.A{0.0, 15.0, 35.0, 0.0, 10.0},
+		},
+		"CovariancePop": {
+			sortBy: byID,
+			output: bson.D{
+				{"$covariancePop", bson.A{"$_id", "$score"}},
+				{"window", bson.D{{"documents", bson.A{int32(-1), int32(0)}}}},
+			},
+			expected: bson.A{0.0, 2.5, 0.0, 0.0, 2.5},
+		},
+		"CovarianceSamp": {
+			sortBy: byID,
+			output: bson.D{
+				{"$covarianceSamp", bson.A{"$_id", "$score"}},
+				{"window", bson.D{{"documents", bson.A{int32(-1), int32(0)}}}},
+			},
+			expected: bson.A{nil, 5.0, 0.0, nil, 5.0},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			spec := bson.D{{"partitionBy", "$team"}}
+			if tc.sortBy != nil {
+				spec = append(spec, bson.E{"sortBy", tc.sortBy})
+			}
+
+			spec = append(spec, bson.E{"output", bson.D{{"res", tc.output}}})
+
+			pipeline := bson.A{
+				bson.D{{"$setWindowFields", spec}},
+				bson.D{{"$project", bson.D{{"_id", 0}, {"team", 1}, {"res", 1}}}},
+			}
+
+			cursor, err := collection.Aggregate(ctx, pipeline)
+			require.NoError(t, err)
+
+			var res []bson.D
+			require.NoError(t, cursor.All(ctx, &res))
+			require.Len(t, res, len(tc.expected))
+
+			for i, doc := range res {
+				team := "a"
+				if i >= 3 {
+					team = "b"
+				}
+
+				assert.Equal(t, bson.D{{"team", team}, {"res", tc.expected[i]}}, doc)
+			}
+		})
+	}
+}
+
+func TestAggregateSetWindowFieldsDates(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	day := func(d int) primitive.DateTime {
+		return primitive.NewDateTimeFromTime(time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC))
+	}
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", int32(1)}, {"d", day(1)}, {"v", int32(10)}},
+		bson.D{{"_id", int32(2)}, {"d", day(2)}, {"v", nil}},
+		bson.D{{"_id", int32(3)}, {"d", day(4)}},
+		bson.D{{"_id", int32(4)}, {"d", day(5)}, {"v", int32(50)}},
+	})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		output   bson.D
+		expected bson.A
+	}{
+		"Locf": {
+			output:   bson.D{{"$locf", "$v"}},
+			expected: bson.A{int32(10), int32(10), int32(10), int32(50)},
+		},
+		"LinearFill": {
+			output:   bson.D{{"$linearFill", "$v"}},
+			expected: bson.A{int32(10), 20.0, 40.0, int32(50)},
+		},
+		"RangeDays": {
+			output: bson.D{
+				{"$push", "$_id"},
+				{"window", bson.D{{"range", bson.A{int32(-1), "current"}}, {"unit", "day"}}},
+			},
+			expected: bson.A{bson.A{int32(1)}, bson.A{int32(1), int32(2)}, bson.A{int32(3)}, bson.A{int32(3), int32(4)}},
+		},
+		"IntegralDays": {
+			output: bson.D{
+				{"$integral", bson.D{{"input", "$v"}, {"unit", "day"}}},
+				{"window", bson.D{{"documents", bson.A{"unbounded", "current"}}}},
+			},
+			expected: bson.A{0.0, 0.0, 0.0, 120.0},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			pipeline := bson.A{
+				bson.D{{"$setWindowFields", bson.D{
+					{"sortBy", bson.D{{"d", int32(1)}}},
+					{"output", bson.D{{"res", tc.output}}},
+				}}},
+				bson.D{{"$project", bson.D{{"res", 1}}}},
+			}
+
+			cursor, err := collection.Aggregate(ctx, pipeline)
+			require.NoError(t, err)
+
+			var res []bson.D
+			require.NoError(t, cursor.All(ctx, &res))
+			require.Len(t, res, len(tc.expected))
+
+			for i, doc := range res {
+				assert.Equal(t, bson.D{{"_id", int32(i + 1)}, {"res", tc.expected[i]}}, doc)
+			}
+		})
+	}
+}
+
+func TestAggregateSetWindowFieldsErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertOne(ctx, bson.D{{"_id", int32(1)}, {"v", "foo"}})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		stage any
+		err   *mongo.CommandError
+	}{
+		"NotObject": {
+			stage: int32(1),
+			err: &mongo.CommandError{
+				Code:    9,
+				Name:    "FailedToParse",
+				Message: "the $setWindowFields stage specification must be an object, found int",
+			},
+		},
+		"UnknownField": {
+			stage: bson.D{{"foo", int32(1)}, {"output", bson.D{}}},
+			err: &mongo.CommandError{
+				Code:    40415,
+				Name:    "Location40415",
+				Message: "BSON field '$setWindowFields.foo' is an unknown field.",
+			},
+		},
+		"MissingOutput": {
+			stage: bson.D{{"partitionBy", "$v"}},
+			err: &mongo.CommandError{
+				Code:    40414,
+				Name:    "Location40414",
+				Message: "BSON field '$setWindowFields.output' is missing but a required field",
+			},
+		},
+		"OutputNotObject": {
+			stage: bson.D{{"output", int32(1)}},
+			err: &mongo.CommandError{
+				Code:    14,
+				Name:    "TypeMismatch",
+				Message: "BSON field '$setWindowFields.output' is the wrong type 'int', expected type 'object'",
+			},
+		},
+		"UnknownFunction": {
+			stage: bson.D{{"output", bson.D{{"res", bson.D{{"$foo", int32(1)}}}}}},
+			err: &mongo.CommandError{
+				Code:    9,
+				Name:    "FailedToParse",
+				Message: "Unrecognized window function, $foo",
+			},
+		},
+		"RankWithoutSortBy": {
+			stage: bson.D{{"output", bson.D{{"res", bson.D{{"$rank", bson.D{}}}}}}},
+			err: &mongo.CommandError{
+				Code:    9,
+				Name:    "FailedToParse",
+				Message: "$rank must be specified with a top level sortBy expression with exactly one element",
+			},
+		},
+		"BoundsNotArray": {
+			stage: bson.D{{"output", bson.D{{"res", bson.D{
+				{"$sum", "$v"},
+				{"window", bson.D{{"documents", int32(1)}}},
+			}}}}},
+			err: &mongo.CommandError{
+				Code:    9,
+				Name:    "FailedToParse",
+				Message: "Window bounds must be a 2-element array: 1",
+			},
+		},
+		"DocumentsWithoutSortBy": {
+			stage: bson.D{{"output", bson.D{{"res", bson.D{
+				{"$sum", "$v"},
+				{"window", bson.D{{"documents", bson.A{int32(-1), int32(0)}}}},
+			}}}}},
+			err: &mongo.CommandError{
+				Code:    9,
+				Name:    "FailedToParse",
+				Message: "Document-based bounds require a sortBy",
+			},
+		},
+		"RangeNotNumber": {
+			stage: bson.D{{"sortBy", bson.D{{"v", int32(1)}}}, {"output", bson.D{{"res", bson.D{
+				{"$sum", int32(1)},
+				{"window", bson.D{{"range", bson.A{int32(-1), int32(0)}}}},
+			}}}}},
+			err: &mongo.CommandError{
+				Code:    2,
+				Name:    "BadValue",
+				Message: "Invalid range: Expected the sortBy field to be a number, but it was string",
+			},
+		},
+		"PartitionByArray": {
+			stage: bson.D{{"partitionBy", bson.A{"$v"}}, {"output", bson.D{}}},
+			err: &mongo.CommandError{
+				Code:    2,
+				Name:    "BadValue",
+				Message: "An expression used to partition cannot evaluate to value of type array",
+			},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := collection.Aggregate(ctx, bson.A{bson.D{{"$setWindowFields", tc.stage}}})
+			AssertEqualCommandError(t, *tc.err, err)
+		})
+	}
+}
@@ -0,0 +1,254 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/FerretDB/FerretDB/integration/setup"
+)
+
+// collationDocs returns documents used by collation tests.
+func collationDocs() []any {
+	return []any{
+		bson.D{{"_id", "lower"}, {"v", "cafe"}},
+		bson.D{{"_id", "upper"}, {"v", "CAFE"}},
+		bson.D{{"_id", "accent"}, {"v", "café"}},
+		bson.D{{"_id", "other"}, {"v", "Banana"}},
+		bson.D{{"_id", "number"}, {"v", int32(42)}},
+	}
+}
+
+func TestCollationFind(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertMany(ctx, collationDocs())
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		filter    bson.D
+		sort      bson.D
+		collation *options.Collation
+		expected  []any
+	}{
+		"Simple": {
+			filter:    bson.D{{"v", "cafe"}},
+			collation: &options.Collation{Locale: "simple"},
+			expected:  []any{"lower"},
+		},
+		"Strength1": {
+			filter:    bson.D{{"v", "cafe"}},
+			collation: &options.Collation{Locale: "en", Strength: 1},
+			expected:  []any{"accent", "lower", "upper"},
+		},
+		"Strength2": {
+			filter:    bson.D{{"v", "cafe"}},
+			collation: &options.Collation{Locale: "en", Strength: 2},
+			expected:  []any{"lower", "upper"},
+		},
+		"Strength3": {
+			filter:    bson.D{{"v", "cafe"}},
+			collation: &options.Collation{Locale: "en", Strength: 3},
+			expected:  []any{"lower"},
+		},
+		"Gt": {
+			filter:    bson.D{{"v", bson.D{{"$gt", "c"}}}},
+			collation: &options.Collation{Locale: "en", Strength: 2},
+			expected:  []any{"accent", "lower", "upper"},
+		},
+		"Sort": {
+			sort:      bson.D{{"v", 1}, {"_id", 1}},
+			collation: &options.Collation{Locale: "en", Strength: 2},
+			expected:  []any{"number", "other", "lower", "upper", "accent"},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			filter := tc.filter
+			if filter == nil {
+				filter = bson.D{}
+			}
+
+			sort := tc.sort
+			if sort == nil {
+				sort = bson.D{{"_id", 1}}
+			}
+
+			opts := options.Find().SetCollation(tc.collation).SetSort(sort)
+
+			cursor, err := collection.Find(ctx, filter, opts)
+			require.NoError(t, err)
+
+			var res []bson.D
+			require.NoError(t, cursor.All(ctx, &res))
+
+			assert.Equal(t, tc.expected, CollectIDs(t, res))
+		})
+	}
+}
+
+func TestCollationAggregate(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertMany(ctx, collationDocs())
+	require.NoError(t, err)
+
+	pipeline := bson.A{
+		bson.D{{"$match", bson.D{{"v", bson.D{{"$in", bson.A{"CAFE", "banana"}}}}}}},
+		bson.D{{"$sort", bson.D{{"v", -1}, {"_id", 1}}}},
+	}
+
+	opts := options.Aggregate().SetCollation(&options.Collation{Locale: "en", Strength: 2})
+
+	cursor, err := collection.Aggregate(ctx, pipeline, opts)
+	require.NoError(t, err)
+
+	var res []bson.D
+	require.NoError(t, cursor.All(ctx, &res))
+
+	assert.Equal(t, []any{"lower", "upper", "other"}, CollectIDs(t, res))
+}
+
+func TestCollationUpdate(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertMany(ctx, collationDocs())
+	require.NoError(t, err)
+
+	opts := options.Update().SetCollation(&options.Collation{Locale: "en", Strength: 1})
+
+	res, err := collection.UpdateMany(ctx, bson.D{{"v", "CAFÉ"}}, bson.D{{"$set", bson.D{{"matched", true}}}}, opts)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), res.MatchedCount)
+}
+
+func TestCollationErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	for name, tc := range map[string]struct {
+		collation  any
+		err        *mongo.CommandError
+		altMessage string
+	}{
+		"MissingLocale": {
+			collation: bson.D{{"strength", int32(2)}},
+			err: &mongo.CommandError{
+				Code:    40414,
+				Name:    "Location40414",
+				Message: "BSON field 'collation.locale' is missing but a required field",
+			},
+		},
+		"UnknownField": {
+			collation: bson.D{{"locale", "en"}, {"foo", int32(1)}},
+			err: &mongo.CommandError{
+				Code:    40415,
+				Name:    "Location40415",
+				Message: "BSON field 'collation.foo' is an unknown field.",
+			},
+		},
+		"StrengthOutOfRange": {
+			collation: bson.D{{"locale", "en"}, {"strength", int32(6)}},
+			err: &mongo.CommandError{
+				Code:    2,
+				Name:    "BadValue",
+				Message: "unable to parse collation :: caused by :: strength must be 1-5, got 6",
+			},
+			altMessage: "unable to parse collation :: caused by :: Enumeration value '6' for field 'strength' is not a valid value.",
+		},
+		"InvalidLocale": {
+			collation: bson.D{{"locale", "not a locale"}},
+			err: &mongo.CommandError{
+				Code:    2,
+				Name:    "BadValue",
+				Message: `Field 'locale' is invalid in: { locale: "not a locale" }`,
+			},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			res := collection.Database().RunCommand(ctx, bson.D{
+				{"find", collection.Name()},
+				{"collation", tc.collation},
+			})
+			AssertEqualAltCommandError(t, *tc.err, tc.altMessage, res.Err())
+		})
+	}
+}
+
+func TestCollationIndex(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	collation := &options.Collation{Locale: "en", Strength: 2}
+
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{"v", 1}},
+		Options: options.Index().SetUnique(true).SetCollation(collation),
+	})
+
+	if !setup.IsPostgreSQL(t) && !setup.IsMongoDB(t) {
+		AssertEqualCommandError(t, mongo.CommandError{
+			Code:    238,
+			Name:    "NotImplemented",
+			Message: `Index option "collation" is not supported by this backend`,
+		}, err)
+
+		return
+	}
+
+	require.NoError(t, err)
+
+	var indexes []bson.D
+
+	cursor, err := collection.Indexes().List(ctx)
+	require.NoError(t, err)
+	require.NoError(t, cursor.All(ctx, &indexes))
+
+	require.Len(t, indexes, 2)
+
+	c, ok := indexes[1].Map()["collation"].(bson.D)
+	require.True(t, ok)
+	assert.Equal(t, "en", c.Map()["locale"])
+	assert.Equal(t, int32(2), c.Map()["strength"])
+
+	_, err = collection.InsertMany(ctx, []any{
+		bson.D{{"_id", "lower"}, {"v", "cafe"}},
+		bson.D{{"_id", "accent"}, {"v", "café"}},
+	})
+	require.NoError(t, err)
+
+	// strings that are equal for the collation are duplicates
+	_, err = collection.InsertOne(ctx, bson.D{{"_id", "upper"}, {"v", "CAFE"}})
+	assert.True(t, mongo.IsDuplicateKeyError(err), "%v", err)
+}
@@ -0,0 +1,204 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/FerretDB/FerretDB/integration/setup"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// decimal returns Decimal128 value for the given string.
+func decimal(s string) primitive.Decimal128 {
+	return must.NotFail(primitive.ParseDecimal128(s))
+}
+
+func TestDecimal128Query(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", "decimal"}, {"v", decimal("1.50")}},
+		bson.D{{"_id", "decimal-int"}, {"v", decimal("2.0")}},
+		bson.D{{"_id", "double"}, {"v", 1.25}},
+		bson.D{{"_id", "int"}, {"v", int32(2)}},
+		bson.D{{"_id", "string"}, {"v", "1.5"}},
+	})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		filter   bson.D
+		expected []string
+	}{
+		"EqDouble": {
+			filter:   bson.D{{"v", 1.5}},
+			expected: []string{"decimal"},
+		},
+		"EqInt": {
+			filter:   bson.D{{"v", int32(2)}},
+			expected: []string{"decimal-int", "int"},
+		},
+		"EqDecimal": {
+			filter:   bson.D{{"v", decimal("2")}},
+			expected: []string{"decimal-int", "int"},
+		},
+		"Gt": {
+			filter:   bson.D{{"v", bson.D{{"$gt", decimal("1.3")}}}},
+			expected: []string{"decimal", "decimal-int", "int"},
+		},
+		"TypeAlias": {
+			filter:   bson.D{{"v", bson.D{{"$type", "decimal"}}}},
+			expected: []string{"decimal", "decimal-int"},
+		},
+		"TypeCode": {
+			filter:   bson.D{{"v", bson.D{{"$type", int32(19)}}}},
+			expected: []string{"decimal", "decimal-int"},
+		},
+		"TypeNumber": {
+			filter:   bson.D{{"v", bson.D{{"$type", "number"}}}},
+			expected: []string{"decimal", "decimal-int", "double", "int"},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cursor, err := collection.Find(ctx, tc.filter, options.Find().SetSort(bson.D{{"_id", 1}}))
+			require.NoError(t, err)
+
+			var res []bson.D
+			require.NoError(t, cursor.All(ctx, &res))
+
+			ids := make([]string, len(res))
+			for i, doc := range res {
+				ids[i] = doc.Map()["_id"].(string)
+			}
+
+			assert.Equal(t, tc.expected, ids)
+		})
+	}
+
+	t.Run("Sort", func(t *testing.T) {
+		t.Parallel()
+
+		opts := options.Find().SetSort(bson.D{{"v", 1}, {"_id", 1}}).SetProjection(bson.D{{"_id", 1}})
+		cursor, err := collection.Find(ctx, bson.D{}, opts)
+		require.NoError(t, err)
+
+		var res []bson.D
+		require.NoError(t, cursor.All(ctx, &res))
+
+		expected := []bson.D{
+			{{"_id", "double"}},
+			{{"_id", "decimal"}},
+			{{"_id", "decimal-int"}},
+			{{"_id", "int"}},
+			{{"_id", "string"}},
+		}
+		assert.Equal(t, expected, res)
+	})
+}
+
+func TestDecimal128Precision(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	v := decimal("1.234567890123456789012345678901234")
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", "array"}, {"v", bson.A{"foo", decimal("3.00")}}},
+		bson.D{{"_id", "precise"}, {"v", v}},
+	})
+	require.NoError(t, err)
+
+	var res bson.D
+	require.NoError(t, collection.FindOne(ctx, bson.D{{"v", v}}).Decode(&res))
+	assert.Equal(t, bson.D{{"_id", "precise"}, {"v", v}}, res)
+
+	require.NoError(t, collection.FindOne(ctx, bson.D{{"v", int32(3)}}).Decode(&res))
+	assert.Equal(t, bson.D{{"_id", "array"}, {"v", bson.A{"foo", decimal("3.00")}}}, res)
+}
+
+func TestDecimal128Update(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		v        any
+		update   bson.D
+		expected any
+	}{
+		"IncDecimal": {
+			v:        decimal("0.1"),
+			update:   bson.D{{"$inc", bson.D{{"v", decimal("0.2")}}}},
+			expected: decimal("0.3"),
+		},
+		"IncIntByDecimal": {
+			v:        int32(1),
+			update:   bson.D{{"$inc", bson.D{{"v", decimal("0.50")}}}},
+			expected: decimal("1.50"),
+		},
+		"IncDecimalByDouble": {
+			v:        decimal("1"),
+			update:   bson.D{{"$inc", bson.D{{"v", 1.5}}}},
+			expected: decimal("2.50000000000000"),
+		},
+		"MulDecimal": {
+			v:        decimal("0.1"),
+			update:   bson.D{{"$mul", bson.D{{"v", decimal("0.2")}}}},
+			expected: decimal("0.02"),
+		},
+		"MulDecimalByLong": {
+			v:        decimal("-1.5"),
+			update:   bson.D{{"$mul", bson.D{{"v", int64(4)}}}},
+			expected: decimal("-6.0"),
+		},
+		"MulMissing": {
+			v:        nil,
+			update:   bson.D{{"$mul", bson.D{{"v", decimal("1.5")}}}},
+			expected: decimal("0"),
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, collection := setup.Setup(t)
+
+			doc := bson.D{{"_id", "test"}}
+			if tc.v != nil {
+				doc = append(doc, bson.E{"v", tc.v})
+			}
+
+			_, err := collection.InsertOne(ctx, doc)
+			require.NoError(t, err)
+
+			_, err = collection.UpdateOne(ctx, bson.D{{"_id", "test"}}, tc.update)
+			require.NoError(t, err)
+
+			var res bson.D
+			require.NoError(t, collection.FindOne(ctx, bson.D{{"_id", "test"}}).Decode(&res))
+			assert.Equal(t, bson.D{{"_id", "test"}, {"v", tc.expected}}, res)
+		})
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 
@@ -71,6 +72,13 @@ func TestDiffDocumentValidation(t *testing.T) {
 					Message: `invalid value: { "foo": -Inf } (infinity values are not allowed)`,
 				}}},
 			},
+			"DecimalNaN": {
+				doc: bson.D{{"foo", primitive.NewDecimal128(0x7c00000000000000, 0)}},
+				err: mongo.WriteException{WriteErrors: []mongo.WriteError{{
+					Code:    2,
+					Message: `invalid value: { "foo": NaN } (infinity and NaN values are not allowed)`,
+				}}},
+			},
 		} {
 			name, tc := name, tc
 			t.Run(name, func(t *testing.T) {
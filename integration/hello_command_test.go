@@ -15,6 +15,7 @@
 package integration
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -22,6 +23,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/must"
@@ -58,6 +60,76 @@ func TestHello(t *testing.T) {
 	assert.Equal(t, must.NotFail(actual.Get("ok")), float64(1))
 }
 
+func TestHelloCompression(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+	db := collection.Database()
+
+	for name, tc := range map[string]struct {
+		compression bson.A
+		expected    any // nil if compression field is not expected
+	}{
+		"Supported": {
+			compression: bson.A{"zstd", "snappy"},
+			expected:    must.NotFail(types.NewArray("zstd", "snappy")),
+		},
+		"Mixed": {
+			compression: bson.A{"lz4", "zlib"},
+			expected:    must.NotFail(types.NewArray("zlib")),
+		},
+		"Unsupported": {
+			compression: bson.A{"lz4"},
+		},
+		"Empty": {
+			compression: bson.A{},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var res bson.D
+
+			require.NoError(t, db.RunCommand(ctx, bson.D{
+				{"hello", "1"},
+				{"compression", tc.compression},
+			}).Decode(&res))
+
+			actual := ConvertDocument(t, res)
+
+			compression, _ := actual.Get("compression")
+			assert.Equal(t, tc.expected, compression)
+		})
+	}
+}
+
+func TestHelloCompressedConnection(t *testing.T) {
+	t.Parallel()
+
+	s := setup.SetupWithOpts(t, nil)
+
+	for _, compressor := range []string{"snappy", "zlib", "zstd"} {
+		t.Run(compressor, func(t *testing.T) {
+			t.Parallel()
+
+			client, err := mongo.Connect(s.Ctx, options.Client().ApplyURI(s.MongoDBURI).SetCompressors([]string{compressor}))
+			require.NoError(t, err)
+
+			defer client.Disconnect(s.Ctx)
+
+			collection := client.Database(s.Collection.Database().Name()).Collection(s.Collection.Name())
+
+			_, err = collection.InsertOne(s.Ctx, bson.D{{"_id", compressor}, {"v", strings.Repeat(compressor, 1000)}})
+			require.NoError(t, err)
+
+			var res bson.D
+			require.NoError(t, collection.FindOne(s.Ctx, bson.D{{"_id", compressor}}).Decode(&res))
+
+			AssertEqualDocuments(t, bson.D{{"_id", compressor}, {"v", strings.Repeat(compressor, 1000)}}, res)
+		})
+	}
+}
+
 func TestHelloWithSupportedMechs(t *testing.T) {
 	t.Parallel()
 
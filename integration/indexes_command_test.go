@@ -16,11 +16,13 @@ package integration
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"github.com/FerretDB/FerretDB/integration/setup"
 	"github.com/FerretDB/FerretDB/integration/shareddata"
@@ -404,6 +406,94 @@ func TestCreateIndexesCommandInvalidSpec(t *testing.T) {
 			altMessage: `Error in specification { key: { v: 1 }, name: "unique_index", unique: {  } } ` +
 				`:: caused by :: The field 'unique' has value unique: {  }, which is not convertible to bool`,
 		},
+		"ExpireAfterSecondsCompound": {
+			indexes: bson.A{
+				bson.D{
+					{"key", bson.D{{"a", 1}, {"b", 1}}},
+					{"name", "ttl_index"},
+					{"expireAfterSeconds", int32(10)},
+				},
+			},
+			err: &mongo.CommandError{
+				Code: 67,
+				Name: "CannotCreateIndex",
+				Message: "TTL indexes are single-field indexes, compound indexes do not support TTL. " +
+					`Index spec: { key: { a: 1, b: 1 }, name: "ttl_index", expireAfterSeconds: 10 }`,
+			},
+			altMessage: "TTL indexes are single-field indexes, compound indexes do not support TTL.",
+		},
+		"ExpireAfterSecondsNegative": {
+			indexes: bson.A{
+				bson.D{
+					{"key", bson.D{{"v", 1}}},
+					{"name", "ttl_index"},
+					{"expireAfterSeconds", int32(-1)},
+				},
+			},
+			err: &mongo.CommandError{
+				Code: 67,
+				Name: "CannotCreateIndex",
+				Message: "TTL index 'expireAfterSeconds' option must be within an acceptable range, try a lower number. " +
+					"Got -1",
+			},
+		},
+		"ExpireAfterSecondsString": {
+			indexes: bson.A{
+				bson.D{
+					{"key", bson.D{{"v", 1}}},
+					{"name", "ttl_index"},
+					{"expireAfterSeconds", "10"},
+				},
+			},
+			err: &mongo.CommandError{
+				Code:    67,
+				Name:    "CannotCreateIndex",
+				Message: "TTL index 'expireAfterSeconds' option must be numeric, but received a type of 'string'.",
+			},
+		},
+		"PartialFilterString": {
+			indexes: bson.A{
+				bson.D{
+					{"key", bson.D{{"v", 1}}},
+					{"name", "partial_index"},
+					{"partialFilterExpression", "foo"},
+				},
+			},
+			err: &mongo.CommandError{
+				Code:    14,
+				Name:    "TypeMismatch",
+				Message: "The field 'partialFilterExpression' must be an object, but got string",
+			},
+		},
+		"PartialFilterNe": {
+			indexes: bson.A{
+				bson.D{
+					{"key", bson.D{{"v", 1}}},
+					{"name", "partial_index"},
+					{"partialFilterExpression", bson.D{{"v", bson.D{{"$ne", 1}}}}},
+				},
+			},
+			err: &mongo.CommandError{
+				Code:    67,
+				Name:    "CannotCreateIndex",
+				Message: "Expression not supported in partial index: $not\n    v $eq 1\n",
+			},
+			altMessage: "Expression not supported in partial index: $ne",
+		},
+		"PartialFilterAndEmpty": {
+			indexes: bson.A{
+				bson.D{
+					{"key", bson.D{{"v", 1}}},
+					{"name", "partial_index"},
+					{"partialFilterExpression", bson.D{{"$and", bson.A{}}}},
+				},
+			},
+			err: &mongo.CommandError{
+				Code:    2,
+				Name:    "BadValue",
+				Message: "$and must be a nonempty array",
+			},
+		},
 	} {
 		name, tc := name, tc
 		t.Run(name, func(t *testing.T) {
@@ -446,6 +536,206 @@ func TestCreateIndexesCommandInvalidSpec(t *testing.T) {
 	}
 }
 
+func TestCreateIndexesCommandTTL(t *testing.T) {
+	t.Parallel()
+
+	s := setup.SetupWithOpts(t, &setup.SetupOpts{
+		BackendOptions: &setup.BackendOpts{TTLCleanupInterval: 100 * time.Millisecond},
+	})
+	ctx, collection := s.Ctx, s.Collection
+
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", "past"}, {"v", past}},
+		bson.D{{"_id", "future"}, {"v", future}},
+		bson.D{{"_id", "array"}, {"v", bson.A{future, past}}},
+		bson.D{{"_id", "string"}, {"v", "foo"}},
+		bson.D{{"_id", "missing"}},
+	})
+	require.NoError(t, err)
+
+	indexName, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{"v", 1}},
+		Options: options.Index().SetExpireAfterSeconds(60),
+	})
+	require.NoError(t, err)
+
+	var indexes []bson.D
+
+	cursor, err := collection.Indexes().List(ctx)
+	require.NoError(t, err)
+	require.NoError(t, cursor.All(ctx, &indexes))
+
+	require.Len(t, indexes, 2)
+	assert.Equal(t, indexName, indexes[1].Map()["name"])
+	assert.Equal(t, int32(60), indexes[1].Map()["expireAfterSeconds"])
+
+	// MongoDB deletes expired documents every 60 seconds
+	require.Eventually(t, func() bool {
+		var n int64
+		n, err = collection.CountDocuments(ctx, bson.D{})
+		require.NoError(t, err)
+
+		return n == 3
+	}, 90*time.Second, 100*time.Millisecond)
+
+	cursor, err = collection.Find(ctx, bson.D{}, options.Find().SetSort(bson.D{{"_id", 1}}).SetProjection(bson.D{{"v", 0}}))
+	require.NoError(t, err)
+
+	var res []bson.D
+	require.NoError(t, cursor.All(ctx, &res))
+
+	expected := []bson.D{{{"_id", "future"}}, {{"_id", "missing"}}, {{"_id", "string"}}}
+	assert.Equal(t, expected, res)
+
+	t.Run("OptionsConflict", func(t *testing.T) {
+		_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    bson.D{{"v", 1}},
+			Options: options.Index().SetExpireAfterSeconds(120),
+		})
+		AssertEqualAltCommandError(t, mongo.CommandError{
+			Code: 85,
+			Name: "IndexOptionsConflict",
+			Message: "An equivalent index already exists with the same name but different options. " +
+				`Requested index: { v: 2, key: { v: 1 }, name: "v_1", expireAfterSeconds: 120 }, ` +
+				`existing index: { v: 2, key: { v: 1 }, name: "v_1", expireAfterSeconds: 60 }`,
+		}, "An equivalent index already exists with the same name but different options. "+
+			`Requested index: { key: { v: 1 }, name: "v_1" }, existing index: { key: { v: 1 }, name: "v_1" }`, err)
+
+		// the same options are not a conflict
+		_, err = collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    bson.D{{"v", 1}},
+			Options: options.Index().SetExpireAfterSeconds(60),
+		})
+		require.NoError(t, err)
+	})
+}
+
+func TestCreateIndexesCommandPartial(t *testing.T) {
+	t.Parallel()
+
+	if setup.IsHana(t) {
+		t.Skip("partial indexes are not supported by that backend")
+	}
+
+	ctx, collection := setup.Setup(t)
+
+	partialFilter := bson.D{
+		{"active", true},
+		{"age", bson.D{{"$gte", int32(18)}}},
+	}
+
+	indexName, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{"email", 1}},
+		Options: options.Index().SetPartialFilterExpression(partialFilter),
+	})
+	require.NoError(t, err)
+
+	var indexes []bson.D
+
+	cursor, err := collection.Indexes().List(ctx)
+	require.NoError(t, err)
+	require.NoError(t, cursor.All(ctx, &indexes))
+
+	require.Len(t, indexes, 2)
+	assert.Equal(t, indexName, indexes[1].Map()["name"])
+	assert.Equal(t, partialFilter, indexes[1].Map()["partialFilterExpression"])
+
+	_, err = collection.InsertMany(ctx, []any{
+		bson.D{{"_id", "active"}, {"email", "foo@example.com"}, {"active", true}, {"age", int32(42)}},
+		bson.D{{"_id", "duplicate"}, {"email", "foo@example.com"}, {"active", true}, {"age", 18.0}},
+		bson.D{{"_id", "inactive"}, {"email", "foo@example.com"}, {"active", false}, {"age", int32(42)}},
+		bson.D{{"_id", "minor"}, {"email", "foo@example.com"}, {"active", true}, {"age", int64(17)}},
+		bson.D{{"_id", "string"}, {"email", "foo@example.com"}, {"active", "true"}, {"age", 42.0}},
+		bson.D{{"_id", "missing"}, {"email", "foo@example.com"}},
+	})
+	require.NoError(t, err)
+
+	t.Run("Unique", func(t *testing.T) {
+		_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys: bson.D{{"login", 1}},
+			Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.D{
+				{"login", bson.D{{"$exists", true}}},
+			}),
+		})
+		require.NoError(t, err)
+
+		// documents without login are not indexed
+		_, err = collection.InsertMany(ctx, []any{
+			bson.D{{"_id", "login1"}, {"login", "foo"}},
+			bson.D{{"_id", "login2"}, {"login", "bar"}},
+		})
+		require.NoError(t, err)
+
+		_, err = collection.InsertOne(ctx, bson.D{{"_id", "login3"}, {"login", "foo"}})
+		assert.True(t, mongo.IsDuplicateKeyError(err), "%v", err)
+
+		// SQLite backend can't match arrays in index predicates,
+		// so uniqueness would not be enforced for documents like {active: [true]}
+		_, err = collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    bson.D{{"phone", 1}},
+			Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.D{{"active", true}}),
+		})
+
+		if setup.IsSQLite(t) {
+			AssertEqualCommandError(t, mongo.CommandError{
+				Code: 238,
+				Name: "NotImplemented",
+				Message: `Index option "partialFilterExpression" with that expression is not supported by this backend ` +
+					`for unique indexes`,
+			}, err)
+
+			return
+		}
+
+		require.NoError(t, err)
+
+		_, err = collection.InsertOne(ctx, bson.D{{"_id", "phone1"}, {"phone", "42"}, {"active", true}})
+		require.NoError(t, err)
+
+		_, err = collection.InsertOne(ctx, bson.D{{"_id", "phone2"}, {"phone", "42"}, {"active", bson.A{false, true}}})
+		assert.True(t, mongo.IsDuplicateKeyError(err), "%v", err)
+
+		_, err = collection.InsertOne(ctx, bson.D{{"_id", "phone3"}, {"phone", "42"}, {"active", bson.A{false}}})
+		require.NoError(t, err)
+	})
+
+	t.Run("OptionsConflict", func(t *testing.T) {
+		_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    bson.D{{"email", 1}},
+			Options: options.Index().SetName(indexName).SetPartialFilterExpression(bson.D{{"active", true}}),
+		})
+
+		var ce mongo.CommandError
+		require.ErrorAs(t, err, &ce)
+		assert.Equal(t, int32(85), ce.Code)
+		assert.Equal(t, "IndexOptionsConflict", ce.Name)
+
+		// the same key with a different filter expression could be indexed under a different name
+		_, err = collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    bson.D{{"email", 1}},
+			Options: options.Index().SetName("email_active").SetPartialFilterExpression(bson.D{{"active", true}}),
+		})
+		require.NoError(t, err)
+
+		cursor, err := collection.Indexes().List(ctx)
+		require.NoError(t, err)
+
+		var indexes []bson.D
+		require.NoError(t, cursor.All(ctx, &indexes))
+
+		names := make([]any, len(indexes))
+		for i, index := range indexes {
+			names[i] = index.Map()["name"]
+		}
+
+		assert.Contains(t, names, indexName)
+		assert.Contains(t, names, "email_active")
+	})
+}
+
 func TestCreateIndexesCommandInvalidCollection(t *testing.T) {
 	t.Parallel()
 
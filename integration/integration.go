@@ -98,6 +98,13 @@ func convert(t testtb.TB, v any) any {
 		return types.NewTimestamp(time.Unix(int64(v.T), 0), v.I)
 	case int64:
 		return v
+	case primitive.Decimal128:
+		h, l := v.GetBytes()
+		return types.Decimal128{L: l, H: h}
+	case primitive.MinKey:
+		return types.MinKey
+	case primitive.MaxKey:
+		return types.MaxKey
 	default:
 		t.Fatalf("unexpected type %T", v)
 		panic("not reached")
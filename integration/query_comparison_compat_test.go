@@ -677,7 +677,8 @@ func TestQueryComparisonCompatGt(t *testing.T) {
 			filter: bson.D{{"v", bson.D{{"$gt", false}}}},
 		},
 		"Datetime": {
-			filter: bson.D{{"v", bson.D{{"$gt", time.Date(2021, 11, 1, 10, 18, 41, 123000000, time.UTC)}}}},
+			filter:         bson.D{{"v", bson.D{{"$gt", time.Date(2021, 11, 1, 10, 18, 41, 123000000, time.UTC)}}}},
+			resultPushdown: allPushdown,
 		},
 		"Null": {
 			filter:     bson.D{{"v", bson.D{{"$gt", nil}}}},
@@ -694,13 +695,16 @@ func TestQueryComparisonCompatGt(t *testing.T) {
 			filter: bson.D{{"v", bson.D{{"$gt", int32(math.MaxInt32)}}}},
 		},
 		"Timestamp": {
-			filter: bson.D{{"v", bson.D{{"$gt", primitive.Timestamp{T: 41, I: 12}}}}},
+			filter:         bson.D{{"v", bson.D{{"$gt", primitive.Timestamp{T: 41, I: 12}}}}},
+			resultPushdown: allPushdown,
 		},
 		"TimestampNoI": {
-			filter: bson.D{{"v", bson.D{{"$gt", primitive.Timestamp{T: 41}}}}},
+			filter:         bson.D{{"v", bson.D{{"$gt", primitive.Timestamp{T: 41}}}}},
+			resultPushdown: allPushdown,
 		},
 		"TimestampNoT": {
-			filter: bson.D{{"v", bson.D{{"$gt", primitive.Timestamp{I: 12}}}}},
+			filter:         bson.D{{"v", bson.D{{"$gt", primitive.Timestamp{I: 12}}}}},
+			resultPushdown: allPushdown,
 		},
 		"Int64": {
 			filter: bson.D{{"v", bson.D{{"$gt", int64(42)}}}},
@@ -931,7 +935,8 @@ func TestQueryComparisonCompatLt(t *testing.T) {
 			filter: bson.D{{"v", bson.D{{"$lt", true}}}},
 		},
 		"Datetime": {
-			filter: bson.D{{"v", bson.D{{"$lt", time.Date(2021, 11, 1, 10, 18, 43, 123000000, time.UTC)}}}},
+			filter:         bson.D{{"v", bson.D{{"$lt", time.Date(2021, 11, 1, 10, 18, 43, 123000000, time.UTC)}}}},
+			resultPushdown: allPushdown,
 		},
 		"Null": {
 			filter:     bson.D{{"v", bson.D{{"$lt", nil}}}},
@@ -948,13 +953,16 @@ func TestQueryComparisonCompatLt(t *testing.T) {
 			filter: bson.D{{"v", bson.D{{"$lt", int32(math.MinInt32)}}}},
 		},
 		"Timestamp": {
-			filter: bson.D{{"v", bson.D{{"$lt", primitive.Timestamp{T: 43, I: 14}}}}},
+			filter:         bson.D{{"v", bson.D{{"$lt", primitive.Timestamp{T: 43, I: 14}}}}},
+			resultPushdown: allPushdown,
 		},
 		"TimestampNoI": {
-			filter: bson.D{{"v", bson.D{{"$lt", primitive.Timestamp{T: 43}}}}},
+			filter:         bson.D{{"v", bson.D{{"$lt", primitive.Timestamp{T: 43}}}}},
+			resultPushdown: allPushdown,
 		},
 		"TimestampNoT": {
-			filter: bson.D{{"v", bson.D{{"$lt", primitive.Timestamp{I: 14}}}}},
+			filter:         bson.D{{"v", bson.D{{"$lt", primitive.Timestamp{I: 14}}}}},
+			resultPushdown: allPushdown,
 		},
 		"Int64": {
 			filter: bson.D{{"v", bson.D{{"$lt", int64(42)}}}},
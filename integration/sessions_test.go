@@ -0,0 +1,221 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+
+	"github.com/FerretDB/FerretDB/integration/setup"
+)
+
+// listLocalSessionIDs returns IDs of all sessions reported by $listLocalSessions.
+func listLocalSessionIDs(t testing.TB, ctx context.Context, db *mongo.Database) []primitive.Binary {
+	t.Helper()
+
+	cursor, err := db.Aggregate(ctx, bson.A{bson.D{{"$listLocalSessions", bson.D{{"allUsers", true}}}}})
+	require.NoError(t, err)
+
+	var res []struct {
+		ID struct {
+			ID primitive.Binary `bson:"id"`
+		} `bson:"_id"`
+	}
+	require.NoError(t, cursor.All(ctx, &res))
+
+	ids := make([]primitive.Binary, len(res))
+	for i, s := range res {
+		ids[i] = s.ID.ID
+	}
+
+	return ids
+}
+
+func TestSessions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("StartEnd", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, collection := setup.Setup(t)
+		db := collection.Database()
+
+		var res bson.D
+		err := db.RunCommand(ctx, bson.D{{"startSession", int32(1)}}).Decode(&res)
+		require.NoError(t, err)
+
+		doc := ConvertDocument(t, res)
+		assert.Equal(t, float64(1), must.NotFail(doc.Get("ok")))
+		assert.Equal(t, int32(30), must.NotFail(doc.Get("timeoutMinutes")))
+
+		id, ok := must.NotFail(doc.Get("id")).(*types.Document)
+		require.True(t, ok)
+
+		b := must.NotFail(id.Get("id")).(types.Binary)
+		sessionID := primitive.Binary{Subtype: byte(b.Subtype), Data: b.B}
+
+		assert.Contains(t, listLocalSessionIDs(t, ctx, db), sessionID)
+
+		err = db.RunCommand(ctx, bson.D{{"refreshSessions", bson.A{bson.D{{"id", sessionID}}}}}).Err()
+		require.NoError(t, err)
+
+		err = db.RunCommand(ctx, bson.D{{"endSessions", bson.A{bson.D{{"id", sessionID}}}}}).Err()
+		require.NoError(t, err)
+
+		assert.NotContains(t, listLocalSessionIDs(t, ctx, db), sessionID)
+	})
+
+	t.Run("KillSessionsClosesCursors", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, collection := setup.Setup(t)
+
+		_, err := collection.InsertMany(ctx, []any{
+			bson.D{{"_id", "one"}},
+			bson.D{{"_id", "two"}},
+			bson.D{{"_id", "three"}},
+		})
+		require.NoError(t, err)
+
+		sess, err := collection.Database().Client().StartSession()
+		require.NoError(t, err)
+
+		defer sess.EndSession(ctx)
+
+		sessCtx := mongo.NewSessionContext(ctx, sess)
+
+		cursor, err := collection.Find(sessCtx, bson.D{}, options.Find().SetBatchSize(1))
+		require.NoError(t, err)
+
+		defer cursor.Close(ctx)
+
+		require.True(t, cursor.Next(sessCtx))
+
+		err = collection.Database().RunCommand(ctx, bson.D{{"killSessions", bson.A{sess.ID()}}}).Err()
+		require.NoError(t, err)
+
+		require.False(t, cursor.Next(sessCtx))
+
+		var ce mongo.CommandError
+		require.ErrorAs(t, cursor.Err(), &ce)
+		assert.Equal(t, int32(43), ce.Code)
+	})
+}
+
+func TestSessionsErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+	db := collection.Database()
+
+	for name, tc := range map[string]struct {
+		command bson.D
+		err     *mongo.CommandError
+	}{
+		"EndSessionsNotArray": {
+			command: bson.D{{"endSessions", "foo"}},
+			err: &mongo.CommandError{
+				Code:    14,
+				Name:    "TypeMismatch",
+				Message: "BSON field 'endSessions.endSessions' is the wrong type 'string', expected type 'array'",
+			},
+		},
+		"EndSessionsMissingID": {
+			command: bson.D{{"endSessions", bson.A{bson.D{}}}},
+			err: &mongo.CommandError{
+				Code:    40414,
+				Name:    "Location40414",
+				Message: "BSON field 'endSessions.endSessions.0.id' is missing but a required field",
+			},
+		},
+		"EndSessionsInvalidUUID": {
+			command: bson.D{{"endSessions", bson.A{bson.D{{"id", "foo"}}}}},
+			err: &mongo.CommandError{
+				Code:    207,
+				Name:    "InvalidUUID",
+				Message: "uuid must be a 16-byte binary field with UUID (4) subtype",
+			},
+		},
+		"AggregateOneNotAgnostic": {
+			command: bson.D{
+				{"aggregate", int32(1)},
+				{"pipeline", bson.A{bson.D{{"$match", bson.D{}}}}},
+				{"cursor", bson.D{}},
+			},
+			err: &mongo.CommandError{
+				Code:    73,
+				Name:    "InvalidNamespace",
+				Message: "{aggregate: 1} is not valid for '$match'; a collection is required.",
+			},
+		},
+		"ListLocalSessionsCollection": {
+			command: bson.D{
+				{"aggregate", collection.Name()},
+				{"pipeline", bson.A{bson.D{{"$listLocalSessions", bson.D{}}}}},
+				{"cursor", bson.D{}},
+			},
+			err: &mongo.CommandError{
+				Code:    73,
+				Name:    "InvalidNamespace",
+				Message: "$listLocalSessions must be run against the database with {aggregate: 1}, not a collection",
+			},
+		},
+		"ListSessionsNamespace": {
+			command: bson.D{
+				{"aggregate", collection.Name()},
+				{"pipeline", bson.A{bson.D{{"$listSessions", bson.D{}}}}},
+				{"cursor", bson.D{}},
+			},
+			err: &mongo.CommandError{
+				Code:    73,
+				Name:    "InvalidNamespace",
+				Message: "$listSessions may only be run against config.system.sessions",
+			},
+		},
+		"ListLocalSessionsNotFirst": {
+			command: bson.D{
+				{"aggregate", int32(1)},
+				{"pipeline", bson.A{
+					bson.D{{"$listLocalSessions", bson.D{}}},
+					bson.D{{"$listLocalSessions", bson.D{}}},
+				}},
+				{"cursor", bson.D{}},
+			},
+			err: &mongo.CommandError{
+				Code:    40602,
+				Name:    "Location40602",
+				Message: "$listLocalSessions is only valid as the first stage in a pipeline",
+			},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			res := db.RunCommand(ctx, tc.command)
+			AssertEqualCommandError(t, *tc.err, res.Err())
+		})
+	}
+}
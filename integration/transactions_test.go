@@ -0,0 +1,446 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/FerretDB/FerretDB/integration/setup"
+)
+
+func TestTransactions(t *testing.T) {
+	t.Parallel()
+
+	if setup.IsHana(t) {
+		t.Skip("transactions are not supported by that backend")
+	}
+
+	t.Run("Commit", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, collection := setup.Setup(t)
+
+		_, err := collection.InsertOne(ctx, bson.D{{"_id", "existing"}, {"v", int32(1)}})
+		require.NoError(t, err)
+
+		sess, err := collection.Database().Client().StartSession()
+		require.NoError(t, err)
+
+		defer sess.EndSession(ctx)
+
+		require.NoError(t, sess.StartTransaction())
+
+		sessCtx := mongo.NewSessionContext(ctx, sess)
+
+		_, err = collection.InsertMany(sessCtx, []any{
+			bson.D{{"_id", "one"}, {"v", int32(1)}},
+			bson.D{{"_id", "two"}, {"v", int32(2)}},
+		})
+		require.NoError(t, err)
+
+		_, err = collection.UpdateOne(sessCtx, bson.D{{"_id", "existing"}}, bson.D{{"$set", bson.D{{"v", int32(42)}}}})
+		require.NoError(t, err)
+
+		_, err = collection.DeleteOne(sessCtx, bson.D{{"_id", "two"}})
+		require.NoError(t, err)
+
+		// changes are visible inside the transaction
+		n, err := collection.CountDocuments(sessCtx, bson.D{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), n)
+
+		// but not outside of it
+		n, err = collection.CountDocuments(ctx, bson.D{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), n)
+
+		var doc bson.D
+		require.NoError(t, collection.FindOne(ctx, bson.D{{"_id", "existing"}}).Decode(&doc))
+		assert.Equal(t, bson.D{{"_id", "existing"}, {"v", int32(1)}}, doc)
+
+		require.NoError(t, sess.CommitTransaction(ctx))
+
+		cursor, err := collection.Find(ctx, bson.D{}, nil)
+		require.NoError(t, err)
+
+		var res []bson.D
+		require.NoError(t, cursor.All(ctx, &res))
+
+		expected := []bson.D{
+			{{"_id", "existing"}, {"v", int32(42)}},
+			{{"_id", "one"}, {"v", int32(1)}},
+		}
+		assert.Equal(t, expected, res)
+	})
+
+	t.Run("Abort", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, collection := setup.Setup(t)
+
+		_, err := collection.InsertOne(ctx, bson.D{{"_id", "existing"}})
+		require.NoError(t, err)
+
+		sess, err := collection.Database().Client().StartSession()
+		require.NoError(t, err)
+
+		defer sess.EndSession(ctx)
+
+		require.NoError(t, sess.StartTransaction())
+
+		sessCtx := mongo.NewSessionContext(ctx, sess)
+
+		_, err = collection.InsertOne(sessCtx, bson.D{{"_id", "new"}})
+		require.NoError(t, err)
+
+		_, err = collection.DeleteOne(sessCtx, bson.D{{"_id", "existing"}})
+		require.NoError(t, err)
+
+		require.NoError(t, sess.AbortTransaction(ctx))
+
+		cursor, err := collection.Find(ctx, bson.D{}, nil)
+		require.NoError(t, err)
+
+		var res []bson.D
+		require.NoError(t, cursor.All(ctx, &res))
+		assert.Equal(t, []bson.D{{{"_id", "existing"}}}, res)
+
+		// session could be used for the next transaction
+		_, err = sess.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (any, error) {
+			return collection.InsertOne(sessCtx, bson.D{{"_id", "next"}})
+		})
+		require.NoError(t, err)
+
+		n, err := collection.CountDocuments(ctx, bson.D{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), n)
+	})
+
+	t.Run("FailedStatement", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, collection := setup.Setup(t)
+
+		_, err := collection.InsertOne(ctx, bson.D{{"_id", "existing"}})
+		require.NoError(t, err)
+
+		sess, err := collection.Database().Client().StartSession()
+		require.NoError(t, err)
+
+		defer sess.EndSession(ctx)
+
+		require.NoError(t, sess.StartTransaction())
+
+		sessCtx := mongo.NewSessionContext(ctx, sess)
+
+		_, err = collection.InsertOne(sessCtx, bson.D{{"_id", "new"}})
+		require.NoError(t, err)
+
+		_, err = collection.InsertOne(sessCtx, bson.D{{"_id", "existing"}})
+		require.True(t, mongo.IsDuplicateKeyError(err), "%v", err)
+
+		// the whole transaction is aborted
+		_, err = collection.InsertOne(sessCtx, bson.D{{"_id", "next"}})
+		AssertEqualCommandError(t, mongo.CommandError{
+			Code:    251,
+			Name:    "NoSuchTransaction",
+			Message: "Transaction with { txnNumber: 1 } has been aborted.",
+			Labels:  []string{"TransientTransactionError"},
+		}, err)
+
+		err = sess.CommitTransaction(ctx)
+		require.Error(t, err)
+
+		var ce mongo.CommandError
+		require.ErrorAs(t, err, &ce)
+		assert.Equal(t, int32(251), ce.Code)
+
+		cursor, err := collection.Find(ctx, bson.D{}, nil)
+		require.NoError(t, err)
+
+		var res []bson.D
+		require.NoError(t, cursor.All(ctx, &res))
+		assert.Equal(t, []bson.D{{{"_id", "existing"}}}, res)
+	})
+
+	t.Run("UpdateMany", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, collection := setup.Setup(t)
+
+		_, err := collection.InsertMany(ctx, []any{
+			bson.D{{"_id", "one"}, {"v", int32(1)}},
+			bson.D{{"_id", "two"}, {"v", int32(2)}},
+			bson.D{{"_id", "three"}, {"v", int32(3)}},
+		})
+		require.NoError(t, err)
+
+		sess, err := collection.Database().Client().StartSession()
+		require.NoError(t, err)
+
+		defer sess.EndSession(ctx)
+
+		_, err = sess.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (any, error) {
+			return collection.UpdateMany(sessCtx, bson.D{}, bson.D{{"$inc", bson.D{{"v", int32(10)}}}})
+		})
+		require.NoError(t, err)
+
+		cursor, err := collection.Find(ctx, bson.D{}, options.Find().SetSort(bson.D{{"v", 1}}))
+		require.NoError(t, err)
+
+		var res []bson.D
+		require.NoError(t, cursor.All(ctx, &res))
+
+		expected := []bson.D{
+			{{"_id", "one"}, {"v", int32(11)}},
+			{{"_id", "two"}, {"v", int32(12)}},
+			{{"_id", "three"}, {"v", int32(13)}},
+		}
+		assert.Equal(t, expected, res)
+	})
+
+	t.Run("FindOneAndDelete", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, collection := setup.Setup(t)
+
+		_, err := collection.InsertMany(ctx, []any{
+			bson.D{{"_id", "one"}, {"v", int32(1)}},
+			bson.D{{"_id", "two"}, {"v", int32(2)}},
+		})
+		require.NoError(t, err)
+
+		sess, err := collection.Database().Client().StartSession()
+		require.NoError(t, err)
+
+		defer sess.EndSession(ctx)
+
+		var doc bson.D
+
+		_, err = sess.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (any, error) {
+			return nil, collection.FindOneAndDelete(sessCtx, bson.D{{"v", bson.D{{"$gt", int32(1)}}}}).Decode(&doc)
+		})
+		require.NoError(t, err)
+		assert.Equal(t, bson.D{{"_id", "two"}, {"v", int32(2)}}, doc)
+
+		n, err := collection.CountDocuments(ctx, bson.D{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), n)
+	})
+
+	t.Run("Lookup", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, collection := setup.Setup(t)
+
+		foreign := collection.Database().Collection(collection.Name() + "_foreign")
+
+		_, err := collection.InsertMany(ctx, []any{
+			bson.D{{"_id", "one"}, {"v", int32(1)}},
+			bson.D{{"_id", "two"}, {"v", int32(2)}},
+		})
+		require.NoError(t, err)
+
+		_, err = foreign.InsertOne(ctx, bson.D{{"_id", "foreign"}, {"v", int32(1)}})
+		require.NoError(t, err)
+
+		sess, err := collection.Database().Client().StartSession()
+		require.NoError(t, err)
+
+		defer sess.EndSession(ctx)
+
+		var res []bson.D
+
+		_, err = sess.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (any, error) {
+			// the foreign collection is queried for every document while the outer cursor is still open
+			pipeline := bson.A{
+				bson.D{{"$sort", bson.D{{"_id", 1}}}},
+				bson.D{{"$lookup", bson.D{
+					{"from", foreign.Name()},
+					{"localField", "v"},
+					{"foreignField", "v"},
+					{"as", "joined"},
+				}}},
+			}
+
+			cursor, err := collection.Aggregate(sessCtx, pipeline, options.Aggregate().SetBatchSize(1))
+			if err != nil {
+				return nil, err
+			}
+
+			return nil, cursor.All(sessCtx, &res)
+		})
+		require.NoError(t, err)
+
+		expected := []bson.D{
+			{{"_id", "one"}, {"v", int32(1)}, {"joined", bson.A{bson.D{{"_id", "foreign"}, {"v", int32(1)}}}}},
+			{{"_id", "two"}, {"v", int32(2)}, {"joined", bson.A{}}},
+		}
+		assert.Equal(t, expected, res)
+	})
+}
+
+func TestTransactionsErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	if setup.IsHana(t) {
+		t.Skip("transactions are not supported by that backend")
+	}
+
+	sess, err := collection.Database().Client().StartSession()
+	require.NoError(t, err)
+
+	defer sess.EndSession(ctx)
+
+	// the driver adds session ID to commands
+	sessCtx := mongo.NewSessionContext(ctx, sess)
+	admin := collection.Database().Client().Database("admin")
+
+	for name, tc := range map[string]struct {
+		db      *mongo.Database
+		command bson.D
+		err     *mongo.CommandError
+	}{
+		"AutocommitTrue": {
+			db: collection.Database(),
+			command: bson.D{
+				{"insert", collection.Name()},
+				{"documents", bson.A{bson.D{{"_id", "v"}}}},
+				{"txnNumber", int64(1)},
+				{"startTransaction", true},
+				{"autocommit", true},
+			},
+			err: &mongo.CommandError{
+				Code:    72,
+				Name:    "InvalidOptions",
+				Message: "Specifying autocommit=true is not allowed.",
+			},
+		},
+		"NoSuchTransaction": {
+			db: collection.Database(),
+			command: bson.D{
+				{"find", collection.Name()},
+				{"txnNumber", int64(42)},
+				{"autocommit", false},
+			},
+			err: &mongo.CommandError{
+				Code:    251,
+				Name:    "NoSuchTransaction",
+				Message: "Given transaction number 42 does not match any in-progress transactions.",
+				Labels:  []string{"TransientTransactionError"},
+			},
+		},
+		"CommitNoSuchTransaction": {
+			db: admin,
+			command: bson.D{
+				{"commitTransaction", int32(1)},
+				{"txnNumber", int64(43)},
+				{"autocommit", false},
+			},
+			err: &mongo.CommandError{
+				Code:    251,
+				Name:    "NoSuchTransaction",
+				Message: "Transaction with { txnNumber: 43 } has been aborted.",
+				Labels:  []string{"TransientTransactionError"},
+			},
+		},
+		"CommitNotAdmin": {
+			db: collection.Database(),
+			command: bson.D{
+				{"commitTransaction", int32(1)},
+				{"txnNumber", int64(44)},
+				{"autocommit", false},
+			},
+			err: &mongo.CommandError{
+				Code:    13,
+				Name:    "Unauthorized",
+				Message: "commitTransaction may only be run against the admin database.",
+			},
+		},
+		"NotSupportedInTransaction": {
+			db: collection.Database(),
+			command: bson.D{
+				{"drop", collection.Name()},
+				{"txnNumber", int64(46)},
+				{"startTransaction", true},
+				{"autocommit", false},
+			},
+			err: &mongo.CommandError{
+				Code:    263,
+				Name:    "OperationNotSupportedInTransaction",
+				Message: "Cannot run 'drop' in a multi-document transaction.",
+			},
+		},
+		"AbortNotAdmin": {
+			db: collection.Database(),
+			command: bson.D{
+				{"abortTransaction", int32(1)},
+				{"txnNumber", int64(45)},
+				{"autocommit", false},
+			},
+			err: &mongo.CommandError{
+				Code:    13,
+				Name:    "Unauthorized",
+				Message: "abortTransaction may only be run against the admin database.",
+			},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			res := tc.db.RunCommand(sessCtx, tc.command)
+			AssertEqualCommandError(t, *tc.err, res.Err())
+		})
+	}
+
+	t.Run("StartedTransaction", func(t *testing.T) {
+		start := func(txnNumber int64) error {
+			return collection.Database().RunCommand(sessCtx, bson.D{
+				{"find", collection.Name()},
+				{"txnNumber", txnNumber},
+				{"startTransaction", true},
+				{"autocommit", false},
+			}).Err()
+		}
+
+		require.NoError(t, start(100))
+
+		AssertEqualCommandError(t, mongo.CommandError{
+			Code:    117,
+			Name:    "ConflictingOperationInProgress",
+			Message: "Cannot start transaction 100 on session because it has already been started.",
+		}, start(100))
+
+		AssertEqualCommandError(t, mongo.CommandError{
+			Code:    225,
+			Name:    "TransactionTooOld",
+			Message: "Cannot start transaction 99 on session because a newer transaction 100 has already started.",
+		}, start(99))
+
+		err := admin.RunCommand(sessCtx, bson.D{
+			{"abortTransaction", int32(1)},
+			{"txnNumber", int64(100)},
+			{"autocommit", false},
+		}).Err()
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,111 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package users
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/FerretDB/FerretDB/integration/setup"
+)
+
+// localSessionIDs returns IDs of sessions reported by $listLocalSessions with the given specification.
+func localSessionIDs(t testing.TB, ctx context.Context, db *mongo.Database, spec bson.D) []primitive.Binary {
+	t.Helper()
+
+	cursor, err := db.Aggregate(ctx, bson.A{bson.D{{"$listLocalSessions", spec}}})
+	require.NoError(t, err)
+
+	var res []struct {
+		ID struct {
+			ID primitive.Binary `bson:"id"`
+		} `bson:"_id"`
+	}
+	require.NoError(t, cursor.All(ctx, &res))
+
+	ids := make([]primitive.Binary, len(res))
+	for i, s := range res {
+		ids[i] = s.ID.ID
+	}
+
+	return ids
+}
+
+func TestSessionsOtherUsers(t *testing.T) {
+	t.Parallel()
+
+	setup.SkipForMongoDB(t, "killAnySession and listSessions privileges are granted by roles")
+
+	s := setup.SetupWithOpts(t, nil)
+	ctx := s.Ctx
+	db := s.Collection.Database()
+
+	err := db.RunCommand(ctx, bson.D{
+		{"createUser", "sessions-user"},
+		{"roles", bson.A{}},
+		{"pwd", "password"},
+		{"mechanisms", bson.A{"SCRAM-SHA-256"}},
+	}).Err()
+	require.NoError(t, err)
+
+	opts := options.Client().ApplyURI(s.MongoDBURI).SetAuth(options.Credential{
+		AuthMechanism: "SCRAM-SHA-256",
+		AuthSource:    db.Name(),
+		Username:      "sessions-user",
+		Password:      "password",
+	})
+
+	client, err := mongo.Connect(ctx, opts)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, client.Disconnect(ctx))
+	})
+
+	userDB := client.Database(db.Name())
+
+	var res bson.M
+	require.NoError(t, db.RunCommand(ctx, bson.D{{"startSession", int32(1)}}).Decode(&res))
+
+	id := res["id"].(bson.M)["id"].(primitive.Binary)
+	ids := bson.A{bson.D{{"id", id}}}
+
+	for _, command := range []bson.D{
+		{{"endSessions", ids}},
+		{{"killSessions", ids}},
+		{{"killSessions", bson.A{}}},
+	} {
+		require.NoError(t, userDB.RunCommand(ctx, command).Err())
+	}
+
+	// the session of another user is still there
+	assert.Contains(t, localSessionIDs(t, ctx, db, bson.D{{"allUsers", true}}), id)
+
+	// and could not be listed
+	_, err = userDB.Aggregate(ctx, bson.A{bson.D{{"$listLocalSessions", bson.D{{"allUsers", true}}}}})
+	var ce mongo.CommandError
+	require.ErrorAs(t, err, &ce)
+	assert.Equal(t, int32(13), ce.Code)
+
+	// but own sessions could
+	assert.NotContains(t, localSessionIDs(t, ctx, userDB, bson.D{}), id)
+}
@@ -0,0 +1,420 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/FerretDB/FerretDB/integration/setup"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// validationSchema is a $jsonSchema validator used by validation tests.
+var validationSchema = bson.D{{"$jsonSchema", bson.D{
+	{"bsonType", "object"},
+	{"required", bson.A{"name"}},
+	{"properties", bson.D{
+		{"name", bson.D{{"bsonType", "string"}, {"minLength", int32(2)}}},
+		{"age", bson.D{{"bsonType", bson.A{"int", "long"}}, {"minimum", int32(0)}}},
+	}},
+}}}
+
+// setupValidation recreates the test collection with the given validation options.
+func setupValidation(t *testing.T, opts *options.CreateCollectionOptions) (context.Context, *mongo.Collection) {
+	t.Helper()
+
+	ctx, collection := setup.Setup(t)
+
+	if setup.IsHana(t) {
+		t.Skip("document validation is not supported by that backend")
+	}
+
+	require.NoError(t, collection.Drop(ctx))
+	require.NoError(t, collection.Database().CreateCollection(ctx, collection.Name(), opts))
+
+	return ctx, collection
+}
+
+// assertValidationFailure asserts that the given error is a DocumentValidationFailure write error.
+//
+// Error details returned by MongoDB are not compared.
+func assertValidationFailure(t *testing.T, err error) {
+	t.Helper()
+
+	var we mongo.WriteException
+	require.ErrorAs(t, err, &we)
+	require.Len(t, we.WriteErrors, 1)
+	assert.Equal(t, 121, we.WriteErrors[0].Code)
+	assert.Equal(t, "Document failed validation", we.WriteErrors[0].Message)
+}
+
+func TestValidationInsert(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setupValidation(t, options.CreateCollection().SetValidator(validationSchema))
+
+	_, err := collection.InsertOne(ctx, bson.D{{"_id", "valid"}, {"name", "Alice"}, {"age", int32(42)}})
+	require.NoError(t, err)
+
+	for name, doc := range map[string]bson.D{
+		"Required":  {{"_id", "required"}, {"age", int32(1)}},
+		"Type":      {{"_id", "type"}, {"name", int32(1)}},
+		"MinLength": {{"_id", "minLength"}, {"name", "A"}},
+		"Minimum":   {{"_id", "minimum"}, {"name", "Bob"}, {"age", int32(-1)}},
+		"TypeList":  {{"_id", "typeList"}, {"name", "Bob"}, {"age", 4.2}},
+	} {
+		name, doc := name, doc
+		t.Run(name, func(t *testing.T) {
+			_, err := collection.InsertOne(ctx, doc)
+			assertValidationFailure(t, err)
+		})
+	}
+
+	t.Run("Unordered", func(t *testing.T) {
+		res, err := collection.InsertMany(ctx, []any{
+			bson.D{{"_id", "u1"}, {"name", "Carol"}},
+			bson.D{{"_id", "u2"}},
+			bson.D{{"_id", "u3"}, {"name", "Dave"}},
+		}, options.InsertMany().SetOrdered(false))
+
+		var we mongo.BulkWriteException
+		require.ErrorAs(t, err, &we)
+		require.Len(t, we.WriteErrors, 1)
+		assert.Equal(t, 1, we.WriteErrors[0].Index)
+		assert.Equal(t, 121, we.WriteErrors[0].Code)
+		assert.Equal(t, []any{"u1", "u3"}, res.InsertedIDs)
+	})
+
+	t.Run("Bypass", func(t *testing.T) {
+		_, err := collection.InsertOne(
+			ctx, bson.D{{"_id", "bypass"}}, options.InsertOne().SetBypassDocumentValidation(true),
+		)
+		require.NoError(t, err)
+	})
+}
+
+func TestValidationUpdate(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setupValidation(t, options.CreateCollection().SetValidator(validationSchema))
+
+	_, err := collection.InsertOne(ctx, bson.D{{"_id", "doc"}, {"name", "Alice"}})
+	require.NoError(t, err)
+
+	_, err = collection.UpdateOne(ctx, bson.D{{"_id", "doc"}}, bson.D{{"$unset", bson.D{{"name", ""}}}})
+	assertValidationFailure(t, err)
+
+	_, err = collection.ReplaceOne(ctx, bson.D{{"_id", "doc"}}, bson.D{{"name", "A"}})
+	assertValidationFailure(t, err)
+
+	_, err = collection.UpdateOne(
+		ctx, bson.D{{"_id", "upsert"}}, bson.D{{"$set", bson.D{{"age", int32(1)}}}}, options.Update().SetUpsert(true),
+	)
+	assertValidationFailure(t, err)
+
+	_, err = collection.UpdateOne(ctx, bson.D{{"_id", "doc"}}, bson.D{{"$set", bson.D{{"age", int32(1)}}}})
+	require.NoError(t, err)
+
+	_, err = collection.UpdateOne(
+		ctx,
+		bson.D{{"_id", "doc"}},
+		bson.D{{"$set", bson.D{{"age", "old"}}}},
+		options.Update().SetBypassDocumentValidation(true),
+	)
+	require.NoError(t, err)
+
+	err = collection.FindOneAndUpdate(ctx, bson.D{{"_id", "doc"}}, bson.D{{"$set", bson.D{{"name", int32(1)}}}}).Err()
+	AssertEqualCommandError(t, mongo.CommandError{
+		Code:    121,
+		Name:    "DocumentValidationFailure",
+		Message: "Document failed validation",
+	}, stripDetails(err))
+
+	var doc bson.D
+	require.NoError(t, collection.FindOne(ctx, bson.D{{"_id", "doc"}}).Decode(&doc))
+	assert.Equal(t, bson.D{{"_id", "doc"}, {"name", "Alice"}, {"age", "old"}}, doc)
+}
+
+// stripDetails returns the given command error without details that are returned by MongoDB.
+func stripDetails(err error) error {
+	ce, ok := err.(mongo.CommandError) //nolint:errorlint // do not inspect error chain
+	if !ok {
+		return err
+	}
+
+	return mongo.CommandError{Code: ce.Code, Name: ce.Name, Message: ce.Message}
+}
+
+func TestValidationAggregate(t *testing.T) {
+	t.Parallel()
+
+	ctx, target := setupValidation(t, options.CreateCollection().SetValidator(validationSchema))
+
+	_, err := target.InsertOne(ctx, bson.D{{"_id", "existing"}, {"name", "Alice"}})
+	require.NoError(t, err)
+
+	source := target.Database().Collection(target.Name() + "_source")
+
+	_, err = source.InsertMany(ctx, []any{
+		bson.D{{"_id", "valid"}, {"name", "Bob"}},
+		bson.D{{"_id", "invalid"}, {"name", "A"}},
+	})
+	require.NoError(t, err)
+
+	validationFailure := mongo.CommandError{
+		Code:    121,
+		Name:    "DocumentValidationFailure",
+		Message: "Document failed validation",
+	}
+
+	for name, stage := range map[string]bson.D{
+		"Out":   {{"$out", target.Name()}},
+		"Merge": {{"$merge", bson.D{{"into", target.Name()}}}},
+	} {
+		name, stage := name, stage
+		t.Run(name, func(t *testing.T) {
+			_, err := source.Aggregate(ctx, bson.A{stage})
+			AssertEqualCommandError(t, validationFailure, stripDetails(err))
+
+			n, err := target.CountDocuments(ctx, bson.D{})
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), n)
+		})
+	}
+
+	t.Run("OutBypass", func(t *testing.T) {
+		opts := options.Aggregate().SetBypassDocumentValidation(true)
+		_, err := source.Aggregate(ctx, bson.A{bson.D{{"$out", target.Name()}}}, opts)
+		require.NoError(t, err)
+
+		n, err := target.CountDocuments(ctx, bson.D{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), n)
+
+		// validator of the replaced collection is preserved
+		_, err = target.InsertOne(ctx, bson.D{{"_id", "new"}})
+		assertValidationFailure(t, err)
+	})
+
+	t.Run("MergeBypass", func(t *testing.T) {
+		_, err := source.UpdateOne(ctx, bson.D{{"_id", "valid"}}, bson.D{{"$set", bson.D{{"name", int32(1)}}}})
+		require.NoError(t, err)
+
+		opts := options.Aggregate().SetBypassDocumentValidation(true)
+		_, err = source.Aggregate(ctx, bson.A{bson.D{{"$merge", bson.D{{"into", target.Name()}}}}}, opts)
+		require.NoError(t, err)
+
+		var doc bson.D
+		require.NoError(t, target.FindOne(ctx, bson.D{{"_id", "valid"}}).Decode(&doc))
+		assert.Equal(t, bson.D{{"_id", "valid"}, {"name", int32(1)}}, doc)
+	})
+}
+
+func TestValidationLevelAction(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Warn", func(t *testing.T) {
+		t.Parallel()
+
+		opts := options.CreateCollection().SetValidator(validationSchema).SetValidationAction("warn")
+		ctx, collection := setupValidation(t, opts)
+
+		_, err := collection.InsertOne(ctx, bson.D{{"_id", "invalid"}})
+		require.NoError(t, err)
+
+		_, err = collection.UpdateOne(ctx, bson.D{{"_id", "invalid"}}, bson.D{{"$set", bson.D{{"name", int32(1)}}}})
+		require.NoError(t, err)
+	})
+
+	t.Run("Off", func(t *testing.T) {
+		t.Parallel()
+
+		opts := options.CreateCollection().SetValidator(validationSchema).SetValidationLevel("off")
+		ctx, collection := setupValidation(t, opts)
+
+		_, err := collection.InsertOne(ctx, bson.D{{"_id", "invalid"}})
+		require.NoError(t, err)
+	})
+
+	t.Run("Moderate", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, collection := setupValidation(t, nil)
+
+		_, err := collection.InsertMany(ctx, []any{
+			bson.D{{"_id", "valid"}, {"name", "Alice"}},
+			bson.D{{"_id", "invalid"}},
+		})
+		require.NoError(t, err)
+
+		err = collection.Database().RunCommand(ctx, bson.D{
+			{"collMod", collection.Name()},
+			{"validator", validationSchema},
+			{"validationLevel", "moderate"},
+		}).Err()
+		require.NoError(t, err)
+
+		// existing invalid documents could be updated
+		_, err = collection.UpdateOne(ctx, bson.D{{"_id", "invalid"}}, bson.D{{"$set", bson.D{{"v", int32(1)}}}})
+		require.NoError(t, err)
+
+		_, err = collection.UpdateOne(ctx, bson.D{{"_id", "valid"}}, bson.D{{"$set", bson.D{{"name", int32(1)}}}})
+		assertValidationFailure(t, err)
+	})
+}
+
+func TestValidationCollMod(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setupValidation(t, options.CreateCollection().SetValidator(bson.D{{"v", bson.D{{"$gt", int32(0)}}}}))
+
+	_, err := collection.InsertOne(ctx, bson.D{{"_id", "invalid"}, {"v", int32(-1)}})
+	assertValidationFailure(t, err)
+
+	err = collection.Database().RunCommand(ctx, bson.D{
+		{"collMod", collection.Name()},
+		{"validator", bson.D{{"v", bson.D{{"$lt", int32(0)}}}}},
+	}).Err()
+	require.NoError(t, err)
+
+	_, err = collection.InsertOne(ctx, bson.D{{"_id", "valid"}, {"v", int32(-1)}})
+	require.NoError(t, err)
+
+	var res bson.D
+	err = collection.Database().RunCommand(ctx, bson.D{
+		{"listCollections", int32(1)},
+		{"filter", bson.D{{"name", collection.Name()}}},
+	}).Decode(&res)
+	require.NoError(t, err)
+
+	doc := ConvertDocument(t, res)
+	v, _ := doc.GetByPath(types.NewStaticPath("cursor", "firstBatch"))
+	opts := must.NotFail(must.NotFail(v.(*types.Array).Get(0)).(*types.Document).Get("options")).(*types.Document)
+	assert.Equal(t, "strict", must.NotFail(opts.Get("validationLevel")))
+	assert.Equal(t, "error", must.NotFail(opts.Get("validationAction")))
+
+	// empty validator removes validation
+	err = collection.Database().RunCommand(ctx, bson.D{
+		{"collMod", collection.Name()},
+		{"validator", bson.D{}},
+	}).Err()
+	require.NoError(t, err)
+
+	_, err = collection.InsertOne(ctx, bson.D{{"_id", "any"}, {"v", int32(1)}})
+	require.NoError(t, err)
+}
+
+func TestValidationErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	if setup.IsHana(t) {
+		t.Skip("document validation is not supported by that backend")
+	}
+
+	db := collection.Database()
+
+	for name, tc := range map[string]struct {
+		command bson.D
+		err     *mongo.CommandError
+	}{
+		"ValidatorType": {
+			command: bson.D{{"create", "validatorType"}, {"validator", "v"}},
+			err: &mongo.CommandError{
+				Code:    14,
+				Name:    "TypeMismatch",
+				Message: "BSON field 'create.validator' is the wrong type 'string', expected type 'object'",
+			},
+		},
+		"Level": {
+			command: bson.D{{"create", "level"}, {"validator", bson.D{}}, {"validationLevel", "foo"}},
+			err: &mongo.CommandError{
+				Code:    2,
+				Name:    "BadValue",
+				Message: "Enumeration value 'foo' for field 'create.validationLevel' is not a valid value.",
+			},
+		},
+		"Action": {
+			command: bson.D{{"create", "action"}, {"validator", bson.D{}}, {"validationAction", "foo"}},
+			err: &mongo.CommandError{
+				Code:    2,
+				Name:    "BadValue",
+				Message: "Enumeration value 'foo' for field 'create.validationAction' is not a valid value.",
+			},
+		},
+		"UnknownKeyword": {
+			command: bson.D{{"create", "unknownKeyword"}, {"validator", bson.D{{"$jsonSchema", bson.D{{"foo", 1}}}}}},
+			err: &mongo.CommandError{
+				Code:    9,
+				Name:    "FailedToParse",
+				Message: "Unknown $jsonSchema keyword: foo",
+			},
+		},
+		"CollModNotFound": {
+			command: bson.D{{"collMod", "notFound"}, {"validator", bson.D{}}},
+			err: &mongo.CommandError{
+				Code:    26,
+				Name:    "NamespaceNotFound",
+				Message: "ns does not exist: " + db.Name() + ".notFound",
+			},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := db.RunCommand(ctx, tc.command).Err()
+			AssertEqualCommandError(t, *tc.err, err)
+		})
+	}
+}
+
+func TestValidationFindJSONSchema(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", "valid"}, {"name", "Alice"}, {"age", int32(1)}},
+		bson.D{{"_id", "noName"}, {"age", int32(1)}},
+		bson.D{{"_id", "short"}, {"name", "A"}},
+		bson.D{{"_id", "double"}, {"name", "Bob"}, {"age", 4.2}},
+	})
+	require.NoError(t, err)
+
+	cursor, err := collection.Find(ctx, validationSchema, options.Find().SetSort(bson.D{{"_id", 1}}))
+	require.NoError(t, err)
+
+	var res []bson.D
+	require.NoError(t, cursor.All(ctx, &res))
+	assert.Equal(t, []bson.D{{{"_id", "valid"}, {"name", "Alice"}, {"age", int32(1)}}}, res)
+
+	cursor, err = collection.Find(
+		ctx, bson.D{{"$nor", bson.A{validationSchema}}}, options.Find().SetSort(bson.D{{"_id", 1}}),
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, cursor.All(ctx, &res))
+	assert.Len(t, res, 3)
+}
@@ -46,6 +46,8 @@ type Backend interface {
 	ListDatabases(context.Context, *ListDatabasesParams) (*ListDatabasesResult, error)
 	DropDatabase(context.Context, *DropDatabaseParams) error
 
+	BeginTransaction(context.Context, *BeginTransactionParams) (Transaction, error)
+
 	prometheus.Collector
 
 	// There is no interface method to create a database; see package documentation.
@@ -197,6 +199,35 @@ func (bc *backendContract) DropDatabase(ctx context.Context, params *DropDatabas
 	return err
 }
 
+// BeginTransactionParams represents the parameters of Backend.BeginTransaction method.
+type BeginTransactionParams struct{}
+
+// BeginTransaction starts a new multi-document transaction.
+//
+// Collection methods called with the context returned by [WithTransaction]
+// should use that transaction for reading and writing documents.
+// Creating and dropping databases, collections, and indexes is not a part of the transaction.
+//
+// Backends that can't support transactions should return an error with
+// ErrorCodeTransactionsNotSupported code.
+func (bc *backendContract) BeginTransaction(ctx context.Context, params *BeginTransactionParams) (Transaction, error) {
+	ctx, span := otel.Tracer("").Start(ctx, "BeginTransaction")
+	defer span.End()
+
+	res, err := bc.b.BeginTransaction(ctx, params)
+	if err != nil {
+		span.SetStatus(otelcodes.Error, "")
+	}
+
+	checkError(err, ErrorCodeTransactionsNotSupported)
+
+	if res != nil {
+		res = newTransactionContract(res)
+	}
+
+	return res, err
+}
+
 // Describe implements prometheus.Collector.
 func (bc *backendContract) Describe(ch chan<- *prometheus.Desc) {
 	bc.b.Describe(ch)
@@ -18,12 +18,14 @@ import (
 	"cmp"
 	"context"
 	"slices"
+	"strings"
 	"time"
 
 	"go.opentelemetry.io/otel"
 	otelcodes "go.opentelemetry.io/otel/codes"
 
 	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
 	"github.com/FerretDB/FerretDB/internal/util/must"
 )
 
@@ -78,14 +80,32 @@ type QueryParams struct {
 	Filter *types.Document
 	Sort   *types.Document
 	Limit  int64
+	Sample int64
+	Graph  *GraphParams
 
 	OnlyRecordIDs bool
 	Comment       string
 }
 
+// GraphParams represents the parameters of recursive graph traversal for Collection.Query method.
+//
+// Documents at depth 0 are those with ConnectToField value equal to one of StartWith values.
+// Documents at depth N+1 are those with ConnectToField value equal to ConnectFromField value
+// (or one of its elements, if it is an array) of some document at depth N.
+// Both fields are top-level field names without dots.
+type GraphParams struct {
+	StartWith        []any
+	ConnectFromField string
+	ConnectToField   string
+	MaxDepth         int64 // negative for no limit
+}
+
 // QueryResult represents the results of Collection.Query method.
 type QueryResult struct {
 	Iter types.DocumentsIterator
+
+	// GraphPushdown is true if Graph parameter was applied.
+	GraphPushdown bool
 }
 
 // Query executes a query against the collection.
@@ -104,6 +124,18 @@ type QueryResult struct {
 // If non-empty, it should be applied.
 //
 // Limit, if non-zero, should be applied.
+//
+// Sample, if non-zero, may be applied: at most that number of randomly selected documents
+// should be returned in random order. It is never set together with Sort or Limit.
+// If it is ignored, the handler samples documents anyway.
+//
+// Graph, if non-nil, may be applied: all documents reachable by the described traversal should be returned,
+// and other documents may be omitted. Extra documents are allowed; the handler traverses returned documents anyway.
+// If it is applied, GraphPushdown of the result should be true; otherwise, all documents are returned.
+// It is never set together with Filter, Sort, Limit or Sample.
+//
+// If the context contains a transaction, all documents are read before returning,
+// so other collection methods could use the same transaction while the iterator is still open.
 func (cc *collectionContract) Query(ctx context.Context, params *QueryParams) (*QueryResult, error) {
 	ctx, span := otel.Tracer("").Start(ctx, "Query")
 	defer span.End()
@@ -121,7 +153,30 @@ func (cc *collectionContract) Query(ctx context.Context, params *QueryParams) (*
 		}
 	}
 
+	if params.Sample != 0 {
+		must.BeTrue(params.Sample > 0)
+		must.BeTrue(params.Sort.Len() == 0)
+		must.BeTrue(params.Limit == 0)
+	}
+
+	if params.Graph != nil {
+		must.BeTrue(params.Filter == nil)
+		must.BeTrue(params.Sort.Len() == 0)
+		must.BeTrue(params.Limit == 0 && params.Sample == 0)
+		must.BeTrue(params.Graph.ConnectFromField != "" && !strings.Contains(params.Graph.ConnectFromField, "."))
+		must.BeTrue(params.Graph.ConnectToField != "" && !strings.Contains(params.Graph.ConnectToField, "."))
+	}
+
 	res, err := cc.c.Query(ctx, params)
+	if err == nil && GetTransaction(ctx) != nil {
+		var docs []*types.Document
+		if docs, err = iterator.ConsumeValues(res.Iter); err == nil {
+			res.Iter = iterator.Values(iterator.ForSlice(docs))
+		} else {
+			res = nil
+		}
+	}
+
 	if err != nil {
 		span.SetStatus(otelcodes.Error, "")
 	}
@@ -375,6 +430,15 @@ type IndexInfo struct {
 	Name   string
 	Key    []IndexKeyPair
 	Unique bool
+
+	// ExpireAfterSeconds is set for TTL indexes.
+	ExpireAfterSeconds *int32
+
+	// PartialFilterExpression is set for partial indexes.
+	PartialFilterExpression *types.Document
+
+	// Collation is set for indexes with non-simple collation.
+	Collation *types.Collation
 }
 
 // IndexKeyPair consists of a field name and a sort order that are part of the index.
@@ -421,6 +485,13 @@ type CreateIndexesResult struct{}
 // and the first encountered error should be returned.
 //
 // Database or collection may not exist; that's not an error.
+//
+// Backends that can't create partial indexes should return ErrorCodeIndexPartialFilterNotSupported
+// for indexes with PartialFilterExpression.
+// The same error should be returned for unique partial indexes if the backend can't select
+// exactly the same documents as PartialFilterExpression, as uniqueness would not be enforced for some of them.
+// Backends that can't create indexes with collation should return ErrorCodeIndexCollationNotSupported
+// for indexes with Collation.
 func (cc *collectionContract) CreateIndexes(ctx context.Context, params *CreateIndexesParams) (*CreateIndexesResult, error) {
 	ctx, span := otel.Tracer("").Start(ctx, "CreateIndexes")
 	defer span.End()
@@ -430,7 +501,7 @@ func (cc *collectionContract) CreateIndexes(ctx context.Context, params *CreateI
 		span.SetStatus(otelcodes.Error, "")
 	}
 
-	checkError(err)
+	checkError(err, ErrorCodeIndexPartialFilterNotSupported, ErrorCodeIndexCollationNotSupported)
 
 	return res, err
 }
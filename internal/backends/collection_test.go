@@ -242,6 +242,54 @@ func TestCollectionInsertAllQueryExplain(t *testing.T) {
 				require.NoError(t, err)
 				assert.False(t, explainRes.SortPushdown)
 			})
+
+			t.Run("NonCappedCollectionSample", func(t *testing.T) {
+				t.Parallel()
+
+				if name == "hana" {
+					t.Skip("sampling is not pushed down to SAP HANA")
+				}
+
+				queryRes, err := coll.Query(ctx, &backends.QueryParams{Sample: 2})
+				require.NoError(t, err)
+
+				docs, err := iterator.ConsumeValues[struct{}, *types.Document](queryRes.Iter)
+				require.NoError(t, err)
+				require.Len(t, docs, 2)
+				assert.NotEqual(t, docs[0], docs[1])
+
+				for _, doc := range docs {
+					assert.True(t, slices.ContainsFunc(insertDocs, func(d *types.Document) bool {
+						return d.Map()["_id"] == doc.Map()["_id"]
+					}))
+				}
+			})
+
+			t.Run("NonCappedCollectionGraph", func(t *testing.T) {
+				t.Parallel()
+
+				queryRes, err := coll.Query(ctx, &backends.QueryParams{Graph: &backends.GraphParams{
+					StartWith:        []any{insertDocs[2].Map()["_id"]},
+					ConnectFromField: "_id",
+					ConnectToField:   "_id",
+					MaxDepth:         -1,
+				}})
+				require.NoError(t, err)
+
+				// extra documents are allowed
+				docs, err := iterator.ConsumeValues[struct{}, *types.Document](queryRes.Iter)
+				require.NoError(t, err)
+				assert.True(t, slices.ContainsFunc(docs, func(d *types.Document) bool {
+					return d.Map()["_id"] == insertDocs[2].Map()["_id"]
+				}))
+
+				// only PostgreSQL applies graph traversal; other backends return all documents
+				assert.Equal(t, name == "postgresql", queryRes.GraphPushdown)
+
+				if !queryRes.GraphPushdown {
+					assert.Len(t, docs, len(insertDocs))
+				}
+			})
 		})
 	}
 }
@@ -22,6 +22,7 @@ import (
 	"go.opentelemetry.io/otel"
 	otelcodes "go.opentelemetry.io/otel/codes"
 
+	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/must"
 )
 
@@ -41,6 +42,7 @@ type Database interface {
 	CreateCollection(context.Context, *CreateCollectionParams) error
 	DropCollection(context.Context, *DropCollectionParams) error
 	RenameCollection(context.Context, *RenameCollectionParams) error
+	ModifyCollection(context.Context, *ModifyCollectionParams) error
 
 	Stats(context.Context, *DatabaseStatsParams) (*DatabaseStatsResult, error)
 }
@@ -94,7 +96,15 @@ type CollectionInfo struct {
 	UUID            string
 	CappedSize      int64
 	CappedDocuments int64
-	_               struct{} // prevent unkeyed literals
+
+	// Validator is set for collections with document validation.
+	// Validator, ValidationLevel and ValidationAction are stored as is;
+	// documents are validated by the handler.
+	Validator        *types.Document
+	ValidationLevel  string
+	ValidationAction string
+
+	_ struct{} // prevent unkeyed literals
 }
 
 // Capped returns true if collection is capped.
@@ -137,7 +147,12 @@ type CreateCollectionParams struct {
 	Name            string
 	CappedSize      int64
 	CappedDocuments int64
-	_               struct{} // prevent unkeyed literals
+
+	Validator        *types.Document
+	ValidationLevel  string
+	ValidationAction string
+
+	_ struct{} // prevent unkeyed literals
 }
 
 // Capped returns true if capped collection creation is requested.
@@ -148,6 +163,9 @@ func (ccp *CreateCollectionParams) Capped() bool {
 // CreateCollection creates a new collection with valid name in the database; it should not already exist.
 //
 // Database may or may not exist; it should be created automatically if needed.
+//
+// Backends that can't store validators should return ErrorCodeCollectionValidationNotSupported
+// if Validator is set.
 func (dbc *databaseContract) CreateCollection(ctx context.Context, params *CreateCollectionParams) error {
 	ctx, span := otel.Tracer("").Start(ctx, "CreateCollection")
 	defer span.End()
@@ -164,7 +182,10 @@ func (dbc *databaseContract) CreateCollection(ctx context.Context, params *Creat
 		span.SetStatus(otelcodes.Error, "")
 	}
 
-	checkError(err, ErrorCodeCollectionNameIsInvalid, ErrorCodeCollectionAlreadyExists)
+	checkError(
+		err,
+		ErrorCodeCollectionNameIsInvalid, ErrorCodeCollectionAlreadyExists, ErrorCodeCollectionValidationNotSupported,
+	)
 
 	return err
 }
@@ -197,13 +218,19 @@ func (dbc *databaseContract) DropCollection(ctx context.Context, params *DropCol
 
 // RenameCollectionParams represents the parameters of Database.RenameCollection method.
 type RenameCollectionParams struct {
-	OldName string
-	NewName string
+	OldName    string
+	NewName    string
+	DropTarget bool
 }
 
 // RenameCollection renames existing collection in the database.
 // Both old and new names should be valid.
 //
+// If DropTarget is true, the existing collection with the new name is replaced atomically:
+// it is dropped in the same transaction, so the new name never refers to a missing collection,
+// and the existing collection is kept if renaming fails.
+// Otherwise, ErrorCodeCollectionAlreadyExists is returned for the existing collection.
+//
 // The errors for non-existing database and non-existing collection are the same.
 func (dbc *databaseContract) RenameCollection(ctx context.Context, params *RenameCollectionParams) error {
 	ctx, span := otel.Tracer("").Start(ctx, "RenameCollection")
@@ -227,6 +254,44 @@ func (dbc *databaseContract) RenameCollection(ctx context.Context, params *Renam
 	return err
 }
 
+// ModifyCollectionParams represents the parameters of Database.ModifyCollection method.
+type ModifyCollectionParams struct {
+	Name string
+
+	// Validator, ValidationLevel and ValidationAction replace existing values.
+	Validator        *types.Document
+	ValidationLevel  string
+	ValidationAction string
+
+	_ struct{} // prevent unkeyed literals
+}
+
+// ModifyCollection modifies existing collection with valid name in the database.
+//
+// The errors for non-existing database and non-existing collection are the same.
+//
+// Backends that can't store validators should return ErrorCodeCollectionValidationNotSupported.
+func (dbc *databaseContract) ModifyCollection(ctx context.Context, params *ModifyCollectionParams) error {
+	ctx, span := otel.Tracer("").Start(ctx, "ModifyCollection")
+	defer span.End()
+
+	err := validateCollectionName(params.Name)
+	if err == nil {
+		err = dbc.db.ModifyCollection(ctx, params)
+	}
+
+	if err != nil {
+		span.SetStatus(otelcodes.Error, "")
+	}
+
+	checkError(
+		err,
+		ErrorCodeCollectionNameIsInvalid, ErrorCodeCollectionDoesNotExist, ErrorCodeCollectionValidationNotSupported,
+	)
+
+	return err
+}
+
 // DatabaseStatsParams represents the parameters of Database.Stats method.
 type DatabaseStatsParams struct {
 	Refresh bool
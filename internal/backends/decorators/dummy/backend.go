@@ -67,6 +67,13 @@ func (b *backend) DropDatabase(ctx context.Context, params *backends.DropDatabas
 	return b.b.DropDatabase(ctx, params)
 }
 
+// BeginTransaction implements backends.Backend interface.
+//
+//nolint:lll // for readability
+func (b *backend) BeginTransaction(ctx context.Context, params *backends.BeginTransactionParams) (backends.Transaction, error) {
+	return b.b.BeginTransaction(ctx, params)
+}
+
 // Describe implements prometheus.Collector.
 func (b *backend) Describe(ch chan<- *prometheus.Desc) {
 	b.b.Describe(ch)
@@ -69,6 +69,13 @@ func (b *backend) DropDatabase(ctx context.Context, params *backends.DropDatabas
 	return b.origB.DropDatabase(ctx, params)
 }
 
+// BeginTransaction implements backends.Backend interface.
+//
+//nolint:lll // for readability
+func (b *backend) BeginTransaction(ctx context.Context, params *backends.BeginTransactionParams) (backends.Transaction, error) {
+	return b.origB.BeginTransaction(ctx, params)
+}
+
 // Describe implements prometheus.Collector.
 func (b *backend) Describe(ch chan<- *prometheus.Desc) {
 	b.origB.Describe(ch)
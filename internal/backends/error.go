@@ -37,8 +37,14 @@ const (
 	ErrorCodeCollectionNameIsInvalid
 	ErrorCodeCollectionDoesNotExist
 	ErrorCodeCollectionAlreadyExists
+	ErrorCodeCollectionValidationNotSupported
 
 	ErrorCodeInsertDuplicateID
+
+	ErrorCodeTransactionsNotSupported
+
+	ErrorCodeIndexPartialFilterNotSupported
+	ErrorCodeIndexCollationNotSupported
 )
 
 // Error represents a backend error returned by all Backend, Database and Collection methods.
@@ -13,12 +13,16 @@ func _() {
 	_ = x[ErrorCodeCollectionNameIsInvalid-3]
 	_ = x[ErrorCodeCollectionDoesNotExist-4]
 	_ = x[ErrorCodeCollectionAlreadyExists-5]
-	_ = x[ErrorCodeInsertDuplicateID-6]
+	_ = x[ErrorCodeCollectionValidationNotSupported-6]
+	_ = x[ErrorCodeInsertDuplicateID-7]
+	_ = x[ErrorCodeTransactionsNotSupported-8]
+	_ = x[ErrorCodeIndexPartialFilterNotSupported-9]
+	_ = x[ErrorCodeIndexCollationNotSupported-10]
 }
 
-const _ErrorCode_name = "ErrorCodeDatabaseNameIsInvalidErrorCodeDatabaseDoesNotExistErrorCodeCollectionNameIsInvalidErrorCodeCollectionDoesNotExistErrorCodeCollectionAlreadyExistsErrorCodeInsertDuplicateID"
+const _ErrorCode_name = "ErrorCodeDatabaseNameIsInvalidErrorCodeDatabaseDoesNotExistErrorCodeCollectionNameIsInvalidErrorCodeCollectionDoesNotExistErrorCodeCollectionAlreadyExistsErrorCodeCollectionValidationNotSupportedErrorCodeInsertDuplicateIDErrorCodeTransactionsNotSupportedErrorCodeIndexPartialFilterNotSupportedErrorCodeIndexCollationNotSupported"
 
-var _ErrorCode_index = [...]uint8{0, 30, 59, 91, 122, 154, 180}
+var _ErrorCode_index = [...]uint16{0, 30, 59, 91, 122, 154, 195, 221, 254, 293, 328}
 
 func (i ErrorCode) String() string {
 	i -= 1
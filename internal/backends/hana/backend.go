@@ -159,6 +159,15 @@ func (b *backend) DropDatabase(ctx context.Context, params *backends.DropDatabas
 	return nil
 }
 
+// BeginTransaction implements backends.Backend interface.
+//
+// Transactions are not supported yet.
+//
+//nolint:lll // for readability
+func (b *backend) BeginTransaction(ctx context.Context, params *backends.BeginTransactionParams) (backends.Transaction, error) {
+	return nil, backends.NewError(backends.ErrorCodeTransactionsNotSupported, nil)
+}
+
 // Describe implements prometheus.Collector.
 func (b *backend) Describe(ch chan<- *prometheus.Desc) {
 }
@@ -398,6 +398,22 @@ func (c *collection) ListIndexes(ctx context.Context, params *backends.ListIndex
 
 // CreateIndexes implements backends.Collection interface.
 func (c *collection) CreateIndexes(ctx context.Context, params *backends.CreateIndexesParams) (*backends.CreateIndexesResult, error) { //nolint:lll // for readability
+	for _, index := range params.Indexes {
+		if index.PartialFilterExpression != nil {
+			return nil, backends.NewError(
+				backends.ErrorCodeIndexPartialFilterNotSupported,
+				lazyerrors.Errorf("partial index %q is not supported by SAP HANA backend", index.Name),
+			)
+		}
+
+		if index.Collation != nil {
+			return nil, backends.NewError(
+				backends.ErrorCodeIndexCollationNotSupported,
+				lazyerrors.Errorf("index %q with collation is not supported by SAP HANA backend", index.Name),
+			)
+		}
+	}
+
 	return createIndexes(ctx, c.hdb, c.database, c.name, params)
 }
 
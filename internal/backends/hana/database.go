@@ -105,6 +105,13 @@ func (db *database) ListCollections(
 
 // CreateCollection implements backends.Database interface.
 func (db *database) CreateCollection(ctx context.Context, params *backends.CreateCollectionParams) error {
+	if params.Validator != nil {
+		return backends.NewError(
+			backends.ErrorCodeCollectionValidationNotSupported,
+			lazyerrors.Errorf("validator of collection %q is not supported by SAP HANA backend", params.Name),
+		)
+	}
+
 	exists, err := collectionExists(ctx, db.hdb, db.name, params.Name)
 	if err != nil {
 		return lazyerrors.Error(err)
@@ -149,7 +156,7 @@ func (db *database) RenameCollection(ctx context.Context, params *backends.Renam
 		return getHanaErrorIfExists(err)
 	}
 
-	if col {
+	if col && !params.DropTarget {
 		return backends.NewError(backends.ErrorCodeCollectionAlreadyExists,
 			lazyerrors.Errorf("new database %q or collection %q already exists", db.name, params.NewName),
 		)
@@ -157,7 +164,20 @@ func (db *database) RenameCollection(ctx context.Context, params *backends.Renam
 
 	sqlStmt := fmt.Sprintf("RENAME COLLECTION %q.%q to %q", db.name, params.OldName, params.NewName)
 
-	_, err = db.hdb.ExecContext(ctx, sqlStmt)
+	err = db.hdb.InTransaction(ctx, func(tx *fsql.Tx) error {
+		if col {
+			dropStmt := fmt.Sprintf("DROP COLLECTION %q.%q CASCADE", db.name, params.NewName)
+			if _, err := tx.ExecContext(ctx, dropStmt); err != nil {
+				return lazyerrors.Error(err)
+			}
+		}
+
+		if _, err := tx.ExecContext(ctx, sqlStmt); err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		return nil
+	})
 	if err != nil {
 		return lazyerrors.Error(err)
 	}
@@ -165,6 +185,16 @@ func (db *database) RenameCollection(ctx context.Context, params *backends.Renam
 	return nil
 }
 
+// ModifyCollection implements backends.Database interface.
+//
+// SAP HANA backend does not store collection metadata, so validators are not supported.
+func (db *database) ModifyCollection(ctx context.Context, params *backends.ModifyCollectionParams) error {
+	return backends.NewError(
+		backends.ErrorCodeCollectionValidationNotSupported,
+		lazyerrors.Errorf("validator of collection %q is not supported by SAP HANA backend", params.Name),
+	)
+}
+
 // Stats implements backends.Database interface.
 func (db *database) Stats(ctx context.Context, params *backends.DatabaseStatsParams) (*backends.DatabaseStatsResult, error) {
 	d, err := databaseExists(ctx, db.hdb, db.name)
@@ -101,7 +101,17 @@ func (c *collection) Query(ctx context.Context, params *backends.QueryParams) (*
 		args = append(args, params.Limit)
 	}
 
-	rows, err := p.QueryContext(ctx, q, args...)
+	if params.Sample != 0 {
+		q += ` ORDER BY RAND() LIMIT ?`
+		args = append(args, params.Sample)
+	}
+
+	qr, err := getQuerier(ctx, p)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	rows, err := qr.QueryContext(ctx, q, args...)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
@@ -130,7 +140,7 @@ func (c *collection) InsertAll(ctx context.Context, params *backends.InsertAllPa
 		return nil, lazyerrors.Error(err)
 	}
 
-	err = p.InTransaction(ctx, func(tx *fsql.Tx) error {
+	err = inTransaction(ctx, p, func(tx *fsql.Tx) error {
 		const batchSize = 100
 
 		var batch []*types.Document
@@ -195,7 +205,7 @@ func (c *collection) UpdateAll(ctx context.Context, params *backends.UpdateAllPa
 		metadata.IDColumn,
 	)
 
-	err = p.InTransaction(ctx, func(tx *fsql.Tx) error {
+	err = inTransaction(ctx, p, func(tx *fsql.Tx) error {
 		for _, doc := range params.Docs {
 			var b []byte
 
@@ -278,7 +288,12 @@ func (c *collection) DeleteAll(ctx context.Context, params *backends.DeleteAllPa
 		strings.Join(placeholders, ", "),
 	)
 
-	res, err := p.ExecContext(ctx, q, args...)
+	qr, err := getQuerier(ctx, p)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	res, err := qr.ExecContext(ctx, q, args...)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
@@ -523,9 +538,10 @@ func (c *collection) ListIndexes(ctx context.Context, params *backends.ListIndex
 
 	for i, index := range coll.Indexes {
 		res.Indexes[i] = backends.IndexInfo{
-			Name:   index.Name,
-			Unique: index.Unique,
-			Key:    make([]backends.IndexKeyPair, len(index.Key)),
+			Name:               index.Name,
+			Unique:             index.Unique,
+			Key:                make([]backends.IndexKeyPair, len(index.Key)),
+			ExpireAfterSeconds: index.ExpireAfterSeconds,
 		}
 
 		for j, key := range index.Key {
@@ -545,10 +561,25 @@ func (c *collection) ListIndexes(ctx context.Context, params *backends.ListIndex
 func (c *collection) CreateIndexes(ctx context.Context, params *backends.CreateIndexesParams) (*backends.CreateIndexesResult, error) { //nolint:lll // for readability
 	indexes := make([]metadata.IndexInfo, len(params.Indexes))
 	for i, index := range params.Indexes {
+		if index.PartialFilterExpression != nil {
+			return nil, backends.NewError(
+				backends.ErrorCodeIndexPartialFilterNotSupported,
+				lazyerrors.Errorf("partial index %q is not supported by MySQL backend", index.Name),
+			)
+		}
+
+		if index.Collation != nil {
+			return nil, backends.NewError(
+				backends.ErrorCodeIndexCollationNotSupported,
+				lazyerrors.Errorf("index %q with collation is not supported by MySQL backend", index.Name),
+			)
+		}
+
 		indexes[i] = metadata.IndexInfo{
-			Name:   index.Name,
-			Key:    make([]metadata.IndexKeyPair, len(index.Key)),
-			Unique: index.Unique,
+			Name:               index.Name,
+			Key:                make([]metadata.IndexKeyPair, len(index.Key)),
+			Unique:             index.Unique,
+			ExpireAfterSeconds: index.ExpireAfterSeconds,
 		}
 
 		for j, key := range index.Key {
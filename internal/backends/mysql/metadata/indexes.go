@@ -33,6 +33,9 @@ type IndexInfo struct {
 	Index  string
 	Key    []IndexKeyPair
 	Unique bool
+
+	// ExpireAfterSeconds is set for TTL indexes.
+	ExpireAfterSeconds *int32
 }
 
 // IndexKeyPair consists of a field name and a sort order that are part of the index.
@@ -47,10 +50,11 @@ func (indexes Indexes) deepCopy() Indexes {
 
 	for i, index := range indexes {
 		res[i] = IndexInfo{
-			Name:   index.Name,
-			Index:  index.Index,
-			Key:    slices.Clone(index.Key),
-			Unique: index.Unique,
+			Name:               index.Name,
+			Index:              index.Index,
+			Key:                slices.Clone(index.Key),
+			Unique:             index.Unique,
+			ExpireAfterSeconds: index.ExpireAfterSeconds,
 		}
 	}
 
@@ -73,12 +77,18 @@ func (indexes Indexes) marshal() *types.Array {
 			key.Set(pair.Field, order)
 		}
 
-		res.Append(must.NotFail(types.NewDocument(
+		doc := must.NotFail(types.NewDocument(
 			"name", index.Name,
 			"index", index.Index,
 			"key", key,
 			"unique", index.Unique,
-		)))
+		))
+
+		if index.ExpireAfterSeconds != nil {
+			doc.Set("expireAfterSeconds", *index.ExpireAfterSeconds)
+		}
+
+		res.Append(doc)
 	}
 
 	return res
@@ -129,6 +139,15 @@ func (s *Indexes) unmarshal(a *types.Array) error {
 			Key:    key,
 			Unique: unique,
 		}
+
+		if v, _ = index.Get("expireAfterSeconds"); v != nil {
+			expireAfterSeconds, ok := v.(int32)
+			if !ok {
+				return lazyerrors.Errorf("unexpected expireAfterSeconds type %T", v)
+			}
+
+			res[i].ExpireAfterSeconds = &expireAfterSeconds
+		}
 	}
 
 	*s = res
@@ -54,6 +54,11 @@ type Collection struct {
 	Indexes         Indexes
 	CappedSize      int64
 	CappedDocuments int64
+
+	// Validator is set for collections with document validation.
+	Validator        *types.Document
+	ValidationLevel  string
+	ValidationAction string
 }
 
 // deepCopy returns a deep copy.
@@ -62,14 +67,22 @@ func (c *Collection) deepCopy() *Collection {
 		return nil
 	}
 
-	return &Collection{
-		Name:            c.Name,
-		UUID:            c.UUID,
-		TableName:       c.TableName,
-		Indexes:         c.Indexes.deepCopy(),
-		CappedSize:      c.CappedSize,
-		CappedDocuments: c.CappedDocuments,
+	res := &Collection{
+		Name:             c.Name,
+		UUID:             c.UUID,
+		TableName:        c.TableName,
+		Indexes:          c.Indexes.deepCopy(),
+		CappedSize:       c.CappedSize,
+		CappedDocuments:  c.CappedDocuments,
+		ValidationLevel:  c.ValidationLevel,
+		ValidationAction: c.ValidationAction,
+	}
+
+	if c.Validator != nil {
+		res.Validator = c.Validator.DeepCopy()
 	}
+
+	return res
 }
 
 // Capped returns true if collection is capped.
@@ -117,7 +130,7 @@ func (c *Collection) Scan(src any) error {
 
 // marshal returns the [*types.Document] for that collection.
 func (c *Collection) marshal() *types.Document {
-	return must.NotFail(types.NewDocument(
+	res := must.NotFail(types.NewDocument(
 		"_id", c.Name,
 		"uuid", c.UUID,
 		"table", c.TableName,
@@ -125,6 +138,14 @@ func (c *Collection) marshal() *types.Document {
 		"cappedSize", c.CappedSize,
 		"cappedDocuments", c.CappedDocuments,
 	))
+
+	if c.Validator != nil {
+		res.Set("validator", c.Validator)
+		res.Set("validationLevel", c.ValidationLevel)
+		res.Set("validationAction", c.ValidationAction)
+	}
+
+	return res
 }
 
 // unmarshal sets collection metadata from [*types.Document].
@@ -166,6 +187,12 @@ func (c *Collection) unmarshal(doc *types.Document) error {
 		c.CappedSize = v.(int64)
 	}
 
+	if v, _ := doc.Get("validator"); v != nil {
+		c.Validator = v.(*types.Document)
+		c.ValidationLevel, _ = must.NotFail(doc.Get("validationLevel")).(string)
+		c.ValidationAction, _ = must.NotFail(doc.Get("validationAction")).(string)
+	}
+
 	return nil
 }
 
@@ -33,8 +33,10 @@ import (
 	"github.com/FerretDB/FerretDB/internal/backends/mysql/metadata/pool"
 	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
 	"github.com/FerretDB/FerretDB/internal/handler/sjson"
+	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/fsql"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/logging"
 	"github.com/FerretDB/FerretDB/internal/util/must"
 	"github.com/FerretDB/FerretDB/internal/util/state"
 )
@@ -450,10 +452,13 @@ func (r *Registry) CollectionList(ctx context.Context, dbName string) ([]*Collec
 
 // CollectionCreateParams contains parameters for CollectionCreate.
 type CollectionCreateParams struct {
-	DBName          string
-	Name            string
-	CappedSize      int64
-	CappedDocuments int64
+	DBName           string
+	Name             string
+	CappedSize       int64
+	CappedDocuments  int64
+	Validator        *types.Document
+	ValidationLevel  string
+	ValidationAction string
 }
 
 // Capped returns true if capped collection creation is requested.
@@ -526,11 +531,14 @@ func (r *Registry) collectionCreate(ctx context.Context, p *fsql.DB, params *Col
 	}
 
 	c := &Collection{
-		Name:            collectionName,
-		UUID:            uuid.NewString(),
-		TableName:       tableName,
-		CappedSize:      params.CappedSize,
-		CappedDocuments: params.CappedDocuments,
+		Name:             collectionName,
+		UUID:             uuid.NewString(),
+		TableName:        tableName,
+		CappedSize:       params.CappedSize,
+		CappedDocuments:  params.CappedDocuments,
+		Validator:        params.Validator,
+		ValidationLevel:  params.ValidationLevel,
+		ValidationAction: params.ValidationAction,
 	}
 
 	q := fmt.Sprintf(`CREATE TABLE %s.%s (`, dbName, tableName)
@@ -728,6 +736,163 @@ func (r *Registry) CollectionRename(ctx context.Context, dbName, oldCollectionNa
 	return true, nil
 }
 
+// CollectionReplace renames a collection in the database, replacing the existing collection with the new name.
+//
+// The metadata of the existing collection is removed, and the collection name is updated in a single transaction,
+// so the new name always refers to either the existing or the renamed collection.
+// If the transaction fails, both collections are kept as they were.
+// MySQL commits transactions implicitly on DROP TABLE,
+// so the table of the existing collection is dropped after the transaction.
+//
+// Returned boolean value indicates whether the collection was renamed.
+// If database or old collection did not exist, (false, nil) is returned.
+//
+// If the user is not authenticated, it returns error.
+func (r *Registry) CollectionReplace(ctx context.Context, dbName, oldCollectionName, newCollectionName string) (bool, error) {
+	p, err := r.getPool(ctx)
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	db := r.colls[dbName]
+	if db == nil {
+		return false, nil
+	}
+
+	c := r.collectionGet(dbName, oldCollectionName)
+	if c == nil {
+		return false, nil
+	}
+
+	target := r.collectionGet(dbName, newCollectionName)
+
+	c.Name = newCollectionName
+
+	b, err := sjson.Marshal(c.marshal())
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	oldArg, err := sjson.MarshalSingleValue(oldCollectionName)
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	newArg, err := sjson.MarshalSingleValue(newCollectionName)
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	err = p.InTransaction(ctx, func(tx *fsql.Tx) error {
+		if target != nil {
+			q := fmt.Sprintf(
+				`DELETE FROM %s.%s WHERE %s = ?`,
+				dbName, metadataTableName,
+				IDIndexColumn,
+			)
+
+			if _, err = tx.ExecContext(ctx, q, string(newArg)); err != nil {
+				return lazyerrors.Error(err)
+			}
+		}
+
+		q := fmt.Sprintf(
+			`UPDATE %s.%s SET %s = ? WHERE %s = ?`,
+			dbName, metadataTableName,
+			DefaultColumn,
+			IDIndexColumn,
+		)
+
+		if _, err = tx.ExecContext(ctx, q, string(b), oldArg); err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	r.colls[dbName][newCollectionName] = c
+	delete(r.colls[dbName], oldCollectionName)
+
+	if target != nil {
+		// the collection is already replaced, and the table is not referenced by metadata,
+		// so the error is only logged
+		q := fmt.Sprintf(`DROP TABLE %s.%s`, dbName, target.TableName)
+		if _, err = p.ExecContext(ctx, q); err != nil {
+			r.l.WarnContext(
+				ctx, "Failed to drop replaced collection table",
+				slog.String("table", target.TableName), logging.Error(err),
+			)
+		}
+	}
+
+	return true, nil
+}
+
+// CollectionModifyParams contains parameters for CollectionModify.
+type CollectionModifyParams struct {
+	DBName           string
+	Name             string
+	Validator        *types.Document
+	ValidationLevel  string
+	ValidationAction string
+}
+
+// CollectionModify replaces validation settings of the collection.
+//
+// Returned boolean value indicates whether the collection was modified.
+// If database or collection did not exist, (false, nil) is returned.
+//
+// If the user is not authenticated, it returns error.
+func (r *Registry) CollectionModify(ctx context.Context, params *CollectionModifyParams) (bool, error) {
+	p, err := r.getPool(ctx)
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	c := r.collectionGet(params.DBName, params.Name)
+	if c == nil {
+		return false, nil
+	}
+
+	c.Validator = params.Validator
+	c.ValidationLevel = params.ValidationLevel
+	c.ValidationAction = params.ValidationAction
+
+	b, err := sjson.Marshal(c.marshal())
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	arg, err := sjson.MarshalSingleValue(params.Name)
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	q := fmt.Sprintf(
+		`UPDATE %s.%s SET %s = ? WHERE %s = ?`,
+		params.DBName, metadataTableName,
+		DefaultColumn,
+		IDIndexColumn,
+	)
+
+	if _, err := p.ExecContext(ctx, q, string(b), arg); err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	r.colls[params.DBName][params.Name] = c
+
+	return true, nil
+}
+
 // IndexesCreate creates indexes in the collection.
 //
 // Existing indexes with given names are ignored.
@@ -170,6 +170,12 @@ func prepareWhereClause(sqlFilters *types.Document) (string, []any, error) {
 						args = append(args, a...)
 					}
 
+				case "$in":
+					if f, a := filterIn(rootKey, v); f != "" {
+						filters = append(filters, f)
+						args = append(args, a...)
+					}
+
 				case "$ne":
 					sql := `NOT ( ` +
 						// check if the value under the key is equal to filter value
@@ -179,7 +185,8 @@ func prepareWhereClause(sqlFilters *types.Document) (string, []any, error) {
 
 					switch v := v.(type) {
 					case *types.Document, *types.Array, types.Binary,
-						types.NullType, types.Regex, types.Timestamp:
+						types.NullType, types.Regex, types.Timestamp,
+						types.Decimal128, types.MinKeyType, types.MaxKeyType:
 					// type not supported for pushdown
 
 					case float64, bool, int32, int64:
@@ -204,14 +211,21 @@ func prepareWhereClause(sqlFilters *types.Document) (string, []any, error) {
 						panic(fmt.Sprintf("Unexpected type of value: %v", v))
 					}
 
+				case "$gt", "$lt":
+					if f, a := filterCompare(rootKey, v, k); f != "" {
+						filters = append(filters, f)
+						args = append(args, a...)
+					}
+
 				default:
-					// $gt and $lt
+					// $gte and $lte
 					// TODO https://github.com/FerretDB/FerretDB/issues/1875
 					continue
 				}
 			}
 
-		case *types.Array, types.Binary, types.NullType, types.Regex, types.Timestamp:
+		case *types.Array, types.Binary, types.NullType, types.Regex, types.Timestamp,
+			types.Decimal128, types.MinKeyType, types.MaxKeyType:
 			// type not supported for pushdown
 
 		case float64, string, types.ObjectID, bool, time.Time, int32, int64:
@@ -241,7 +255,8 @@ func filterEqual(k string, v any) (filter string, args []any) {
 
 	switch v := v.(type) {
 	case *types.Document, *types.Array, types.Binary,
-		types.NullType, types.Regex, types.Timestamp:
+		types.NullType, types.Regex, types.Timestamp,
+		types.Decimal128, types.MinKeyType, types.MaxKeyType:
 		// type not supported for pushdown
 
 	case float64:
@@ -259,7 +274,7 @@ func filterEqual(k string, v any) (filter string, args []any) {
 			// don't change the default eq query
 		}
 
-		filter = fmt.Sprintf(sql, metadata.DefaultColumn)
+		filter = filterNumber(fmt.Sprintf(sql, metadata.DefaultColumn))
 		args = append(args, k, v)
 
 	case string, types.ObjectID, time.Time:
@@ -267,11 +282,16 @@ func filterEqual(k string, v any) (filter string, args []any) {
 		filter = fmt.Sprintf(sql, metadata.DefaultColumn)
 		args = append(args, k, string(must.NotFail(sjson.MarshalSingleValue(v))))
 
-	case bool, int32:
+	case bool:
 		// don't change the default eq query
 		filter = fmt.Sprintf(sql, metadata.DefaultColumn)
 		args = append(args, k, v)
 
+	case int32:
+		// don't change the default eq query
+		filter = filterNumber(fmt.Sprintf(sql, metadata.DefaultColumn))
+		args = append(args, k, v)
+
 	case int64:
 		maxSafeDouble := int64(types.MaxSafeDouble)
 
@@ -288,7 +308,7 @@ func filterEqual(k string, v any) (filter string, args []any) {
 			// don't change the default eq query
 		}
 
-		filter = fmt.Sprintf(sql, metadata.DefaultColumn)
+		filter = filterNumber(fmt.Sprintf(sql, metadata.DefaultColumn))
 		args = append(args, k, v)
 
 	default:
@@ -297,3 +317,75 @@ func filterEqual(k string, v any) (filter string, args []any) {
 
 	return
 }
+
+// filterNumber extends the filter for a number to also select documents
+// where the value under the key is Decimal128 or an array containing Decimal128.
+// Decimals are stored as JSON strings, so they could not be compared with numbers in SQL.
+func filterNumber(filter string) string {
+	return fmt.Sprintf(
+		`(%[1]s OR JSON_CONTAINS(%[2]s->'$.$s.p.?', '{"t": "decimal"}') OR JSON_CONTAINS(%[2]s->'$.$s.p.?.i', '{"t": "decimal"}'))`,
+		filter, metadata.DefaultColumn,
+	)
+}
+
+// filterCompare returns the proper SQL filter with arguments that filters documents
+// where the value under k is greater than ($gt) or less than ($lt) v.
+//
+// Only dates and timestamps are supported, as they are stored as JSON numbers
+// that could be compared directly. Arrays are always selected, as their elements could match.
+// It returns an empty filter for other values.
+func filterCompare(k string, v any, op string) (filter string, args []any) {
+	var n int64
+
+	switch v := v.(type) {
+	case time.Time:
+		n = v.UnixMilli()
+
+	case types.Timestamp:
+		if n = v.Signed(); n < 0 {
+			return
+		}
+
+	default:
+		return
+	}
+
+	sqlOp := ">"
+	if op == "$lt" {
+		sqlOp = "<"
+	}
+
+	filter = fmt.Sprintf(
+		`((%[1]s->$.? %[2]s ? AND %[1]s->'$.$s.p.?.t' = '"%[3]s"') OR JSON_TYPE(%[1]s->$.?) = 'ARRAY')`,
+		metadata.DefaultColumn, sqlOp, sjson.GetTypeOfValue(v),
+	)
+	args = append(args, k, n, k)
+
+	return
+}
+
+// filterIn returns the proper SQL filter with arguments that filters documents
+// where the value under k is equal to any element of the array v.
+//
+// It returns an empty filter if v is not an array, or any of its elements is not supported for pushdown.
+func filterIn(k string, v any) (filter string, args []any) {
+	arr, ok := v.(*types.Array)
+	if !ok || arr.Len() == 0 {
+		return
+	}
+
+	filters := make([]string, arr.Len())
+
+	for i := range filters {
+		var a []any
+		if filters[i], a = filterEqual(k, must.NotFail(arr.Get(i))); filters[i] == "" {
+			return "", nil
+		}
+
+		args = append(args, a...)
+	}
+
+	filter = `(` + strings.Join(filters, ` OR `) + `)`
+
+	return
+}
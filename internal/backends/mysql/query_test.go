@@ -95,7 +95,12 @@ func TestPrepareWhereClause(t *testing.T) {
 
 	// WHERE clauses occurring frequently in tests
 	whereContain := " WHERE JSON_CONTAINS(_ferretdb_sjson->$.?, ?, '$')"
-	whereGt := " WHERE _ferretdb_sjson->$.? > ?"
+
+	// numbers also select Decimal128 values stored as strings
+	whereDecimal := ` OR JSON_CONTAINS(_ferretdb_sjson->'$.$s.p.?', '{"t": "decimal"}')` +
+		` OR JSON_CONTAINS(_ferretdb_sjson->'$.$s.p.?.i', '{"t": "decimal"}'))`
+	whereContainNumber := " WHERE (JSON_CONTAINS(_ferretdb_sjson->$.?, ?, '$')" + whereDecimal
+	whereGtNumber := " WHERE (_ferretdb_sjson->$.? > ?" + whereDecimal
 	whereNotEq := ` WHERE NOT ( JSON_CONTAINS(_ferretdb_sjson->$.?, ?, '$') AND _ferretdb_sjson->'$.$s.p.?.t' = `
 
 	for name, tc := range map[string]struct {
@@ -137,19 +142,19 @@ func TestPrepareWhereClause(t *testing.T) {
 		},
 		"ImplicitInt32": {
 			filter:   must.NotFail(types.NewDocument("v", int32(42))),
-			expected: whereContain,
+			expected: whereContainNumber,
 		},
 		"ImplicitInt64": {
 			filter:   must.NotFail(types.NewDocument("v", int64(42))),
-			expected: whereContain,
+			expected: whereContainNumber,
 		},
 		"ImplicitFloat64": {
 			filter:   must.NotFail(types.NewDocument("v", float64(42.13))),
-			expected: whereContain,
+			expected: whereContainNumber,
 		},
 		"ImplicitMaxFloat64": {
 			filter:   must.NotFail(types.NewDocument("v", math.MaxFloat64)),
-			expected: whereGt,
+			expected: whereGtNumber,
 		},
 		"ImplicitBool": {
 			filter:   must.NotFail(types.NewDocument("v", true)),
@@ -183,26 +188,26 @@ func TestPrepareWhereClause(t *testing.T) {
 			filter: must.NotFail(types.NewDocument(
 				"v", must.NotFail(types.NewDocument("$eq", int32(42))),
 			)),
-			expected: whereContain,
+			expected: whereContainNumber,
 		},
 		"EqInt64": {
 			filter: must.NotFail(types.NewDocument(
 				"v", must.NotFail(types.NewDocument("$eq", int64(42))),
 			)),
-			expected: whereContain,
+			expected: whereContainNumber,
 		},
 		"EqFloat64": {
 			filter: must.NotFail(types.NewDocument(
 				"v", must.NotFail(types.NewDocument("$eq", float64(42.13))),
 			)),
-			expected: whereContain,
+			expected: whereContainNumber,
 		},
 		"EqMaxFloat64": {
 			filter: must.NotFail(types.NewDocument(
 				"v", must.NotFail(types.NewDocument("$eq", math.MaxFloat64)),
 			)),
 			args:     []any{`v`, types.MaxSafeDouble},
-			expected: whereGt,
+			expected: whereGtNumber,
 		},
 		"EqDoubleBigInt64": {
 			filter: must.NotFail(types.NewDocument(
@@ -210,7 +215,7 @@ func TestPrepareWhereClause(t *testing.T) {
 				"v", must.NotFail(types.NewDocument("$eq", float64(2<<61))),
 			)),
 			args:     []any{`v`, types.MaxSafeDouble},
-			expected: whereGt,
+			expected: whereGtNumber,
 		},
 		"EqBool": {
 			filter: must.NotFail(types.NewDocument(
@@ -291,6 +296,52 @@ func TestPrepareWhereClause(t *testing.T) {
 			expected: whereNotEq + `'"objectId"' )`,
 		},
 
+		"GtDatetime": {
+			filter: must.NotFail(types.NewDocument(
+				"v", must.NotFail(types.NewDocument(
+					"$gt", time.Date(2021, 11, 1, 10, 18, 42, 123000000, time.UTC),
+				)),
+			)),
+			expected: ` WHERE ((_ferretdb_sjson->$.? > ? AND _ferretdb_sjson->'$.$s.p.?.t' = '"date"') OR ` +
+				`JSON_TYPE(_ferretdb_sjson->$.?) = 'ARRAY')`,
+			args: []any{"v", int64(1635761922123), "v"},
+		},
+		"LtTimestamp": {
+			filter: must.NotFail(types.NewDocument(
+				"v", must.NotFail(types.NewDocument("$lt", types.Timestamp(42))),
+			)),
+			expected: ` WHERE ((_ferretdb_sjson->$.? < ? AND _ferretdb_sjson->'$.$s.p.?.t' = '"timestamp"') OR ` +
+				`JSON_TYPE(_ferretdb_sjson->$.?) = 'ARRAY')`,
+			args: []any{"v", int64(42), "v"},
+		},
+		"GtUnsupported": {
+			filter: must.NotFail(types.NewDocument(
+				"v", must.NotFail(types.NewDocument("$gt", int32(42))),
+			)),
+		},
+		"In": {
+			filter: must.NotFail(types.NewDocument(
+				"v", must.NotFail(types.NewDocument("$in", must.NotFail(types.NewArray("foo", int32(42))))),
+			)),
+			expected: " WHERE (JSON_CONTAINS(_ferretdb_sjson->$.?, ?, '$') OR (JSON_CONTAINS(_ferretdb_sjson->$.?, ?, '$')" +
+				whereDecimal + ")",
+		},
+		"InEmpty": {
+			filter: must.NotFail(types.NewDocument(
+				"v", must.NotFail(types.NewDocument("$in", must.NotFail(types.NewArray()))),
+			)),
+		},
+		"InUnsupported": {
+			filter: must.NotFail(types.NewDocument(
+				"v", must.NotFail(types.NewDocument("$in", must.NotFail(types.NewArray("foo", types.Null)))),
+			)),
+		},
+		"InNotArray": {
+			filter: must.NotFail(types.NewDocument(
+				"v", must.NotFail(types.NewDocument("$in", "foo")),
+			)),
+		},
+
 		"Comment": {
 			filter: must.NotFail(types.NewDocument("$comment", "I'm comment")),
 		},
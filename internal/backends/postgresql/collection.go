@@ -27,7 +27,6 @@ import (
 
 	"github.com/FerretDB/FerretDB/internal/backends"
 	"github.com/FerretDB/FerretDB/internal/backends/postgresql/metadata"
-	"github.com/FerretDB/FerretDB/internal/backends/postgresql/metadata/pool"
 	"github.com/FerretDB/FerretDB/internal/handler/sjson"
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
@@ -94,6 +93,11 @@ func (c *collection) Query(ctx context.Context, params *backends.QueryParams) (*
 
 	q += where
 
+	graph, graphArgs := prepareGraphWhereClause(&placeholder, c.dbName, meta.TableName, params.Graph)
+
+	q += graph
+	args = append(args, graphArgs...)
+
 	sort, sortArgs := prepareOrderByClause(params.Sort)
 
 	q += sort
@@ -104,13 +108,28 @@ func (c *collection) Query(ctx context.Context, params *backends.QueryParams) (*
 		args = append(args, params.Limit)
 	}
 
-	rows, err := p.Query(ctx, q, args...)
+	if params.Sample != 0 {
+		// TABLESAMPLE is not used because it selects an approximate fraction of table pages,
+		// so it could return fewer documents than requested
+		q += fmt.Sprintf(` ORDER BY random() LIMIT %s`, placeholder.Next())
+		args = append(args, params.Sample)
+	}
+
+	qr, err := getQuerier(ctx, p)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	// if qr is a transaction, rows are read by backends.CollectionContract before returning,
+	// so the connection of the transaction is not busy when other collection methods are called
+	rows, err := qr.Query(ctx, q, args...)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
 
 	return &backends.QueryResult{
-		Iter: newQueryIterator(ctx, rows, params.OnlyRecordIDs),
+		Iter:          newQueryIterator(ctx, rows, params.OnlyRecordIDs),
+		GraphPushdown: graph != "",
 	}, nil
 }
 
@@ -133,7 +152,7 @@ func (c *collection) InsertAll(ctx context.Context, params *backends.InsertAllPa
 		return nil, lazyerrors.Error(err)
 	}
 
-	err = pool.InTransaction(ctx, p, func(tx pgx.Tx) error {
+	err = inTransaction(ctx, p, func(tx pgx.Tx) error {
 		batchSize := c.r.BatchSize
 		if batchSize < 1 {
 			panic("batch-size should be greater or equal to 1")
@@ -201,7 +220,7 @@ func (c *collection) UpdateAll(ctx context.Context, params *backends.UpdateAllPa
 		metadata.IDColumn,
 	)
 
-	err = pool.InTransaction(ctx, p, func(tx pgx.Tx) error {
+	err = inTransaction(ctx, p, func(tx pgx.Tx) error {
 		for _, doc := range params.Docs {
 			var b []byte
 			if b, err = sjson.Marshal(doc); err != nil {
@@ -286,7 +305,12 @@ func (c *collection) DeleteAll(ctx context.Context, params *backends.DeleteAllPa
 		strings.Join(placeholders, ", "),
 	)
 
-	res, err := p.Exec(ctx, q, args...)
+	qr, err := getQuerier(ctx, p)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	res, err := qr.Exec(ctx, q, args...)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
@@ -530,9 +554,12 @@ func (c *collection) ListIndexes(ctx context.Context, params *backends.ListIndex
 
 	for i, index := range coll.Indexes {
 		res.Indexes[i] = backends.IndexInfo{
-			Name:   index.Name,
-			Unique: index.Unique,
-			Key:    make([]backends.IndexKeyPair, len(index.Key)),
+			Name:                    index.Name,
+			Unique:                  index.Unique,
+			Key:                     make([]backends.IndexKeyPair, len(index.Key)),
+			ExpireAfterSeconds:      index.ExpireAfterSeconds,
+			PartialFilterExpression: index.PartialFilterExpression,
+			Collation:               index.Collation,
 		}
 
 		for j, key := range index.Key {
@@ -555,9 +582,26 @@ func (c *collection) CreateIndexes(ctx context.Context, params *backends.CreateI
 	indexes := make([]metadata.IndexInfo, len(params.Indexes))
 	for i, index := range params.Indexes {
 		indexes[i] = metadata.IndexInfo{
-			Name:   index.Name,
-			Key:    make([]metadata.IndexKeyPair, len(index.Key)),
-			Unique: index.Unique,
+			Name:                    index.Name,
+			Key:                     make([]metadata.IndexKeyPair, len(index.Key)),
+			Unique:                  index.Unique,
+			ExpireAfterSeconds:      index.ExpireAfterSeconds,
+			PartialFilterExpression: index.PartialFilterExpression,
+			Collation:               index.Collation,
+		}
+
+		if index.PartialFilterExpression != nil {
+			if index.Unique && !exactPartialFilter(index.PartialFilterExpression) {
+				return nil, backends.NewError(
+					backends.ErrorCodeIndexPartialFilterNotSupported,
+					lazyerrors.Errorf("unique index %q with that partial filter is not supported by PostgreSQL backend", index.Name),
+				)
+			}
+
+			var err error
+			if indexes[i].PartialFilterClause, err = preparePartialFilterClause(index.PartialFilterExpression); err != nil {
+				return nil, lazyerrors.Error(err)
+			}
 		}
 
 		for j, key := range index.Key {
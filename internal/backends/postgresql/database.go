@@ -75,10 +75,13 @@ func (db *database) ListCollections(ctx context.Context, params *backends.ListCo
 
 	for i, c := range list {
 		res[i] = backends.CollectionInfo{
-			Name:            c.Name,
-			UUID:            c.UUID,
-			CappedSize:      c.CappedSize,
-			CappedDocuments: c.CappedDocuments,
+			Name:             c.Name,
+			UUID:             c.UUID,
+			CappedSize:       c.CappedSize,
+			CappedDocuments:  c.CappedDocuments,
+			Validator:        c.Validator,
+			ValidationLevel:  c.ValidationLevel,
+			ValidationAction: c.ValidationAction,
 		}
 	}
 
@@ -90,10 +93,13 @@ func (db *database) ListCollections(ctx context.Context, params *backends.ListCo
 // CreateCollection implements backends.Database interface.
 func (db *database) CreateCollection(ctx context.Context, params *backends.CreateCollectionParams) error {
 	created, err := db.r.CollectionCreate(ctx, &metadata.CollectionCreateParams{
-		DBName:          db.name,
-		Name:            params.Name,
-		CappedSize:      params.CappedSize,
-		CappedDocuments: params.CappedDocuments,
+		DBName:           db.name,
+		Name:             params.Name,
+		CappedSize:       params.CappedSize,
+		CappedDocuments:  params.CappedDocuments,
+		Validator:        params.Validator,
+		ValidationLevel:  params.ValidationLevel,
+		ValidationAction: params.ValidationAction,
 	})
 	if err != nil {
 		return lazyerrors.Error(err)
@@ -134,6 +140,22 @@ func (db *database) RenameCollection(ctx context.Context, params *backends.Renam
 		)
 	}
 
+	if params.DropTarget {
+		renamed, err := db.r.CollectionReplace(ctx, db.name, params.OldName, params.NewName)
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		if !renamed {
+			return backends.NewError(
+				backends.ErrorCodeCollectionDoesNotExist,
+				lazyerrors.Errorf("old database %q or collection %q does not exist", db.name, params.OldName),
+			)
+		}
+
+		return nil
+	}
+
 	c, err = db.r.CollectionGet(ctx, db.name, params.NewName)
 	if err != nil {
 		return lazyerrors.Error(err)
@@ -158,6 +180,26 @@ func (db *database) RenameCollection(ctx context.Context, params *backends.Renam
 	return nil
 }
 
+// ModifyCollection implements backends.Database interface.
+func (db *database) ModifyCollection(ctx context.Context, params *backends.ModifyCollectionParams) error {
+	modified, err := db.r.CollectionModify(ctx, &metadata.CollectionModifyParams{
+		DBName:           db.name,
+		Name:             params.Name,
+		Validator:        params.Validator,
+		ValidationLevel:  params.ValidationLevel,
+		ValidationAction: params.ValidationAction,
+	})
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	if !modified {
+		return backends.NewError(backends.ErrorCodeCollectionDoesNotExist, err)
+	}
+
+	return nil
+}
+
 // Stats implements backends.Database interface.
 func (db *database) Stats(ctx context.Context, params *backends.DatabaseStatsParams) (*backends.DatabaseStatsResult, error) {
 	if params == nil {
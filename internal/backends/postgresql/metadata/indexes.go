@@ -16,7 +16,11 @@ package metadata
 
 import (
 	"errors"
+	"fmt"
 	"slices"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
 
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/iterator"
@@ -33,6 +37,19 @@ type IndexInfo struct {
 	PgIndex string
 	Key     []IndexKeyPair
 	Unique  bool
+
+	// ExpireAfterSeconds is set for TTL indexes.
+	ExpireAfterSeconds *int32
+
+	// PartialFilterExpression is set for partial indexes.
+	PartialFilterExpression *types.Document
+
+	// PartialFilterClause is the predicate of partial index built from PartialFilterExpression.
+	// It is used only for index creation and is not stored.
+	PartialFilterClause string
+
+	// Collation is set for indexes with non-simple collation.
+	Collation *types.Collation
 }
 
 // IndexKeyPair consists of a field name and a sort order that are part of the index.
@@ -47,10 +64,16 @@ func (indexes Indexes) deepCopy() Indexes {
 
 	for i, index := range indexes {
 		res[i] = IndexInfo{
-			Name:    index.Name,
-			PgIndex: index.PgIndex,
-			Key:     slices.Clone(index.Key),
-			Unique:  index.Unique,
+			Name:               index.Name,
+			PgIndex:            index.PgIndex,
+			Key:                slices.Clone(index.Key),
+			Unique:             index.Unique,
+			ExpireAfterSeconds: index.ExpireAfterSeconds,
+			Collation:          index.Collation, // immutable
+		}
+
+		if index.PartialFilterExpression != nil {
+			res[i].PartialFilterExpression = index.PartialFilterExpression.DeepCopy()
 		}
 	}
 
@@ -73,12 +96,29 @@ func (indexes Indexes) marshal() *types.Array {
 			key.Set(pair.Field, order)
 		}
 
-		res.Append(must.NotFail(types.NewDocument(
+		doc := must.NotFail(types.NewDocument(
 			"pgindex", index.PgIndex,
 			"name", index.Name,
 			"key", key,
 			"unique", index.Unique,
-		)))
+		))
+
+		if index.ExpireAfterSeconds != nil {
+			doc.Set("expireAfterSeconds", *index.ExpireAfterSeconds)
+		}
+
+		if index.PartialFilterExpression != nil {
+			doc.Set("partialFilterExpression", index.PartialFilterExpression)
+		}
+
+		if index.Collation != nil {
+			doc.Set("collation", must.NotFail(types.NewDocument(
+				"locale", index.Collation.Locale(),
+				"strength", int32(index.Collation.Strength()),
+			)))
+		}
+
+		res.Append(doc)
 	}
 
 	return res
@@ -130,9 +170,60 @@ func (s *Indexes) unmarshal(a *types.Array) error {
 			Key:     key,
 			Unique:  unique,
 		}
+
+		if v, _ = index.Get("expireAfterSeconds"); v != nil {
+			expireAfterSeconds, ok := v.(int32)
+			if !ok {
+				return lazyerrors.Errorf("unexpected expireAfterSeconds type %T", v)
+			}
+
+			res[i].ExpireAfterSeconds = &expireAfterSeconds
+		}
+
+		if v, _ = index.Get("partialFilterExpression"); v != nil {
+			res[i].PartialFilterExpression = v.(*types.Document)
+		}
+
+		if v, _ = index.Get("collation"); v != nil {
+			c := v.(*types.Document)
+			locale := must.NotFail(c.Get("locale")).(string)
+			strength := must.NotFail(c.Get("strength")).(int32)
+
+			if res[i].Collation, err = types.NewCollation(locale, int(strength)); err != nil {
+				return lazyerrors.Error(err)
+			}
+		}
 	}
 
 	*s = res
 
 	return nil
 }
+
+// collationName returns the name of PostgreSQL ICU collation for the given non-simple collation.
+func collationName(c *types.Collation) string {
+	locale := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			return r
+		}
+
+		return '_'
+	}, strings.ToLower(c.Locale()))
+
+	return fmt.Sprintf("ferretdb_%s_%d", locale, c.Strength())
+}
+
+// createCollationQuery returns a query that creates PostgreSQL ICU collation
+// for the given non-simple collation in the given schema, if it does not exist yet.
+//
+// Collations are not deterministic, so strings that are equal for the given strength
+// are equal for unique indexes too.
+func createCollationQuery(schema string, c *types.Collation) string {
+	locale := fmt.Sprintf("%s-u-ks-level%d", strings.ReplaceAll(c.Locale(), "_", "-"), c.Strength())
+
+	return fmt.Sprintf(
+		`CREATE COLLATION IF NOT EXISTS %s (provider = icu, locale = %s, deterministic = false)`,
+		pgx.Identifier{schema, collationName(c)}.Sanitize(),
+		quoteString(locale),
+	)
+}
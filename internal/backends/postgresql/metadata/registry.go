@@ -35,6 +35,7 @@ import (
 	"github.com/FerretDB/FerretDB/internal/backends/postgresql/metadata/pool"
 	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
 	"github.com/FerretDB/FerretDB/internal/handler/sjson"
+	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
 	"github.com/FerretDB/FerretDB/internal/util/must"
 	"github.com/FerretDB/FerretDB/internal/util/state"
@@ -441,11 +442,14 @@ func (r *Registry) CollectionList(ctx context.Context, dbName string) ([]*Collec
 
 // CollectionCreateParams contains parameters for CollectionCreate.
 type CollectionCreateParams struct {
-	DBName          string
-	Name            string
-	CappedSize      int64
-	CappedDocuments int64
-	_               struct{} // prevent unkeyed literals
+	DBName           string
+	Name             string
+	CappedSize       int64
+	CappedDocuments  int64
+	Validator        *types.Document
+	ValidationLevel  string
+	ValidationAction string
+	_                struct{} // prevent unkeyed literals
 }
 
 // Capped returns true if capped collection creation is requested.
@@ -518,11 +522,14 @@ func (r *Registry) collectionCreate(ctx context.Context, p *pgxpool.Pool, params
 	}
 
 	c := &Collection{
-		Name:            collectionName,
-		UUID:            uuid.NewString(),
-		TableName:       tableName,
-		CappedSize:      params.CappedSize,
-		CappedDocuments: params.CappedDocuments,
+		Name:             collectionName,
+		UUID:             uuid.NewString(),
+		TableName:        tableName,
+		CappedSize:       params.CappedSize,
+		CappedDocuments:  params.CappedDocuments,
+		Validator:        params.Validator,
+		ValidationLevel:  params.ValidationLevel,
+		ValidationAction: params.ValidationAction,
 	}
 
 	q := fmt.Sprintf(`CREATE TABLE %s (`, pgx.Identifier{dbName, tableName}.Sanitize())
@@ -721,6 +728,159 @@ func (r *Registry) CollectionRename(ctx context.Context, dbName, oldCollectionNa
 	return true, nil
 }
 
+// CollectionReplace renames a collection in the database, replacing the existing collection with the new name.
+//
+// The existing collection is dropped, and the collection name is updated in a single transaction,
+// so the new name always refers to either the existing or the renamed collection.
+// If the transaction fails, both collections are kept as they were.
+//
+// Returned boolean value indicates whether the collection was renamed.
+// If database or old collection did not exist, (false, nil) is returned.
+//
+// If the user is not authenticated, it returns error.
+func (r *Registry) CollectionReplace(ctx context.Context, dbName, oldCollectionName, newCollectionName string) (bool, error) {
+	p, err := r.getPool(ctx)
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	db := r.colls[dbName]
+	if db == nil {
+		return false, nil
+	}
+
+	c := r.collectionGet(dbName, oldCollectionName)
+	if c == nil {
+		return false, nil
+	}
+
+	target := r.collectionGet(dbName, newCollectionName)
+
+	c.Name = newCollectionName
+
+	b, err := sjson.Marshal(c.marshal())
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	oldArg, err := sjson.MarshalSingleValue(oldCollectionName)
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	newArg, err := sjson.MarshalSingleValue(newCollectionName)
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	err = pool.InTransaction(ctx, p, func(tx pgx.Tx) error {
+		if target != nil {
+			q := fmt.Sprintf(
+				`DROP TABLE %s CASCADE`,
+				pgx.Identifier{dbName, target.TableName}.Sanitize(),
+			)
+
+			if _, err = tx.Exec(ctx, q); err != nil {
+				return lazyerrors.Error(err)
+			}
+
+			q = fmt.Sprintf(
+				`DELETE FROM %s WHERE %s = $1`,
+				pgx.Identifier{dbName, metadataTableName}.Sanitize(),
+				IDColumn,
+			)
+
+			if _, err = tx.Exec(ctx, q, newArg); err != nil {
+				return lazyerrors.Error(err)
+			}
+		}
+
+		q := fmt.Sprintf(
+			`UPDATE %s SET %s = $1 WHERE %s = $2`,
+			pgx.Identifier{dbName, metadataTableName}.Sanitize(),
+			DefaultColumn,
+			IDColumn,
+		)
+
+		if _, err = tx.Exec(ctx, q, string(b), oldArg); err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	r.colls[dbName][newCollectionName] = c
+	delete(r.colls[dbName], oldCollectionName)
+
+	return true, nil
+}
+
+// CollectionModifyParams contains parameters for CollectionModify.
+type CollectionModifyParams struct {
+	DBName           string
+	Name             string
+	Validator        *types.Document
+	ValidationLevel  string
+	ValidationAction string
+	_                struct{} // prevent unkeyed literals
+}
+
+// CollectionModify replaces validation settings of the collection.
+//
+// Returned boolean value indicates whether the collection was modified.
+// If database or collection did not exist, (false, nil) is returned.
+//
+// If the user is not authenticated, it returns error.
+func (r *Registry) CollectionModify(ctx context.Context, params *CollectionModifyParams) (bool, error) {
+	p, err := r.getPool(ctx)
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	c := r.collectionGet(params.DBName, params.Name)
+	if c == nil {
+		return false, nil
+	}
+
+	c.Validator = params.Validator
+	c.ValidationLevel = params.ValidationLevel
+	c.ValidationAction = params.ValidationAction
+
+	b, err := sjson.Marshal(c.marshal())
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	arg, err := sjson.MarshalSingleValue(params.Name)
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	q := fmt.Sprintf(
+		`UPDATE %s SET %s = $1 WHERE %s = $2`,
+		pgx.Identifier{params.DBName, metadataTableName}.Sanitize(),
+		DefaultColumn,
+		IDColumn,
+	)
+
+	if _, err := p.Exec(ctx, q, string(b), arg); err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	r.colls[params.DBName][params.Name] = c
+
+	return true, nil
+}
+
 // IndexesCreate creates indexes in the collection.
 //
 // Existing indexes with given names are ignored.
@@ -832,11 +992,35 @@ func (r *Registry) indexesCreate(ctx context.Context, p *pgxpool.Pool, dbName, c
 			}
 
 			columns[i] = fmt.Sprintf("((%s->%s))", DefaultColumn, strings.Join(transformedParts, " -> "))
+
+			// strings are compared as text with ICU collation;
+			// the JSON type is indexed too, so strings are not equal to other values with the same text
+			if index.Collation != nil {
+				path := strings.Join(transformedParts[:len(fs)-1], " -> ")
+				if path != "" {
+					path = "->" + path
+				}
+
+				columns[i] = fmt.Sprintf(
+					"(jsonb_typeof(%[1]s->%[2]s)), ((%[1]s%[3]s->>%[4]s) COLLATE %[5]s)",
+					DefaultColumn, strings.Join(transformedParts, " -> "),
+					path, transformedParts[len(fs)-1],
+					pgx.Identifier{dbName, collationName(index.Collation)}.Sanitize(),
+				)
+			}
+
 			if key.Descending {
 				columns[i] += " DESC"
 			}
 		}
 
+		if index.Collation != nil {
+			if _, err = p.Exec(ctx, createCollationQuery(dbName, index.Collation)); err != nil {
+				_ = r.indexesDrop(ctx, p, dbName, collectionName, created)
+				return lazyerrors.Error(err)
+			}
+		}
+
 		q = fmt.Sprintf(
 			q,
 			pgx.Identifier{index.PgIndex}.Sanitize(),
@@ -844,6 +1028,10 @@ func (r *Registry) indexesCreate(ctx context.Context, p *pgxpool.Pool, dbName, c
 			strings.Join(columns, ", "),
 		)
 
+		if index.PartialFilterClause != "" {
+			q += " WHERE " + index.PartialFilterClause
+		}
+
 		if _, err = p.Exec(ctx, q); err != nil {
 			_ = r.indexesDrop(ctx, p, dbName, collectionName, created)
 			return lazyerrors.Error(err)
@@ -15,13 +15,18 @@
 package postgresql
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
 
+	"github.com/FerretDB/FerretDB/internal/backends"
 	"github.com/FerretDB/FerretDB/internal/backends/postgresql/metadata"
 	"github.com/FerretDB/FerretDB/internal/handler/sjson"
 	"github.com/FerretDB/FerretDB/internal/types"
@@ -103,13 +108,6 @@ func prepareWhereClause(p *metadata.Placeholder, sqlFilters *types.Document) (st
 			return "", nil, lazyerrors.Error(err)
 		}
 
-		keyOperator := "->" // keyOperator is the operator that is used to access the field. (->/#>)
-
-		// key can be either a string '"v"' or PostgreSQL path '{v,foo}'.
-		// We use path type only for dot notation due to simplicity of SQL queries, and the fact
-		// that path doesn't handle empty keys.
-		var key any = rootKey
-
 		// don't pushdown $comment, as it's attached to query with select clause
 		//
 		// all of the other top-level operators such as `$or` do not support pushdown yet
@@ -117,24 +115,9 @@ func prepareWhereClause(p *metadata.Placeholder, sqlFilters *types.Document) (st
 			continue
 		}
 
-		path, err := types.NewPathFromString(rootKey)
-
-		var pe *types.PathError
-
-		switch {
-		case err == nil:
-			if path.Len() > 1 {
-				keyOperator = "#>"
-				key = path.Slice() // '{v,foo}'
-			}
-
-		case errors.As(err, &pe):
-			// ignore empty key error, otherwise return error
-			if pe.Code() != types.ErrPathElementEmpty {
-				return "", nil, lazyerrors.Error(err)
-			}
-		default:
-			panic("Invalid error type: PathError expected")
+		key, keyOperator, err := fieldKey(rootKey)
+		if err != nil {
+			return "", nil, lazyerrors.Error(err)
 		}
 
 		switch v := rootVal.(type) {
@@ -160,6 +143,12 @@ func prepareWhereClause(p *metadata.Placeholder, sqlFilters *types.Document) (st
 						args = append(args, a...)
 					}
 
+				case "$in":
+					if f, a := filterIn(p, key, v, keyOperator); f != "" {
+						filters = append(filters, f)
+						args = append(args, a...)
+					}
+
 				case "$ne":
 					sql := `NOT ( ` +
 						// does document contain the key,
@@ -172,7 +161,8 @@ func prepareWhereClause(p *metadata.Placeholder, sqlFilters *types.Document) (st
 
 					switch v := v.(type) {
 					case *types.Document, *types.Array, types.Binary,
-						types.NullType, types.Regex, types.Timestamp:
+						types.NullType, types.Regex, types.Timestamp,
+						types.Decimal128, types.MinKeyType, types.MaxKeyType:
 						// type not supported for pushdown
 
 					case float64, bool, int32, int64:
@@ -205,14 +195,36 @@ func prepareWhereClause(p *metadata.Placeholder, sqlFilters *types.Document) (st
 						panic(fmt.Sprintf("Unexpected type of value: %v", v))
 					}
 
+				case "$gt", "$lt":
+					// only dates and timestamps of top-level fields are supported
+					if keyOperator != "->" {
+						continue
+					}
+
+					switch v.(type) {
+					case time.Time, types.Timestamp:
+					default:
+						continue
+					}
+
+					if f, a := filterCompare(p, key, keyOperator, v, k); f != "" {
+						// arrays are selected, as their elements could match
+						filters = append(filters, fmt.Sprintf(
+							`(%s OR jsonb_typeof(%s->%s) = 'array')`, f, metadata.DefaultColumn, p.Next(),
+						))
+						args = append(args, a...)
+						args = append(args, rootKey)
+					}
+
 				default:
-					// $gt and $lt
+					// $gte and $lte
 					// TODO https://github.com/FerretDB/FerretDB/issues/1875
 					continue
 				}
 			}
 
-		case *types.Array, types.Binary, types.NullType, types.Regex, types.Timestamp:
+		case *types.Array, types.Binary, types.NullType, types.Regex, types.Timestamp,
+			types.Decimal128, types.MinKeyType, types.MaxKeyType:
 			// type not supported for pushdown
 
 		case float64, string, types.ObjectID, bool, time.Time, int32, int64:
@@ -234,6 +246,259 @@ func prepareWhereClause(p *metadata.Placeholder, sqlFilters *types.Document) (st
 	return filter, args, nil
 }
 
+// preparePartialFilterClause returns the predicate of partial index for the given filter expression.
+//
+// It is built with the same filters as query pushdown, but arguments are inlined as literals,
+// as index predicate can't use placeholders.
+// Unlike pushdown, unsupported expressions are not skipped, but return an error.
+// Only field equality, $eq, $gt, $gte, $lt, $lte, `$exists: true` and $and are supported;
+// only equality matches arrays containing the given value.
+// See exactPartialFilter.
+func preparePartialFilterClause(filter *types.Document) (string, error) {
+	p := new(metadata.Placeholder)
+
+	filters, args, err := partialFilters(p, filter)
+	if err != nil {
+		return "", lazyerrors.Error(err)
+	}
+
+	return inlineArgs(strings.Join(filters, " AND "), args)
+}
+
+// exactPartialFilter returns true if the predicate built by preparePartialFilterClause
+// matches exactly the same documents as the given filter expression.
+//
+// Only boolean equality and `$exists: true` for top-level fields (combined with $and) are exact.
+// Comparisons do not match arrays, paths do not go through arrays, and array elements of other types
+// could have the same JSON representation (like dates and numbers), so unique indexes can't use them.
+func exactPartialFilter(filter *types.Document) bool {
+	for _, k := range filter.Keys() {
+		v := must.NotFail(filter.Get(k))
+
+		if k == "$and" {
+			arr, ok := v.(*types.Array)
+			if !ok {
+				return false
+			}
+
+			for i := 0; i < arr.Len(); i++ {
+				doc, ok := must.NotFail(arr.Get(i)).(*types.Document)
+				if !ok || !exactPartialFilter(doc) {
+					return false
+				}
+			}
+
+			continue
+		}
+
+		if strings.Contains(k, ".") {
+			return false
+		}
+
+		ops, ok := v.(*types.Document)
+		if !ok {
+			ops = must.NotFail(types.NewDocument("$eq", v))
+		}
+
+		for _, op := range ops.Keys() {
+			switch opV := must.NotFail(ops.Get(op)); op {
+			case "$eq":
+				if _, ok = opV.(bool); !ok {
+					return false
+				}
+
+			case "$exists":
+				if opV != true {
+					return false
+				}
+
+			default:
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// partialFilters returns SQL filters with arguments for the given partial filter expression.
+func partialFilters(p *metadata.Placeholder, filter *types.Document) (filters []string, args []any, err error) {
+	for _, k := range filter.Keys() {
+		v := must.NotFail(filter.Get(k))
+
+		if k == "$and" {
+			arr, ok := v.(*types.Array)
+			if !ok {
+				return nil, nil, lazyerrors.Errorf("unexpected $and value %s", types.FormatAnyValue(v))
+			}
+
+			for i := 0; i < arr.Len(); i++ {
+				doc, ok := must.NotFail(arr.Get(i)).(*types.Document)
+				if !ok {
+					return nil, nil, lazyerrors.Errorf("unexpected $and value %s", types.FormatAnyValue(v))
+				}
+
+				f, a, err := partialFilters(p, doc)
+				if err != nil {
+					return nil, nil, lazyerrors.Error(err)
+				}
+
+				filters = append(filters, "("+strings.Join(f, " AND ")+")")
+				args = append(args, a...)
+			}
+
+			continue
+		}
+
+		if strings.HasPrefix(k, "$") {
+			return nil, nil, lazyerrors.Errorf("unsupported partial filter expression %s", k)
+		}
+
+		key, keyOperator, err := fieldKey(k)
+		if err != nil {
+			return nil, nil, lazyerrors.Error(err)
+		}
+
+		ops, ok := v.(*types.Document)
+		if !ok {
+			ops = must.NotFail(types.NewDocument("$eq", v))
+		}
+
+		for _, op := range ops.Keys() {
+			var f string
+			var a []any
+
+			switch opV := must.NotFail(ops.Get(op)); op {
+			case "$eq":
+				f, a = partialFilterEqual(p, key, keyOperator, opV)
+
+			case "$exists":
+				if opV == true {
+					f, a = fmt.Sprintf(`%s%s%s IS NOT NULL`, metadata.DefaultColumn, keyOperator, p.Next()), []any{key}
+				}
+
+			default:
+				f, a = filterCompare(p, key, keyOperator, opV, op)
+			}
+
+			if f == "" {
+				return nil, nil, lazyerrors.Errorf(
+					"unsupported partial filter expression %s: %s", op, types.FormatAnyValue(must.NotFail(ops.Get(op))),
+				)
+			}
+
+			filters = append(filters, f)
+			args = append(args, a...)
+		}
+	}
+
+	return filters, args, nil
+}
+
+// partialFilterEqual returns SQL filter with arguments that selects documents
+// where the value under k is equal to v, or is an array containing v.
+//
+// Unlike filterEqual, it checks the value type using the document's schema instead of
+// selecting Decimal128 values, and does not support numbers that are not safe doubles.
+func partialFilterEqual(p *metadata.Placeholder, k any, operator string, v any) (filter string, args []any) {
+	var typs string
+
+	switch v := v.(type) {
+	case float64:
+		if v > types.MaxSafeDouble || v < -types.MaxSafeDouble {
+			return
+		}
+
+		typs = `'"double"', '"int"', '"long"'`
+
+	case int64:
+		if v > int64(types.MaxSafeDouble) || v < -int64(types.MaxSafeDouble) {
+			return
+		}
+
+		typs = `'"double"', '"int"', '"long"'`
+
+	case int32:
+		typs = `'"double"', '"int"', '"long"'`
+
+	case string, types.ObjectID, time.Time, bool:
+		typs = `'"` + sjson.GetTypeOfValue(v) + `"'`
+
+	default:
+		return
+	}
+
+	arg := v
+	switch v.(type) {
+	case string, types.ObjectID, time.Time:
+		arg = string(must.NotFail(sjson.MarshalSingleValue(v)))
+	}
+
+	key, value := p.Next(), p.Next()
+	typeExpr, typeArgs := filterType(p, k, operator)
+
+	filter = fmt.Sprintf(
+		`(%s%s%s @> %s AND %s IN (%s, '"array"'))`,
+		metadata.DefaultColumn, operator, key, value, typeExpr, typs,
+	)
+	args = append(args, k, arg)
+	args = append(args, typeArgs...)
+
+	return
+}
+
+// inlineArgs replaces placeholders in the given SQL with quoted literals of arguments.
+//
+// Literals are untyped, so PostgreSQL infers their types from the context, as it does for placeholders.
+func inlineArgs(sql string, args []any) (string, error) {
+	literals := make([]string, len(args))
+
+	for i, arg := range args {
+		var s string
+
+		switch arg := arg.(type) {
+		case string:
+			s = arg
+		case []string:
+			elems := make([]string, len(arg))
+			for j, e := range arg {
+				elems[j] = `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(e) + `"`
+			}
+
+			s = "{" + strings.Join(elems, ",") + "}"
+		case float64:
+			s = strconv.FormatFloat(arg, 'g', -1, 64)
+		case int32, int64, bool:
+			s = fmt.Sprint(arg)
+		default:
+			return "", lazyerrors.Errorf("unexpected argument type %T", arg)
+		}
+
+		literals[i] = "'" + strings.ReplaceAll(s, "'", "''") + "'"
+	}
+
+	var err error
+
+	res := placeholderRe.ReplaceAllStringFunc(sql, func(ph string) string {
+		n, _ := strconv.Atoi(ph[1:])
+		if n < 1 || n > len(literals) {
+			err = lazyerrors.Errorf("unexpected placeholder %s", ph)
+			return ph
+		}
+
+		return literals[n-1]
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	return res, nil
+}
+
+// placeholderRe matches placeholders produced by [metadata.Placeholder].
+var placeholderRe = regexp.MustCompile(`\$[0-9]+`)
+
 // prepareOrderByClause returns ORDER BY clause with arguments for given sort document.
 //
 // The provided sort document should be already validated.
@@ -258,6 +523,92 @@ func prepareOrderByClause(sort *types.Document) (string, []any) {
 	return fmt.Sprintf(" ORDER BY %s%s", metadata.RecordIDColumn, order), nil
 }
 
+// prepareGraphWhereClause returns WHERE clause that selects documents reachable by the given graph traversal
+// with recursive CTE, and its arguments. It returns empty string if the traversal could not be pushed down.
+//
+// Values are compared by their JSON representation which is coarser than BSON comparison
+// (for example, ObjectID and its hex string are equal), so extra documents could be selected.
+// Regular expressions in connectFromField match strings in MongoDB, so documents containing them
+// are connected to all documents. Decimal128 values are stored as JSON strings, so documents
+// containing them in connectFromField or connectToField are connected to all documents too.
+func prepareGraphWhereClause(p *metadata.Placeholder, schema, table string, graph *backends.GraphParams) (string, []any) {
+	if graph == nil {
+		return "", nil
+	}
+
+	start := make([]json.RawMessage, len(graph.StartWith))
+
+	for i, v := range graph.StartWith {
+		switch v.(type) {
+		case *types.Document, *types.Array, types.Regex, types.Decimal128:
+			// type not supported for pushdown
+			return "", nil
+		}
+
+		b, err := sjson.MarshalSingleValue(v)
+		if err != nil {
+			// NaN and infinite values could not be represented in JSON
+			return "", nil
+		}
+
+		start[i] = b
+	}
+
+	startP, fromP, toP := p.Next(), p.Next(), p.Next()
+	args := []any{string(must.NotFail(json.Marshal(start))), graph.ConnectFromField, graph.ConnectToField}
+
+	// is the value of connectToField equal to s.value, missing if s.value is null, or contains decimals
+	match := fmt.Sprintf(
+		`(t.%[1]s->%[2]s @> s.value OR (s.value = 'null' AND t.%[1]s->%[2]s IS NULL) OR `+
+			`t.%[1]s->'$s'->'p'->%[2]s->'t' = '"decimal"' OR t.%[1]s->'$s'->'p'->%[2]s->'i' @> '[{"t": "decimal"}]')`,
+		metadata.DefaultColumn, toP,
+	)
+
+	// does connectFromField contain regular expressions or decimals
+	wildcard := fmt.Sprintf(
+		`(t.%[1]s->'$s'->'p'->%[2]s->'t' IN ('"regex"', '"decimal"') OR `+
+			`t.%[1]s->'$s'->'p'->%[2]s->'i' @> '[{"t": "regex"}]' OR t.%[1]s->'$s'->'p'->%[2]s->'i' @> '[{"t": "decimal"}]')`,
+		metadata.DefaultColumn, fromP,
+	)
+
+	tableName := pgx.Identifier{schema, table}.Sanitize()
+
+	// without depth, UNION stops the recursion when no new documents are found;
+	// with depth, it is limited by the number of documents, as the same document could be found at many depths
+	var depth, depthStart, depthNext, depthWhere string
+
+	if graph.MaxDepth >= 0 {
+		depth, depthStart, depthNext = `, depth`, `, 0`, `, g.depth + 1`
+		depthWhere = fmt.Sprintf(` AND g.depth < LEAST(%s::bigint, (SELECT count(*) FROM %s))`, p.Next(), tableName)
+		args = append(args, graph.MaxDepth)
+	}
+
+	q := fmt.Sprintf(
+		` WHERE %[1]s->'_id' IN (`+
+			`WITH RECURSIVE graph (id, next, wildcard%[2]s) AS (`+
+			`SELECT t.%[1]s->'_id', t.%[1]s->%[6]s, %[8]s%[3]s `+
+			`FROM %[5]s t, jsonb_array_elements(%[7]s::jsonb) s (value) `+
+			`WHERE %[9]s `+
+			`UNION `+
+			`SELECT t.%[1]s->'_id', t.%[1]s->%[6]s, %[8]s%[4]s `+
+			`FROM graph g `+
+			`CROSS JOIN LATERAL jsonb_array_elements(`+
+			`CASE jsonb_typeof(g.next) WHEN 'array' THEN g.next ELSE jsonb_build_array(g.next) END`+
+			`) s (value) `+
+			`JOIN %[5]s t ON g.wildcard OR %[9]s `+
+			`WHERE g.next IS NOT NULL%[10]s`+
+			`) SELECT id FROM graph)`,
+		metadata.DefaultColumn,
+		depth, depthStart, depthNext,
+		tableName,
+		fromP, startP,
+		wildcard, match,
+		depthWhere,
+	)
+
+	return q, args
+}
+
 // filterEqual returns the proper SQL filter with arguments that filters documents
 // where the value under k is equal to v.
 func filterEqual(p *metadata.Placeholder, k any, v any, operator string) (filter string, args []any) {
@@ -266,7 +617,8 @@ func filterEqual(p *metadata.Placeholder, k any, v any, operator string) (filter
 
 	switch v := v.(type) {
 	case *types.Document, *types.Array, types.Binary,
-		types.NullType, types.Regex, types.Timestamp:
+		types.NullType, types.Regex, types.Timestamp,
+		types.Decimal128, types.MinKeyType, types.MaxKeyType:
 		// type not supported for pushdown
 
 	case float64:
@@ -287,6 +639,8 @@ func filterEqual(p *metadata.Placeholder, k any, v any, operator string) (filter
 		filter = fmt.Sprintf(sql, metadata.DefaultColumn, operator, p.Next(), p.Next())
 		args = append(args, k, v)
 
+		filter, args = filterNumber(p, k, operator, filter, args)
+
 	case string, types.ObjectID, time.Time:
 		// merge with the case below?
 		// TODO https://github.com/FerretDB/FerretDB/issues/3626
@@ -295,7 +649,7 @@ func filterEqual(p *metadata.Placeholder, k any, v any, operator string) (filter
 		filter = fmt.Sprintf(sql, metadata.DefaultColumn, operator, p.Next(), p.Next())
 		args = append(args, k, string(must.NotFail(sjson.MarshalSingleValue(v))))
 
-	case bool, int32:
+	case bool:
 		// merge with the case above?
 		// TODO https://github.com/FerretDB/FerretDB/issues/3626
 
@@ -303,6 +657,13 @@ func filterEqual(p *metadata.Placeholder, k any, v any, operator string) (filter
 		filter = fmt.Sprintf(sql, metadata.DefaultColumn, operator, p.Next(), p.Next())
 		args = append(args, k, v)
 
+	case int32:
+		// don't change the default eq query
+		filter = fmt.Sprintf(sql, metadata.DefaultColumn, operator, p.Next(), p.Next())
+		args = append(args, k, v)
+
+		filter, args = filterNumber(p, k, operator, filter, args)
+
 	case int64:
 		// TODO https://github.com/FerretDB/FerretDB/issues/3626
 		maxSafeDouble := int64(types.MaxSafeDouble)
@@ -323,9 +684,183 @@ func filterEqual(p *metadata.Placeholder, k any, v any, operator string) (filter
 		filter = fmt.Sprintf(sql, metadata.DefaultColumn, operator, p.Next(), p.Next())
 		args = append(args, k, v)
 
+		filter, args = filterNumber(p, k, operator, filter, args)
+
 	default:
 		panic(fmt.Sprintf("Unexpected type of value: %v", v))
 	}
 
 	return
 }
+
+// filterNumber extends the filter with arguments for a number to also select documents
+// where the value under k is Decimal128 or an array containing Decimal128.
+// Decimals are stored as JSON strings, so they could not be compared with numbers in SQL.
+func filterNumber(p *metadata.Placeholder, k any, operator string, filter string, args []any) (string, []any) {
+	schemaExpr, schemaArgs := filterSchema(p, k, operator)
+
+	filter = fmt.Sprintf(
+		`(%[1]s OR %[2]s @> '{"t":"decimal"}' OR %[2]s @> '{"i":[{"t":"decimal"}]}')`,
+		filter, schemaExpr,
+	)
+
+	return filter, append(args, schemaArgs...)
+}
+
+// filterCompare returns the proper SQL filter with arguments that filters documents
+// where the scalar value under k is greater than ($gt), greater than or equal to ($gte),
+// less than ($lt), or less than or equal to ($lte) v.
+//
+// Only numbers, dates, and timestamps are supported, as they are stored as JSON numbers
+// that could be compared directly; value types are checked using the document's schema.
+// Arrays are not selected.
+// It returns an empty filter for other values and operators.
+func filterCompare(p *metadata.Placeholder, k any, operator string, v any, op string) (filter string, args []any) {
+	var sqlOp string
+
+	switch op {
+	case "$gt":
+		sqlOp = ">"
+	case "$gte":
+		sqlOp = ">="
+	case "$lt":
+		sqlOp = "<"
+	case "$lte":
+		sqlOp = "<="
+	default:
+		return
+	}
+
+	var arg any
+	var typs string
+
+	switch v := v.(type) {
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return
+		}
+
+		arg = v
+		typs = `'"double"', '"int"', '"long"'`
+
+	case int32, int64:
+		arg = v
+		typs = `'"double"', '"int"', '"long"'`
+
+	case time.Time:
+		arg = v.UnixMilli()
+		typs = `'"date"'`
+
+	case types.Timestamp:
+		if arg = v.Signed(); v.Signed() < 0 {
+			return
+		}
+
+		typs = `'"timestamp"'`
+
+	default:
+		return
+	}
+
+	key, value := p.Next(), p.Next()
+	typeExpr, typeArgs := filterType(p, k, operator)
+
+	filter = fmt.Sprintf(
+		`(%[1]s%[2]s%[3]s %[4]s %[5]s AND %[6]s IN (%[7]s))`,
+		metadata.DefaultColumn, operator, key, sqlOp, value, typeExpr, typs,
+	)
+	args = append(args, k, arg)
+	args = append(args, typeArgs...)
+
+	return
+}
+
+// filterType returns the SQL expression with arguments for the type of the value under k
+// stored in the document's schema.
+func filterType(p *metadata.Placeholder, k any, operator string) (expr string, args []any) {
+	expr, args = filterSchema(p, k, operator)
+
+	if path, ok := args[0].([]string); ok {
+		args[0] = append(path, "t")
+		return expr, args
+	}
+
+	return expr + `->'t'`, args
+}
+
+// filterSchema returns the SQL expression with arguments for the schema element
+// of the value under k stored in the document's schema.
+func filterSchema(p *metadata.Placeholder, k any, operator string) (expr string, args []any) {
+	if path, ok := k.([]string); ok {
+		// the schema of a.b is stored under $s -> p -> a -> $s -> p -> b
+		schemaPath := make([]string, 0, len(path)*3)
+		for _, f := range path {
+			schemaPath = append(schemaPath, "$s", "p", f)
+		}
+
+		return fmt.Sprintf(`%s%s%s`, metadata.DefaultColumn, operator, p.Next()), []any{schemaPath}
+	}
+
+	return fmt.Sprintf(`%s->'$s'->'p'->%s`, metadata.DefaultColumn, p.Next()), []any{k}
+}
+
+// fieldKey returns the key and the operator (-> or #>) used to access the field with the given name.
+//
+// Key can be either a string '"v"' or PostgreSQL path '{v,foo}'.
+// We use path type only for dot notation due to simplicity of SQL queries, and the fact
+// that path doesn't handle empty keys.
+func fieldKey(name string) (key any, operator string, err error) {
+	path, err := types.NewPathFromString(name)
+
+	var pe *types.PathError
+
+	switch {
+	case err == nil:
+		if path.Len() > 1 {
+			return path.Slice(), "#>", nil
+		}
+
+	case errors.As(err, &pe):
+		// ignore empty key error, otherwise return error
+		if pe.Code() != types.ErrPathElementEmpty {
+			return nil, "", lazyerrors.Error(err)
+		}
+
+	default:
+		panic("Invalid error type: PathError expected")
+	}
+
+	return name, "->", nil
+}
+
+// filterIn returns the proper SQL filter with arguments that filters documents
+// where the value under k is equal to any element of the array v.
+//
+// It returns an empty filter if v is not an array, or any of its elements is not supported for pushdown.
+func filterIn(p *metadata.Placeholder, k any, v any, operator string) (filter string, args []any) {
+	arr, ok := v.(*types.Array)
+	if !ok || arr.Len() == 0 {
+		return
+	}
+
+	// check all elements first, as filterEqual takes placeholders
+	for i := 0; i < arr.Len(); i++ {
+		switch must.NotFail(arr.Get(i)).(type) {
+		case float64, string, types.ObjectID, bool, time.Time, int32, int64:
+		default:
+			return
+		}
+	}
+
+	filters := make([]string, arr.Len())
+
+	for i := range filters {
+		var a []any
+		filters[i], a = filterEqual(p, k, must.NotFail(arr.Get(i)), operator)
+		args = append(args, a...)
+	}
+
+	filter = `(` + strings.Join(filters, ` OR `) + `)`
+
+	return
+}
@@ -97,7 +97,10 @@ func TestPrepareWhereClause(t *testing.T) {
 	whereContain := " WHERE _jsonb->$1 @> $2"
 	whereContainDotNotation := " WHERE _jsonb#>$1 @> $2"
 
-	whereGt := " WHERE _jsonb->$1 > $2"
+	// numbers also select Decimal128 values stored as strings
+	whereDecimal := ` OR _jsonb->'$s'->'p'->$3 @> '{"t":"decimal"}' OR _jsonb->'$s'->'p'->$3 @> '{"i":[{"t":"decimal"}]}')`
+	whereContainNumber := " WHERE (_jsonb->$1 @> $2" + whereDecimal
+	whereGtNumber := " WHERE (_jsonb->$1 > $2" + whereDecimal
 	whereNotEq := ` WHERE NOT ( _jsonb ? $1 AND _jsonb->$1 @> $2 AND _jsonb->'$s'->'p'->$1->'t' = `
 
 	for name, tc := range map[string]struct {
@@ -142,19 +145,19 @@ func TestPrepareWhereClause(t *testing.T) {
 		},
 		"ImplicitInt32": {
 			filter:   must.NotFail(types.NewDocument("v", int32(42))),
-			expected: whereContain,
+			expected: whereContainNumber,
 		},
 		"ImplicitInt64": {
 			filter:   must.NotFail(types.NewDocument("v", int64(42))),
-			expected: whereContain,
+			expected: whereContainNumber,
 		},
 		"ImplicitFloat64": {
 			filter:   must.NotFail(types.NewDocument("v", float64(42.13))),
-			expected: whereContain,
+			expected: whereContainNumber,
 		},
 		"ImplicitMaxFloat64": {
 			filter:   must.NotFail(types.NewDocument("v", math.MaxFloat64)),
-			expected: whereGt,
+			expected: whereGtNumber,
 		},
 		"ImplicitBool": {
 			filter:   must.NotFail(types.NewDocument("v", true)),
@@ -188,34 +191,34 @@ func TestPrepareWhereClause(t *testing.T) {
 			filter: must.NotFail(types.NewDocument(
 				"v", must.NotFail(types.NewDocument("$eq", int32(42))),
 			)),
-			expected: whereContain,
+			expected: whereContainNumber,
 		},
 		"EqInt64": {
 			filter: must.NotFail(types.NewDocument(
 				"v", must.NotFail(types.NewDocument("$eq", int64(42))),
 			)),
-			expected: whereContain,
+			expected: whereContainNumber,
 		},
 		"EqFloat64": {
 			filter: must.NotFail(types.NewDocument(
 				"v", must.NotFail(types.NewDocument("$eq", float64(42.13))),
 			)),
-			expected: whereContain,
+			expected: whereContainNumber,
 		},
 		"EqMaxFloat64": {
 			filter: must.NotFail(types.NewDocument(
 				"v", must.NotFail(types.NewDocument("$eq", math.MaxFloat64)),
 			)),
-			args:     []any{`v`, types.MaxSafeDouble},
-			expected: whereGt,
+			args:     []any{`v`, types.MaxSafeDouble, `v`},
+			expected: whereGtNumber,
 		},
 		"EqDoubleBigInt64": {
 			filter: must.NotFail(types.NewDocument(
 				// TODO https://github.com/FerretDB/FerretDB/issues/3626
 				"v", must.NotFail(types.NewDocument("$eq", float64(2<<61))),
 			)),
-			args:     []any{`v`, types.MaxSafeDouble},
-			expected: whereGt,
+			args:     []any{`v`, types.MaxSafeDouble, `v`},
+			expected: whereGtNumber,
 		},
 		"EqBool": {
 			filter: must.NotFail(types.NewDocument(
@@ -296,6 +299,64 @@ func TestPrepareWhereClause(t *testing.T) {
 			expected: whereNotEq + `'"objectId"' )`,
 		},
 
+		"In": {
+			filter: must.NotFail(types.NewDocument(
+				"v", must.NotFail(types.NewDocument("$in", must.NotFail(types.NewArray("foo", int32(42))))),
+			)),
+			expected: " WHERE (_jsonb->$1 @> $2 OR (_jsonb->$3 @> $4 OR " +
+				`_jsonb->'$s'->'p'->$5 @> '{"t":"decimal"}' OR _jsonb->'$s'->'p'->$5 @> '{"i":[{"t":"decimal"}]}'))`,
+		},
+		"InDotNotation": {
+			filter: must.NotFail(types.NewDocument(
+				"v.doc", must.NotFail(types.NewDocument("$in", must.NotFail(types.NewArray("foo", objectID)))),
+			)),
+			expected: " WHERE (_jsonb#>$1 @> $2 OR _jsonb#>$3 @> $4)",
+		},
+		"InEmpty": {
+			filter: must.NotFail(types.NewDocument(
+				"v", must.NotFail(types.NewDocument("$in", must.NotFail(types.NewArray()))),
+			)),
+		},
+		"InUnsupported": {
+			filter: must.NotFail(types.NewDocument(
+				"v", must.NotFail(types.NewDocument("$in", must.NotFail(types.NewArray("foo", types.Null)))),
+			)),
+		},
+		"InNotArray": {
+			filter: must.NotFail(types.NewDocument(
+				"v", must.NotFail(types.NewDocument("$in", "foo")),
+			)),
+		},
+
+		"GtDatetime": {
+			filter: must.NotFail(types.NewDocument(
+				"v", must.NotFail(types.NewDocument(
+					"$gt", time.Date(2021, 11, 1, 10, 18, 42, 123000000, time.UTC),
+				)),
+			)),
+			expected: ` WHERE ((_jsonb->$1 > $2 AND _jsonb->'$s'->'p'->$3->'t' IN ('"date"')) OR ` +
+				`jsonb_typeof(_jsonb->$4) = 'array')`,
+			args: []any{"v", int64(1635761922123), "v", "v"},
+		},
+		"LtTimestamp": {
+			filter: must.NotFail(types.NewDocument(
+				"v", must.NotFail(types.NewDocument("$lt", types.Timestamp(42))),
+			)),
+			expected: ` WHERE ((_jsonb->$1 < $2 AND _jsonb->'$s'->'p'->$3->'t' IN ('"timestamp"')) OR ` +
+				`jsonb_typeof(_jsonb->$4) = 'array')`,
+			args: []any{"v", int64(42), "v", "v"},
+		},
+		"GtUnsupported": {
+			filter: must.NotFail(types.NewDocument(
+				"v", must.NotFail(types.NewDocument("$gt", int32(42))),
+			)),
+		},
+		"GtDotNotation": {
+			filter: must.NotFail(types.NewDocument(
+				"v.doc", must.NotFail(types.NewDocument("$gt", types.Timestamp(42))),
+			)),
+		},
+
 		"Comment": {
 			filter: must.NotFail(types.NewDocument("$comment", "I'm comment")),
 		},
@@ -378,3 +439,116 @@ func TestPrepareOrderByClause(t *testing.T) {
 		})
 	}
 }
+
+func TestPreparePartialFilterClause(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		filter   *types.Document
+		expected string
+		err      bool
+	}{
+		"Equality": {
+			filter: must.NotFail(types.NewDocument("v", "it's")),
+			expected: `(_jsonb->'v' @> '"it''s"' AND ` +
+				`_jsonb->'$s'->'p'->'v'->'t' IN ('"string"', '"array"'))`,
+		},
+		"EqualityDotNotation": {
+			filter: must.NotFail(types.NewDocument("v.f\"oo", int32(42))),
+			expected: `(_jsonb#>'{"v","f\"oo"}' @> '42' AND ` +
+				`_jsonb#>'{"$s","p","v","$s","p","f\"oo","t"}' IN ('"double"', '"int"', '"long"', '"array"'))`,
+		},
+		"Comparison": {
+			filter: must.NotFail(types.NewDocument(
+				"active", true,
+				"age", must.NotFail(types.NewDocument("$gte", int32(18))),
+			)),
+			expected: `(_jsonb->'active' @> 'true' AND ` +
+				`_jsonb->'$s'->'p'->'active'->'t' IN ('"bool"', '"array"')) AND ` +
+				`(_jsonb->'age' >= '18' AND _jsonb->'$s'->'p'->'age'->'t' IN ('"double"', '"int"', '"long"'))`,
+		},
+		"And": {
+			filter: must.NotFail(types.NewDocument(
+				"$and", must.NotFail(types.NewArray(
+					must.NotFail(types.NewDocument("v", must.NotFail(types.NewDocument("$exists", true)))),
+					must.NotFail(types.NewDocument("v", must.NotFail(types.NewDocument("$lt", 42.5)))),
+				)),
+			)),
+			expected: `(_jsonb->'v' IS NOT NULL) AND ` +
+				`((_jsonb->'v' < '42.5' AND _jsonb->'$s'->'p'->'v'->'t' IN ('"double"', '"int"', '"long"')))`,
+		},
+		"ExistsFalse": {
+			filter: must.NotFail(types.NewDocument("v", must.NotFail(types.NewDocument("$exists", false)))),
+			err:    true,
+		},
+		"CompareString": {
+			filter: must.NotFail(types.NewDocument("v", must.NotFail(types.NewDocument("$gt", "foo")))),
+			err:    true,
+		},
+		"UnsafeNumber": {
+			filter: must.NotFail(types.NewDocument("v", math.MaxFloat64)),
+			err:    true,
+		},
+		"Or": {
+			filter: must.NotFail(types.NewDocument("$or", must.NotFail(types.NewArray()))),
+			err:    true,
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := preparePartialFilterClause(tc.filter)
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestExactPartialFilter(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		filter   *types.Document
+		expected bool
+	}{
+		"EqualityBool": {
+			filter:   must.NotFail(types.NewDocument("active", true)),
+			expected: true,
+		},
+		"EqBool": {
+			filter:   must.NotFail(types.NewDocument("active", must.NotFail(types.NewDocument("$eq", false)))),
+			expected: true,
+		},
+		"AndExists": {
+			filter: must.NotFail(types.NewDocument(
+				"$and", must.NotFail(types.NewArray(
+					must.NotFail(types.NewDocument("v", must.NotFail(types.NewDocument("$exists", true)))),
+					must.NotFail(types.NewDocument("active", true)),
+				)),
+			)),
+			expected: true,
+		},
+		"EqualityNumber": {
+			filter: must.NotFail(types.NewDocument("v", int32(42))),
+		},
+		"EqualityDotNotation": {
+			filter: must.NotFail(types.NewDocument("v.active", true)),
+		},
+		"Comparison": {
+			filter: must.NotFail(types.NewDocument("v", must.NotFail(types.NewDocument("$gte", int32(18))))),
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, exactPartialFilter(tc.filter))
+		})
+	}
+}
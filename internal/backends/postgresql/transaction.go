@@ -0,0 +1,144 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgresql
+
+import (
+	"context"
+	"sync"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/backends/postgresql/metadata/pool"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+)
+
+// transaction implements backends.Transaction interface.
+//
+// PostgreSQL transactions are started lazily on the first use of each connection pool
+// (there is a separate pool for each PostgreSQL user).
+type transaction struct {
+	m   sync.Mutex
+	txs map[*pgxpool.Pool]pgx.Tx
+}
+
+// newTransaction creates a new transaction.
+func newTransaction() *transaction {
+	return &transaction{
+		txs: map[*pgxpool.Pool]pgx.Tx{},
+	}
+}
+
+// tx returns PostgreSQL transaction for the given pool, starting it if needed.
+func (t *transaction) tx(ctx context.Context, p *pgxpool.Pool) (pgx.Tx, error) {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	if tx := t.txs[p]; tx != nil {
+		return tx, nil
+	}
+
+	// transaction outlives the context of the command that started it;
+	// MongoDB transactions use snapshot isolation
+	tx, err := p.BeginTx(context.WithoutCancel(ctx), pgx.TxOptions{IsoLevel: pgx.RepeatableRead})
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	t.txs[p] = tx
+
+	return tx, nil
+}
+
+// Commit implements backends.Transaction interface.
+func (t *transaction) Commit(ctx context.Context) error {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	var res error
+
+	for p, tx := range t.txs {
+		if res != nil {
+			_ = tx.Rollback(ctx)
+		} else if err := tx.Commit(ctx); err != nil {
+			res = lazyerrors.Error(err)
+		}
+
+		delete(t.txs, p)
+	}
+
+	return res
+}
+
+// Abort implements backends.Transaction interface.
+func (t *transaction) Abort(ctx context.Context) error {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	var res error
+
+	for p, tx := range t.txs {
+		if err := tx.Rollback(ctx); err != nil && res == nil {
+			res = lazyerrors.Error(err)
+		}
+
+		delete(t.txs, p)
+	}
+
+	return res
+}
+
+// querier represents methods common for *pgxpool.Pool and pgx.Tx.
+type querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// getQuerier returns the transaction from the context for the given pool,
+// or the pool itself if there is no transaction.
+func getQuerier(ctx context.Context, p *pgxpool.Pool) (querier, error) {
+	t, _ := backends.GetTransaction(ctx).(*transaction)
+	if t == nil {
+		return p, nil
+	}
+
+	return t.tx(ctx, p)
+}
+
+// inTransaction calls f with the transaction from the context for the given pool.
+// If there is no transaction, f is wrapped in a new one using pool p.
+func inTransaction(ctx context.Context, p *pgxpool.Pool, f func(tx pgx.Tx) error) error {
+	t, _ := backends.GetTransaction(ctx).(*transaction)
+	if t == nil {
+		return pool.InTransaction(ctx, p, f)
+	}
+
+	tx, err := t.tx(ctx, p)
+	if err != nil {
+		return err
+	}
+
+	return f(tx)
+}
+
+// check interfaces
+var (
+	_ backends.Transaction = (*transaction)(nil)
+	_ querier              = (*pgxpool.Pool)(nil)
+	_ querier              = (pgx.Tx)(nil)
+)
@@ -138,6 +138,18 @@ func (b *backend) DropDatabase(ctx context.Context, params *backends.DropDatabas
 	return nil
 }
 
+// BeginTransaction implements backends.Backend interface.
+//
+// Transactions are not supported for in-memory databases
+// because they use a single connection that is shared by all clients.
+func (b *backend) BeginTransaction(ctx context.Context, params *backends.BeginTransactionParams) (backends.Transaction, error) {
+	if b.r.InMemory() {
+		return nil, backends.NewError(backends.ErrorCodeTransactionsNotSupported, nil)
+	}
+
+	return newTransaction(), nil
+}
+
 // Describe implements prometheus.Collector.
 func (b *backend) Describe(ch chan<- *prometheus.Desc) {
 	b.r.Describe(ch)
@@ -67,19 +67,7 @@ func (c *collection) Query(ctx context.Context, params *backends.QueryParams) (*
 
 	q := prepareSelectClause(meta.TableName, params.Comment, meta.Capped(), params.OnlyRecordIDs)
 
-	var whereClause string
-	var args []any
-
-	// that logic should exist in one place
-	// TODO https://github.com/FerretDB/FerretDB/issues/3235
-	if params.Filter.Len() == 1 {
-		v, _ := params.Filter.Get("_id")
-		switch v.(type) {
-		case string, types.ObjectID:
-			whereClause = fmt.Sprintf(` WHERE %s = ?`, metadata.IDColumn)
-			args = []any{string(must.NotFail(sjson.MarshalSingleValue(v)))}
-		}
-	}
+	whereClause, args := prepareWhereClause(params.Filter)
 
 	q += whereClause
 	q += prepareOrderByClause(params.Sort)
@@ -89,7 +77,17 @@ func (c *collection) Query(ctx context.Context, params *backends.QueryParams) (*
 		args = append(args, params.Limit)
 	}
 
-	rows, err := db.QueryContext(ctx, q, args...)
+	if params.Sample != 0 {
+		q += ` ORDER BY random() LIMIT ?`
+		args = append(args, params.Sample)
+	}
+
+	qr, err := getQuerier(ctx, db)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	rows, err := qr.QueryContext(ctx, q, args...)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
@@ -108,7 +106,7 @@ func (c *collection) InsertAll(ctx context.Context, params *backends.InsertAllPa
 	db := c.r.DatabaseGetExisting(ctx, c.dbName)
 	meta := c.r.CollectionGet(ctx, c.dbName, c.name)
 
-	err := db.InTransaction(ctx, func(tx *fsql.Tx) error {
+	err := inTransaction(ctx, db, func(tx *fsql.Tx) error {
 		batchSize := c.r.BatchSize
 		if batchSize < 1 {
 			panic("batch-size should be greater or equal to 1")
@@ -160,7 +158,7 @@ func (c *collection) UpdateAll(ctx context.Context, params *backends.UpdateAllPa
 
 	q := fmt.Sprintf(`UPDATE %q SET %s = ? WHERE %s = ?`, meta.TableName, metadata.DefaultColumn, metadata.IDColumn)
 
-	err := db.InTransaction(ctx, func(tx *fsql.Tx) error {
+	err := inTransaction(ctx, db, func(tx *fsql.Tx) error {
 		for _, doc := range params.Docs {
 			b, err := sjson.Marshal(doc)
 			if err != nil {
@@ -236,7 +234,12 @@ func (c *collection) DeleteAll(ctx context.Context, params *backends.DeleteAllPa
 
 	q := fmt.Sprintf(`DELETE FROM %q WHERE %s IN (%s)`, meta.TableName, column, strings.Join(placeholders, ", "))
 
-	res, err := db.ExecContext(ctx, q, args...)
+	qr, err := getQuerier(ctx, db)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	res, err := qr.ExecContext(ctx, q, args...)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
@@ -269,21 +272,8 @@ func (c *collection) Explain(ctx context.Context, params *backends.ExplainParams
 
 	selectClause := prepareSelectClause(meta.TableName, "", meta.Capped(), false)
 
-	var filterPushdown bool
-	var whereClause string
-	var args []any
-
-	// that logic should exist in one place
-	// TODO https://github.com/FerretDB/FerretDB/issues/3235
-	if params.Filter.Len() == 1 {
-		v, _ := params.Filter.Get("_id")
-		switch v.(type) {
-		case string, types.ObjectID:
-			filterPushdown = true
-			whereClause = fmt.Sprintf(` WHERE %s = ?`, metadata.IDColumn)
-			args = []any{string(must.NotFail(sjson.MarshalSingleValue(v)))}
-		}
-	}
+	whereClause, args := prepareWhereClause(params.Filter)
+	filterPushdown := whereClause != ""
 
 	orderByClause := prepareOrderByClause(params.Sort)
 	sortPushdown := orderByClause != ""
@@ -475,9 +465,11 @@ func (c *collection) ListIndexes(ctx context.Context, params *backends.ListIndex
 
 	for i, index := range coll.Settings.Indexes {
 		res.Indexes[i] = backends.IndexInfo{
-			Name:   index.Name,
-			Unique: index.Unique,
-			Key:    make([]backends.IndexKeyPair, len(index.Key)),
+			Name:                    index.Name,
+			Unique:                  index.Unique,
+			Key:                     make([]backends.IndexKeyPair, len(index.Key)),
+			ExpireAfterSeconds:      index.ExpireAfterSeconds,
+			PartialFilterExpression: index.PartialFilterExpression,
 		}
 
 		for j, key := range index.Key {
@@ -499,10 +491,34 @@ func (c *collection) ListIndexes(ctx context.Context, params *backends.ListIndex
 func (c *collection) CreateIndexes(ctx context.Context, params *backends.CreateIndexesParams) (*backends.CreateIndexesResult, error) { //nolint:lll // for readability
 	indexes := make([]metadata.IndexInfo, len(params.Indexes))
 	for i, index := range params.Indexes {
+		// SQLite has no locale-aware collations
+		if index.Collation != nil {
+			return nil, backends.NewError(
+				backends.ErrorCodeIndexCollationNotSupported,
+				lazyerrors.Errorf("index %q with collation is not supported by SQLite backend", index.Name),
+			)
+		}
+
 		indexes[i] = metadata.IndexInfo{
-			Name:   index.Name,
-			Key:    make([]metadata.IndexKeyPair, len(index.Key)),
-			Unique: index.Unique,
+			Name:                    index.Name,
+			Key:                     make([]metadata.IndexKeyPair, len(index.Key)),
+			Unique:                  index.Unique,
+			ExpireAfterSeconds:      index.ExpireAfterSeconds,
+			PartialFilterExpression: index.PartialFilterExpression,
+		}
+
+		if index.PartialFilterExpression != nil {
+			if index.Unique && !exactPartialFilter(index.PartialFilterExpression) {
+				return nil, backends.NewError(
+					backends.ErrorCodeIndexPartialFilterNotSupported,
+					lazyerrors.Errorf("unique index %q with that partial filter is not supported by SQLite backend", index.Name),
+				)
+			}
+
+			var err error
+			if indexes[i].PartialFilterClause, err = preparePartialFilterClause(index.PartialFilterExpression); err != nil {
+				return nil, lazyerrors.Error(err)
+			}
 		}
 
 		for j, key := range index.Key {
@@ -85,3 +85,52 @@ func TestCappedCollectionInsertAllQueryExplain(t *testing.T) {
 		assert.True(t, explainRes.SortPushdown)
 	})
 }
+
+func TestQueryCompare(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Ctx(t)
+
+	sp, err := state.NewProvider("")
+	require.NoError(t, err)
+
+	b, err := NewBackend(&NewBackendParams{URI: testutil.TestSQLiteURI(t, ""), L: testutil.Logger(t), P: sp, BatchSize: 100})
+	require.NoError(t, err)
+	t.Cleanup(b.Close)
+
+	db, err := b.Database(testutil.DatabaseName(t))
+	require.NoError(t, err)
+
+	coll, err := db.Collection(testutil.CollectionName(t))
+	require.NoError(t, err)
+
+	insertDocs := []*types.Document{
+		must.NotFail(types.NewDocument("_id", "ts1", "v", types.Timestamp(1))),
+		must.NotFail(types.NewDocument("_id", "ts2", "v", types.Timestamp(2))),
+		must.NotFail(types.NewDocument("_id", "int", "v", int64(3))),
+		must.NotFail(types.NewDocument("_id", "array", "v", must.NotFail(types.NewArray(types.Timestamp(0))))),
+		must.NotFail(types.NewDocument("_id", "missing")),
+	}
+
+	_, err = coll.InsertAll(ctx, &backends.InsertAllParams{Docs: insertDocs})
+	require.NoError(t, err)
+
+	filter := must.NotFail(types.NewDocument("v", must.NotFail(types.NewDocument("$gt", types.Timestamp(1)))))
+
+	queryRes, err := coll.Query(ctx, &backends.QueryParams{Filter: filter})
+	require.NoError(t, err)
+
+	docs, err := iterator.ConsumeValues[struct{}, *types.Document](queryRes.Iter)
+	require.NoError(t, err)
+
+	var ids []any
+	for _, doc := range docs {
+		ids = append(ids, must.NotFail(doc.Get("_id")))
+	}
+
+	assert.ElementsMatch(t, []any{"ts2", "array"}, ids)
+
+	explainRes, err := coll.Explain(ctx, &backends.ExplainParams{Filter: filter})
+	require.NoError(t, err)
+	assert.True(t, explainRes.FilterPushdown)
+}
@@ -117,6 +117,11 @@ func (p *Pool) memory() bool {
 	return p.uri.Query().Get("mode") == "memory"
 }
 
+// InMemory returns true if the pool is for the in-memory database.
+func (p *Pool) InMemory() bool {
+	return p.memory()
+}
+
 // databaseName returns database name for given database file path.
 func (p *Pool) databaseName(databaseFile string) string {
 	if p.memory() {
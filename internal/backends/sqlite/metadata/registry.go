@@ -30,6 +30,7 @@ import (
 
 	"github.com/FerretDB/FerretDB/internal/backends"
 	"github.com/FerretDB/FerretDB/internal/backends/sqlite/metadata/pool"
+	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/fsql"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
 	"github.com/FerretDB/FerretDB/internal/util/must"
@@ -130,6 +131,11 @@ func (r *Registry) DatabaseList(ctx context.Context) []string {
 	return r.p.List(ctx)
 }
 
+// InMemory returns true if the registry is for in-memory databases.
+func (r *Registry) InMemory() bool {
+	return r.p.InMemory()
+}
+
 // DatabaseGetExisting returns a connection to existing database or nil if it doesn't exist.
 func (r *Registry) DatabaseGetExisting(ctx context.Context, dbName string) *fsql.DB {
 	return r.p.GetExisting(ctx, dbName)
@@ -217,11 +223,14 @@ func (r *Registry) CollectionList(ctx context.Context, dbName string) ([]*Collec
 
 // CollectionCreateParams contains parameters for CollectionCreate.
 type CollectionCreateParams struct {
-	DBName          string
-	Name            string
-	CappedSize      int64
-	CappedDocuments int64
-	_               struct{} // prevent unkeyed literals
+	DBName           string
+	Name             string
+	CappedSize       int64
+	CappedDocuments  int64
+	Validator        *types.Document
+	ValidationLevel  string
+	ValidationAction string
+	_                struct{} // prevent unkeyed literals
 }
 
 // Capped returns true if capped collection creation is requested.
@@ -307,9 +316,12 @@ func (r *Registry) collectionCreate(ctx context.Context, params *CollectionCreat
 		Name:      collectionName,
 		TableName: tableName,
 		Settings: Settings{
-			UUID:            uuid.NewString(),
-			CappedSize:      params.CappedSize,
-			CappedDocuments: params.CappedDocuments,
+			UUID:             uuid.NewString(),
+			CappedSize:       params.CappedSize,
+			CappedDocuments:  params.CappedDocuments,
+			Validator:        params.Validator,
+			ValidationLevel:  params.ValidationLevel,
+			ValidationAction: params.ValidationAction,
 		},
 	}
 
@@ -427,6 +439,103 @@ func (r *Registry) CollectionRename(ctx context.Context, dbName, oldCollectionNa
 	return true, nil
 }
 
+// CollectionReplace renames a collection in the database, replacing the existing collection with the new name.
+//
+// The existing collection is dropped, and the collection name is updated in a single transaction,
+// so the new name always refers to either the existing or the renamed collection.
+// If the transaction fails, both collections are kept as they were.
+//
+// Returned boolean value indicates whether the collection was renamed.
+// If database or old collection did not exist, (false, nil) is returned.
+func (r *Registry) CollectionReplace(ctx context.Context, dbName, oldCollectionName, newCollectionName string) (bool, error) {
+	db := r.DatabaseGetExisting(ctx, dbName)
+	if db == nil {
+		return false, nil
+	}
+
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	c := r.collectionGet(dbName, oldCollectionName)
+	if c == nil {
+		return false, nil
+	}
+
+	target := r.collectionGet(dbName, newCollectionName)
+
+	err := db.InTransaction(ctx, func(tx *fsql.Tx) error {
+		if target != nil {
+			q := fmt.Sprintf("DELETE FROM %q WHERE name = ?", metadataTableName)
+			if _, err := tx.ExecContext(ctx, q, newCollectionName); err != nil {
+				return lazyerrors.Error(err)
+			}
+
+			q = fmt.Sprintf("DROP TABLE %q", target.TableName)
+			if _, err := tx.ExecContext(ctx, q); err != nil {
+				return lazyerrors.Error(err)
+			}
+		}
+
+		q := fmt.Sprintf(`UPDATE %q SET name = ? WHERE table_name = ?`, metadataTableName)
+		if _, err := tx.ExecContext(ctx, q, newCollectionName, c.TableName); err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	c.Name = newCollectionName
+	r.colls[dbName][newCollectionName] = c
+	delete(r.colls[dbName], oldCollectionName)
+
+	return true, nil
+}
+
+// CollectionModifyParams contains parameters for CollectionModify.
+type CollectionModifyParams struct {
+	DBName           string
+	Name             string
+	Validator        *types.Document
+	ValidationLevel  string
+	ValidationAction string
+	_                struct{} // prevent unkeyed literals
+}
+
+// CollectionModify replaces validation settings of the collection.
+//
+// Returned boolean value indicates whether the collection was modified.
+// If database or collection did not exist, (false, nil) is returned.
+func (r *Registry) CollectionModify(ctx context.Context, params *CollectionModifyParams) (bool, error) {
+	db := r.DatabaseGetExisting(ctx, params.DBName)
+	if db == nil {
+		return false, nil
+	}
+
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	c := r.collectionGet(params.DBName, params.Name)
+	if c == nil {
+		return false, nil
+	}
+
+	c.Settings.Validator = params.Validator
+	c.Settings.ValidationLevel = params.ValidationLevel
+	c.Settings.ValidationAction = params.ValidationAction
+
+	q := fmt.Sprintf("UPDATE %q SET settings = ? WHERE table_name = ?", metadataTableName)
+	if _, err := db.ExecContext(ctx, q, c.Settings, c.TableName); err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	r.colls[params.DBName][params.Name] = c
+
+	return true, nil
+}
+
 // IndexesCreate creates indexes in the collection.
 //
 // Existing indexes with given names are ignored.
@@ -493,6 +602,10 @@ func (r *Registry) indexesCreate(ctx context.Context, dbName, collectionName str
 			strings.Join(columns, ", "),
 		)
 
+		if index.PartialFilterClause != "" {
+			q += " WHERE " + index.PartialFilterClause
+		}
+
 		if _, err := db.ExecContext(ctx, q); err != nil {
 			_ = r.indexesDrop(ctx, dbName, collectionName, created)
 			return lazyerrors.Error(err)
@@ -467,3 +467,78 @@ func TestIndexesCreateDrop(t *testing.T) {
 		require.Equal(t, 1, len(collection.Settings.Indexes))
 	})
 }
+
+func TestCollectionReplace(t *testing.T) {
+	t.Parallel()
+	ctx := testutil.Ctx(t)
+
+	sp, err := state.NewProvider("")
+	require.NoError(t, err)
+
+	r, err := NewRegistry(testutil.TestSQLiteURI(t, ""), 100, testutil.Logger(t), sp)
+	require.NoError(t, err)
+	t.Cleanup(r.Close)
+
+	dbName := testutil.DatabaseName(t)
+
+	db, err := r.DatabaseGetOrCreate(ctx, dbName)
+	require.NoError(t, err)
+	require.NotNil(t, db)
+
+	oldName, newName := testutil.CollectionName(t)+"_old", testutil.CollectionName(t)+"_new"
+
+	for _, name := range []string{oldName, newName} {
+		created, err := r.CollectionCreate(ctx, &CollectionCreateParams{DBName: dbName, Name: name})
+		require.NoError(t, err)
+		require.True(t, created)
+	}
+
+	oldTable := r.CollectionGet(ctx, dbName, oldName).TableName
+	newTable := r.CollectionGet(ctx, dbName, newName).TableName
+
+	t.Run("Fail", func(t *testing.T) {
+		// make the update of collection name fail after the existing collection was dropped
+		q := fmt.Sprintf(
+			"CREATE TRIGGER fail_rename BEFORE UPDATE ON %q BEGIN SELECT RAISE(ABORT, 'rename failed'); END",
+			metadataTableName,
+		)
+		_, err = db.ExecContext(ctx, q)
+		require.NoError(t, err)
+
+		replaced, err := r.CollectionReplace(ctx, dbName, oldName, newName)
+		require.ErrorContains(t, err, "rename failed")
+		require.False(t, replaced)
+
+		_, err = db.ExecContext(ctx, "DROP TRIGGER fail_rename")
+		require.NoError(t, err)
+
+		// both collections are kept in the database and in the cache
+		err = r.initCollections(ctx, dbName, db)
+		require.NoError(t, err)
+
+		require.Equal(t, oldTable, r.CollectionGet(ctx, dbName, oldName).TableName)
+		require.Equal(t, newTable, r.CollectionGet(ctx, dbName, newName).TableName)
+
+		var count int
+		row := db.QueryRowContext(ctx, "SELECT count(*) FROM sqlite_master WHERE type = 'table' AND name = ?", newTable)
+		require.NoError(t, row.Scan(&count))
+		require.Equal(t, 1, count)
+	})
+
+	t.Run("Replace", func(t *testing.T) {
+		replaced, err := r.CollectionReplace(ctx, dbName, oldName, newName)
+		require.NoError(t, err)
+		require.True(t, replaced)
+
+		err = r.initCollections(ctx, dbName, db)
+		require.NoError(t, err)
+
+		require.Nil(t, r.CollectionGet(ctx, dbName, oldName))
+		require.Equal(t, oldTable, r.CollectionGet(ctx, dbName, newName).TableName)
+
+		var count int
+		row := db.QueryRowContext(ctx, "SELECT count(*) FROM sqlite_master WHERE type = 'table' AND name = ?", newTable)
+		require.NoError(t, row.Scan(&count))
+		require.Equal(t, 0, count)
+	})
+}
@@ -20,6 +20,8 @@ import (
 	"encoding/json"
 	"slices"
 
+	"github.com/FerretDB/FerretDB/internal/handler/sjson"
+	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
 )
 
@@ -29,6 +31,79 @@ type Settings struct {
 	Indexes         []IndexInfo `json:"indexes"`
 	CappedSize      int64       `json:"cappedSize"`
 	CappedDocuments int64       `json:"cappedDocuments"`
+
+	// Validator is set for collections with document validation.
+	// It is stored as sjson to preserve BSON types.
+	Validator        *types.Document `json:"-"`
+	ValidationLevel  string          `json:"validationLevel,omitempty"`
+	ValidationAction string          `json:"validationAction,omitempty"`
+}
+
+// settingsJSON is used for JSON encoding of Settings.
+type settingsJSON struct {
+	UUID             string          `json:"uuid"`
+	Indexes          []IndexInfo     `json:"indexes"`
+	CappedSize       int64           `json:"cappedSize"`
+	CappedDocuments  int64           `json:"cappedDocuments"`
+	Validator        json.RawMessage `json:"validator,omitempty"`
+	ValidationLevel  string          `json:"validationLevel,omitempty"`
+	ValidationAction string          `json:"validationAction,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler interface.
+func (s Settings) MarshalJSON() ([]byte, error) {
+	res := settingsJSON{
+		UUID:             s.UUID,
+		Indexes:          s.Indexes,
+		CappedSize:       s.CappedSize,
+		CappedDocuments:  s.CappedDocuments,
+		ValidationLevel:  s.ValidationLevel,
+		ValidationAction: s.ValidationAction,
+	}
+
+	if s.Validator != nil {
+		b, err := sjson.Marshal(s.Validator)
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		res.Validator = b
+	}
+
+	b, err := json.Marshal(res)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return b, nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface.
+func (s *Settings) UnmarshalJSON(data []byte) error {
+	var res settingsJSON
+	if err := json.Unmarshal(data, &res); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	*s = Settings{
+		UUID:             res.UUID,
+		Indexes:          res.Indexes,
+		CappedSize:       res.CappedSize,
+		CappedDocuments:  res.CappedDocuments,
+		ValidationLevel:  res.ValidationLevel,
+		ValidationAction: res.ValidationAction,
+	}
+
+	if res.Validator != nil {
+		doc, err := sjson.Unmarshal(res.Validator)
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		s.Validator = doc
+	}
+
+	return nil
 }
 
 // IndexInfo represents information about a single index.
@@ -36,6 +111,78 @@ type IndexInfo struct {
 	Name   string         `json:"name"`
 	Key    []IndexKeyPair `json:"key"`
 	Unique bool           `json:"unique"`
+
+	// ExpireAfterSeconds is set for TTL indexes.
+	ExpireAfterSeconds *int32 `json:"expireAfterSeconds,omitempty"`
+
+	// PartialFilterExpression is set for partial indexes.
+	// It is stored as sjson to preserve BSON types.
+	PartialFilterExpression *types.Document `json:"-"`
+
+	// PartialFilterClause is the predicate of partial index built from PartialFilterExpression.
+	// It is used only for index creation and is not stored.
+	PartialFilterClause string `json:"-"`
+}
+
+// indexInfoJSON is used for JSON encoding of IndexInfo.
+type indexInfoJSON struct {
+	Name                    string          `json:"name"`
+	Key                     []IndexKeyPair  `json:"key"`
+	Unique                  bool            `json:"unique"`
+	ExpireAfterSeconds      *int32          `json:"expireAfterSeconds,omitempty"`
+	PartialFilterExpression json.RawMessage `json:"partialFilterExpression,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler interface.
+func (index IndexInfo) MarshalJSON() ([]byte, error) {
+	res := indexInfoJSON{
+		Name:               index.Name,
+		Key:                index.Key,
+		Unique:             index.Unique,
+		ExpireAfterSeconds: index.ExpireAfterSeconds,
+	}
+
+	if index.PartialFilterExpression != nil {
+		b, err := sjson.Marshal(index.PartialFilterExpression)
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		res.PartialFilterExpression = b
+	}
+
+	b, err := json.Marshal(res)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return b, nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface.
+func (index *IndexInfo) UnmarshalJSON(data []byte) error {
+	var res indexInfoJSON
+	if err := json.Unmarshal(data, &res); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	*index = IndexInfo{
+		Name:               res.Name,
+		Key:                res.Key,
+		Unique:             res.Unique,
+		ExpireAfterSeconds: res.ExpireAfterSeconds,
+	}
+
+	if res.PartialFilterExpression != nil {
+		doc, err := sjson.Unmarshal(res.PartialFilterExpression)
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		index.PartialFilterExpression = doc
+	}
+
+	return nil
 }
 
 // IndexKeyPair consists of a field name and a sort order that are part of the index.
@@ -50,18 +197,31 @@ func (s Settings) deepCopy() Settings {
 
 	for i, index := range s.Indexes {
 		indexes[i] = IndexInfo{
-			Name:   index.Name,
-			Key:    slices.Clone(index.Key),
-			Unique: index.Unique,
+			Name:               index.Name,
+			Key:                slices.Clone(index.Key),
+			Unique:             index.Unique,
+			ExpireAfterSeconds: index.ExpireAfterSeconds,
+		}
+
+		if index.PartialFilterExpression != nil {
+			indexes[i].PartialFilterExpression = index.PartialFilterExpression.DeepCopy()
 		}
 	}
 
-	return Settings{
-		UUID:            s.UUID,
-		Indexes:         indexes,
-		CappedSize:      s.CappedSize,
-		CappedDocuments: s.CappedDocuments,
+	res := Settings{
+		UUID:             s.UUID,
+		Indexes:          indexes,
+		CappedSize:       s.CappedSize,
+		CappedDocuments:  s.CappedDocuments,
+		ValidationLevel:  s.ValidationLevel,
+		ValidationAction: s.ValidationAction,
 	}
+
+	if s.Validator != nil {
+		res.Validator = s.Validator.DeepCopy()
+	}
+
+	return res
 }
 
 // Value implements driver.Valuer interface.
@@ -98,6 +258,10 @@ func (s *Settings) Scan(src any) error {
 
 // check interfaces
 var (
-	_ driver.Valuer = Settings{}
-	_ sql.Scanner   = (*Settings)(nil)
+	_ json.Marshaler   = Settings{}
+	_ json.Unmarshaler = (*Settings)(nil)
+	_ driver.Valuer    = Settings{}
+	_ sql.Scanner      = (*Settings)(nil)
+	_ json.Marshaler   = IndexInfo{}
+	_ json.Unmarshaler = (*IndexInfo)(nil)
 )
@@ -15,11 +15,17 @@
 package sqlite
 
 import (
+	"encoding/hex"
 	"fmt"
+	"math"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/FerretDB/FerretDB/internal/backends/sqlite/metadata"
+	"github.com/FerretDB/FerretDB/internal/handler/sjson"
 	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
 	"github.com/FerretDB/FerretDB/internal/util/must"
 )
 
@@ -69,3 +75,360 @@ func prepareOrderByClause(sort *types.Document) string {
 
 	return fmt.Sprintf(" ORDER BY %s%s", metadata.RecordIDColumn, order)
 }
+
+// prepareWhereClause returns WHERE clause with arguments for the given filter.
+//
+// Only a few filters are pushed down; the handler filters returned documents again anyway.
+func prepareWhereClause(filter *types.Document) (string, []any) {
+	// that logic should exist in one place
+	// TODO https://github.com/FerretDB/FerretDB/issues/3235
+	if filter.Len() == 1 {
+		v, _ := filter.Get("_id")
+		switch v.(type) {
+		case string, types.ObjectID:
+			return fmt.Sprintf(` WHERE %s = ?`, metadata.IDColumn), []any{string(must.NotFail(sjson.MarshalSingleValue(v)))}
+		}
+	}
+
+	var filters []string
+	var args []any
+
+	for _, k := range filter.Keys() {
+		// top-level operators and dot notation are not supported
+		if k == "" || strings.HasPrefix(k, "$") || strings.Contains(k, ".") {
+			continue
+		}
+
+		ops, ok := must.NotFail(filter.Get(k)).(*types.Document)
+		if !ok {
+			continue
+		}
+
+		for _, op := range ops.Keys() {
+			if op != "$gt" && op != "$lt" {
+				continue
+			}
+
+			// only dates and timestamps are supported
+			v := must.NotFail(ops.Get(op))
+			switch v.(type) {
+			case time.Time, types.Timestamp:
+			default:
+				continue
+			}
+
+			if f, a := filterCompare(k, v, op); f != "" {
+				// arrays are selected, as their elements could match
+				filters = append(filters, fmt.Sprintf(`(%s OR json_type(%s, ?) = 'array')`, f, metadata.DefaultColumn))
+				args = append(args, a...)
+				args = append(args, a[0])
+			}
+		}
+	}
+
+	if len(filters) == 0 {
+		return "", nil
+	}
+
+	return ` WHERE ` + strings.Join(filters, " AND "), args
+}
+
+// preparePartialFilterClause returns the predicate of partial index for the given filter expression.
+//
+// It is built with the same filters as query pushdown, but arguments are inlined as literals,
+// as index predicate can't use placeholders.
+// Unlike pushdown, unsupported expressions are not skipped, but return an error.
+// Only field equality, $eq, $gt, $gte, $lt, $lte, `$exists: true` and $and are supported.
+//
+// The predicate does not match documents where the field is an array containing the given value,
+// or where the path goes through an array, because index predicates can't use subqueries like json_each.
+// See exactPartialFilter.
+func preparePartialFilterClause(filter *types.Document) (string, error) {
+	filters, args, err := partialFilters(filter)
+	if err != nil {
+		return "", lazyerrors.Error(err)
+	}
+
+	return inlineArgs(strings.Join(filters, " AND "), args)
+}
+
+// exactPartialFilter returns true if the predicate built by preparePartialFilterClause
+// matches exactly the same documents as the given filter expression.
+//
+// Only `$exists: true` for top-level fields (combined with $and) is exact.
+// Other predicates do not match some documents with arrays, so unique indexes can't use them.
+func exactPartialFilter(filter *types.Document) bool {
+	for _, k := range filter.Keys() {
+		v := must.NotFail(filter.Get(k))
+
+		if k == "$and" {
+			arr, ok := v.(*types.Array)
+			if !ok {
+				return false
+			}
+
+			for i := 0; i < arr.Len(); i++ {
+				doc, ok := must.NotFail(arr.Get(i)).(*types.Document)
+				if !ok || !exactPartialFilter(doc) {
+					return false
+				}
+			}
+
+			continue
+		}
+
+		if strings.Contains(k, ".") {
+			return false
+		}
+
+		ops, ok := v.(*types.Document)
+		if !ok {
+			return false
+		}
+
+		for _, op := range ops.Keys() {
+			if op != "$exists" || must.NotFail(ops.Get(op)) != true {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// partialFilters returns SQL filters with arguments for the given partial filter expression.
+func partialFilters(filter *types.Document) (filters []string, args []any, err error) {
+	for _, k := range filter.Keys() {
+		v := must.NotFail(filter.Get(k))
+
+		if k == "$and" {
+			arr, ok := v.(*types.Array)
+			if !ok {
+				return nil, nil, lazyerrors.Errorf("unexpected $and value %s", types.FormatAnyValue(v))
+			}
+
+			for i := 0; i < arr.Len(); i++ {
+				doc, ok := must.NotFail(arr.Get(i)).(*types.Document)
+				if !ok {
+					return nil, nil, lazyerrors.Errorf("unexpected $and value %s", types.FormatAnyValue(v))
+				}
+
+				f, a, err := partialFilters(doc)
+				if err != nil {
+					return nil, nil, lazyerrors.Error(err)
+				}
+
+				filters = append(filters, "("+strings.Join(f, " AND ")+")")
+				args = append(args, a...)
+			}
+
+			continue
+		}
+
+		if strings.HasPrefix(k, "$") {
+			return nil, nil, lazyerrors.Errorf("unsupported partial filter expression %s", k)
+		}
+
+		ops, ok := v.(*types.Document)
+		if !ok {
+			ops = must.NotFail(types.NewDocument("$eq", v))
+		}
+
+		for _, op := range ops.Keys() {
+			var f string
+			var a []any
+
+			switch opV := must.NotFail(ops.Get(op)); op {
+			case "$eq":
+				f, a = filterEqual(k, opV)
+
+			case "$exists":
+				if valuePath, _, ok := fieldPaths(k); ok && opV == true {
+					f, a = fmt.Sprintf(`json_type(%s, ?) IS NOT NULL`, metadata.DefaultColumn), []any{valuePath}
+				}
+
+			default:
+				f, a = filterCompare(k, opV, op)
+			}
+
+			if f == "" {
+				return nil, nil, lazyerrors.Errorf(
+					"unsupported partial filter expression %s: %s", op, types.FormatAnyValue(must.NotFail(ops.Get(op))),
+				)
+			}
+
+			filters = append(filters, f)
+			args = append(args, a...)
+		}
+	}
+
+	return filters, args, nil
+}
+
+// fieldPaths returns JSON paths of the value and the type of the field with the given name.
+//
+// For example, the value of a.b is stored under $."a"."b",
+// and its type is stored under $."$s"."p"."a"."$s"."p"."b"."t".
+// It returns false if the name can't be used in a JSON path.
+func fieldPaths(name string) (valuePath, typePath string, ok bool) {
+	if name == "" || strings.ContainsAny(name, `"\`) {
+		return "", "", false
+	}
+
+	valuePath, typePath = "$", "$"
+
+	for _, f := range strings.Split(name, ".") {
+		valuePath += `."` + f + `"`
+		typePath += `."$s"."p"."` + f + `"`
+	}
+
+	typePath += `."t"`
+
+	return valuePath, typePath, true
+}
+
+// filterEqual returns the proper SQL filter with arguments that filters documents
+// where the scalar value under k is equal to v.
+//
+// Value types are checked using the document's schema.
+// Arrays are not selected.
+// It returns an empty filter for unsupported values.
+func filterEqual(k string, v any) (filter string, args []any) {
+	valuePath, typePath, ok := fieldPaths(k)
+	if !ok {
+		return
+	}
+
+	var arg any
+	var typs string
+
+	switch v := v.(type) {
+	case float64, int32, int64:
+		arg = v
+		typs = `'double', 'int', 'long'`
+
+	case string:
+		arg = v
+		typs = `'string'`
+
+	case types.ObjectID:
+		arg = hex.EncodeToString(v[:])
+		typs = `'objectId'`
+
+	case time.Time:
+		arg = v.UnixMilli()
+		typs = `'date'`
+
+	case bool:
+		// ->> returns 1 and 0 for JSON booleans
+		arg = int32(0)
+		if v {
+			arg = int32(1)
+		}
+
+		typs = `'bool'`
+
+	default:
+		return
+	}
+
+	filter = fmt.Sprintf(`(%[1]s->>? = ? AND %[1]s->>? IN (%[2]s))`, metadata.DefaultColumn, typs)
+	args = append(args, valuePath, arg, typePath)
+
+	return
+}
+
+// filterCompare returns the proper SQL filter with arguments that filters documents
+// where the scalar value under k is greater than ($gt), greater than or equal to ($gte),
+// less than ($lt), or less than or equal to ($lte) v.
+//
+// Only numbers, dates, and timestamps are supported, as they are stored as JSON numbers
+// that could be compared directly; value types are checked using the document's schema.
+// Arrays are not selected.
+// It returns an empty filter for other values and operators.
+func filterCompare(k string, v any, op string) (filter string, args []any) {
+	valuePath, typePath, ok := fieldPaths(k)
+	if !ok {
+		return
+	}
+
+	var sqlOp string
+
+	switch op {
+	case "$gt":
+		sqlOp = ">"
+	case "$gte":
+		sqlOp = ">="
+	case "$lt":
+		sqlOp = "<"
+	case "$lte":
+		sqlOp = "<="
+	default:
+		return
+	}
+
+	var arg any
+	var typs string
+
+	switch v := v.(type) {
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return
+		}
+
+		arg = v
+		typs = `'double', 'int', 'long'`
+
+	case int32, int64:
+		arg = v
+		typs = `'double', 'int', 'long'`
+
+	case time.Time:
+		arg = v.UnixMilli()
+		typs = `'date'`
+
+	case types.Timestamp:
+		if arg = v.Signed(); v.Signed() < 0 {
+			return
+		}
+
+		typs = `'timestamp'`
+
+	default:
+		return
+	}
+
+	filter = fmt.Sprintf(`(%[1]s->>? %[2]s ? AND %[1]s->>? IN (%[3]s))`, metadata.DefaultColumn, sqlOp, typs)
+	args = append(args, valuePath, arg, typePath)
+
+	return
+}
+
+// inlineArgs replaces placeholders in the given SQL with literals of arguments.
+func inlineArgs(sql string, args []any) (string, error) {
+	parts := strings.Split(sql, "?")
+	if len(parts) != len(args)+1 {
+		return "", lazyerrors.Errorf("got %d placeholders for %d arguments", len(parts)-1, len(args))
+	}
+
+	var res strings.Builder
+
+	for i, arg := range args {
+		res.WriteString(parts[i])
+
+		switch arg := arg.(type) {
+		case string:
+			res.WriteString("'" + strings.ReplaceAll(arg, "'", "''") + "'")
+		case float64:
+			res.WriteString(strconv.FormatFloat(arg, 'g', -1, 64))
+		case int32, int64:
+			res.WriteString(fmt.Sprint(arg))
+		default:
+			return "", lazyerrors.Errorf("unexpected argument type %T", arg)
+		}
+	}
+
+	res.WriteString(parts[len(args)])
+
+	return res.String(), nil
+}
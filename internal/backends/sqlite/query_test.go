@@ -17,8 +17,10 @@ package sqlite
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/FerretDB/FerretDB/internal/backends/sqlite/metadata"
 	"github.com/FerretDB/FerretDB/internal/types"
@@ -119,3 +121,162 @@ func TestPrepareOrderByClause(t *testing.T) {
 		})
 	}
 }
+
+func TestPrepareWhereClause(t *testing.T) {
+	t.Parallel()
+
+	whereCompare := ` WHERE ((_ferretdb_sjson->>? %s ? AND _ferretdb_sjson->>? IN ('%s')) OR ` +
+		`json_type(_ferretdb_sjson, ?) = 'array')`
+
+	for name, tc := range map[string]struct {
+		filter   *types.Document
+		expected string
+		args     []any
+	}{
+		"IDString": {
+			filter:   must.NotFail(types.NewDocument("_id", "foo")),
+			expected: ` WHERE _ferretdb_sjson->'$._id' = ?`,
+			args:     []any{`"foo"`},
+		},
+		"GtDatetime": {
+			filter: must.NotFail(types.NewDocument(
+				"v", must.NotFail(types.NewDocument(
+					"$gt", time.Date(2021, 11, 1, 10, 18, 42, 123000000, time.UTC),
+				)),
+			)),
+			expected: fmt.Sprintf(whereCompare, ">", "date"),
+			args:     []any{`$."v"`, int64(1635761922123), `$."$s"."p"."v"."t"`, `$."v"`},
+		},
+		"LtTimestamp": {
+			filter: must.NotFail(types.NewDocument(
+				"_id", "foo",
+				"v", must.NotFail(types.NewDocument("$lt", types.Timestamp(42))),
+			)),
+			expected: fmt.Sprintf(whereCompare, "<", "timestamp"),
+			args:     []any{`$."v"`, int64(42), `$."$s"."p"."v"."t"`, `$."v"`},
+		},
+		"GtUnsupported": {
+			filter: must.NotFail(types.NewDocument(
+				"v", must.NotFail(types.NewDocument("$gt", int32(42))),
+			)),
+		},
+		"GtDotNotation": {
+			filter: must.NotFail(types.NewDocument(
+				"v.foo", must.NotFail(types.NewDocument("$gt", types.Timestamp(42))),
+			)),
+		},
+		"Nil": {},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, args := prepareWhereClause(tc.filter)
+			assert.Equal(t, tc.expected, actual)
+			assert.Equal(t, tc.args, args)
+		})
+	}
+}
+
+func TestPreparePartialFilterClause(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		filter   *types.Document
+		expected string
+		err      bool
+	}{
+		"Equality": {
+			filter: must.NotFail(types.NewDocument("v", "it's?")),
+			expected: `(_ferretdb_sjson->>'$."v"' = 'it''s?' AND ` +
+				`_ferretdb_sjson->>'$."$s"."p"."v"."t"' IN ('string'))`,
+		},
+		"EqualityDotNotation": {
+			filter: must.NotFail(types.NewDocument("v.foo", true)),
+			expected: `(_ferretdb_sjson->>'$."v"."foo"' = 1 AND ` +
+				`_ferretdb_sjson->>'$."$s"."p"."v"."$s"."p"."foo"."t"' IN ('bool'))`,
+		},
+		"Comparison": {
+			filter: must.NotFail(types.NewDocument(
+				"age", must.NotFail(types.NewDocument("$gte", int32(18), "$lt", 65.5)),
+			)),
+			expected: `(_ferretdb_sjson->>'$."age"' >= 18 AND ` +
+				`_ferretdb_sjson->>'$."$s"."p"."age"."t"' IN ('double', 'int', 'long')) AND ` +
+				`(_ferretdb_sjson->>'$."age"' < 65.5 AND ` +
+				`_ferretdb_sjson->>'$."$s"."p"."age"."t"' IN ('double', 'int', 'long'))`,
+		},
+		"And": {
+			filter: must.NotFail(types.NewDocument(
+				"$and", must.NotFail(types.NewArray(
+					must.NotFail(types.NewDocument("v", must.NotFail(types.NewDocument("$exists", true)))),
+				)),
+			)),
+			expected: `(json_type(_ferretdb_sjson, '$."v"') IS NOT NULL)`,
+		},
+		"ExistsFalse": {
+			filter: must.NotFail(types.NewDocument("v", must.NotFail(types.NewDocument("$exists", false)))),
+			err:    true,
+		},
+		"CompareString": {
+			filter: must.NotFail(types.NewDocument("v", must.NotFail(types.NewDocument("$gt", "foo")))),
+			err:    true,
+		},
+		"QuotedField": {
+			filter: must.NotFail(types.NewDocument(`"v"`, "foo")),
+			err:    true,
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := preparePartialFilterClause(tc.filter)
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestExactPartialFilter(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		filter   *types.Document
+		expected bool
+	}{
+		"Exists": {
+			filter:   must.NotFail(types.NewDocument("v", must.NotFail(types.NewDocument("$exists", true)))),
+			expected: true,
+		},
+		"AndExists": {
+			filter: must.NotFail(types.NewDocument(
+				"$and", must.NotFail(types.NewArray(
+					must.NotFail(types.NewDocument("v", must.NotFail(types.NewDocument("$exists", true)))),
+					must.NotFail(types.NewDocument("w", must.NotFail(types.NewDocument("$exists", true)))),
+				)),
+			)),
+			expected: true,
+		},
+		"ExistsDotNotation": {
+			filter: must.NotFail(types.NewDocument("v.foo", must.NotFail(types.NewDocument("$exists", true)))),
+		},
+		"Equality": {
+			filter: must.NotFail(types.NewDocument("v", true)),
+		},
+		"Comparison": {
+			filter: must.NotFail(types.NewDocument("v", must.NotFail(types.NewDocument("$gte", int32(18))))),
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, exactPartialFilter(tc.filter))
+		})
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/util/fsql"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+)
+
+// transaction implements backends.Transaction interface.
+//
+// SQLite transactions are started lazily on the first use of each database
+// (each FerretDB database is a separate SQLite database file).
+type transaction struct {
+	m   sync.Mutex
+	txs map[*fsql.DB]*fsql.Tx
+}
+
+// newTransaction creates a new transaction.
+func newTransaction() *transaction {
+	return &transaction{
+		txs: map[*fsql.DB]*fsql.Tx{},
+	}
+}
+
+// tx returns SQLite transaction for the given database, starting it if needed.
+func (t *transaction) tx(ctx context.Context, db *fsql.DB) (*fsql.Tx, error) {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	if tx := t.txs[db]; tx != nil {
+		return tx, nil
+	}
+
+	// transaction outlives the context of the command that started it
+	tx, err := db.BeginTx(context.WithoutCancel(ctx), nil)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	t.txs[db] = tx
+
+	return tx, nil
+}
+
+// Commit implements backends.Transaction interface.
+func (t *transaction) Commit(ctx context.Context) error {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	var res error
+
+	for db, tx := range t.txs {
+		if res != nil {
+			_ = tx.Rollback()
+		} else if err := tx.Commit(); err != nil {
+			res = lazyerrors.Error(err)
+		}
+
+		delete(t.txs, db)
+	}
+
+	return res
+}
+
+// Abort implements backends.Transaction interface.
+func (t *transaction) Abort(ctx context.Context) error {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	var res error
+
+	for db, tx := range t.txs {
+		if err := tx.Rollback(); err != nil && res == nil {
+			res = lazyerrors.Error(err)
+		}
+
+		delete(t.txs, db)
+	}
+
+	return res
+}
+
+// querier represents methods common for *fsql.DB and *fsql.Tx.
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*fsql.Rows, error)
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// getQuerier returns the transaction from the context for the given database,
+// or the database itself if there is no transaction.
+func getQuerier(ctx context.Context, db *fsql.DB) (querier, error) {
+	t, _ := backends.GetTransaction(ctx).(*transaction)
+	if t == nil {
+		return db, nil
+	}
+
+	return t.tx(ctx, db)
+}
+
+// inTransaction calls f with the transaction from the context for the given database.
+// If there is no transaction, f is wrapped in a new one using db.
+func inTransaction(ctx context.Context, db *fsql.DB, f func(tx *fsql.Tx) error) error {
+	t, _ := backends.GetTransaction(ctx).(*transaction)
+	if t == nil {
+		return db.InTransaction(ctx, f)
+	}
+
+	tx, err := t.tx(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	return f(tx)
+}
+
+// check interfaces
+var (
+	_ backends.Transaction = (*transaction)(nil)
+	_ querier              = (*fsql.DB)(nil)
+	_ querier              = (*fsql.Tx)(nil)
+)
@@ -0,0 +1,150 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backends
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	otelcodes "go.opentelemetry.io/otel/codes"
+
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/resource"
+)
+
+// Transaction is a handle of a multi-document transaction started by [Backend.BeginTransaction].
+//
+// Collection methods use the transaction when it is present in the context; see [WithTransaction].
+// Transaction should be either committed or aborted exactly once;
+// subsequent calls return an error.
+//
+// Transaction methods should be thread-safe,
+// but collection methods using the same transaction are not called concurrently.
+//
+// See transactionContract and its methods for additional details.
+type Transaction interface {
+	Commit(context.Context) error
+	Abort(context.Context) error
+}
+
+// transactionContract implements Transaction interface.
+type transactionContract struct {
+	t     Transaction
+	token *resource.Token
+
+	m    sync.Mutex
+	done bool
+}
+
+// newTransactionContract wraps Transaction and enforces its contract.
+func newTransactionContract(t Transaction) Transaction {
+	tc := &transactionContract{
+		t:     t,
+		token: resource.NewToken(),
+	}
+	resource.Track(tc, tc.token)
+
+	return tc
+}
+
+// Commit commits the transaction.
+//
+// If commit fails, the transaction is rolled back.
+func (tc *transactionContract) Commit(ctx context.Context) error {
+	ctx, span := otel.Tracer("").Start(ctx, "Commit")
+	defer span.End()
+
+	if err := tc.finish(); err != nil {
+		span.SetStatus(otelcodes.Error, "")
+		return err
+	}
+
+	err := tc.t.Commit(ctx)
+	if err != nil {
+		span.SetStatus(otelcodes.Error, "")
+	}
+
+	checkError(err)
+
+	return err
+}
+
+// Abort rolls back the transaction.
+func (tc *transactionContract) Abort(ctx context.Context) error {
+	ctx, span := otel.Tracer("").Start(ctx, "Abort")
+	defer span.End()
+
+	if err := tc.finish(); err != nil {
+		span.SetStatus(otelcodes.Error, "")
+		return err
+	}
+
+	err := tc.t.Abort(ctx)
+	if err != nil {
+		span.SetStatus(otelcodes.Error, "")
+	}
+
+	checkError(err)
+
+	return err
+}
+
+// finish marks the transaction as committed or aborted.
+//
+// It returns an error if that was already done.
+func (tc *transactionContract) finish() error {
+	tc.m.Lock()
+	defer tc.m.Unlock()
+
+	if tc.done {
+		return lazyerrors.New("transaction is already committed or aborted")
+	}
+
+	tc.done = true
+
+	resource.Untrack(tc, tc.token)
+
+	return nil
+}
+
+// transactionKey is a context key for the transaction.
+type transactionKey struct{}
+
+// WithTransaction returns a new context with the given transaction.
+//
+// Collection methods called with that context use the transaction.
+func WithTransaction(ctx context.Context, t Transaction) context.Context {
+	return context.WithValue(ctx, transactionKey{}, t)
+}
+
+// GetTransaction returns the transaction stored in the context by [WithTransaction], or nil.
+//
+// It returns the transaction created by the backend implementation itself,
+// without wrappers added by contracts.
+func GetTransaction(ctx context.Context) Transaction {
+	t, _ := ctx.Value(transactionKey{}).(Transaction)
+
+	if tc, ok := t.(*transactionContract); ok {
+		return tc.t
+	}
+
+	return t
+}
+
+// check interfaces
+var (
+	_ Transaction = (*transactionContract)(nil)
+)
@@ -0,0 +1,75 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backends
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testTransaction counts calls of Transaction methods.
+type testTransaction struct {
+	commits int
+	aborts  int
+}
+
+// Commit implements Transaction interface.
+func (t *testTransaction) Commit(context.Context) error {
+	t.commits++
+	return nil
+}
+
+// Abort implements Transaction interface.
+func (t *testTransaction) Abort(context.Context) error {
+	t.aborts++
+	return nil
+}
+
+func TestTransactionContract(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	t.Run("CommitTwice", func(t *testing.T) {
+		t.Parallel()
+
+		tt := new(testTransaction)
+		tc := newTransactionContract(tt)
+
+		require.NoError(t, tc.Commit(ctx))
+		assert.Error(t, tc.Commit(ctx))
+		assert.Error(t, tc.Abort(ctx))
+
+		assert.Equal(t, 1, tt.commits)
+		assert.Equal(t, 0, tt.aborts)
+	})
+
+	t.Run("AbortTwice", func(t *testing.T) {
+		t.Parallel()
+
+		tt := new(testTransaction)
+		tc := newTransactionContract(tt)
+
+		require.NoError(t, tc.Abort(ctx))
+		assert.Error(t, tc.Abort(ctx))
+		assert.Error(t, tc.Commit(ctx))
+
+		assert.Equal(t, 0, tt.commits)
+		assert.Equal(t, 1, tt.aborts)
+	})
+}
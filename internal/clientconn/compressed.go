@@ -0,0 +1,135 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientconn
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+
+	"github.com/FerretDB/wire"
+
+	"github.com/FerretDB/FerretDB/internal/clientconn/compression"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+)
+
+// compressedPrefixLen is the length of OP_COMPRESSED fields before the compressed message:
+// original opcode (int32), uncompressed size (int32), and compressor ID (uint8).
+const compressedPrefixLen = 9
+
+// readMessage reads the next message like [wire.ReadMessage].
+//
+// OP_COMPRESSED messages are decompressed, and the header and body of the original message are returned
+// together with the compressor used by the client.
+// For uncompressed messages, the returned compressor is empty.
+func readMessage(r *bufio.Reader) (*wire.MsgHeader, wire.MsgBody, compression.Compressor, error) {
+	b, err := r.Peek(wire.MsgHeaderLen)
+	if err != nil || wire.OpCode(binary.LittleEndian.Uint32(b[12:16])) != wire.OpCodeCompressed {
+		// let wire.ReadMessage handle errors, including zero reads
+		header, body, err := wire.ReadMessage(r)
+		return header, body, "", err
+	}
+
+	length := int32(binary.LittleEndian.Uint32(b[0:4]))
+	if length < wire.MsgHeaderLen+compressedPrefixLen || length > wire.MaxMsgLen {
+		return nil, nil, "", lazyerrors.Errorf("invalid OP_COMPRESSED message length %d", length)
+	}
+
+	msg := make([]byte, length)
+	if n, err := io.ReadFull(r, msg); err != nil {
+		return nil, nil, "", lazyerrors.Errorf("expected %d, read %d: %w", len(msg), n, err)
+	}
+
+	prefix := msg[wire.MsgHeaderLen:]
+
+	opCode := wire.OpCode(binary.LittleEndian.Uint32(prefix[0:4]))
+	if opCode == wire.OpCodeCompressed {
+		return nil, nil, "", lazyerrors.New("nested OP_COMPRESSED message")
+	}
+
+	size := int32(binary.LittleEndian.Uint32(prefix[4:8]))
+	if size < 0 || size > wire.MaxMsgLen-wire.MsgHeaderLen {
+		return nil, nil, "", lazyerrors.Errorf("invalid OP_COMPRESSED uncompressed size %d", size)
+	}
+
+	compressor, err := compression.FromID(prefix[8])
+	if err != nil {
+		return nil, nil, "", lazyerrors.Error(err)
+	}
+
+	data, err := compression.Decompress(compressor, prefix[compressedPrefixLen:], int(size))
+	if err != nil {
+		return nil, nil, "", lazyerrors.Error(err)
+	}
+
+	// rebuild the original message with the same request ID and response to fields
+	orig := make([]byte, wire.MsgHeaderLen+len(data))
+	binary.LittleEndian.PutUint32(orig[0:4], uint32(len(orig)))
+	copy(orig[4:12], msg[4:12])
+	binary.LittleEndian.PutUint32(orig[12:16], uint32(opCode))
+	copy(orig[wire.MsgHeaderLen:], data)
+
+	header, body, err := wire.ReadMessage(bufio.NewReader(bytes.NewReader(orig)))
+	if err != nil {
+		return nil, nil, "", lazyerrors.Error(err)
+	}
+
+	return header, body, compressor, nil
+}
+
+// writeMessage writes the message like [wire.WriteMessage].
+//
+// If compressor is not empty, the message is written as OP_COMPRESSED message.
+func writeMessage(w *bufio.Writer, header *wire.MsgHeader, body wire.MsgBody, compressor compression.Compressor) error {
+	if compressor == "" {
+		return wire.WriteMessage(w, header, body)
+	}
+
+	b, err := body.MarshalBinary()
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	data, err := compression.Compress(compressor, b)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	compressedHeader := &wire.MsgHeader{
+		MessageLength: int32(wire.MsgHeaderLen + compressedPrefixLen + len(data)),
+		RequestID:     header.RequestID,
+		ResponseTo:    header.ResponseTo,
+		OpCode:        wire.OpCodeCompressed,
+	}
+
+	hb, err := compressedHeader.MarshalBinary()
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	prefix := make([]byte, compressedPrefixLen)
+	binary.LittleEndian.PutUint32(prefix[0:4], uint32(header.OpCode))
+	binary.LittleEndian.PutUint32(prefix[4:8], uint32(len(b)))
+	prefix[8] = compressor.ID()
+
+	for _, p := range [][]byte{hb, prefix, data} {
+		if _, err = w.Write(p); err != nil {
+			return lazyerrors.Error(err)
+		}
+	}
+
+	return nil
+}
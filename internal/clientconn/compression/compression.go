@@ -0,0 +1,196 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package compression provides compressors for OP_COMPRESSED wire protocol messages.
+package compression
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+
+	"github.com/FerretDB/wire"
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// Compressor represents a compressor name as used in `hello` command and connection strings.
+type Compressor string
+
+// Known compressors.
+const (
+	Noop   Compressor = "noop"
+	Snappy Compressor = "snappy"
+	Zlib   Compressor = "zlib"
+	Zstd   Compressor = "zstd"
+)
+
+// ids maps compressors to IDs used in OP_COMPRESSED messages.
+var ids = map[Compressor]uint8{
+	Noop:   0,
+	Snappy: 1,
+	Zlib:   2,
+	Zstd:   3,
+}
+
+// supported contains compressors that could be negotiated by clients.
+var supported = []Compressor{Snappy, Zlib, Zstd}
+
+// zstd encoder and decoder are safe for concurrent use of EncodeAll and DecodeAll;
+// they are created lazily because they allocate a lot.
+var (
+	zstdEncoder = sync.OnceValue(func() *zstd.Encoder {
+		return must.NotFail(zstd.NewWriter(nil))
+	})
+	zstdDecoder = sync.OnceValue(func() *zstd.Decoder {
+		return must.NotFail(zstd.NewReader(nil, zstd.WithDecoderMaxMemory(wire.MaxMsgLen)))
+	})
+)
+
+// Negotiate returns supported compressors from the list requested by the client, keeping client's order.
+func Negotiate(requested []string) []Compressor {
+	var res []Compressor
+
+	for _, name := range requested {
+		c := Compressor(name)
+		if slices.Contains(supported, c) && !slices.Contains(res, c) {
+			res = append(res, c)
+		}
+	}
+
+	return res
+}
+
+// FromID returns the compressor with the given OP_COMPRESSED ID.
+func FromID(id uint8) (Compressor, error) {
+	for c, cid := range ids {
+		if cid == id {
+			return c, nil
+		}
+	}
+
+	return "", lazyerrors.Errorf("unknown compressor ID %d", id)
+}
+
+// ID returns the OP_COMPRESSED ID of the compressor.
+//
+// It panics for unknown compressors.
+func (c Compressor) ID() uint8 {
+	id, ok := ids[c]
+	if !ok {
+		panic(fmt.Sprintf("unknown compressor %q", c))
+	}
+
+	return id
+}
+
+// Compress returns data compressed with the given compressor.
+func Compress(c Compressor, data []byte) ([]byte, error) {
+	switch c {
+	case Noop:
+		return slices.Clone(data), nil
+
+	case Snappy:
+		return snappy.Encode(nil, data), nil
+
+	case Zlib:
+		var buf bytes.Buffer
+
+		w := zlib.NewWriter(&buf)
+
+		if _, err := w.Write(data); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if err := w.Close(); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		return buf.Bytes(), nil
+
+	case Zstd:
+		return zstdEncoder().EncodeAll(data, nil), nil
+
+	default:
+		return nil, lazyerrors.Errorf("unknown compressor %q", c)
+	}
+}
+
+// Decompress returns data decompressed with the given compressor.
+//
+// The size is the expected uncompressed size; it is an error if the actual size is different.
+// It should be checked by the caller before calling this function,
+// as a buffer of that size is allocated.
+func Decompress(c Compressor, data []byte, size int) ([]byte, error) {
+	var res []byte
+
+	switch c {
+	case Noop:
+		res = slices.Clone(data)
+
+	case Snappy:
+		n, err := snappy.DecodedLen(data)
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if n != size {
+			return nil, lazyerrors.Errorf("expected uncompressed size %d, got %d", size, n)
+		}
+
+		if res, err = snappy.Decode(make([]byte, size), data); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+	case Zlib:
+		r, err := zlib.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		defer r.Close()
+
+		// read one extra byte to detect data larger than expected
+		res = make([]byte, size+1)
+
+		n, err := io.ReadFull(r, res)
+		if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+			return nil, lazyerrors.Error(err)
+		}
+
+		res = res[:n]
+
+	case Zstd:
+		var err error
+		if res, err = zstdDecoder().DecodeAll(data, make([]byte, 0, size)); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+	default:
+		return nil, lazyerrors.Errorf("unknown compressor %q", c)
+	}
+
+	if len(res) != size {
+		return nil, lazyerrors.Errorf("expected uncompressed size %d, got %d", size, len(res))
+	}
+
+	return res, nil
+}
@@ -0,0 +1,64 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compression
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompression(t *testing.T) {
+	t.Parallel()
+
+	data := bytes.Repeat([]byte("FerretDB"), 1000)
+
+	for _, c := range []Compressor{Noop, Snappy, Zlib, Zstd} {
+		t.Run(string(c), func(t *testing.T) {
+			t.Parallel()
+
+			id := c.ID()
+			actualC, err := FromID(id)
+			require.NoError(t, err)
+			assert.Equal(t, c, actualC)
+
+			compressed, err := Compress(c, data)
+			require.NoError(t, err)
+
+			actual, err := Decompress(c, compressed, len(data))
+			require.NoError(t, err)
+			assert.Equal(t, data, actual)
+
+			_, err = Decompress(c, compressed, len(data)-1)
+			assert.Error(t, err)
+
+			_, err = Decompress(c, compressed, len(data)+1)
+			assert.Error(t, err)
+		})
+	}
+
+	_, err := FromID(42)
+	assert.Error(t, err)
+}
+
+func TestNegotiate(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, Negotiate(nil))
+	assert.Nil(t, Negotiate([]string{"noop", "lz4"}))
+	assert.Equal(t, []Compressor{Zstd, Snappy}, Negotiate([]string{"zstd", "lz4", "snappy", "zstd"}))
+}
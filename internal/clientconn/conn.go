@@ -39,6 +39,7 @@ import (
 	"go.opentelemetry.io/otel/trace"
 
 	"github.com/FerretDB/FerretDB/internal/bson"
+	"github.com/FerretDB/FerretDB/internal/clientconn/compression"
 	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
 	"github.com/FerretDB/FerretDB/internal/clientconn/connmetrics"
 	"github.com/FerretDB/FerretDB/internal/handler"
@@ -251,13 +252,23 @@ func (c *conn) run(ctx context.Context) (err error) {
 		var reqBody wire.MsgBody
 		var resHeader *wire.MsgHeader
 		var resBody wire.MsgBody
+		var compressor compression.Compressor
 
 		// TODO https://github.com/FerretDB/FerretDB/issues/2412
-		reqHeader, reqBody, err = wire.ReadMessage(bufr)
+		reqHeader, reqBody, compressor, err = readMessage(bufr)
 		if err != nil {
 			return
 		}
 
+		if compressor != "" {
+			c.m.CompressedRequests.WithLabelValues(string(compressor)).Inc()
+
+			if connInfo.Compressor() != compressor {
+				connInfo.SetCompressor(compressor)
+				c.m.Compressors.WithLabelValues(string(compressor)).Inc()
+			}
+		}
+
 		if c.l.Enabled(ctx, slog.LevelDebug) {
 			c.l.DebugContext(ctx, "Request header: "+reqHeader.String())
 			c.l.DebugContext(ctx, "Request message:\n"+reqBody.String()+"\n")
@@ -348,10 +359,15 @@ func (c *conn) run(ctx context.Context) (err error) {
 			panic("no response to send to client")
 		}
 
-		if err = wire.WriteMessage(bufw, resHeader, resBody); err != nil {
+		// reply with the same compressor the client used for the request
+		if err = writeMessage(bufw, resHeader, resBody, compressor); err != nil {
 			return
 		}
 
+		if compressor != "" {
+			c.m.CompressedResponses.WithLabelValues(string(compressor)).Inc()
+		}
+
 		if err = bufw.Flush(); err != nil {
 			return
 		}
@@ -468,12 +484,11 @@ func (c *conn) route(connCtx context.Context, reqHeader *wire.MsgHeader, reqBody
 	case wire.OpCodeDelete:
 		fallthrough
 	case wire.OpCodeKillCursors:
-		fallthrough
-	case wire.OpCodeCompressed:
 		connCtx, span = otel.Tracer("").Start(connCtx, "")
 		err = lazyerrors.Errorf("unhandled OpCode %s", reqHeader.OpCode)
 
 	default:
+		// that includes OP_COMPRESSED, as it is decompressed by [readMessage]
 		connCtx, span = otel.Tracer("").Start(connCtx, "")
 		err = lazyerrors.Errorf("unexpected OpCode %s", reqHeader.OpCode)
 	}
@@ -21,6 +21,8 @@ import (
 	"sync"
 
 	"github.com/xdg-go/scram"
+
+	"github.com/FerretDB/FerretDB/internal/clientconn/compression"
 )
 
 // contextKey is a named unexported type for the safe use of context.WithValue.
@@ -41,6 +43,9 @@ type ConnInfo struct {
 	username string // protected by rw
 	password string // protected by rw
 
+	compressors []compression.Compressor // protected by rw
+	compressor  compression.Compressor   // protected by rw
+
 	rw sync.RWMutex
 
 	metadataRecv bool // protected by rw
@@ -101,6 +106,39 @@ func (connInfo *ConnInfo) SetMetadataRecv() {
 	connInfo.metadataRecv = true
 }
 
+// Compressors returns compressors negotiated by `hello` command.
+func (connInfo *ConnInfo) Compressors() []compression.Compressor {
+	connInfo.rw.RLock()
+	defer connInfo.rw.RUnlock()
+
+	return connInfo.compressors
+}
+
+// SetCompressors stores compressors negotiated by `hello` command.
+func (connInfo *ConnInfo) SetCompressors(compressors []compression.Compressor) {
+	connInfo.rw.Lock()
+	defer connInfo.rw.Unlock()
+
+	connInfo.compressors = compressors
+}
+
+// Compressor returns the compressor used by the client for the last compressed message,
+// or empty value if the client did not send compressed messages.
+func (connInfo *ConnInfo) Compressor() compression.Compressor {
+	connInfo.rw.RLock()
+	defer connInfo.rw.RUnlock()
+
+	return connInfo.compressor
+}
+
+// SetCompressor stores the compressor used by the client.
+func (connInfo *ConnInfo) SetCompressor(compressor compression.Compressor) {
+	connInfo.rw.Lock()
+	defer connInfo.rw.Unlock()
+
+	connInfo.compressor = compressor
+}
+
 // SetBypassBackendAuth marks the connection as not requiring backend authentication.
 func (connInfo *ConnInfo) SetBypassBackendAuth() {
 	connInfo.rw.Lock()
@@ -26,8 +26,11 @@ import (
 
 // ConnMetrics represents metrics of an individual conn or a collection of conns.
 type ConnMetrics struct {
-	Requests  *prometheus.CounterVec
-	Responses *prometheus.CounterVec
+	Requests            *prometheus.CounterVec
+	Responses           *prometheus.CounterVec
+	Compressors         *prometheus.CounterVec
+	CompressedRequests  *prometheus.CounterVec
+	CompressedResponses *prometheus.CounterVec
 }
 
 // commandMetrics represents command results metrics.
@@ -57,6 +60,33 @@ func newConnMetrics() *ConnMetrics {
 			},
 			[]string{"opcode", "command", "argument", "result"},
 		),
+		Compressors: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "compressors_total",
+				Help:      "Total number of connections that started using the compressor.",
+			},
+			[]string{"compressor"},
+		),
+		CompressedRequests: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "compressed_requests_total",
+				Help:      "Total number of compressed requests.",
+			},
+			[]string{"compressor"},
+		),
+		CompressedResponses: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "compressed_responses_total",
+				Help:      "Total number of compressed responses.",
+			},
+			[]string{"compressor"},
+		),
 	}
 }
 
@@ -64,12 +94,18 @@ func newConnMetrics() *ConnMetrics {
 func (cm *ConnMetrics) Describe(ch chan<- *prometheus.Desc) {
 	cm.Requests.Describe(ch)
 	cm.Responses.Describe(ch)
+	cm.Compressors.Describe(ch)
+	cm.CompressedRequests.Describe(ch)
+	cm.CompressedResponses.Describe(ch)
 }
 
 // Collect implements [prometheus.Collector].
 func (cm *ConnMetrics) Collect(ch chan<- prometheus.Metric) {
 	cm.Requests.Collect(ch)
 	cm.Responses.Collect(ch)
+	cm.Compressors.Collect(ch)
+	cm.CompressedRequests.Collect(ch)
+	cm.CompressedResponses.Collect(ch)
 }
 
 // GetResponses returns a map with all response metrics:
@@ -114,6 +114,11 @@ func (c *Cursor) Reset(iter types.DocumentsIterator) error {
 
 	c.m.Unlock()
 
+	// nothing to skip if no documents with record IDs were returned yet
+	if recordID == 0 {
+		return nil
+	}
+
 	for {
 		_, doc, err := c.Next()
 		if err != nil {
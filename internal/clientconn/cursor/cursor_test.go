@@ -166,5 +166,46 @@ func TestCursor(t *testing.T) {
 			require.NoError(t, err)
 			assert.Equal(t, []*types.Document{doc3}, actual)
 		})
+
+		t.Run("ResetEmpty", func(t *testing.T) {
+			t.Parallel()
+
+			c := r.NewCursor(ctx, iterator.Values(iterator.ForSlice([]*types.Document{})), params)
+
+			actual, err := iterator.ConsumeValues(c)
+			require.NoError(t, err)
+			assert.Empty(t, actual)
+
+			// nothing was returned, so nothing should be skipped
+			err = c.Reset(iterator.Values(iterator.ForSlice(all)))
+			require.NoError(t, err)
+
+			actual, err = iterator.ConsumeValues(c)
+			require.NoError(t, err)
+			assert.Equal(t, all, actual)
+		})
+
+		t.Run("ResetWithoutRecordIDs", func(t *testing.T) {
+			t.Parallel()
+
+			// documents without record IDs, such as change events, are never skipped
+			events := []*types.Document{
+				must.NotFail(types.NewDocument("v", int32(1))),
+				must.NotFail(types.NewDocument("v", int32(2))),
+			}
+
+			c := r.NewCursor(ctx, iterator.Values(iterator.ForSlice(events[:1])), params)
+
+			actual, err := iterator.ConsumeValues(c)
+			require.NoError(t, err)
+			assert.Equal(t, events[:1], actual)
+
+			err = c.Reset(iterator.Values(iterator.ForSlice(events[1:])))
+			require.NoError(t, err)
+
+			actual, err = iterator.ConsumeValues(c)
+			require.NoError(t, err)
+			assert.Equal(t, events[1:], actual)
+		})
 	})
 }
@@ -22,6 +22,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/prometheus/client_golang/prometheus"
 	"golang.org/x/exp/maps"
 
@@ -117,6 +118,10 @@ type NewParams struct {
 	Collection string
 	Username   string
 
+	// SessionID is the ID of the logical session that created the cursor, or zero UUID.
+	// All session's cursors are closed when the session ends.
+	SessionID uuid.UUID
+
 	Type         Type
 	ShowRecordID bool
 
@@ -184,6 +189,26 @@ func (r *Registry) All() []*Cursor {
 	return maps.Values(r.m)
 }
 
+// CloseAndRemoveSession closes all cursors of the given logical session of the given user,
+// then removes them from the registry.
+func (r *Registry) CloseAndRemoveSession(id uuid.UUID, username string) {
+	r.rw.RLock()
+
+	var cursors []*Cursor
+
+	for _, c := range r.m {
+		if c.SessionID == id && c.Username == username {
+			cursors = append(cursors, c)
+		}
+	}
+
+	r.rw.RUnlock()
+
+	for _, c := range cursors {
+		r.CloseAndRemove(c)
+	}
+}
+
 // CloseAndRemove closes the given cursors, then removes it from the registry.
 func (r *Registry) CloseAndRemove(c *Cursor) {
 	c.Close()
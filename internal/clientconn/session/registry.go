@@ -0,0 +1,214 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"cmp"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Registry stores logical sessions.
+//
+// Sessions are identified by session ID and the user that owns them,
+// so sessions with the same ID of different users are different sessions.
+//
+// Removing sessions from the registry does not close their cursors or abort their transactions;
+// the caller should do that for returned sessions.
+type Registry struct {
+	rw sync.RWMutex
+	m  map[key]*Session
+
+	l       *slog.Logger
+	timeout time.Duration
+}
+
+// key identifies the session in the registry.
+type key struct {
+	username string
+	id       uuid.UUID
+}
+
+// NewRegistry creates a new Registry.
+//
+// Sessions that are not used for the given timeout duration are expired.
+func NewRegistry(timeout time.Duration, l *slog.Logger) *Registry {
+	return &Registry{
+		m:       map[key]*Session{},
+		l:       l,
+		timeout: timeout,
+	}
+}
+
+// Timeout returns the duration after which unused sessions are expired.
+func (r *Registry) Timeout() time.Duration {
+	return r.timeout
+}
+
+// Start creates and stores a new session for the given user.
+func (r *Registry) Start(username string) uuid.UUID {
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	id := uuid.New()
+	for r.m[key{username: username, id: id}] != nil {
+		id = uuid.New()
+	}
+
+	r.l.Debug("Starting session", slog.String("id", id.String()), slog.String("username", username))
+
+	r.m[key{username: username, id: id}] = &Session{
+		LastUse:  time.Now(),
+		Username: username,
+		ID:       id,
+	}
+
+	return id
+}
+
+// Use updates the last use time of the given session of the given user.
+//
+// The session is created if it does not exist (drivers create session IDs on the client side).
+func (r *Registry) Use(id uuid.UUID, username string) {
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	k := key{username: username, id: id}
+
+	if s := r.m[k]; s != nil {
+		s.LastUse = time.Now()
+		return
+	}
+
+	r.l.Debug("Starting implicit session", slog.String("id", id.String()), slog.String("username", username))
+
+	r.m[k] = &Session{
+		LastUse:  time.Now(),
+		Username: username,
+		ID:       id,
+	}
+}
+
+// Refresh updates the last use time of the given existing sessions of the given user.
+func (r *Registry) Refresh(ids []uuid.UUID, username string) {
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	now := time.Now()
+
+	for _, id := range ids {
+		if s := r.m[key{username: username, id: id}]; s != nil {
+			s.LastUse = now
+		}
+	}
+}
+
+// Remove removes sessions with the given IDs that are owned by one of the given users.
+// If users is nil, sessions of all users are removed.
+//
+// It returns sessions that were actually removed.
+func (r *Registry) Remove(ids []uuid.UUID, users []string) []Session {
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	var res []Session
+
+	for k, s := range r.m {
+		if !slices.Contains(ids, k.id) || !owned(s, users) {
+			continue
+		}
+
+		r.l.Debug("Removing session", slog.String("id", k.id.String()), slog.String("username", k.username))
+
+		delete(r.m, k)
+		res = append(res, *s)
+	}
+
+	return res
+}
+
+// RemoveAll removes all sessions owned by one of the given users.
+// If users is nil, sessions of all users are removed.
+//
+// It returns removed sessions.
+func (r *Registry) RemoveAll(users []string) []Session {
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	var res []Session
+
+	for k, s := range r.m {
+		if !owned(s, users) {
+			continue
+		}
+
+		delete(r.m, k)
+		res = append(res, *s)
+	}
+
+	r.l.Debug("Removing all sessions", slog.Int("total", len(res)))
+
+	return res
+}
+
+// RemoveExpired removes sessions that were not used for the timeout duration.
+//
+// It returns removed sessions.
+func (r *Registry) RemoveExpired() []Session {
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	var res []Session
+
+	for k, s := range r.m {
+		if time.Since(s.LastUse) < r.timeout {
+			continue
+		}
+
+		r.l.Debug("Expiring session", slog.String("id", k.id.String()), slog.Time("last_use", s.LastUse))
+
+		delete(r.m, k)
+		res = append(res, *s)
+	}
+
+	return res
+}
+
+// All returns copies of all stored sessions sorted by the last use time.
+func (r *Registry) All() []Session {
+	r.rw.RLock()
+	defer r.rw.RUnlock()
+
+	res := make([]Session, 0, len(r.m))
+
+	for _, s := range r.m {
+		res = append(res, *s)
+	}
+
+	slices.SortFunc(res, func(a, b Session) int {
+		return cmp.Compare(a.LastUse.UnixNano(), b.LastUse.UnixNano())
+	})
+
+	return res
+}
+
+// owned returns true if the given session is owned by one of the given users, or if users is nil.
+func owned(s *Session, users []string) bool {
+	return users == nil || slices.Contains(users, s.Username)
+}
@@ -0,0 +1,93 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/util/testutil"
+)
+
+func TestRegistry(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry(time.Minute, testutil.Logger(t))
+
+	id1 := r.Start("user")
+	id2 := uuid.New()
+	r.Use(id2, "")
+
+	all := r.All()
+	require.Len(t, all, 2)
+	assert.Equal(t, id1, all[0].ID)
+	assert.Equal(t, "user", all[0].Username)
+	assert.Equal(t, id2, all[1].ID)
+
+	assert.Empty(t, r.RemoveExpired())
+
+	// sessions of other users are not removed
+	assert.Empty(t, r.Remove([]uuid.UUID{id1}, []string{""}))
+	assert.Empty(t, r.RemoveAll([]string{"other"}))
+	assert.Len(t, r.All(), 2)
+
+	removed := r.Remove([]uuid.UUID{id1, uuid.New()}, []string{"user"})
+	require.Len(t, removed, 1)
+	assert.Equal(t, id1, removed[0].ID)
+	assert.Len(t, r.All(), 1)
+
+	removed = r.RemoveAll(nil)
+	require.Len(t, removed, 1)
+	assert.Equal(t, id2, removed[0].ID)
+	assert.Empty(t, r.All())
+}
+
+func TestRegistryUsers(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry(time.Minute, testutil.Logger(t))
+
+	// the same session ID used by different users identifies different sessions
+	id := uuid.New()
+	r.Use(id, "user1")
+	r.Use(id, "user2")
+	require.Len(t, r.All(), 2)
+
+	removed := r.Remove([]uuid.UUID{id}, []string{"user1"})
+	require.Len(t, removed, 1)
+	assert.Equal(t, "user1", removed[0].Username)
+
+	all := r.All()
+	require.Len(t, all, 1)
+	assert.Equal(t, "user2", all[0].Username)
+}
+
+func TestRegistryExpired(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry(0, testutil.Logger(t))
+
+	id := r.Start("")
+	r.Refresh([]uuid.UUID{id}, "")
+
+	removed := r.RemoveExpired()
+	require.Len(t, removed, 1)
+	assert.Equal(t, id, removed[0].ID)
+	assert.Empty(t, r.All())
+}
@@ -0,0 +1,51 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package session provides access to logical sessions registry.
+//
+// Logical sessions are created explicitly by `startSession` command,
+// or implicitly on the first use of a session ID sent by the client in the `lsid` field.
+// Sessions that are not used for the timeout duration are expired.
+package session
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// contextKey is a named unexported type for the safe use of context.WithValue.
+type contextKey struct{}
+
+// Context key for Ctx/GetID.
+var sessionKey = contextKey{}
+
+// Session represents a logical session.
+type Session struct {
+	LastUse  time.Time
+	Username string
+	ID       uuid.UUID
+}
+
+// Ctx returns a derived context with the given session ID.
+func Ctx(ctx context.Context, id uuid.UUID) context.Context {
+	return context.WithValue(ctx, sessionKey, id)
+}
+
+// GetID returns the session ID stored in ctx, or zero UUID if there is none.
+func GetID(ctx context.Context) uuid.UUID {
+	id, _ := ctx.Value(sessionKey).(uuid.UUID)
+	return id
+}
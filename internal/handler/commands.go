@@ -44,6 +44,10 @@ type command struct {
 func (h *Handler) initCommands() {
 	h.commands = map[string]*command{
 		// sorted alphabetically
+		"abortTransaction": {
+			Handler: h.MsgAbortTransaction,
+			Help:    "Aborts a multi-document transaction.",
+		},
 		"aggregate": {
 			Handler: h.MsgAggregate,
 			Help:    "Returns aggregated data.",
@@ -66,6 +70,10 @@ func (h *Handler) initCommands() {
 			Handler: h.MsgCollStats,
 			Help:    "Returns storage data for a collection.",
 		},
+		"commitTransaction": {
+			Handler: h.MsgCommitTransaction,
+			Help:    "Commits a multi-document transaction.",
+		},
 		"compact": {
 			Handler: h.MsgCompact,
 			Help:    "Reduces the disk space collection takes and refreshes its statistics.",
@@ -128,6 +136,10 @@ func (h *Handler) initCommands() {
 			Handler: h.MsgDropIndexes,
 			Help:    "Drops indexes on a collection.",
 		},
+		"endSessions": {
+			Handler: h.MsgEndSessions,
+			Help:    "Expires specified sessions.",
+		},
 		"explain": {
 			Handler: h.MsgExplain,
 			Help:    "Returns the execution plan.",
@@ -191,6 +203,10 @@ func (h *Handler) initCommands() {
 			Handler: h.MsgKillCursors,
 			Help:    "Closes server cursors.",
 		},
+		"killSessions": {
+			Handler: h.MsgKillSessions,
+			Help:    "Kills specified sessions.",
+		},
 		"listCollections": {
 			Handler: h.MsgListCollections,
 			Help:    "Returns the information of the collections and views in the database.",
@@ -217,6 +233,10 @@ func (h *Handler) initCommands() {
 			anonymous: true,
 			Help:      "Returns a pong response.",
 		},
+		"refreshSessions": {
+			Handler: h.MsgRefreshSessions,
+			Help:    "Updates the last used time of specified sessions.",
+		},
 		"renameCollection": {
 			Handler: h.MsgRenameCollection,
 			Help:    "Changes the name of an existing collection.",
@@ -239,6 +259,10 @@ func (h *Handler) initCommands() {
 			Handler: h.MsgSetFreeMonitoring,
 			Help:    "Toggles free monitoring.",
 		},
+		"startSession": {
+			Handler: h.MsgStartSession,
+			Help:    "Starts a new logical session.",
+		},
 		"update": {
 			Handler: h.MsgUpdate,
 			Help:    "Updates documents that are matched by the query.",
@@ -281,6 +305,23 @@ func (h *Handler) initCommands() {
 	}
 
 	for name, cmd := range h.commands {
+		if _, ok := transactionCommands[name]; ok {
+			cmd.Handler = h.transactionHandler(cmd.Handler)
+		} else if _, ok = transactionNeutralCommands[name]; !ok {
+			cmd.Handler = h.noTransactionHandler(name, cmd.Handler)
+		}
+
+		sessionHandler := cmd.Handler
+
+		cmd.Handler = func(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+			ctx, err := h.sessionCtx(ctx, msg)
+			if err != nil {
+				return nil, err
+			}
+
+			return sessionHandler(ctx, msg)
+		}
+
 		if h.EnableNewAuth && !cmd.anonymous {
 			cmdHandler := h.commands[name].Handler
 
@@ -17,6 +17,7 @@ package common
 import (
 	"errors"
 
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
 	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations/operators"
 	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
 	"github.com/FerretDB/FerretDB/internal/types"
@@ -26,15 +27,17 @@ import (
 )
 
 // AddFieldsIterator returns an iterator that adds a new field to the underlying iterator.
+// Operators and expressions are evaluated with the given scope of variables.
 // It will be added to the given closer.
 //
 // Next method returns the next document after adding the new field to the document.
 //
 // Close method closes the underlying iterator.
-func AddFieldsIterator(iter types.DocumentsIterator, closer *iterator.MultiCloser, newField *types.Document) types.DocumentsIterator { //nolint:lll // for readability
+func AddFieldsIterator(iter types.DocumentsIterator, closer *iterator.MultiCloser, newField *types.Document, vars *aggregations.Variables) types.DocumentsIterator { //nolint:lll // for readability
 	res := &addFieldsIterator{
 		iter:     iter,
 		newField: newField,
+		vars:     vars,
 	}
 	closer.Add(res)
 
@@ -45,6 +48,7 @@ func AddFieldsIterator(iter types.DocumentsIterator, closer *iterator.MultiClose
 type addFieldsIterator struct {
 	iter     types.DocumentsIterator
 	newField *types.Document
+	vars     *aggregations.Variables
 }
 
 // Next implements iterator.Interface. See addFieldsIterator for details.
@@ -70,10 +74,28 @@ func (iter *addFieldsIterator) Next() (struct{}, *types.Document, error) {
 				return unused, nil, err
 			}
 
-			val, err = op.Process(doc)
+			val, err = op.Process(doc, iter.vars)
 			if err = processAddFieldsError(err); err != nil {
 				return unused, nil, err
 			}
+
+			// missing value removes the field
+			if val == nil {
+				doc.Remove(key)
+				continue
+			}
+
+		case string:
+			// expressions were validated by the stage
+			val, err = operators.Evaluate(v, doc, iter.vars)
+			if err != nil {
+				return unused, nil, err
+			}
+
+			if val == nil {
+				doc.Remove(key)
+				continue
+			}
 		}
 
 		doc.Set(key, val)
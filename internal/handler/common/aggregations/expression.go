@@ -17,6 +17,8 @@ package aggregations
 import (
 	"fmt"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/FerretDB/FerretDB/internal/handler/commonpath"
 	"github.com/FerretDB/FerretDB/internal/types"
@@ -79,10 +81,13 @@ func (e *ExpressionError) Name() string {
 //
 // Expression for access field in document should be prefixed with a dollar sign $ followed by field key.
 // For accessing embedded document or array, a dollar sign $ should be followed by dot notation.
+// Expression for access variable should be prefixed with a double dollar sign $$ followed by variable name,
+// optionally followed by dot notation for accessing embedded fields of the variable value.
 // Options can be provided to specify how to access fields in embedded array.
 type Expression struct {
-	opts commonpath.FindValuesOpts
-	path types.Path
+	opts     commonpath.FindValuesOpts
+	path     types.Path // empty for variable without dot notation
+	variable string     // CURRENT for field path
 }
 
 // NewExpression returns Expression from dollar sign $ prefixed string.
@@ -99,6 +104,7 @@ func NewExpression(expression string, opts *commonpath.FindValuesOpts) (*Express
 	}
 
 	var val string
+	variable := "CURRENT"
 
 	switch {
 	case strings.HasPrefix(expression, "$$"):
@@ -108,12 +114,28 @@ func NewExpression(expression string, opts *commonpath.FindValuesOpts) (*Express
 			return nil, newExpressionError(ErrEmptyVariable, v)
 		}
 
-		if strings.HasPrefix(v, "$") {
+		variable, val, _ = strings.Cut(v, ".")
+
+		r, _ := utf8.DecodeRuneInString(variable)
+
+		switch {
+		case unicode.IsLower(r) || r >= utf8.RuneSelf:
+			// user variable that may be defined by the enclosing scope
+		case unicode.IsUpper(r):
+			if _, ok := systemVariables[variable]; !ok {
+				return nil, newExpressionError(ErrUndefinedVariable, variable)
+			}
+		default:
 			return nil, newExpressionError(ErrInvalidExpression, v)
 		}
 
-		// TODO https://github.com/FerretDB/FerretDB/issues/2275
-		return nil, newExpressionError(ErrUndefinedVariable, v)
+		if val == "" {
+			return &Expression{
+				variable: variable,
+				opts:     *opts,
+			}, nil
+		}
+
 	case strings.HasPrefix(expression, "$"):
 		// dollar sign $ prefixed string indicates Expression accesses field or embedded fields
 		val = strings.TrimPrefix(expression, "$")
@@ -133,18 +155,62 @@ func NewExpression(expression string, opts *commonpath.FindValuesOpts) (*Express
 	}
 
 	return &Expression{
-		path: path,
-		opts: *opts,
+		path:     path,
+		variable: variable,
+		opts:     *opts,
 	}, nil
 }
 
-// Evaluate uses Expression to find a field value or an embedded field value of the document and
-// returns found value. If values were found from embedded array, it returns *types.Array
-// containing values.
+// Evaluate uses Expression to find a field value or an embedded field value of the document
+// (or of the variable value from the given scope) and returns found value.
+// If values were found from embedded array, it returns *types.Array containing values.
 //
 // It returns error if field value was not found. With embedded array field being exception,
 // that case it returns empty array instead of error.
-func (e *Expression) Evaluate(doc *types.Document) (any, error) {
+// If the variable is not defined, it returns *ExpressionError with ErrUndefinedVariable code.
+func (e *Expression) Evaluate(doc *types.Document, vars *Variables) (any, error) {
+	v, ok := vars.Get(e.variable, doc)
+	if !ok {
+		return nil, newExpressionError(ErrUndefinedVariable, e.variable)
+	}
+
+	if e.path.Len() == 0 {
+		if v == nil {
+			return nil, fmt.Errorf("variable %s is missing", e.variable)
+		}
+
+		return v, nil
+	}
+
+	switch v := v.(type) {
+	case *types.Document:
+		return e.evaluatePath(v)
+
+	case *types.Array:
+		// embedded fields of array elements are returned as an array
+		res := types.MakeArray(v.Len())
+
+		for i := 0; i < v.Len(); i++ {
+			elem, ok := must.NotFail(v.Get(i)).(*types.Document)
+			if !ok {
+				continue
+			}
+
+			if val, err := e.evaluatePath(elem); err == nil {
+				res.Append(val)
+			}
+		}
+
+		return res, nil
+
+	default:
+		return nil, fmt.Errorf("no document found under %s path", e.path)
+	}
+}
+
+// evaluatePath finds a field value or an embedded field value of the document.
+// See Evaluate for details.
+func (e *Expression) evaluatePath(doc *types.Document) (any, error) {
 	path := e.path
 
 	if path.Len() == 1 {
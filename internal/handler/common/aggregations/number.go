@@ -15,57 +15,303 @@
 package aggregations
 
 import (
+	"fmt"
 	"math"
 	"math/big"
+
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
 )
 
 // SumNumbers accumulate numbers and returns the result of summation.
 // The result has the same type as the input, except when the result
 // cannot be presented accurately. Then int32 is converted to int64,
-// and int64 is converted to float64. It ignores non-number values.
+// and int64 is converted to float64. If any value is Decimal128,
+// the result is Decimal128. It ignores non-number values.
 // For empty `vs`, it returns int32(0).
 // This should only be used for aggregation, aggregation does not return
 // error on overflow.
 func SumNumbers(vs ...any) any {
+	var s NumbersSum
+
+	for _, v := range vs {
+		s.Add(v)
+	}
+
+	return s.Result()
+}
+
+// NumbersSum accumulates numbers one by one without keeping them.
+// The result is the same as the result of SumNumbers for all added values.
+//
+// The zero value is an empty sum.
+type NumbersSum struct {
 	// use big.Int to accumulate values larger than math.MaxInt64.
-	intSum := big.NewInt(0)
+	intSum *big.Int
 
 	// handle accumulation of doubles close to max precision.
 	// TODO https://github.com/FerretDB/FerretDB/issues/2300
-	var floatSum float64
+	floatSum float64
+
+	// accumulate all values as decimals after the first Decimal128.
+	decimalSum types.Decimal128
+
+	hasFloat64, hasInt64, hasDecimal bool
+}
+
+// Add adds the value to the sum. It ignores non-number values.
+func (s *NumbersSum) Add(v any) {
+	if s.intSum == nil {
+		s.intSum = big.NewInt(0)
+	}
+
+	switch v := v.(type) {
+	case float64:
+		s.hasFloat64 = true
+
+		if s.hasDecimal {
+			s.decimalSum = s.decimalSum.Add(types.NewDecimal128FromFloat64(v))
+			return
+		}
+
+		s.floatSum = s.floatSum + v
+	case int32:
+		if s.hasDecimal {
+			s.decimalSum = s.decimalSum.Add(types.NewDecimal128FromInt64(int64(v)))
+			return
+		}
+
+		s.intSum.Add(s.intSum, big.NewInt(int64(v)))
+	case int64:
+		s.hasInt64 = true
+
+		if s.hasDecimal {
+			s.decimalSum = s.decimalSum.Add(types.NewDecimal128FromInt64(v))
+			return
+		}
+
+		s.intSum.Add(s.intSum, big.NewInt(v))
+	case types.Decimal128:
+		if !s.hasDecimal {
+			// like MongoDB, switch to decimals only when the first one is added,
+			// converting the sums accumulated so far
+			s.hasDecimal = true
+			s.decimalSum = must.NotFail(types.ParseDecimal128(s.intSum.String())).
+				Add(types.NewDecimal128FromFloat64(s.floatSum))
+		}
+
+		s.decimalSum = s.decimalSum.Add(v)
+	default:
+		// ignore non-number
+	}
+}
+
+// Result returns the sum of all added numbers.
+func (s *NumbersSum) Result() any {
+	if s.intSum == nil {
+		return int32(0)
+	}
+
+	if s.hasDecimal {
+		return s.decimalSum
+	}
+
+	if s.hasFloat64 {
+		// ignore accuracy because there is no rounding from int64.
+		intAsFloat, _ := new(big.Float).SetInt(s.intSum).Float64()
+
+		return intAsFloat + s.floatSum
+	}
+
+	// convert to int32 if input has no int64 and can be represented in int32.
+	return promoteInteger(s.intSum, s.hasInt64)
+}
+
+// SubtractNumbers returns the result of subtraction of number b from number a.
+// The result type is promoted the same way as for SumNumbers.
+// Both values must be numbers.
+func SubtractNumbers(a, b any) any {
+	switch {
+	case isDecimal(a) || isDecimal(b):
+		return NumberToDecimal128(a).Add(NumberToDecimal128(b).Neg())
+
+	case isFloat64(a) || isFloat64(b):
+		return NumberToFloat64(a) - NumberToFloat64(b)
+	}
+
+	res := new(big.Int).Sub(toBigInt(a), toBigInt(b))
+
+	return promoteInteger(res, isInt64(a) || isInt64(b))
+}
+
+// MultiplyNumbers accumulates numbers and returns the result of multiplication.
+// The result type is promoted the same way as for SumNumbers.
+// It ignores non-number values.
+// For empty `vs`, it returns int32(1).
+func MultiplyNumbers(vs ...any) any {
+	intProduct := big.NewInt(1)
+	floatProduct := float64(1)
+	decimalProduct := types.NewDecimal128FromInt64(1)
 
-	var hasFloat64, hasInt64 bool
+	var hasFloat64, hasInt64, hasDecimal bool
 
 	for _, v := range vs {
 		switch v := v.(type) {
 		case float64:
 			hasFloat64 = true
 
-			floatSum = floatSum + v
+			floatProduct *= v
 		case int32:
-			intSum.Add(intSum, big.NewInt(int64(v)))
+			intProduct.Mul(intProduct, big.NewInt(int64(v)))
 		case int64:
 			hasInt64 = true
 
-			intSum.Add(intSum, big.NewInt(v))
+			intProduct.Mul(intProduct, big.NewInt(v))
+		case types.Decimal128:
+			hasDecimal = true
+
+			decimalProduct = decimalProduct.Mul(v)
+
+			continue
 		default:
 			// ignore non-number
+			continue
 		}
+
+		decimalProduct = decimalProduct.Mul(NumberToDecimal128(v))
 	}
 
-	if hasFloat64 || !intSum.IsInt64() {
-		// ignore accuracy because there is no rounding from int64.
-		intAsFloat, _ := new(big.Float).SetInt(intSum).Float64()
+	if hasDecimal {
+		return decimalProduct
+	}
 
-		return intAsFloat + floatSum
+	if hasFloat64 {
+		intAsFloat, _ := new(big.Float).SetInt(intProduct).Float64()
+		return intAsFloat * floatProduct
 	}
 
-	integer := intSum.Int64()
+	return promoteInteger(intProduct, hasInt64)
+}
 
-	if !hasInt64 && integer <= math.MaxInt32 && integer >= math.MinInt32 {
-		// convert to int32 if input has no int64 and can be represented in int32.
-		return int32(integer)
+// DivideNumbers returns the result of division of number a by non-zero number b.
+// The result is Decimal128 if any value is Decimal128, and float64 otherwise.
+// Both values must be numbers.
+func DivideNumbers(a, b any) any {
+	if isDecimal(a) || isDecimal(b) {
+		return NumberToDecimal128(a).Quo(NumberToDecimal128(b))
 	}
 
-	return integer
+	return NumberToFloat64(a) / NumberToFloat64(b)
+}
+
+// ModNumbers returns the remainder of truncated division of number a by non-zero number b.
+// The result has the widest type of both values; the sign of the result is the sign of a.
+// Both values must be numbers.
+func ModNumbers(a, b any) any {
+	switch {
+	case isDecimal(a) || isDecimal(b):
+		return NumberToDecimal128(a).Rem(NumberToDecimal128(b))
+
+	case isFloat64(a) || isFloat64(b):
+		return math.Mod(NumberToFloat64(a), NumberToFloat64(b))
+	}
+
+	res := new(big.Int).Rem(toBigInt(a), toBigInt(b))
+
+	if isInt64(a) || isInt64(b) {
+		return res.Int64()
+	}
+
+	return int32(res.Int64())
+}
+
+// promoteInteger returns the integer as int32 if it fits and long was not requested,
+// as int64 if it fits, or as float64 otherwise.
+func promoteInteger(i *big.Int, long bool) any {
+	if !i.IsInt64() {
+		f, _ := new(big.Float).SetInt(i).Float64()
+		return f
+	}
+
+	res := i.Int64()
+
+	if !long && res <= math.MaxInt32 && res >= math.MinInt32 {
+		return int32(res)
+	}
+
+	return res
+}
+
+// IsNumber returns true if the given value is int32, int64, float64 or Decimal128.
+func IsNumber(v any) bool {
+	switch v.(type) {
+	case float64, int32, int64, types.Decimal128:
+		return true
+	default:
+		return false
+	}
+}
+
+// isDecimal returns true if the given value is Decimal128.
+func isDecimal(v any) bool {
+	_, ok := v.(types.Decimal128)
+	return ok
+}
+
+// isFloat64 returns true if the given value is float64.
+func isFloat64(v any) bool {
+	_, ok := v.(float64)
+	return ok
+}
+
+// isInt64 returns true if the given value is int64.
+func isInt64(v any) bool {
+	_, ok := v.(int64)
+	return ok
+}
+
+// toBigInt converts int32 or int64 number to big.Int.
+func toBigInt(v any) *big.Int {
+	switch v := v.(type) {
+	case int32:
+		return big.NewInt(int64(v))
+	case int64:
+		return big.NewInt(v)
+	default:
+		panic(fmt.Sprintf("unexpected type %T", v))
+	}
+}
+
+// NumberToFloat64 converts number to float64.
+// The value must be a number.
+func NumberToFloat64(v any) float64 {
+	switch v := v.(type) {
+	case float64:
+		return v
+	case int32:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case types.Decimal128:
+		return v.Float64()
+	default:
+		panic(fmt.Sprintf("unexpected type %T", v))
+	}
+}
+
+// NumberToDecimal128 converts number to Decimal128.
+// The value must be a number.
+func NumberToDecimal128(v any) types.Decimal128 {
+	switch v := v.(type) {
+	case float64:
+		return types.NewDecimal128FromFloat64(v)
+	case int32:
+		return types.NewDecimal128FromInt64(int64(v))
+	case int64:
+		return types.NewDecimal128FromInt64(v)
+	case types.Decimal128:
+		return v
+	default:
+		panic(fmt.Sprintf("unexpected type %T", v))
+	}
 }
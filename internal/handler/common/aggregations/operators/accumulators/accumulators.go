@@ -22,24 +22,37 @@
 package accumulators
 
 import (
-	"errors"
 	"fmt"
 
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
 	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
 	"github.com/FerretDB/FerretDB/internal/types"
-	"github.com/FerretDB/FerretDB/internal/util/iterator"
 	"github.com/FerretDB/FerretDB/internal/util/must"
 )
 
 // newAccumulatorFunc is a type for a function that creates an accumulation operator.
-// It takes the arguments extracted from the accumulator document.
-type newAccumulatorFunc func(args ...any) (Accumulator, error)
+// It takes the argument extracted from the accumulator document.
+type newAccumulatorFunc func(arg any) (Accumulator, error)
 
 // Accumulator is a common interface for aggregation accumulation operators.
+//
+// Accumulator holds parsed arguments of the operator and could be used for any number of groups.
+// The state of each group is held by the Accumulation, so only the values required
+// to compute the result are kept in memory, not the whole documents of the group.
 type Accumulator interface {
-	// Accumulate documents and returns the result of applying operator.
-	// It should always close iterator.
-	Accumulate(iter types.DocumentsIterator) (any, error)
+	// NewAccumulation returns a new empty accumulation for a single group of documents.
+	// Values kept by the accumulation are added to the given memory usage of the stage.
+	NewAccumulation(memory *MemoryUsage) Accumulation
+}
+
+// Accumulation accumulates documents of a single group.
+type Accumulation interface {
+	// Add adds the document of the group to the accumulation.
+	// Expressions are evaluated with the given scope of variables.
+	Add(doc *types.Document, vars *aggregations.Variables) error
+
+	// Result returns the result of accumulation of all added documents.
+	Result() any
 }
 
 // NewAccumulator returns accumulator for provided value.
@@ -64,46 +77,67 @@ func NewAccumulator(stage, key string, value any) (Accumulator, error) {
 
 	operator := accumulation.Command()
 
-	expr := must.NotFail(accumulation.Get(operator))
-
-	var args []any
-
-	switch expr := expr.(type) {
-	case *types.Document:
-		args = append(args, expr)
-	case *types.Array:
-		iter := expr.Iterator()
-		defer iter.Close()
-
-		for {
-			_, v, err := iter.Next()
-
-			if errors.Is(err, iterator.ErrIteratorDone) {
-				break
-			}
-
-			args = append(args, v)
+	newAccumulator, ok := Accumulators[operator]
+	if !ok {
+		if _, ok = unsupportedAccumulators[operator]; ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrNotImplemented,
+				fmt.Sprintf("%s accumulator %q is not implemented yet", stage, operator),
+				operator+" (accumulator)",
+			)
 		}
-	default:
-		args = append(args, expr)
+
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrStageGroupUnknownAccumulator,
+			fmt.Sprintf("unknown group operator '%s'", operator),
+			stage+" (stage)",
+		)
 	}
 
-	newAccumulator, ok := Accumulators[operator]
-	if !ok {
+	expr := must.NotFail(accumulation.Get(operator))
+
+	if _, ok = expr.(*types.Array); ok {
 		return nil, handlererrors.NewCommandErrorMsgWithArgument(
-			handlererrors.ErrNotImplemented,
-			fmt.Sprintf("%s accumulator %q is not implemented yet", stage, operator),
+			handlererrors.ErrStageGroupUnaryOperator,
+			fmt.Sprintf("The %s accumulator is a unary operator", operator),
 			operator+" (accumulator)",
 		)
 	}
 
-	return newAccumulator(args...)
+	return newAccumulator(expr)
 }
 
 // Accumulators maps all aggregation accumulators.
 var Accumulators = map[string]newAccumulatorFunc{
 	// sorted alphabetically
-	"$count": newCount,
-	"$sum":   newSum,
+	"$addToSet":     newAddToSet,
+	"$avg":          newAvg,
+	"$bottom":       newTopBottomFunc("$bottom", false, false),
+	"$bottomN":      newTopBottomFunc("$bottomN", false, true),
+	"$count":        newCount,
+	"$first":        newFirstLastFunc(true),
+	"$firstN":       newFirstLastNFunc("$firstN", true),
+	"$last":         newFirstLastFunc(false),
+	"$lastN":        newFirstLastNFunc("$lastN", false),
+	"$max":          newMinMaxFunc(false),
+	"$maxN":         newMinMaxNFunc("$maxN", false),
+	"$mergeObjects": newMergeObjects,
+	"$min":          newMinMaxFunc(true),
+	"$minN":         newMinMaxNFunc("$minN", true),
+	"$push":         newPush,
+	"$stdDevPop":    newStdDevFunc(false),
+	"$stdDevSamp":   newStdDevFunc(true),
+	"$sum":          newSum,
+	"$top":          newTopBottomFunc("$top", true, false),
+	"$topN":         newTopBottomFunc("$topN", true, true),
+	// please keep sorted alphabetically
+}
+
+// unsupportedAccumulators maps all unsupported yet accumulators.
+var unsupportedAccumulators = map[string]struct{}{
+	// sorted alphabetically
+	"$accumulator": {},
+	"$median":      {},
+	"$percentile":  {},
 	// please keep sorted alphabetically
 }
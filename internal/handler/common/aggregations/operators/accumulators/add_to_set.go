@@ -0,0 +1,92 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accumulators
+
+import (
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// addToSet represents $addToSet aggregation operator.
+type addToSet struct {
+	expression *expression
+}
+
+// newAddToSet creates a new $addToSet aggregation operator.
+func newAddToSet(arg any) (Accumulator, error) {
+	expr, err := newExpression(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &addToSet{
+		expression: expr,
+	}, nil
+}
+
+// NewAccumulation implements Accumulator interface.
+func (a *addToSet) NewAccumulation(memory *MemoryUsage) Accumulation {
+	return &addToSetAccumulation{
+		addToSet: a,
+		memory:   memory,
+	}
+}
+
+// addToSetAccumulation represents the state of $addToSet operator.
+type addToSetAccumulation struct {
+	*addToSet
+	values []any
+	memory *MemoryUsage
+}
+
+// Add implements Accumulation interface.
+//
+// Missing values and values equal to already added ones are ignored.
+func (a *addToSetAccumulation) Add(doc *types.Document, vars *aggregations.Variables) error {
+	v, err := a.expression.evaluate(doc, vars)
+	if err != nil {
+		return err
+	}
+
+	if v == nil {
+		return nil
+	}
+
+	for _, existing := range a.values {
+		if types.CompareForAggregation(existing, v) == types.Equal {
+			return nil
+		}
+	}
+
+	if err = a.memory.Add(v); err != nil {
+		return err
+	}
+
+	a.values = append(a.values, v)
+
+	return nil
+}
+
+// Result implements Accumulation interface.
+func (a *addToSetAccumulation) Result() any {
+	return must.NotFail(types.NewArray(a.values...))
+}
+
+// check interfaces
+var (
+	_ Accumulator  = (*addToSet)(nil)
+	_ Accumulation = (*addToSetAccumulation)(nil)
+)
@@ -0,0 +1,85 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accumulators
+
+import (
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+// avg represents $avg aggregation operator.
+type avg struct {
+	expression *expression
+}
+
+// newAvg creates a new $avg aggregation operator.
+func newAvg(arg any) (Accumulator, error) {
+	expr, err := newExpression(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &avg{
+		expression: expr,
+	}, nil
+}
+
+// NewAccumulation implements Accumulator interface.
+func (a *avg) NewAccumulation(memory *MemoryUsage) Accumulation {
+	return &avgAccumulation{
+		avg: a,
+	}
+}
+
+// avgAccumulation represents the state of $avg operator.
+type avgAccumulation struct {
+	*avg
+	numbers aggregations.NumbersSum
+	count   int64
+}
+
+// Add implements Accumulation interface.
+//
+// Non-numeric and missing values are ignored.
+func (a *avgAccumulation) Add(doc *types.Document, vars *aggregations.Variables) error {
+	v, err := a.expression.evaluate(doc, vars)
+	if err != nil {
+		return err
+	}
+
+	if aggregations.IsNumber(v) {
+		a.numbers.Add(v)
+		a.count++
+	}
+
+	return nil
+}
+
+// Result implements Accumulation interface.
+//
+// It returns null if there were no numeric values.
+func (a *avgAccumulation) Result() any {
+	if a.count == 0 {
+		return types.Null
+	}
+
+	return aggregations.DivideNumbers(a.numbers.Result(), a.count)
+}
+
+// check interfaces
+var (
+	_ Accumulator  = (*avg)(nil)
+	_ Accumulation = (*avgAccumulation)(nil)
+)
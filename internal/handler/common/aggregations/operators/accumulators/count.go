@@ -15,27 +15,17 @@
 package accumulators
 
 import (
-	"errors"
-
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
 	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
 	"github.com/FerretDB/FerretDB/internal/types"
-	"github.com/FerretDB/FerretDB/internal/util/iterator"
 )
 
 // count represents $count operator.
 type count struct{}
 
 // newCount creates a new $count aggregation operator.
-func newCount(args ...any) (Accumulator, error) {
-	if len(args) != 1 {
-		return nil, handlererrors.NewCommandErrorMsgWithArgument(
-			handlererrors.ErrStageGroupUnaryOperator,
-			"The $count accumulator is a unary operator",
-			"$count (accumulator)",
-		)
-	}
-
-	doc, ok := args[0].(*types.Document)
+func newCount(arg any) (Accumulator, error) {
+	doc, ok := arg.(*types.Document)
 
 	if !ok || doc.Len() > 0 {
 		return nil, handlererrors.NewCommandErrorMsgWithArgument(
@@ -48,27 +38,29 @@ func newCount(args ...any) (Accumulator, error) {
 	return new(count), nil
 }
 
-// Accumulate implements Accumulator interface.
-func (c *count) Accumulate(iter types.DocumentsIterator) (any, error) {
-	defer iter.Close()
-	var count int32
+// NewAccumulation implements Accumulator interface.
+func (c *count) NewAccumulation(memory *MemoryUsage) Accumulation {
+	return new(countAccumulation)
+}
 
-	for {
-		_, _, err := iter.Next()
-		if err != nil {
-			if errors.Is(err, iterator.ErrIteratorDone) {
-				break
-			}
+// countAccumulation represents the state of $count operator.
+type countAccumulation struct {
+	count int32
+}
 
-			return nil, err
-		}
-		count++
-	}
+// Add implements Accumulation interface.
+func (c *countAccumulation) Add(*types.Document, *aggregations.Variables) error {
+	c.count++
+	return nil
+}
 
-	return count, nil
+// Result implements Accumulation interface.
+func (c *countAccumulation) Result() any {
+	return c.count
 }
 
 // check interfaces
 var (
-	_ Accumulator = (*count)(nil)
+	_ Accumulator  = (*count)(nil)
+	_ Accumulation = (*countAccumulation)(nil)
 )
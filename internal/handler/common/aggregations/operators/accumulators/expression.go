@@ -0,0 +1,55 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accumulators
+
+import (
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations/operators"
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+// expression represents an argument of accumulator that is evaluated for each document,
+// like `$field` path, operator, or literal value.
+type expression struct {
+	value any
+}
+
+// newExpression validates the argument of accumulator and returns it as expression.
+//
+// Errors of operators and path expressions are returned as is,
+// so they could be processed by the stage.
+func newExpression(value any) (*expression, error) {
+	if err := operators.ValidateExpression(value); err != nil {
+		return nil, err
+	}
+
+	return &expression{value: value}, nil
+}
+
+// evaluate returns the value of expression for the given document.
+// It returns nil for a missing value.
+func (e *expression) evaluate(doc *types.Document, vars *aggregations.Variables) (any, error) {
+	return operators.Evaluate(e.value, doc, vars)
+}
+
+// isNullish returns true if the value is missing or null.
+func isNullish(v any) bool {
+	switch v.(type) {
+	case nil, types.NullType:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accumulators
+
+import (
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+// firstLast represents $first and $last aggregation operators.
+type firstLast struct {
+	expression *expression
+	first      bool
+}
+
+// newFirstLastFunc returns a function that creates $first or $last aggregation operator.
+func newFirstLastFunc(first bool) newAccumulatorFunc {
+	return func(arg any) (Accumulator, error) {
+		expr, err := newExpression(arg)
+		if err != nil {
+			return nil, err
+		}
+
+		return &firstLast{
+			expression: expr,
+			first:      first,
+		}, nil
+	}
+}
+
+// NewAccumulation implements Accumulator interface.
+func (f *firstLast) NewAccumulation(memory *MemoryUsage) Accumulation {
+	return &firstLastAccumulation{
+		firstLast: f,
+		memory:    memory,
+	}
+}
+
+// firstLastAccumulation represents the state of $first and $last operators.
+type firstLastAccumulation struct {
+	*firstLast
+	value  any
+	set    bool
+	memory *MemoryUsage
+}
+
+// Add implements Accumulation interface.
+//
+// Missing value is accumulated as null.
+func (f *firstLastAccumulation) Add(doc *types.Document, vars *aggregations.Variables) error {
+	if f.first && f.set {
+		return nil
+	}
+
+	v, err := f.expression.evaluate(doc, vars)
+	if err != nil {
+		return err
+	}
+
+	if v == nil {
+		v = types.Null
+	}
+
+	if f.set {
+		f.memory.Remove(f.value)
+	}
+
+	if err = f.memory.Add(v); err != nil {
+		return err
+	}
+
+	f.value = v
+	f.set = true
+
+	return nil
+}
+
+// Result implements Accumulation interface.
+func (f *firstLastAccumulation) Result() any {
+	if !f.set {
+		return types.Null
+	}
+
+	return f.value
+}
+
+// check interfaces
+var (
+	_ Accumulator  = (*firstLast)(nil)
+	_ Accumulation = (*firstLastAccumulation)(nil)
+)
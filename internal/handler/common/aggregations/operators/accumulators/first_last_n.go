@@ -0,0 +1,107 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accumulators
+
+import (
+	"slices"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// firstLastN represents $firstN and $lastN aggregation operators.
+type firstLastN struct {
+	name  string
+	input *expression
+	n     int64
+	first bool
+}
+
+// newFirstLastNFunc returns a function that creates $firstN or $lastN aggregation operator.
+func newFirstLastNFunc(name string, first bool) newAccumulatorFunc {
+	return func(arg any) (Accumulator, error) {
+		input, n, err := parseInputN(name, handlererrors.ErrFirstLastNNotObject, arg)
+		if err != nil {
+			return nil, err
+		}
+
+		return &firstLastN{
+			name:  name,
+			input: input,
+			n:     n,
+			first: first,
+		}, nil
+	}
+}
+
+// NewAccumulation implements Accumulator interface.
+func (f *firstLastN) NewAccumulation(memory *MemoryUsage) Accumulation {
+	return &firstLastNAccumulation{
+		firstLastN: f,
+		memory:     memory,
+	}
+}
+
+// firstLastNAccumulation represents the state of $firstN and $lastN operators.
+type firstLastNAccumulation struct {
+	*firstLastN
+	values []any
+	memory *MemoryUsage
+}
+
+// Add implements Accumulation interface.
+//
+// Missing value is accumulated as null.
+// Only n first or last values are kept.
+func (f *firstLastNAccumulation) Add(doc *types.Document, vars *aggregations.Variables) error {
+	if f.first && int64(len(f.values)) == f.n {
+		return nil
+	}
+
+	v, err := f.input.evaluate(doc, vars)
+	if err != nil {
+		return err
+	}
+
+	if v == nil {
+		v = types.Null
+	}
+
+	if err = f.memory.Add(v); err != nil {
+		return err
+	}
+
+	f.values = append(f.values, v)
+
+	if int64(len(f.values)) > f.n {
+		f.memory.Remove(f.values[0])
+		f.values = slices.Delete(f.values, 0, 1)
+	}
+
+	return nil
+}
+
+// Result implements Accumulation interface.
+func (f *firstLastNAccumulation) Result() any {
+	return must.NotFail(types.NewArray(f.values...))
+}
+
+// check interfaces
+var (
+	_ Accumulator  = (*firstLastN)(nil)
+	_ Accumulation = (*firstLastNAccumulation)(nil)
+)
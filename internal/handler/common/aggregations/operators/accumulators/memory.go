@@ -0,0 +1,118 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accumulators
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+)
+
+// maxMemoryBytes is the maximum memory usage of a stage that keeps groups of documents, like `$group`.
+const maxMemoryBytes = 100 * 1024 * 1024
+
+// MemoryUsage tracks approximate memory usage of a stage that keeps groups of documents:
+// group keys and values kept by all accumulations of all groups, like `$push` or `$addToSet`.
+type MemoryUsage struct {
+	stage string
+	bytes int
+}
+
+// NewMemoryUsage returns a new memory usage tracker for the given stage.
+func NewMemoryUsage(stage string) *MemoryUsage {
+	return &MemoryUsage{
+		stage: stage,
+	}
+}
+
+// Add adds the size of the value that is kept to memory usage.
+// It returns an error if the limit is exceeded.
+func (m *MemoryUsage) Add(v any) error {
+	m.bytes += valueSize(v)
+
+	if m.bytes > maxMemoryBytes {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrExceededMemoryLimit,
+			fmt.Sprintf(
+				"%s used too much memory and cannot spill to disk. Memory limit: %d bytes",
+				m.stage, maxMemoryBytes,
+			),
+			m.stage+" (stage)",
+		)
+	}
+
+	return nil
+}
+
+// Remove subtracts the size of the value that is not kept anymore from memory usage.
+func (m *MemoryUsage) Remove(v any) {
+	m.bytes -= valueSize(v)
+}
+
+// valueSize returns the approximate size of the value in BSON encoding.
+func valueSize(v any) int {
+	switch v := v.(type) {
+	case *types.Document:
+		size := 5
+
+		iter := v.Iterator()
+		defer iter.Close()
+
+		for {
+			k, v, err := iter.Next()
+			if errors.Is(err, iterator.ErrIteratorDone) {
+				break
+			}
+
+			size += 2 + len(k) + valueSize(v)
+		}
+
+		return size
+
+	case *types.Array:
+		size := 5
+
+		for i := 0; i < v.Len(); i++ {
+			elem, _ := v.Get(i)
+			size += 2 + len(strconv.Itoa(i)) + valueSize(elem)
+		}
+
+		return size
+
+	case string:
+		return 5 + len(v)
+	case types.Binary:
+		return 5 + len(v.B)
+	case types.Regex:
+		return 2 + len(v.Pattern) + len(v.Options)
+	case types.Decimal128:
+		return 16
+	case types.ObjectID:
+		return types.ObjectIDLen
+	case int32:
+		return 4
+	case bool:
+		return 1
+	case types.NullType, types.MinKeyType, types.MaxKeyType, nil:
+		return 0
+	default:
+		// float64, int64, time.Time, types.Timestamp
+		return 8
+	}
+}
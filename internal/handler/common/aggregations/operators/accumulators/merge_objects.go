@@ -0,0 +1,128 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accumulators
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+)
+
+// mergeObjects represents $mergeObjects aggregation operator.
+type mergeObjects struct {
+	expression *expression
+}
+
+// newMergeObjects creates a new $mergeObjects aggregation operator.
+func newMergeObjects(arg any) (Accumulator, error) {
+	expr, err := newExpression(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mergeObjects{
+		expression: expr,
+	}, nil
+}
+
+// NewAccumulation implements Accumulator interface.
+func (m *mergeObjects) NewAccumulation(memory *MemoryUsage) Accumulation {
+	return &mergeObjectsAccumulation{
+		mergeObjects: m,
+		res:          new(types.Document),
+		memory:       memory,
+	}
+}
+
+// mergeObjectsAccumulation represents the state of $mergeObjects operator.
+type mergeObjectsAccumulation struct {
+	*mergeObjects
+	res    *types.Document
+	memory *MemoryUsage
+}
+
+// Add implements Accumulation interface.
+//
+// Null and missing values are ignored.
+// Fields of later documents overwrite fields with the same name of earlier documents.
+func (m *mergeObjectsAccumulation) Add(doc *types.Document, vars *aggregations.Variables) error {
+	v, err := m.expression.evaluate(doc, vars)
+	if err != nil {
+		return err
+	}
+
+	if isNullish(v) {
+		return nil
+	}
+
+	d, ok := v.(*types.Document)
+	if !ok {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrMergeObjectsNotObject,
+			fmt.Sprintf(
+				"$mergeObjects requires object inputs, but input %s is of type %s",
+				types.FormatAnyValue(v), handlerparams.AliasFromType(v),
+			),
+			"$mergeObjects (accumulator)",
+		)
+	}
+
+	iter := d.Iterator()
+	defer iter.Close()
+
+	for {
+		k, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if old, err := m.res.Get(k); err == nil {
+			m.memory.Remove(k)
+			m.memory.Remove(old)
+		}
+
+		if err = m.memory.Add(k); err != nil {
+			return err
+		}
+
+		if err = m.memory.Add(v); err != nil {
+			return err
+		}
+
+		m.res.Set(k, v)
+	}
+
+	return nil
+}
+
+// Result implements Accumulation interface.
+func (m *mergeObjectsAccumulation) Result() any {
+	return m.res
+}
+
+// check interfaces
+var (
+	_ Accumulator  = (*mergeObjects)(nil)
+	_ Accumulation = (*mergeObjectsAccumulation)(nil)
+)
@@ -0,0 +1,103 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accumulators
+
+import (
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+// minMax represents $min and $max aggregation operators.
+type minMax struct {
+	expression *expression
+	min        bool
+}
+
+// newMinMaxFunc returns a function that creates $min or $max aggregation operator.
+func newMinMaxFunc(min bool) newAccumulatorFunc {
+	return func(arg any) (Accumulator, error) {
+		expr, err := newExpression(arg)
+		if err != nil {
+			return nil, err
+		}
+
+		return &minMax{
+			expression: expr,
+			min:        min,
+		}, nil
+	}
+}
+
+// NewAccumulation implements Accumulator interface.
+func (m *minMax) NewAccumulation(memory *MemoryUsage) Accumulation {
+	return &minMaxAccumulation{
+		minMax: m,
+		memory: memory,
+	}
+}
+
+// minMaxAccumulation represents the state of $min and $max operators.
+type minMaxAccumulation struct {
+	*minMax
+	value  any
+	memory *MemoryUsage
+}
+
+// Add implements Accumulation interface.
+//
+// Null and missing values are ignored.
+// Values of different types are compared using BSON comparison order.
+func (m *minMaxAccumulation) Add(doc *types.Document, vars *aggregations.Variables) error {
+	v, err := m.expression.evaluate(doc, vars)
+	if err != nil {
+		return err
+	}
+
+	if isNullish(v) {
+		return nil
+	}
+
+	if m.value != nil {
+		res := types.CompareForAggregation(v, m.value)
+		if (m.min && res != types.Less) || (!m.min && res != types.Greater) {
+			return nil
+		}
+
+		m.memory.Remove(m.value)
+	}
+
+	if err = m.memory.Add(v); err != nil {
+		return err
+	}
+
+	m.value = v
+
+	return nil
+}
+
+// Result implements Accumulation interface.
+func (m *minMaxAccumulation) Result() any {
+	if m.value == nil {
+		return types.Null
+	}
+
+	return m.value
+}
+
+// check interfaces
+var (
+	_ Accumulator  = (*minMax)(nil)
+	_ Accumulation = (*minMaxAccumulation)(nil)
+)
@@ -0,0 +1,118 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accumulators
+
+import (
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// minMaxN represents $minN and $maxN aggregation operators.
+type minMaxN struct {
+	name  string
+	input *expression
+	n     int64
+	min   bool
+}
+
+// newMinMaxNFunc returns a function that creates $minN or $maxN aggregation operator.
+func newMinMaxNFunc(name string, min bool) newAccumulatorFunc {
+	return func(arg any) (Accumulator, error) {
+		input, n, err := parseInputN(name, handlererrors.ErrMinMaxNNotObject, arg)
+		if err != nil {
+			return nil, err
+		}
+
+		return &minMaxN{
+			name:  name,
+			input: input,
+			n:     n,
+			min:   min,
+		}, nil
+	}
+}
+
+// NewAccumulation implements Accumulator interface.
+func (m *minMaxN) NewAccumulation(memory *MemoryUsage) Accumulation {
+	return &minMaxNAccumulation{
+		minMaxN: m,
+		memory:  memory,
+	}
+}
+
+// minMaxNAccumulation represents the state of $minN and $maxN operators.
+type minMaxNAccumulation struct {
+	*minMaxN
+	values []any
+	memory *MemoryUsage
+}
+
+// Add implements Accumulation interface.
+//
+// Null and missing values are ignored.
+// Only n minimal or maximal values are kept, sorted in ascending or descending order respectively.
+func (m *minMaxNAccumulation) Add(doc *types.Document, vars *aggregations.Variables) error {
+	v, err := m.input.evaluate(doc, vars)
+	if err != nil {
+		return err
+	}
+
+	if isNullish(v) {
+		return nil
+	}
+
+	if err = m.memory.Add(v); err != nil {
+		return err
+	}
+
+	m.values = insertSorted(m.values, v, m.compare)
+
+	if int64(len(m.values)) > m.n {
+		m.memory.Remove(m.values[m.n])
+		m.values = m.values[:m.n]
+	}
+
+	return nil
+}
+
+// compare compares values so the preferred value is less.
+func (m *minMaxNAccumulation) compare(a, b any) types.CompareResult {
+	res := types.CompareForAggregation(a, b)
+	if m.min {
+		return res
+	}
+
+	switch res {
+	case types.Less:
+		return types.Greater
+	case types.Greater:
+		return types.Less
+	default:
+		return res
+	}
+}
+
+// Result implements Accumulation interface.
+func (m *minMaxNAccumulation) Result() any {
+	return must.NotFail(types.NewArray(m.values...))
+}
+
+// check interfaces
+var (
+	_ Accumulator  = (*minMaxN)(nil)
+	_ Accumulation = (*minMaxNAccumulation)(nil)
+)
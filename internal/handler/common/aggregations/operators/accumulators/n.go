@@ -0,0 +1,139 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accumulators
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+// parseInputN parses the argument `{input: <expression>, n: <expression>}`
+// of $firstN, $lastN, $minN and $maxN operators.
+//
+// The notObjectCode is returned if the argument is not a document.
+func parseInputN(name string, notObjectCode handlererrors.ErrorCode, arg any) (*expression, int64, error) {
+	doc, ok := arg.(*types.Document)
+	if !ok {
+		return nil, 0, handlererrors.NewCommandErrorMsgWithArgument(
+			notObjectCode,
+			fmt.Sprintf("specification must be an object; found %s: %s", name, types.FormatAnyValue(arg)),
+			name+" (accumulator)",
+		)
+	}
+
+	for _, k := range doc.Keys() {
+		if k != "input" && k != "n" {
+			return nil, 0, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrAccumulatorNUnknownField,
+				fmt.Sprintf("Unknown argument for 'n' operator: %s", k),
+				name+" (accumulator)",
+			)
+		}
+	}
+
+	nValue, err := doc.Get("n")
+	if err != nil {
+		return nil, 0, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrAccumulatorNMissingN,
+			"Missing value for 'n'",
+			name+" (accumulator)",
+		)
+	}
+
+	inputValue, err := doc.Get("input")
+	if err != nil {
+		return nil, 0, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrAccumulatorNMissingInput,
+			"Missing value for 'input'",
+			name+" (accumulator)",
+		)
+	}
+
+	n, err := parseN(name, nValue)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	input, err := newExpression(inputValue)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return input, n, nil
+}
+
+// parseN evaluates and validates the value of `n` argument of accumulator.
+//
+// The value must be a constant expression that evaluates to a positive integral number.
+func parseN(name string, value any) (int64, error) {
+	expr, err := newExpression(value)
+	if err != nil {
+		return 0, err
+	}
+
+	v, err := expr.evaluate(new(types.Document), nil)
+	if err != nil {
+		return 0, err
+	}
+
+	if !aggregations.IsNumber(v) {
+		found := "missing"
+		if v != nil {
+			found = types.FormatAnyValue(v)
+		}
+
+		return 0, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrAccumulatorNNonNumeric,
+			fmt.Sprintf("Value for 'n' must be of integral type, but found %s", found),
+			name+" (accumulator)",
+		)
+	}
+
+	n, err := handlerparams.GetWholeNumberParam(v)
+	if err != nil {
+		return 0, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrAccumulatorNNonIntegral,
+			fmt.Sprintf("Value for 'n' must be of integral type, but found %s", types.FormatAnyValue(v)),
+			name+" (accumulator)",
+		)
+	}
+
+	if n <= 0 {
+		return 0, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrAccumulatorNNonPositive,
+			fmt.Sprintf("'n' must be greater than 0, found %d", n),
+			name+" (accumulator)",
+		)
+	}
+
+	return min(n, math.MaxInt32), nil
+}
+
+// insertSorted inserts the value into the slice sorted by cmp function
+// after all values that are equal to it, and returns the updated slice.
+func insertSorted[T any](s []T, v T, cmp func(a, b T) types.CompareResult) []T {
+	i := sort.Search(len(s), func(i int) bool {
+		return cmp(s[i], v) == types.Greater
+	})
+
+	return slices.Insert(s, i, v)
+}
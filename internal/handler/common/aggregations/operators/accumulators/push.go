@@ -0,0 +1,86 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accumulators
+
+import (
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// push represents $push aggregation operator.
+type push struct {
+	expression *expression
+}
+
+// newPush creates a new $push aggregation operator.
+func newPush(arg any) (Accumulator, error) {
+	expr, err := newExpression(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &push{
+		expression: expr,
+	}, nil
+}
+
+// NewAccumulation implements Accumulator interface.
+func (p *push) NewAccumulation(memory *MemoryUsage) Accumulation {
+	return &pushAccumulation{
+		push:   p,
+		memory: memory,
+	}
+}
+
+// pushAccumulation represents the state of $push operator.
+type pushAccumulation struct {
+	*push
+	values []any
+	memory *MemoryUsage
+}
+
+// Add implements Accumulation interface.
+//
+// Missing values are ignored.
+func (p *pushAccumulation) Add(doc *types.Document, vars *aggregations.Variables) error {
+	v, err := p.expression.evaluate(doc, vars)
+	if err != nil {
+		return err
+	}
+
+	if v == nil {
+		return nil
+	}
+
+	if err = p.memory.Add(v); err != nil {
+		return err
+	}
+
+	p.values = append(p.values, v)
+
+	return nil
+}
+
+// Result implements Accumulation interface.
+func (p *pushAccumulation) Result() any {
+	return must.NotFail(types.NewArray(p.values...))
+}
+
+// check interfaces
+var (
+	_ Accumulator  = (*push)(nil)
+	_ Accumulation = (*pushAccumulation)(nil)
+)
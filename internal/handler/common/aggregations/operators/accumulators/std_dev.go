@@ -0,0 +1,109 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accumulators
+
+import (
+	"math"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+// stdDev represents $stdDevPop and $stdDevSamp aggregation operators.
+type stdDev struct {
+	expression *expression
+	sample     bool
+}
+
+// newStdDevFunc returns a function that creates $stdDevPop or $stdDevSamp aggregation operator.
+func newStdDevFunc(sample bool) newAccumulatorFunc {
+	return func(arg any) (Accumulator, error) {
+		expr, err := newExpression(arg)
+		if err != nil {
+			return nil, err
+		}
+
+		return &stdDev{
+			expression: expr,
+			sample:     sample,
+		}, nil
+	}
+}
+
+// NewAccumulation implements Accumulator interface.
+func (s *stdDev) NewAccumulation(memory *MemoryUsage) Accumulation {
+	return &stdDevAccumulation{
+		stdDev: s,
+	}
+}
+
+// stdDevAccumulation represents the state of $stdDevPop and $stdDevSamp operators.
+//
+// It uses Welford's online algorithm, so values are not kept in memory.
+type stdDevAccumulation struct {
+	*stdDev
+	count int64
+	mean  float64
+	m2    float64
+}
+
+// Add implements Accumulation interface.
+//
+// Non-numeric and missing values are ignored.
+func (s *stdDevAccumulation) Add(doc *types.Document, vars *aggregations.Variables) error {
+	v, err := s.expression.evaluate(doc, vars)
+	if err != nil {
+		return err
+	}
+
+	if !aggregations.IsNumber(v) {
+		return nil
+	}
+
+	f := aggregations.NumberToFloat64(v)
+
+	s.count++
+	delta := f - s.mean
+	s.mean += delta / float64(s.count)
+	s.m2 += delta * (f - s.mean)
+
+	return nil
+}
+
+// Result implements Accumulation interface.
+//
+// It returns null if there were no numeric values,
+// or less than two numeric values for sample standard deviation.
+func (s *stdDevAccumulation) Result() any {
+	if s.sample {
+		if s.count < 2 {
+			return types.Null
+		}
+
+		return math.Sqrt(s.m2 / float64(s.count-1))
+	}
+
+	if s.count == 0 {
+		return types.Null
+	}
+
+	return math.Sqrt(s.m2 / float64(s.count))
+}
+
+// check interfaces
+var (
+	_ Accumulator  = (*stdDev)(nil)
+	_ Accumulation = (*stdDevAccumulation)(nil)
+)
@@ -15,126 +15,64 @@
 package accumulators
 
 import (
-	"errors"
-
 	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
-	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations/operators"
-	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
 	"github.com/FerretDB/FerretDB/internal/types"
-	"github.com/FerretDB/FerretDB/internal/util/iterator"
-	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
 )
 
 // sum represents $sum aggregation operator.
 type sum struct {
-	expression *aggregations.Expression
-	operator   operators.Operator
-	number     any
+	expression *expression
 }
 
 // newSum creates a new $sum aggregation operator.
-func newSum(args ...any) (Accumulator, error) {
-	accumulator := new(sum)
-
-	if len(args) != 1 {
-		return nil, handlererrors.NewCommandErrorMsgWithArgument(
-			handlererrors.ErrStageGroupUnaryOperator,
-			"The $sum accumulator is a unary operator",
-			"$sum (accumulator)",
-		)
+func newSum(arg any) (Accumulator, error) {
+	expr, err := newExpression(arg)
+	if err != nil {
+		return nil, err
 	}
 
-	for _, arg := range args {
-		switch arg := arg.(type) {
-		case *types.Document:
-			if !operators.IsOperator(arg) {
-				accumulator.number = int32(0)
-				break
-			}
-
-			op, err := operators.NewOperator(arg)
-			if err != nil {
-				var opErr operators.OperatorError
-				if !errors.As(err, &opErr) {
-					return nil, lazyerrors.Error(err)
-				}
-
-				return nil, opErr
-			}
+	return &sum{
+		expression: expr,
+	}, nil
+}
 
-			accumulator.operator = op
-		case float64:
-			accumulator.number = arg
-		case string:
-			var err error
-			if accumulator.expression, err = aggregations.NewExpression(arg, nil); err != nil {
-				// $sum returns 0 on non-existent field.
-				accumulator.number = int32(0)
-			}
-		case int32, int64:
-			accumulator.number = arg
-		default:
-			accumulator.number = int32(0)
-			// $sum returns 0 on non-numeric field
-		}
+// NewAccumulation implements Accumulator interface.
+func (s *sum) NewAccumulation(memory *MemoryUsage) Accumulation {
+	return &sumAccumulation{
+		sum: s,
 	}
-
-	return accumulator, nil
 }
 
-// Accumulate implements Accumulator interface.
-func (s *sum) Accumulate(iter types.DocumentsIterator) (any, error) {
-	var numbers []any
-
-	for {
-		_, doc, err := iter.Next()
-
-		if errors.Is(err, iterator.ErrIteratorDone) {
-			break
-		}
-
-		if err != nil {
-			return nil, lazyerrors.Error(err)
-		}
-
-		switch {
-		case s.operator != nil:
-			v, err := s.operator.Process(doc)
-			if err != nil {
-				return nil, err
-			}
-
-			numbers = append(numbers, v)
-
-			continue
-
-		case s.expression != nil:
-			value, err := s.expression.Evaluate(doc)
+// sumAccumulation represents the state of $sum operator.
+type sumAccumulation struct {
+	*sum
+	numbers aggregations.NumbersSum
+}
 
-			// sum fields that exist
-			if err == nil {
-				numbers = append(numbers, value)
-			}
+// Add implements Accumulation interface.
+//
+// Non-numeric and missing values are ignored.
+// For a number, the result is equivalent to the number multiplied by the count of documents,
+// with conversion handled upon overflow of int32 and int64.
+// For example, { $sum: 1 } is equivalent of { $count: { } }.
+func (s *sumAccumulation) Add(doc *types.Document, vars *aggregations.Variables) error {
+	v, err := s.expression.evaluate(doc, vars)
+	if err != nil {
+		return err
+	}
 
-			continue
-		}
+	s.numbers.Add(v)
 
-		switch number := s.number.(type) {
-		case float64, int32, int64:
-			// For number types, the result is equivalent of iterator len*number,
-			// with conversion handled upon overflow of int32 and int64.
-			// For example, { $sum: 1 } is equivalent of { $count: { } }.
-			numbers = append(numbers, number)
-		default:
-			// $sum returns 0 on non-existent and non-numeric field.
-			return int32(0), nil
-		}
-	}
+	return nil
+}
 
-	return aggregations.SumNumbers(numbers...), nil
+// Result implements Accumulation interface.
+func (s *sumAccumulation) Result() any {
+	return s.numbers.Result()
 }
 
 // check interfaces
 var (
-	_ Accumulator = (*sum)(nil)
+	_ Accumulator  = (*sum)(nil)
+	_ Accumulation = (*sumAccumulation)(nil)
 )
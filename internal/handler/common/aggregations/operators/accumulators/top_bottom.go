@@ -0,0 +1,247 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accumulators
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// sortKey represents a single field of `sortBy` argument.
+type sortKey struct {
+	path  types.Path
+	order types.SortType
+}
+
+// topBottom represents $top, $bottom, $topN and $bottomN aggregation operators.
+type topBottom struct {
+	name   string
+	output *expression
+	sortBy []sortKey
+	n      int64
+	top    bool
+	multi  bool
+}
+
+// newTopBottomFunc returns a function that creates $top, $bottom, $topN or $bottomN aggregation operator.
+// The multi is true for operators that take `n` argument and return an array.
+func newTopBottomFunc(name string, top, multi bool) newAccumulatorFunc {
+	return func(arg any) (Accumulator, error) {
+		doc, ok := arg.(*types.Document)
+		if !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrTopBottomNotObject,
+				fmt.Sprintf("specification must be an object; found %s: %s", name, types.FormatAnyValue(arg)),
+				name+" (accumulator)",
+			)
+		}
+
+		for _, k := range doc.Keys() {
+			if k == "output" || k == "sortBy" || (multi && k == "n") {
+				continue
+			}
+
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrTopBottomUnknownField,
+				fmt.Sprintf("Unknown argument to %s '%s'", name, k),
+				name+" (accumulator)",
+			)
+		}
+
+		tb := &topBottom{
+			name:  name,
+			n:     1,
+			top:   top,
+			multi: multi,
+		}
+
+		if multi {
+			nValue, err := doc.Get("n")
+			if err != nil {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrTopBottomMissingN,
+					"Missing value for 'n'",
+					name+" (accumulator)",
+				)
+			}
+
+			if tb.n, err = parseN(name, nValue); err != nil {
+				return nil, err
+			}
+		}
+
+		outputValue, err := doc.Get("output")
+		if err != nil {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrTopBottomMissingOutput,
+				"Missing value for 'output'",
+				name+" (accumulator)",
+			)
+		}
+
+		sortByValue, err := doc.Get("sortBy")
+		if err != nil {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrTopBottomMissingSortBy,
+				"Missing value for 'sortBy'",
+				name+" (accumulator)",
+			)
+		}
+
+		sortBy, ok := sortByValue.(*types.Document)
+		if !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrTopBottomSortByNotObject,
+				fmt.Sprintf("expected 'sortBy' to already be an object in the arguments to %s", name),
+				name+" (accumulator)",
+			)
+		}
+
+		for _, k := range sortBy.Keys() {
+			order, err := common.GetSortType(k, must.NotFail(sortBy.Get(k)))
+			if err != nil {
+				return nil, err
+			}
+
+			path, err := types.NewPathFromString(k)
+			if err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+
+			tb.sortBy = append(tb.sortBy, sortKey{path: path, order: order})
+		}
+
+		if tb.output, err = newExpression(outputValue); err != nil {
+			return nil, err
+		}
+
+		return tb, nil
+	}
+}
+
+// NewAccumulation implements Accumulator interface.
+func (t *topBottom) NewAccumulation(memory *MemoryUsage) Accumulation {
+	return &topBottomAccumulation{
+		topBottom: t,
+		memory:    memory,
+	}
+}
+
+// topBottomItem represents an output value with values of sort fields of the same document.
+type topBottomItem struct {
+	sortValues []any
+	output     any
+}
+
+// topBottomAccumulation represents the state of $top, $bottom, $topN and $bottomN operators.
+type topBottomAccumulation struct {
+	*topBottom
+	items  []topBottomItem
+	memory *MemoryUsage
+}
+
+// Add implements Accumulation interface.
+//
+// Missing output value and missing sort field values are accumulated as null.
+// Only n first or last items in sort order are kept.
+func (t *topBottomAccumulation) Add(doc *types.Document, vars *aggregations.Variables) error {
+	output, err := t.output.evaluate(doc, vars)
+	if err != nil {
+		return err
+	}
+
+	if output == nil {
+		output = types.Null
+	}
+
+	item := topBottomItem{
+		sortValues: make([]any, len(t.sortBy)),
+		output:     output,
+	}
+
+	for i, key := range t.sortBy {
+		v, err := doc.GetByPath(key.path)
+		if err != nil {
+			// sort order treats null and non-existent field equivalent
+			v = types.Null
+		}
+
+		item.sortValues[i] = v
+	}
+
+	if err = t.memory.Add(item.output); err != nil {
+		return err
+	}
+
+	t.items = insertSorted(t.items, item, t.compare)
+
+	if int64(len(t.items)) <= t.n {
+		return nil
+	}
+
+	if t.top {
+		t.memory.Remove(t.items[t.n].output)
+		t.items = t.items[:t.n]
+
+		return nil
+	}
+
+	t.memory.Remove(t.items[0].output)
+	t.items = slices.Delete(t.items, 0, 1)
+
+	return nil
+}
+
+// compare compares items by sort fields.
+func (t *topBottomAccumulation) compare(a, b topBottomItem) types.CompareResult {
+	for i, key := range t.sortBy {
+		if res := types.CompareOrderForSort(a.sortValues[i], b.sortValues[i], key.order); res != types.Equal {
+			return res
+		}
+	}
+
+	return types.Equal
+}
+
+// Result implements Accumulation interface.
+func (t *topBottomAccumulation) Result() any {
+	if !t.multi {
+		if len(t.items) == 0 {
+			return types.Null
+		}
+
+		return t.items[0].output
+	}
+
+	res := types.MakeArray(len(t.items))
+	for _, item := range t.items {
+		res.Append(item.output)
+	}
+
+	return res
+}
+
+// check interfaces
+var (
+	_ Accumulator  = (*topBottom)(nil)
+	_ Accumulation = (*topBottomAccumulation)(nil)
+)
@@ -0,0 +1,117 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+// add represents `$add` operator.
+type add struct {
+	args []any
+}
+
+// newAdd returns `$add` operator.
+func newAdd(args ...any) (Operator, error) {
+	return &add{
+		args: args,
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// It returns the sum of numbers, or a date with the sum of numbers added as milliseconds
+// if one of the arguments is a date. Null is returned if any argument is null or missing.
+func (a *add) Process(doc *types.Document, vars *aggregations.Variables) (any, error) {
+	values, err := evaluateArgs(a.args, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	var date *time.Time
+
+	numbers := make([]any, 0, len(values))
+
+	for _, v := range values {
+		if isNullish(v) {
+			return types.Null, nil
+		}
+
+		switch v := v.(type) {
+		case time.Time:
+			if date != nil {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrAddMultipleDates,
+					"only one date allowed in an $add expression",
+					"$add (operator)",
+				)
+			}
+
+			date = &v
+
+		default:
+			if !aggregations.IsNumber(v) {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrTypeMismatch,
+					fmt.Sprintf("$add only supports numeric or date types, not %s", handlerparams.AliasFromType(v)),
+					"$add (operator)",
+				)
+			}
+
+			numbers = append(numbers, v)
+		}
+	}
+
+	sum := aggregations.SumNumbers(numbers...)
+
+	if date == nil {
+		return sum, nil
+	}
+
+	return addMillis(*date, sum), nil
+}
+
+// addMillis returns the date with the given number of milliseconds added.
+// Non-integer numbers are rounded.
+func addMillis(date time.Time, v any) time.Time {
+	var ms int64
+
+	switch v := v.(type) {
+	case int32:
+		ms = int64(v)
+	case int64:
+		ms = v
+	case float64:
+		ms = int64(math.Round(v))
+	case types.Decimal128:
+		ms = int64(math.Round(v.Float64()))
+	default:
+		panic(fmt.Sprintf("unexpected type %T", v))
+	}
+
+	return time.UnixMilli(date.UnixMilli() + ms).UTC()
+}
+
+// check interfaces
+var (
+	_ Operator = (*add)(nil)
+)
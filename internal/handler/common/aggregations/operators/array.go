@@ -0,0 +1,517 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// arrayElemAt represents `$arrayElemAt` operator.
+type arrayElemAt struct {
+	array any
+	index any
+}
+
+// newArrayElemAt returns `$arrayElemAt` operator.
+func newArrayElemAt(args ...any) (Operator, error) {
+	if len(args) != 2 {
+		return nil, newArgsLenError("$arrayElemAt", 2, len(args))
+	}
+
+	return &arrayElemAt{
+		array: args[0],
+		index: args[1],
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// Negative index counts from the end of the array.
+// It returns missing value if the index is out of bounds.
+func (a *arrayElemAt) Process(doc *types.Document, vars *aggregations.Variables) (any, error) {
+	values, err := evaluateArgs([]any{a.array, a.index}, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if isNullish(values[0]) || isNullish(values[1]) {
+		return types.Null, nil
+	}
+
+	arr, ok := values[0].(*types.Array)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrArrayElemAtFirstArg,
+			fmt.Sprintf("$arrayElemAt's first argument must be an array, but is %s", typeAlias(values[0])),
+			"$arrayElemAt (operator)",
+		)
+	}
+
+	if !isNumber(values[1]) {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrArrayElemAtSecondArg,
+			fmt.Sprintf("$arrayElemAt's second argument must be a numeric value, but is %s", typeAlias(values[1])),
+			"$arrayElemAt (operator)",
+		)
+	}
+
+	index, ok := integralToInt32(values[1])
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrArrayElemAtIndexNotInt32,
+			fmt.Sprintf(
+				"$arrayElemAt's second argument must be representable as a 32-bit integer: %s",
+				types.FormatAnyValue(values[1]),
+			),
+			"$arrayElemAt (operator)",
+		)
+	}
+
+	i := int(index)
+	if i < 0 {
+		i += arr.Len()
+	}
+
+	if i < 0 || i >= arr.Len() {
+		return nil, nil
+	}
+
+	return must.NotFail(arr.Get(i)), nil
+}
+
+// concatArrays represents `$concatArrays` operator.
+type concatArrays struct {
+	args []any
+}
+
+// newConcatArrays returns `$concatArrays` operator.
+func newConcatArrays(args ...any) (Operator, error) {
+	return &concatArrays{
+		args: args,
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// It returns null if any argument is null or missing.
+func (c *concatArrays) Process(doc *types.Document, vars *aggregations.Variables) (any, error) {
+	values, err := evaluateArgs(c.args, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []any
+
+	for _, v := range values {
+		if isNullish(v) {
+			return types.Null, nil
+		}
+
+		arr, ok := v.(*types.Array)
+		if !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrConcatArraysNotArray,
+				fmt.Sprintf("$concatArrays only supports arrays, not %s", typeAlias(v)),
+				"$concatArrays (operator)",
+			)
+		}
+
+		res = append(res, arrayValues(arr)...)
+	}
+
+	return must.NotFail(types.NewArray(res...)), nil
+}
+
+// size represents `$size` operator.
+type size struct {
+	arg any
+}
+
+// newSize returns `$size` operator.
+func newSize(args ...any) (Operator, error) {
+	if len(args) != 1 {
+		return nil, newArgsLenError("$size", 1, len(args))
+	}
+
+	return &size{
+		arg: args[0],
+	}, nil
+}
+
+// Process implements Operator interface.
+func (s *size) Process(doc *types.Document, vars *aggregations.Variables) (any, error) {
+	v, err := evaluate(s.arg, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	arr, ok := v.(*types.Array)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSizeNotArray,
+			fmt.Sprintf("The argument to $size must be an array. Type of argument: %s", typeAlias(v)),
+			"$size (operator)",
+		)
+	}
+
+	return int32(arr.Len()), nil
+}
+
+// isArray represents `$isArray` operator.
+type isArray struct {
+	arg any
+}
+
+// newIsArray returns `$isArray` operator.
+func newIsArray(args ...any) (Operator, error) {
+	if len(args) != 1 {
+		return nil, newArgsLenError("$isArray", 1, len(args))
+	}
+
+	return &isArray{
+		arg: args[0],
+	}, nil
+}
+
+// Process implements Operator interface.
+func (i *isArray) Process(doc *types.Document, vars *aggregations.Variables) (any, error) {
+	v, err := evaluate(i.arg, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	_, ok := v.(*types.Array)
+
+	return ok, nil
+}
+
+// reverseArray represents `$reverseArray` operator.
+type reverseArray struct {
+	arg any
+}
+
+// newReverseArray returns `$reverseArray` operator.
+func newReverseArray(args ...any) (Operator, error) {
+	if len(args) != 1 {
+		return nil, newArgsLenError("$reverseArray", 1, len(args))
+	}
+
+	return &reverseArray{
+		arg: args[0],
+	}, nil
+}
+
+// Process implements Operator interface.
+func (r *reverseArray) Process(doc *types.Document, vars *aggregations.Variables) (any, error) {
+	v, err := evaluate(r.arg, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if isNullish(v) {
+		return types.Null, nil
+	}
+
+	arr, ok := v.(*types.Array)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrReverseArrayNotArray,
+			fmt.Sprintf("The argument to $reverseArray must be an array, but was of type: %s", typeAlias(v)),
+			"$reverseArray (operator)",
+		)
+	}
+
+	res := types.MakeArray(arr.Len())
+
+	for i := arr.Len() - 1; i >= 0; i-- {
+		res.Append(must.NotFail(arr.Get(i)))
+	}
+
+	return res, nil
+}
+
+// in represents `$in` aggregation operator.
+type in struct {
+	value any
+	array any
+}
+
+// newIn returns `$in` aggregation operator.
+func newIn(args ...any) (Operator, error) {
+	if len(args) != 2 {
+		return nil, newArgsLenError("$in", 2, len(args))
+	}
+
+	return &in{
+		value: args[0],
+		array: args[1],
+	}, nil
+}
+
+// Process implements Operator interface.
+func (i *in) Process(doc *types.Document, vars *aggregations.Variables) (any, error) {
+	values, err := evaluateArgs([]any{i.value, i.array}, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	arr, ok := values[1].(*types.Array)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrInNotArray,
+			fmt.Sprintf("$in requires an array as a second argument, found: %s", typeAlias(values[1])),
+			"$in (operator)",
+		)
+	}
+
+	for j := 0; j < arr.Len(); j++ {
+		if compareValues(values[0], must.NotFail(arr.Get(j))) == types.Equal {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// indexOfArray represents `$indexOfArray` operator.
+type indexOfArray struct {
+	args []any
+}
+
+// newIndexOfArray returns `$indexOfArray` operator.
+func newIndexOfArray(args ...any) (Operator, error) {
+	if len(args) < 2 || len(args) > 4 {
+		return nil, newOperatorError(
+			ErrArgsInvalidLen,
+			"$indexOfArray",
+			fmt.Sprintf(
+				"Expression $indexOfArray takes at least 2 arguments, and at most 4, but %d were passed in.",
+				len(args),
+			),
+		)
+	}
+
+	return &indexOfArray{
+		args: args,
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// It returns the index of the first occurrence of the value in the array,
+// -1 if it was not found, or null if the array is null or missing.
+func (i *indexOfArray) Process(doc *types.Document, vars *aggregations.Variables) (any, error) {
+	values, err := evaluateArgs(i.args, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if isNullish(values[0]) {
+		return types.Null, nil
+	}
+
+	arr, ok := values[0].(*types.Array)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrIndexOfArrayNotArray,
+			fmt.Sprintf("$indexOfArray requires an array as a first argument, found: %s", typeAlias(values[0])),
+			"$indexOfArray (operator)",
+		)
+	}
+
+	start, end := 0, arr.Len()
+
+	if len(values) > 2 {
+		if start, err = getIndexOfIndex("$indexOfArray", values[2], "starting", "starting"); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(values) > 3 {
+		if end, err = getIndexOfIndex("$indexOfArray", values[3], "ending", "ending"); err != nil {
+			return nil, err
+		}
+
+		end = min(end, arr.Len())
+	}
+
+	for j := start; j < end; j++ {
+		if compareValues(values[1], must.NotFail(arr.Get(j))) == types.Equal {
+			return int32(j), nil
+		}
+	}
+
+	return int32(-1), nil
+}
+
+// slice represents `$slice` aggregation operator.
+type slice struct {
+	args []any
+}
+
+// newSlice returns `$slice` aggregation operator.
+//
+// Both `[<array>, <n>]` and `[<array>, <position>, <n>]` forms are supported.
+func newSlice(args ...any) (Operator, error) {
+	if len(args) < 2 || len(args) > 3 {
+		return nil, newOperatorError(
+			ErrArgsInvalidLen,
+			"$slice",
+			fmt.Sprintf("Expression $slice takes at least 2 arguments, and at most 3, but %d were passed in.", len(args)),
+		)
+	}
+
+	return &slice{
+		args: args,
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// It returns null if any argument is null or missing.
+func (s *slice) Process(doc *types.Document, vars *aggregations.Variables) (any, error) {
+	values, err := evaluateArgs(s.args, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if isNullish(values[0]) || isNullish(values[1]) {
+		return types.Null, nil
+	}
+
+	arr, ok := values[0].(*types.Array)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSliceFirstArg,
+			fmt.Sprintf("First argument to $slice must be an array, but is of type: %s", typeAlias(values[0])),
+			"$slice (operator)",
+		)
+	}
+
+	if !isNumber(values[1]) {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSliceSecondArgNotNumber,
+			fmt.Sprintf("Second argument to $slice must be a numeric value, but is of type: %s", typeAlias(values[1])),
+			"$slice (operator)",
+		)
+	}
+
+	arg2, ok := integralToInt32(values[1])
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSliceSecondArgNotInt32,
+			fmt.Sprintf(
+				"Second argument to $slice can't be represented as a 32-bit integer: %s",
+				types.FormatAnyValue(values[1]),
+			),
+			"$slice (operator)",
+		)
+	}
+
+	length := arr.Len()
+	start, end := 0, length
+
+	if len(values) == 2 {
+		// the second argument is the number of elements from the start or from the end
+		if n := int(arg2); n >= 0 {
+			end = min(n, length)
+		} else {
+			start = max(length+n, 0)
+		}
+
+		return arraySlice(arr, start, end), nil
+	}
+
+	if isNullish(values[2]) {
+		return types.Null, nil
+	}
+
+	if !isNumber(values[2]) {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSliceThirdArgNotNumber,
+			fmt.Sprintf("Third argument to $slice must be numeric, but is of type: %s", typeAlias(values[2])),
+			"$slice (operator)",
+		)
+	}
+
+	n, ok := integralToInt32(values[2])
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSliceThirdArgNotInt32,
+			fmt.Sprintf(
+				"Third argument to $slice can't be represented as a 32-bit integer: %s",
+				types.FormatAnyValue(values[2]),
+			),
+			"$slice (operator)",
+		)
+	}
+
+	if n <= 0 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSliceThirdArgNotPositive,
+			fmt.Sprintf("Third argument to $slice must be positive: %d", n),
+			"$slice (operator)",
+		)
+	}
+
+	// the second argument is the starting position, negative position counts from the end
+	if position := int(arg2); position >= 0 {
+		start = min(position, length)
+	} else {
+		start = max(length+position, 0)
+	}
+
+	end = min(start+int(n), length)
+
+	return arraySlice(arr, start, end), nil
+}
+
+// arraySlice returns a new array with elements of the given array from start (inclusive) to end (exclusive).
+func arraySlice(arr *types.Array, start, end int) *types.Array {
+	res := types.MakeArray(end - start)
+
+	for i := start; i < end; i++ {
+		res.Append(must.NotFail(arr.Get(i)))
+	}
+
+	return res
+}
+
+// isNumber returns true if the value is a number of any BSON numeric type.
+func isNumber(v any) bool {
+	switch v.(type) {
+	case float64, int32, int64, types.Decimal128:
+		return true
+	default:
+		return false
+	}
+}
+
+// check interfaces
+var (
+	_ Operator = (*arrayElemAt)(nil)
+	_ Operator = (*concatArrays)(nil)
+	_ Operator = (*size)(nil)
+	_ Operator = (*isArray)(nil)
+	_ Operator = (*reverseArray)(nil)
+	_ Operator = (*in)(nil)
+	_ Operator = (*indexOfArray)(nil)
+	_ Operator = (*slice)(nil)
+)
@@ -0,0 +1,289 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// arrayToObject represents `$arrayToObject` operator.
+type arrayToObject struct {
+	arg any
+}
+
+// newArrayToObject returns `$arrayToObject` operator.
+func newArrayToObject(args ...any) (Operator, error) {
+	if len(args) != 1 {
+		return nil, newArgsLenError("$arrayToObject", 1, len(args))
+	}
+
+	return &arrayToObject{
+		arg: args[0],
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// The input array should contain either `[<key>, <value>]` pairs or `{k: <key>, v: <value>}` documents.
+// If the key repeats, the last value is used.
+func (a *arrayToObject) Process(doc *types.Document, vars *aggregations.Variables) (any, error) {
+	v, err := evaluate(a.arg, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if isNullish(v) {
+		return types.Null, nil
+	}
+
+	arr, ok := v.(*types.Array)
+	if !ok {
+		return nil, newArrayToObjectError(
+			handlererrors.ErrArrayToObjectNotArray,
+			fmt.Sprintf("$arrayToObject requires an array input, found: %s", typeAlias(v)),
+		)
+	}
+
+	res := types.MakeDocument(arr.Len())
+
+	if arr.Len() == 0 {
+		return res, nil
+	}
+
+	_, pairs := must.NotFail(arr.Get(0)).(*types.Array)
+
+	iter := arr.Iterator()
+	defer iter.Close()
+
+	for {
+		i, elem, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		var key string
+		var value any
+
+		switch elem := elem.(type) {
+		case *types.Array:
+			if !pairs {
+				return nil, newArrayToObjectError(
+					handlererrors.ErrArrayToObjectInconsistent,
+					"$arrayToObject requires a consistent input format. "+
+						"Elements must all be arrays or all be objects. Object was detected, now found: array",
+				)
+			}
+
+			if key, value, err = arrayToObjectPair(elem); err != nil {
+				return nil, err
+			}
+
+		case *types.Document:
+			if pairs {
+				return nil, newArrayToObjectError(
+					handlererrors.ErrArrayToObjectInconsistent,
+					"$arrayToObject requires a consistent input format. "+
+						"Elements must all be arrays or all be objects. Array was detected, now found: object",
+				)
+			}
+
+			if key, value, err = arrayToObjectDocument(elem); err != nil {
+				return nil, err
+			}
+
+		default:
+			if i == 0 {
+				return nil, newArrayToObjectError(
+					handlererrors.ErrArrayToObjectWrongType,
+					fmt.Sprintf("Unrecognised input type format for $arrayToObject: %s", typeAlias(elem)),
+				)
+			}
+
+			detected := "Object"
+			if pairs {
+				detected = "Array"
+			}
+
+			return nil, newArrayToObjectError(
+				handlererrors.ErrArrayToObjectInconsistent,
+				fmt.Sprintf(
+					"$arrayToObject requires a consistent input format. "+
+						"Elements must all be arrays or all be objects. %s was detected, now found: %s",
+					detected, typeAlias(elem),
+				),
+			)
+		}
+
+		if strings.ContainsRune(key, 0) {
+			return nil, newArrayToObjectError(
+				handlererrors.ErrArrayToObjectNullByte,
+				"Key field cannot contain an embedded null byte",
+			)
+		}
+
+		res.Set(key, value)
+	}
+
+	return res, nil
+}
+
+// arrayToObjectPair returns the key and the value of `[<key>, <value>]` pair.
+func arrayToObjectPair(pair *types.Array) (string, any, error) {
+	if pair.Len() != 2 {
+		return "", nil, newArrayToObjectError(
+			handlererrors.ErrArrayToObjectPairLen,
+			fmt.Sprintf("$arrayToObject requires an array of size 2 arrays,found array of size: %d", pair.Len()),
+		)
+	}
+
+	k := must.NotFail(pair.Get(0))
+
+	key, ok := k.(string)
+	if !ok {
+		return "", nil, newArrayToObjectError(
+			handlererrors.ErrArrayToObjectPairKeyNotString,
+			fmt.Sprintf(
+				"$arrayToObject requires an array of key-value pairs, where the key must be of type string. "+
+					"Found key type: %s",
+				typeAlias(k),
+			),
+		)
+	}
+
+	return key, must.NotFail(pair.Get(1)), nil
+}
+
+// arrayToObjectDocument returns the key and the value of `{k: <key>, v: <value>}` document.
+func arrayToObjectDocument(doc *types.Document) (string, any, error) {
+	if doc.Len() != 2 {
+		return "", nil, newArrayToObjectError(
+			handlererrors.ErrArrayToObjectWrongKeysNumber,
+			fmt.Sprintf(
+				"$arrayToObject requires an object keys of 'k' and 'v'. Found incorrect number of keys:%d",
+				doc.Len(),
+			),
+		)
+	}
+
+	k, _ := doc.Get("k")
+	v, _ := doc.Get("v")
+
+	if k == nil || v == nil {
+		return "", nil, newArrayToObjectError(
+			handlererrors.ErrArrayToObjectMissingKeys,
+			fmt.Sprintf(
+				"$arrayToObject requires an object with keys 'k' and 'v'. Missing either or both keys from: %s",
+				types.FormatAnyValue(doc),
+			),
+		)
+	}
+
+	key, ok := k.(string)
+	if !ok {
+		return "", nil, newArrayToObjectError(
+			handlererrors.ErrArrayToObjectKeyNotString,
+			fmt.Sprintf(
+				"$arrayToObject requires an object with keys 'k' and 'v', where the value of 'k' must be of type string. "+
+					"Found type: %s",
+				typeAlias(k),
+			),
+		)
+	}
+
+	return key, v, nil
+}
+
+// newArrayToObjectError returns CommandError for `$arrayToObject` operator.
+func newArrayToObjectError(code handlererrors.ErrorCode, msg string) error {
+	return handlererrors.NewCommandErrorMsgWithArgument(code, msg, "$arrayToObject (operator)")
+}
+
+// objectToArray represents `$objectToArray` operator.
+type objectToArray struct {
+	arg any
+}
+
+// newObjectToArray returns `$objectToArray` operator.
+func newObjectToArray(args ...any) (Operator, error) {
+	if len(args) != 1 {
+		return nil, newArgsLenError("$objectToArray", 1, len(args))
+	}
+
+	return &objectToArray{
+		arg: args[0],
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// It returns an array of `{k: <key>, v: <value>}` documents for document fields.
+func (o *objectToArray) Process(doc *types.Document, vars *aggregations.Variables) (any, error) {
+	v, err := evaluate(o.arg, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if isNullish(v) {
+		return types.Null, nil
+	}
+
+	d, ok := v.(*types.Document)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrObjectToArrayNotObject,
+			fmt.Sprintf("$objectToArray requires a document input, found: %s", typeAlias(v)),
+			"$objectToArray (operator)",
+		)
+	}
+
+	res := types.MakeArray(d.Len())
+
+	iter := d.Iterator()
+	defer iter.Close()
+
+	for {
+		k, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		res.Append(must.NotFail(types.NewDocument("k", k, "v", v)))
+	}
+
+	return res, nil
+}
+
+// check interfaces
+var (
+	_ Operator = (*arrayToObject)(nil)
+	_ Operator = (*objectToArray)(nil)
+)
@@ -0,0 +1,135 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+// and represents `$and` operator.
+type and struct {
+	args []any
+}
+
+// newAnd returns `$and` operator.
+func newAnd(args ...any) (Operator, error) {
+	return &and{
+		args: args,
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// It returns true if all arguments are true; arguments after the first false one are not evaluated.
+func (a *and) Process(doc *types.Document, vars *aggregations.Variables) (any, error) {
+	for _, arg := range a.args {
+		v, err := evaluate(arg, doc, vars)
+		if err != nil {
+			return nil, err
+		}
+
+		if !isTrue(v) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// or represents `$or` operator.
+type or struct {
+	args []any
+}
+
+// newOr returns `$or` operator.
+func newOr(args ...any) (Operator, error) {
+	return &or{
+		args: args,
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// It returns true if any argument is true; arguments after the first true one are not evaluated.
+func (o *or) Process(doc *types.Document, vars *aggregations.Variables) (any, error) {
+	for _, arg := range o.args {
+		v, err := evaluate(arg, doc, vars)
+		if err != nil {
+			return nil, err
+		}
+
+		if isTrue(v) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// not represents `$not` operator.
+type not struct {
+	arg any
+}
+
+// newNot returns `$not` operator.
+func newNot(args ...any) (Operator, error) {
+	if len(args) != 1 {
+		return nil, newArgsLenError("$not", 1, len(args))
+	}
+
+	return &not{
+		arg: args[0],
+	}, nil
+}
+
+// Process implements Operator interface.
+func (n *not) Process(doc *types.Document, vars *aggregations.Variables) (any, error) {
+	v, err := evaluate(n.arg, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	return !isTrue(v), nil
+}
+
+// isTrue returns the boolean value of the evaluated expression.
+//
+// False, null, missing value and zero numbers are false; all other values are true.
+func isTrue(v any) bool {
+	switch v := v.(type) {
+	case nil, types.NullType:
+		return false
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case int32:
+		return v != 0
+	case int64:
+		return v != 0
+	case types.Decimal128:
+		return v.Sign() != 0 || v.IsNaN()
+	default:
+		return true
+	}
+}
+
+// check interfaces
+var (
+	_ Operator = (*and)(nil)
+	_ Operator = (*or)(nil)
+	_ Operator = (*not)(nil)
+)
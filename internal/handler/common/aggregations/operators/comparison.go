@@ -0,0 +1,117 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"slices"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+// comparison represents comparison operators `$eq`, `$ne`, `$gt`, `$gte`, `$lt` and `$lte`.
+type comparison struct {
+	a, b    any
+	results []types.CompareResult
+}
+
+// newComparisonFunc returns a function that creates comparison operator with the given name.
+// The operator returns true if the comparison result of both arguments is one of the given results.
+func newComparisonFunc(name string, results ...types.CompareResult) newOperatorFunc {
+	return func(args ...any) (Operator, error) {
+		if len(args) != 2 {
+			return nil, newArgsLenError(name, 2, len(args))
+		}
+
+		return &comparison{
+			a:       args[0],
+			b:       args[1],
+			results: results,
+		}, nil
+	}
+}
+
+// Process implements Operator interface.
+func (c *comparison) Process(doc *types.Document, vars *aggregations.Variables) (any, error) {
+	res, err := compareArgs(c.a, c.b, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.Contains(c.results, res), nil
+}
+
+// cmp represents `$cmp` operator.
+type cmp struct {
+	a, b any
+}
+
+// newCmp returns `$cmp` operator.
+func newCmp(args ...any) (Operator, error) {
+	if len(args) != 2 {
+		return nil, newArgsLenError("$cmp", 2, len(args))
+	}
+
+	return &cmp{
+		a: args[0],
+		b: args[1],
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// It returns -1 if the first value is less than the second, 1 if it is greater, and 0 if they are equal.
+func (c *cmp) Process(doc *types.Document, vars *aggregations.Variables) (any, error) {
+	res, err := compareArgs(c.a, c.b, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	return int32(res), nil
+}
+
+// compareArgs evaluates both arguments and compares them using BSON comparison order.
+//
+// Missing value is less than any other value, including null.
+func compareArgs(a, b any, doc *types.Document, vars *aggregations.Variables) (types.CompareResult, error) {
+	values, err := evaluateArgs([]any{a, b}, doc, vars)
+	if err != nil {
+		return 0, err
+	}
+
+	return compareValues(values[0], values[1]), nil
+}
+
+// compareValues compares evaluated values using BSON comparison order.
+//
+// Missing value is less than any other value, including null.
+func compareValues(a, b any) types.CompareResult {
+	switch {
+	case a == nil && b == nil:
+		return types.Equal
+	case a == nil:
+		return types.Less
+	case b == nil:
+		return types.Greater
+	default:
+		return types.CompareForAggregation(a, b)
+	}
+}
+
+// check interfaces
+var (
+	_ Operator = (*comparison)(nil)
+	_ Operator = (*cmp)(nil)
+)
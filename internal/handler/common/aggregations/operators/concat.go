@@ -0,0 +1,73 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+// concat represents `$concat` operator.
+type concat struct {
+	args []any
+}
+
+// newConcat returns `$concat` operator.
+func newConcat(args ...any) (Operator, error) {
+	return &concat{
+		args: args,
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// It returns null if any argument is null or missing.
+func (c *concat) Process(doc *types.Document, vars *aggregations.Variables) (any, error) {
+	values, err := evaluateArgs(c.args, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	var res strings.Builder
+
+	for _, v := range values {
+		if isNullish(v) {
+			return types.Null, nil
+		}
+
+		s, ok := v.(string)
+		if !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrConcatNonString,
+				fmt.Sprintf("$concat only supports strings, not %s", handlerparams.AliasFromType(v)),
+				"$concat (operator)",
+			)
+		}
+
+		res.WriteString(s)
+	}
+
+	return res.String(), nil
+}
+
+// check interfaces
+var (
+	_ Operator = (*concat)(nil)
+)
@@ -0,0 +1,131 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+)
+
+// cond represents `$cond` operator.
+type cond struct {
+	ifExpr   any
+	thenExpr any
+	elseExpr any
+}
+
+// newCond returns `$cond` operator.
+//
+// Both `{$cond: {if: <expr>, then: <expr>, else: <expr>}}`
+// and `{$cond: [<if>, <then>, <else>]}` forms are supported.
+func newCond(args ...any) (Operator, error) {
+	if len(args) == 1 {
+		if doc, ok := args[0].(*types.Document); ok && !IsOperator(doc) {
+			return newCondFromDocument(doc)
+		}
+	}
+
+	if len(args) != 3 {
+		return nil, newArgsLenError("$cond", 3, len(args))
+	}
+
+	return &cond{
+		ifExpr:   args[0],
+		thenExpr: args[1],
+		elseExpr: args[2],
+	}, nil
+}
+
+// newCondFromDocument returns `$cond` operator for the document form.
+func newCondFromDocument(doc *types.Document) (Operator, error) {
+	var c cond
+	var hasIf, hasThen, hasElse bool
+
+	iter := doc.Iterator()
+	defer iter.Close()
+
+	for {
+		k, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		switch k {
+		case "if":
+			c.ifExpr, hasIf = v, true
+		case "then":
+			c.thenExpr, hasThen = v, true
+		case "else":
+			c.elseExpr, hasElse = v, true
+		default:
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrCondUnknownParameter,
+				fmt.Sprintf("Unrecognized parameter to $cond: %s", k),
+				"$cond (operator)",
+			)
+		}
+	}
+
+	for _, p := range []struct {
+		name string
+		code handlererrors.ErrorCode
+		ok   bool
+	}{
+		{"if", handlererrors.ErrCondMissingIf, hasIf},
+		{"then", handlererrors.ErrCondMissingThen, hasThen},
+		{"else", handlererrors.ErrCondMissingElse, hasElse},
+	} {
+		if !p.ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				p.code,
+				fmt.Sprintf("Missing '%s' parameter to $cond", p.name),
+				"$cond (operator)",
+			)
+		}
+	}
+
+	return &c, nil
+}
+
+// Process implements Operator interface.
+//
+// Only the expression of the chosen branch is evaluated.
+func (c *cond) Process(doc *types.Document, vars *aggregations.Variables) (any, error) {
+	v, err := evaluate(c.ifExpr, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if isTrue(v) {
+		return evaluate(c.thenExpr, doc, vars)
+	}
+
+	return evaluate(c.elseExpr, doc, vars)
+}
+
+// check interfaces
+var (
+	_ Operator = (*cond)(nil)
+)
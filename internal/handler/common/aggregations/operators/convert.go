@@ -0,0 +1,641 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// legacyTypeCodes contains codes of deprecated BSON types that are valid `to` values of `$convert`,
+// but no value could be converted to.
+var legacyTypeCodes = map[int32]string{
+	6:  "undefined",
+	12: "dbPointer",
+	13: "javascript",
+	14: "symbol",
+	15: "javascriptWithScope",
+}
+
+// convert represents `$convert` operator and type conversion operators like `$toInt`.
+type convert struct {
+	input   any
+	to      any
+	onError any
+	onNull  any
+}
+
+// newConvert returns `$convert` operator.
+func newConvert(args ...any) (Operator, error) {
+	fields, err := namedArgs(
+		args,
+		[]string{"input", "to", "onError", "onNull"},
+		func(v any) error {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrFailedToParse,
+				fmt.Sprintf("$convert expects an object of named arguments but found: %s", typeAlias(v)),
+				"$convert (operator)",
+			)
+		},
+		func(field string) error {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrFailedToParse,
+				fmt.Sprintf("$convert found an unknown argument: %s", field),
+				"$convert (operator)",
+			)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, field := range []string{"input", "to"} {
+		if fields[field] == nil {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrFailedToParse,
+				fmt.Sprintf("Missing '%s' parameter to $convert", field),
+				"$convert (operator)",
+			)
+		}
+	}
+
+	return &convert{
+		input:   fields["input"],
+		to:      fields["to"],
+		onError: fields["onError"],
+		onNull:  fields["onNull"],
+	}, nil
+}
+
+// newConvertFunc returns a function that creates conversion operator with the given name,
+// like `$toInt`, that is a shorthand for `$convert` to the given type.
+func newConvertFunc(name string, to handlerparams.TypeCode) newOperatorFunc {
+	return func(args ...any) (Operator, error) {
+		if len(args) != 1 {
+			return nil, newArgsLenError(name, 1, len(args))
+		}
+
+		return &convert{
+			input: args[0],
+			to:    to.String(),
+		}, nil
+	}
+}
+
+// Process implements Operator interface.
+//
+// If the input is null or missing, the value of `onNull` or null is returned.
+// If `to` is null or missing, null is returned.
+// If the conversion fails and `onError` is set, its value is returned.
+func (c *convert) Process(doc *types.Document, vars *aggregations.Variables) (any, error) {
+	to, err := evaluate(c.to, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	input, err := evaluate(c.input, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	var target string
+
+	if !isNullish(to) {
+		if target, err = getConvertTarget(to); err != nil {
+			return nil, err
+		}
+	}
+
+	if isNullish(input) {
+		if c.onNull != nil {
+			return evaluate(c.onNull, doc, vars)
+		}
+
+		return types.Null, nil
+	}
+
+	if target == "" {
+		return types.Null, nil
+	}
+
+	res, err := convertValue(input, target)
+	if err == nil || c.onError == nil {
+		return res, err
+	}
+
+	var cmdErr *handlererrors.CommandError
+	if errors.As(err, &cmdErr) && cmdErr.Code() == handlererrors.ErrConversionFailure {
+		return evaluate(c.onError, doc, vars)
+	}
+
+	return nil, err
+}
+
+// getConvertTarget returns the name of the BSON type specified by `to` argument of `$convert`.
+// The type could be specified by its name or by its numeric code.
+func getConvertTarget(to any) (string, error) {
+	switch to := to.(type) {
+	case string:
+		if code, err := handlerparams.ParseTypeCode(to); err == nil && code != handlerparams.TypeCodeNumber {
+			return to, nil
+		}
+
+		for _, name := range legacyTypeCodes {
+			if name == to {
+				return to, nil
+			}
+		}
+
+		return "", handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			fmt.Sprintf("Unknown type name: %s", to),
+			"$convert (operator)",
+		)
+
+	case float64, int32, int64, types.Decimal128:
+		code, ok := integralToInt32(to)
+		if !ok {
+			return "", handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrFailedToParse,
+				"In $convert, numeric 'to' argument is not an integer",
+				"$convert (operator)",
+			)
+		}
+
+		if name, ok := legacyTypeCodes[code]; ok {
+			return name, nil
+		}
+
+		if tc, err := handlerparams.NewTypeCode(code); err == nil && tc != handlerparams.TypeCodeNumber {
+			return tc.String(), nil
+		}
+
+		return "", handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			fmt.Sprintf("In $convert, numeric value for 'to' does not correspond to a BSON type: %d", code),
+			"$convert (operator)",
+		)
+
+	default:
+		return "", handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			fmt.Sprintf("$convert's 'to' argument must be a string or number, but is %s", typeAlias(to)),
+			"$convert (operator)",
+		)
+	}
+}
+
+// convertValue converts non-null value to the BSON type with the given name.
+func convertValue(v any, to string) (any, error) {
+	switch to {
+	case handlerparams.TypeCodeDouble.String():
+		return convertToDouble(v)
+	case handlerparams.TypeCodeString.String():
+		return convertToString(v)
+	case handlerparams.TypeCodeObjectID.String():
+		return convertToObjectID(v)
+	case handlerparams.TypeCodeBool.String():
+		return convertToBool(v), nil
+	case handlerparams.TypeCodeDate.String():
+		return convertToDate(v)
+	case handlerparams.TypeCodeInt.String():
+		return convertToInt(v)
+	case handlerparams.TypeCodeLong.String():
+		return convertToLong(v)
+	case handlerparams.TypeCodeDecimal.String():
+		return convertToDecimal(v)
+	default:
+		return nil, newUnsupportedConversionError(v, to)
+	}
+}
+
+// convertToDouble converts the value to float64.
+func convertToDouble(v any) (any, error) {
+	switch v := v.(type) {
+	case float64:
+		return v, nil
+	case int32, int64:
+		return aggregations.NumberToFloat64(v), nil
+	case types.Decimal128:
+		f := v.Float64()
+		if math.IsInf(f, 0) && !v.IsInf(0) {
+			return nil, newConversionOverflowError(v.String())
+		}
+
+		return f, nil
+	case bool:
+		if v {
+			return float64(1), nil
+		}
+
+		return float64(0), nil
+	case time.Time:
+		return float64(v.UnixMilli()), nil
+	case string:
+		return parseDouble(v)
+	default:
+		return nil, newUnsupportedConversionError(v, handlerparams.TypeCodeDouble.String())
+	}
+}
+
+// convertToString converts the value to string.
+func convertToString(v any) (any, error) {
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case float64:
+		return formatDouble(v), nil
+	case int32:
+		return strconv.FormatInt(int64(v), 10), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case types.Decimal128:
+		return v.String(), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case types.ObjectID:
+		return hex.EncodeToString(v[:]), nil
+	case time.Time:
+		return formatDate(v.UTC(), "%Y-%m-%dT%H:%M:%S.%LZ"), nil
+	default:
+		return nil, newUnsupportedConversionError(v, handlerparams.TypeCodeString.String())
+	}
+}
+
+// convertToObjectID converts the value to ObjectID.
+// Only strings of 24 hexadecimal characters could be converted.
+func convertToObjectID(v any) (any, error) {
+	switch v := v.(type) {
+	case types.ObjectID:
+		return v, nil
+	case string:
+		var reason string
+
+		if len(v) != 24 {
+			reason = fmt.Sprintf("Invalid string length for parsing to OID, expected 24 but found %d", len(v))
+		} else if i := strings.IndexFunc(v, isNotHexDigit); i >= 0 {
+			reason = fmt.Sprintf("Invalid character found in hex string: '%c'", []rune(v[i:])[0])
+		}
+
+		if reason != "" {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrConversionFailure,
+				fmt.Sprintf("Failed to parse objectId '%s' in $convert with no onError value: %s", v, reason),
+				"$convert (operator)",
+			)
+		}
+
+		return must.NotFail(types.ParseObjectID(v)), nil
+	default:
+		return nil, newUnsupportedConversionError(v, handlerparams.TypeCodeObjectID.String())
+	}
+}
+
+// convertToBool converts the value to bool.
+// Numbers are converted to false if they are zero, all other values are converted to true.
+func convertToBool(v any) bool {
+	switch v := v.(type) {
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case int32:
+		return v != 0
+	case int64:
+		return v != 0
+	case types.Decimal128:
+		return v.Sign() != 0 || v.IsNaN()
+	default:
+		return true
+	}
+}
+
+// convertToDate converts the value to time.Time.
+// Numbers other than int32 are treated as milliseconds since epoch.
+func convertToDate(v any) (any, error) {
+	if t, ok := toDate(v); ok {
+		return t, nil
+	}
+
+	switch v := v.(type) {
+	case float64, int64, types.Decimal128:
+		ms, err := convertToLong(v)
+		if err != nil {
+			return nil, err
+		}
+
+		return time.UnixMilli(ms.(int64)).UTC(), nil
+	case string:
+		p, ok := parseDate(v)
+
+		var t time.Time
+
+		if ok {
+			loc := time.UTC
+			if p.loc != nil {
+				loc = p.loc
+			}
+
+			t, ok = p.time(loc)
+		}
+
+		if !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrConversionFailure,
+				fmt.Sprintf("Error parsing date string '%s'", v),
+				"$convert (operator)",
+			)
+		}
+
+		return t.UTC(), nil
+	default:
+		return nil, newUnsupportedConversionError(v, handlerparams.TypeCodeDate.String())
+	}
+}
+
+// convertToInt converts the value to int32.
+// Doubles and decimals are truncated.
+func convertToInt(v any) (any, error) {
+	switch v := v.(type) {
+	case int32:
+		return v, nil
+	case int64:
+		if v < math.MinInt32 || v > math.MaxInt32 {
+			return nil, newConversionOverflowError(strconv.FormatInt(v, 10))
+		}
+
+		return int32(v), nil
+	case float64, types.Decimal128:
+		l, err := convertToLong(v)
+		if err != nil {
+			return nil, err
+		}
+
+		i := l.(int64)
+		if i < math.MinInt32 || i > math.MaxInt32 {
+			return nil, newConversionOverflowError(formatNumber(v))
+		}
+
+		return int32(i), nil
+	case bool:
+		if v {
+			return int32(1), nil
+		}
+
+		return int32(0), nil
+	case string:
+		i, err := parseInteger(v, 32)
+		if err != nil {
+			return nil, err
+		}
+
+		return int32(i), nil
+	default:
+		return nil, newUnsupportedConversionError(v, handlerparams.TypeCodeInt.String())
+	}
+}
+
+// convertToLong converts the value to int64.
+// Doubles and decimals are truncated, dates are converted to milliseconds since epoch.
+func convertToLong(v any) (any, error) {
+	switch v := v.(type) {
+	case int64:
+		return v, nil
+	case int32:
+		return int64(v), nil
+	case float64:
+		switch {
+		case math.IsNaN(v):
+			return nil, newConversionNaNError()
+		case math.IsInf(v, 0):
+			return nil, newConversionInfinityError()
+		case v < math.MinInt64 || v >= math.MaxInt64:
+			return nil, newConversionOverflowError(formatDouble(v))
+		}
+
+		return int64(v), nil
+	case types.Decimal128:
+		switch {
+		case v.IsNaN():
+			return nil, newConversionNaNError()
+		case v.IsInf(0):
+			return nil, newConversionInfinityError()
+		}
+
+		i, ok := v.Int64()
+		if !ok {
+			return nil, newConversionOverflowError(v.String())
+		}
+
+		return i, nil
+	case bool:
+		if v {
+			return int64(1), nil
+		}
+
+		return int64(0), nil
+	case time.Time:
+		return v.UnixMilli(), nil
+	case string:
+		return parseInteger(v, 64)
+	default:
+		return nil, newUnsupportedConversionError(v, handlerparams.TypeCodeLong.String())
+	}
+}
+
+// convertToDecimal converts the value to Decimal128.
+func convertToDecimal(v any) (any, error) {
+	switch v := v.(type) {
+	case float64, int32, int64, types.Decimal128:
+		return aggregations.NumberToDecimal128(v), nil
+	case bool:
+		if v {
+			return types.NewDecimal128FromInt64(1), nil
+		}
+
+		return types.NewDecimal128FromInt64(0), nil
+	case time.Time:
+		return types.NewDecimal128FromInt64(v.UnixMilli()), nil
+	case string:
+		d, err := types.ParseDecimal128(v)
+		if err != nil {
+			return nil, newParseNumberError(v, "Failed to parse string to decimal")
+		}
+
+		return d, nil
+	default:
+		return nil, newUnsupportedConversionError(v, handlerparams.TypeCodeDecimal.String())
+	}
+}
+
+// parseDouble parses the decimal representation of float64, including NaN and infinities.
+func parseDouble(s string) (float64, error) {
+	if s == "" {
+		return 0, newParseNumberError(s, "No digits")
+	}
+
+	// hexadecimal floating-point numbers are not allowed
+	if strings.ContainsAny(s, "xX") {
+		return 0, newParseNumberError(s, "Did not consume whole string.")
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err == nil {
+		return f, nil
+	}
+
+	if errors.Is(err, strconv.ErrRange) {
+		return 0, newParseNumberError(s, "Out of range")
+	}
+
+	return 0, newParseNumberError(s, "Did not consume whole string.")
+}
+
+// parseInteger parses the decimal representation of the integer with the given bit size.
+func parseInteger(s string, bitSize int) (int64, error) {
+	if s == "" {
+		return 0, newParseNumberError(s, "No digits")
+	}
+
+	i, err := strconv.ParseInt(s, 10, bitSize)
+	if err == nil {
+		return i, nil
+	}
+
+	if errors.Is(err, strconv.ErrRange) {
+		return 0, newParseNumberError(s, "Overflow")
+	}
+
+	return 0, newParseNumberError(s, "Did not consume whole string.")
+}
+
+// isNotHexDigit returns true if the rune is not a hexadecimal digit.
+func isNotHexDigit(r rune) bool {
+	return !strings.ContainsRune("0123456789abcdefABCDEF", r)
+}
+
+// formatDouble returns the string representation of float64 like MongoDB does,
+// with at most 6 significant digits.
+func formatDouble(f float64) string {
+	switch {
+	case math.IsNaN(f):
+		return "NaN"
+	case math.IsInf(f, 1):
+		return "Infinity"
+	case math.IsInf(f, -1):
+		return "-Infinity"
+	case f == 0 && math.Signbit(f):
+		return "-0"
+	}
+
+	return strconv.FormatFloat(f, 'g', 6, 64)
+}
+
+// formatNumber returns the string representation of float64 or Decimal128 for error messages.
+func formatNumber(v any) string {
+	if d, ok := v.(types.Decimal128); ok {
+		return d.String()
+	}
+
+	return formatDouble(v.(float64))
+}
+
+// newUnsupportedConversionError returns an error for the value that can't be converted to the given type.
+func newUnsupportedConversionError(v any, to string) error {
+	return handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrConversionFailure,
+		fmt.Sprintf("Unsupported conversion from %s to %s in $convert with no onError value", typeAlias(v), to),
+		"$convert (operator)",
+	)
+}
+
+// newConversionOverflowError returns an error for the value that doesn't fit into the target type.
+func newConversionOverflowError(v string) error {
+	return handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrConversionFailure,
+		fmt.Sprintf("Conversion would overflow target type in $convert with no onError value: %s", v),
+		"$convert (operator)",
+	)
+}
+
+// newConversionNaNError returns an error for NaN value converted to integer.
+func newConversionNaNError() error {
+	return handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrConversionFailure,
+		"Attempt to convert NaN value to integer type in $convert with no onError value",
+		"$convert (operator)",
+	)
+}
+
+// newConversionInfinityError returns an error for infinite value converted to integer.
+func newConversionInfinityError() error {
+	return handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrConversionFailure,
+		"Attempt to convert infinity value to integer type in $convert with no onError value",
+		"$convert (operator)",
+	)
+}
+
+// newParseNumberError returns an error for the string that can't be parsed as a number.
+func newParseNumberError(s, reason string) error {
+	return handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrConversionFailure,
+		fmt.Sprintf("Failed to parse number '%s' in $convert with no onError value: %s", s, reason),
+		"$convert (operator)",
+	)
+}
+
+// isNumberOp represents `$isNumber` operator.
+type isNumberOp struct {
+	arg any
+}
+
+// newIsNumber returns `$isNumber` operator.
+func newIsNumber(args ...any) (Operator, error) {
+	if len(args) != 1 {
+		return nil, newArgsLenError("$isNumber", 1, len(args))
+	}
+
+	return &isNumberOp{
+		arg: args[0],
+	}, nil
+}
+
+// Process implements Operator interface.
+func (n *isNumberOp) Process(doc *types.Document, vars *aggregations.Variables) (any, error) {
+	v, err := evaluate(n.arg, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	return aggregations.IsNumber(v), nil
+}
+
+// check interfaces
+var (
+	_ Operator = (*convert)(nil)
+	_ Operator = (*isNumberOp)(nil)
+)
@@ -0,0 +1,445 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"encoding/binary"
+	"fmt"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+	_ "time/tzdata" // embed time zone database for Olson time zone identifiers
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// toDate converts the evaluated value to time.Time in UTC.
+// Dates, timestamps and ObjectIDs could be converted.
+func toDate(v any) (time.Time, bool) {
+	switch v := v.(type) {
+	case time.Time:
+		return v.UTC(), true
+	case types.Timestamp:
+		return v.Time(), true
+	case types.ObjectID:
+		return time.Unix(int64(binary.BigEndian.Uint32(v[:4])), 0).UTC(), true
+	default:
+		return time.Time{}, false
+	}
+}
+
+// newConvertToDateError returns an error for the value that can't be converted to date.
+func newConvertToDateError(name string, v any) error {
+	return handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrConvertToDate,
+		fmt.Sprintf("can't convert from BSON type %s to Date", typeAlias(v)),
+		name+" (operator)",
+	)
+}
+
+// utcOffsetRe matches UTC offsets in `+/-[hh]`, `+/-[hh][mm]` and `+/-[hh]:[mm]` formats.
+var utcOffsetRe = regexp.MustCompile(`^([+-])(\d{2})(?::?(\d{2}))?$`)
+
+// locations caches loaded time zones by their identifiers.
+var locations sync.Map
+
+// loadLocation returns the location for the given Olson time zone identifier or UTC offset.
+func loadLocation(tz string) (*time.Location, bool) {
+	if loc, ok := locations.Load(tz); ok {
+		return loc.(*time.Location), true
+	}
+
+	var loc *time.Location
+
+	if m := utcOffsetRe.FindStringSubmatch(tz); m != nil {
+		hours := must.NotFail(strconv.Atoi(m[2]))
+
+		var minutes int
+		if m[3] != "" {
+			minutes = must.NotFail(strconv.Atoi(m[3]))
+		}
+
+		offset := (hours*60 + minutes) * 60
+		if m[1] == "-" {
+			offset = -offset
+		}
+
+		loc = time.FixedZone(tz, offset)
+	} else {
+		// time.LoadLocation treats empty string as UTC and "Local" as the local time zone
+		if tz == "" || tz == "Local" {
+			return nil, false
+		}
+
+		var err error
+		if loc, err = time.LoadLocation(tz); err != nil {
+			return nil, false
+		}
+	}
+
+	locations.Store(tz, loc)
+
+	return loc, true
+}
+
+// evaluateTimezone evaluates the timezone argument of the date operator and returns its location.
+//
+// If the argument is not specified (nil), UTC is returned.
+// If it is evaluated to null or missing value, nil location is returned;
+// operators return null in that case.
+func evaluateTimezone(name string, arg any, doc *types.Document, vars *aggregations.Variables) (*time.Location, error) {
+	if arg == nil {
+		return time.UTC, nil
+	}
+
+	v, err := evaluate(arg, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if isNullish(v) {
+		return nil, nil
+	}
+
+	tz, ok := v.(string)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrTimezoneNonString,
+			fmt.Sprintf(
+				"%s requires a string for the timezone argument, but was given a %s (%s)",
+				name, typeAlias(v), types.FormatAnyValue(v),
+			),
+			name+" (operator)",
+		)
+	}
+
+	loc, ok := loadLocation(tz)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrUnrecognizedTimezone,
+			fmt.Sprintf("unrecognized time zone identifier: %q", tz),
+			name+" (operator)",
+		)
+	}
+
+	return loc, nil
+}
+
+// datePartFunc returns a part of the given date in the operator's time zone.
+type datePartFunc func(t time.Time) int
+
+// datePart represents operators that return a part of the date like `$year` or `$hour`.
+type datePart struct {
+	name     string
+	date     any
+	timezone any // nil if not set
+	f        datePartFunc
+}
+
+// newDatePartFunc returns a function that creates a date part operator with the given name.
+//
+// Operators accept a date expression, an array with a single date expression,
+// or a document with `date` and optional `timezone` fields.
+func newDatePartFunc(name string, f datePartFunc) newOperatorFunc {
+	return func(args ...any) (Operator, error) {
+		if len(args) != 1 {
+			return nil, newArgsLenError(name, 1, len(args))
+		}
+
+		dp := &datePart{
+			name: name,
+			date: args[0],
+			f:    f,
+		}
+
+		spec, ok := args[0].(*types.Document)
+		if !ok || IsOperator(spec) {
+			return dp, nil
+		}
+
+		fields, err := namedArgs(
+			args,
+			[]string{"date", "timezone"},
+			nil, // the argument is a document
+			func(field string) error {
+				return handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrDateUnknownArgument,
+					fmt.Sprintf("unrecognized option to %s: %q", name, field),
+					name+" (operator)",
+				)
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if dp.date = fields["date"]; dp.date == nil {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrDateMissingDate,
+				fmt.Sprintf("missing 'date' argument to %s, provided: %s", name, types.FormatAnyValue(spec)),
+				name+" (operator)",
+			)
+		}
+
+		dp.timezone = fields["timezone"]
+
+		return dp, nil
+	}
+}
+
+// Process implements Operator interface.
+//
+// It returns null if the date or the time zone is null or missing.
+func (dp *datePart) Process(doc *types.Document, vars *aggregations.Variables) (any, error) {
+	v, err := evaluate(dp.date, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if isNullish(v) {
+		return types.Null, nil
+	}
+
+	t, ok := toDate(v)
+	if !ok {
+		return nil, newConvertToDateError(dp.name, v)
+	}
+
+	loc, err := evaluateTimezone(dp.name, dp.timezone, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if loc == nil {
+		return types.Null, nil
+	}
+
+	return int32(dp.f(t.In(loc))), nil
+}
+
+// year returns the year of the date.
+func year(t time.Time) int {
+	return t.Year()
+}
+
+// month returns the month of the date (1-12).
+func month(t time.Time) int {
+	return int(t.Month())
+}
+
+// dayOfMonth returns the day of the month (1-31).
+func dayOfMonth(t time.Time) int {
+	return t.Day()
+}
+
+// dayOfYear returns the day of the year (1-366).
+func dayOfYear(t time.Time) int {
+	return t.YearDay()
+}
+
+// dayOfWeek returns the day of the week from 1 (Sunday) to 7 (Saturday).
+func dayOfWeek(t time.Time) int {
+	return int(t.Weekday()) + 1
+}
+
+// hour returns the hour of the date (0-23).
+func hour(t time.Time) int {
+	return t.Hour()
+}
+
+// minute returns the minute of the date (0-59).
+func minute(t time.Time) int {
+	return t.Minute()
+}
+
+// second returns the second of the date (0-59).
+func second(t time.Time) int {
+	return t.Second()
+}
+
+// millisecond returns the millisecond of the date (0-999).
+func millisecond(t time.Time) int {
+	return t.Nanosecond() / int(time.Millisecond)
+}
+
+// week returns the week of the year (0-53); weeks begin on Sundays,
+// and days preceding the first Sunday of the year are in week 0.
+func week(t time.Time) int {
+	return (t.YearDay() - 1 + 7 - int(t.Weekday())) / 7
+}
+
+// isoWeek returns the week number in ISO 8601 format (1-53).
+func isoWeek(t time.Time) int {
+	_, w := t.ISOWeek()
+	return w
+}
+
+// isoWeekYear returns the year number in ISO 8601 format.
+func isoWeekYear(t time.Time) int {
+	y, _ := t.ISOWeek()
+	return y
+}
+
+// isoDayOfWeek returns the weekday number in ISO 8601 format from 1 (Monday) to 7 (Sunday).
+func isoDayOfWeek(t time.Time) int {
+	if t.Weekday() == time.Sunday {
+		return 7
+	}
+
+	return int(t.Weekday())
+}
+
+// isoWeekDate returns the date for the given ISO 8601 week date and time in the given location.
+func isoWeekDate(year, week, weekDay, hour, minute, second, milli int, loc *time.Location) time.Time {
+	// January 4th is always in the first week
+	jan4 := (int(time.Date(year, 1, 4, 0, 0, 0, 0, time.UTC).Weekday()) + 6) % 7
+	day := 4 - jan4 + (week-1)*7 + weekDay - 1
+
+	return time.Date(year, 1, day, hour, minute, second, milli*int(time.Millisecond), loc)
+}
+
+// timeUnit represents a time unit of date arithmetic operators.
+type timeUnit string
+
+// Time units.
+const (
+	unitYear        timeUnit = "year"
+	unitQuarter     timeUnit = "quarter"
+	unitMonth       timeUnit = "month"
+	unitWeek        timeUnit = "week"
+	unitDay         timeUnit = "day"
+	unitHour        timeUnit = "hour"
+	unitMinute      timeUnit = "minute"
+	unitSecond      timeUnit = "second"
+	unitMillisecond timeUnit = "millisecond"
+)
+
+// unitDurations contains durations of time units that have fixed length.
+var unitDurations = map[timeUnit]time.Duration{
+	unitHour:        time.Hour,
+	unitMinute:      time.Minute,
+	unitSecond:      time.Second,
+	unitMillisecond: time.Millisecond,
+}
+
+// unitMonths contains numbers of months in time units based on months.
+var unitMonths = map[timeUnit]int{
+	unitYear:    12,
+	unitQuarter: 3,
+	unitMonth:   1,
+}
+
+// getTimeUnit returns the time unit from the evaluated value.
+//
+// Code is used for the error when the value is not a string.
+func getTimeUnit(name string, v any, code handlererrors.ErrorCode) (timeUnit, error) {
+	s, ok := v.(string)
+	if !ok {
+		return "", handlererrors.NewCommandErrorMsgWithArgument(
+			code,
+			fmt.Sprintf("%s requires 'unit' to be a string, but got %s", name, typeAlias(v)),
+			name+" (operator)",
+		)
+	}
+
+	if !IsTimeUnit(s) {
+		return "", handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			fmt.Sprintf("%s parameter 'unit' value cannot be recognized as a time unit: %s", name, s),
+			name+" (operator)",
+		)
+	}
+
+	return timeUnit(s), nil
+}
+
+// IsTimeUnit returns true if s is a time unit like "day" or "hour".
+func IsTimeUnit(s string) bool {
+	switch timeUnit(s) {
+	case unitYear, unitQuarter, unitMonth, unitWeek, unitDay, unitHour, unitMinute, unitSecond, unitMillisecond:
+		return true
+	default:
+		return false
+	}
+}
+
+// getStartOfWeek returns the first day of the week from the evaluated `startOfWeek` value.
+// Full names and three-letter abbreviations are accepted case-insensitively.
+func getStartOfWeek(name string, v any) (time.Weekday, error) {
+	s, ok := v.(string)
+	if !ok {
+		return 0, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrStartOfWeekNonString,
+			fmt.Sprintf("%s requires 'startOfWeek' to be a string, but got %s", name, typeAlias(v)),
+			name+" (operator)",
+		)
+	}
+
+	lower := asciiToLower(s)
+
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		day := asciiToLower(d.String())
+		if lower == day || lower == day[:3] {
+			return d, nil
+		}
+	}
+
+	return 0, handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrStartOfWeekInvalid,
+		fmt.Sprintf("%s parameter 'startOfWeek' value cannot be recognized as a day of a week: %s", name, s),
+		name+" (operator)",
+	)
+}
+
+// daysSinceEpoch returns the number of days between Unix epoch and the date of t in its location.
+func daysSinceEpoch(t time.Time) int64 {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Unix() / 86400
+}
+
+// wallClock returns the number of milliseconds since Unix epoch
+// of the date and time of t in its location, as if it were UTC.
+func wallClock(t time.Time) int64 {
+	_, offset := t.Zone()
+	return t.UnixMilli() + int64(offset)*1000
+}
+
+// fromWallClock returns the time in the given location
+// with the date and time of wall clock milliseconds returned by [wallClock].
+func fromWallClock(ms int64, loc *time.Location) time.Time {
+	w := time.UnixMilli(ms).UTC()
+	y, m, d := w.Date()
+
+	return time.Date(y, m, d, w.Hour(), w.Minute(), w.Second(), w.Nanosecond(), loc)
+}
+
+// floorDiv returns the quotient of a and b rounded towards negative infinity.
+func floorDiv(a, b int64) int64 {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+
+	return q
+}
+
+// check interfaces
+var (
+	_ Operator = (*datePart)(nil)
+)
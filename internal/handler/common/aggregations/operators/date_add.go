@@ -0,0 +1,250 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+// dateAdd represents `$dateAdd` and `$dateSubtract` operators.
+type dateAdd struct {
+	name      string
+	startDate any
+	unit      any
+	amount    any
+	timezone  any // nil if not set
+	subtract  bool
+}
+
+// newDateAdd returns `$dateAdd` operator.
+func newDateAdd(args ...any) (Operator, error) {
+	return newDateAddOrSubtract("$dateAdd", false, args)
+}
+
+// newDateSubtract returns `$dateSubtract` operator.
+func newDateSubtract(args ...any) (Operator, error) {
+	return newDateAddOrSubtract("$dateSubtract", true, args)
+}
+
+// newDateAddOrSubtract returns `$dateAdd` or `$dateSubtract` operator.
+func newDateAddOrSubtract(name string, subtract bool, args []any) (Operator, error) {
+	fields, err := namedArgs(
+		args,
+		[]string{"startDate", "unit", "amount", "timezone"},
+		func(any) error {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrDateAddNotObject,
+				fmt.Sprintf("%s expects an object as its argument", name),
+				name+" (operator)",
+			)
+		},
+		func(field string) error {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrDateAddUnknownArgument,
+				fmt.Sprintf("Unrecognized argument to %s: %s", name, field),
+				name+" (operator)",
+			)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if fields["startDate"] == nil || fields["unit"] == nil || fields["amount"] == nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrDateAddMissingArgument,
+			fmt.Sprintf("%s requires startDate, unit, and amount to be present", name),
+			name+" (operator)",
+		)
+	}
+
+	return &dateAdd{
+		name:      name,
+		startDate: fields["startDate"],
+		unit:      fields["unit"],
+		amount:    fields["amount"],
+		timezone:  fields["timezone"],
+		subtract:  subtract,
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// It returns null if any argument is null or missing.
+func (d *dateAdd) Process(doc *types.Document, vars *aggregations.Variables) (any, error) {
+	values, err := evaluateArgs([]any{d.startDate, d.unit, d.amount}, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range values {
+		if isNullish(v) {
+			return types.Null, nil
+		}
+	}
+
+	loc, err := evaluateTimezone(d.name, d.timezone, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if loc == nil {
+		return types.Null, nil
+	}
+
+	t, ok := toDate(values[0])
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrDateAddInvalidStartDate,
+			fmt.Sprintf("%s requires startDate to be convertible to a date", d.name),
+			d.name+" (operator)",
+		)
+	}
+
+	unit, err := getTimeUnit(d.name, values[1], handlererrors.ErrDateAddUnitNonString)
+	if err != nil {
+		return nil, err
+	}
+
+	amount, ok := integralToInt64(values[2])
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrDateAddAmountNotIntegral,
+			fmt.Sprintf("%s expects integer amount of time units", d.name),
+			d.name+" (operator)",
+		)
+	}
+
+	if d.subtract {
+		if amount == math.MinInt64 {
+			ok = false
+		}
+
+		amount = -amount
+	}
+
+	var res time.Time
+	if ok {
+		res, ok = addTimeUnits(t.In(loc), unit, amount)
+	}
+
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrDateAddOverflow,
+			fmt.Sprintf("%s overflowed", d.name),
+			d.name+" (operator)",
+		)
+	}
+
+	return res.UTC(), nil
+}
+
+// AddTimeUnits adds the amount of time units to t in UTC, like `$dateAdd` does.
+// It is used by stages like `$densify`.
+//
+// Unit must be valid for [IsTimeUnit]. It returns false if the result overflows.
+func AddTimeUnits(t time.Time, unit string, amount int64) (time.Time, bool) {
+	res, ok := addTimeUnits(t.UTC(), timeUnit(unit), amount)
+	return res.UTC(), ok
+}
+
+// addTimeUnits adds the amount of time units to t in its location.
+//
+// Days and larger units are added to the local date,
+// and the day of the month is clamped to the last day of the resulting month.
+// It returns false if the result overflows.
+func addTimeUnits(t time.Time, unit timeUnit, amount int64) (time.Time, bool) {
+	if d, ok := unitDurations[unit]; ok {
+		delta, ok := mulInt64(amount, int64(d/time.Millisecond))
+		if !ok {
+			return time.Time{}, false
+		}
+
+		ms := t.UnixMilli() + delta
+		if (delta > 0 && ms < t.UnixMilli()) || (delta < 0 && ms > t.UnixMilli()) {
+			return time.Time{}, false
+		}
+
+		return time.UnixMilli(ms).In(t.Location()), true
+	}
+
+	// the range of dates is about ±292 million years
+	const maxMonths = 292_000_000 * 12
+
+	if m, ok := unitMonths[unit]; ok {
+		months, ok := mulInt64(amount, int64(m))
+		if !ok || months > maxMonths || months < -maxMonths {
+			return time.Time{}, false
+		}
+
+		y, mon, d := t.Date()
+		total := int64(mon-1) + months
+		year := int64(y) + floorDiv(total, 12)
+		mon = time.Month(total-floorDiv(total, 12)*12) + 1
+
+		// the last day of the month
+		d = min(d, time.Date(int(year), mon+1, 0, 0, 0, 0, 0, time.UTC).Day())
+
+		res := time.Date(int(year), mon, d, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+
+		return res, inDateRange(res)
+	}
+
+	days := amount
+	if unit == unitWeek {
+		var ok bool
+		if days, ok = mulInt64(amount, 7); !ok {
+			return time.Time{}, false
+		}
+	}
+
+	if days > maxMonths*31 || days < -maxMonths*31 {
+		return time.Time{}, false
+	}
+
+	res := t.AddDate(0, 0, int(days))
+
+	return res, inDateRange(res)
+}
+
+// inDateRange returns true if the number of milliseconds since Unix epoch of t fits in 64-bit integer.
+func inDateRange(t time.Time) bool {
+	return t.Unix() > math.MinInt64/1000 && t.Unix() < math.MaxInt64/1000
+}
+
+// mulInt64 returns a*b and true, or false if the result overflows.
+func mulInt64(a, b int64) (int64, bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+
+	res := a * b
+	if res/b != a || (a == -1 && b == math.MinInt64) || (b == -1 && a == math.MinInt64) {
+		return 0, false
+	}
+
+	return res, true
+}
+
+// check interfaces
+var (
+	_ Operator = (*dateAdd)(nil)
+)
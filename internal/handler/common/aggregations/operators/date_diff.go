@@ -0,0 +1,187 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+// dateDiff represents `$dateDiff` operator.
+type dateDiff struct {
+	startDate   any
+	endDate     any
+	unit        any
+	timezone    any // nil if not set
+	startOfWeek any // nil if not set
+}
+
+// newDateDiff returns `$dateDiff` operator.
+func newDateDiff(args ...any) (Operator, error) {
+	fields, err := namedArgs(
+		args,
+		[]string{"startDate", "endDate", "unit", "timezone", "startOfWeek"},
+		func(any) error {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrDateDiffNotObject,
+				"$dateDiff only supports an object as its argument",
+				"$dateDiff (operator)",
+			)
+		},
+		func(field string) error {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrDateDiffUnknownArgument,
+				fmt.Sprintf("Unrecognized argument to $dateDiff: %s", field),
+				"$dateDiff (operator)",
+			)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range []struct {
+		field string
+		code  handlererrors.ErrorCode
+	}{
+		{"startDate", handlererrors.ErrDateDiffMissingStartDate},
+		{"endDate", handlererrors.ErrDateDiffMissingEndDate},
+		{"unit", handlererrors.ErrDateDiffMissingUnit},
+	} {
+		if fields[f.field] == nil {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				f.code,
+				fmt.Sprintf("Missing '%s' parameter to $dateDiff", f.field),
+				"$dateDiff (operator)",
+			)
+		}
+	}
+
+	return &dateDiff{
+		startDate:   fields["startDate"],
+		endDate:     fields["endDate"],
+		unit:        fields["unit"],
+		timezone:    fields["timezone"],
+		startOfWeek: fields["startOfWeek"],
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// It returns the number of unit boundaries crossed between start and end dates as long,
+// or null if any argument is null or missing.
+func (d *dateDiff) Process(doc *types.Document, vars *aggregations.Variables) (any, error) {
+	values, err := evaluateArgs([]any{d.startDate, d.endDate, d.unit}, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range values {
+		if isNullish(v) {
+			return types.Null, nil
+		}
+	}
+
+	loc, err := evaluateTimezone("$dateDiff", d.timezone, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if loc == nil {
+		return types.Null, nil
+	}
+
+	dates := make([]time.Time, 2)
+
+	for i, field := range []string{"startDate", "endDate"} {
+		t, ok := toDate(values[i])
+		if !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrDateDiffInvalidDate,
+				fmt.Sprintf("$dateDiff requires '%s' to be a date, but got %s", field, typeAlias(values[i])),
+				"$dateDiff (operator)",
+			)
+		}
+
+		dates[i] = t.In(loc)
+	}
+
+	unit, err := getTimeUnit("$dateDiff", values[2], handlererrors.ErrTimeUnitNonString)
+	if err != nil {
+		return nil, err
+	}
+
+	startOfWeek := time.Sunday
+
+	if unit == unitWeek && d.startOfWeek != nil {
+		v, err := evaluate(d.startOfWeek, doc, vars)
+		if err != nil {
+			return nil, err
+		}
+
+		if isNullish(v) {
+			return types.Null, nil
+		}
+
+		if startOfWeek, err = getStartOfWeek("$dateDiff", v); err != nil {
+			return nil, err
+		}
+	}
+
+	return diffTimeUnits(dates[0], dates[1], unit, startOfWeek), nil
+}
+
+// diffTimeUnits returns the number of time unit boundaries between start and end
+// in their location.
+func diffTimeUnits(start, end time.Time, unit timeUnit, startOfWeek time.Weekday) int64 {
+	switch unit {
+	case unitMillisecond:
+		return end.UnixMilli() - start.UnixMilli()
+
+	case unitSecond, unitMinute, unitHour:
+		ms := int64(unitDurations[unit] / time.Millisecond)
+		return floorDiv(wallClock(end), ms) - floorDiv(wallClock(start), ms)
+
+	case unitDay:
+		return daysSinceEpoch(end) - daysSinceEpoch(start)
+
+	case unitWeek:
+		// Unix epoch was Thursday
+		week := func(t time.Time) int64 {
+			return floorDiv(daysSinceEpoch(t)+int64(time.Thursday-startOfWeek), 7)
+		}
+
+		return week(end) - week(start)
+
+	default:
+		months := int64(unitMonths[unit])
+
+		bin := func(t time.Time) int64 {
+			y, m, _ := t.Date()
+			return floorDiv(int64(y)*12+int64(m-1), months)
+		}
+
+		return bin(end) - bin(start)
+	}
+}
+
+// check interfaces
+var (
+	_ Operator = (*dateDiff)(nil)
+)
@@ -0,0 +1,431 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// dateFromStringSpecifiers contains format specifiers supported by `$dateFromString`.
+const dateFromStringSpecifiers = "dGHjLmMSuVYzZ%"
+
+// dateFromString represents `$dateFromString` operator.
+type dateFromString struct {
+	dateString any
+	format     any // nil if not set
+	timezone   any // nil if not set
+	onError    any // nil if not set
+	onNull     any // nil if not set
+}
+
+// newDateFromString returns `$dateFromString` operator.
+func newDateFromString(args ...any) (Operator, error) {
+	fields, err := namedArgs(
+		args,
+		[]string{"dateString", "format", "timezone", "onError", "onNull"},
+		func(v any) error {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrDateFromStringNotObject,
+				fmt.Sprintf(
+					"$dateFromString only supports an object as an argument, found: %s",
+					typeAlias(v),
+				),
+				"$dateFromString (operator)",
+			)
+		},
+		func(field string) error {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrDateFromStringUnknownArgument,
+				fmt.Sprintf("Unrecognized argument to $dateFromString: %s", field),
+				"$dateFromString (operator)",
+			)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if fields["dateString"] == nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrDateFromStringMissingDateString,
+			"Missing 'dateString' parameter to $dateFromString",
+			"$dateFromString (operator)",
+		)
+	}
+
+	return &dateFromString{
+		dateString: fields["dateString"],
+		format:     fields["format"],
+		timezone:   fields["timezone"],
+		onError:    fields["onError"],
+		onNull:     fields["onNull"],
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// If the date string is null or missing, the value of `onNull` or null is returned.
+// If the date string can't be parsed and `onError` is set, its value is returned.
+func (d *dateFromString) Process(doc *types.Document, vars *aggregations.Variables) (any, error) {
+	res, err := d.process(doc, vars)
+	if err == nil || d.onError == nil {
+		return res, err
+	}
+
+	var cmdErr *handlererrors.CommandError
+	if errors.As(err, &cmdErr) && cmdErr.Code() == handlererrors.ErrConversionFailure {
+		return evaluate(d.onError, doc, vars)
+	}
+
+	return nil, err
+}
+
+// process converts the date string to date.
+func (d *dateFromString) process(doc *types.Document, vars *aggregations.Variables) (any, error) {
+	format := ""
+
+	if d.format != nil {
+		v, err := evaluate(d.format, doc, vars)
+		if err != nil {
+			return nil, err
+		}
+
+		if isNullish(v) {
+			return types.Null, nil
+		}
+
+		var ok bool
+		if format, ok = v.(string); !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrDateFromStringFormatNonString,
+				fmt.Sprintf(
+					"$dateFromString requires that 'format' be a string, found: %s with value %s",
+					typeAlias(v), types.FormatAnyValue(v),
+				),
+				"$dateFromString (operator)",
+			)
+		}
+
+		if err = validateDateFormat("$dateFromString", format, dateFromStringSpecifiers); err != nil {
+			return nil, err
+		}
+	}
+
+	loc, err := evaluateTimezone("$dateFromString", d.timezone, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if loc == nil {
+		return types.Null, nil
+	}
+
+	v, err := evaluate(d.dateString, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if isNullish(v) {
+		if d.onNull != nil {
+			return evaluate(d.onNull, doc, vars)
+		}
+
+		return types.Null, nil
+	}
+
+	s, ok := v.(string)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrConversionFailure,
+			fmt.Sprintf(
+				"$dateFromString requires that 'dateString' be a string, found: %s with value %s",
+				typeAlias(v), types.FormatAnyValue(v),
+			),
+			"$dateFromString (operator)",
+		)
+	}
+
+	var p *parsedDate
+
+	if format == "" {
+		p, ok = parseDate(s)
+	} else {
+		p, ok = parseDateWithFormat(s, format)
+	}
+
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrConversionFailure,
+			fmt.Sprintf("Error parsing date string '%s'", s),
+			"$dateFromString (operator)",
+		)
+	}
+
+	if p.loc != nil {
+		if d.timezone != nil {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrConversionFailure,
+				fmt.Sprintf(
+					"you cannot pass in a date/time string with time zone information ('%s') together with a timezone argument",
+					s,
+				),
+				"$dateFromString (operator)",
+			)
+		}
+
+		loc = p.loc
+	}
+
+	t, ok := p.time(loc)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrConversionFailure,
+			fmt.Sprintf("Error parsing date string '%s'", s),
+			"$dateFromString (operator)",
+		)
+	}
+
+	return t.UTC(), nil
+}
+
+// parsedDate represents date components parsed from the string.
+type parsedDate struct {
+	loc *time.Location // nil if the string does not contain time zone information
+
+	year, month, day            int
+	hour, minute, second, milli int
+
+	yearDay int // 0 if not set
+
+	// set if ISO 8601 components were parsed
+	iso                              bool
+	isoWeekYear, isoWeek, isoWeekDay int
+}
+
+// time returns the date in the given location.
+// It returns false if some components are out of their ranges.
+func (p *parsedDate) time(loc *time.Location) (time.Time, bool) {
+	if p.month < 1 || p.month > 12 || p.day < 1 || p.day > 31 ||
+		p.hour > 23 || p.minute > 59 || p.second > 59 || p.milli > 999 {
+		return time.Time{}, false
+	}
+
+	var t time.Time
+
+	switch {
+	case p.iso:
+		if p.isoWeek < 1 || p.isoWeek > 53 || p.isoWeekDay < 1 || p.isoWeekDay > 7 {
+			return time.Time{}, false
+		}
+
+		t = isoWeekDate(p.isoWeekYear, p.isoWeek, p.isoWeekDay, p.hour, p.minute, p.second, p.milli, loc)
+
+	case p.yearDay != 0:
+		if p.yearDay > 366 {
+			return time.Time{}, false
+		}
+
+		t = time.Date(p.year, 1, p.yearDay, p.hour, p.minute, p.second, p.milli*int(time.Millisecond), loc)
+		if t.Year() != p.year {
+			return time.Time{}, false
+		}
+
+	default:
+		t = time.Date(p.year, time.Month(p.month), p.day, p.hour, p.minute, p.second, p.milli*int(time.Millisecond), loc)
+		if t.Day() != p.day {
+			return time.Time{}, false
+		}
+	}
+
+	return t, true
+}
+
+// dateStringRe matches date strings in ISO 8601 format with optional time and UTC offset.
+var dateStringRe = regexp.MustCompile(
+	`^(\d{4})-(\d{2})-(\d{2})(?:[T ](\d{2}):(\d{2})(?::(\d{2})(?:\.(\d{1,3})\d*)?)?)?\s*(Z|[+-]\d{2}(?::?\d{2})?)?$`,
+)
+
+// utcOffsetPrefixRe matches UTC offset in `+/-[hh][mm]` and `+/-[hh]:[mm]` formats at the start of the string.
+var utcOffsetPrefixRe = regexp.MustCompile(`^[+-]\d{2}:?\d{2}`)
+
+// parseDate parses the date string without format.
+func parseDate(s string) (*parsedDate, bool) {
+	m := dateStringRe.FindStringSubmatch(s)
+	if m == nil {
+		return nil, false
+	}
+
+	atoi := func(s string) int {
+		if s == "" {
+			return 0
+		}
+
+		return must.NotFail(strconv.Atoi(s))
+	}
+
+	p := &parsedDate{
+		year:   atoi(m[1]),
+		month:  atoi(m[2]),
+		day:    atoi(m[3]),
+		hour:   atoi(m[4]),
+		minute: atoi(m[5]),
+		second: atoi(m[6]),
+	}
+
+	if m[7] != "" {
+		p.milli = atoi((m[7] + "00")[:3])
+	}
+
+	switch m[8] {
+	case "":
+	case "Z":
+		p.loc = time.UTC
+	default:
+		p.loc, _ = loadLocation(m[8])
+	}
+
+	return p, true
+}
+
+// parseDateWithFormat parses the date string with the validated format.
+func parseDateWithFormat(s, format string) (*parsedDate, bool) {
+	p := &parsedDate{
+		year:       1970,
+		month:      1,
+		day:        1,
+		isoWeek:    1,
+		isoWeekDay: 1,
+	}
+
+	// digits reads from 1 to n digits from s
+	digits := func(n int) (int, bool) {
+		var i int
+		for i < n && i < len(s) && s[i] >= '0' && s[i] <= '9' {
+			i++
+		}
+
+		if i == 0 {
+			return 0, false
+		}
+
+		v := must.NotFail(strconv.Atoi(s[:i]))
+		s = s[i:]
+
+		return v, true
+	}
+
+	// literal reads the given character from s
+	literal := func(c byte) bool {
+		if s == "" || s[0] != c {
+			return false
+		}
+
+		s = s[1:]
+
+		return true
+	}
+
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			if !literal(format[i]) {
+				return nil, false
+			}
+
+			continue
+		}
+
+		i++
+
+		var ok bool
+
+		switch format[i] {
+		case 'd':
+			p.day, ok = digits(2)
+		case 'G':
+			p.iso = true
+			p.isoWeekYear, ok = digits(4)
+		case 'H':
+			p.hour, ok = digits(2)
+		case 'j':
+			p.yearDay, ok = digits(3)
+			ok = ok && p.yearDay > 0
+		case 'L':
+			p.milli, ok = digits(3)
+		case 'm':
+			p.month, ok = digits(2)
+		case 'M':
+			p.minute, ok = digits(2)
+		case 'S':
+			p.second, ok = digits(2)
+		case 'u':
+			p.iso = true
+			p.isoWeekDay, ok = digits(1)
+		case 'V':
+			p.iso = true
+			p.isoWeek, ok = digits(2)
+		case 'Y':
+			p.year, ok = digits(4)
+		case '%':
+			ok = literal('%')
+		case 'z':
+			m := utcOffsetPrefixRe.FindString(s)
+			if ok = m != ""; ok {
+				p.loc, _ = loadLocation(m)
+				s = s[len(m):]
+			}
+		case 'Z':
+			if ok = s != "" && (s[0] == '+' || s[0] == '-'); !ok {
+				break
+			}
+
+			sign := s[0]
+			s = s[1:]
+
+			var minutes int
+			if minutes, ok = digits(4); ok {
+				offset := minutes * 60
+				if sign == '-' {
+					offset = -offset
+				}
+
+				p.loc = time.FixedZone("", offset)
+			}
+		}
+
+		if !ok {
+			return nil, false
+		}
+	}
+
+	if s != "" {
+		return nil, false
+	}
+
+	return p, true
+}
+
+// check interfaces
+var (
+	_ Operator = (*dateFromString)(nil)
+)
@@ -0,0 +1,313 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// dateToParts represents `$dateToParts` operator.
+type dateToParts struct {
+	date     any
+	timezone any // nil if not set
+	iso8601  any // nil if not set
+}
+
+// newDateToParts returns `$dateToParts` operator.
+func newDateToParts(args ...any) (Operator, error) {
+	fields, err := namedArgs(
+		args,
+		[]string{"date", "timezone", "iso8601"},
+		func(any) error {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrDateToPartsNotObject,
+				"$dateToParts only supports an object as its argument",
+				"$dateToParts (operator)",
+			)
+		},
+		func(field string) error {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrDateToPartsUnknownArgument,
+				fmt.Sprintf("Unrecognized argument to $dateToParts: %s", field),
+				"$dateToParts (operator)",
+			)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if fields["date"] == nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrDateToPartsMissingDate,
+			"Missing 'date' parameter to $dateToParts",
+			"$dateToParts (operator)",
+		)
+	}
+
+	return &dateToParts{
+		date:     fields["date"],
+		timezone: fields["timezone"],
+		iso8601:  fields["iso8601"],
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// It returns null if any argument is null or missing.
+func (d *dateToParts) Process(doc *types.Document, vars *aggregations.Variables) (any, error) {
+	var iso bool
+
+	if d.iso8601 != nil {
+		v, err := evaluate(d.iso8601, doc, vars)
+		if err != nil {
+			return nil, err
+		}
+
+		if isNullish(v) {
+			return types.Null, nil
+		}
+
+		var ok bool
+		if iso, ok = v.(bool); !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrDateToPartsISO8601NonBool,
+				fmt.Sprintf("iso8601 must evaluate to a bool, found %s", typeAlias(v)),
+				"$dateToParts (operator)",
+			)
+		}
+	}
+
+	loc, err := evaluateTimezone("$dateToParts", d.timezone, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if loc == nil {
+		return types.Null, nil
+	}
+
+	v, err := evaluate(d.date, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if isNullish(v) {
+		return types.Null, nil
+	}
+
+	t, ok := toDate(v)
+	if !ok {
+		return nil, newConvertToDateError("$dateToParts", v)
+	}
+
+	t = t.In(loc)
+
+	if iso {
+		return must.NotFail(types.NewDocument(
+			"isoWeekYear", int32(isoWeekYear(t)),
+			"isoWeek", int32(isoWeek(t)),
+			"isoDayOfWeek", int32(isoDayOfWeek(t)),
+			"hour", int32(t.Hour()),
+			"minute", int32(t.Minute()),
+			"second", int32(t.Second()),
+			"millisecond", int32(millisecond(t)),
+		)), nil
+	}
+
+	return must.NotFail(types.NewDocument(
+		"year", int32(t.Year()),
+		"month", int32(t.Month()),
+		"day", int32(t.Day()),
+		"hour", int32(t.Hour()),
+		"minute", int32(t.Minute()),
+		"second", int32(t.Second()),
+		"millisecond", int32(millisecond(t)),
+	)), nil
+}
+
+// dateFromPartsFields contains the names of `$dateFromParts` date parts in the order of evaluation;
+// natural and ISO 8601 date fields are followed by common time fields.
+var dateFromPartsFields = []string{
+	"year", "month", "day",
+	"isoWeekYear", "isoWeek", "isoDayOfWeek",
+	"hour", "minute", "second", "millisecond",
+}
+
+// dateFromParts represents `$dateFromParts` operator.
+type dateFromParts struct {
+	parts    map[string]any
+	timezone any // nil if not set
+	iso      bool
+}
+
+// newDateFromParts returns `$dateFromParts` operator.
+func newDateFromParts(args ...any) (Operator, error) {
+	fields, err := namedArgs(
+		args,
+		append([]string{"timezone"}, dateFromPartsFields...),
+		func(any) error {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrDateFromPartsNotObject,
+				"$dateFromParts only supports an object as its argument",
+				"$dateFromParts (operator)",
+			)
+		},
+		func(field string) error {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrDateFromPartsUnknownArgument,
+				fmt.Sprintf("Unrecognized argument to $dateFromParts: %s", field),
+				"$dateFromParts (operator)",
+			)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	natural := fields["year"] != nil || fields["month"] != nil || fields["day"] != nil
+	iso := fields["isoWeekYear"] != nil || fields["isoWeek"] != nil || fields["isoDayOfWeek"] != nil
+
+	if natural && iso {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrDateFromPartsMixed,
+			"$dateFromParts does not allow mixing natural dates with ISO dates",
+			"$dateFromParts (operator)",
+		)
+	}
+
+	if fields["year"] == nil && fields["isoWeekYear"] == nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrDateFromPartsMissingYear,
+			"$dateFromParts requires either 'year' or 'isoWeekYear' to be present",
+			"$dateFromParts (operator)",
+		)
+	}
+
+	d := &dateFromParts{
+		parts:    fields,
+		timezone: fields["timezone"],
+		iso:      iso,
+	}
+
+	delete(d.parts, "timezone")
+
+	return d, nil
+}
+
+// Process implements Operator interface.
+//
+// It returns null if any argument is null or missing.
+func (d *dateFromParts) Process(doc *types.Document, vars *aggregations.Variables) (any, error) {
+	values := map[string]int{
+		"month":        1,
+		"day":          1,
+		"isoWeek":      1,
+		"isoDayOfWeek": 1,
+	}
+
+	for _, field := range dateFromPartsFields {
+		arg := d.parts[field]
+		if arg == nil {
+			continue
+		}
+
+		v, err := evaluate(arg, doc, vars)
+		if err != nil {
+			return nil, err
+		}
+
+		if isNullish(v) {
+			return types.Null, nil
+		}
+
+		i, ok := integralToInt64(v)
+		if !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrDateFromPartsNotIntegral,
+				fmt.Sprintf(
+					"'%s' must evaluate to an integer, found %s with value %s",
+					field, typeAlias(v), types.FormatAnyValue(v),
+				),
+				"$dateFromParts (operator)",
+			)
+		}
+
+		switch field {
+		case "year", "isoWeekYear":
+			if i < 1 || i > 9999 {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrDateFromPartsYearOutOfRange,
+					fmt.Sprintf("'%s' must evaluate to an integer in the range 1 to 9999, found %d", field, i),
+					"$dateFromParts (operator)",
+				)
+			}
+
+		default:
+			if i < math.MinInt16 || i > math.MaxInt16 {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrDateFromPartsOutOfRange,
+					fmt.Sprintf(
+						"'%s' must evaluate to a value in the range [-32768, 32767]; value %d is not in range",
+						field, i,
+					),
+					"$dateFromParts (operator)",
+				)
+			}
+		}
+
+		values[field] = int(i)
+	}
+
+	loc, err := evaluateTimezone("$dateFromParts", d.timezone, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if loc == nil {
+		return types.Null, nil
+	}
+
+	var t time.Time
+
+	if d.iso {
+		t = isoWeekDate(
+			values["isoWeekYear"], values["isoWeek"], values["isoDayOfWeek"],
+			values["hour"], values["minute"], values["second"], values["millisecond"],
+			loc,
+		)
+	} else {
+		t = time.Date(
+			values["year"], time.Month(values["month"]), values["day"],
+			values["hour"], values["minute"], values["second"], values["millisecond"]*int(time.Millisecond),
+			loc,
+		)
+	}
+
+	return t.UTC(), nil
+}
+
+// check interfaces
+var (
+	_ Operator = (*dateToParts)(nil)
+	_ Operator = (*dateFromParts)(nil)
+)
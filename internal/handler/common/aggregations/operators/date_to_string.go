@@ -0,0 +1,236 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+// dateToStringSpecifiers contains format specifiers supported by `$dateToString`.
+const dateToStringSpecifiers = "dGHjLmMSuUVwYzZ%"
+
+// dateToString represents `$dateToString` operator.
+type dateToString struct {
+	date     any
+	format   any // nil if not set
+	timezone any // nil if not set
+	onNull   any // nil if not set
+}
+
+// newDateToString returns `$dateToString` operator.
+func newDateToString(args ...any) (Operator, error) {
+	fields, err := namedArgs(
+		args,
+		[]string{"date", "format", "timezone", "onNull"},
+		func(any) error {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrDateToStringNotObject,
+				"$dateToString only supports an object as its argument",
+				"$dateToString (operator)",
+			)
+		},
+		func(field string) error {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrDateToStringUnknownArgument,
+				fmt.Sprintf("Unrecognized argument to $dateToString: %s", field),
+				"$dateToString (operator)",
+			)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if fields["date"] == nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrDateToStringMissingDate,
+			"Missing 'date' parameter to $dateToString",
+			"$dateToString (operator)",
+		)
+	}
+
+	return &dateToString{
+		date:     fields["date"],
+		format:   fields["format"],
+		timezone: fields["timezone"],
+		onNull:   fields["onNull"],
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// If the date is null or missing, the value of `onNull` or null is returned.
+// If the format or the time zone is null or missing, null is returned.
+func (d *dateToString) Process(doc *types.Document, vars *aggregations.Variables) (any, error) {
+	format := "%Y-%m-%dT%H:%M:%S.%LZ"
+	if d.timezone != nil {
+		format = "%Y-%m-%dT%H:%M:%S.%L"
+	}
+
+	if d.format != nil {
+		v, err := evaluate(d.format, doc, vars)
+		if err != nil {
+			return nil, err
+		}
+
+		if isNullish(v) {
+			return types.Null, nil
+		}
+
+		var ok bool
+		if format, ok = v.(string); !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrDateToStringFormatNonString,
+				fmt.Sprintf(
+					"$dateToString requires that 'format' be a string, found: %s with value %s",
+					typeAlias(v), types.FormatAnyValue(v),
+				),
+				"$dateToString (operator)",
+			)
+		}
+
+		if err = validateDateFormat("$dateToString", format, dateToStringSpecifiers); err != nil {
+			return nil, err
+		}
+	}
+
+	loc, err := evaluateTimezone("$dateToString", d.timezone, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if loc == nil {
+		return types.Null, nil
+	}
+
+	v, err := evaluate(d.date, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if isNullish(v) {
+		if d.onNull != nil {
+			return evaluate(d.onNull, doc, vars)
+		}
+
+		return types.Null, nil
+	}
+
+	t, ok := toDate(v)
+	if !ok {
+		return nil, newConvertToDateError("$dateToString", v)
+	}
+
+	return formatDate(t.In(loc), format), nil
+}
+
+// validateDateFormat checks that the format string contains only given format specifiers.
+func validateDateFormat(name, format, specifiers string) error {
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+
+		if i == len(format)-1 {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrDateFormatUnmatchedPercent,
+				"Unmatched '%' at end of format string",
+				name+" (operator)",
+			)
+		}
+
+		i++
+
+		if !strings.ContainsRune(specifiers, rune(format[i])) {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrDateFormatInvalidCharacter,
+				fmt.Sprintf("Invalid format character '%%%c' in format string", format[i]),
+				name+" (operator)",
+			)
+		}
+	}
+
+	return nil
+}
+
+// formatDate formats the date according to the validated format string.
+func formatDate(t time.Time, format string) string {
+	var res strings.Builder
+
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			res.WriteByte(format[i])
+			continue
+		}
+
+		i++
+
+		switch format[i] {
+		case 'd':
+			fmt.Fprintf(&res, "%02d", t.Day())
+		case 'G':
+			fmt.Fprintf(&res, "%04d", isoWeekYear(t))
+		case 'H':
+			fmt.Fprintf(&res, "%02d", t.Hour())
+		case 'j':
+			fmt.Fprintf(&res, "%03d", t.YearDay())
+		case 'L':
+			fmt.Fprintf(&res, "%03d", millisecond(t))
+		case 'm':
+			fmt.Fprintf(&res, "%02d", t.Month())
+		case 'M':
+			fmt.Fprintf(&res, "%02d", t.Minute())
+		case 'S':
+			fmt.Fprintf(&res, "%02d", t.Second())
+		case 'u':
+			fmt.Fprintf(&res, "%d", isoDayOfWeek(t))
+		case 'U':
+			fmt.Fprintf(&res, "%02d", week(t))
+		case 'V':
+			fmt.Fprintf(&res, "%02d", isoWeek(t))
+		case 'w':
+			fmt.Fprintf(&res, "%d", dayOfWeek(t))
+		case 'Y':
+			fmt.Fprintf(&res, "%04d", t.Year())
+		case 'z':
+			_, offset := t.Zone()
+
+			sign := '+'
+			if offset < 0 {
+				sign, offset = '-', -offset
+			}
+
+			fmt.Fprintf(&res, "%c%02d%02d", sign, offset/3600, offset%3600/60)
+		case 'Z':
+			_, offset := t.Zone()
+			fmt.Fprintf(&res, "%+d", offset/60)
+		case '%':
+			res.WriteByte('%')
+		}
+	}
+
+	return res.String()
+}
+
+// check interfaces
+var (
+	_ Operator = (*dateToString)(nil)
+)
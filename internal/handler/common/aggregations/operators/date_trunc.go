@@ -0,0 +1,224 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+// dateTrunc represents `$dateTrunc` operator.
+type dateTrunc struct {
+	date        any
+	unit        any
+	binSize     any // nil if not set
+	timezone    any // nil if not set
+	startOfWeek any // nil if not set
+}
+
+// newDateTrunc returns `$dateTrunc` operator.
+func newDateTrunc(args ...any) (Operator, error) {
+	fields, err := namedArgs(
+		args,
+		[]string{"date", "unit", "binSize", "timezone", "startOfWeek"},
+		func(any) error {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrDateTruncNotObject,
+				"$dateTrunc only supports an object as its argument",
+				"$dateTrunc (operator)",
+			)
+		},
+		func(field string) error {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrDateTruncUnknownArgument,
+				fmt.Sprintf("Unrecognized argument to $dateTrunc: %s", field),
+				"$dateTrunc (operator)",
+			)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if fields["date"] == nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrDateTruncMissingDate,
+			"Missing 'date' parameter to $dateTrunc",
+			"$dateTrunc (operator)",
+		)
+	}
+
+	if fields["unit"] == nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrDateTruncMissingUnit,
+			"Missing 'unit' parameter to $dateTrunc",
+			"$dateTrunc (operator)",
+		)
+	}
+
+	return &dateTrunc{
+		date:        fields["date"],
+		unit:        fields["unit"],
+		binSize:     fields["binSize"],
+		timezone:    fields["timezone"],
+		startOfWeek: fields["startOfWeek"],
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// It returns null if any argument is null or missing.
+func (d *dateTrunc) Process(doc *types.Document, vars *aggregations.Variables) (any, error) {
+	values, err := evaluateArgs([]any{d.date, d.unit}, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range values {
+		if isNullish(v) {
+			return types.Null, nil
+		}
+	}
+
+	loc, err := evaluateTimezone("$dateTrunc", d.timezone, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if loc == nil {
+		return types.Null, nil
+	}
+
+	t, ok := toDate(values[0])
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrDateTruncInvalidDate,
+			fmt.Sprintf("$dateTrunc requires 'date' to be a date, but got %s", typeAlias(values[0])),
+			"$dateTrunc (operator)",
+		)
+	}
+
+	unit, err := getTimeUnit("$dateTrunc", values[1], handlererrors.ErrTimeUnitNonString)
+	if err != nil {
+		return nil, err
+	}
+
+	binSize := int64(1)
+
+	if d.binSize != nil {
+		v, err := evaluate(d.binSize, doc, vars)
+		if err != nil {
+			return nil, err
+		}
+
+		if isNullish(v) {
+			return types.Null, nil
+		}
+
+		if binSize, ok = integralToInt64(v); !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrDateTruncBinSizeNotIntegral,
+				fmt.Sprintf(
+					"$dateTrunc requires 'binSize' to be a 64-bit integer, but got value '%s' of type %s",
+					types.FormatAnyValue(v), typeAlias(v),
+				),
+				"$dateTrunc (operator)",
+			)
+		}
+
+		if binSize <= 0 {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrDateTruncBinSizeNotPositive,
+				fmt.Sprintf("$dateTrunc requires 'binSize' to be greater than 0, but got value %d", binSize),
+				"$dateTrunc (operator)",
+			)
+		}
+	}
+
+	startOfWeek := time.Sunday
+
+	if unit == unitWeek && d.startOfWeek != nil {
+		v, err := evaluate(d.startOfWeek, doc, vars)
+		if err != nil {
+			return nil, err
+		}
+
+		if isNullish(v) {
+			return types.Null, nil
+		}
+
+		if startOfWeek, err = getStartOfWeek("$dateTrunc", v); err != nil {
+			return nil, err
+		}
+	}
+
+	return truncTimeUnits(t.In(loc), unit, binSize, startOfWeek).UTC(), nil
+}
+
+// truncTimeUnits returns the start of the bin of binSize time units that contains t in its location.
+//
+// Bins are aligned to 2000-01-01T00:00:00 in the location,
+// or to the first startOfWeek day of 2000 for weeks.
+func truncTimeUnits(t time.Time, unit timeUnit, binSize int64, startOfWeek time.Weekday) time.Time {
+	loc := t.Location()
+	ref := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// bin returns the start of the bin of size n that contains v relative to the reference point
+	bin := func(v, n int64) int64 {
+		return floorDiv(v, n) * n
+	}
+
+	// size returns the bin size in the given units
+	size := func(units int64) int64 {
+		res, ok := mulInt64(binSize, units)
+		if !ok {
+			return math.MaxInt64
+		}
+
+		return res
+	}
+
+	if d, ok := unitDurations[unit]; ok {
+		ms := bin(wallClock(t)-ref.UnixMilli(), size(int64(d/time.Millisecond)))
+		return fromWallClock(ref.UnixMilli()+ms, loc)
+	}
+
+	if m, ok := unitMonths[unit]; ok {
+		y, mon, _ := t.Date()
+		months := bin(int64(y-2000)*12+int64(mon-1), size(int64(m)))
+
+		return time.Date(2000, time.Month(months+1), 1, 0, 0, 0, 0, loc)
+	}
+
+	days := daysSinceEpoch(t) - daysSinceEpoch(ref)
+	if unit == unitDay {
+		return time.Date(2000, 1, int(bin(days, size(1))+1), 0, 0, 0, 0, loc)
+	}
+
+	// the first startOfWeek day of 2000
+	first := int64(startOfWeek-ref.Weekday()+7) % 7
+
+	return time.Date(2000, 1, int(bin(days-first, size(7))+first+1), 0, 0, 0, 0, loc)
+}
+
+// check interfaces
+var (
+	_ Operator = (*dateTrunc)(nil)
+)
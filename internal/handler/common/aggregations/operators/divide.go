@@ -0,0 +1,102 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+// divide represents `$divide` operator.
+type divide struct {
+	dividend any
+	divisor  any
+}
+
+// newDivide returns `$divide` operator.
+func newDivide(args ...any) (Operator, error) {
+	if len(args) != 2 {
+		return nil, newArgsLenError("$divide", 2, len(args))
+	}
+
+	return &divide{
+		dividend: args[0],
+		divisor:  args[1],
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// The result is double, or decimal if any argument is decimal.
+// Null is returned if any argument is null or missing.
+func (d *divide) Process(doc *types.Document, vars *aggregations.Variables) (any, error) {
+	values, err := evaluateArgs([]any{d.dividend, d.divisor}, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	a, b := values[0], values[1]
+
+	switch {
+	case aggregations.IsNumber(a) && aggregations.IsNumber(b):
+		if isZero(b) {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrDivideByZero,
+				"can't $divide by zero",
+				"$divide (operator)",
+			)
+		}
+
+		return aggregations.DivideNumbers(a, b), nil
+
+	case isNullish(a) || isNullish(b):
+		return types.Null, nil
+
+	default:
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrDivideNonNumeric,
+			fmt.Sprintf(
+				"$divide only supports numeric types, not %s and %s",
+				handlerparams.AliasFromType(a), handlerparams.AliasFromType(b),
+			),
+			"$divide (operator)",
+		)
+	}
+}
+
+// isZero returns true if the given number is zero.
+func isZero(v any) bool {
+	switch v := v.(type) {
+	case float64:
+		return v == 0
+	case int32:
+		return v == 0
+	case int64:
+		return v == 0
+	case types.Decimal128:
+		return v.Sign() == 0 && !v.IsNaN()
+	default:
+		return false
+	}
+}
+
+// check interfaces
+var (
+	_ Operator = (*divide)(nil)
+)
@@ -0,0 +1,322 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+)
+
+// evaluate returns the value of operator's argument for the given document.
+//
+// The argument could be a nested operator, a path expression,
+// a document or an array with fields or elements that are evaluated recursively,
+// or a literal value that is returned as is.
+//
+// It returns nil for a path expression of a missing field;
+// document fields that evaluate to missing values are omitted,
+// and array elements that evaluate to missing values are set to null.
+func evaluate(arg any, doc *types.Document, vars *aggregations.Variables) (any, error) {
+	switch arg := arg.(type) {
+	case *types.Document:
+		if IsOperator(arg) {
+			op, err := NewOperator(arg)
+			if err != nil {
+				var opErr OperatorError
+				if errors.As(err, &opErr) && opErr.Code() == ErrInvalidExpression {
+					opErr.code = ErrInvalidNestedExpression
+					return nil, opErr
+				}
+
+				return nil, err
+			}
+
+			return op.Process(doc, vars)
+		}
+
+		res := types.MakeDocument(arg.Len())
+
+		iter := arg.Iterator()
+		defer iter.Close()
+
+		for {
+			k, v, err := iter.Next()
+			if errors.Is(err, iterator.ErrIteratorDone) {
+				break
+			}
+
+			if err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+
+			if v, err = evaluate(v, doc, vars); err != nil {
+				return nil, err
+			}
+
+			if v != nil {
+				res.Set(k, v)
+			}
+		}
+
+		return res, nil
+
+	case *types.Array:
+		res := types.MakeArray(arg.Len())
+
+		iter := arg.Iterator()
+		defer iter.Close()
+
+		for {
+			_, v, err := iter.Next()
+			if errors.Is(err, iterator.ErrIteratorDone) {
+				break
+			}
+
+			if err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+
+			if v, err = evaluate(v, doc, vars); err != nil {
+				return nil, err
+			}
+
+			if v == nil {
+				v = types.Null
+			}
+
+			res.Append(v)
+		}
+
+		return res, nil
+
+	case string:
+		expression, err := aggregations.NewExpression(arg, nil)
+		if err != nil {
+			var exprErr *aggregations.ExpressionError
+			if errors.As(err, &exprErr) && exprErr.Code() == aggregations.ErrNotExpression {
+				return arg, nil
+			}
+
+			return nil, err
+		}
+
+		v, err := expression.Evaluate(doc, vars)
+		if err != nil {
+			if err = checkUndefinedVariable(err); err != nil {
+				return nil, err
+			}
+
+			// missing field
+			return nil, nil
+		}
+
+		return v, nil
+
+	default:
+		return arg, nil
+	}
+}
+
+// Evaluate returns the value of the expression for the given document,
+// the same way as arguments of operators are evaluated.
+// It is used by expressions that are not operators, like arguments of accumulators.
+// See evaluate for details.
+func Evaluate(expr any, doc *types.Document, vars *aggregations.Variables) (any, error) {
+	return evaluate(expr, doc, vars)
+}
+
+// ValidateExpression returns an error if the expression evaluated by Evaluate is invalid,
+// like unknown operator or invalid path expression.
+//
+// Operator errors that could happen only during evaluation are ignored.
+// Errors of operators and path expressions are returned as is,
+// so they could be processed by the stage.
+func ValidateExpression(expr any) error {
+	switch expr := expr.(type) {
+	case *types.Document:
+		if !IsOperator(expr) {
+			return nil
+		}
+
+		op, err := NewOperator(expr)
+		if err != nil {
+			return err
+		}
+
+		if _, err = op.Process(nil, nil); err != nil && !IsEvaluationError(err) {
+			return err
+		}
+
+	case string:
+		_, err := aggregations.NewExpression(expr, nil)
+
+		var exprErr *aggregations.ExpressionError
+		if err != nil && !(errors.As(err, &exprErr) && exprErr.Code() == aggregations.ErrNotExpression) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// evaluateArgs evaluates all operator's arguments for the given document.
+// See evaluate for details.
+func evaluateArgs(args []any, doc *types.Document, vars *aggregations.Variables) ([]any, error) {
+	res := make([]any, len(args))
+
+	for i, arg := range args {
+		v, err := evaluate(arg, doc, vars)
+		if err != nil {
+			return nil, err
+		}
+
+		res[i] = v
+	}
+
+	return res, nil
+}
+
+// checkUndefinedVariable returns CommandError if the given error of expression evaluation
+// was caused by undefined variable, and nil otherwise.
+func checkUndefinedVariable(err error) error {
+	var exprErr *aggregations.ExpressionError
+	if !errors.As(err, &exprErr) || exprErr.Code() != aggregations.ErrUndefinedVariable {
+		return nil
+	}
+
+	return handlererrors.NewCommandErrorMsg(
+		handlererrors.ErrGroupUndefinedVariable,
+		"Use of undefined variable: "+exprErr.Name(),
+	)
+}
+
+// isNullish returns true if the value is missing (nil) or null.
+func isNullish(v any) bool {
+	if v == nil {
+		return true
+	}
+
+	_, ok := v.(types.NullType)
+
+	return ok
+}
+
+// newArgsLenError returns an error for the operator that takes exactly the given number of arguments.
+func newArgsLenError(name string, expected, actual int) error {
+	return newOperatorError(
+		ErrArgsInvalidLen,
+		name,
+		fmt.Sprintf("Expression %s takes exactly %d arguments. %d were passed in.", name, expected, actual),
+	)
+}
+
+// integralToInt64 returns the given number as int64
+// if it is integral and could be represented as a 64-bit integer.
+func integralToInt64(v any) (int64, bool) {
+	switch v := v.(type) {
+	case int32:
+		return int64(v), true
+
+	case int64:
+		return v, true
+
+	case float64:
+		if v != math.Trunc(v) || v < math.MinInt64 || v >= math.MaxInt64 {
+			return 0, false
+		}
+
+		return int64(v), true
+
+	case types.Decimal128:
+		if !v.IsInteger() {
+			return 0, false
+		}
+
+		return v.Int64()
+
+	default:
+		return 0, false
+	}
+}
+
+// integralToInt32 returns the given number as int32
+// if it is integral and could be represented as a 32-bit integer.
+func integralToInt32(v any) (int32, bool) {
+	i, ok := integralToInt64(v)
+	if !ok || i < math.MinInt32 || i > math.MaxInt32 {
+		return 0, false
+	}
+
+	return int32(i), true
+}
+
+// namedArgs returns named arguments of the operator that takes a single document
+// with the given fields, like `{$op: {field1: <expression>, field2: <expression>}}`.
+//
+// If the argument is not a document, the error returned by notObject for the argument is returned.
+// If the document contains an unknown field, the error returned by unknown for that field is returned.
+func namedArgs(
+	args []any,
+	fields []string,
+	notObject func(v any) error,
+	unknown func(field string) error,
+) (map[string]any, error) {
+	var spec *types.Document
+
+	if len(args) == 1 {
+		spec, _ = args[0].(*types.Document)
+	}
+
+	if spec == nil {
+		var v any = types.MakeArray(len(args))
+		if len(args) == 1 {
+			v = args[0]
+		}
+
+		return nil, notObject(v)
+	}
+
+	res := make(map[string]any, spec.Len())
+
+	iter := spec.Iterator()
+	defer iter.Close()
+
+	for {
+		k, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if !slices.Contains(fields, k) {
+			return nil, unknown(k)
+		}
+
+		res[k] = v
+	}
+
+	return res, nil
+}
@@ -52,8 +52,8 @@ func NewExpr(exprValue *types.Document, errArgument string) (Operator, error) {
 }
 
 // Process implements Operator interface.
-func (e *expr) Process(doc *types.Document) (any, error) {
-	return e.processExpr(e.exprValue, doc)
+func (e *expr) Process(doc *types.Document, vars *aggregations.Variables) (any, error) {
+	return e.processExpr(e.exprValue, doc, vars)
 }
 
 // processExpr recursively validates operators and expressions.
@@ -69,8 +69,8 @@ func (e *expr) validateExpr(exprValue any) error {
 				return processExprOperatorErrors(err, e.errArgument)
 			}
 
-			_, err = op.Process(nil)
-			if err != nil {
+			_, err = op.Process(nil, nil)
+			if err != nil && !IsEvaluationError(err) {
 				// TODO https://github.com/FerretDB/FerretDB/issues/3129
 				return processExprOperatorErrors(err, e.errArgument)
 			}
@@ -134,7 +134,7 @@ func (e *expr) validateExpr(exprValue any) error {
 // Each array values and document fields are processed recursively.
 // String expression is evaluated if any, and Null is returned if field is missing.
 // Any value that does not require processing, it returns the original value.
-func (e *expr) processExpr(exprValue any, doc *types.Document) (any, error) {
+func (e *expr) processExpr(exprValue any, doc *types.Document, vars *aggregations.Variables) (any, error) {
 	switch exprValue := exprValue.(type) {
 	case *types.Document:
 		if IsOperator(exprValue) {
@@ -144,12 +144,17 @@ func (e *expr) processExpr(exprValue any, doc *types.Document) (any, error) {
 				return nil, lazyerrors.Error(err)
 			}
 
-			v, err := op.Process(doc)
+			v, err := op.Process(doc, vars)
 			if err != nil {
-				// Process does not return error for existing operators
+				// evaluation errors are returned to the client as is
 				return nil, lazyerrors.Error(err)
 			}
 
+			if v == nil {
+				// missing value is set to null
+				return types.Null, nil
+			}
+
 			return v, nil
 		}
 
@@ -168,7 +173,7 @@ func (e *expr) processExpr(exprValue any, doc *types.Document) (any, error) {
 				return nil, lazyerrors.Error(err)
 			}
 
-			processed, err := e.processExpr(v, doc)
+			processed, err := e.processExpr(v, doc, vars)
 			if err != nil {
 				return nil, lazyerrors.Error(err)
 			}
@@ -193,7 +198,7 @@ func (e *expr) processExpr(exprValue any, doc *types.Document) (any, error) {
 				return nil, lazyerrors.Error(err)
 			}
 
-			processed, err := e.processExpr(v, doc)
+			processed, err := e.processExpr(v, doc, vars)
 			if err != nil {
 				return nil, lazyerrors.Error(err)
 			}
@@ -216,8 +221,12 @@ func (e *expr) processExpr(exprValue any, doc *types.Document) (any, error) {
 			return nil, lazyerrors.Error(err)
 		}
 
-		v, err := expression.Evaluate(doc)
+		v, err := expression.Evaluate(doc, vars)
 		if err != nil {
+			if err = checkUndefinedVariable(err); err != nil {
+				return nil, err
+			}
+
 			// missing field is set to null
 			return types.Null, nil
 		}
@@ -285,10 +294,9 @@ func processExprOperatorErrors(err error, argument string) error {
 				argument,
 			)
 		case aggregations.ErrUndefinedVariable:
-			// TODO https://github.com/FerretDB/FerretDB/issues/2275
 			return handlererrors.NewCommandErrorMsgWithArgument(
-				handlererrors.ErrNotImplemented,
-				"Aggregation expression variables are not implemented yet",
+				handlererrors.ErrGroupUndefinedVariable,
+				"Use of undefined variable: "+exErr.Name(),
 				argument,
 			)
 		case aggregations.ErrEmptyVariable:
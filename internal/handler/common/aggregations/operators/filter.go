@@ -0,0 +1,161 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// filter represents `$filter` operator.
+type filter struct {
+	input any
+	as    string
+	cond  any
+	limit any // nil if not set
+}
+
+// newFilter returns `$filter` operator.
+func newFilter(args ...any) (Operator, error) {
+	fields, err := namedArgs(
+		args,
+		[]string{"input", "as", "cond", "limit"},
+		func(any) error {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrFilterNotObject,
+				"$filter only supports an object as its argument",
+				"$filter (operator)",
+			)
+		},
+		func(field string) error {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrFilterUnknownField,
+				fmt.Sprintf("Unrecognized parameter to $filter: %s", field),
+				"$filter (operator)",
+			)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if fields["input"] == nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFilterMissingInput,
+			"Missing 'input' parameter to $filter",
+			"$filter (operator)",
+		)
+	}
+
+	if fields["cond"] == nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFilterMissingCond,
+			"Missing 'cond' parameter to $filter",
+			"$filter (operator)",
+		)
+	}
+
+	as, err := getVariableName("$filter", fields["as"])
+	if err != nil {
+		return nil, err
+	}
+
+	return &filter{
+		input: fields["input"],
+		as:    as,
+		cond:  fields["cond"],
+		limit: fields["limit"],
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// It returns elements of the input array for which the condition is true,
+// up to the limit if it is set.
+func (f *filter) Process(doc *types.Document, vars *aggregations.Variables) (any, error) {
+	input, err := evaluate(f.input, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := -1
+
+	if f.limit != nil {
+		v, err := evaluate(f.limit, doc, vars)
+		if err != nil {
+			return nil, err
+		}
+
+		if !isNullish(v) {
+			l, ok := integralToInt32(v)
+			if !ok {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrFilterLimitNotInt32,
+					fmt.Sprintf("$filter: limit must be represented as a 32-bit integral value: %s", types.FormatAnyValue(v)),
+					"$filter (operator)",
+				)
+			}
+
+			if l <= 0 {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrFilterLimitNotPositive,
+					fmt.Sprintf("$filter: limit must be greater than 0: %d", l),
+					"$filter (operator)",
+				)
+			}
+
+			limit = int(l)
+		}
+	}
+
+	if isNullish(input) {
+		return types.Null, nil
+	}
+
+	arr, ok := input.(*types.Array)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFilterInputNotArray,
+			fmt.Sprintf("input to $filter must be an array not %s", typeAlias(input)),
+			"$filter (operator)",
+		)
+	}
+
+	res := types.MakeArray(0)
+
+	for i := 0; i < arr.Len() && res.Len() != limit; i++ {
+		elem := must.NotFail(arr.Get(i))
+
+		v, err := evaluate(f.cond, doc, vars.With(map[string]any{f.as: elem}))
+		if err != nil {
+			return nil, err
+		}
+
+		if isTrue(v) {
+			res.Append(elem)
+		}
+	}
+
+	return res, nil
+}
+
+// check interfaces
+var (
+	_ Operator = (*filter)(nil)
+)
@@ -0,0 +1,69 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+// ifNull represents `$ifNull` operator.
+type ifNull struct {
+	exprs       []any
+	replacement any
+}
+
+// newIfNull returns `$ifNull` operator.
+func newIfNull(args ...any) (Operator, error) {
+	if len(args) < 2 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrIfNullArgs,
+			fmt.Sprintf("$ifNull needs at least two arguments, had: %d", len(args)),
+			"$ifNull (operator)",
+		)
+	}
+
+	return &ifNull{
+		exprs:       args[:len(args)-1],
+		replacement: args[len(args)-1],
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// It returns the first input expression value that is not null or missing,
+// or the value of the replacement expression otherwise.
+func (n *ifNull) Process(doc *types.Document, vars *aggregations.Variables) (any, error) {
+	for _, expr := range n.exprs {
+		v, err := evaluate(expr, doc, vars)
+		if err != nil {
+			return nil, err
+		}
+
+		if !isNullish(v) {
+			return v, nil
+		}
+	}
+
+	return evaluate(n.replacement, doc, vars)
+}
+
+// check interfaces
+var (
+	_ Operator = (*ifNull)(nil)
+)
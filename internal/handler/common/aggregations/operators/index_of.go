@@ -0,0 +1,174 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+// indexOf represents `$indexOfBytes` and `$indexOfCP` operators.
+type indexOf struct {
+	name       string
+	args       []any
+	codePoints bool
+}
+
+// newIndexOfBytes returns `$indexOfBytes` operator.
+func newIndexOfBytes(args ...any) (Operator, error) {
+	return newIndexOf("$indexOfBytes", false, args)
+}
+
+// newIndexOfCP returns `$indexOfCP` operator.
+func newIndexOfCP(args ...any) (Operator, error) {
+	return newIndexOf("$indexOfCP", true, args)
+}
+
+// newIndexOf returns `$indexOfBytes` or `$indexOfCP` operator.
+func newIndexOf(name string, codePoints bool, args []any) (Operator, error) {
+	if len(args) < 2 || len(args) > 4 {
+		return nil, newOperatorError(
+			ErrArgsInvalidLen,
+			name,
+			fmt.Sprintf(
+				"Expression %s takes at least 2 arguments, and at most 4, but %d were passed in.",
+				name, len(args),
+			),
+		)
+	}
+
+	return &indexOf{
+		name:       name,
+		args:       args,
+		codePoints: codePoints,
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// It returns the index of the first occurrence of the substring (in bytes or code points),
+// -1 if it was not found, or null if the string is null or missing.
+func (i *indexOf) Process(doc *types.Document, vars *aggregations.Variables) (any, error) {
+	values, err := evaluateArgs(i.args, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if isNullish(values[0]) {
+		return types.Null, nil
+	}
+
+	strCode, substrCode := handlererrors.ErrIndexOfBytesNonString, handlererrors.ErrIndexOfBytesSubstringNonString
+	if i.codePoints {
+		strCode, substrCode = handlererrors.ErrIndexOfCPNonString, handlererrors.ErrIndexOfCPSubstringNonString
+	}
+
+	str, ok := values[0].(string)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			strCode,
+			fmt.Sprintf("%s requires a string as the first argument, found: %s", i.name, typeAlias(values[0])),
+			i.name+" (operator)",
+		)
+	}
+
+	substr, ok := values[1].(string)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			substrCode,
+			fmt.Sprintf("%s requires a string as the second argument, found: %s", i.name, typeAlias(values[1])),
+			i.name+" (operator)",
+		)
+	}
+
+	length := len(str)
+	if i.codePoints {
+		length = utf8.RuneCountInString(str)
+	}
+
+	start, end := 0, length
+
+	if len(values) > 2 {
+		if start, err = getIndexOfIndex(i.name, values[2], "starting", "start"); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(values) > 3 {
+		if end, err = getIndexOfIndex(i.name, values[3], "ending", "ending"); err != nil {
+			return nil, err
+		}
+
+		end = min(end, length)
+	}
+
+	if start > end || start > length {
+		return int32(-1), nil
+	}
+
+	if !i.codePoints {
+		idx := strings.Index(str[start:end], substr)
+		if idx < 0 {
+			return int32(-1), nil
+		}
+
+		return int32(start + idx), nil
+	}
+
+	runes := []rune(str)
+
+	idx := strings.Index(string(runes[start:end]), substr)
+	if idx < 0 {
+		return int32(-1), nil
+	}
+
+	return int32(start + utf8.RuneCountInString(string(runes[start:end])[:idx])), nil
+}
+
+// getIndexOfIndex validates and returns the starting or ending index argument
+// of `$indexOfBytes`, `$indexOfCP` or `$indexOfArray` operator with the given name.
+func getIndexOfIndex(name string, v any, integralName, nonNegativeName string) (int, error) {
+	index, ok := integralToInt32(v)
+	if !ok {
+		return 0, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrIndexOfIndexNotIntegral,
+			fmt.Sprintf(
+				"%s requires an integral %s index, found a value of type: %s, with value: %s",
+				name, integralName, typeAlias(v), types.FormatAnyValue(v),
+			),
+			name+" (operator)",
+		)
+	}
+
+	if index < 0 {
+		return 0, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrIndexOfIndexNegative,
+			fmt.Sprintf("%s requires a nonnegative %s index, found: %d", name, nonNegativeName, index),
+			name+" (operator)",
+		)
+	}
+
+	return int(index), nil
+}
+
+// check interfaces
+var (
+	_ Operator = (*indexOf)(nil)
+)
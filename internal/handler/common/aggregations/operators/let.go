@@ -0,0 +1,170 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+)
+
+// let represents `$let` operator.
+type let struct {
+	vars *types.Document
+	in   any
+}
+
+// newLet returns `$let` operator.
+func newLet(args ...any) (Operator, error) {
+	fields, err := namedArgs(
+		args,
+		[]string{"vars", "in"},
+		func(any) error {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrLetNotObject,
+				"$let only supports an object as its argument",
+				"$let (operator)",
+			)
+		},
+		func(field string) error {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrLetUnknownField,
+				fmt.Sprintf("Unrecognized parameter to $let: %s", field),
+				"$let (operator)",
+			)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if fields["vars"] == nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrLetMissingVars,
+			"Missing 'vars' parameter to $let",
+			"$let (operator)",
+		)
+	}
+
+	if fields["in"] == nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrLetMissingIn,
+			"Missing 'in' parameter to $let",
+			"$let (operator)",
+		)
+	}
+
+	vars, ok := fields["vars"].(*types.Document)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrIndexesWrongType,
+			"invalid parameter: expected an object (vars)",
+			"$let (operator)",
+		)
+	}
+
+	for _, name := range vars.Keys() {
+		// unlike other system variables, CURRENT could be redefined
+		if name == "CURRENT" {
+			continue
+		}
+
+		if err = validateVariableName("$let", name); err != nil {
+			return nil, err
+		}
+	}
+
+	return &let{
+		vars: vars,
+		in:   fields["in"],
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// Variables are evaluated in the enclosing scope,
+// so they could not refer to each other.
+func (l *let) Process(doc *types.Document, vars *aggregations.Variables) (any, error) {
+	values := make(map[string]any, l.vars.Len())
+
+	iter := l.vars.Iterator()
+	defer iter.Close()
+
+	for {
+		k, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if values[k], err = evaluate(v, doc, vars); err != nil {
+			return nil, err
+		}
+	}
+
+	return evaluate(l.in, doc, vars.With(values))
+}
+
+// validateVariableName returns an error if the given name could not be used
+// for the variable defined by the operator.
+//
+// User variable names should start with a lowercase ASCII letter or a non-ASCII character,
+// and contain only ASCII letters, digits, underscores and non-ASCII characters.
+func validateVariableName(operator, name string) error {
+	if name == "" {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			"empty variable names are not allowed",
+			operator+" (operator)",
+		)
+	}
+
+	if c := name[0]; !(c >= 'a' && c <= 'z') && c < 0x80 {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			fmt.Sprintf("'%s' starts with an invalid character for a user variable name", name),
+			operator+" (operator)",
+		)
+	}
+
+	for i := 1; i < len(name); i++ {
+		c := name[i]
+
+		if (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == '_' || c >= 0x80 {
+			continue
+		}
+
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			fmt.Sprintf("'%s' contains an invalid character for a variable name: '%c'", name, c),
+			operator+" (operator)",
+		)
+	}
+
+	return nil
+}
+
+// check interfaces
+var (
+	_ Operator = (*let)(nil)
+)
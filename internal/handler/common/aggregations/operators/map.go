@@ -0,0 +1,147 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// mapOp represents `$map` operator.
+type mapOp struct {
+	input any
+	as    string
+	in    any
+}
+
+// newMap returns `$map` operator.
+func newMap(args ...any) (Operator, error) {
+	fields, err := namedArgs(
+		args,
+		[]string{"input", "as", "in"},
+		func(any) error {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrMapNotObject,
+				"$map only supports an object as its argument",
+				"$map (operator)",
+			)
+		},
+		func(field string) error {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrMapUnknownField,
+				fmt.Sprintf("Unrecognized parameter to $map: %s", field),
+				"$map (operator)",
+			)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if fields["input"] == nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrMapMissingInput,
+			"Missing 'input' parameter to $map",
+			"$map (operator)",
+		)
+	}
+
+	if fields["in"] == nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrMapMissingIn,
+			"Missing 'in' parameter to $map",
+			"$map (operator)",
+		)
+	}
+
+	as, err := getVariableName("$map", fields["as"])
+	if err != nil {
+		return nil, err
+	}
+
+	return &mapOp{
+		input: fields["input"],
+		as:    as,
+		in:    fields["in"],
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// Elements that are evaluated to missing values are set to null.
+func (m *mapOp) Process(doc *types.Document, vars *aggregations.Variables) (any, error) {
+	input, err := evaluate(m.input, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if isNullish(input) {
+		return types.Null, nil
+	}
+
+	arr, ok := input.(*types.Array)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrMapInputNotArray,
+			fmt.Sprintf("input to $map must be an array not %s", typeAlias(input)),
+			"$map (operator)",
+		)
+	}
+
+	res := types.MakeArray(arr.Len())
+
+	for i := 0; i < arr.Len(); i++ {
+		scope := vars.With(map[string]any{m.as: must.NotFail(arr.Get(i))})
+
+		v, err := evaluate(m.in, doc, scope)
+		if err != nil {
+			return nil, err
+		}
+
+		if v == nil {
+			v = types.Null
+		}
+
+		res.Append(v)
+	}
+
+	return res, nil
+}
+
+// getVariableName returns the name of the variable defined by the `as` argument of the operator,
+// or `this` if the argument is not set.
+func getVariableName(operator string, as any) (string, error) {
+	if as == nil {
+		return "this", nil
+	}
+
+	// non-string values are treated as empty names
+	name, _ := as.(string)
+
+	if err := validateVariableName(operator, name); err != nil {
+		return "", err
+	}
+
+	return name, nil
+}
+
+// check interfaces
+var (
+	_ Operator = (*mapOp)(nil)
+)
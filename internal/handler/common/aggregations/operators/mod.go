@@ -0,0 +1,86 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+// mod represents `$mod` operator.
+type mod struct {
+	dividend any
+	divisor  any
+}
+
+// newMod returns `$mod` operator.
+func newMod(args ...any) (Operator, error) {
+	if len(args) != 2 {
+		return nil, newArgsLenError("$mod", 2, len(args))
+	}
+
+	return &mod{
+		dividend: args[0],
+		divisor:  args[1],
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// The result has the widest type of both arguments and the sign of the dividend.
+// Null is returned if any argument is null or missing.
+func (m *mod) Process(doc *types.Document, vars *aggregations.Variables) (any, error) {
+	values, err := evaluateArgs([]any{m.dividend, m.divisor}, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	a, b := values[0], values[1]
+
+	switch {
+	case aggregations.IsNumber(a) && aggregations.IsNumber(b):
+		if isZero(b) {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrModByZero,
+				"can't $mod by zero",
+				"$mod (operator)",
+			)
+		}
+
+		return aggregations.ModNumbers(a, b), nil
+
+	case isNullish(a) || isNullish(b):
+		return types.Null, nil
+
+	default:
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrModNonNumeric,
+			fmt.Sprintf(
+				"$mod only supports numeric types, not %s and %s",
+				handlerparams.AliasFromType(a), handlerparams.AliasFromType(b),
+			),
+			"$mod (operator)",
+		)
+	}
+}
+
+// check interfaces
+var (
+	_ Operator = (*mod)(nil)
+)
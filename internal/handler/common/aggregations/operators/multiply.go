@@ -0,0 +1,67 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+// multiply represents `$multiply` operator.
+type multiply struct {
+	args []any
+}
+
+// newMultiply returns `$multiply` operator.
+func newMultiply(args ...any) (Operator, error) {
+	return &multiply{
+		args: args,
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// It returns the product of numbers. Null is returned if any argument is null or missing.
+func (m *multiply) Process(doc *types.Document, vars *aggregations.Variables) (any, error) {
+	values, err := evaluateArgs(m.args, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range values {
+		if isNullish(v) {
+			return types.Null, nil
+		}
+
+		if !aggregations.IsNumber(v) {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrTypeMismatch,
+				fmt.Sprintf("$multiply only supports numeric types, not %s", handlerparams.AliasFromType(v)),
+				"$multiply (operator)",
+			)
+		}
+	}
+
+	return aggregations.MultiplyNumbers(values...), nil
+}
+
+// check interfaces
+var (
+	_ Operator = (*multiply)(nil)
+)
@@ -26,6 +26,8 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/iterator"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
@@ -45,7 +47,7 @@ type newOperatorFunc func(args ...any) (Operator, error)
 // Operator is a common interface for standard aggregation operators.
 type Operator interface {
 	// Process document and returns the result of applying operator.
-	Process(in *types.Document) (any, error)
+	Process(in *types.Document, vars *aggregations.Variables) (any, error)
 }
 
 // IsOperator returns true if provided document should be
@@ -139,23 +141,115 @@ func NewOperator(doc *types.Document) (Operator, error) {
 // Operators maps all standard aggregation operators.
 var Operators = map[string]newOperatorFunc{
 	// sorted alphabetically
-	"$sum":  newSum,
-	"$type": newType,
+	"$abs":            newUnaryFunc("$abs", abs),
+	"$add":            newAdd,
+	"$and":            newAnd,
+	"$arrayElemAt":    newArrayElemAt,
+	"$arrayToObject":  newArrayToObject,
+	"$ceil":           newUnaryFunc("$ceil", ceil),
+	"$cmp":            newCmp,
+	"$concat":         newConcat,
+	"$concatArrays":   newConcatArrays,
+	"$cond":           newCond,
+	"$convert":        newConvert,
+	"$dateAdd":        newDateAdd,
+	"$dateDiff":       newDateDiff,
+	"$dateFromParts":  newDateFromParts,
+	"$dateFromString": newDateFromString,
+	"$dateSubtract":   newDateSubtract,
+	"$dateToParts":    newDateToParts,
+	"$dateToString":   newDateToString,
+	"$dateTrunc":      newDateTrunc,
+	"$dayOfMonth":     newDatePartFunc("$dayOfMonth", dayOfMonth),
+	"$dayOfWeek":      newDatePartFunc("$dayOfWeek", dayOfWeek),
+	"$dayOfYear":      newDatePartFunc("$dayOfYear", dayOfYear),
+	"$divide":         newDivide,
+	"$eq":             newComparisonFunc("$eq", types.Equal),
+	"$exp":            newUnaryFunc("$exp", exp),
+	"$filter":         newFilter,
+	"$floor":          newUnaryFunc("$floor", floor),
+	"$gt":             newComparisonFunc("$gt", types.Greater),
+	"$gte":            newComparisonFunc("$gte", types.Greater, types.Equal),
+	"$hour":           newDatePartFunc("$hour", hour),
+	"$ifNull":         newIfNull,
+	"$in":             newIn,
+	"$indexOfArray":   newIndexOfArray,
+	"$indexOfBytes":   newIndexOfBytes,
+	"$indexOfCP":      newIndexOfCP,
+	"$isArray":        newIsArray,
+	"$isNumber":       newIsNumber,
+	"$isoDayOfWeek":   newDatePartFunc("$isoDayOfWeek", isoDayOfWeek),
+	"$isoWeek":        newDatePartFunc("$isoWeek", isoWeek),
+	"$isoWeekYear":    newDatePartFunc("$isoWeekYear", isoWeekYear),
+	"$let":            newLet,
+	"$ln":             newUnaryFunc("$ln", ln),
+	"$log":            newLog,
+	"$log10":          newUnaryFunc("$log10", log10),
+	"$lt":             newComparisonFunc("$lt", types.Less),
+	"$lte":            newComparisonFunc("$lte", types.Less, types.Equal),
+	"$ltrim":          newTrimFunc("$ltrim", trimLeft),
+	"$map":            newMap,
+	"$millisecond":    newDatePartFunc("$millisecond", millisecond),
+	"$minute":         newDatePartFunc("$minute", minute),
+	"$mod":            newMod,
+	"$month":          newDatePartFunc("$month", month),
+	"$multiply":       newMultiply,
+	"$ne":             newComparisonFunc("$ne", types.Less, types.Greater),
+	"$not":            newNot,
+	"$objectToArray":  newObjectToArray,
+	"$or":             newOr,
+	"$pow":            newPow,
+	"$range":          newRange,
+	"$reduce":         newReduce,
+	"$regexFind":      newRegexFunc("$regexFind", regexFind),
+	"$regexFindAll":   newRegexFunc("$regexFindAll", regexFindAll),
+	"$regexMatch":     newRegexFunc("$regexMatch", regexMatch),
+	"$replaceAll":     newReplaceAll,
+	"$replaceOne":     newReplaceOne,
+	"$reverseArray":   newReverseArray,
+	"$round":          newRound,
+	"$rtrim":          newTrimFunc("$rtrim", trimRight),
+	"$second":         newDatePartFunc("$second", second),
+	"$size":           newSize,
+	"$slice":          newSlice,
+	"$sortArray":      newSortArray,
+	"$split":          newSplit,
+	"$sqrt":           newUnaryFunc("$sqrt", sqrt),
+	"$strcasecmp":     newStrcasecmp,
+	"$strLenBytes":    newStrLenBytes,
+	"$strLenCP":       newStrLenCP,
+	"$substr":         newSubstrBytes,
+	"$substrBytes":    newSubstrBytes,
+	"$substrCP":       newSubstrCP,
+	"$subtract":       newSubtract,
+	"$sum":            newSum,
+	"$switch":         newSwitch,
+	"$toBool":         newConvertFunc("$toBool", handlerparams.TypeCodeBool),
+	"$toDate":         newConvertFunc("$toDate", handlerparams.TypeCodeDate),
+	"$toDecimal":      newConvertFunc("$toDecimal", handlerparams.TypeCodeDecimal),
+	"$toDouble":       newConvertFunc("$toDouble", handlerparams.TypeCodeDouble),
+	"$toInt":          newConvertFunc("$toInt", handlerparams.TypeCodeInt),
+	"$toLong":         newConvertFunc("$toLong", handlerparams.TypeCodeLong),
+	"$toLower":        newToLower,
+	"$toObjectId":     newConvertFunc("$toObjectId", handlerparams.TypeCodeObjectID),
+	"$toString":       newConvertFunc("$toString", handlerparams.TypeCodeString),
+	"$toUpper":        newToUpper,
+	"$trim":           newTrimFunc("$trim", trimBoth),
+	"$trunc":          newTrunc,
+	"$type":           newType,
+	"$week":           newDatePartFunc("$week", week),
+	"$year":           newDatePartFunc("$year", year),
+	"$zip":            newZip,
 	// please keep sorted alphabetically
 }
 
 // unsupportedOperators maps all unsupported yet operators.
 var unsupportedOperators = map[string]struct{}{
 	// sorted alphabetically
-	"$abs":              {},
 	"$acos":             {},
 	"$acosh":            {},
-	"$add":              {},
 	"$allElementsTrue":  {},
-	"$and":              {},
 	"$anyElementTrue":   {},
-	"$arrayElemAt":      {},
-	"$arrayToObject":    {},
 	"$asin":             {},
 	"$asinh":            {},
 	"$atan":             {},
@@ -164,93 +258,29 @@ var unsupportedOperators = map[string]struct{}{
 	"$avg":              {},
 	"$binarySize":       {},
 	"$bsonSize":         {},
-	"$ceil":             {},
-	"$cmp":              {},
-	"$concat":           {},
-	"$concatArrays":     {},
-	"$cond":             {},
-	"$convert":          {},
 	"$cos":              {},
 	"$cosh":             {},
 	"$covariancePop":    {},
 	"$covarianceSamp":   {},
-	"$dateAdd":          {},
-	"$dateDiff":         {},
-	"$dateFromParts":    {},
-	"$dateSubtract":     {},
-	"$dateTrunc":        {},
-	"$dateToParts":      {},
-	"$dateFromString":   {},
-	"$dateToString":     {},
-	"$dayOfMonth":       {},
-	"$dayOfWeek":        {},
-	"$dayOfYear":        {},
 	"$degreesToRadians": {},
 	"$denseRank":        {},
 	"$derivative":       {},
-	"$divide":           {},
 	"$documentNumber":   {},
-	"$eq":               {},
-	"$exp":              {},
 	"$expMovingAvg":     {},
-	"$filter":           {},
-	"$floor":            {},
 	"$function":         {},
 	"$getField":         {},
-	"$gt":               {},
-	"$gte":              {},
-	"$hour":             {},
-	"$ifNull":           {},
-	"$in":               {},
-	"$indexOfArray":     {},
-	"$indexOfBytes":     {},
-	"$indexOfCP":        {},
 	"$integral":         {},
-	"$isArray":          {},
-	"$isNumber":         {},
-	"$isoDayOfWeek":     {},
-	"$isoWeek":          {},
-	"$isoWeekYear":      {},
-	"$let":              {},
 	"$linearFill":       {},
 	"$literal":          {},
-	"$ln":               {},
 	"$locf":             {},
-	"$log":              {},
-	"$log10":            {},
-	"$lt":               {},
-	"$lte":              {},
-	"$ltrim":            {},
-	"$map":              {},
 	"$max":              {},
 	"$meta":             {},
 	"$min":              {},
 	"$minN":             {},
-	"$millisecond":      {},
-	"$minute":           {},
-	"$mod":              {},
-	"$month":            {},
-	"$multiply":         {},
-	"$ne":               {},
-	"$not":              {},
-	"$objectToArray":    {},
-	"$or":               {},
-	"$pow":              {},
 	"$radiansToDegrees": {},
 	"$rand":             {},
-	"$range":            {},
 	"$rank":             {},
-	"$reduce":           {},
-	"$regexFind":        {},
-	"$regexFindAll":     {},
-	"$regexMatch":       {},
-	"$replaceOne":       {},
-	"$replaceAll":       {},
-	"$reverseArray":     {},
-	"$round":            {},
-	"$rtrim":            {},
 	"$sampleRate":       {},
-	"$second":           {},
 	"$setDifference":    {},
 	"$setEquals":        {},
 	"$setField":         {},
@@ -258,42 +288,14 @@ var unsupportedOperators = map[string]struct{}{
 	"$setIsSubset":      {},
 	"$setUnion":         {},
 	"$shift":            {},
-	"$size":             {},
 	"$sin":              {},
 	"$sinh":             {},
-	"$slice":            {},
-	"$sortArray":        {},
-	"$split":            {},
-	"$sqrt":             {},
 	"$stdDevPop":        {},
 	"$stdDevSamp":       {},
-	"$strcasecmp":       {},
-	"$strLenBytes":      {},
-	"$strLenCP":         {},
-	"$substr":           {},
-	"$substrBytes":      {},
-	"$substrCP":         {},
-	"$subtract":         {},
-	"$switch":           {},
 	"$tan":              {},
 	"$tanh":             {},
-	"$toBool":           {},
-	"$toDate":           {},
-	"$toDecimal":        {},
-	"$toDouble":         {},
-	"$toInt":            {},
-	"$toLong":           {},
-	"$toObjectId":       {},
-	"$toString":         {},
-	"$toLower":          {},
-	"$toUpper":          {},
-	"$trim":             {},
-	"$trunc":            {},
 	"$tsIncrement":      {},
 	"$tsSecond":         {},
 	"$unsetField":       {},
-	"$week":             {},
-	"$year":             {},
-	"$zip":              {},
 	// please keep sorted alphabetically
 }
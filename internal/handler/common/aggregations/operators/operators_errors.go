@@ -14,6 +14,12 @@
 
 package operators
 
+import (
+	"errors"
+
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+)
+
 // operatorErrorCode represents the type of error.
 type operatorErrorCode uint
 
@@ -66,3 +72,13 @@ func (opErr OperatorError) Code() operatorErrorCode {
 func (opErr OperatorError) Name() string {
 	return opErr.name
 }
+
+// IsEvaluationError returns true if the error was returned by operator's Process
+// because of the values of the processed document, and not because of the invalid operator.
+//
+// Such errors should be ignored when the operator is validated on an empty or fake document;
+// they are returned to the client when actual documents are processed.
+func IsEvaluationError(err error) bool {
+	var cmdErr *handlererrors.CommandError
+	return errors.As(err, &cmdErr)
+}
@@ -0,0 +1,168 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+// pow represents `$pow` operator.
+type pow struct {
+	base     any
+	exponent any
+}
+
+// newPow returns `$pow` operator.
+func newPow(args ...any) (Operator, error) {
+	if len(args) != 2 {
+		return nil, newArgsLenError("$pow", 2, len(args))
+	}
+
+	return &pow{
+		base:     args[0],
+		exponent: args[1],
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// Integer base raised to non-negative integer exponent is an integer if it fits,
+// otherwise the result is double, or decimal if any argument is decimal.
+// Null is returned if any argument is null or missing.
+func (p *pow) Process(doc *types.Document, vars *aggregations.Variables) (any, error) {
+	values, err := evaluateArgs([]any{p.base, p.exponent}, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	base, exponent := values[0], values[1]
+
+	if isNullish(base) || isNullish(exponent) {
+		return types.Null, nil
+	}
+
+	if !aggregations.IsNumber(base) {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrPowBaseNonNumeric,
+			fmt.Sprintf("$pow's base must be numeric, not %s", handlerparams.AliasFromType(base)),
+			"$pow (operator)",
+		)
+	}
+
+	if !aggregations.IsNumber(exponent) {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrPowExponentNonNumeric,
+			fmt.Sprintf("$pow's exponent must be numeric, not %s", handlerparams.AliasFromType(exponent)),
+			"$pow (operator)",
+		)
+	}
+
+	b, e := aggregations.NumberToFloat64(base), aggregations.NumberToFloat64(exponent)
+
+	if b == 0 && e < 0 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrPowZeroNegativeExponent,
+			"$pow cannot take a base of 0 and a negative exponent",
+			"$pow (operator)",
+		)
+	}
+
+	switch base.(type) {
+	case types.Decimal128:
+		return types.NewDecimal128FromFloat64(math.Pow(b, e)), nil
+	case float64:
+		return math.Pow(b, e), nil
+	}
+
+	switch exponent.(type) {
+	case types.Decimal128:
+		return types.NewDecimal128FromFloat64(math.Pow(b, e)), nil
+	case float64:
+		return math.Pow(b, e), nil
+	}
+
+	return powIntegers(base, exponent), nil
+}
+
+// powIntegers returns int32 or int64 base raised to int32 or int64 exponent.
+//
+// The result is int32 if both arguments are int32 and the result fits,
+// int64 if the result fits, and double otherwise.
+func powIntegers(base, exponent any) any {
+	_, longBase := base.(int64)
+	_, longExponent := exponent.(int64)
+
+	b, e := integerToInt64(base), integerToInt64(exponent)
+
+	var res int64
+
+	switch {
+	case b == 0:
+		res = 0
+		if e == 0 {
+			res = 1
+		}
+
+	case b == 1:
+		res = 1
+
+	case b == -1:
+		res = 1
+		if e%2 != 0 {
+			res = -1
+		}
+
+	case e < 0, e >= 64:
+		return math.Pow(float64(b), float64(e))
+
+	default:
+		r := new(big.Int).Exp(big.NewInt(b), big.NewInt(e), nil)
+		if !r.IsInt64() {
+			return math.Pow(float64(b), float64(e))
+		}
+
+		res = r.Int64()
+	}
+
+	if !longBase && !longExponent && res >= math.MinInt32 && res <= math.MaxInt32 {
+		return int32(res)
+	}
+
+	return res
+}
+
+// integerToInt64 converts int32 or int64 value to int64.
+func integerToInt64(v any) int64 {
+	switch v := v.(type) {
+	case int32:
+		return int64(v)
+	case int64:
+		return v
+	default:
+		panic(fmt.Sprintf("unexpected type %T", v))
+	}
+}
+
+// check interfaces
+var (
+	_ Operator = (*pow)(nil)
+)
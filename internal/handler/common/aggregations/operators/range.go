@@ -0,0 +1,161 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+// rangeMaxBytes is the maximum estimated memory size of `$range` result,
+// like MongoDB's internalQueryMaxRangeBytes.
+const rangeMaxBytes = 100 * 1024 * 1024
+
+// rangeElementBytes is the estimated memory size of a single `$range` result element.
+const rangeElementBytes = 16
+
+// rangeOp represents `$range` operator.
+type rangeOp struct {
+	start any
+	end   any
+	step  any // nil if not set
+}
+
+// newRange returns `$range` operator.
+func newRange(args ...any) (Operator, error) {
+	if len(args) < 2 || len(args) > 3 {
+		return nil, newOperatorError(
+			ErrArgsInvalidLen,
+			"$range",
+			fmt.Sprintf("Expression $range takes at least 2 arguments, and at most 3, but %d were passed in.", len(args)),
+		)
+	}
+
+	r := &rangeOp{
+		start: args[0],
+		end:   args[1],
+	}
+
+	if len(args) == 3 {
+		r.step = args[2]
+	}
+
+	return r, nil
+}
+
+// Process implements Operator interface.
+func (r *rangeOp) Process(doc *types.Document, vars *aggregations.Variables) (any, error) {
+	values, err := evaluateArgs([]any{r.start, r.end}, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	start, err := getRangeArg(values[0], "starting", handlererrors.ErrRangeStartNotNumber, handlererrors.ErrRangeStartNotInt32)
+	if err != nil {
+		return nil, err
+	}
+
+	end, err := getRangeArg(values[1], "ending", handlererrors.ErrRangeEndNotNumber, handlererrors.ErrRangeEndNotInt32)
+	if err != nil {
+		return nil, err
+	}
+
+	step := int64(1)
+
+	if r.step != nil {
+		v, err := evaluate(r.step, doc, vars)
+		if err != nil {
+			return nil, err
+		}
+
+		if step, err = getRangeArg(v, "step", handlererrors.ErrRangeStepNotNumber, handlererrors.ErrRangeStepNotInt32); err != nil {
+			return nil, err
+		}
+
+		if step == 0 {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrRangeStepZero,
+				"$range requires a non-zero step value",
+				"$range (operator)",
+			)
+		}
+	}
+
+	var n int64
+	if (step > 0 && start < end) || (step < 0 && start > end) {
+		n = (end - start + step - sign(step)) / step
+	}
+
+	if bytes := n * rangeElementBytes; bytes > rangeMaxBytes {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrExceededMemoryLimit,
+			fmt.Sprintf(
+				"$range would use too much memory (%d bytes) and cannot spill to disk. Memory limit: %d bytes",
+				bytes, rangeMaxBytes,
+			),
+			"$range (operator)",
+		)
+	}
+
+	res := types.MakeArray(int(n))
+
+	for i := int64(0); i < n; i++ {
+		res.Append(int32(start + i*step))
+	}
+
+	return res, nil
+}
+
+// getRangeArg validates and returns the starting, ending or step value of `$range` operator.
+func getRangeArg(v any, name string, notNumberCode, notInt32Code handlererrors.ErrorCode) (int64, error) {
+	if !isNumber(v) {
+		return 0, handlererrors.NewCommandErrorMsgWithArgument(
+			notNumberCode,
+			fmt.Sprintf("$range requires a numeric %s value, found value of type: %s", name, typeAlias(v)),
+			"$range (operator)",
+		)
+	}
+
+	i, ok := integralToInt32(v)
+	if !ok {
+		return 0, handlererrors.NewCommandErrorMsgWithArgument(
+			notInt32Code,
+			fmt.Sprintf(
+				"$range requires a %s value that can be represented as a 32-bit integer, found value: %s",
+				name, types.FormatAnyValue(v),
+			),
+			"$range (operator)",
+		)
+	}
+
+	return int64(i), nil
+}
+
+// sign returns -1 for negative numbers and 1 otherwise.
+func sign(v int64) int64 {
+	if v < 0 {
+		return -1
+	}
+
+	return 1
+}
+
+// check interfaces
+var (
+	_ Operator = (*rangeOp)(nil)
+)
@@ -0,0 +1,126 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// reduce represents `$reduce` operator.
+type reduce struct {
+	input        any
+	initialValue any
+	in           any
+}
+
+// newReduce returns `$reduce` operator.
+func newReduce(args ...any) (Operator, error) {
+	fields, err := namedArgs(
+		args,
+		[]string{"input", "initialValue", "in"},
+		func(v any) error {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrReduceNotObject,
+				fmt.Sprintf("$reduce only supports an object as its argument, found %s", typeAlias(v)),
+				"$reduce (operator)",
+			)
+		},
+		func(field string) error {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrReduceUnknownField,
+				fmt.Sprintf("$reduce found an unknown argument: %s", field),
+				"$reduce (operator)",
+			)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range []struct {
+		name string
+		code handlererrors.ErrorCode
+	}{
+		{"input", handlererrors.ErrReduceMissingInput},
+		{"initialValue", handlererrors.ErrReduceMissingInitialValue},
+		{"in", handlererrors.ErrReduceMissingIn},
+	} {
+		if fields[p.name] == nil {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				p.code,
+				fmt.Sprintf("$reduce requires '%s' to be specified", p.name),
+				"$reduce (operator)",
+			)
+		}
+	}
+
+	return &reduce{
+		input:        fields["input"],
+		initialValue: fields["initialValue"],
+		in:           fields["in"],
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// The `in` expression is evaluated for each element of the input array
+// with `$$this` set to the element and `$$value` set to the accumulated value.
+func (r *reduce) Process(doc *types.Document, vars *aggregations.Variables) (any, error) {
+	input, err := evaluate(r.input, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if isNullish(input) {
+		return types.Null, nil
+	}
+
+	arr, ok := input.(*types.Array)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrReduceInputNotArray,
+			fmt.Sprintf("$reduce requires that 'input' be an array, found: %s", types.FormatAnyValue(input)),
+			"$reduce (operator)",
+		)
+	}
+
+	value, err := evaluate(r.initialValue, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < arr.Len(); i++ {
+		scope := vars.With(map[string]any{
+			"this":  must.NotFail(arr.Get(i)),
+			"value": value,
+		})
+
+		if value, err = evaluate(r.in, doc, scope); err != nil {
+			return nil, err
+		}
+	}
+
+	return value, nil
+}
+
+// check interfaces
+var (
+	_ Operator = (*reduce)(nil)
+)
@@ -0,0 +1,302 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"unicode/utf8"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// regexMode represents the kind of the regex operator.
+type regexMode int
+
+const (
+	regexMatch regexMode = iota
+	regexFind
+	regexFindAll
+)
+
+// regexOp represents `$regexMatch`, `$regexFind` and `$regexFindAll` operators.
+type regexOp struct {
+	name    string
+	input   any
+	regex   any
+	options any // nil if not set
+	mode    regexMode
+}
+
+// newRegexFunc returns a function that creates a regex operator with the given name and mode.
+func newRegexFunc(name string, mode regexMode) newOperatorFunc {
+	return func(args ...any) (Operator, error) {
+		var spec *types.Document
+
+		if len(args) == 1 {
+			spec, _ = args[0].(*types.Document)
+		}
+
+		if spec == nil {
+			var v any = types.MakeArray(len(args))
+			if len(args) == 1 {
+				v = args[0]
+			}
+
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrRegexNotObject,
+				fmt.Sprintf("%s expects an object of named arguments but found: %s", name, handlerparams.AliasFromType(v)),
+				name+" (operator)",
+			)
+		}
+
+		r := &regexOp{
+			name: name,
+			mode: mode,
+		}
+
+		iter := spec.Iterator()
+		defer iter.Close()
+
+		for {
+			k, v, err := iter.Next()
+			if errors.Is(err, iterator.ErrIteratorDone) {
+				break
+			}
+
+			if err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+
+			switch k {
+			case "input":
+				r.input = v
+			case "regex":
+				r.regex = v
+			case "options":
+				r.options = v
+			default:
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrRegexUnknownArgument,
+					fmt.Sprintf("%s found an unknown argument: %s", name, k),
+					name+" (operator)",
+				)
+			}
+		}
+
+		if r.input == nil {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrRegexMissingInput,
+				fmt.Sprintf("%s requires 'input' parameter", name),
+				name+" (operator)",
+			)
+		}
+
+		if r.regex == nil {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrRegexMissingRegex,
+				fmt.Sprintf("%s requires 'regex' parameter", name),
+				name+" (operator)",
+			)
+		}
+
+		return r, nil
+	}
+}
+
+// Process implements Operator interface.
+//
+// If input or regex is null or missing, `$regexMatch` returns false,
+// `$regexFind` returns null, and `$regexFindAll` returns an empty array.
+func (r *regexOp) Process(doc *types.Document, vars *aggregations.Variables) (any, error) {
+	input, err := evaluate(r.input, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	re, err := r.compile(doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isNullish(input) {
+		if _, ok := input.(string); !ok {
+			return nil, r.newError(
+				handlererrors.ErrRegexInputNonString,
+				fmt.Sprintf("%s needs 'input' to be of type string", r.name),
+			)
+		}
+	}
+
+	if isNullish(input) || re == nil {
+		switch r.mode {
+		case regexMatch:
+			return false, nil
+		case regexFind:
+			return types.Null, nil
+		default:
+			return types.MakeArray(0), nil
+		}
+	}
+
+	s := input.(string)
+
+	switch r.mode {
+	case regexMatch:
+		return re.MatchString(s), nil
+
+	case regexFind:
+		loc := re.FindStringSubmatchIndex(s)
+		if loc == nil {
+			return types.Null, nil
+		}
+
+		return regexMatchDocument(s, loc), nil
+
+	default:
+		locs := re.FindAllStringSubmatchIndex(s, -1)
+
+		res := types.MakeArray(len(locs))
+		for _, loc := range locs {
+			res.Append(regexMatchDocument(s, loc))
+		}
+
+		return res, nil
+	}
+}
+
+// compile evaluates regex and options arguments and returns compiled regular expression.
+// It returns nil if regex is null or missing.
+func (r *regexOp) compile(doc *types.Document, vars *aggregations.Variables) (*regexp.Regexp, error) {
+	regex, err := evaluate(r.regex, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	var options string
+
+	if r.options != nil {
+		var v any
+
+		if v, err = evaluate(r.options, doc, vars); err != nil {
+			return nil, err
+		}
+
+		if !isNullish(v) {
+			var ok bool
+			if options, ok = v.(string); !ok {
+				return nil, r.newError(
+					handlererrors.ErrRegexOptionsNonString,
+					fmt.Sprintf("%s needs 'options' to be of type string", r.name),
+				)
+			}
+		}
+	}
+
+	var pattern types.Regex
+
+	switch regex := regex.(type) {
+	case nil, types.NullType:
+		return nil, nil
+
+	case string:
+		pattern.Pattern = regex
+
+	case types.Regex:
+		if regex.Options != "" && options != "" {
+			return nil, r.newError(
+				handlererrors.ErrRegexOptionsConflict,
+				fmt.Sprintf("%s found regex option(s) specified in both 'regex' and 'option' fields", r.name),
+			)
+		}
+
+		pattern = regex
+
+	default:
+		return nil, r.newError(
+			handlererrors.ErrRegexInvalidRegexType,
+			fmt.Sprintf("%s needs 'regex' to be of type string or regex", r.name),
+		)
+	}
+
+	if options != "" {
+		pattern.Options = options
+	}
+
+	for _, o := range pattern.Options {
+		switch o {
+		case 'i', 'm', 's', 'x':
+		default:
+			return nil, r.newError(
+				handlererrors.ErrBadRegexOption,
+				fmt.Sprintf("%s invalid flag in regex options: %c", r.name, o),
+			)
+		}
+	}
+
+	re, err := pattern.Compile()
+	if err != nil && err == types.ErrOptionNotImplemented {
+		return nil, r.newError(handlererrors.ErrNotImplemented, `option 'x' not implemented`)
+	}
+
+	if err != nil {
+		return nil, r.newError(
+			handlererrors.ErrRegexInvalid,
+			fmt.Sprintf("Invalid Regex in %s: %s", r.name, err),
+		)
+	}
+
+	return re, nil
+}
+
+// newError returns a new command error for the operator.
+func (r *regexOp) newError(code handlererrors.ErrorCode, msg string) error {
+	return handlererrors.NewCommandErrorMsgWithArgument(code, msg, r.name+" (operator)")
+}
+
+// regexMatchDocument returns a document describing a single match
+// given by submatch indexes loc in the string s.
+//
+// The index is in code points; captures of unmatched groups are null.
+func regexMatchDocument(s string, loc []int) *types.Document {
+	captures := types.MakeArray(len(loc)/2 - 1)
+
+	for i := 2; i < len(loc); i += 2 {
+		if loc[i] < 0 {
+			captures.Append(types.Null)
+			continue
+		}
+
+		captures.Append(s[loc[i]:loc[i+1]])
+	}
+
+	return must.NotFail(types.NewDocument(
+		"match", s[loc[0]:loc[1]],
+		"idx", int32(utf8.RuneCountInString(s[:loc[0]])),
+		"captures", captures,
+	))
+}
+
+// check interfaces
+var (
+	_ Operator = (*regexOp)(nil)
+)
@@ -0,0 +1,180 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+)
+
+// replace represents `$replaceOne` and `$replaceAll` operators.
+type replace struct {
+	name        string
+	input       any
+	find        any
+	replacement any
+	all         bool
+}
+
+// newReplaceOne returns `$replaceOne` operator.
+func newReplaceOne(args ...any) (Operator, error) {
+	return newReplace("$replaceOne", false, args)
+}
+
+// newReplaceAll returns `$replaceAll` operator.
+func newReplaceAll(args ...any) (Operator, error) {
+	return newReplace("$replaceAll", true, args)
+}
+
+// newReplace returns `$replaceOne` or `$replaceAll` operator.
+func newReplace(name string, all bool, args []any) (Operator, error) {
+	var spec *types.Document
+
+	if len(args) == 1 {
+		spec, _ = args[0].(*types.Document)
+	}
+
+	if spec == nil {
+		var v any = types.MakeArray(len(args))
+		if len(args) == 1 {
+			v = args[0]
+		}
+
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrReplaceNotObject,
+			fmt.Sprintf("%s requires an object as an argument, found: %s", name, handlerparams.AliasFromType(v)),
+			name+" (operator)",
+		)
+	}
+
+	r := &replace{
+		name: name,
+		all:  all,
+	}
+
+	iter := spec.Iterator()
+	defer iter.Close()
+
+	for {
+		k, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		switch k {
+		case "input":
+			r.input = v
+		case "find":
+			r.find = v
+		case "replacement":
+			r.replacement = v
+		default:
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrReplaceUnknownArgument,
+				fmt.Sprintf("%s found an unknown argument: %s", name, k),
+				name+" (operator)",
+			)
+		}
+	}
+
+	for _, f := range []struct {
+		v     any
+		field string
+		code  handlererrors.ErrorCode
+	}{
+		{r.input, "input", handlererrors.ErrReplaceMissingInput},
+		{r.find, "find", handlererrors.ErrReplaceMissingFind},
+		{r.replacement, "replacement", handlererrors.ErrReplaceMissingReplacement},
+	} {
+		if f.v == nil {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				f.code,
+				fmt.Sprintf("%s requires '%s' to be specified", name, f.field),
+				name+" (operator)",
+			)
+		}
+	}
+
+	return r, nil
+}
+
+// Process implements Operator interface.
+//
+// It returns null if any argument is null or missing.
+func (r *replace) Process(doc *types.Document, vars *aggregations.Variables) (any, error) {
+	values, err := evaluateArgs([]any{r.input, r.find, r.replacement}, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	strs := make([]string, len(values))
+
+	for i, f := range []struct {
+		field string
+		code  handlererrors.ErrorCode
+	}{
+		{"input", handlererrors.ErrReplaceInputNonString},
+		{"find", handlererrors.ErrReplaceFindNonString},
+		{"replacement", handlererrors.ErrReplaceReplacementNonString},
+	} {
+		if isNullish(values[i]) {
+			continue
+		}
+
+		s, ok := values[i].(string)
+		if !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				f.code,
+				fmt.Sprintf(
+					"%s requires that '%s' be a string, found: %s",
+					r.name, f.field, types.FormatAnyValue(values[i]),
+				),
+				r.name+" (operator)",
+			)
+		}
+
+		strs[i] = s
+	}
+
+	for _, v := range values {
+		if isNullish(v) {
+			return types.Null, nil
+		}
+	}
+
+	n := 1
+	if r.all {
+		n = -1
+	}
+
+	return strings.Replace(strs[0], strs[1], strs[2], n), nil
+}
+
+// check interfaces
+var (
+	_ Operator = (*replace)(nil)
+)
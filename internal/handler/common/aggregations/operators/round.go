@@ -0,0 +1,207 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+// round represents `$round` and `$trunc` operators.
+type round struct {
+	name   string
+	number any
+	place  any // nil if not set
+	trunc  bool
+}
+
+// newRound returns `$round` operator.
+func newRound(args ...any) (Operator, error) {
+	return newRoundOrTrunc("$round", false, args)
+}
+
+// newTrunc returns `$trunc` operator.
+func newTrunc(args ...any) (Operator, error) {
+	return newRoundOrTrunc("$trunc", true, args)
+}
+
+// newRoundOrTrunc returns `$round` or `$trunc` operator.
+func newRoundOrTrunc(name string, trunc bool, args []any) (Operator, error) {
+	if len(args) < 1 || len(args) > 2 {
+		return nil, newOperatorError(
+			ErrArgsInvalidLen,
+			name,
+			fmt.Sprintf(
+				"Expression %s takes at least 1 arguments, and at most 2, but %d were passed in.",
+				name, len(args),
+			),
+		)
+	}
+
+	r := &round{
+		name:   name,
+		number: args[0],
+		trunc:  trunc,
+	}
+
+	if len(args) == 2 {
+		r.place = args[1]
+	}
+
+	return r, nil
+}
+
+// Process implements Operator interface.
+//
+// The result has the same type as the number; `$round` rounds half to even.
+// Null is returned if any argument is null or missing.
+func (r *round) Process(doc *types.Document, vars *aggregations.Variables) (any, error) {
+	number, err := evaluate(r.number, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if isNullish(number) {
+		return types.Null, nil
+	}
+
+	if !aggregations.IsNumber(number) {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrRoundNonNumeric,
+			fmt.Sprintf("%s only supports numeric types, not %s", r.name, handlerparams.AliasFromType(number)),
+			r.name+" (operator)",
+		)
+	}
+
+	var place int64
+
+	if r.place != nil {
+		v, err := evaluate(r.place, doc, vars)
+		if err != nil {
+			return nil, err
+		}
+
+		if isNullish(v) {
+			return types.Null, nil
+		}
+
+		if place, err = r.getPlace(v); err != nil {
+			return nil, err
+		}
+	}
+
+	return r.roundNumber(number, int(place)), nil
+}
+
+// getPlace returns the number of decimal places from the evaluated place argument.
+func (r *round) getPlace(v any) (int64, error) {
+	if !aggregations.IsNumber(v) {
+		return 0, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrConvertToLong,
+			fmt.Sprintf("can't convert from BSON type %s to long", handlerparams.AliasFromType(v)),
+			r.name+" (operator)",
+		)
+	}
+
+	var place int64
+	var err error
+
+	if d, ok := v.(types.Decimal128); ok {
+		if !d.IsInteger() {
+			err = handlerparams.ErrNotWholeNumber
+		}
+
+		place, _ = d.Int64()
+	} else {
+		place, err = handlerparams.GetWholeNumberParam(v)
+	}
+
+	if err != nil {
+		return 0, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrRoundPrecisionNotIntegral,
+			fmt.Sprintf("precision argument to  %s must be a integral value", r.name),
+			r.name+" (operator)",
+		)
+	}
+
+	if place < -20 || place > 100 {
+		return 0, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrRoundPrecisionOutOfRange,
+			fmt.Sprintf("cannot apply %s with precision value %d value must be in [-20, 100]", r.name, place),
+			r.name+" (operator)",
+		)
+	}
+
+	return place, nil
+}
+
+// roundNumber rounds or truncates the number to the given number of decimal places.
+func (r *round) roundNumber(number any, place int) any {
+	switch number := number.(type) {
+	case types.Decimal128:
+		return r.roundDecimal(number, place)
+
+	case float64:
+		if math.IsNaN(number) || math.IsInf(number, 0) {
+			return number
+		}
+
+		// use the shortest representation to avoid losing precision of the double
+		d, err := types.ParseDecimal128(strconv.FormatFloat(number, 'g', -1, 64))
+		if err != nil {
+			return number
+		}
+
+		return r.roundDecimal(d, place).Float64()
+
+	default:
+		if place >= 0 {
+			return number
+		}
+
+		d := r.roundDecimal(aggregations.NumberToDecimal128(number), place)
+
+		res, ok := d.Int64()
+		if !ok {
+			return d.Float64()
+		}
+
+		if _, ok := number.(int32); ok && res >= math.MinInt32 && res <= math.MaxInt32 {
+			return int32(res)
+		}
+
+		return res
+	}
+}
+
+// roundDecimal rounds or truncates the decimal to the given number of decimal places.
+func (r *round) roundDecimal(d types.Decimal128, place int) types.Decimal128 {
+	if r.trunc {
+		return d.Trunc(place)
+	}
+
+	return d.Round(place)
+}
+
+// check interfaces
+var (
+	_ Operator = (*round)(nil)
+)
@@ -0,0 +1,239 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// sortArrayKey represents a single field of `$sortArray` sort pattern.
+type sortArrayKey struct {
+	path  types.Path
+	order types.SortType
+}
+
+// sortArray represents `$sortArray` operator.
+type sortArray struct {
+	input any
+	order types.SortType // used if keys are not set
+	keys  []sortArrayKey // nil if elements are sorted by their values
+}
+
+// newSortArray returns `$sortArray` operator.
+func newSortArray(args ...any) (Operator, error) {
+	fields, err := namedArgs(
+		args,
+		[]string{"input", "sortBy"},
+		func(v any) error {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrSortArrayNotObject,
+				fmt.Sprintf("$sortArray requires an object as an argument, found: %s", typeAlias(v)),
+				"$sortArray (operator)",
+			)
+		},
+		func(field string) error {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrSortArrayUnknownField,
+				fmt.Sprintf("$sortArray found an unknown argument: %s", field),
+				"$sortArray (operator)",
+			)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if fields["input"] == nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSortArrayMissingInput,
+			"$sortArray requires 'input' to be specified",
+			"$sortArray (operator)",
+		)
+	}
+
+	if fields["sortBy"] == nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSortArrayMissingSortBy,
+			"$sortArray requires 'sortBy' to be specified",
+			"$sortArray (operator)",
+		)
+	}
+
+	s := &sortArray{
+		input: fields["input"],
+	}
+
+	sortBy, ok := fields["sortBy"].(*types.Document)
+	if !ok {
+		if s.order, err = getSortArrayOrder(fields["sortBy"]); err != nil {
+			return nil, err
+		}
+
+		return s, nil
+	}
+
+	if sortBy.Len() == 0 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			"$sortArray sortBy must not be an empty object",
+			"$sortArray (operator)",
+		)
+	}
+
+	iter := sortBy.Iterator()
+	defer iter.Close()
+
+	for {
+		k, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		path, err := types.NewPathFromString(k)
+		if err != nil {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrFailedToParse,
+				fmt.Sprintf("$sortArray sortBy contains an invalid field path: %q", k),
+				"$sortArray (operator)",
+			)
+		}
+
+		order, err := getSortArrayOrder(v)
+		if err != nil {
+			return nil, err
+		}
+
+		s.keys = append(s.keys, sortArrayKey{path: path, order: order})
+	}
+
+	return s, nil
+}
+
+// getSortArrayOrder returns the sort order for the given `$sortArray` sortBy value.
+func getSortArrayOrder(v any) (types.SortType, error) {
+	if !isNumber(v) {
+		return 0, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			fmt.Sprintf("$sortArray sortBy must be either 1, -1 or an object, found: %s", typeAlias(v)),
+			"$sortArray (operator)",
+		)
+	}
+
+	switch i, _ := integralToInt32(v); i {
+	case 1:
+		return types.Ascending, nil
+	case -1:
+		return types.Descending, nil
+	default:
+		return 0, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSortBadOrder,
+			"$sort key ordering must be 1 (for ascending) or -1 (for descending)",
+			"$sortArray (operator)",
+		)
+	}
+}
+
+// Process implements Operator interface.
+//
+// Sort is stable.
+// Without sort pattern, elements are compared as whole values using BSON comparison order.
+// With sort pattern, elements are compared by the fields like the `$sort` stage does;
+// missing fields and fields of non-document elements are treated as null.
+func (s *sortArray) Process(doc *types.Document, vars *aggregations.Variables) (any, error) {
+	input, err := evaluate(s.input, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if isNullish(input) {
+		return types.Null, nil
+	}
+
+	arr, ok := input.(*types.Array)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSortArrayInputNotArray,
+			fmt.Sprintf("The input argument to $sortArray must be an array, but was of type: %s", typeAlias(input)),
+			"$sortArray (operator)",
+		)
+	}
+
+	values := arrayValues(arr)
+
+	slices.SortStableFunc(values, func(a, b any) int {
+		if s.keys == nil {
+			return compareResultToInt(compareValues(a, b)) * int(s.order)
+		}
+
+		for _, key := range s.keys {
+			av, bv := sortArrayField(a, key.path), sortArrayField(b, key.path)
+
+			// the result is already inverted for descending order
+			if res := types.CompareOrderForSort(av, bv, key.order); res != types.Equal {
+				return compareResultToInt(res)
+			}
+		}
+
+		return 0
+	})
+
+	return must.NotFail(types.NewArray(values...)), nil
+}
+
+// sortArrayField returns the value of the field on the given path of the array element,
+// or null if the element is not a document or the field is missing.
+func sortArrayField(elem any, path types.Path) any {
+	doc, ok := elem.(*types.Document)
+	if !ok {
+		return types.Null
+	}
+
+	v, err := doc.GetByPath(path)
+	if err != nil {
+		return types.Null
+	}
+
+	return v
+}
+
+// compareResultToInt converts the comparison result to -1, 0 or 1.
+func compareResultToInt(res types.CompareResult) int {
+	switch res {
+	case types.Less:
+		return -1
+	case types.Greater:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// check interfaces
+var (
+	_ Operator = (*sortArray)(nil)
+)
@@ -0,0 +1,101 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+// split represents `$split` operator.
+type split struct {
+	str, delimiter any
+}
+
+// newSplit returns `$split` operator.
+func newSplit(args ...any) (Operator, error) {
+	if len(args) != 2 {
+		return nil, newArgsLenError("$split", 2, len(args))
+	}
+
+	return &split{
+		str:       args[0],
+		delimiter: args[1],
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// It returns null if the string or the delimiter is null or missing.
+func (s *split) Process(doc *types.Document, vars *aggregations.Variables) (any, error) {
+	values, err := evaluateArgs([]any{s.str, s.delimiter}, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if isNullish(values[0]) || isNullish(values[1]) {
+		return types.Null, nil
+	}
+
+	str, ok := values[0].(string)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSplitNonString,
+			fmt.Sprintf(
+				"$split requires an expression that evaluates to a string as a first argument, found: %s",
+				typeAlias(values[0]),
+			),
+			"$split (operator)",
+		)
+	}
+
+	delimiter, ok := values[1].(string)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSplitDelimiterNonString,
+			fmt.Sprintf(
+				"$split requires an expression that evaluates to a string as a second argument, found: %s",
+				typeAlias(values[1]),
+			),
+			"$split (operator)",
+		)
+	}
+
+	if delimiter == "" {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSplitEmptyDelimiter,
+			"$split requires a non-empty separator",
+			"$split (operator)",
+		)
+	}
+
+	parts := strings.Split(str, delimiter)
+
+	res := types.MakeArray(len(parts))
+	for _, p := range parts {
+		res.Append(p)
+	}
+
+	return res, nil
+}
+
+// check interfaces
+var (
+	_ Operator = (*split)(nil)
+)
@@ -0,0 +1,252 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+// coerceToString converts the evaluated value to string the way string operators do it.
+//
+// Null and missing values are converted to empty string;
+// numbers, dates and timestamps are formatted.
+// Other types are not converted and the error is returned.
+func coerceToString(name string, v any) (string, error) {
+	switch v := v.(type) {
+	case nil, types.NullType:
+		return "", nil
+	case string:
+		return v, nil
+	case int32:
+		return strconv.FormatInt(int64(v), 10), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	case types.Decimal128:
+		return v.String(), nil
+	case time.Time:
+		return v.UTC().Format("2006-01-02T15:04:05.000Z"), nil
+	case types.Timestamp:
+		return fmt.Sprintf("Timestamp(%d, %d)", uint64(v)>>32, uint32(v)), nil
+	default:
+		return "", handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrConvertToString,
+			fmt.Sprintf("can't convert from BSON type %s to String", handlerparams.AliasFromType(v)),
+			name+" (operator)",
+		)
+	}
+}
+
+// typeAlias returns the type alias of the evaluated value for error messages,
+// including "missing" for a missing value.
+func typeAlias(v any) string {
+	if v == nil {
+		return "missing"
+	}
+
+	return handlerparams.AliasFromType(v)
+}
+
+// stringCase represents `$toLower` and `$toUpper` operators.
+type stringCase struct {
+	name  string
+	arg   any
+	upper bool
+}
+
+// newToLower returns `$toLower` operator.
+func newToLower(args ...any) (Operator, error) {
+	if len(args) != 1 {
+		return nil, newArgsLenError("$toLower", 1, len(args))
+	}
+
+	return &stringCase{
+		name: "$toLower",
+		arg:  args[0],
+	}, nil
+}
+
+// newToUpper returns `$toUpper` operator.
+func newToUpper(args ...any) (Operator, error) {
+	if len(args) != 1 {
+		return nil, newArgsLenError("$toUpper", 1, len(args))
+	}
+
+	return &stringCase{
+		name:  "$toUpper",
+		arg:   args[0],
+		upper: true,
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// Like MongoDB, only ASCII characters are converted.
+func (c *stringCase) Process(doc *types.Document, vars *aggregations.Variables) (any, error) {
+	v, err := evaluate(c.arg, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := coerceToString(c.name, v)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.upper {
+		return asciiToUpper(s), nil
+	}
+
+	return asciiToLower(s), nil
+}
+
+// asciiToLower returns s with all ASCII letters mapped to their lower case.
+func asciiToLower(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'A' && r <= 'Z' {
+			return r + 'a' - 'A'
+		}
+
+		return r
+	}, s)
+}
+
+// asciiToUpper returns s with all ASCII letters mapped to their upper case.
+func asciiToUpper(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' {
+			return r - 'a' + 'A'
+		}
+
+		return r
+	}, s)
+}
+
+// strcasecmp represents `$strcasecmp` operator.
+type strcasecmp struct {
+	a, b any
+}
+
+// newStrcasecmp returns `$strcasecmp` operator.
+func newStrcasecmp(args ...any) (Operator, error) {
+	if len(args) != 2 {
+		return nil, newArgsLenError("$strcasecmp", 2, len(args))
+	}
+
+	return &strcasecmp{
+		a: args[0],
+		b: args[1],
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// It returns -1, 0 or 1 for case-insensitive comparison of ASCII strings.
+func (c *strcasecmp) Process(doc *types.Document, vars *aggregations.Variables) (any, error) {
+	values, err := evaluateArgs([]any{c.a, c.b}, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := coerceToString("$strcasecmp", values[0])
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := coerceToString("$strcasecmp", values[1])
+	if err != nil {
+		return nil, err
+	}
+
+	return int32(strings.Compare(asciiToUpper(a), asciiToUpper(b))), nil
+}
+
+// strLen represents `$strLenBytes` and `$strLenCP` operators.
+type strLen struct {
+	arg        any
+	codePoints bool
+}
+
+// newStrLenBytes returns `$strLenBytes` operator.
+func newStrLenBytes(args ...any) (Operator, error) {
+	if len(args) != 1 {
+		return nil, newArgsLenError("$strLenBytes", 1, len(args))
+	}
+
+	return &strLen{
+		arg: args[0],
+	}, nil
+}
+
+// newStrLenCP returns `$strLenCP` operator.
+func newStrLenCP(args ...any) (Operator, error) {
+	if len(args) != 1 {
+		return nil, newArgsLenError("$strLenCP", 1, len(args))
+	}
+
+	return &strLen{
+		arg:        args[0],
+		codePoints: true,
+	}, nil
+}
+
+// Process implements Operator interface.
+func (l *strLen) Process(doc *types.Document, vars *aggregations.Variables) (any, error) {
+	v, err := evaluate(l.arg, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	s, ok := v.(string)
+
+	switch {
+	case !ok && l.codePoints:
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrStrLenCPNonString,
+			fmt.Sprintf("$strLenCP requires a string argument, found: %s", typeAlias(v)),
+			"$strLenCP (operator)",
+		)
+
+	case !ok:
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrStrLenBytesNonString,
+			fmt.Sprintf("$strLenBytes requires a string argument, found: %s", typeAlias(v)),
+			"$strLenBytes (operator)",
+		)
+
+	case l.codePoints:
+		return int32(utf8.RuneCountInString(s)), nil
+
+	default:
+		return int32(len(s)), nil
+	}
+}
+
+// check interfaces
+var (
+	_ Operator = (*stringCase)(nil)
+	_ Operator = (*strcasecmp)(nil)
+	_ Operator = (*strLen)(nil)
+)
@@ -0,0 +1,210 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+// substrBytes represents `$substrBytes` (and deprecated `$substr`) operator.
+type substrBytes struct {
+	str, start, length any
+}
+
+// newSubstrBytes returns `$substrBytes` operator.
+func newSubstrBytes(args ...any) (Operator, error) {
+	if len(args) != 3 {
+		return nil, newArgsLenError("$substrBytes", 3, len(args))
+	}
+
+	return &substrBytes{
+		str:    args[0],
+		start:  args[1],
+		length: args[2],
+	}, nil
+}
+
+// Process implements Operator interface.
+func (s *substrBytes) Process(doc *types.Document, vars *aggregations.Variables) (any, error) {
+	values, err := evaluateArgs([]any{s.str, s.start, s.length}, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	str, err := coerceToString("$substrBytes", values[0])
+	if err != nil {
+		return nil, err
+	}
+
+	if !aggregations.IsNumber(values[1]) {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSubstrStartNonNumeric,
+			fmt.Sprintf("$substrBytes: starting index must be a numeric type (is BSON type %s)", typeAlias(values[1])),
+			"$substrBytes (operator)",
+		)
+	}
+
+	if !aggregations.IsNumber(values[2]) {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSubstrLengthNonNumeric,
+			fmt.Sprintf("$substrBytes: length must be a numeric type (is BSON type %s)", typeAlias(values[2])),
+			"$substrBytes (operator)",
+		)
+	}
+
+	start := aggregations.NumberToFloat64(values[1])
+	if start < 0 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSubstrBytesStartNegative,
+			fmt.Sprintf("$substrBytes: starting index must be non-negative (got: %d)", int64(start)),
+			"$substrBytes (operator)",
+		)
+	}
+
+	if start >= float64(len(str)) {
+		return "", nil
+	}
+
+	lower := int(start)
+
+	// negative length means the rest of the string
+	upper := len(str)
+	if length := aggregations.NumberToFloat64(values[2]); length >= 0 && length < float64(len(str)-lower) {
+		upper = lower + int(length)
+	}
+
+	if !utf8.RuneStart(str[lower]) {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSubstrBytesStartContinuation,
+			"$substrBytes:  Invalid range, starting index is a UTF-8 continuation byte.",
+			"$substrBytes (operator)",
+		)
+	}
+
+	if upper < len(str) && !utf8.RuneStart(str[upper]) {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSubstrBytesEndContinuation,
+			"$substrBytes:  Invalid range, ending index is in the middle of a UTF-8 character.",
+			"$substrBytes (operator)",
+		)
+	}
+
+	return str[lower:upper], nil
+}
+
+// substrCP represents `$substrCP` operator.
+type substrCP struct {
+	str, start, length any
+}
+
+// newSubstrCP returns `$substrCP` operator.
+func newSubstrCP(args ...any) (Operator, error) {
+	if len(args) != 3 {
+		return nil, newArgsLenError("$substrCP", 3, len(args))
+	}
+
+	return &substrCP{
+		str:    args[0],
+		start:  args[1],
+		length: args[2],
+	}, nil
+}
+
+// Process implements Operator interface.
+func (s *substrCP) Process(doc *types.Document, vars *aggregations.Variables) (any, error) {
+	values, err := evaluateArgs([]any{s.str, s.start, s.length}, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	str, err := coerceToString("$substrCP", values[0])
+	if err != nil {
+		return nil, err
+	}
+
+	if !aggregations.IsNumber(values[1]) {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSubstrCPStartNonNumeric,
+			fmt.Sprintf("$substrCP: starting index must be a numeric type (is BSON type %s)", typeAlias(values[1])),
+			"$substrCP (operator)",
+		)
+	}
+
+	if !aggregations.IsNumber(values[2]) {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSubstrCPLengthNonNumeric,
+			fmt.Sprintf("$substrCP: length must be a numeric type (is BSON type %s)", typeAlias(values[2])),
+			"$substrCP (operator)",
+		)
+	}
+
+	start, ok := integralToInt32(values[1])
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSubstrCPStartNotIntegral,
+			fmt.Sprintf(
+				"$substrCP: starting index cannot be represented as a 32-bit integral value: %s",
+				types.FormatAnyValue(values[1]),
+			),
+			"$substrCP (operator)",
+		)
+	}
+
+	length, ok := integralToInt32(values[2])
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSubstrCPLengthNotIntegral,
+			fmt.Sprintf(
+				"$substrCP: length cannot be represented as a 32-bit integral value: %s",
+				types.FormatAnyValue(values[2]),
+			),
+			"$substrCP (operator)",
+		)
+	}
+
+	if length < 0 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSubstrCPLengthNegative,
+			"$substrCP: length must be a nonnegative integer.",
+			"$substrCP (operator)",
+		)
+	}
+
+	if start < 0 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSubstrCPStartNegative,
+			"$substrCP: the starting index must be nonnegative integer.",
+			"$substrCP (operator)",
+		)
+	}
+
+	runes := []rune(str)
+
+	lower := min(int(start), len(runes))
+	upper := min(lower+int(length), len(runes))
+
+	return string(runes[lower:upper]), nil
+}
+
+// check interfaces
+var (
+	_ Operator = (*substrBytes)(nil)
+	_ Operator = (*substrCP)(nil)
+)
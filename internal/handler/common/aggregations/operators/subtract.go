@@ -0,0 +1,98 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+// subtract represents `$subtract` operator.
+type subtract struct {
+	minuend    any
+	subtrahend any
+}
+
+// newSubtract returns `$subtract` operator.
+func newSubtract(args ...any) (Operator, error) {
+	if len(args) != 2 {
+		return nil, newArgsLenError("$subtract", 2, len(args))
+	}
+
+	return &subtract{
+		minuend:    args[0],
+		subtrahend: args[1],
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// It returns the difference of two numbers, the difference of two dates in milliseconds,
+// or a date with the number of milliseconds subtracted.
+// Null is returned if any argument is null or missing.
+func (s *subtract) Process(doc *types.Document, vars *aggregations.Variables) (any, error) {
+	values, err := evaluateArgs([]any{s.minuend, s.subtrahend}, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	a, b := values[0], values[1]
+
+	switch {
+	case aggregations.IsNumber(a) && aggregations.IsNumber(b):
+		return aggregations.SubtractNumbers(a, b), nil
+
+	case isNullish(a) || isNullish(b):
+		return types.Null, nil
+	}
+
+	date, ok := a.(time.Time)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrTypeMismatch,
+			fmt.Sprintf(
+				"can't $subtract %s from %s",
+				handlerparams.AliasFromType(b), handlerparams.AliasFromType(a),
+			),
+			"$subtract (operator)",
+		)
+	}
+
+	switch b := b.(type) {
+	case time.Time:
+		return date.UnixMilli() - b.UnixMilli(), nil
+
+	default:
+		if !aggregations.IsNumber(b) {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrTypeMismatch,
+				fmt.Sprintf("can't $subtract %s from Date", handlerparams.AliasFromType(b)),
+				"$subtract (operator)",
+			)
+		}
+
+		return addMillis(date, aggregations.SubtractNumbers(int32(0), b)), nil
+	}
+}
+
+// check interfaces
+var (
+	_ Operator = (*subtract)(nil)
+)
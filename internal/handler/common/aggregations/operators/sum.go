@@ -75,12 +75,16 @@ func newSum(args ...any) (Operator, error) {
 // Process implements Operator interface.
 // It evaluates expressions if any to fetch a value, creates new operator and processes them if any
 // and sums all int32, int64 and float64 numbers ignoring other types.
-func (s *sum) Process(doc *types.Document) (any, error) {
+func (s *sum) Process(doc *types.Document, vars *aggregations.Variables) (any, error) {
 	var numbers []any
 
 	for _, expression := range s.expressions {
-		value, err := expression.Evaluate(doc)
+		value, err := expression.Evaluate(doc, vars)
 		if err != nil {
+			if err = checkUndefinedVariable(err); err != nil {
+				return nil, err
+			}
+
 			// $sum ignores failed expression evaluation
 			continue
 		}
@@ -125,7 +129,7 @@ func (s *sum) Process(doc *types.Document) (any, error) {
 			return nil, err
 		}
 
-		v, err := op.Process(doc)
+		v, err := op.Process(doc, vars)
 		if err != nil {
 			return nil, err
 		}
@@ -135,7 +139,7 @@ func (s *sum) Process(doc *types.Document) (any, error) {
 
 	for _, number := range s.numbers {
 		switch number := number.(type) {
-		case float64, int32, int64:
+		case float64, int32, int64, types.Decimal128:
 			numbers = append(numbers, number)
 		}
 	}
@@ -0,0 +1,210 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+)
+
+// switchBranch represents a single branch of `$switch` operator.
+type switchBranch struct {
+	caseExpr any
+	thenExpr any
+}
+
+// switchOp represents `$switch` operator.
+type switchOp struct {
+	branches    []switchBranch
+	defaultExpr any // nil if not set
+}
+
+// newSwitch returns `$switch` operator.
+func newSwitch(args ...any) (Operator, error) {
+	var spec *types.Document
+
+	if len(args) == 1 {
+		spec, _ = args[0].(*types.Document)
+	}
+
+	if spec == nil {
+		found := "array"
+		if len(args) == 1 {
+			found = handlerparams.AliasFromType(args[0])
+		}
+
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSwitchNotObject,
+			fmt.Sprintf("$switch requires an object as an argument, found: %s", found),
+			"$switch (operator)",
+		)
+	}
+
+	var s switchOp
+
+	iter := spec.Iterator()
+	defer iter.Close()
+
+	for {
+		k, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		switch k {
+		case "branches":
+			if s.branches, err = newSwitchBranches(v); err != nil {
+				return nil, err
+			}
+
+		case "default":
+			s.defaultExpr = v
+
+		default:
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrSwitchUnknownArgument,
+				fmt.Sprintf("$switch found an unknown argument: %s", k),
+				"$switch (operator)",
+			)
+		}
+	}
+
+	if len(s.branches) == 0 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSwitchNoBranches,
+			"$switch requires at least one branch.",
+			"$switch (operator)",
+		)
+	}
+
+	return &s, nil
+}
+
+// newSwitchBranches returns `$switch` branches from the value of `branches` field.
+func newSwitchBranches(v any) ([]switchBranch, error) {
+	arr, ok := v.(*types.Array)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSwitchBranchesNotArray,
+			fmt.Sprintf("$switch expected an array for 'branches', found: %s", handlerparams.AliasFromType(v)),
+			"$switch (operator)",
+		)
+	}
+
+	res := make([]switchBranch, 0, arr.Len())
+
+	iter := arr.Iterator()
+	defer iter.Close()
+
+	for {
+		_, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		doc, ok := v.(*types.Document)
+		if !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrSwitchBranchNotObject,
+				fmt.Sprintf("$switch expected each branch to be an object, found: %s", handlerparams.AliasFromType(v)),
+				"$switch (operator)",
+			)
+		}
+
+		var branch switchBranch
+
+		for _, k := range doc.Keys() {
+			switch k {
+			case "case":
+				branch.caseExpr, _ = doc.Get(k)
+			case "then":
+				branch.thenExpr, _ = doc.Get(k)
+			default:
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrSwitchUnknownBranchArgument,
+					fmt.Sprintf("$switch found an unknown argument to a branch: %s", k),
+					"$switch (operator)",
+				)
+			}
+		}
+
+		if !doc.Has("case") {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrSwitchMissingCase,
+				"$switch requires each branch have a 'case' expression",
+				"$switch (operator)",
+			)
+		}
+
+		if !doc.Has("then") {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrSwitchMissingThen,
+				"$switch requires each branch have a 'then' expression.",
+				"$switch (operator)",
+			)
+		}
+
+		res = append(res, branch)
+	}
+
+	return res, nil
+}
+
+// Process implements Operator interface.
+//
+// It returns the value of `then` expression of the first branch with true `case` expression,
+// or the value of `default` expression if no branch matches.
+func (s *switchOp) Process(doc *types.Document, vars *aggregations.Variables) (any, error) {
+	for _, branch := range s.branches {
+		v, err := evaluate(branch.caseExpr, doc, vars)
+		if err != nil {
+			return nil, err
+		}
+
+		if isTrue(v) {
+			return evaluate(branch.thenExpr, doc, vars)
+		}
+	}
+
+	if s.defaultExpr == nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSwitchNoMatchingBranch,
+			"$switch could not find a matching branch for an input, and no default was specified.",
+			"$switch (operator)",
+		)
+	}
+
+	return evaluate(s.defaultExpr, doc, vars)
+}
+
+// check interfaces
+var (
+	_ Operator = (*switchOp)(nil)
+)
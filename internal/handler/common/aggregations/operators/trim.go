@@ -0,0 +1,180 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+)
+
+// trimWhitespace contains characters trimmed by default,
+// the same as in MongoDB.
+const trimWhitespace = "\x00 \t\n\v\f\r\u00a0\u1680" +
+	"\u2000\u2001\u2002\u2003\u2004\u2005\u2006\u2007\u2008\u2009\u200a"
+
+// trimType represents a side of the string to trim.
+type trimType int
+
+const (
+	trimBoth trimType = iota
+	trimLeft
+	trimRight
+)
+
+// trim represents `$trim`, `$ltrim` and `$rtrim` operators.
+type trim struct {
+	name  string
+	input any
+	chars any // nil for default whitespace characters
+	typ   trimType
+}
+
+// newTrimFunc returns a function that creates a trim operator with the given name and type.
+func newTrimFunc(name string, typ trimType) newOperatorFunc {
+	return func(args ...any) (Operator, error) {
+		var spec *types.Document
+
+		if len(args) == 1 {
+			spec, _ = args[0].(*types.Document)
+		}
+
+		if spec == nil {
+			var v any = types.MakeArray(len(args))
+			if len(args) == 1 {
+				v = args[0]
+			}
+
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrTrimNotObject,
+				fmt.Sprintf("%s only supports an object as an argument, found: %s", name, handlerparams.AliasFromType(v)),
+				name+" (operator)",
+			)
+		}
+
+		t := &trim{
+			name: name,
+			typ:  typ,
+		}
+
+		iter := spec.Iterator()
+		defer iter.Close()
+
+		for {
+			k, v, err := iter.Next()
+			if errors.Is(err, iterator.ErrIteratorDone) {
+				break
+			}
+
+			if err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+
+			switch k {
+			case "input":
+				t.input = v
+			case "chars":
+				t.chars = v
+			default:
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrTrimUnknownArgument,
+					fmt.Sprintf("%s found an unknown argument: %s", name, k),
+					name+" (operator)",
+				)
+			}
+		}
+
+		if t.input == nil {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrTrimMissingInput,
+				fmt.Sprintf("%s requires an 'input' field", name),
+				name+" (operator)",
+			)
+		}
+
+		return t, nil
+	}
+}
+
+// Process implements Operator interface.
+//
+// It returns null if input or chars is null or missing.
+func (t *trim) Process(doc *types.Document, vars *aggregations.Variables) (any, error) {
+	input, err := evaluate(t.input, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if isNullish(input) {
+		return types.Null, nil
+	}
+
+	s, ok := input.(string)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrTrimInputNonString,
+			fmt.Sprintf(
+				"%s requires its input to be a string, got %s (of type %s) instead.",
+				t.name, types.FormatAnyValue(input), handlerparams.AliasFromType(input),
+			),
+			t.name+" (operator)",
+		)
+	}
+
+	cutset := trimWhitespace
+
+	if t.chars != nil {
+		chars, err := evaluate(t.chars, doc, vars)
+		if err != nil {
+			return nil, err
+		}
+
+		if isNullish(chars) {
+			return types.Null, nil
+		}
+
+		if cutset, ok = chars.(string); !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrTrimCharsNonString,
+				fmt.Sprintf(
+					"%s requires 'chars' to be a string, got %s (of type %s) instead.",
+					t.name, types.FormatAnyValue(chars), handlerparams.AliasFromType(chars),
+				),
+				t.name+" (operator)",
+			)
+		}
+	}
+
+	switch t.typ {
+	case trimLeft:
+		return strings.TrimLeft(s, cutset), nil
+	case trimRight:
+		return strings.TrimRight(s, cutset), nil
+	default:
+		return strings.Trim(s, cutset), nil
+	}
+}
+
+// check interfaces
+var (
+	_ Operator = (*trim)(nil)
+)
@@ -48,7 +48,7 @@ func newType(args ...any) (Operator, error) {
 }
 
 // Process implements Operator interface.
-func (t *typeOp) Process(doc *types.Document) (any, error) {
+func (t *typeOp) Process(doc *types.Document, vars *aggregations.Variables) (any, error) {
 	typeParam := t.param
 
 	var paramEvaluated bool
@@ -79,7 +79,7 @@ func (t *typeOp) Process(doc *types.Document) (any, error) {
 				return nil, opErr
 			}
 
-			if typeParam, err = operator.Process(doc); err != nil {
+			if typeParam, err = operator.Process(doc, vars); err != nil {
 				var opErr OperatorError
 				if !errors.As(err, &opErr) {
 					return nil, lazyerrors.Error(err)
@@ -92,7 +92,8 @@ func (t *typeOp) Process(doc *types.Document) (any, error) {
 			paramEvaluated = false
 
 		case *types.Array, float64, types.Binary, types.ObjectID, bool, time.Time,
-			types.NullType, types.Regex, int32, types.Timestamp, int64:
+			types.NullType, types.Regex, int32, types.Timestamp, int64,
+			types.Decimal128, types.MinKeyType, types.MaxKeyType:
 			res = param
 
 		case string:
@@ -102,8 +103,12 @@ func (t *typeOp) Process(doc *types.Document) (any, error) {
 					return nil, err
 				}
 
-				value, err := expression.Evaluate(doc)
+				value, err := expression.Evaluate(doc, vars)
 				if err != nil {
+					if err = checkUndefinedVariable(err); err != nil {
+						return nil, err
+					}
+
 					return "missing", nil
 				}
 
@@ -0,0 +1,200 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+// unaryFunc is a function that computes the result of numeric operator
+// for the given int32, int64, float64 or Decimal128 number.
+type unaryFunc func(number any) (any, error)
+
+// unary represents numeric operators with a single argument, like `$abs` or `$sqrt`.
+type unary struct {
+	name string
+	arg  any
+	f    unaryFunc
+}
+
+// newUnaryFunc returns a function that creates numeric operator with the given name.
+func newUnaryFunc(name string, f unaryFunc) newOperatorFunc {
+	return func(args ...any) (Operator, error) {
+		if len(args) != 1 {
+			return nil, newArgsLenError(name, 1, len(args))
+		}
+
+		return &unary{
+			name: name,
+			arg:  args[0],
+			f:    f,
+		}, nil
+	}
+}
+
+// Process implements Operator interface.
+//
+// Null is returned if the argument is null or missing.
+func (u *unary) Process(doc *types.Document, vars *aggregations.Variables) (any, error) {
+	v, err := evaluate(u.arg, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if isNullish(v) {
+		return types.Null, nil
+	}
+
+	if !aggregations.IsNumber(v) {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrOperatorNonNumeric,
+			fmt.Sprintf("%s only supports numeric types, not %s", u.name, handlerparams.AliasFromType(v)),
+			u.name+" (operator)",
+		)
+	}
+
+	return u.f(v)
+}
+
+// abs returns the absolute value of the number.
+// The absolute value of the minimal int32 is int64.
+func abs(number any) (any, error) {
+	switch number := number.(type) {
+	case int32:
+		if number == math.MinInt32 {
+			return -int64(number), nil
+		}
+
+		return max(number, -number), nil
+
+	case int64:
+		if number == math.MinInt64 {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrAbsLongMin,
+				"can't take $abs of long long min",
+				"$abs (operator)",
+			)
+		}
+
+		return max(number, -number), nil
+
+	case float64:
+		return math.Abs(number), nil
+
+	default:
+		return number.(types.Decimal128).Abs(), nil
+	}
+}
+
+// ceil returns the least integer value greater than or equal to the number.
+func ceil(number any) (any, error) {
+	switch number := number.(type) {
+	case float64:
+		return math.Ceil(number), nil
+	case types.Decimal128:
+		return number.Ceil(), nil
+	default:
+		return number, nil
+	}
+}
+
+// floor returns the greatest integer value less than or equal to the number.
+func floor(number any) (any, error) {
+	switch number := number.(type) {
+	case float64:
+		return math.Floor(number), nil
+	case types.Decimal128:
+		return number.Floor(), nil
+	default:
+		return number, nil
+	}
+}
+
+// exp returns e raised to the power of the number.
+func exp(number any) (any, error) {
+	return floatResult(number, math.Exp(aggregations.NumberToFloat64(number))), nil
+}
+
+// ln returns the natural logarithm of the positive number.
+func ln(number any) (any, error) {
+	f := aggregations.NumberToFloat64(number)
+
+	if !(f > 0) && !math.IsNaN(f) {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrLnNonPositive,
+			fmt.Sprintf("$ln's argument must be a positive number, but is %v", number),
+			"$ln (operator)",
+		)
+	}
+
+	return floatResult(number, math.Log(f)), nil
+}
+
+// log10 returns the decimal logarithm of the positive number.
+func log10(number any) (any, error) {
+	f := aggregations.NumberToFloat64(number)
+
+	if !(f > 0) && !math.IsNaN(f) {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrLog10NonPositive,
+			fmt.Sprintf("$log10's argument must be a positive number, but is %v", number),
+			"$log10 (operator)",
+		)
+	}
+
+	return floatResult(number, math.Log10(f)), nil
+}
+
+// sqrt returns the square root of the non-negative number.
+func sqrt(number any) (any, error) {
+	f := aggregations.NumberToFloat64(number)
+
+	if f < 0 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSqrtNegative,
+			"$sqrt's argument must be greater than or equal to 0",
+			"$sqrt (operator)",
+		)
+	}
+
+	return floatResult(number, math.Sqrt(f)), nil
+}
+
+// floatResult returns the result of computation on float64 as Decimal128
+// if the argument is Decimal128, and as float64 otherwise.
+func floatResult(arg any, res float64) any {
+	if isDecimal(arg) {
+		return types.NewDecimal128FromFloat64(res)
+	}
+
+	return res
+}
+
+// isDecimal returns true if the given value is Decimal128.
+func isDecimal(v any) bool {
+	_, ok := v.(types.Decimal128)
+	return ok
+}
+
+// check interfaces
+var (
+	_ Operator = (*unary)(nil)
+)
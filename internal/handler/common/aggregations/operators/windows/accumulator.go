@@ -0,0 +1,97 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package windows
+
+import (
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations/operators/accumulators"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// windowAccumulator represents an accumulator used as a window operator.
+type windowAccumulator struct {
+	accumulator accumulators.Accumulator
+	window      *bounds
+}
+
+// newWindowAccumulator creates a window operator for the accumulator with the given name.
+func newWindowAccumulator(field, name string, arg any, window *bounds) (Operator, error) {
+	accumulator, err := accumulators.NewAccumulator(
+		"$setWindowFields",
+		field,
+		must.NotFail(types.NewDocument(name, arg)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &windowAccumulator{
+		accumulator: accumulator,
+		window:      window,
+	}, nil
+}
+
+// Apply implements Operator interface.
+//
+// While the first document of the window stays the same and the window only grows,
+// the same accumulation is used, so running totals are computed in linear time.
+func (w *windowAccumulator) Apply(partition []*types.Document, vars *aggregations.Variables) ([]any, error) {
+	windows, err := w.window.resolve(partition)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]any, len(partition))
+
+	var accumulation accumulators.Accumulation
+	var first, last int
+
+	for i, window := range windows {
+		if accumulation == nil || window.first != first || window.last < last {
+			accumulation = w.accumulator.NewAccumulation(accumulators.NewMemoryUsage("$setWindowFields"))
+			first, last = window.first, window.first-1
+		}
+
+		for last < window.last {
+			last++
+
+			if err = accumulation.Add(partition[last], vars); err != nil {
+				return nil, err
+			}
+		}
+
+		res[i] = copyValue(accumulation.Result())
+	}
+
+	return res, nil
+}
+
+// copyValue returns a deep copy of composite value, so it is not modified by the following accumulation.
+func copyValue(v any) any {
+	switch v := v.(type) {
+	case *types.Document:
+		return v.DeepCopy()
+	case *types.Array:
+		return v.DeepCopy()
+	default:
+		return v
+	}
+}
+
+// check interfaces
+var (
+	_ Operator = (*windowAccumulator)(nil)
+)
@@ -0,0 +1,356 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package windows
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// unitMillis maps fixed size time units to their duration in milliseconds.
+var unitMillis = map[string]float64{
+	"week":        float64(7 * 24 * time.Hour / time.Millisecond),
+	"day":         float64(24 * time.Hour / time.Millisecond),
+	"hour":        float64(time.Hour / time.Millisecond),
+	"minute":      float64(time.Minute / time.Millisecond),
+	"second":      float64(time.Second / time.Millisecond),
+	"millisecond": 1,
+}
+
+// unitMonths maps time units of variable size to the number of months.
+var unitMonths = map[string]int{
+	"year":    12,
+	"quarter": 3,
+	"month":   1,
+}
+
+// bound represents a lower or upper bound of the window.
+type bound struct {
+	unbounded bool
+	offset    float64 // zero for the current document
+}
+
+// bounds represents document-based or range-based window.
+//
+//	{ documents: [ <lower>, <upper> ] }
+//	{ range: [ <lower>, <upper> ], unit: <time unit> }
+type bounds struct {
+	lower bound
+	upper bound
+
+	// rangeKey is the sort key used by range-based window, nil for document-based window.
+	rangeKey *sortKey
+
+	// unit is the time unit of range-based window for dates, empty for numbers.
+	unit string
+}
+
+// window represents indexes of the first and the last documents of the window in the partition.
+// The last index is less than the first one for an empty window.
+type window struct {
+	first int
+	last  int
+}
+
+// newBounds parses `window` field of window operator.
+func newBounds(value any, sortBy []sortKey) (*bounds, error) {
+	doc, ok := value.(*types.Document)
+	if !ok {
+		return nil, newParseError("'window' field must be an object")
+	}
+
+	var documents, rangeValue, unit any
+
+	iter := doc.Iterator()
+	defer iter.Close()
+
+	for {
+		k, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		switch k {
+		case "documents":
+			documents = v
+		case "range":
+			rangeValue = v
+		case "unit":
+			unit = v
+		default:
+			return nil, newParseError(
+				"'window' field can only contain 'documents' as the only argument or 'range' with an optional 'unit' field",
+			)
+		}
+	}
+
+	switch {
+	case documents != nil && rangeValue != nil:
+		return nil, newParseError("Window bounds can specify either 'documents' or 'range', not both")
+	case documents == nil && rangeValue == nil:
+		return nil, newParseError("'window' field must specify either 'documents' or 'range'")
+	case documents != nil && unit != nil:
+		return nil, newParseError("Window bounds can only specify 'unit' with range-based bounds")
+	}
+
+	var b bounds
+	var err error
+
+	if documents != nil {
+		if b.lower, b.upper, err = parseBounds(documents, true); err != nil {
+			return nil, err
+		}
+
+		if (!b.lower.unbounded || !b.upper.unbounded) && len(sortBy) == 0 {
+			return nil, newParseError("Document-based bounds require a sortBy")
+		}
+
+		return &b, nil
+	}
+
+	if b.lower, b.upper, err = parseBounds(rangeValue, false); err != nil {
+		return nil, err
+	}
+
+	if len(sortBy) != 1 {
+		return nil, newParseError("Range-based window require sortBy a single field")
+	}
+
+	b.rangeKey = &sortBy[0]
+
+	if unit != nil {
+		if b.unit, err = parseUnit(unit); err != nil {
+			return nil, err
+		}
+	}
+
+	return &b, nil
+}
+
+// parseBounds parses an array of lower and upper bounds of document-based or range-based window.
+func parseBounds(value any, documents bool) (bound, bound, error) {
+	arr, ok := value.(*types.Array)
+	if !ok || arr.Len() != 2 {
+		return bound{}, bound{}, newParseError(
+			fmt.Sprintf("Window bounds must be a 2-element array: %s", types.FormatAnyValue(value)),
+		)
+	}
+
+	lower, err := parseBound(must.NotFail(arr.Get(0)), documents)
+	if err != nil {
+		return bound{}, bound{}, err
+	}
+
+	upper, err := parseBound(must.NotFail(arr.Get(1)), documents)
+	if err != nil {
+		return bound{}, bound{}, err
+	}
+
+	if !lower.unbounded && !upper.unbounded && lower.offset > upper.offset {
+		return bound{}, bound{}, newParseError(
+			fmt.Sprintf("Lower bound must not exceed upper bound: %s", types.FormatAnyValue(arr)),
+		)
+	}
+
+	return lower, upper, nil
+}
+
+// parseBound parses a single bound: "unbounded", "current", or a number.
+// Bounds of document-based window must be integral.
+func parseBound(value any, documents bool) (bound, error) {
+	switch value {
+	case "unbounded":
+		return bound{unbounded: true}, nil
+	case "current":
+		return bound{}, nil
+	}
+
+	if documents {
+		offset, err := handlerparams.GetWholeNumberParam(value)
+		if err != nil {
+			return bound{}, newParseError(fmt.Sprintf(
+				"Numeric document-based bounds must be an integer, 'unbounded' or 'current', but found %s",
+				types.FormatAnyValue(value),
+			))
+		}
+
+		return bound{offset: float64(offset)}, nil
+	}
+
+	if !aggregations.IsNumber(value) {
+		return bound{}, newParseError(fmt.Sprintf(
+			"Range-based bounds expression must be a number, 'unbounded' or 'current', but found %s",
+			types.FormatAnyValue(value),
+		))
+	}
+
+	return bound{offset: aggregations.NumberToFloat64(value)}, nil
+}
+
+// parseUnit parses the time unit.
+func parseUnit(value any) (string, error) {
+	unit, ok := value.(string)
+	if !ok {
+		return "", newParseError(fmt.Sprintf("'unit' must be a string, but found %s", types.FormatAnyValue(value)))
+	}
+
+	_, fixed := unitMillis[unit]
+	_, variable := unitMonths[unit]
+
+	if !fixed && !variable {
+		return "", newParseError(fmt.Sprintf("unknown time unit value: %s", unit))
+	}
+
+	return unit, nil
+}
+
+// resolve returns the window of each document of the partition.
+// Nil bounds represent the whole partition.
+func (b *bounds) resolve(partition []*types.Document) ([]window, error) {
+	res := make([]window, len(partition))
+
+	if b == nil {
+		for i := range res {
+			res[i] = window{first: 0, last: len(partition) - 1}
+		}
+
+		return res, nil
+	}
+
+	if b.rangeKey == nil {
+		for i := range res {
+			w := window{first: 0, last: len(partition) - 1}
+
+			if !b.lower.unbounded {
+				w.first = max(i+int(b.lower.offset), 0)
+			}
+
+			if !b.upper.unbounded {
+				w.last = min(i+int(b.upper.offset), len(partition)-1)
+			}
+
+			res[i] = w
+		}
+
+		return res, nil
+	}
+
+	values := make([]float64, len(partition))
+
+	for i, doc := range partition {
+		v, err := b.rangeValue(doc)
+		if err != nil {
+			return nil, err
+		}
+
+		values[i] = v
+	}
+
+	for i, v := range values {
+		lower, upper := math.Inf(-1), math.Inf(1)
+
+		if !b.lower.unbounded {
+			lower = b.add(v, b.lower.offset)
+		}
+
+		if !b.upper.unbounded {
+			upper = b.add(v, b.upper.offset)
+		}
+
+		// documents are sorted by the range key, so the window is contiguous
+		w := window{first: 0, last: -1}
+		found := false
+
+		for j, other := range values {
+			if other < lower || other > upper {
+				continue
+			}
+
+			if !found {
+				w.first = j
+				found = true
+			}
+
+			w.last = j
+		}
+
+		res[i] = w
+	}
+
+	return res, nil
+}
+
+// rangeValue returns the value of the range key of the document
+// as a number, or as milliseconds since epoch for dates.
+func (b *bounds) rangeValue(doc *types.Document) (float64, error) {
+	v := sortValue(doc, *b.rangeKey)
+
+	if b.unit == "" {
+		if !aggregations.IsNumber(v) {
+			return 0, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrBadValue,
+				fmt.Sprintf(
+					"Invalid range: Expected the sortBy field to be a number, but it was %s",
+					handlerparams.AliasFromType(v),
+				),
+				"$setWindowFields (stage)",
+			)
+		}
+
+		return aggregations.NumberToFloat64(v), nil
+	}
+
+	t, ok := v.(time.Time)
+	if !ok {
+		return 0, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			fmt.Sprintf(
+				"Invalid range: Expected the sortBy field to be a Date, but it was %s",
+				handlerparams.AliasFromType(v),
+			),
+			"$setWindowFields (stage)",
+		)
+	}
+
+	return float64(t.UnixMilli()), nil
+}
+
+// add returns the value of the range key shifted by the offset in units of the window.
+func (b *bounds) add(v, offset float64) float64 {
+	if months, ok := unitMonths[b.unit]; ok {
+		t := time.UnixMilli(int64(v)).UTC()
+		return float64(t.AddDate(0, months*int(offset), 0).UnixMilli())
+	}
+
+	if ms, ok := unitMillis[b.unit]; ok {
+		return v + offset*ms
+	}
+
+	return v + offset
+}
@@ -0,0 +1,211 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package windows
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations/operators"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+// calculus represents $derivative and $integral window operators.
+//
+//	{ $derivative: { input: <expression>, unit: <time unit> } }
+//	{ $integral: { input: <expression>, unit: <time unit> } }
+//
+// The sortBy field is used as x-axis; it must be a number, or a date if unit is specified.
+type calculus struct {
+	name     string
+	input    any
+	key      sortKey
+	unitMs   float64 // zero if unit is not specified
+	window   *bounds
+	integral bool
+}
+
+// newDerivative creates a new $derivative window operator.
+func newDerivative(arg any, sortBy []sortKey, window *bounds) (Operator, error) {
+	if window == nil {
+		return nil, newParseError("$derivative requires explicit window bounds")
+	}
+
+	return newCalculus("$derivative", arg, sortBy, window, false)
+}
+
+// newIntegral creates a new $integral window operator.
+func newIntegral(arg any, sortBy []sortKey, window *bounds) (Operator, error) {
+	return newCalculus("$integral", arg, sortBy, window, true)
+}
+
+// newCalculus parses arguments of $derivative and $integral window operators.
+func newCalculus(name string, arg any, sortBy []sortKey, window *bounds, integral bool) (Operator, error) {
+	doc, ok := arg.(*types.Document)
+	if !ok {
+		return nil, newParseError(name + " only accepts an object as its argument")
+	}
+
+	for _, k := range doc.Keys() {
+		if k != "input" && k != "unit" {
+			return nil, newParseError(fmt.Sprintf("%s got unexpected argument: %s", name, k))
+		}
+	}
+
+	input, err := doc.Get("input")
+	if err != nil {
+		return nil, newParseError(name + " requires an 'input' expression")
+	}
+
+	if err = operators.ValidateExpression(input); err != nil {
+		return nil, err
+	}
+
+	if err = requireSingleSortKey(name, sortBy); err != nil {
+		return nil, err
+	}
+
+	c := &calculus{
+		name:     name,
+		input:    input,
+		key:      sortBy[0],
+		window:   window,
+		integral: integral,
+	}
+
+	if unitValue, _ := doc.Get("unit"); unitValue != nil {
+		unit, err := parseUnit(unitValue)
+		if err != nil {
+			return nil, err
+		}
+
+		var fixed bool
+		if c.unitMs, fixed = unitMillis[unit]; !fixed {
+			return nil, newParseError(fmt.Sprintf("%s 'unit' must be 'week' or smaller, but found %s", name, unit))
+		}
+	}
+
+	return c, nil
+}
+
+// Apply implements Operator interface.
+//
+// Non-numeric input values are ignored.
+func (c *calculus) Apply(partition []*types.Document, vars *aggregations.Variables) ([]any, error) {
+	windows, err := c.window.resolve(partition)
+	if err != nil {
+		return nil, err
+	}
+
+	xs := make([]float64, len(partition))
+	ys := make([]any, len(partition))
+
+	for i, doc := range partition {
+		if xs[i], err = c.x(doc); err != nil {
+			return nil, err
+		}
+
+		if ys[i], err = operators.Evaluate(c.input, doc, vars); err != nil {
+			return nil, err
+		}
+	}
+
+	res := make([]any, len(partition))
+
+	for i, w := range windows {
+		if c.integral {
+			res[i] = integrate(xs, ys, w)
+			continue
+		}
+
+		res[i] = types.Null
+
+		if w.last <= w.first || !aggregations.IsNumber(ys[w.first]) || !aggregations.IsNumber(ys[w.last]) {
+			continue
+		}
+
+		dx := xs[w.last] - xs[w.first]
+		if dx == 0 {
+			continue
+		}
+
+		res[i] = (aggregations.NumberToFloat64(ys[w.last]) - aggregations.NumberToFloat64(ys[w.first])) / dx
+	}
+
+	return res, nil
+}
+
+// x returns the value of the sortBy field of the document, in units for dates.
+func (c *calculus) x(doc *types.Document) (float64, error) {
+	v := sortValue(doc, c.key)
+
+	if c.unitMs == 0 {
+		if !aggregations.IsNumber(v) {
+			return 0, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrBadValue,
+				fmt.Sprintf("%s (with no 'unit') expects the sortBy field to be numeric", c.name),
+				"$setWindowFields (stage)",
+			)
+		}
+
+		return aggregations.NumberToFloat64(v), nil
+	}
+
+	t, ok := v.(time.Time)
+	if !ok {
+		return 0, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			fmt.Sprintf("%s with 'unit' expects the sortBy field to be a Date", c.name),
+			"$setWindowFields (stage)",
+		)
+	}
+
+	return float64(t.UnixMilli()) / c.unitMs, nil
+}
+
+// integrate returns the approximate integral of the window using the trapezoidal rule.
+// It returns null for an empty window.
+func integrate(xs []float64, ys []any, w window) any {
+	if w.last < w.first {
+		return types.Null
+	}
+
+	var res float64
+
+	prev := -1
+
+	for j := w.first; j <= w.last; j++ {
+		if !aggregations.IsNumber(ys[j]) {
+			continue
+		}
+
+		if prev >= 0 {
+			y0 := aggregations.NumberToFloat64(ys[prev])
+			y1 := aggregations.NumberToFloat64(ys[j])
+			res += (xs[j] - xs[prev]) * (y0 + y1) / 2
+		}
+
+		prev = j
+	}
+
+	return res
+}
+
+// check interfaces
+var (
+	_ Operator = (*calculus)(nil)
+)
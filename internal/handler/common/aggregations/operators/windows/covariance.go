@@ -0,0 +1,119 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package windows
+
+import (
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations/operators"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// covariance represents $covariancePop and $covarianceSamp window operators.
+//
+//	{ $covariancePop: [ <expression>, <expression> ] }
+type covariance struct {
+	x, y   any
+	window *bounds
+	sample bool
+}
+
+// newCovarianceFunc returns a function that creates $covariancePop or $covarianceSamp window operator.
+func newCovarianceFunc(name string, sample bool) newOperatorFunc {
+	return func(arg any, sortBy []sortKey, window *bounds) (Operator, error) {
+		arr, ok := arg.(*types.Array)
+		if !ok || arr.Len() != 2 {
+			return nil, newParseError(name + " requires an array of two expressions")
+		}
+
+		c := &covariance{
+			x:      must.NotFail(arr.Get(0)),
+			y:      must.NotFail(arr.Get(1)),
+			window: window,
+			sample: sample,
+		}
+
+		for _, expr := range []any{c.x, c.y} {
+			if err := operators.ValidateExpression(expr); err != nil {
+				return nil, err
+			}
+		}
+
+		return c, nil
+	}
+}
+
+// Apply implements Operator interface.
+//
+// Documents where any of the values is not a number are ignored.
+// Null is returned if there are no numeric pairs in the window,
+// or less than two of them for sample covariance.
+func (c *covariance) Apply(partition []*types.Document, vars *aggregations.Variables) ([]any, error) {
+	windows, err := c.window.resolve(partition)
+	if err != nil {
+		return nil, err
+	}
+
+	xs := make([]any, len(partition))
+	ys := make([]any, len(partition))
+
+	for i, doc := range partition {
+		if xs[i], err = operators.Evaluate(c.x, doc, vars); err != nil {
+			return nil, err
+		}
+
+		if ys[i], err = operators.Evaluate(c.y, doc, vars); err != nil {
+			return nil, err
+		}
+	}
+
+	res := make([]any, len(partition))
+
+	for i, w := range windows {
+		var n, meanX, meanY, c2 float64
+
+		// online algorithm similar to Welford's one for variance
+		for j := w.first; j <= w.last; j++ {
+			if !aggregations.IsNumber(xs[j]) || !aggregations.IsNumber(ys[j]) {
+				continue
+			}
+
+			x := aggregations.NumberToFloat64(xs[j])
+			y := aggregations.NumberToFloat64(ys[j])
+
+			n++
+			dx := x - meanX
+			meanX += dx / n
+			meanY += (y - meanY) / n
+			c2 += dx * (y - meanY)
+		}
+
+		switch {
+		case c.sample && n < 2, n == 0:
+			res[i] = types.Null
+		case c.sample:
+			res[i] = c2 / (n - 1)
+		default:
+			res[i] = c2 / n
+		}
+	}
+
+	return res, nil
+}
+
+// check interfaces
+var (
+	_ Operator = (*covariance)(nil)
+)
@@ -0,0 +1,142 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package windows
+
+import (
+	"fmt"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations/operators"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// expMovingAvg represents $expMovingAvg window operator.
+//
+//	{ $expMovingAvg: { input: <expression>, N: <integer> } }
+//	{ $expMovingAvg: { input: <expression>, alpha: <number> } }
+type expMovingAvg struct {
+	input any
+	alpha float64
+}
+
+// newExpMovingAvg creates a new $expMovingAvg window operator.
+func newExpMovingAvg(arg any, sortBy []sortKey, window *bounds) (Operator, error) {
+	const fieldsMsg = "$expMovingAvg sub object must have exactly two fields: " +
+		"An 'input' field, and either an 'N' field or an 'alpha' field"
+
+	doc, ok := arg.(*types.Document)
+	if !ok {
+		return nil, newParseError("$expMovingAvg must have exactly one of 'N' or 'alpha' fields")
+	}
+
+	for _, k := range doc.Keys() {
+		switch k {
+		case "input", "N", "alpha":
+		default:
+			return nil, newParseError("Got unrecognized field in $expMovingAvg, " + fieldsMsg)
+		}
+	}
+
+	if err := requireNoWindow("$expMovingAvg", window); err != nil {
+		return nil, err
+	}
+
+	if len(sortBy) == 0 {
+		return nil, newParseError("$expMovingAvg requires an explicit 'sortBy'")
+	}
+
+	input, err := doc.Get("input")
+	if err != nil || doc.Len() != 2 {
+		return nil, newParseError(fieldsMsg)
+	}
+
+	if err = operators.ValidateExpression(input); err != nil {
+		return nil, err
+	}
+
+	e := &expMovingAvg{
+		input: input,
+	}
+
+	if nValue, _ := doc.Get("N"); nValue != nil {
+		n, err := handlerparams.GetWholeNumberParam(nValue)
+		if err != nil {
+			return nil, newParseError(
+				fmt.Sprintf("'N' field must be an integer, but found type %s", handlerparams.AliasFromType(nValue)),
+			)
+		}
+
+		if n <= 0 {
+			return nil, newParseError(fmt.Sprintf("'N' must be greater than zero. Got %d", n))
+		}
+
+		e.alpha = 2 / (float64(n) + 1)
+
+		return e, nil
+	}
+
+	alpha := must.NotFail(doc.Get("alpha"))
+	if !aggregations.IsNumber(alpha) {
+		return nil, newParseError(
+			fmt.Sprintf("'alpha' must be a number, but found type %s", handlerparams.AliasFromType(alpha)),
+		)
+	}
+
+	e.alpha = aggregations.NumberToFloat64(alpha)
+	if e.alpha <= 0 || e.alpha >= 1 {
+		return nil, newParseError(
+			fmt.Sprintf("'alpha' must be between 0 and 1 (exclusive), found %s", types.FormatAnyValue(alpha)),
+		)
+	}
+
+	return e, nil
+}
+
+// Apply implements Operator interface.
+//
+// Non-numeric values are ignored; the current average is returned for their documents.
+func (e *expMovingAvg) Apply(partition []*types.Document, vars *aggregations.Variables) ([]any, error) {
+	res := make([]any, len(partition))
+
+	var avg any = types.Null
+
+	for i, doc := range partition {
+		v, err := operators.Evaluate(e.input, doc, vars)
+		if err != nil {
+			return nil, err
+		}
+
+		if aggregations.IsNumber(v) {
+			f := aggregations.NumberToFloat64(v)
+
+			if prev, ok := avg.(float64); ok {
+				f = f*e.alpha + prev*(1-e.alpha)
+			}
+
+			avg = f
+		}
+
+		res[i] = avg
+	}
+
+	return res, nil
+}
+
+// check interfaces
+var (
+	_ Operator = (*expMovingAvg)(nil)
+)
@@ -0,0 +1,175 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package windows
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations/operators"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+// locf represents $locf (last observation carried forward) window operator.
+type locf struct {
+	input any
+}
+
+// newLocf creates a new $locf window operator.
+func newLocf(arg any, _ []sortKey, window *bounds) (Operator, error) {
+	if err := requireNoWindow("$locf", window); err != nil {
+		return nil, err
+	}
+
+	if err := operators.ValidateExpression(arg); err != nil {
+		return nil, err
+	}
+
+	return &locf{
+		input: arg,
+	}, nil
+}
+
+// Apply implements Operator interface.
+//
+// Null and missing values are replaced by the last non-null value.
+func (l *locf) Apply(partition []*types.Document, vars *aggregations.Variables) ([]any, error) {
+	res := make([]any, len(partition))
+
+	var last any = types.Null
+
+	for i, doc := range partition {
+		v, err := operators.Evaluate(l.input, doc, vars)
+		if err != nil {
+			return nil, err
+		}
+
+		if !isNullish(v) {
+			last = v
+		}
+
+		res[i] = last
+	}
+
+	return res, nil
+}
+
+// linearFill represents $linearFill window operator.
+type linearFill struct {
+	input any
+	key   sortKey
+}
+
+// newLinearFill creates a new $linearFill window operator.
+func newLinearFill(arg any, sortBy []sortKey, window *bounds) (Operator, error) {
+	if err := requireNoWindow("$linearFill", window); err != nil {
+		return nil, err
+	}
+
+	if err := requireSingleSortKey("$linearFill", sortBy); err != nil {
+		return nil, err
+	}
+
+	if err := operators.ValidateExpression(arg); err != nil {
+		return nil, err
+	}
+
+	return &linearFill{
+		input: arg,
+		key:   sortBy[0],
+	}, nil
+}
+
+// Apply implements Operator interface.
+//
+// Null and missing values are linearly interpolated between surrounding numeric values
+// using the sortBy field, which must be a number or a date.
+// Values before the first and after the last numeric values stay null.
+func (l *linearFill) Apply(partition []*types.Document, vars *aggregations.Variables) ([]any, error) {
+	res := make([]any, len(partition))
+	xs := make([]float64, len(partition))
+
+	for i, doc := range partition {
+		v, err := operators.Evaluate(l.input, doc, vars)
+		if err != nil {
+			return nil, err
+		}
+
+		if isNullish(v) {
+			v = types.Null
+		} else if !aggregations.IsNumber(v) {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrTypeMismatch,
+				fmt.Sprintf("Expected the input to $linearFill to be a number, but found %s", handlerparams.AliasFromType(v)),
+				"$setWindowFields (stage)",
+			)
+		}
+
+		res[i] = v
+
+		switch x := sortValue(doc, l.key).(type) {
+		case time.Time:
+			xs[i] = float64(x.UnixMilli())
+		case float64, int32, int64, types.Decimal128:
+			xs[i] = aggregations.NumberToFloat64(x)
+		default:
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrTypeMismatch,
+				"Expected the sortBy field to be a number or a date for $linearFill",
+				"$setWindowFields (stage)",
+			)
+		}
+	}
+
+	prev := -1
+
+	for i, v := range res {
+		if isNullish(v) {
+			continue
+		}
+
+		if prev >= 0 && i-prev > 1 && xs[i] != xs[prev] {
+			y0 := aggregations.NumberToFloat64(res[prev])
+			y1 := aggregations.NumberToFloat64(v)
+
+			for j := prev + 1; j < i; j++ {
+				res[j] = y0 + (y1-y0)*(xs[j]-xs[prev])/(xs[i]-xs[prev])
+			}
+		}
+
+		prev = i
+	}
+
+	return res, nil
+}
+
+// isNullish returns true if the value is missing or null.
+func isNullish(v any) bool {
+	switch v.(type) {
+	case nil, types.NullType:
+		return true
+	default:
+		return false
+	}
+}
+
+// check interfaces
+var (
+	_ Operator = (*locf)(nil)
+	_ Operator = (*linearFill)(nil)
+)
@@ -0,0 +1,97 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package windows
+
+import (
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+// rankKind represents the kind of rank operator.
+type rankKind int
+
+const (
+	// rank is the rank of the document with gaps for ties.
+	rank rankKind = iota
+
+	// denseRank is the rank of the document without gaps for ties.
+	denseRank
+
+	// documentNumber is the position of the document in the partition.
+	documentNumber
+)
+
+// rankOperator represents $rank, $denseRank and $documentNumber window operators.
+type rankOperator struct {
+	key  sortKey
+	kind rankKind
+}
+
+// newRankFunc returns a function that creates $rank, $denseRank or $documentNumber window operator.
+func newRankFunc(name string, kind rankKind) newOperatorFunc {
+	return func(arg any, sortBy []sortKey, window *bounds) (Operator, error) {
+		if doc, ok := arg.(*types.Document); !ok || doc.Len() != 0 {
+			return nil, newParseError(name + " must be specified with '{}' as the value")
+		}
+
+		if err := requireNoWindow(name, window); err != nil {
+			return nil, err
+		}
+
+		if err := requireSingleSortKey(name, sortBy); err != nil {
+			return nil, err
+		}
+
+		return &rankOperator{
+			key:  sortBy[0],
+			kind: kind,
+		}, nil
+	}
+}
+
+// Apply implements Operator interface.
+func (r *rankOperator) Apply(partition []*types.Document, _ *aggregations.Variables) ([]any, error) {
+	res := make([]any, len(partition))
+
+	var prev any
+	var rankValue, denseValue int32
+
+	for i, doc := range partition {
+		v := sortValue(doc, r.key)
+
+		if i == 0 || types.CompareOrderForSort(v, prev, r.key.order) != types.Equal {
+			rankValue = int32(i + 1)
+			denseValue++
+		}
+
+		prev = v
+
+		switch r.kind {
+		case denseRank:
+			res[i] = denseValue
+		case documentNumber:
+			res[i] = int32(i + 1)
+		default:
+			res[i] = rankValue
+		}
+	}
+
+	return res, nil
+}
+
+// check interfaces
+var (
+	_ Operator = (*rankOperator)(nil)
+)
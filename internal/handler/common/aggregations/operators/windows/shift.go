@@ -0,0 +1,139 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package windows
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations/operators"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+// shift represents $shift window operator.
+//
+//	{ $shift: { output: <expression>, by: <integer>, default: <constant expression> } }
+type shift struct {
+	output       any
+	by           int64
+	defaultValue any
+}
+
+// newShift creates a new $shift window operator.
+func newShift(arg any, sortBy []sortKey, window *bounds) (Operator, error) {
+	doc, ok := arg.(*types.Document)
+	if !ok {
+		return nil, newParseError("Argument to $shift must be an object")
+	}
+
+	for _, k := range doc.Keys() {
+		switch k {
+		case "output", "by", "default":
+		default:
+			return nil, newParseError(fmt.Sprintf("$shift got unexpected argument: %s", k))
+		}
+	}
+
+	if err := requireNoWindow("$shift", window); err != nil {
+		return nil, err
+	}
+
+	if len(sortBy) == 0 {
+		return nil, newParseError("$shift requires a sortBy")
+	}
+
+	output, err := doc.Get("output")
+	if err != nil {
+		return nil, newParseError("$shift requires an 'output' expression.")
+	}
+
+	if err = operators.ValidateExpression(output); err != nil {
+		return nil, err
+	}
+
+	byValue, err := doc.Get("by")
+	if err != nil {
+		return nil, newParseError("$shift requires 'by' field")
+	}
+
+	by, err := handlerparams.GetWholeNumberParam(byValue)
+	if err != nil {
+		return nil, newParseError(
+			fmt.Sprintf("'$shift:by' field must be an integer, but found %s", types.FormatAnyValue(byValue)),
+		)
+	}
+
+	s := &shift{
+		output:       output,
+		by:           by,
+		defaultValue: types.Null,
+	}
+
+	if v, _ := doc.Get("default"); v != nil {
+		if path, ok := v.(string); ok && strings.HasPrefix(path, "$") {
+			return nil, newParseError("'$shift:default' expression must yield a constant value.")
+		}
+
+		if err = operators.ValidateExpression(v); err != nil {
+			return nil, err
+		}
+
+		if s.defaultValue, err = operators.Evaluate(v, new(types.Document), nil); err != nil {
+			return nil, err
+		}
+
+		if s.defaultValue == nil {
+			s.defaultValue = types.Null
+		}
+	}
+
+	return s, nil
+}
+
+// Apply implements Operator interface.
+//
+// The value of the document that is `by` positions away from the current one is returned,
+// or the default value if such document does not exist.
+func (s *shift) Apply(partition []*types.Document, vars *aggregations.Variables) ([]any, error) {
+	res := make([]any, len(partition))
+
+	for i := range partition {
+		j := int64(i) + s.by
+		if j < 0 || j >= int64(len(partition)) {
+			res[i] = s.defaultValue
+			continue
+		}
+
+		v, err := operators.Evaluate(s.output, partition[j], vars)
+		if err != nil {
+			return nil, err
+		}
+
+		if v == nil {
+			v = types.Null
+		}
+
+		res[i] = v
+	}
+
+	return res, nil
+}
+
+// check interfaces
+var (
+	_ Operator = (*shift)(nil)
+)
@@ -0,0 +1,206 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package windows provides window operators of `$setWindowFields` aggregation stage.
+//
+// Window operators compute the value of the output field for each document of the partition,
+// using other documents of the same partition sorted by `sortBy`.
+// All accumulators could be used as window operators;
+// they accumulate documents of the window specified by `window` field,
+// or all documents of the partition if the window is not specified.
+package windows
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations/operators/accumulators"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// newOperatorFunc is a type for a function that creates a window operator.
+// It takes the argument of the operator, sort keys of the stage and the window, which is nil if not specified.
+type newOperatorFunc func(arg any, sortBy []sortKey, window *bounds) (Operator, error)
+
+// Operator is a common interface for window operators.
+type Operator interface {
+	// Apply returns values of the operator for each document of the partition.
+	// Documents are sorted by `sortBy` of the stage.
+	// Expressions are evaluated with the given scope of variables.
+	Apply(partition []*types.Document, vars *aggregations.Variables) ([]any, error)
+}
+
+// sortKey represents a single field of `sortBy`.
+type sortKey struct {
+	path  types.Path
+	order types.SortType
+}
+
+// NewOperator returns window operator for the given output field specification.
+//
+// The sortBy is a validated `sortBy` document of the stage, or nil if not specified.
+func NewOperator(field string, spec any, sortBy *types.Document) (Operator, error) {
+	doc, ok := spec.(*types.Document)
+	if !ok {
+		return nil, newParseError(fmt.Sprintf("The field '%s' must be an object", field))
+	}
+
+	var name string
+	var arg, windowValue any
+
+	iter := doc.Iterator()
+	defer iter.Close()
+
+	for {
+		k, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		switch {
+		case strings.HasPrefix(k, "$"):
+			if name != "" {
+				return nil, newParseError("Cannot specify multiple functions in window function spec")
+			}
+
+			name, arg = k, v
+
+		case k == "window":
+			windowValue = v
+
+		default:
+			return nil, newParseError(fmt.Sprintf("Window function found an unknown argument: %s", k))
+		}
+	}
+
+	if name == "" {
+		return nil, newParseError("Expected a $-prefixed window function")
+	}
+
+	keys, err := newSortKeys(sortBy)
+	if err != nil {
+		return nil, err
+	}
+
+	var window *bounds
+
+	if windowValue != nil {
+		if window, err = newBounds(windowValue, keys); err != nil {
+			return nil, err
+		}
+	}
+
+	if newOperator, ok := Operators[name]; ok {
+		return newOperator(arg, keys, window)
+	}
+
+	if _, ok := accumulators.Accumulators[name]; ok {
+		return newWindowAccumulator(field, name, arg, window)
+	}
+
+	return nil, newParseError(fmt.Sprintf("Unrecognized window function, %s", name))
+}
+
+// newSortKeys returns sort keys for the given validated `sortBy` document.
+func newSortKeys(sortBy *types.Document) ([]sortKey, error) {
+	if sortBy == nil {
+		return nil, nil
+	}
+
+	keys := make([]sortKey, 0, sortBy.Len())
+
+	for _, k := range sortBy.Keys() {
+		order, err := common.GetSortType(k, must.NotFail(sortBy.Get(k)))
+		if err != nil {
+			return nil, err
+		}
+
+		path, err := types.NewPathFromString(k)
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		keys = append(keys, sortKey{path: path, order: order})
+	}
+
+	return keys, nil
+}
+
+// sortValue returns the value of the sort key of the document.
+// Missing value is returned as null, as sort order treats null and non-existent field equivalent.
+func sortValue(doc *types.Document, key sortKey) any {
+	v, err := doc.GetByPath(key.path)
+	if err != nil {
+		return types.Null
+	}
+
+	return v
+}
+
+// requireSingleSortKey returns an error if sort keys do not contain exactly one field.
+func requireSingleSortKey(name string, sortBy []sortKey) error {
+	if len(sortBy) != 1 {
+		return newParseError(
+			fmt.Sprintf("%s must be specified with a top level sortBy expression with exactly one element", name),
+		)
+	}
+
+	return nil
+}
+
+// requireNoWindow returns an error if the window is specified for the operator that does not accept it.
+func requireNoWindow(name string, window *bounds) error {
+	if window != nil {
+		return newParseError(fmt.Sprintf("%s does not accept a 'window' field", name))
+	}
+
+	return nil
+}
+
+// newParseError returns an error for invalid window operator specification.
+func newParseError(msg string) error {
+	return handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrFailedToParse,
+		msg,
+		"$setWindowFields (stage)",
+	)
+}
+
+// Operators maps all window operators that are not accumulators.
+var Operators = map[string]newOperatorFunc{
+	// sorted alphabetically
+	"$covariancePop":  newCovarianceFunc("$covariancePop", false),
+	"$covarianceSamp": newCovarianceFunc("$covarianceSamp", true),
+	"$denseRank":      newRankFunc("$denseRank", denseRank),
+	"$derivative":     newDerivative,
+	"$documentNumber": newRankFunc("$documentNumber", documentNumber),
+	"$expMovingAvg":   newExpMovingAvg,
+	"$integral":       newIntegral,
+	"$linearFill":     newLinearFill,
+	"$locf":           newLocf,
+	"$rank":           newRankFunc("$rank", rank),
+	"$shift":          newShift,
+	// please keep sorted alphabetically
+}
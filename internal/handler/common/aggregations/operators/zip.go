@@ -0,0 +1,210 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// zip represents `$zip` operator.
+type zip struct {
+	inputs           []any
+	defaults         []any // nil if not set
+	useLongestLength bool
+}
+
+// newZip returns `$zip` operator.
+func newZip(args ...any) (Operator, error) {
+	fields, err := namedArgs(
+		args,
+		[]string{"inputs", "useLongestLength", "defaults"},
+		func(v any) error {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrZipNotObject,
+				fmt.Sprintf("$zip only supports an object as an argument, found %s", typeAlias(v)),
+				"$zip (operator)",
+			)
+		},
+		func(field string) error {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrZipUnknownField,
+				fmt.Sprintf("$zip found an unknown argument: %s", field),
+				"$zip (operator)",
+			)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var z zip
+
+	if v := fields["inputs"]; v != nil {
+		arr, ok := v.(*types.Array)
+		if !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrZipInputsNotArray,
+				fmt.Sprintf("inputs must be an array of expressions, found %s", typeAlias(v)),
+				"$zip (operator)",
+			)
+		}
+
+		z.inputs = arrayValues(arr)
+	}
+
+	if v := fields["defaults"]; v != nil {
+		arr, ok := v.(*types.Array)
+		if !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrZipDefaultsNotArray,
+				fmt.Sprintf("defaults must be an array of expressions, found %s", typeAlias(v)),
+				"$zip (operator)",
+			)
+		}
+
+		z.defaults = arrayValues(arr)
+	}
+
+	if v := fields["useLongestLength"]; v != nil {
+		var ok bool
+		if z.useLongestLength, ok = v.(bool); !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrZipUseLongestLengthNotBool,
+				fmt.Sprintf("useLongestLength must be a bool, found %s", typeAlias(v)),
+				"$zip (operator)",
+			)
+		}
+	}
+
+	if len(z.inputs) == 0 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrZipMissingInputs,
+			"$zip requires at least one input array",
+			"$zip (operator)",
+		)
+	}
+
+	if z.defaults != nil && !z.useLongestLength {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrZipDefaultsWithoutUseLongestLength,
+			"cannot specify defaults unless useLongestLength is true",
+			"$zip (operator)",
+		)
+	}
+
+	if z.defaults != nil && len(z.defaults) != len(z.inputs) {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrZipDefaultsLen,
+			"inputs and defaults must be the same length",
+			"$zip (operator)",
+		)
+	}
+
+	return &z, nil
+}
+
+// Process implements Operator interface.
+//
+// It returns null if any input is null or missing.
+func (z *zip) Process(doc *types.Document, vars *aggregations.Variables) (any, error) {
+	inputs, err := evaluateArgs(z.inputs, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	arrays := make([]*types.Array, len(inputs))
+	length := -1
+
+	for i, v := range inputs {
+		if isNullish(v) {
+			return types.Null, nil
+		}
+
+		arr, ok := v.(*types.Array)
+		if !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrZipInputNotArray,
+				fmt.Sprintf("$zip found a non-array expression in input: %s", types.FormatAnyValue(v)),
+				"$zip (operator)",
+			)
+		}
+
+		arrays[i] = arr
+
+		switch {
+		case length < 0:
+			length = arr.Len()
+		case z.useLongestLength:
+			length = max(length, arr.Len())
+		default:
+			length = min(length, arr.Len())
+		}
+	}
+
+	defaults := make([]any, len(inputs))
+
+	if z.defaults != nil {
+		if defaults, err = evaluateArgs(z.defaults, doc, vars); err != nil {
+			return nil, err
+		}
+	}
+
+	res := types.MakeArray(length)
+
+	for i := 0; i < length; i++ {
+		elem := types.MakeArray(len(arrays))
+
+		for j, arr := range arrays {
+			var v any
+
+			if i < arr.Len() {
+				v = must.NotFail(arr.Get(i))
+			} else {
+				v = defaults[j]
+			}
+
+			if v == nil {
+				v = types.Null
+			}
+
+			elem.Append(v)
+		}
+
+		res.Append(elem)
+	}
+
+	return res, nil
+}
+
+// arrayValues returns the elements of the given array.
+func arrayValues(arr *types.Array) []any {
+	res := make([]any, arr.Len())
+
+	for i := range res {
+		res[i] = must.NotFail(arr.Get(i))
+	}
+
+	return res
+}
+
+// check interfaces
+var (
+	_ Operator = (*zip)(nil)
+)
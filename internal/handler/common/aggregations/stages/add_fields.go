@@ -34,7 +34,7 @@ type addFields struct {
 }
 
 // newAddFields validates stage document and creates a new $addFields stage.
-func newAddFields(stage *types.Document) (aggregations.Stage, error) {
+func newAddFields(stage *types.Document, _ *NewStageParams) (aggregations.Stage, error) {
 	fields, err := stage.Get("$addFields")
 	if err != nil {
 		return nil, lazyerrors.Error(err)
@@ -63,8 +63,8 @@ func newAddFields(stage *types.Document) (aggregations.Stage, error) {
 }
 
 // Process implements Stage interface.
-func (s *addFields) Process(_ context.Context, iter types.DocumentsIterator, closer *iterator.MultiCloser) (types.DocumentsIterator, error) { //nolint:lll // for readability
-	return common.AddFieldsIterator(iter, closer, s.newField), nil
+func (s *addFields) Process(ctx context.Context, iter types.DocumentsIterator, closer *iterator.MultiCloser) (types.DocumentsIterator, error) { //nolint:lll // for readability
+	return common.AddFieldsIterator(iter, closer, s.newField, aggregations.GetVariables(ctx)), nil
 }
 
 // check interfaces
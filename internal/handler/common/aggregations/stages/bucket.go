@@ -0,0 +1,364 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stages
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations/operators"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// bucket represents $bucket stage.
+//
+//	{ $bucket: {
+//		groupBy: <expression>,
+//		boundaries: [ <lowerbound0>, <lowerbound1>, ... ],
+//		default: <literal>,
+//		output: { <output0>: { <accumulator0>: <expression0> }, ... },
+//	}}
+//
+// It is processed as $group stage which _id is the lower boundary of the bucket
+// (evaluated with $switch operator), followed by $sort stage by that _id.
+type bucket struct {
+	group aggregations.Stage
+	sort  aggregations.Stage
+}
+
+// newBucket creates a new $bucket stage.
+func newBucket(stage *types.Document, params *NewStageParams) (aggregations.Stage, error) {
+	spec, ok := must.NotFail(stage.Get("$bucket")).(*types.Document)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBucketNotObject,
+			fmt.Sprintf(
+				"Argument to $bucket stage must be an object, but found type: %s.",
+				handlerparams.AliasFromType(must.NotFail(stage.Get("$bucket"))),
+			),
+			"$bucket (stage)",
+		)
+	}
+
+	var groupBy, defaultValue any
+	var boundaries *types.Array
+	var output *types.Document
+
+	iter := spec.Iterator()
+	defer iter.Close()
+
+	for {
+		k, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		switch k {
+		case "groupBy":
+			if !isBucketGroupBy(v) {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrBucketInvalidGroupBy,
+					fmt.Sprintf(
+						"The $bucket 'groupBy' field must be defined as a $-prefixed path or an expression, but found: %s.",
+						types.FormatAnyValue(v),
+					),
+					"$bucket (stage)",
+				)
+			}
+
+			groupBy = v
+
+		case "boundaries":
+			if boundaries, ok = v.(*types.Array); !ok {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrBucketBoundariesNotArray,
+					fmt.Sprintf(
+						"The $bucket 'boundaries' field must be an array, but found type: %s.",
+						handlerparams.AliasFromType(v),
+					),
+					"$bucket (stage)",
+				)
+			}
+
+		case "default":
+			if !isConstantValue(v) {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrBucketDefaultNotConstant,
+					fmt.Sprintf(
+						"The $bucket 'default' field must be a constant expression, but found: %s.",
+						types.FormatAnyValue(v),
+					),
+					"$bucket (stage)",
+				)
+			}
+
+			defaultValue = v
+
+		case "output":
+			if output, ok = v.(*types.Document); !ok {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrBucketOutputNotObject,
+					fmt.Sprintf(
+						"The $bucket 'output' field must be an object, but found type: %s.",
+						handlerparams.AliasFromType(v),
+					),
+					"$bucket (stage)",
+				)
+			}
+
+		default:
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrBucketUnknownOption,
+				fmt.Sprintf("Unrecognized option to $bucket: %s.", k),
+				"$bucket (stage)",
+			)
+		}
+	}
+
+	if groupBy == nil || boundaries == nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBucketMissingArgs,
+			"$bucket requires 'groupBy' and 'boundaries' to be specified.",
+			"$bucket (stage)",
+		)
+	}
+
+	if err := validateBucketBoundaries(boundaries, defaultValue); err != nil {
+		return nil, err
+	}
+
+	groupSpec := must.NotFail(types.NewDocument("_id", bucketSwitch(groupBy, boundaries, defaultValue)))
+
+	if output == nil {
+		output = must.NotFail(types.NewDocument("count", must.NotFail(types.NewDocument("$sum", int32(1)))))
+	}
+
+	for _, k := range output.Keys() {
+		if k == "_id" {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrStageGroupID,
+				"a group's _id may only be specified once",
+				"$bucket (stage)",
+			)
+		}
+
+		groupSpec.Set(k, must.NotFail(output.Get(k)))
+	}
+
+	groupStage, err := newGroup(must.NotFail(types.NewDocument("$group", groupSpec)), params)
+	if err != nil {
+		return nil, err
+	}
+
+	sortStage, err := newSort(must.NotFail(types.NewDocument(
+		"$sort", must.NotFail(types.NewDocument("_id", int32(1))),
+	)), params)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return &bucket{
+		group: groupStage,
+		sort:  sortStage,
+	}, nil
+}
+
+// Process implements Stage interface.
+func (b *bucket) Process(ctx context.Context, iter types.DocumentsIterator, closer *iterator.MultiCloser) (types.DocumentsIterator, error) { //nolint:lll // for readability
+	iter, err := b.group.Process(ctx, iter, closer)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.sort.Process(ctx, iter, closer)
+}
+
+// validateBucketBoundaries returns error if boundaries are not constant values of the same type
+// sorted in ascending order, or if the default value is within boundaries.
+func validateBucketBoundaries(boundaries *types.Array, defaultValue any) error {
+	if boundaries.Len() < 2 {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBucketBoundariesTooFew,
+			fmt.Sprintf(
+				"The $bucket 'boundaries' field must have at least 2 values, but found %d value(s).",
+				boundaries.Len(),
+			),
+			"$bucket (stage)",
+		)
+	}
+
+	for i := 0; i < boundaries.Len(); i++ {
+		v := must.NotFail(boundaries.Get(i))
+
+		if !isConstantValue(v) {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrBucketBoundariesNotConstant,
+				fmt.Sprintf(
+					"The $bucket 'boundaries' field must be an array of constant values, but found value: %s.",
+					types.FormatAnyValue(v),
+				),
+				"$bucket (stage)",
+			)
+		}
+
+		if i == 0 {
+			continue
+		}
+
+		prev := must.NotFail(boundaries.Get(i - 1))
+
+		if !sameCanonicalType(prev, v) {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrBucketBoundariesMixedTypes,
+				fmt.Sprintf(
+					"All values in the the 'boundaries' option to $bucket must have the same type. "+
+						"Found conflicting types %s and %s.",
+					handlerparams.AliasFromType(prev), handlerparams.AliasFromType(v),
+				),
+				"$bucket (stage)",
+			)
+		}
+
+		if types.CompareForAggregation(prev, v) != types.Less {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrBucketBoundariesNotSorted,
+				fmt.Sprintf(
+					"The 'boundaries' option to $bucket must be sorted, but elements %d and %d are not in "+
+						"ascending order (%s is not less than %s).",
+					i-1, i, types.FormatAnyValue(prev), types.FormatAnyValue(v),
+				),
+				"$bucket (stage)",
+			)
+		}
+	}
+
+	if defaultValue == nil {
+		return nil
+	}
+
+	lower := must.NotFail(boundaries.Get(0))
+	upper := must.NotFail(boundaries.Get(boundaries.Len() - 1))
+
+	// default value of another type is never within boundaries
+	if !sameCanonicalType(lower, defaultValue) {
+		return nil
+	}
+
+	if types.CompareForAggregation(defaultValue, lower) == types.Less ||
+		types.CompareForAggregation(defaultValue, upper) != types.Less {
+		return nil
+	}
+
+	return handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrBucketDefaultInRange,
+		"The $bucket 'default' field must be less than the lowest boundary or "+
+			"greater than or equal to the highest boundary.",
+		"$bucket (stage)",
+	)
+}
+
+// bucketSwitch returns $switch operator that evaluates groupBy expression
+// to the lower boundary of the matching bucket, or to the default value.
+func bucketSwitch(groupBy any, boundaries *types.Array, defaultValue any) *types.Document {
+	branches := types.MakeArray(boundaries.Len() - 1)
+
+	for i := 0; i < boundaries.Len()-1; i++ {
+		lower := must.NotFail(boundaries.Get(i))
+		upper := must.NotFail(boundaries.Get(i + 1))
+
+		branches.Append(must.NotFail(types.NewDocument(
+			"case", must.NotFail(types.NewDocument(
+				"$and", must.NotFail(types.NewArray(
+					must.NotFail(types.NewDocument("$gte", must.NotFail(types.NewArray(groupBy, lower)))),
+					must.NotFail(types.NewDocument("$lt", must.NotFail(types.NewArray(groupBy, upper)))),
+				)),
+			)),
+			"then", lower,
+		)))
+	}
+
+	spec := must.NotFail(types.NewDocument("branches", branches))
+
+	if defaultValue != nil {
+		spec.Set("default", defaultValue)
+	}
+
+	return must.NotFail(types.NewDocument("$switch", spec))
+}
+
+// isBucketGroupBy returns true if the value is a $-prefixed path or an expression object.
+func isBucketGroupBy(v any) bool {
+	switch v := v.(type) {
+	case string:
+		return strings.HasPrefix(v, "$")
+	case *types.Document:
+		return true
+	default:
+		return false
+	}
+}
+
+// isConstantValue returns true if the value does not contain path expressions or operators.
+func isConstantValue(v any) bool {
+	switch v := v.(type) {
+	case string:
+		return !strings.HasPrefix(v, "$")
+	case *types.Document:
+		if operators.IsOperator(v) {
+			return false
+		}
+
+		for _, k := range v.Keys() {
+			if !isConstantValue(must.NotFail(v.Get(k))) {
+				return false
+			}
+		}
+	case *types.Array:
+		for i := 0; i < v.Len(); i++ {
+			if !isConstantValue(must.NotFail(v.Get(i))) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// sameCanonicalType returns true if both values have the same BSON type,
+// considering all numbers to be of the same type.
+func sameCanonicalType(a, b any) bool {
+	if aggregations.IsNumber(a) && aggregations.IsNumber(b) {
+		return true
+	}
+
+	return handlerparams.AliasFromType(a) == handlerparams.AliasFromType(b)
+}
+
+// check interfaces
+var (
+	_ aggregations.Stage = (*bucket)(nil)
+)
@@ -0,0 +1,375 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stages
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations/operators"
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations/operators/accumulators"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// bucketAuto represents $bucketAuto stage.
+//
+//	{ $bucketAuto: {
+//		groupBy: <expression>,
+//		buckets: <number>,
+//		output: { <output0>: { <accumulator0>: <expression0> }, ... },
+//		granularity: <string>,
+//	}}
+//
+// $bucketAuto sorts documents by the evaluated groupBy expression
+// and distributes them evenly into the given number of buckets.
+// Documents with the same value are always placed into the same bucket,
+// so there could be fewer buckets.
+// For each bucket, accumulators are applied the same way as for $group stage.
+type bucketAuto struct {
+	groupBy     any
+	output      []groupBy
+	buckets     int
+	granularity *granularity
+	collation   *types.Collation
+}
+
+// newBucketAuto creates a new $bucketAuto stage.
+func newBucketAuto(stage *types.Document, params *NewStageParams) (aggregations.Stage, error) {
+	spec, ok := must.NotFail(stage.Get("$bucketAuto")).(*types.Document)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBucketAutoNotObject,
+			fmt.Sprintf(
+				"The argument to $bucketAuto must be an object, but found type: %s.",
+				handlerparams.AliasFromType(must.NotFail(stage.Get("$bucketAuto"))),
+			),
+			"$bucketAuto (stage)",
+		)
+	}
+
+	b := &bucketAuto{
+		collation: params.Collation,
+	}
+
+	var output *types.Document
+
+	iter := spec.Iterator()
+	defer iter.Close()
+
+	for {
+		k, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		switch k {
+		case "groupBy":
+			if !isBucketGroupBy(v) {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrBucketAutoInvalidGroupBy,
+					fmt.Sprintf(
+						"The $bucketAuto 'groupBy' field must be defined as a $-prefixed path or an expression object, "+
+							"but found: %s.",
+						types.FormatAnyValue(v),
+					),
+					"$bucketAuto (stage)",
+				)
+			}
+
+			if err = operators.ValidateExpression(v); err != nil {
+				return nil, processStageOperatorError("$bucketAuto", err)
+			}
+
+			b.groupBy = v
+
+		case "buckets":
+			if b.buckets, err = getBucketAutoBuckets(v); err != nil {
+				return nil, err
+			}
+
+		case "output":
+			if output, ok = v.(*types.Document); !ok {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrBucketAutoOutputNotObject,
+					fmt.Sprintf(
+						"The $bucketAuto 'output' field must be an object, but found type: %s.",
+						handlerparams.AliasFromType(v),
+					),
+					"$bucketAuto (stage)",
+				)
+			}
+
+		case "granularity":
+			name, ok := v.(string)
+			if !ok {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrBucketAutoGranularityNotString,
+					fmt.Sprintf(
+						"The $bucketAuto 'granularity' field must be a string, but found type: %s.",
+						handlerparams.AliasFromType(v),
+					),
+					"$bucketAuto (stage)",
+				)
+			}
+
+			if b.granularity, err = newGranularity(name); err != nil {
+				return nil, err
+			}
+
+		default:
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrBucketAutoUnknownOption,
+				fmt.Sprintf("Unrecognized option to $bucketAuto: %s.", k),
+				"$bucketAuto (stage)",
+			)
+		}
+	}
+
+	if b.groupBy == nil || b.buckets == 0 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBucketAutoMissingArgs,
+			"$bucketAuto requires 'groupBy' and 'buckets' to be specified",
+			"$bucketAuto (stage)",
+		)
+	}
+
+	if output == nil {
+		output = must.NotFail(types.NewDocument("count", must.NotFail(types.NewDocument("$sum", int32(1)))))
+	}
+
+	for _, field := range output.Keys() {
+		if field == "_id" {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrStageGroupID,
+				"a group's _id may only be specified once",
+				"$bucketAuto (stage)",
+			)
+		}
+
+		accumulator, err := accumulators.NewAccumulator("$bucketAuto", field, must.NotFail(output.Get(field)))
+		if err != nil {
+			return nil, processStageOperatorError("$bucketAuto", err)
+		}
+
+		b.output = append(b.output, groupBy{
+			outputField: field,
+			accumulator: accumulator,
+		})
+	}
+
+	return b, nil
+}
+
+// getBucketAutoBuckets returns the validated number of buckets.
+func getBucketAutoBuckets(v any) (int, error) {
+	if !aggregations.IsNumber(v) {
+		return 0, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBucketAutoBucketsNotNumber,
+			fmt.Sprintf(
+				"The $bucketAuto 'buckets' field must be a numeric value, but found type: %s.",
+				handlerparams.AliasFromType(v),
+			),
+			"$bucketAuto (stage)",
+		)
+	}
+
+	n, err := handlerparams.GetWholeNumberParam(aggregations.NumberToFloat64(v))
+	if err != nil || n > math.MaxInt32 || n < math.MinInt32 {
+		return 0, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBucketAutoBucketsNotInt32,
+			fmt.Sprintf(
+				"The $bucketAuto 'buckets' field must be representable as a 32-bit integer, but found %s.",
+				types.FormatAnyValue(v),
+			),
+			"$bucketAuto (stage)",
+		)
+	}
+
+	if n <= 0 {
+		return 0, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBucketAutoBucketsNotPositive,
+			fmt.Sprintf("The $bucketAuto 'buckets' field must be greater than 0, but found: %d.", n),
+			"$bucketAuto (stage)",
+		)
+	}
+
+	return int(n), nil
+}
+
+// bucketAutoEntry contains the document and its evaluated groupBy expression.
+type bucketAutoEntry struct {
+	value any
+	doc   *types.Document
+}
+
+// autoBucket contains bucket boundaries and the accumulations of its documents.
+type autoBucket struct {
+	min, max      any
+	accumulations []accumulators.Accumulation
+}
+
+// Process implements Stage interface.
+func (b *bucketAuto) Process(ctx context.Context, iter types.DocumentsIterator, closer *iterator.MultiCloser) (types.DocumentsIterator, error) { //nolint:lll // for readability
+	docs, err := iterator.ConsumeValues(iter)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	vars := aggregations.GetVariables(ctx)
+
+	entries := make([]bucketAutoEntry, len(docs))
+
+	for i, doc := range docs {
+		v, err := operators.Evaluate(b.groupBy, doc, vars)
+		if err != nil {
+			return nil, processStageOperatorError("$bucketAuto", err)
+		}
+
+		if v == nil {
+			// missing values are treated as nulls
+			v = types.Null
+		}
+
+		if b.granularity != nil {
+			if err = b.granularity.validate(v); err != nil {
+				return nil, err
+			}
+		}
+
+		entries[i] = bucketAutoEntry{value: v, doc: doc}
+	}
+
+	slices.SortStableFunc(entries, func(a, c bucketAutoEntry) int {
+		return int(b.compare(a.value, c.value))
+	})
+
+	buckets, err := b.populateBuckets(entries, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*types.Document, len(buckets))
+
+	for i, bucket := range buckets {
+		doc := must.NotFail(types.NewDocument(
+			"_id", must.NotFail(types.NewDocument("min", bucket.min, "max", bucket.max)),
+		))
+
+		for j, accumulation := range b.output {
+			doc.Set(accumulation.outputField, bucket.accumulations[j].Result())
+		}
+
+		res[i] = doc
+	}
+
+	iter = iterator.Values(iterator.ForSlice(res))
+	closer.Add(iter)
+
+	return iter, nil
+}
+
+// populateBuckets distributes sorted entries into buckets.
+// Accumulator expressions are evaluated with the given scope of variables.
+func (b *bucketAuto) populateBuckets(entries []bucketAutoEntry, vars *aggregations.Variables) ([]*autoBucket, error) {
+	size := int(math.Round(float64(len(entries)) / float64(b.buckets)))
+	if size < 1 {
+		size = 1
+	}
+
+	var res []*autoBucket
+
+	memory := accumulators.NewMemoryUsage("$bucketAuto")
+
+	var i int
+
+	for i < len(entries) {
+		bucket := &autoBucket{
+			min:           entries[i].value,
+			accumulations: make([]accumulators.Accumulation, len(b.output)),
+		}
+
+		for j, accumulation := range b.output {
+			bucket.accumulations[j] = accumulation.accumulator.NewAccumulation(memory)
+		}
+
+		end := i + size
+		if len(res) == b.buckets-1 || end > len(entries) {
+			// the last bucket contains all remaining entries
+			end = len(entries)
+		}
+
+		// entries with the same value as the last one are placed in the same bucket
+		for end < len(entries) && b.compare(entries[end].value, entries[end-1].value) == types.Equal {
+			end++
+		}
+
+		bucket.max = entries[end-1].value
+
+		if b.granularity != nil {
+			bucket.max = b.granularity.roundUp(bucket.max)
+
+			// the rounded maximum is exclusive, so entries less than it are placed in the same bucket
+			for end < len(entries) && b.compare(entries[end].value, bucket.max) == types.Less {
+				end++
+			}
+		} else if end < len(entries) {
+			// the maximum is exclusive, and equal to the minimum of the next bucket
+			bucket.max = entries[end].value
+		}
+
+		for ; i < end; i++ {
+			for _, accumulation := range bucket.accumulations {
+				if err := accumulation.Add(entries[i].doc, vars); err != nil {
+					return nil, processStageOperatorError("$bucketAuto", err)
+				}
+			}
+		}
+
+		res = append(res, bucket)
+	}
+
+	if b.granularity != nil && len(res) > 0 {
+		res[0].min = b.granularity.roundDown(res[0].min)
+
+		for j := 1; j < len(res); j++ {
+			res[j].min = res[j-1].max
+		}
+	}
+
+	return res, nil
+}
+
+// compare compares values in the ascending sort order using stage's collation.
+func (b *bucketAuto) compare(a, c any) types.CompareResult {
+	return b.collation.CompareOrderForSort(a, c, types.Ascending)
+}
+
+// check interfaces
+var (
+	_ aggregations.Stage = (*bucketAuto)(nil)
+)
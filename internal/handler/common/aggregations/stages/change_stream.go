@@ -0,0 +1,460 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stages
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// OpLog database and collection names used by $changeStream.
+const (
+	oplogDatabase   = "local"
+	oplogCollection = "oplog.rs"
+)
+
+// changeStream represents $changeStream stage.
+//
+//	{ $changeStream: {
+//		allChangesForCluster: <boolean>,
+//		fullDocument: <string>,
+//		resumeAfter: <resume token>,
+//		startAfter: <resume token>,
+//		startAtOperationTime: <timestamp>,
+//	}}
+//
+// It ignores input documents, reads the OpLog collection instead,
+// and returns change events for OpLog entries that were not returned yet.
+// That allows the same stage to be processed again for each getMore of a tailable cursor.
+type changeStream struct {
+	b            backends.Backend
+	dbName       string          // empty for the whole cluster
+	cName        string          // empty for the whole database
+	fullDocument string          // "default" or "updateLookup"
+	lastTS       types.Timestamp // the timestamp of the last seen OpLog entry
+}
+
+// newChangeStream creates a new $changeStream stage.
+func newChangeStream(stage *types.Document, params *NewStageParams) (aggregations.Stage, error) {
+	spec, ok := must.NotFail(stage.Get("$changeStream")).(*types.Document)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrTypeMismatch,
+			fmt.Sprintf(
+				"BSON field '$changeStream' is the wrong type '%s', expected type 'object'",
+				handlerparams.AliasFromType(must.NotFail(stage.Get("$changeStream"))),
+			),
+			"$changeStream (stage)",
+		)
+	}
+
+	cs := &changeStream{
+		b:            params.Backend,
+		dbName:       params.DBName,
+		cName:        params.CollectionName,
+		fullDocument: "default",
+		lastTS:       types.NextTimestamp(time.Now()),
+	}
+
+	var allChangesForCluster bool
+	var resumeOptions []string
+
+	iter := spec.Iterator()
+	defer iter.Close()
+
+	for {
+		k, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		switch k {
+		case "allChangesForCluster":
+			if allChangesForCluster, ok = v.(bool); !ok {
+				return nil, changeStreamTypeError(k, v, "bool")
+			}
+
+		case "fullDocument":
+			if cs.fullDocument, ok = v.(string); !ok {
+				return nil, changeStreamTypeError(k, v, "string")
+			}
+
+			switch cs.fullDocument {
+			case "default", "updateLookup":
+			case "required", "whenAvailable":
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrNotImplemented,
+					fmt.Sprintf("$changeStream fullDocument '%s' is not implemented yet", cs.fullDocument),
+					"$changeStream (stage)",
+				)
+			default:
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrBadValue,
+					fmt.Sprintf("'%s' is not a valid value for the $changeStream fullDocument option", cs.fullDocument),
+					"$changeStream (stage)",
+				)
+			}
+
+		case "resumeAfter", "startAfter":
+			if cs.lastTS, err = parseResumeToken(v); err != nil {
+				return nil, err
+			}
+
+			resumeOptions = append(resumeOptions, k)
+
+		case "startAtOperationTime":
+			ts, ok := v.(types.Timestamp)
+			if !ok {
+				return nil, changeStreamTypeError(k, v, "timestamp")
+			}
+
+			// entries with the given timestamp should be returned
+			cs.lastTS = ts - 1
+
+			resumeOptions = append(resumeOptions, k)
+
+		case "showExpandedEvents":
+			// there are no expanded events yet
+
+		default:
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrFailedToParseInput,
+				fmt.Sprintf("BSON field '$changeStream.%s' is an unknown field.", k),
+				"$changeStream (stage)",
+			)
+		}
+	}
+
+	if len(resumeOptions) > 1 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			fmt.Sprintf("Only one type of resume option is allowed, but multiple were found: %s", strings.Join(resumeOptions, ", ")),
+			"$changeStream (stage)",
+		)
+	}
+
+	switch {
+	case allChangesForCluster:
+		if cs.dbName != "admin" || cs.cName != "" {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrInvalidNamespace,
+				"A $changeStream with 'allChangesForCluster:true' may only be opened "+
+					"on the 'admin' database, and with no collection name",
+				"$changeStream (stage)",
+			)
+		}
+
+		cs.dbName = ""
+
+	case isSystemDatabase(cs.dbName):
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrInvalidNamespace,
+			fmt.Sprintf("$changeStream may not be opened on the internal %s database", cs.dbName),
+			"$changeStream (stage)",
+		)
+	}
+
+	return cs, nil
+}
+
+// changeStreamTypeError returns an error for the $changeStream option of the wrong type.
+func changeStreamTypeError(field string, v any, expected string) error {
+	return handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrTypeMismatch,
+		fmt.Sprintf(
+			"BSON field '$changeStream.%s' is the wrong type '%s', expected type '%s'",
+			field, handlerparams.AliasFromType(v), expected,
+		),
+		"$changeStream (stage)",
+	)
+}
+
+// isSystemDatabase returns true if changes of the given database are not reported by change streams.
+func isSystemDatabase(dbName string) bool {
+	switch dbName {
+	case "admin", "config", oplogDatabase:
+		return true
+	default:
+		return false
+	}
+}
+
+// resumeToken returns the resume token of the change event for the OpLog entry with the given timestamp.
+//
+// Unlike MongoDB, the token contains only the hex-encoded timestamp.
+func resumeToken(ts types.Timestamp) *types.Document {
+	b := binary.BigEndian.AppendUint64(nil, uint64(ts))
+	return must.NotFail(types.NewDocument("_data", strings.ToUpper(hex.EncodeToString(b))))
+}
+
+// parseResumeToken returns the timestamp of the given resume token.
+func parseResumeToken(v any) (types.Timestamp, error) {
+	invalid := handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrInvalidResumeToken,
+		fmt.Sprintf("Invalid resume token: %s", types.FormatAnyValue(v)),
+		"$changeStream (stage)",
+	)
+
+	token, ok := v.(*types.Document)
+	if !ok {
+		return 0, invalid
+	}
+
+	data, _ := token.Get("_data")
+
+	s, ok := data.(string)
+	if !ok {
+		return 0, invalid
+	}
+
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) != 8 {
+		return 0, invalid
+	}
+
+	return types.Timestamp(binary.BigEndian.Uint64(b)), nil
+}
+
+// Process implements Stage interface.
+//
+// Input documents are ignored.
+func (cs *changeStream) Process(ctx context.Context, iter types.DocumentsIterator, closer *iterator.MultiCloser) (types.DocumentsIterator, error) { //nolint:lll // for readability
+	entries, err := cs.oplogEntries(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	closer.Add(entries)
+
+	iter = iterator.ForFunc(func() (struct{}, *types.Document, error) {
+		for {
+			_, entry, err := entries.Next()
+			if err != nil {
+				return struct{}{}, nil, err
+			}
+
+			// the backend may not push down the filter, so check it again
+			ts, _ := must.NotFail(entry.Get("ts")).(types.Timestamp)
+			if ts <= cs.lastTS {
+				continue
+			}
+
+			cs.lastTS = ts
+
+			event, err := cs.changeEvent(ctx, entry)
+			if err != nil {
+				return struct{}{}, nil, err
+			}
+
+			if event != nil {
+				return struct{}{}, event, nil
+			}
+		}
+	})
+	closer.Add(iter)
+
+	return iter, nil
+}
+
+// oplogEntries returns an iterator of OpLog entries after the last seen one, sorted by their timestamps.
+func (cs *changeStream) oplogEntries(ctx context.Context) (types.DocumentsIterator, error) {
+	db, err := cs.b.Database(oplogDatabase)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	list, err := db.ListCollections(ctx, &backends.ListCollectionsParams{Name: oplogCollection})
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if len(list.Collections) == 0 || !list.Collections[0].Capped() {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrChangeStreamNotSupported,
+			"The $changeStream stage is only supported on replica sets",
+			"$changeStream (stage)",
+		)
+	}
+
+	c, err := db.Collection(oplogCollection)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	qp := &backends.QueryParams{
+		Filter: must.NotFail(types.NewDocument("ts", must.NotFail(types.NewDocument("$gt", cs.lastTS)))),
+		Sort:   must.NotFail(types.NewDocument("$natural", int64(1))),
+	}
+
+	if cs.dbName != "" && cs.cName != "" {
+		qp.Filter.Set("ns", cs.dbName+"."+cs.cName)
+	}
+
+	res, err := c.Query(ctx, qp)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return res.Iter, nil
+}
+
+// changeEvent returns the change event for the given OpLog entry,
+// or nil if the entry should not be reported.
+func (cs *changeStream) changeEvent(ctx context.Context, entry *types.Document) (*types.Document, error) {
+	ns, _ := must.NotFail(entry.Get("ns")).(string)
+
+	dbName, cName, ok := strings.Cut(ns, ".")
+	if !ok || isSystemDatabase(dbName) {
+		return nil, nil
+	}
+
+	if cs.dbName != "" && cs.dbName != dbName {
+		return nil, nil
+	}
+
+	if cs.cName != "" && cs.cName != cName {
+		return nil, nil
+	}
+
+	ts := must.NotFail(entry.Get("ts")).(types.Timestamp)
+	o := must.NotFail(entry.Get("o")).(*types.Document)
+
+	var operationType string
+	var documentKey, updateDescription *types.Document
+	var fullDocument any
+
+	switch op := must.NotFail(entry.Get("op")).(string); op {
+	case "i":
+		operationType = "insert"
+		fullDocument = o
+		documentKey = must.NotFail(types.NewDocument("_id", must.NotFail(o.Get("_id"))))
+
+	case "u":
+		operationType = "update"
+		documentKey = must.NotFail(entry.Get("o2")).(*types.Document)
+
+		// OpLog entries contain the whole updated document, not the actual changes,
+		// so updatedFields contains all fields and removedFields is always empty;
+		// see website/docs/configuration/oplog-support.md
+		updatedFields := must.NotFail(o.Get("$set")).(*types.Document).DeepCopy()
+		updatedFields.Remove("_id")
+
+		updateDescription = must.NotFail(types.NewDocument(
+			"updatedFields", updatedFields,
+			"removedFields", types.MakeArray(0),
+			"truncatedArrays", types.MakeArray(0),
+		))
+
+	case "d":
+		operationType = "delete"
+		documentKey = o
+
+	default:
+		return nil, lazyerrors.Errorf("unexpected OpLog operation %q", op)
+	}
+
+	if operationType == "update" && cs.fullDocument == "updateLookup" {
+		doc, err := cs.lookupDocument(ctx, dbName, cName, documentKey)
+		if err != nil {
+			return nil, err
+		}
+
+		fullDocument = types.Null
+		if doc != nil {
+			fullDocument = doc
+		}
+	}
+
+	event := must.NotFail(types.NewDocument(
+		"_id", resumeToken(ts),
+		"operationType", operationType,
+		"clusterTime", ts,
+		"wallTime", must.NotFail(entry.Get("wall")),
+	))
+
+	if fullDocument != nil {
+		event.Set("fullDocument", fullDocument)
+	}
+
+	event.Set("ns", must.NotFail(types.NewDocument("db", dbName, "coll", cName)))
+	event.Set("documentKey", documentKey)
+
+	if updateDescription != nil {
+		event.Set("updateDescription", updateDescription)
+	}
+
+	return event, nil
+}
+
+// lookupDocument returns the current version of the document with the given key,
+// or nil if it does not exist.
+func (cs *changeStream) lookupDocument(ctx context.Context, dbName, cName string, documentKey *types.Document) (*types.Document, error) { //nolint:lll // for readability
+	db, err := cs.b.Database(dbName)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	c, err := db.Collection(cName)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	res, err := c.Query(ctx, &backends.QueryParams{Filter: documentKey})
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	docs, err := iterator.ConsumeValues(res.Iter)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	for _, doc := range docs {
+		matches, err := common.FilterDocument(doc, documentKey)
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if matches {
+			return doc, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// check interfaces
+var (
+	_ aggregations.Stage = (*changeStream)(nil)
+)
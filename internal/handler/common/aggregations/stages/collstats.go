@@ -43,7 +43,7 @@ type storageStats struct {
 }
 
 // newCollStats creates a new $collStats stage.
-func newCollStats(stage *types.Document) (aggregations.Stage, error) {
+func newCollStats(stage *types.Document, _ *NewStageParams) (aggregations.Stage, error) {
 	fields, err := common.GetRequiredParam[*types.Document](stage, "$collStats")
 	if err != nil {
 		return nil, handlererrors.NewCommandErrorMsgWithArgument(
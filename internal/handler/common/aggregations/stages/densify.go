@@ -0,0 +1,556 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stages
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations/operators"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// densifyMaxDocuments is the maximum number of documents generated by `$densify`.
+const densifyMaxDocuments = 500_000
+
+// densify represents $densify stage.
+//
+//	{ $densify: {
+//		field: <fieldName>,
+//		partitionByFields: [ <field 1>, <field 2>, ... <field n> ],
+//		range: {
+//			step: <number>,
+//			unit: <time unit>,
+//			bounds: < "full" || "partition" || [ < lower bound >, < upper bound > ] >
+//		}
+//	}}
+//
+// $densify generates documents with field values lower, lower+step, lower+2*step, ...
+// that are missing in each partition. Documents without field (or with null) are returned as-is.
+// Upper bound is exclusive for explicit bounds and inclusive for "full" and "partition" bounds.
+// Documents are returned sorted by partition fields, then by field.
+type densify struct {
+	field             types.Path
+	partitionByFields []types.Path
+	step              any    // number
+	unit              string // empty for numeric range
+	bounds            string // "full" or "partition"; empty for explicit bounds
+	lower             any    // explicit lower bound
+	upper             any    // explicit upper bound
+}
+
+// newDensify validates stage document and creates a new $densify stage.
+func newDensify(stage *types.Document, params *NewStageParams) (aggregations.Stage, error) {
+	fields, err := common.GetRequiredParam[*types.Document](stage, "$densify")
+	if err != nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			fmt.Sprintf(
+				"the $densify stage specification must be an object, found %s",
+				handlerparams.AliasFromType(must.NotFail(stage.Get("$densify"))),
+			),
+			"$densify (stage)",
+		)
+	}
+
+	d := new(densify)
+
+	var field string
+	var rangeDoc *types.Document
+
+	iter := fields.Iterator()
+	defer iter.Close()
+
+	for {
+		k, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		switch k {
+		case "field":
+			var ok bool
+			if field, ok = v.(string); !ok {
+				return nil, newDensifyTypeError(k, "string", v)
+			}
+
+			if d.field, err = fieldPath("$densify", field); err != nil {
+				return nil, err
+			}
+
+		case "partitionByFields":
+			if d.partitionByFields, err = getPartitionByFields("$densify", v); err != nil {
+				return nil, err
+			}
+
+		case "range":
+			var ok bool
+			if rangeDoc, ok = v.(*types.Document); !ok {
+				return nil, newDensifyTypeError(k, "object", v)
+			}
+
+		default:
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrFailedToParseInput,
+				fmt.Sprintf("BSON field '$densify.%s' is an unknown field.", k),
+				"$densify (stage)",
+			)
+		}
+	}
+
+	if field == "" {
+		return nil, newDensifyMissingFieldError("field")
+	}
+
+	if rangeDoc == nil {
+		return nil, newDensifyMissingFieldError("range")
+	}
+
+	if err = d.parseRange(rangeDoc); err != nil {
+		return nil, err
+	}
+
+	// generated documents could not have both fields if one is a prefix of another
+	for _, p := range d.partitionByFields {
+		if isPathPrefix(p, d.field) || isPathPrefix(d.field, p) {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrBadValue,
+				fmt.Sprintf("Cannot densify field %s as it is a prefix of or equal to partition field %s", field, p.String()),
+				"$densify (stage)",
+			)
+		}
+	}
+
+	return d, nil
+}
+
+// parseRange validates `range` argument of $densify stage.
+func (d *densify) parseRange(rangeDoc *types.Document) error {
+	var bounds any
+
+	iter := rangeDoc.Iterator()
+	defer iter.Close()
+
+	for {
+		k, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		switch k {
+		case "step":
+			if !aggregations.IsNumber(v) {
+				return newDensifyTypeError("range.step", "number", v)
+			}
+
+			d.step = v
+
+		case "unit":
+			var ok bool
+			if d.unit, ok = v.(string); !ok {
+				return newDensifyTypeError("range.unit", "string", v)
+			}
+
+			if !operators.IsTimeUnit(d.unit) {
+				return handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrBadValue,
+					fmt.Sprintf("unknown time unit value: %s", d.unit),
+					"$densify (stage)",
+				)
+			}
+
+		case "bounds":
+			bounds = v
+
+		default:
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrFailedToParseInput,
+				fmt.Sprintf("BSON field '$densify.range.%s' is an unknown field.", k),
+				"$densify (stage)",
+			)
+		}
+	}
+
+	if d.step == nil {
+		return newDensifyMissingFieldError("range.step")
+	}
+
+	if bounds == nil {
+		return newDensifyMissingFieldError("range.bounds")
+	}
+
+	if types.Compare(d.step, int32(0)) != types.Greater {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			"The step parameter in a range statement must be a strictly positive numeric value",
+			"$densify (stage)",
+		)
+	}
+
+	if d.unit != "" {
+		step, ok := densifyStepToInt64(d.step)
+		if !ok {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrBadValue,
+				"The step parameter in a range statement must be a whole number when densifying a date range",
+				"$densify (stage)",
+			)
+		}
+
+		d.step = step
+	}
+
+	switch bounds := bounds.(type) {
+	case string:
+		if bounds != "full" && bounds != "partition" {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrBadValue,
+				"Bounds string must either be 'full' or 'partition'",
+				"$densify (stage)",
+			)
+		}
+
+		d.bounds = bounds
+
+	case *types.Array:
+		if bounds.Len() != 2 {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrBadValue,
+				"A bounding array must contain exactly two elements",
+				"$densify (stage)",
+			)
+		}
+
+		d.lower, d.upper = must.NotFail(bounds.Get(0)), must.NotFail(bounds.Get(1))
+
+		if !d.isRangeValue(d.lower) || !d.isRangeValue(d.upper) {
+			msg := "A bounding array must contain numeric values if unit is not specified"
+			if d.unit != "" {
+				msg = "A bounding array must contain dates if unit is specified"
+			}
+
+			return handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrBadValue, msg, "$densify (stage)")
+		}
+
+		if types.CompareOrder(d.lower, d.upper, types.Ascending) == types.Greater {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrBadValue,
+				"A bounding array must be an ascending array of either two dates or two numbers",
+				"$densify (stage)",
+			)
+		}
+
+	default:
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			"the bounds in a range statement must be the string 'full', 'partition', or an ascending array "+
+				"of two numbers or two dates",
+			"$densify (stage)",
+		)
+	}
+
+	return nil
+}
+
+// isPathPrefix returns true if prefix is equal to path or is its prefix.
+func isPathPrefix(prefix, path types.Path) bool {
+	if prefix.Len() > path.Len() {
+		return false
+	}
+
+	return slices.Equal(prefix.Slice(), path.Slice()[:prefix.Len()])
+}
+
+// densifyStepToInt64 returns the integral step value.
+func densifyStepToInt64(v any) (int64, bool) {
+	switch v := v.(type) {
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case float64:
+		i := float64ToInt64(v)
+		return i, float64(i) == v
+	case types.Decimal128:
+		i := float64ToInt64(v.Float64())
+		return i, v.IsInteger() && float64(i) == v.Float64()
+	default:
+		return 0, false
+	}
+}
+
+// isRangeValue returns true if the value could be densified:
+// a date if unit is set, or a number otherwise.
+func (d *densify) isRangeValue(v any) bool {
+	if d.unit != "" {
+		_, ok := v.(time.Time)
+		return ok
+	}
+
+	return aggregations.IsNumber(v)
+}
+
+// newDensifyTypeError returns an error for the field of $densify stage that has an unexpected type.
+func newDensifyTypeError(field, expected string, value any) error {
+	return handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrTypeMismatch,
+		fmt.Sprintf(
+			"BSON field '$densify.%s' is the wrong type '%s', expected type '%s'",
+			field, handlerparams.AliasFromType(value), expected,
+		),
+		"$densify (stage)",
+	)
+}
+
+// newDensifyMissingFieldError returns an error for the missing required field of $densify stage.
+func newDensifyMissingFieldError(field string) error {
+	return handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrMissingField,
+		fmt.Sprintf("BSON field '$densify.%s' is missing but a required field", field),
+		"$densify (stage)",
+	)
+}
+
+// Process implements Stage interface.
+func (d *densify) Process(ctx context.Context, iter types.DocumentsIterator, closer *iterator.MultiCloser) (types.DocumentsIterator, error) { //nolint:lll // for readability
+	docs, err := iterator.ConsumeValues(iter)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	var key partitionKeyFunc
+	if len(d.partitionByFields) > 0 {
+		key = partitionByFields(d.partitionByFields)
+	}
+
+	partitions, err := partitionDocuments(docs, key, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	sortBy := must.NotFail(types.NewDocument(d.field.String(), int32(1)))
+
+	for _, partition := range partitions {
+		if err = common.SortDocumentsWithCollation(partition, sortBy, nil); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+	}
+
+	lower, upper := d.lower, d.upper
+
+	if d.bounds == "full" {
+		if lower, upper, err = d.valuesRange(docs); err != nil {
+			return nil, err
+		}
+	}
+
+	res := make([]*types.Document, 0, len(docs))
+
+	var generated int
+
+	for _, partition := range partitions {
+		if d.bounds == "partition" {
+			if lower, upper, err = d.valuesRange(partition); err != nil {
+				return nil, err
+			}
+		}
+
+		if res, generated, err = d.densifyPartition(res, partition, lower, upper, generated); err != nil {
+			return nil, err
+		}
+	}
+
+	iter = iterator.Values(iterator.ForSlice(res))
+	closer.Add(iter)
+
+	return iter, nil
+}
+
+// valuesRange returns the minimal and maximal field values of the given documents.
+// It returns nils if there are no such values.
+func (d *densify) valuesRange(docs []*types.Document) (any, any, error) {
+	var lower, upper any
+
+	for _, doc := range docs {
+		v, err := d.value(doc)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if v == nil {
+			continue
+		}
+
+		if lower == nil || types.CompareOrder(v, lower, types.Ascending) == types.Less {
+			lower = v
+		}
+
+		if upper == nil || types.CompareOrder(v, upper, types.Ascending) == types.Greater {
+			upper = v
+		}
+	}
+
+	return lower, upper, nil
+}
+
+// value returns the field value of the given document, or nil if it is missing or null.
+func (d *densify) value(doc *types.Document) (any, error) {
+	v, err := doc.GetByPath(d.field)
+	if err != nil || v == types.Null {
+		return nil, nil
+	}
+
+	if d.isRangeValue(v) {
+		return v, nil
+	}
+
+	msg := "Densify field type must be numeric"
+	if d.unit != "" {
+		msg = "Densify field type must be a date if unit is specified"
+	}
+
+	return nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrTypeMismatch, msg, "$densify (stage)")
+}
+
+// densifyPartition appends documents of the sorted partition and generated documents to res.
+// It returns the updated res and the total number of generated documents.
+//
+// Explicit upper bound is exclusive, "full" and "partition" upper bounds are inclusive.
+func (d *densify) densifyPartition(res, partition []*types.Document, lower, upper any, generated int) ([]*types.Document, int, error) { //nolint:lll // for readability
+	next := lower
+	var n int64
+
+	// inRange returns true if the next value should be generated before the given value,
+	// or before the end of the range if it is nil.
+	inRange := func(before any) bool {
+		if next == nil {
+			return false
+		}
+
+		if before != nil && types.CompareOrder(next, before, types.Ascending) != types.Less {
+			return false
+		}
+
+		cmp := types.CompareOrder(next, upper, types.Ascending)
+		if d.bounds == "" {
+			return cmp == types.Less
+		}
+
+		return cmp != types.Greater
+	}
+
+	// advance sets next to the value after the current one, or to nil if it overflows
+	advance := func() {
+		n++
+
+		if d.unit == "" {
+			next = aggregations.SumNumbers(next, d.step)
+			return
+		}
+
+		t, ok := operators.AddTimeUnits(lower.(time.Time), d.unit, n*d.step.(int64))
+		if !ok {
+			next = nil
+			return
+		}
+
+		next = t
+	}
+
+	generate := func() error {
+		if generated++; generated > densifyMaxDocuments {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrExceededMemoryLimit,
+				fmt.Sprintf("Generated %d documents in $densify, which is over the limit of %d", generated, densifyMaxDocuments),
+				"$densify (stage)",
+			)
+		}
+
+		doc := types.MakeDocument(len(d.partitionByFields) + 1)
+
+		if err := doc.SetByPath(d.field, next); err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		for _, p := range d.partitionByFields {
+			v, err := partition[0].GetByPath(p)
+			if err != nil {
+				continue
+			}
+
+			if err = doc.SetByPath(p, v); err != nil {
+				return lazyerrors.Error(err)
+			}
+		}
+
+		res = append(res, doc)
+		advance()
+
+		return nil
+	}
+
+	for _, doc := range partition {
+		v, err := d.value(doc)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if v != nil {
+			for inRange(v) {
+				if err = generate(); err != nil {
+					return nil, 0, err
+				}
+			}
+
+			// the value is already present
+			if next != nil && types.CompareOrder(next, v, types.Ascending) == types.Equal {
+				advance()
+			}
+		}
+
+		res = append(res, doc)
+	}
+
+	for inRange(nil) {
+		if err := generate(); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return res, generated, nil
+}
+
+// check interfaces
+var (
+	_ aggregations.Stage = (*densify)(nil)
+)
@@ -0,0 +1,310 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stages
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/bson"
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// facetNotAllowedStages contains stages that can't be used in $facet sub-pipelines.
+var facetNotAllowedStages = map[string]struct{}{
+	"$changeStream": {},
+	"$collStats":    {},
+	"$facet":        {},
+	"$geoNear":      {},
+	"$indexStats":   {},
+	"$merge":        {},
+	"$out":          {},
+}
+
+// facet represents $facet stage.
+//
+//	{ $facet: {
+//		<output field 1>: [ <stage 1>, <stage 2>, ... ],
+//		<output field 2>: [ <stage 1>, <stage 2>, ... ],
+//		...
+//	}}
+type facet struct {
+	facets  []facetPipeline
+	maxSize int
+}
+
+// facetPipeline represents a single $facet sub-pipeline.
+type facetPipeline struct {
+	field  string
+	stages []aggregations.Stage
+}
+
+// newFacet validates stage document and creates a new $facet stage.
+func newFacet(stage *types.Document, params *NewStageParams) (aggregations.Stage, error) {
+	v := must.NotFail(stage.Get("$facet"))
+
+	fields, ok := v.(*types.Document)
+	if !ok || fields.Len() == 0 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrStageFacetInvalidSpec,
+			fmt.Sprintf("the $facet specification must be a non-empty object, but found: %s", handlerparams.AliasFromType(v)),
+			"$facet (stage)",
+		)
+	}
+
+	f := &facet{
+		facets:  make([]facetPipeline, 0, fields.Len()),
+		maxSize: params.MaxBsonObjectSizeBytes,
+	}
+
+	if f.maxSize == 0 {
+		f.maxSize = types.MaxDocumentLen
+	}
+
+	iter := fields.Iterator()
+	defer iter.Close()
+
+	for {
+		field, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if strings.HasPrefix(field, "$") {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrFieldPathInvalidName,
+				"FieldPath field names may not start with '$'. Consider using $getField or $setField.",
+				"$facet (stage)",
+			)
+		}
+
+		pipeline, ok := v.(*types.Array)
+		if !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrStageFacetArgNotArray,
+				fmt.Sprintf("arguments to $facet must be arrays, %s is type %s", field, handlerparams.AliasFromType(v)),
+				"$facet (stage)",
+			)
+		}
+
+		stages := make([]aggregations.Stage, pipeline.Len())
+
+		for i := 0; i < pipeline.Len(); i++ {
+			v := must.NotFail(pipeline.Get(i))
+
+			d, ok := v.(*types.Document)
+			if !ok {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrStageFacetInvalidStage,
+					fmt.Sprintf(
+						"elements of arrays in $facet spec must be non-empty objects, %s argument contained an element of type %s",
+						field, handlerparams.AliasFromType(v),
+					),
+					"$facet (stage)",
+				)
+			}
+
+			if _, notAllowed := facetNotAllowedStages[d.Command()]; notAllowed {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrStageFacetNotAllowed,
+					fmt.Sprintf("%s is not allowed to be used within a $facet stage", d.Command()),
+					"$facet (stage)",
+				)
+			}
+
+			if stages[i], err = newPipelineStage(d, params); err != nil {
+				return nil, err
+			}
+		}
+
+		f.facets = append(f.facets, facetPipeline{
+			field:  field,
+			stages: stages,
+		})
+	}
+
+	return f, nil
+}
+
+// Process implements Stage interface.
+//
+// It buffers all input documents and runs all sub-pipelines on them in parallel.
+// If the context contains a backend transaction, sub-pipelines are run one after another instead,
+// because backend transactions (like pgx.Tx) can't be used concurrently.
+// The result is a single document with a field for each sub-pipeline.
+func (f *facet) Process(ctx context.Context, iter types.DocumentsIterator, closer *iterator.MultiCloser) (types.DocumentsIterator, error) { //nolint:lll // for readability
+	docs, err := iterator.ConsumeValues(iter)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	// the first error cancels other sub-pipelines
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([][]*types.Document, len(f.facets))
+
+	if backends.GetTransaction(ctx) != nil {
+		for i, fp := range f.facets {
+			if results[i], err = fp.run(ctx, docs); err != nil {
+				return nil, err
+			}
+		}
+
+		return f.result(results, closer)
+	}
+
+	var wg sync.WaitGroup
+	var errOnce sync.Once
+	var firstErr error
+
+	for i, fp := range f.facets {
+		wg.Add(1)
+
+		go func(i int, fp facetPipeline) {
+			defer wg.Done()
+
+			var err error
+			if results[i], err = fp.run(ctx, docs); err != nil {
+				errOnce.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+		}(i, fp)
+	}
+
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	return f.result(results, closer)
+}
+
+// result returns an iterator for a single document with the given results of sub-pipelines.
+func (f *facet) result(results [][]*types.Document, closer *iterator.MultiCloser) (types.DocumentsIterator, error) {
+	res := types.MakeDocument(len(f.facets))
+
+	for i, fp := range f.facets {
+		arr := types.MakeArray(len(results[i]))
+		for _, d := range results[i] {
+			arr.Append(d)
+		}
+
+		res.Set(fp.field, arr)
+	}
+
+	if err := f.checkSize(res); err != nil {
+		return nil, err
+	}
+
+	facetIter := iterator.Values(iterator.ForSlice([]*types.Document{res}))
+	closer.Add(facetIter)
+
+	return facetIter, nil
+}
+
+// run runs sub-pipeline on copies of the given documents.
+//
+// It stops when the context is canceled.
+func (fp facetPipeline) run(ctx context.Context, docs []*types.Document) ([]*types.Document, error) {
+	// documents are copied because the same documents are processed by all sub-pipelines,
+	// and stages modify documents in place
+	copies := make([]*types.Document, len(docs))
+	for i, d := range docs {
+		copies[i] = d.DeepCopy()
+	}
+
+	closer := iterator.NewMultiCloser()
+	defer closer.Close()
+
+	iter := iterator.Values(iterator.ForSlice(copies))
+	closer.Add(iter)
+
+	var err error
+
+	for _, s := range fp.stages {
+		if err = ctx.Err(); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if iter, err = s.Process(ctx, iter, closer); err != nil {
+			return nil, err
+		}
+	}
+
+	var res []*types.Document
+
+	for {
+		if err = ctx.Err(); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		_, doc, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			return res, nil
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		res = append(res, doc)
+	}
+}
+
+// checkSize returns an error if the given document constructed by $facet is too large.
+func (f *facet) checkSize(doc *types.Document) error {
+	d, err := bson.FromDocument(doc)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	raw, err := d.Encode()
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	if len(raw) > f.maxSize {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBSONObjectTooLarge,
+			fmt.Sprintf("document constructed by $facet is %d bytes, which exceeds the limit of %d bytes", len(raw), f.maxSize),
+			"$facet (stage)",
+		)
+	}
+
+	return nil
+}
+
+// check interfaces
+var (
+	_ aggregations.Stage = (*facet)(nil)
+)
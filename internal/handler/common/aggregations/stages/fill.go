@@ -0,0 +1,325 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stages
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations/operators"
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations/operators/windows"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// fill represents $fill stage.
+//
+//	{ $fill: {
+//		partitionBy: <expression>,
+//		partitionByFields: [ <field 1>, <field 2>, ... , <field n> ],
+//		sortBy: { <sort field 1>: <sort order>, ... },
+//		output: {
+//			<field 1>: { value: <expression> },
+//			<field 2>: { method: <"linear" or "locf"> },
+//			...
+//		}
+//	}}
+//
+// $fill sets null and missing output fields.
+// The value method sets the evaluated expression;
+// locf and linear methods use `$locf` and `$linearFill` window operators.
+// Documents are returned sorted by the partition key, then by sortBy.
+type fill struct {
+	partition partitionKeyFunc // nil if not partitioned
+	sortBy    *types.Document
+	values    []fillValue
+	methods   []windowOutput
+	collation *types.Collation
+}
+
+// fillValue represents the output field of $fill stage with value method.
+type fillValue struct {
+	path  types.Path
+	value any
+}
+
+// newFill validates stage document and creates a new $fill stage.
+func newFill(stage *types.Document, params *NewStageParams) (aggregations.Stage, error) {
+	fields, err := common.GetRequiredParam[*types.Document](stage, "$fill")
+	if err != nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			fmt.Sprintf(
+				"the $fill stage specification must be an object, found %s",
+				handlerparams.AliasFromType(must.NotFail(stage.Get("$fill"))),
+			),
+			"$fill (stage)",
+		)
+	}
+
+	f := &fill{
+		collation: params.Collation,
+	}
+
+	var output *types.Document
+	var hasPartitionBy bool
+
+	iter := fields.Iterator()
+	defer iter.Close()
+
+	for {
+		k, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		switch k {
+		case "partitionBy", "partitionByFields":
+			if hasPartitionBy {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrFailedToParse,
+					"Only one of 'partitionBy' and 'partitionByFields' is allowed in '$fill'",
+					"$fill (stage)",
+				)
+			}
+
+			hasPartitionBy = true
+
+			if k == "partitionBy" {
+				if err = operators.ValidateExpression(v); err != nil {
+					return nil, processStageOperatorError("$fill", err)
+				}
+
+				f.partition = partitionByExpression("$fill", v)
+
+				continue
+			}
+
+			var paths []types.Path
+			if paths, err = getPartitionByFields("$fill", v); err != nil {
+				return nil, err
+			}
+
+			f.partition = partitionByFields(paths)
+
+		case "sortBy":
+			sortBy, ok := v.(*types.Document)
+			if !ok {
+				return nil, newFillTypeError(k, v)
+			}
+
+			if f.sortBy, err = common.ValidateSortDocument(sortBy); err != nil {
+				return nil, err
+			}
+
+		case "output":
+			var ok bool
+			if output, ok = v.(*types.Document); !ok {
+				return nil, newFillTypeError(k, v)
+			}
+
+		default:
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrFailedToParseInput,
+				fmt.Sprintf("BSON field '$fill.%s' is an unknown field.", k),
+				"$fill (stage)",
+			)
+		}
+	}
+
+	if output == nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrMissingField,
+			"BSON field '$fill.output' is missing but a required field",
+			"$fill (stage)",
+		)
+	}
+
+	if err = validateFieldPath("$fill", output); err != nil {
+		return nil, err
+	}
+
+	for _, field := range output.Keys() {
+		if err = f.addOutput(field, must.NotFail(output.Get(field))); err != nil {
+			return nil, err
+		}
+	}
+
+	return f, nil
+}
+
+// addOutput validates the output field specification of $fill stage and adds it to the stage.
+func (f *fill) addOutput(field string, spec any) error {
+	path, err := fieldPath("$fill", field)
+	if err != nil {
+		return err
+	}
+
+	doc, ok := spec.(*types.Document)
+	if !ok || doc.Len() != 1 || (!doc.Has("value") && !doc.Has("method")) {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			"Each fill output specification must be an object with exactly one of a 'method' or 'value' field",
+			"$fill (stage)",
+		)
+	}
+
+	if doc.Has("value") {
+		value := must.NotFail(doc.Get("value"))
+
+		if err = operators.ValidateExpression(value); err != nil {
+			return processStageOperatorError("$fill", err)
+		}
+
+		f.values = append(f.values, fillValue{
+			path:  path,
+			value: value,
+		})
+
+		return nil
+	}
+
+	var name string
+
+	switch method := must.NotFail(doc.Get("method")); method {
+	case "locf":
+		name = "$locf"
+	case "linear":
+		name = "$linearFill"
+	default:
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			"Method must be either locf or linear",
+			"$fill (stage)",
+		)
+	}
+
+	if f.sortBy == nil {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			"sortBy required if any output field specifies a 'method'",
+			"$fill (stage)",
+		)
+	}
+
+	operator, err := windows.NewOperator(field, must.NotFail(types.NewDocument(name, "$"+field)), f.sortBy)
+	if err != nil {
+		return processStageOperatorError("$fill", err)
+	}
+
+	f.methods = append(f.methods, windowOutput{
+		path:     path,
+		operator: operator,
+	})
+
+	return nil
+}
+
+// newFillTypeError returns an error for the field of $fill stage that is not a document.
+func newFillTypeError(field string, value any) error {
+	return handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrTypeMismatch,
+		fmt.Sprintf(
+			"BSON field '$fill.%s' is the wrong type '%s', expected type 'object'",
+			field, handlerparams.AliasFromType(value),
+		),
+		"$fill (stage)",
+	)
+}
+
+// Process implements Stage interface.
+func (f *fill) Process(ctx context.Context, iter types.DocumentsIterator, closer *iterator.MultiCloser) (types.DocumentsIterator, error) { //nolint:lll // for readability
+	docs, err := iterator.ConsumeValues(iter)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	vars := aggregations.GetVariables(ctx)
+
+	partitions, err := partitionDocuments(docs, f.partition, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*types.Document, 0, len(docs))
+
+	for _, partition := range partitions {
+		if f.sortBy != nil {
+			if err = common.SortDocumentsWithCollation(partition, f.sortBy, f.collation); err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+		}
+
+		// all method values are computed before setting any of them,
+		// so window operators use original documents
+		values := make([][]any, len(f.methods))
+
+		for i, method := range f.methods {
+			if values[i], err = method.operator.Apply(partition, vars); err != nil {
+				return nil, processStageOperatorError("$fill", err)
+			}
+		}
+
+		for j, doc := range partition {
+			for i, method := range f.methods {
+				if err = doc.SetByPath(method.path, values[i][j]); err != nil {
+					return nil, lazyerrors.Error(err)
+				}
+			}
+
+			for _, value := range f.values {
+				if v, err := doc.GetByPath(value.path); err == nil && v != types.Null {
+					continue
+				}
+
+				v, err := operators.Evaluate(value.value, doc, vars)
+				if err != nil {
+					return nil, processStageOperatorError("$fill", err)
+				}
+
+				if v == nil {
+					v = types.Null
+				}
+
+				if err = doc.SetByPath(value.path, v); err != nil {
+					return nil, lazyerrors.Error(err)
+				}
+			}
+
+			res = append(res, doc)
+		}
+	}
+
+	iter = iterator.Values(iterator.ForSlice(res))
+	closer.Add(iter)
+
+	return iter, nil
+}
+
+// check interfaces
+var (
+	_ aggregations.Stage = (*fill)(nil)
+)
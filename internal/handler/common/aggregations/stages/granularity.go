@@ -0,0 +1,255 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stages
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+// granularity rounds $bucketAuto boundaries to the values of a number series.
+//
+// See https://en.wikipedia.org/wiki/Preferred_number.
+type granularity struct {
+	// series contains values of preferred numbers series in [100, 1000) range
+	// (scaled to integers); nil series means powers of 2.
+	series []float64
+}
+
+// preferredNumbers maps granularities to their series of preferred numbers.
+var preferredNumbers = map[string][]float64{
+	"R5": {
+		100, 160, 250, 400, 630,
+	},
+	"R10": {
+		100, 125, 160, 200, 250, 315, 400, 500, 630, 800,
+	},
+	"R20": {
+		100, 112, 125, 140, 160, 180, 200, 224, 250, 280, 315, 355, 400, 450, 500, 560, 630, 710, 800, 900,
+	},
+	"R40": {
+		100, 106, 112, 118, 125, 132, 140, 150, 160, 170, 180, 190, 200, 212, 224, 236, 250, 265, 280, 300, 315,
+		335, 355, 375, 400, 425, 450, 475, 500, 530, 560, 600, 630, 670, 710, 750, 800, 850, 900, 950,
+	},
+	"R80": {
+		100, 103, 106, 109, 112, 115, 118, 122, 125, 128, 132, 136, 140, 145, 150, 155, 160, 165, 170, 175, 180,
+		185, 190, 195, 200, 206, 212, 218, 224, 230, 236, 243, 250, 258, 265, 272, 280, 290, 300, 307, 315, 325,
+		335, 345, 355, 365, 375, 387, 400, 412, 425, 437, 450, 462, 475, 487, 500, 515, 530, 545, 560, 575, 600,
+		615, 630, 650, 670, 690, 710, 730, 750, 775, 800, 825, 850, 875, 900, 925, 950, 975,
+	},
+	"1-2-5": {
+		100, 200, 500,
+	},
+	"E6": {
+		100, 150, 220, 330, 470, 680,
+	},
+	"E12": {
+		100, 120, 150, 180, 220, 270, 330, 390, 470, 560, 680, 820,
+	},
+	"E24": {
+		100, 110, 120, 130, 150, 160, 180, 200, 220, 240, 270, 300, 330, 360, 390, 430, 470, 510, 560, 620, 680,
+		750, 820, 910,
+	},
+	"E48": {
+		100, 105, 110, 115, 121, 127, 133, 140, 147, 154, 162, 169, 178, 187, 196, 205, 215, 226, 237, 249, 261,
+		274, 287, 301, 316, 332, 348, 365, 383, 402, 422, 442, 464, 487, 511, 536, 562, 590, 619, 649, 681, 715,
+		750, 787, 825, 866, 909, 953,
+	},
+	"E96": {
+		100, 102, 105, 107, 110, 113, 115, 118, 121, 124, 127, 130, 133, 137, 140, 143, 147, 150, 154, 158, 162,
+		165, 169, 174, 178, 182, 187, 191, 196, 200, 205, 210, 215, 221, 226, 232, 237, 243, 249, 255, 261, 267,
+		274, 280, 287, 294, 301, 309, 316, 324, 332, 340, 348, 357, 365, 374, 383, 392, 402, 412, 422, 432, 442,
+		453, 464, 475, 487, 499, 511, 523, 536, 549, 562, 576, 590, 604, 619, 634, 649, 665, 681, 698, 715, 732,
+		750, 768, 787, 806, 825, 845, 866, 887, 909, 931, 953, 976,
+	},
+	"E192": {
+		100, 101, 102, 104, 105, 106, 107, 109, 110, 111, 113, 114, 115, 117, 118, 120, 121, 123, 124, 126, 127,
+		129, 130, 132, 133, 135, 137, 138, 140, 142, 143, 145, 147, 149, 150, 152, 154, 156, 158, 160, 162, 164,
+		165, 167, 169, 172, 174, 176, 178, 180, 182, 184, 187, 189, 191, 193, 196, 198, 200, 203, 205, 208, 210,
+		213, 215, 218, 221, 223, 226, 229, 232, 234, 237, 240, 243, 246, 249, 252, 255, 258, 261, 264, 267, 271,
+		274, 277, 280, 284, 287, 291, 294, 298, 301, 305, 309, 312, 316, 320, 324, 328, 332, 336, 340, 344, 348,
+		352, 357, 361, 365, 370, 374, 379, 383, 388, 392, 397, 402, 407, 412, 417, 422, 427, 432, 437, 442, 448,
+		453, 459, 464, 470, 475, 481, 487, 493, 499, 505, 511, 517, 523, 530, 536, 542, 549, 556, 562, 569, 576,
+		583, 590, 597, 604, 612, 619, 626, 634, 642, 649, 657, 665, 673, 681, 690, 698, 706, 715, 723, 732, 741,
+		750, 759, 768, 777, 787, 796, 806, 816, 825, 835, 845, 856, 866, 876, 887, 898, 909, 920, 931, 942, 953,
+		965, 976, 988,
+	},
+}
+
+// newGranularity returns granularity for the given name.
+func newGranularity(name string) (*granularity, error) {
+	if name == "POWERSOF2" {
+		return new(granularity), nil
+	}
+
+	series, ok := preferredNumbers[name]
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBucketAutoUnknownGranularity,
+			fmt.Sprintf("Unknown rounding granularity '%s'", name),
+			"$bucketAuto (stage)",
+		)
+	}
+
+	return &granularity{series: series}, nil
+}
+
+// validate returns error if the value cannot be rounded.
+func (g *granularity) validate(v any) error {
+	if !aggregations.IsNumber(v) {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBucketAutoGranularityNotNumber,
+			fmt.Sprintf(
+				"$bucketAuto can specify a 'granularity' with numeric boundaries only, but found type: %s",
+				handlerparams.AliasFromType(v),
+			),
+			"$bucketAuto (stage)",
+		)
+	}
+
+	f := aggregations.NumberToFloat64(v)
+
+	if math.IsNaN(f) {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBucketAutoGranularityNaN,
+			"$bucketAuto can specify a 'granularity' with numeric boundaries only, but found a NaN",
+			"$bucketAuto (stage)",
+		)
+	}
+
+	if f < 0 {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBucketAutoGranularityNegative,
+			fmt.Sprintf(
+				"$bucketAuto can specify a 'granularity' with non-negative numbers only, but found: %s",
+				types.FormatAnyValue(v),
+			),
+			"$bucketAuto (stage)",
+		)
+	}
+
+	return nil
+}
+
+// roundUp returns the smallest value of the series that is greater than the given number.
+// Zero and infinity are returned as is.
+func (g *granularity) roundUp(v any) any {
+	return g.round(v, true)
+}
+
+// roundDown returns the largest value of the series that is less than the given number.
+// Zero and infinity are returned as is.
+func (g *granularity) roundDown(v any) any {
+	return g.round(v, false)
+}
+
+// round rounds the validated number up or down.
+//
+// Preferred numbers are returned as float64 (or Decimal128 for Decimal128 input).
+// Powers of 2 keep the integer type of the input if the result fits.
+func (g *granularity) round(v any, up bool) any {
+	f := aggregations.NumberToFloat64(v)
+	if f == 0 || math.IsInf(f, 1) {
+		return v
+	}
+
+	var res float64
+
+	if g.series == nil {
+		res = roundPowerOf2(f, up)
+	} else {
+		res = roundPreferredNumber(g.series, f, up)
+	}
+
+	switch v.(type) {
+	case types.Decimal128:
+		return types.NewDecimal128FromFloat64(res)
+
+	case int32, int64:
+		if g.series != nil || res != math.Trunc(res) {
+			return res
+		}
+
+		if _, ok := v.(int32); ok && res <= math.MaxInt32 {
+			return int32(res)
+		}
+
+		if res < math.MaxInt64 {
+			return int64(res)
+		}
+	}
+
+	return res
+}
+
+// roundPowerOf2 rounds positive number to the nearest power of 2 that is greater (or less) than the number.
+func roundPowerOf2(f float64, up bool) float64 {
+	// f = frac × 2^exp, where frac is in [0.5, 1)
+	frac, exp := math.Frexp(f)
+
+	switch {
+	case up:
+		return math.Ldexp(1, exp)
+	case frac == 0.5:
+		// f is a power of 2 itself
+		return math.Ldexp(1, exp-2)
+	default:
+		return math.Ldexp(1, exp-1)
+	}
+}
+
+// roundPreferredNumber rounds positive number to the nearest value of the series
+// scaled by a power of 10 that is greater (or less) than the number.
+func roundPreferredNumber(series []float64, f float64, up bool) float64 {
+	// check adjacent decades too, because logarithm is not exact
+	exp := int(math.Floor(math.Log10(f)))
+
+	if up {
+		for e := exp - 1; e <= exp+1; e++ {
+			for _, s := range series {
+				if v := scalePow10(s, e-2); v > f {
+					return v
+				}
+			}
+		}
+	} else {
+		for e := exp + 1; e >= exp-1; e-- {
+			for i := len(series) - 1; i >= 0; i-- {
+				if v := scalePow10(series[i], e-2); v < f {
+					return v
+				}
+			}
+		}
+	}
+
+	// only possible for subnormal numbers
+	return f
+}
+
+// scalePow10 returns v × 10^exp.
+func scalePow10(v float64, exp int) float64 {
+	if exp < 0 && exp >= -308 {
+		// division of integer by exact power of 10 returns the nearest float64 to the decimal value,
+		// unlike multiplication by inexact negative power of 10
+		return v / math.Pow10(-exp)
+	}
+
+	return v * math.Pow10(exp)
+}
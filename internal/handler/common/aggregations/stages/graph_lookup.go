@@ -0,0 +1,526 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stages
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/bson"
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations/operators"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// graphLookupMaxMemoryBytes is the maximum total size of foreign documents loaded by `$graphLookup`,
+// and of documents found for a single input document.
+const graphLookupMaxMemoryBytes = 100 * 1024 * 1024
+
+// graphLookup represents $graphLookup stage.
+//
+//	{ $graphLookup: {
+//		from: <collection>,
+//		startWith: <expression>,
+//		connectFromField: <string>,
+//		connectToField: <string>,
+//		as: <string>,
+//		maxDepth: <number>,
+//		depthField: <string>,
+//		restrictSearchWithMatch: <document>
+//	}}
+//
+// $graphLookup recursively searches documents of the foreign collection,
+// starting with those which connectToField is equal to the evaluated startWith expression,
+// and following their connectFromField values.
+// Each document is visited once, so cycles are allowed.
+type graphLookup struct {
+	params           *NewStageParams
+	from             string
+	startWith        any
+	connectFromField types.Path
+	connectToField   types.Path
+	as               types.Path
+	depthField       *types.Path
+	maxDepth         int64 // negative if not set
+	restrict         *types.Document
+	pushdown         bool
+}
+
+// newGraphLookup validates stage document and creates a new $graphLookup stage.
+func newGraphLookup(stage *types.Document, params *NewStageParams) (aggregations.Stage, error) {
+	v := must.NotFail(stage.Get("$graphLookup"))
+
+	fields, ok := v.(*types.Document)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			fmt.Sprintf("the $graphLookup stage specification must be an object, but found %s", handlerparams.AliasFromType(v)),
+			"$graphLookup (stage)",
+		)
+	}
+
+	g := &graphLookup{
+		params:   params,
+		maxDepth: -1,
+	}
+
+	var connectFromField, connectToField, as, depthField string
+	var hasStartWith bool
+
+	iter := fields.Iterator()
+	defer iter.Close()
+
+	for {
+		k, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		switch k {
+		case "startWith":
+			if err = operators.ValidateExpression(v); err != nil {
+				return nil, processStageOperatorError("$graphLookup", err)
+			}
+
+			g.startWith = v
+			hasStartWith = true
+
+		case "maxDepth":
+			if g.maxDepth, err = getGraphLookupMaxDepth(v); err != nil {
+				return nil, err
+			}
+
+		case "restrictSearchWithMatch":
+			if g.restrict, ok = v.(*types.Document); !ok {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrGraphLookupRestrictNotObject,
+					fmt.Sprintf("restrictSearchWithMatch must be an object, found %s", handlerparams.AliasFromType(v)),
+					"$graphLookup (stage)",
+				)
+			}
+
+			if err = validateMatch(g.restrict); err != nil {
+				return nil, err
+			}
+
+		case "from", "as", "connectFromField", "connectToField", "depthField":
+			s, ok := v.(string)
+			if !ok {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrGraphLookupNotString,
+					fmt.Sprintf("expected string as argument for %s, found: %s", k, types.FormatAnyValue(v)),
+					"$graphLookup (stage)",
+				)
+			}
+
+			switch k {
+			case "from":
+				g.from = s
+			case "as":
+				as = s
+			case "connectFromField":
+				connectFromField = s
+			case "connectToField":
+				connectToField = s
+			case "depthField":
+				depthField = s
+			}
+
+		default:
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrGraphLookupUnknownArgument,
+				fmt.Sprintf("Unknown argument to $graphLookup: %s", k),
+				"$graphLookup (stage)",
+			)
+		}
+	}
+
+	if g.from == "" || as == "" || !hasStartWith || connectFromField == "" || connectToField == "" {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrGraphLookupMissingField,
+			"$graphLookup requires 'from', 'as', 'startWith', 'connectFromField', and 'connectToField' to be specified.",
+			"$graphLookup (stage)",
+		)
+	}
+
+	var err error
+
+	if g.as, err = fieldPath("$graphLookup", as); err != nil {
+		return nil, err
+	}
+
+	if g.connectFromField, err = fieldPath("$graphLookup", connectFromField); err != nil {
+		return nil, err
+	}
+
+	if g.connectToField, err = fieldPath("$graphLookup", connectToField); err != nil {
+		return nil, err
+	}
+
+	if depthField != "" {
+		var path types.Path
+		if path, err = fieldPath("$graphLookup", depthField); err != nil {
+			return nil, err
+		}
+
+		g.depthField = &path
+	}
+
+	if _, err = params.Database.Collection(g.from); err != nil {
+		if backends.ErrorCodeIs(err, backends.ErrorCodeCollectionNameIsInvalid) {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrInvalidNamespace,
+				fmt.Sprintf("Invalid $graphLookup namespace: %s.%s", params.DBName, g.from),
+				"$graphLookup (stage)",
+			)
+		}
+
+		return nil, lazyerrors.Error(err)
+	}
+
+	// backends compare values without collation
+	g.pushdown = !params.DisablePushdown && params.Collation == nil &&
+		g.connectFromField.Len() == 1 && g.connectToField.Len() == 1
+
+	return g, nil
+}
+
+// getGraphLookupMaxDepth returns maxDepth argument of $graphLookup stage.
+func getGraphLookupMaxDepth(v any) (int64, error) {
+	var maxDepth int64
+	var exact bool
+
+	switch v := v.(type) {
+	case int32:
+		maxDepth, exact = int64(v), true
+	case int64:
+		maxDepth, exact = v, true
+	case float64:
+		maxDepth = float64ToInt64(v)
+		exact = float64(maxDepth) == v
+	case types.Decimal128:
+		maxDepth = float64ToInt64(v.Float64())
+		exact = v.IsInteger() && float64(maxDepth) == v.Float64()
+	default:
+		return 0, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrGraphLookupMaxDepthNotNumber,
+			fmt.Sprintf("maxDepth must be numeric, found type: %s", handlerparams.AliasFromType(v)),
+			"$graphLookup (stage)",
+		)
+	}
+
+	if maxDepth < 0 {
+		return 0, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrGraphLookupMaxDepthNegative,
+			fmt.Sprintf("maxDepth requires a nonnegative argument, found: %d", maxDepth),
+			"$graphLookup (stage)",
+		)
+	}
+
+	if !exact {
+		return 0, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrGraphLookupMaxDepthNotInteger,
+			fmt.Sprintf("maxDepth could not be represented as a long long: %d", maxDepth),
+			"$graphLookup (stage)",
+		)
+	}
+
+	return maxDepth, nil
+}
+
+// Process implements Stage interface.
+//
+// Without pushdown, it fetches all documents of the foreign collection once.
+// With pushdown, it queries documents reachable from each input document.
+// If the backend does not apply the traversal, it returns all documents,
+// so they are used for all following input documents without pushdown.
+func (g *graphLookup) Process(ctx context.Context, iter types.DocumentsIterator, closer *iterator.MultiCloser) (types.DocumentsIterator, error) { //nolint:lll // for readability
+	c, err := g.params.Database.Collection(g.from)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	var foreignDocs []*types.Document
+
+	loaded := !g.pushdown
+
+	if loaded {
+		if foreignDocs, _, err = g.query(ctx, c, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	vars := aggregations.GetVariables(ctx)
+
+	graphIter := iterator.ForFunc(func() (struct{}, *types.Document, error) {
+		var unused struct{}
+
+		_, doc, err := iter.Next()
+		if err != nil {
+			return unused, nil, lazyerrors.Error(err)
+		}
+
+		startWith, err := g.startValues(doc, vars)
+		if err != nil {
+			return unused, nil, err
+		}
+
+		docs := foreignDocs
+
+		if !loaded {
+			var pushdown bool
+			if docs, pushdown, err = g.query(ctx, c, startWith); err != nil {
+				return unused, nil, err
+			}
+
+			if !pushdown {
+				foreignDocs, loaded = docs, true
+			}
+		}
+
+		found, err := g.traverse(startWith, docs)
+		if err != nil {
+			return unused, nil, err
+		}
+
+		if err = doc.SetByPath(g.as, found); err != nil {
+			return unused, nil, lazyerrors.Error(err)
+		}
+
+		return unused, doc, nil
+	})
+	closer.Add(graphIter)
+
+	return graphIter, nil
+}
+
+// query returns documents of the foreign collection, and true if the traversal was pushed down.
+// If startWith is not nil, the traversal is passed to the backend that may apply it.
+// It returns an error if the total size of returned documents exceeds the limit.
+func (g *graphLookup) query(ctx context.Context, c backends.Collection, startWith []any) ([]*types.Document, bool, error) {
+	var qp backends.QueryParams
+
+	if startWith != nil {
+		qp.Graph = &backends.GraphParams{
+			StartWith:        startWith,
+			ConnectFromField: g.connectFromField.String(),
+			ConnectToField:   g.connectToField.String(),
+			MaxDepth:         g.maxDepth,
+		}
+	}
+
+	res, err := c.Query(ctx, &qp)
+	if err != nil {
+		return nil, false, lazyerrors.Error(err)
+	}
+
+	defer res.Iter.Close()
+
+	var docs []*types.Document
+	var size int
+
+	for {
+		_, doc, err := res.Iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, false, lazyerrors.Error(err)
+		}
+
+		if size, err = g.addSize(size, doc); err != nil {
+			return nil, false, err
+		}
+
+		docs = append(docs, doc)
+	}
+
+	return docs, res.GraphPushdown, nil
+}
+
+// startValues returns values of the startWith expression evaluated for the given document
+// with the given scope of variables.
+// Array elements are separate values; missing value is null.
+func (g *graphLookup) startValues(doc *types.Document, vars *aggregations.Variables) ([]any, error) {
+	v, err := operators.Evaluate(g.startWith, doc, vars)
+	if err != nil {
+		return nil, processStageOperatorError("$graphLookup", err)
+	}
+
+	switch v := v.(type) {
+	case nil:
+		return []any{types.Null}, nil
+	case *types.Array:
+		res := make([]any, v.Len())
+		for i := range res {
+			res[i] = must.NotFail(v.Get(i))
+		}
+
+		return res, nil
+	default:
+		return []any{v}, nil
+	}
+}
+
+// traverse returns an array of copies of the given foreign documents found by breadth-first search.
+func (g *graphLookup) traverse(startWith []any, foreignDocs []*types.Document) (*types.Array, error) {
+	res := types.MakeArray(0)
+
+	// documents are visited once, even if they are reachable by several paths or cycles
+	visited := make([]bool, len(foreignDocs))
+
+	var size int
+
+	frontier := startWith
+
+	for depth := int64(0); len(frontier) > 0 && (g.maxDepth < 0 || depth <= g.maxDepth); depth++ {
+		filter := must.NotFail(types.NewDocument(
+			g.connectToField.String(), must.NotFail(types.NewDocument("$in", types.MakeArray(len(frontier)))),
+		))
+
+		in := must.NotFail(must.NotFail(filter.Get(g.connectToField.String())).(*types.Document).Get("$in")).(*types.Array)
+		for _, v := range frontier {
+			in.Append(v)
+		}
+
+		var next []any
+
+		for i, foreignDoc := range foreignDocs {
+			if visited[i] {
+				continue
+			}
+
+			matches, err := g.matches(foreignDoc, filter)
+			if err != nil {
+				return nil, err
+			}
+
+			if !matches {
+				continue
+			}
+
+			visited[i] = true
+
+			next = append(next, g.connectFromValues(foreignDoc)...)
+
+			// the same foreign document could be found for many input documents,
+			// and stages modify documents in place
+			found := foreignDoc.DeepCopy()
+
+			if g.depthField != nil {
+				if err = found.SetByPath(*g.depthField, depth); err != nil {
+					return nil, lazyerrors.Error(err)
+				}
+			}
+
+			if size, err = g.addSize(size, found); err != nil {
+				return nil, err
+			}
+
+			res.Append(found)
+		}
+
+		frontier = next
+	}
+
+	return res, nil
+}
+
+// matches returns true if the given foreign document matches the filter of the current depth
+// and restrictSearchWithMatch filter.
+func (g *graphLookup) matches(doc, filter *types.Document) (bool, error) {
+	matches, err := common.FilterDocumentWithCollation(doc, filter, g.params.Collation)
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	if !matches || g.restrict == nil {
+		return matches, nil
+	}
+
+	if matches, err = common.FilterDocumentWithCollation(doc, g.restrict, g.params.Collation); err != nil {
+		return false, err
+	}
+
+	return matches, nil
+}
+
+// connectFromValues returns values of connectFromField of the given document to search at the next depth.
+// Array elements are separate values; missing value is not searched.
+func (g *graphLookup) connectFromValues(doc *types.Document) []any {
+	v, err := doc.GetByPath(g.connectFromField)
+	if err != nil {
+		return nil
+	}
+
+	arr, ok := v.(*types.Array)
+	if !ok {
+		return []any{v}
+	}
+
+	res := make([]any, arr.Len())
+	for i := range res {
+		res[i] = must.NotFail(arr.Get(i))
+	}
+
+	return res
+}
+
+// addSize adds the size of the loaded or found document to the total size of such documents,
+// and returns an error if it exceeds the limit.
+func (g *graphLookup) addSize(size int, doc *types.Document) (int, error) {
+	d, err := bson.FromDocument(doc)
+	if err != nil {
+		return 0, lazyerrors.Error(err)
+	}
+
+	raw, err := d.Encode()
+	if err != nil {
+		return 0, lazyerrors.Error(err)
+	}
+
+	size += len(raw)
+
+	if size > graphLookupMaxMemoryBytes {
+		return 0, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrExceededMemoryLimit,
+			fmt.Sprintf(
+				"$graphLookup reached maximum memory consumption of %d bytes and cannot spill to disk",
+				graphLookupMaxMemoryBytes,
+			),
+			"$graphLookup (stage)",
+		)
+	}
+
+	return size, nil
+}
+
+// check interfaces
+var (
+	_ aggregations.Stage = (*graphLookup)(nil)
+)
@@ -16,8 +16,11 @@ package stages
 
 import (
 	"context"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/FerretDB/FerretDB/internal/handler/common"
@@ -55,7 +58,7 @@ type groupBy struct {
 }
 
 // newGroup creates a new $group stage.
-func newGroup(stage *types.Document) (aggregations.Stage, error) {
+func newGroup(stage *types.Document, _ *NewStageParams) (aggregations.Stage, error) {
 	fields, err := common.GetRequiredParam[*types.Document](stage, "$group")
 	if err != nil {
 		return nil, handlererrors.NewCommandErrorMsgWithArgument(
@@ -118,26 +121,17 @@ func newGroup(stage *types.Document) (aggregations.Stage, error) {
 
 // Process implements Stage interface.
 func (g *group) Process(ctx context.Context, iter types.DocumentsIterator, closer *iterator.MultiCloser) (types.DocumentsIterator, error) { //nolint:lll // for readability
-	groupedDocuments, err := g.groupDocuments(iter)
+	groups, err := g.groupDocuments(iter, aggregations.GetVariables(ctx))
 	if err != nil {
 		return nil, err
 	}
 
-	var res []*types.Document
+	res := make([]*types.Document, 0, len(groups))
 
-	for _, groupedDocument := range groupedDocuments {
-		doc := must.NotFail(types.NewDocument("_id", groupedDocument.groupID))
-
-		groupIter := iterator.Values(iterator.ForSlice(groupedDocument.documents))
-		defer groupIter.Close()
-
-		for _, accumulation := range g.groupBy {
-			out, err := accumulation.accumulator.Accumulate(groupIter)
-			if err != nil {
-				// existing accumulators do not return error
-				return nil, processGroupStageError(err)
-			}
+	for _, group := range groups {
+		doc := must.NotFail(types.NewDocument("_id", group.groupID))
 
+		for i, accumulation := range g.groupBy {
 			if doc.Has(accumulation.outputField) {
 				// document has duplicate key
 				return nil, handlererrors.NewCommandErrorMsgWithArgument(
@@ -147,7 +141,7 @@ func (g *group) Process(ctx context.Context, iter types.DocumentsIterator, close
 				)
 			}
 
-			doc.Set(accumulation.outputField, out)
+			doc.Set(accumulation.outputField, group.accumulations[i].Result())
 		}
 
 		res = append(res, doc)
@@ -162,6 +156,10 @@ func (g *group) Process(ctx context.Context, iter types.DocumentsIterator, close
 // validateGroupKey returns error on invalid group key.
 // If group key is a document, it recursively validates operator and expression.
 func validateGroupKey(groupKey any) error {
+	if expression, ok := groupKey.(string); ok {
+		return validateGroupExpression(expression)
+	}
+
 	doc, ok := groupKey.(*types.Document)
 	if !ok {
 		return nil
@@ -173,8 +171,8 @@ func validateGroupKey(groupKey any) error {
 			return processGroupStageError(err)
 		}
 
-		_, err = op.Process(nil)
-		if err != nil {
+		_, err = op.Process(nil, nil)
+		if err != nil && !operators.IsEvaluationError(err) {
 			// TODO https://github.com/FerretDB/FerretDB/issues/3129
 			return processGroupStageError(err)
 		}
@@ -208,15 +206,8 @@ func validateGroupKey(groupKey any) error {
 		case *types.Document:
 			return validateGroupKey(v)
 		case string:
-			_, err := aggregations.NewExpression(v, nil)
-			var exprErr *aggregations.ExpressionError
-
-			if errors.As(err, &exprErr) && exprErr.Code() == aggregations.ErrNotExpression {
-				err = nil
-			}
-
-			if err != nil {
-				return processGroupStageError(err)
+			if err = validateGroupExpression(v); err != nil {
+				return err
 			}
 		}
 	}
@@ -224,10 +215,38 @@ func validateGroupKey(groupKey any) error {
 	return nil
 }
 
-// groupDocuments groups documents into groups using group key. If group key contains expressions
-// or operators, they are evaluated before using it as the group key of documents.
-func (g *group) groupDocuments(iter types.DocumentsIterator) ([]groupedDocuments, error) {
-	var m groupMap
+// validateGroupExpression returns error on invalid expression of group key,
+// including the use of undefined variable.
+func validateGroupExpression(v string) error {
+	expression, err := aggregations.NewExpression(v, nil)
+
+	var exprErr *aggregations.ExpressionError
+	if errors.As(err, &exprErr) && exprErr.Code() == aggregations.ErrNotExpression {
+		return nil
+	}
+
+	if err == nil {
+		// only system variables are defined for group key
+		if _, err = expression.Evaluate(new(types.Document), nil); !errors.As(err, &exprErr) {
+			return nil
+		}
+	}
+
+	return processGroupStageError(err)
+}
+
+// groupDocuments groups documents into groups using group key and accumulates them.
+// If group key contains expressions or operators, they are evaluated before using it as the group key of documents.
+//
+// Documents are not kept in memory; only the state of accumulations of each group is.
+// Expressions are evaluated with the given scope of variables.
+func (g *group) groupDocuments(iter types.DocumentsIterator, vars *aggregations.Variables) ([]*groupAccumulations, error) {
+	m := groupMap{
+		groupBy: g.groupBy,
+		vars:    vars,
+		memory:  accumulators.NewMemoryUsage("$group"),
+		buckets: map[string][]int{},
+	}
 
 	for {
 		_, doc, err := iter.Next()
@@ -241,23 +260,31 @@ func (g *group) groupDocuments(iter types.DocumentsIterator) ([]groupedDocuments
 
 		switch groupKey := g.groupExpression.(type) {
 		case *types.Document:
-			val, err := evaluateDocument(groupKey, doc, false)
+			val, err := evaluateDocument(groupKey, doc, false, vars)
 			if err != nil {
 				// operator and expression errors are validated in newGroup
 				return nil, lazyerrors.Error(err)
 			}
 
-			m.addOrAppend(val, doc)
+			if err = m.add(val, doc); err != nil {
+				return nil, err
+			}
 		case *types.Array, float64, types.Binary, types.ObjectID, bool, time.Time, types.NullType,
-			types.Regex, int32, types.Timestamp, int64:
-			m.addOrAppend(groupKey, doc)
+			types.Regex, int32, types.Timestamp, int64,
+			types.Decimal128, types.MinKeyType, types.MaxKeyType:
+			if err := m.add(groupKey, doc); err != nil {
+				return nil, err
+			}
 		case string:
 			expression, err := aggregations.NewExpression(groupKey, nil)
 			if err != nil {
 				var exprErr *aggregations.ExpressionError
 				if errors.As(err, &exprErr) {
 					if exprErr.Code() == aggregations.ErrNotExpression {
-						m.addOrAppend(groupKey, doc)
+						if err = m.add(groupKey, doc); err != nil {
+							return nil, err
+						}
+
 						continue
 					}
 
@@ -267,23 +294,26 @@ func (g *group) groupDocuments(iter types.DocumentsIterator) ([]groupedDocuments
 				return nil, lazyerrors.Error(err)
 			}
 
-			val, err := expression.Evaluate(doc)
+			val, err := expression.Evaluate(doc, vars)
 			if err != nil {
 				// $group treats non-existent fields as nulls
 				val = types.Null
 			}
 
-			m.addOrAppend(val, doc)
+			if err = m.add(val, doc); err != nil {
+				return nil, err
+			}
 		default:
 			panic(fmt.Sprintf("unexpected type %[1]T (%#[1]v)", groupKey))
 		}
 	}
 
-	return m.docs, nil
+	return m.groups, nil
 }
 
-// evaluateDocument recursively evaluates document's field expressions and operators.
-func evaluateDocument(expr, doc *types.Document, nestedField bool) (any, error) {
+// evaluateDocument recursively evaluates document's field expressions and operators
+// with the given scope of variables.
+func evaluateDocument(expr, doc *types.Document, nestedField bool, vars *aggregations.Variables) (any, error) {
 	if operators.IsOperator(expr) {
 		op, err := operators.NewOperator(expr)
 		if err != nil {
@@ -291,12 +321,17 @@ func evaluateDocument(expr, doc *types.Document, nestedField bool) (any, error)
 			return nil, processGroupStageError(err)
 		}
 
-		v, err := op.Process(doc)
+		v, err := op.Process(doc, vars)
 		if err != nil {
 			// operator and expression errors are validated in newGroup
 			return nil, processGroupStageError(err)
 		}
 
+		if v == nil {
+			// $group treats missing values as nulls
+			return types.Null, nil
+		}
+
 		return v, nil
 	}
 
@@ -317,7 +352,7 @@ func evaluateDocument(expr, doc *types.Document, nestedField bool) (any, error)
 
 		switch exprVal := exprVal.(type) {
 		case *types.Document:
-			v, err := evaluateDocument(exprVal, doc, true)
+			v, err := evaluateDocument(exprVal, doc, true, vars)
 			if err != nil {
 				return nil, lazyerrors.Error(err)
 			}
@@ -337,7 +372,7 @@ func evaluateDocument(expr, doc *types.Document, nestedField bool) (any, error)
 				return nil, lazyerrors.Error(err)
 			}
 
-			v, err := expression.Evaluate(doc)
+			v, err := expression.Evaluate(doc, vars)
 			if err != nil {
 				if expr.Len() == 1 && !nestedField {
 					// non-existent path is set to null if expression contains single field and not a nested document
@@ -356,41 +391,149 @@ func evaluateDocument(expr, doc *types.Document, nestedField bool) (any, error)
 	return evaluatedDocument, nil
 }
 
-// groupedDocuments contains group key and the documents for that group.
-type groupedDocuments struct {
-	groupID   any
-	documents []*types.Document
+// groupAccumulations contains group key and the accumulations of documents for that group.
+type groupAccumulations struct {
+	groupID       any
+	accumulations []accumulators.Accumulation
 }
 
-// groupMap holds groups of documents.
+// groupMap holds groups of documents in the order they were first seen.
 type groupMap struct {
-	docs []groupedDocuments
+	groupBy []groupBy
+	groups  []*groupAccumulations
+	vars    *aggregations.Variables
+
+	// memory tracks group keys and values kept by accumulations of all groups.
+	memory *accumulators.MemoryUsage
+
+	// buckets maps hashes of group keys to indexes of groups.
+	buckets map[string][]int
 }
 
-// addOrAppend adds a groupID documents pair if the groupID does not exist,
-// if the groupID exists it appends the documents to the slice.
-func (m *groupMap) addOrAppend(groupKey any, docs ...*types.Document) {
-	for i, g := range m.docs {
+// add adds the document to the accumulations of the group with the given key,
+// creating a new group if it does not exist.
+func (m *groupMap) add(groupKey any, doc *types.Document) error {
+	g, err := m.get(groupKey)
+	if err != nil {
+		return err
+	}
+
+	for _, accumulation := range g.accumulations {
+		if err := accumulation.Add(doc, m.vars); err != nil {
+			return processGroupStageError(err)
+		}
+	}
+
+	return nil
+}
+
+// get returns the group with the given key, creating it if it does not exist.
+// It returns an error if the new group exceeds the memory limit.
+func (m *groupMap) get(groupKey any) (*groupAccumulations, error) {
+	hash := groupKeyHash(groupKey)
+
+	for _, i := range m.buckets[hash] {
 		// groupID is a distinct key and can be any BSON type including array and Binary,
-		// so we cannot use structure like map.
+		// so it is hashed only to find candidates.
 		// Compare is used to check if groupID exists in groupMap, because
 		// numbers are grouped for the same value regardless of their number type.
-		if types.CompareForAggregation(groupKey, g.groupID) == types.Equal {
-			m.docs[i].documents = append(m.docs[i].documents, docs...)
-			return
+		if types.CompareForAggregation(groupKey, m.groups[i].groupID) == types.Equal {
+			return m.groups[i], nil
 		}
 	}
 
-	m.docs = append(m.docs, groupedDocuments{
-		groupID:   groupKey,
-		documents: docs,
-	})
+	if err := m.memory.Add(groupKey); err != nil {
+		return nil, err
+	}
+
+	g := &groupAccumulations{
+		groupID:       groupKey,
+		accumulations: make([]accumulators.Accumulation, len(m.groupBy)),
+	}
+
+	for i, accumulation := range m.groupBy {
+		g.accumulations[i] = accumulation.accumulator.NewAccumulation(m.memory)
+	}
+
+	m.buckets[hash] = append(m.buckets[hash], len(m.groups))
+	m.groups = append(m.groups, g)
+
+	return g, nil
+}
+
+// groupKeyHash returns a hash of the group key.
+// Keys that are equal for types.CompareForAggregation have the same hash;
+// different keys may have the same hash too.
+func groupKeyHash(v any) string {
+	var sb strings.Builder
+	writeGroupKeyHash(&sb, v)
+
+	return sb.String()
+}
+
+// writeGroupKeyHash writes a hash of the value to the builder.
+func writeGroupKeyHash(sb *strings.Builder, v any) {
+	switch v := v.(type) {
+	case *types.Document:
+		sb.WriteString("{")
+
+		for _, k := range v.Keys() {
+			sb.WriteString(strconv.Quote(k))
+			sb.WriteString(":")
+			writeGroupKeyHash(sb, must.NotFail(v.Get(k)))
+			sb.WriteString(",")
+		}
+
+		sb.WriteString("}")
+
+	case *types.Array:
+		sb.WriteString("[")
+
+		for i := 0; i < v.Len(); i++ {
+			writeGroupKeyHash(sb, must.NotFail(v.Get(i)))
+			sb.WriteString(",")
+		}
+
+		sb.WriteString("]")
+
+	case float64, int32, int64, types.Decimal128:
+		f := aggregations.NumberToFloat64(v)
+		if f == 0 {
+			// -0 is equal to 0
+			f = 0
+		}
+
+		sb.WriteString("n")
+		sb.WriteString(strconv.FormatFloat(f, 'g', 15, 64))
+
+	case string:
+		sb.WriteString(strconv.Quote(v))
+	case types.ObjectID:
+		sb.WriteString("o")
+		sb.WriteString(hex.EncodeToString(v[:]))
+	case bool:
+		sb.WriteString(strconv.FormatBool(v))
+	case time.Time:
+		sb.WriteString("d")
+		sb.WriteString(strconv.FormatInt(v.UnixMilli(), 10))
+
+	default:
+		// other types are rarely used as keys, they are compared in the same bucket
+		fmt.Fprintf(sb, "%T", v)
+	}
 }
 
 // processGroupStageError takes internal error related to operator evaluation and
 // expression evaluation and returns CommandError that can be returned by $group
 // aggregation stage.
 func processGroupStageError(err error) error {
+	return processStageOperatorError("$group", err)
+}
+
+// processStageOperatorError takes internal error related to operator evaluation and
+// expression evaluation and returns CommandError that can be returned by the given
+// aggregation stage (like `$group` or `$setWindowFields`).
+func processStageOperatorError(stage string, err error) error {
 	var opErr operators.OperatorError
 	var exErr *aggregations.ExpressionError
 
@@ -401,31 +544,31 @@ func processGroupStageError(err error) error {
 			return handlererrors.NewCommandErrorMsgWithArgument(
 				handlererrors.ErrExpressionWrongLenOfFields,
 				"An object representing an expression must have exactly one field",
-				"$group (stage)",
+				stage+" (stage)",
 			)
 		case operators.ErrNotImplemented:
 			return handlererrors.NewCommandErrorMsgWithArgument(
 				handlererrors.ErrNotImplemented,
-				"Invalid $group :: caused by :: "+opErr.Error(),
-				"$group (stage)",
+				"Invalid "+stage+" :: caused by :: "+opErr.Error(),
+				stage+" (stage)",
 			)
 		case operators.ErrArgsInvalidLen:
 			return handlererrors.NewCommandErrorMsgWithArgument(
 				handlererrors.ErrOperatorWrongLenOfArgs,
 				opErr.Error(),
-				"$group (stage)",
+				stage+" (stage)",
 			)
 		case operators.ErrInvalidExpression:
 			return handlererrors.NewCommandErrorMsgWithArgument(
 				handlererrors.ErrInvalidPipelineOperator,
 				opErr.Error(),
-				"$group (stage)",
+				stage+" (stage)",
 			)
 		case operators.ErrInvalidNestedExpression:
 			return handlererrors.NewCommandErrorMsgWithArgument(
 				handlererrors.ErrInvalidPipelineOperator,
 				opErr.Error(),
-				"$group (stage)",
+				stage+" (stage)",
 			)
 		}
 
@@ -438,26 +581,25 @@ func processGroupStageError(err error) error {
 			return handlererrors.NewCommandErrorMsgWithArgument(
 				handlererrors.ErrFailedToParse,
 				"'$' starts with an invalid character for a user variable name",
-				"$group (stage)",
+				stage+" (stage)",
 			)
 		case aggregations.ErrEmptyFieldPath:
 			return handlererrors.NewCommandErrorMsgWithArgument(
 				handlererrors.ErrGroupInvalidFieldPath,
 				"'$' by itself is not a valid FieldPath",
-				"$group (stage)",
+				stage+" (stage)",
 			)
 		case aggregations.ErrUndefinedVariable:
-			// TODO https://github.com/FerretDB/FerretDB/issues/2275
 			return handlererrors.NewCommandErrorMsgWithArgument(
-				handlererrors.ErrNotImplemented,
-				"Aggregation expression variables are not implemented yet",
-				"$group (stage)",
+				handlererrors.ErrGroupUndefinedVariable,
+				"Use of undefined variable: "+exErr.Name(),
+				stage+" (stage)",
 			)
 		case aggregations.ErrEmptyVariable:
 			return handlererrors.NewCommandErrorMsgWithArgument(
 				handlererrors.ErrFailedToParse,
 				"empty variable names are not allowed",
-				"$group (stage)",
+				stage+" (stage)",
 			)
 		}
 	}
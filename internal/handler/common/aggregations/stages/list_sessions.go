@@ -0,0 +1,232 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stages
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/FerretDB/FerretDB/internal/clientconn/session"
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// listSessions represents $listSessions and $listLocalSessions stages.
+//
+// Both stages report sessions from the local sessions registry.
+type listSessions struct {
+	sessions *session.Registry
+	users    []string // nil for all users
+}
+
+// newListSessions creates a new $listSessions stage.
+func newListSessions(stage *types.Document, params *NewStageParams) (aggregations.Stage, error) {
+	if params.DBName != "config" || params.CollectionName != "system.sessions" {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrInvalidNamespace,
+			"$listSessions may only be run against config.system.sessions",
+			"$listSessions (stage)",
+		)
+	}
+
+	return newListSessionsStage(stage, params)
+}
+
+// newListLocalSessions creates a new $listLocalSessions stage.
+func newListLocalSessions(stage *types.Document, params *NewStageParams) (aggregations.Stage, error) {
+	if params.CollectionName != "" {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrInvalidNamespace,
+			"$listLocalSessions must be run against the database with {aggregate: 1}, not a collection",
+			"$listLocalSessions (stage)",
+		)
+	}
+
+	return newListSessionsStage(stage, params)
+}
+
+// newListSessionsStage parses common specification of $listSessions and $listLocalSessions stages.
+func newListSessionsStage(stage *types.Document, params *NewStageParams) (aggregations.Stage, error) {
+	name := stage.Command()
+
+	spec, ok := must.NotFail(stage.Get(name)).(*types.Document)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrTypeMismatch,
+			fmt.Sprintf(
+				"BSON field '%s' is the wrong type '%s', expected type 'object'",
+				name, handlerparams.AliasFromType(must.NotFail(stage.Get(name))),
+			),
+			name+" (stage)",
+		)
+	}
+
+	ls := &listSessions{
+		sessions: params.Sessions,
+		users:    []string{params.Username},
+	}
+
+	var allUsers bool
+
+	iter := spec.Iterator()
+	defer iter.Close()
+
+	for {
+		k, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		switch k {
+		case "allUsers":
+			if allUsers, ok = v.(bool); !ok {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrTypeMismatch,
+					fmt.Sprintf(
+						"BSON field '%s.allUsers' is the wrong type '%s', expected type 'bool'",
+						name, handlerparams.AliasFromType(v),
+					),
+					name+" (stage)",
+				)
+			}
+
+		case "users":
+			if ls.users, err = getListSessionsUsers(name, v); err != nil {
+				return nil, err
+			}
+
+		default:
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrFailedToParseInput,
+				fmt.Sprintf("BSON field '%s.%s' is an unknown field.", name, k),
+				name+" (stage)",
+			)
+		}
+	}
+
+	if allUsers {
+		if spec.Has("users") {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrBadValue,
+				"Cannot specify both allUsers and users",
+				name+" (stage)",
+			)
+		}
+
+		ls.users = nil
+	}
+
+	if !params.AllUsersSessions && (ls.users == nil || slices.ContainsFunc(ls.users, func(u string) bool {
+		return u != params.Username
+	})) {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrUnauthorized,
+			"not authorized to list sessions of other users",
+			name+" (stage)",
+		)
+	}
+
+	return ls, nil
+}
+
+// getListSessionsUsers returns user names from the `users` field ([{user: string, db: string}]).
+func getListSessionsUsers(name string, v any) ([]string, error) {
+	arr, ok := v.(*types.Array)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrTypeMismatch,
+			fmt.Sprintf(
+				"BSON field '%s.users' is the wrong type '%s', expected type 'array'",
+				name, handlerparams.AliasFromType(v),
+			),
+			name+" (stage)",
+		)
+	}
+
+	res := make([]string, 0, arr.Len())
+
+	for i := 0; i < arr.Len(); i++ {
+		u, _ := must.NotFail(arr.Get(i)).(*types.Document)
+
+		var user string
+		if u != nil {
+			v, _ := u.Get("user")
+			user, _ = v.(string)
+		}
+
+		if user == "" {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrTypeMismatch,
+				fmt.Sprintf("BSON field '%s.users.%d' must be an object with string 'user' and 'db' fields", name, i),
+				name+" (stage)",
+			)
+		}
+
+		res = append(res, user)
+	}
+
+	return res, nil
+}
+
+// Process implements Stage interface.
+//
+// The input documents are ignored.
+func (ls *listSessions) Process(ctx context.Context, iter types.DocumentsIterator, closer *iterator.MultiCloser) (types.DocumentsIterator, error) { //nolint:lll // for readability
+	var res []*types.Document
+
+	for _, s := range ls.sessions.All() {
+		if ls.users != nil && !slices.Contains(ls.users, s.Username) {
+			continue
+		}
+
+		uid := sha256.Sum256([]byte(s.Username))
+
+		doc := must.NotFail(types.NewDocument(
+			"_id", must.NotFail(types.NewDocument(
+				"id", types.Binary{Subtype: types.BinaryUUID, B: must.NotFail(s.ID.MarshalBinary())},
+				"uid", types.Binary{Subtype: types.BinaryGeneric, B: uid[:]},
+			)),
+			"lastUse", s.LastUse,
+		))
+
+		if s.Username != "" {
+			doc.Set("user", must.NotFail(types.NewDocument("name", s.Username)))
+		}
+
+		res = append(res, doc)
+	}
+
+	iter = iterator.Values(iterator.ForSlice(res))
+	closer.Add(iter)
+
+	return iter, nil
+}
+
+// check interfaces
+var (
+	_ aggregations.Stage = (*listSessions)(nil)
+)
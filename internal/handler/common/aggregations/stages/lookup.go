@@ -0,0 +1,661 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stages
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations/operators"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// lookup represents $lookup stage.
+//
+//	{ $lookup: {
+//		from: <foreign collection>,
+//		localField: <field from the input documents>,
+//		foreignField: <field from the documents of the "from" collection>,
+//		let: { <var_1>: <expression>, …, <var_n>: <expression> },
+//		pipeline: [ <pipeline to run on the foreign collection> ],
+//		as: <output array field>
+//	}}
+//
+// Either both localField and foreignField (equality match), pipeline, or all of them should be set.
+// When all of them are set, the pipeline runs on documents found by equality match.
+type lookup struct {
+	params       *NewStageParams
+	localField   *aggregations.Expression
+	let          *types.Document
+	from         string
+	foreignField string
+	as           types.Path
+	pipeline     []*types.Document
+	stages       []aggregations.Stage // nil if pipeline uses let variables and should be created for each document
+}
+
+// newLookup validates stage document and creates a new $lookup stage.
+func newLookup(stage *types.Document, params *NewStageParams) (aggregations.Stage, error) {
+	v := must.NotFail(stage.Get("$lookup"))
+
+	fields, ok := v.(*types.Document)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			fmt.Sprintf("the $lookup stage specification must be an object, but found %s", handlerparams.AliasFromType(v)),
+			"$lookup (stage)",
+		)
+	}
+
+	l := &lookup{
+		params: params,
+	}
+
+	var hasLocalField, hasForeignField, hasPipeline bool
+
+	iter := fields.Iterator()
+	defer iter.Close()
+
+	for {
+		k, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		switch k {
+		case "from":
+			if l.from, ok = v.(string); !ok {
+				return nil, lookupFieldTypeError(k, "string", v)
+			}
+
+		case "as":
+			var as string
+			if as, ok = v.(string); !ok {
+				return nil, lookupFieldTypeError(k, "string", v)
+			}
+
+			if l.as, err = validateLookupAs(as); err != nil {
+				return nil, err
+			}
+
+		case "localField":
+			var localField string
+			if localField, ok = v.(string); !ok {
+				return nil, lookupFieldTypeError(k, "string", v)
+			}
+
+			if l.localField, err = aggregations.NewExpression("$"+localField, nil); err != nil {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrFailedToParse,
+					fmt.Sprintf("$lookup argument 'localField' must be a valid field path, got %q", localField),
+					"$lookup (stage)",
+				)
+			}
+
+			hasLocalField = true
+
+		case "foreignField":
+			if l.foreignField, ok = v.(string); !ok {
+				return nil, lookupFieldTypeError(k, "string", v)
+			}
+
+			if _, err = types.NewPathFromString(l.foreignField); err != nil {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrFailedToParse,
+					fmt.Sprintf("$lookup argument 'foreignField' must be a valid field path, got %q", l.foreignField),
+					"$lookup (stage)",
+				)
+			}
+
+			hasForeignField = true
+
+		case "let":
+			if l.let, ok = v.(*types.Document); !ok {
+				return nil, lookupFieldTypeError(k, "object", v)
+			}
+
+			for _, name := range l.let.Keys() {
+				if err = validateVariableName(name, "$lookup (stage)"); err != nil {
+					return nil, err
+				}
+			}
+
+		case "pipeline":
+			var pipeline *types.Array
+			if pipeline, ok = v.(*types.Array); !ok {
+				return nil, lookupFieldTypeError(k, "array", v)
+			}
+
+			if l.pipeline, err = pipelineDocuments(pipeline, "$lookup (stage)"); err != nil {
+				return nil, err
+			}
+
+			for _, d := range l.pipeline {
+				switch d.Command() {
+				case "$out", "$merge":
+					return nil, handlererrors.NewCommandErrorMsgWithArgument(
+						handlererrors.ErrStageLookupNotAllowed,
+						fmt.Sprintf("%s is not allowed to be used within a $lookup stage", d.Command()),
+						"$lookup (stage)",
+					)
+				}
+			}
+
+			hasPipeline = true
+
+		default:
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrFailedToParse,
+				fmt.Sprintf("unknown argument to $lookup: %s", k),
+				"$lookup (stage)",
+			)
+		}
+	}
+
+	if l.as.Len() == 0 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			"must specify 'as' field for a $lookup",
+			"$lookup (stage)",
+		)
+	}
+
+	if l.from == "" {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			"must specify 'from' field for a $lookup",
+			"$lookup (stage)",
+		)
+	}
+
+	if _, err := params.Database.Collection(l.from); err != nil {
+		if backends.ErrorCodeIs(err, backends.ErrorCodeCollectionNameIsInvalid) {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrInvalidNamespace,
+				fmt.Sprintf("Invalid $lookup namespace: %s.%s", params.DBName, l.from),
+				"$lookup (stage)",
+			)
+		}
+
+		return nil, lazyerrors.Error(err)
+	}
+
+	if hasLocalField != hasForeignField {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			"$lookup requires both or neither of 'localField' and 'foreignField' to be specified",
+			"$lookup (stage)",
+		)
+	}
+
+	if !hasLocalField && !hasPipeline {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			"$lookup requires either 'pipeline' or both 'localField' and 'foreignField' to be specified",
+			"$lookup (stage)",
+		)
+	}
+
+	if l.let != nil && !hasPipeline {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			"$lookup with 'let' must also specify 'pipeline'",
+			"$lookup (stage)",
+		)
+	}
+
+	var err error
+	if l.stages, err = l.newPipelineStages(); err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// Process implements Stage interface.
+//
+// For equality match, input documents are processed in batches:
+// foreign documents with foreignField equal to any of localField values of the batch
+// are queried with the filter pushed down to the backend, and then joined with each input document.
+// Without equality match, the pipeline runs on all foreign documents for each input document.
+func (l *lookup) Process(ctx context.Context, iter types.DocumentsIterator, closer *iterator.MultiCloser) (types.DocumentsIterator, error) { //nolint:lll // for readability
+	c, err := l.params.Database.Collection(l.from)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	vars := aggregations.GetVariables(ctx)
+
+	// joined input documents that are not returned yet
+	var batch []*types.Document
+
+	// pipeline results for all input documents if the pipeline does not depend on them
+	var cached []*types.Document
+
+	lookupIter := iterator.ForFunc(func() (struct{}, *types.Document, error) {
+		var unused struct{}
+
+		if len(batch) > 0 {
+			doc := batch[0]
+			batch = batch[1:]
+
+			return unused, doc, nil
+		}
+
+		if l.localField != nil {
+			var err error
+			if batch, err = l.nextBatch(iter); err != nil {
+				return unused, nil, err
+			}
+
+			if err = l.joinBatch(ctx, c, batch, vars); err != nil {
+				return unused, nil, err
+			}
+
+			doc := batch[0]
+			batch = batch[1:]
+
+			return unused, doc, nil
+		}
+
+		_, doc, err := iter.Next()
+		if err != nil {
+			return unused, nil, lazyerrors.Error(err)
+		}
+
+		var matched []*types.Document
+
+		switch {
+		case cached != nil:
+			matched = make([]*types.Document, len(cached))
+			for i, d := range cached {
+				matched[i] = d.DeepCopy()
+			}
+
+		default:
+			if matched, err = l.runPipelineOnCollection(ctx, c, doc, vars); err != nil {
+				return unused, nil, err
+			}
+
+			if l.let.Len() == 0 {
+				// the pipeline does not depend on the input document
+				cached = make([]*types.Document, len(matched))
+				for i, d := range matched {
+					cached[i] = d.DeepCopy()
+				}
+			}
+		}
+
+		if err = doc.SetByPath(l.as, documentsArray(matched)); err != nil {
+			return unused, nil, lazyerrors.Error(err)
+		}
+
+		return unused, doc, nil
+	})
+	closer.Add(lookupIter)
+
+	return lookupIter, nil
+}
+
+// lookupBatchSize is the maximum number of input documents joined with a single query.
+const lookupBatchSize = 100
+
+// nextBatch returns the next batch of input documents.
+// It returns ErrIteratorDone if there are no more documents.
+func (l *lookup) nextBatch(iter types.DocumentsIterator) ([]*types.Document, error) {
+	res := make([]*types.Document, 0, lookupBatchSize)
+
+	for len(res) < lookupBatchSize {
+		_, doc, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		res = append(res, doc)
+	}
+
+	if len(res) == 0 {
+		return nil, iterator.ErrIteratorDone
+	}
+
+	return res, nil
+}
+
+// joinBatch sets the `as` field of each input document of the batch to the array of joined foreign documents.
+//
+// Foreign documents are queried once for the whole batch; they are not kept in memory unless matched.
+func (l *lookup) joinBatch(ctx context.Context, c backends.Collection, batch []*types.Document, vars *aggregations.Variables) error { //nolint:lll // for readability
+	filters := make([]*types.Document, len(batch))
+	values := types.MakeArray(len(batch))
+
+	for i, doc := range batch {
+		filters[i] = l.equalityFilter(doc, vars)
+
+		in := must.NotFail(must.NotFail(filters[i].Get(l.foreignField)).(*types.Document).Get("$in")).(*types.Array)
+		for j := 0; j < in.Len(); j++ {
+			values.Append(must.NotFail(in.Get(j)))
+		}
+	}
+
+	var qp backends.QueryParams
+
+	// the backend may return more documents than matched; they are filtered below
+	if !l.params.DisablePushdown && l.params.Collation == nil {
+		qp.Filter = must.NotFail(types.NewDocument(
+			l.foreignField, must.NotFail(types.NewDocument("$in", values)),
+		))
+	}
+
+	res, err := c.Query(ctx, &qp)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	defer res.Iter.Close()
+
+	matched := make([][]*types.Document, len(batch))
+
+	for {
+		_, foreignDoc, err := res.Iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		for i, filter := range filters {
+			matches, err := common.FilterDocumentWithCollation(foreignDoc, filter, l.params.Collation)
+			if err != nil {
+				return lazyerrors.Error(err)
+			}
+
+			if !matches {
+				continue
+			}
+
+			// the same foreign document could be joined with many input documents,
+			// and stages modify documents in place
+			matched[i] = append(matched[i], foreignDoc.DeepCopy())
+		}
+	}
+
+	for i, doc := range batch {
+		docs := matched[i]
+
+		if l.pipeline != nil {
+			closer := iterator.NewMultiCloser()
+			iter := iterator.Values(iterator.ForSlice(docs))
+			closer.Add(iter)
+
+			docs, err = l.runPipeline(ctx, doc, iter, closer, vars)
+			closer.Close()
+
+			if err != nil {
+				return err
+			}
+		}
+
+		if err = doc.SetByPath(l.as, documentsArray(docs)); err != nil {
+			return lazyerrors.Error(err)
+		}
+	}
+
+	return nil
+}
+
+// equalityFilter returns a filter that matches foreign documents
+// which foreignField is equal to the localField of the given document.
+//
+// If localField is an array, any of its elements should match.
+// If localField is missing, it matches foreign documents with null or missing foreignField.
+func (l *lookup) equalityFilter(doc *types.Document, vars *aggregations.Variables) *types.Document {
+	values := types.MakeArray(1)
+
+	v, err := l.localField.Evaluate(doc, vars)
+
+	switch v := v.(type) {
+	case *types.Array:
+		for i := 0; i < v.Len(); i++ {
+			values.Append(must.NotFail(v.Get(i)))
+		}
+	default:
+		if err != nil {
+			v = types.Null
+		}
+
+		values.Append(v)
+	}
+
+	return must.NotFail(types.NewDocument(
+		l.foreignField, must.NotFail(types.NewDocument("$in", values)),
+	))
+}
+
+// runPipelineOnCollection runs $lookup pipeline on all foreign documents for the given input document.
+func (l *lookup) runPipelineOnCollection(ctx context.Context, c backends.Collection, doc *types.Document, vars *aggregations.Variables) ([]*types.Document, error) { //nolint:lll // for readability
+	res, err := c.Query(ctx, nil)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	closer := iterator.NewMultiCloser()
+	defer closer.Close()
+
+	closer.Add(res.Iter)
+
+	return l.runPipeline(ctx, doc, res.Iter, closer, vars)
+}
+
+// runPipeline runs $lookup pipeline on foreign documents for the given input document.
+//
+// The pipeline stages get `let` variables evaluated for the input document
+// in a scope nested in the given scope.
+func (l *lookup) runPipeline(ctx context.Context, doc *types.Document, iter types.DocumentsIterator, closer *iterator.MultiCloser, vars *aggregations.Variables) ([]*types.Document, error) { //nolint:lll // for readability
+	if l.let.Len() > 0 {
+		letVars, err := evaluateVariables("$lookup", l.let, doc, vars)
+		if err != nil {
+			return nil, err
+		}
+
+		vars = vars.With(letVars)
+	}
+
+	ctx = aggregations.CtxWithVariables(ctx, vars)
+
+	var err error
+
+	for _, s := range l.stages {
+		if iter, err = s.Process(ctx, iter, closer); err != nil {
+			return nil, err
+		}
+	}
+
+	return iterator.ConsumeValues(iter)
+}
+
+// newPipelineStages creates $lookup pipeline stages.
+//
+// They are created once; variables are passed to them with the context.
+func (l *lookup) newPipelineStages() ([]aggregations.Stage, error) {
+	res := make([]aggregations.Stage, len(l.pipeline))
+
+	for i, d := range l.pipeline {
+		s, err := newPipelineStage(d, &NewStageParams{
+			Backend:                l.params.Backend,
+			Database:               l.params.Database,
+			DBName:                 l.params.DBName,
+			CollectionName:         l.from,
+			MaxBsonObjectSizeBytes: l.params.MaxBsonObjectSizeBytes,
+			Collation:              l.params.Collation,
+			DisablePushdown:        l.params.DisablePushdown,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		res[i] = s
+	}
+
+	return res, nil
+}
+
+// documentsArray returns an array of the given documents.
+func documentsArray(docs []*types.Document) *types.Array {
+	res := types.MakeArray(len(docs))
+	for _, d := range docs {
+		res.Append(d)
+	}
+
+	return res
+}
+
+// validateLookupAs validates $lookup `as` field and returns its path.
+func validateLookupAs(as string) (types.Path, error) {
+	if strings.HasPrefix(as, "$") {
+		return types.Path{}, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFieldPathInvalidName,
+			"FieldPath field names may not start with '$'. Consider using $getField or $setField.",
+			"$lookup (stage)",
+		)
+	}
+
+	path, err := types.NewPathFromString(as)
+	if err != nil {
+		return types.Path{}, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			fmt.Sprintf("$lookup argument 'as' must be a valid field path, got %q", as),
+			"$lookup (stage)",
+		)
+	}
+
+	return path, nil
+}
+
+// lookupFieldTypeError returns an error for $lookup field of unexpected type.
+func lookupFieldTypeError(field, expected string, v any) error {
+	return handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrFailedToParse,
+		fmt.Sprintf("$lookup argument '%s' must be %s, is type %s", field, expected, handlerparams.AliasFromType(v)),
+		"$lookup (stage)",
+	)
+}
+
+// pipelineDocuments returns documents of the given sub-pipeline array.
+//
+// It returns an error if any of the elements is not a document.
+func pipelineDocuments(pipeline *types.Array, argument string) ([]*types.Document, error) {
+	res := make([]*types.Document, pipeline.Len())
+
+	for i := 0; i < pipeline.Len(); i++ {
+		d, ok := must.NotFail(pipeline.Get(i)).(*types.Document)
+		if !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrTypeMismatch,
+				"Each element of the 'pipeline' array must be an object",
+				argument,
+			)
+		}
+
+		res[i] = d
+	}
+
+	return res, nil
+}
+
+// validateVariableName returns an error if the given user variable name is invalid.
+//
+// User variable names should start with a lowercase ASCII letter or a non-ASCII character.
+func validateVariableName(name, argument string) error {
+	if name == "" {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			"empty variable names are not allowed",
+			argument,
+		)
+	}
+
+	r, _ := utf8.DecodeRuneInString(name)
+	if r < utf8.RuneSelf && !unicode.IsLower(r) {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			fmt.Sprintf("'%s' starts with an invalid character for a user variable name", name),
+			argument,
+		)
+	}
+
+	return nil
+}
+
+// evaluateVariables evaluates variable expressions of the given `let` document
+// for the given document with the given scope of variables.
+// Values are document data, they are not evaluated again when variables are used.
+//
+// Missing values are evaluated to null.
+func evaluateVariables(stage string, let, doc *types.Document, vars *aggregations.Variables) (map[string]any, error) {
+	res := make(map[string]any, let.Len())
+
+	iter := let.Iterator()
+	defer iter.Close()
+
+	for {
+		name, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if v, err = operators.Evaluate(v, doc, vars); err != nil {
+			return nil, processStageOperatorError(stage, err)
+		}
+
+		if v == nil {
+			v = types.Null
+		}
+
+		res[name] = v
+	}
+
+	return res, nil
+}
+
+// check interfaces
+var (
+	_ aggregations.Stage = (*lookup)(nil)
+)
@@ -27,11 +27,12 @@ import (
 
 // match represents $match stage.
 type match struct {
-	filter *types.Document
+	filter    *types.Document
+	collation *types.Collation
 }
 
 // newMatch creates a new $match stage.
-func newMatch(stage *types.Document) (aggregations.Stage, error) {
+func newMatch(stage *types.Document, params *NewStageParams) (aggregations.Stage, error) {
 	filter, err := common.GetRequiredParam[*types.Document](stage, "$match")
 	if err != nil {
 		return nil, handlererrors.NewCommandErrorMsgWithArgument(
@@ -46,13 +47,14 @@ func newMatch(stage *types.Document) (aggregations.Stage, error) {
 	}
 
 	return &match{
-		filter: filter,
+		filter:    filter,
+		collation: params.Collation,
 	}, nil
 }
 
 // Process implements Stage interface.
 func (m *match) Process(ctx context.Context, iter types.DocumentsIterator, closer *iterator.MultiCloser) (types.DocumentsIterator, error) { //nolint:lll // for readability
-	return common.FilterIterator(iter, closer, m.filter), nil
+	return common.FilterIterator(iter, closer, m.filter, m.collation, aggregations.GetVariables(ctx)), nil
 }
 
 // validateMatch validates $expr field if any.
@@ -0,0 +1,741 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stages
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// $merge `whenMatched` and `whenNotMatched` modes.
+const (
+	mergeReplace      = "replace"
+	mergeKeepExisting = "keepExisting"
+	mergeMerge        = "merge"
+	mergeFail         = "fail"
+	mergePipeline     = "pipeline"
+	mergeInsert       = "insert"
+	mergeDiscard      = "discard"
+)
+
+// mergePipelineStages contains stages that can be used in $merge `whenMatched` pipeline.
+var mergePipelineStages = map[string]struct{}{
+	"$addFields":   {},
+	"$project":     {},
+	"$replaceRoot": {},
+	"$replaceWith": {},
+	"$set":         {},
+	"$unset":       {},
+}
+
+// merge represents $merge stage.
+//
+//	{ $merge: {
+//		into: <collection> -or- { db: <db>, coll: <collection> },
+//		on: <identifier field> -or- [ <identifier field1>, ...],
+//		let: <variables>,
+//		whenMatched: <replace|keepExisting|merge|fail|pipeline>,
+//		whenNotMatched: <insert|discard|fail>
+//	}}
+//	{ $merge: <collection> }
+type merge struct {
+	params         *NewStageParams
+	db             backends.Database
+	dbName         string
+	cName          string
+	on             []string
+	let            *types.Document
+	whenMatched    string
+	pipeline       []*types.Document // whenMatched pipeline
+	stages         []aggregations.Stage
+	whenNotMatched string
+}
+
+// newMerge validates stage document and creates a new $merge stage.
+func newMerge(stage *types.Document, params *NewStageParams) (aggregations.Stage, error) {
+	v := must.NotFail(stage.Get("$merge"))
+
+	m := &merge{
+		params:         params,
+		dbName:         params.DBName,
+		on:             []string{"_id"},
+		whenMatched:    mergeMerge,
+		whenNotMatched: mergeInsert,
+	}
+
+	switch v := v.(type) {
+	case string:
+		m.cName = v
+
+	case *types.Document:
+		if err := m.parseFields(v); err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrStageMergeInvalidArg,
+			fmt.Sprintf("$merge only supports a string or object argument, not %s", handlerparams.AliasFromType(v)),
+			"$merge (stage)",
+		)
+	}
+
+	var err error
+	if m.db, err = outputDatabase(params, m.dbName, m.cName, "$merge"); err != nil {
+		return nil, err
+	}
+
+	if m.pipeline != nil {
+		if m.stages, err = m.newPipelineStages(); err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+// parseFields parses $merge stage document fields.
+func (m *merge) parseFields(fields *types.Document) error {
+	iter := fields.Iterator()
+	defer iter.Close()
+
+	var hasInto bool
+
+	for {
+		k, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		switch k {
+		case "into":
+			switch v := v.(type) {
+			case string:
+				m.cName = v
+			case *types.Document:
+				if m.dbName, m.cName, err = outputNamespace(v, "$merge.into", m.dbName); err != nil {
+					return err
+				}
+			default:
+				return mergeFieldTypeError(k, "[string, object]", v)
+			}
+
+			hasInto = true
+
+		case "on":
+			if m.on, err = mergeOn(v); err != nil {
+				return err
+			}
+
+		case "let":
+			var ok bool
+			if m.let, ok = v.(*types.Document); !ok {
+				return mergeFieldTypeError(k, "object", v)
+			}
+
+			for _, name := range m.let.Keys() {
+				if err = validateVariableName(name, "$merge (stage)"); err != nil {
+					return err
+				}
+			}
+
+		case "whenMatched":
+			switch v := v.(type) {
+			case string:
+				if !slices.Contains([]string{mergeReplace, mergeKeepExisting, mergeMerge, mergeFail}, v) {
+					return mergeEnumerationError(k, v)
+				}
+
+				m.whenMatched = v
+
+			case *types.Array:
+				if m.pipeline, err = pipelineDocuments(v, "$merge (stage)"); err != nil {
+					return err
+				}
+
+				for _, d := range m.pipeline {
+					if _, ok := mergePipelineStages[d.Command()]; !ok {
+						return handlererrors.NewCommandErrorMsgWithArgument(
+							handlererrors.ErrBadValue,
+							fmt.Sprintf("%s is not allowed to be used within a $merge 'whenMatched' pipeline", d.Command()),
+							"$merge (stage)",
+						)
+					}
+				}
+
+				m.whenMatched = mergePipeline
+
+			default:
+				return mergeFieldTypeError(k, "[string, array]", v)
+			}
+
+		case "whenNotMatched":
+			s, ok := v.(string)
+			if !ok {
+				return mergeFieldTypeError(k, "string", v)
+			}
+
+			if !slices.Contains([]string{mergeInsert, mergeDiscard, mergeFail}, s) {
+				return mergeEnumerationError(k, s)
+			}
+
+			m.whenNotMatched = s
+
+		default:
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrFailedToParseInput,
+				fmt.Sprintf("BSON field '$merge.%s' is an unknown field.", k),
+				"$merge (stage)",
+			)
+		}
+	}
+
+	if !hasInto {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrMissingField,
+			"BSON field '$merge.into' is missing but a required field",
+			"$merge (stage)",
+		)
+	}
+
+	return nil
+}
+
+// Process implements Stage interface.
+//
+// It merges all documents into the output collection.
+// It returns no documents.
+func (m *merge) Process(ctx context.Context, iter types.DocumentsIterator, closer *iterator.MultiCloser) (types.DocumentsIterator, error) { //nolint:lll // for readability
+	docs, err := iterator.ConsumeValues(iter)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	c, err := m.db.Collection(m.cName)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if err = m.checkUniqueIndex(ctx, c); err != nil {
+		return nil, err
+	}
+
+	list, err := m.db.ListCollections(ctx, &backends.ListCollectionsParams{Name: m.cName})
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	var info *backends.CollectionInfo
+	if len(list.Collections) > 0 {
+		info = &list.Collections[0]
+	}
+
+	v := outputValidator(info, m.params)
+
+	// documents of the output collection that were found or inserted
+	var targets []*types.Document
+
+	// maps hashes of `on` field values to indexes in targets
+	buckets := map[string][]int{}
+
+	// documents of the output collection that should be inserted or updated, by index in targets
+	inserted := map[int]struct{}{}
+	updated := map[int]struct{}{}
+
+	for _, doc := range docs {
+		generatedID := !doc.Has("_id")
+		if generatedID {
+			doc.Set("_id", types.NewObjectID())
+		}
+
+		values, err := m.onValues(doc)
+		if err != nil {
+			return nil, err
+		}
+
+		hash := groupKeyHash(values)
+
+		i, err := m.findTarget(values, targets, buckets[hash])
+		if err != nil {
+			return nil, err
+		}
+
+		if i < 0 {
+			var found *types.Document
+			if found, err = m.queryTarget(ctx, c, values); err != nil {
+				return nil, err
+			}
+
+			if found != nil {
+				i = len(targets)
+				buckets[hash] = append(buckets[hash], i)
+				targets = append(targets, found)
+			}
+		}
+
+		if i < 0 {
+			switch m.whenNotMatched {
+			case mergeInsert:
+				if err = validateOutputDocument(v, doc, "$merge"); err != nil {
+					return nil, err
+				}
+
+				buckets[hash] = append(buckets[hash], len(targets))
+				inserted[len(targets)] = struct{}{}
+				targets = append(targets, doc)
+
+			case mergeDiscard:
+				// nothing
+
+			case mergeFail:
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrMergeStageNoMatchingDocument,
+					"$merge could not find a matching document in the target collection "+
+						"for at least one document in the source collection",
+					"$merge (stage)",
+				)
+
+			default:
+				panic(fmt.Sprintf("unexpected whenNotMatched mode %q", m.whenNotMatched))
+			}
+
+			continue
+		}
+
+		if m.whenMatched == mergeKeepExisting {
+			continue
+		}
+
+		target := targets[i]
+
+		var newDoc *types.Document
+
+		switch m.whenMatched {
+		case mergeReplace:
+			newDoc = doc
+
+		case mergeMerge:
+			newDoc = target.DeepCopy()
+
+			for _, k := range doc.Keys() {
+				newDoc.Set(k, must.NotFail(doc.Get(k)))
+			}
+
+		case mergeFail:
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrDuplicateKeyInsert,
+				"$merge write error: E11000 duplicate key error",
+				"$merge (stage)",
+			)
+
+		case mergePipeline:
+			if newDoc, err = m.runPipeline(ctx, doc, target); err != nil {
+				return nil, err
+			}
+
+		default:
+			panic(fmt.Sprintf("unexpected whenMatched mode %q", m.whenMatched))
+		}
+
+		targetID := must.NotFail(target.Get("_id"))
+
+		if generatedID || !newDoc.Has("_id") {
+			newDoc.Set("_id", targetID)
+		}
+
+		if types.Compare(must.NotFail(newDoc.Get("_id")), targetID) != types.Equal {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrImmutableField,
+				"$merge failed to update the matching document, did you attempt to modify the _id or the shard key?",
+				"$merge (stage)",
+			)
+		}
+
+		applies, err := v.Applies(target)
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if applies {
+			if err = validateOutputDocument(v, newDoc, "$merge"); err != nil {
+				return nil, err
+			}
+		}
+
+		targets[i] = newDoc
+
+		if _, ok := inserted[i]; !ok {
+			updated[i] = struct{}{}
+		}
+	}
+
+	if err = m.write(ctx, c, targets, inserted, updated); err != nil {
+		return nil, err
+	}
+
+	mergeIter := iterator.Values(iterator.ForSlice([]*types.Document{}))
+	closer.Add(mergeIter)
+
+	return mergeIter, nil
+}
+
+// write inserts and updates documents of the output collection.
+func (m *merge) write(ctx context.Context, c backends.Collection, targets []*types.Document, inserted, updated map[int]struct{}) error { //nolint:lll // for readability
+	insertDocs := make([]*types.Document, 0, len(inserted))
+	updateDocs := make([]*types.Document, 0, len(updated))
+
+	for i, doc := range targets {
+		_, isInserted := inserted[i]
+		_, isUpdated := updated[i]
+
+		if !isInserted && !isUpdated {
+			continue
+		}
+
+		if err := prepareOutputDocument(doc); err != nil {
+			return err
+		}
+
+		if isInserted {
+			insertDocs = append(insertDocs, doc)
+		} else {
+			updateDocs = append(updateDocs, doc)
+		}
+	}
+
+	if len(insertDocs) > 0 {
+		if _, err := c.InsertAll(ctx, &backends.InsertAllParams{Docs: insertDocs}); err != nil {
+			return outputWriteError(err, "$merge")
+		}
+	}
+
+	if len(updateDocs) > 0 {
+		if _, err := c.UpdateAll(ctx, &backends.UpdateAllParams{Docs: updateDocs}); err != nil {
+			return lazyerrors.Error(err)
+		}
+	}
+
+	return nil
+}
+
+// onValues returns values of `on` fields of the given document as an array.
+func (m *merge) onValues(doc *types.Document) (*types.Array, error) {
+	res := types.MakeArray(len(m.on))
+
+	for _, field := range m.on {
+		v, err := doc.GetByPath(must.NotFail(types.NewPathFromString(field)))
+
+		switch v.(type) {
+		case types.NullType, *types.Array:
+			err = errors.New("invalid value")
+		}
+
+		if err != nil {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrStageMergeInvalidOn,
+				"$merge write error: 'on' field cannot be missing, null, undefined or an array",
+				"$merge (stage)",
+			)
+		}
+
+		res.Append(v)
+	}
+
+	return res, nil
+}
+
+// onFilter returns a filter that matches documents with the given values of `on` fields.
+func (m *merge) onFilter(values *types.Array) *types.Document {
+	res := types.MakeDocument(len(m.on))
+
+	for i, field := range m.on {
+		res.Set(field, must.NotFail(types.NewDocument("$eq", must.NotFail(values.Get(i)))))
+	}
+
+	return res
+}
+
+// findTarget returns the index of the document with the given values of `on` fields
+// among candidates from targets, or -1 if there is no such document.
+func (m *merge) findTarget(values *types.Array, targets []*types.Document, candidates []int) (int, error) {
+	filter := m.onFilter(values)
+
+	for _, i := range candidates {
+		matches, err := common.FilterDocument(targets[i], filter)
+		if err != nil {
+			return 0, lazyerrors.Error(err)
+		}
+
+		if matches {
+			return i, nil
+		}
+	}
+
+	return -1, nil
+}
+
+// queryTarget returns the output collection document with the given values of `on` fields,
+// or nil if there is no such document.
+//
+// The filter is pushed down to the backend.
+func (m *merge) queryTarget(ctx context.Context, c backends.Collection, values *types.Array) (*types.Document, error) {
+	filter := m.onFilter(values)
+
+	// documents are compared by the backend as plain values (if at all),
+	// so the pushed down filter may match more documents than needed
+	pushdown := types.MakeDocument(len(m.on))
+
+	for i, field := range m.on {
+		v := must.NotFail(values.Get(i))
+		if _, ok := v.(*types.Document); ok {
+			v = must.NotFail(types.NewDocument("$eq", v))
+		}
+
+		pushdown.Set(field, v)
+	}
+
+	res, err := c.Query(ctx, &backends.QueryParams{Filter: pushdown})
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	defer res.Iter.Close()
+
+	for {
+		_, doc, err := res.Iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			return nil, nil
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		matches, err := common.FilterDocument(doc, filter)
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if matches {
+			return doc, nil
+		}
+	}
+}
+
+// checkUniqueIndex returns an error if `on` fields other than `_id`
+// are not covered by a unique index of the output collection.
+func (m *merge) checkUniqueIndex(ctx context.Context, c backends.Collection) error {
+	if len(m.on) == 1 && m.on[0] == "_id" {
+		return nil
+	}
+
+	noIndexErr := handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrStageMergeNoUniqueIndex,
+		"Cannot find index to verify that join fields will be unique",
+		"$merge (stage)",
+	)
+
+	res, err := c.ListIndexes(ctx, nil)
+	if err != nil {
+		if backends.ErrorCodeIs(err, backends.ErrorCodeCollectionDoesNotExist) {
+			return noIndexErr
+		}
+
+		return lazyerrors.Error(err)
+	}
+
+	for _, index := range res.Indexes {
+		if !index.Unique || len(index.Key) != len(m.on) {
+			continue
+		}
+
+		covered := true
+
+		for _, key := range index.Key {
+			if !slices.Contains(m.on, key.Field) {
+				covered = false
+				break
+			}
+		}
+
+		if covered {
+			return nil
+		}
+	}
+
+	return noIndexErr
+}
+
+// runPipeline runs `whenMatched` pipeline on the copy of the matched target document
+// with `$$new` variable set to the given document, unless `let` defines it.
+func (m *merge) runPipeline(ctx context.Context, doc, target *types.Document) (*types.Document, error) {
+	vars := aggregations.GetVariables(ctx)
+
+	letVars, err := evaluateVariables("$merge", m.let, doc, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := letVars["new"]; !ok {
+		letVars["new"] = doc
+	}
+
+	ctx = aggregations.CtxWithVariables(ctx, vars.With(letVars))
+
+	closer := iterator.NewMultiCloser()
+	defer closer.Close()
+
+	iter := iterator.Values(iterator.ForSlice([]*types.Document{target.DeepCopy()}))
+	closer.Add(iter)
+
+	for _, s := range m.stages {
+		if iter, err = s.Process(ctx, iter, closer); err != nil {
+			return nil, err
+		}
+	}
+
+	res, err := iterator.ConsumeValues(iter)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if len(res) != 1 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			fmt.Sprintf("$merge 'whenMatched' pipeline must produce exactly one document, got %d", len(res)),
+			"$merge (stage)",
+		)
+	}
+
+	return res[0], nil
+}
+
+// newPipelineStages creates $merge `whenMatched` pipeline stages.
+//
+// They are created once; variables are passed to them with the context.
+func (m *merge) newPipelineStages() ([]aggregations.Stage, error) {
+	res := make([]aggregations.Stage, len(m.pipeline))
+
+	for i, d := range m.pipeline {
+		s, err := newPipelineStage(d, m.params)
+		if err != nil {
+			return nil, err
+		}
+
+		res[i] = s
+	}
+
+	return res, nil
+}
+
+// mergeOn returns $merge `on` fields.
+func mergeOn(v any) ([]string, error) {
+	invalidErr := handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrTypeMismatch,
+		fmt.Sprintf(
+			"$merge 'on' field must be either a string or an array of strings, but found %s",
+			handlerparams.AliasFromType(v),
+		),
+		"$merge (stage)",
+	)
+
+	var res []string
+
+	switch v := v.(type) {
+	case string:
+		res = []string{v}
+
+	case *types.Array:
+		if v.Len() == 0 {
+			return nil, invalidErr
+		}
+
+		res = make([]string, 0, v.Len())
+
+		for i := 0; i < v.Len(); i++ {
+			field, ok := must.NotFail(v.Get(i)).(string)
+			if !ok {
+				return nil, invalidErr
+			}
+
+			if slices.Contains(res, field) {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrBadValue,
+					fmt.Sprintf("Found a duplicate field '%s'", field),
+					"$merge (stage)",
+				)
+			}
+
+			res = append(res, field)
+		}
+
+	default:
+		return nil, invalidErr
+	}
+
+	for _, field := range res {
+		if _, err := types.NewPathFromString(field); err != nil {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrBadValue,
+				fmt.Sprintf("$merge 'on' field must be a valid field path, got %q", field),
+				"$merge (stage)",
+			)
+		}
+	}
+
+	return res, nil
+}
+
+// mergeFieldTypeError returns an error for $merge field of unexpected type.
+func mergeFieldTypeError(field, expected string, v any) error {
+	return handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrTypeMismatch,
+		fmt.Sprintf(
+			"BSON field '$merge.%s' is the wrong type '%s', expected type '%s'",
+			field, handlerparams.AliasFromType(v), expected,
+		),
+		"$merge (stage)",
+	)
+}
+
+// mergeEnumerationError returns an error for invalid $merge mode.
+func mergeEnumerationError(field, v string) error {
+	return handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrBadValue,
+		fmt.Sprintf("Enumeration value '%s' for field '$merge.%s' is not a valid value.", v, field),
+		"$merge (stage)",
+	)
+}
+
+// check interfaces
+var (
+	_ aggregations.Stage = (*merge)(nil)
+)
@@ -0,0 +1,389 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stages
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// out represents $out stage.
+//
+//	{ $out: <output collection> }
+//	{ $out: { db: <output database>, coll: <output collection> } }
+type out struct {
+	params *NewStageParams
+	db     backends.Database
+	dbName string
+	cName  string
+}
+
+// newOut validates stage document and creates a new $out stage.
+func newOut(stage *types.Document, params *NewStageParams) (aggregations.Stage, error) {
+	v := must.NotFail(stage.Get("$out"))
+
+	o := &out{
+		params: params,
+		dbName: params.DBName,
+	}
+
+	switch v := v.(type) {
+	case string:
+		o.cName = v
+
+	case *types.Document:
+		var err error
+		if o.dbName, o.cName, err = outputNamespace(v, "$out", ""); err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrStageOutInvalidArg,
+			fmt.Sprintf("$out only supports a string or object argument, not %s", handlerparams.AliasFromType(v)),
+			"$out (stage)",
+		)
+	}
+
+	var err error
+	if o.db, err = outputDatabase(params, o.dbName, o.cName, "$out"); err != nil {
+		return nil, err
+	}
+
+	return o, nil
+}
+
+// Process implements Stage interface.
+//
+// It writes all documents to a temporary collection
+// and then replaces the output collection with it.
+// It returns no documents.
+func (o *out) Process(ctx context.Context, iter types.DocumentsIterator, closer *iterator.MultiCloser) (types.DocumentsIterator, error) { //nolint:lll // for readability
+	docs, err := iterator.ConsumeValues(iter)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	for _, doc := range docs {
+		if err = prepareOutputDocument(doc); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = o.write(ctx, docs); err != nil {
+		return nil, err
+	}
+
+	outIter := iterator.Values(iterator.ForSlice([]*types.Document{}))
+	closer.Add(outIter)
+
+	return outIter, nil
+}
+
+// write replaces the output collection with a new collection containing given documents.
+//
+// Indexes and validation options of the existing output collection are preserved,
+// and documents are validated unless bypass is requested.
+// Capped output collections are not supported, like in MongoDB.
+func (o *out) write(ctx context.Context, docs []*types.Document) (err error) {
+	list, err := o.db.ListCollections(ctx, &backends.ListCollectionsParams{Name: o.cName})
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	tmpName := "tmp.agg_out." + uuid.NewString()
+	createParams := &backends.CreateCollectionParams{Name: tmpName}
+
+	var info *backends.CollectionInfo
+
+	if len(list.Collections) > 0 {
+		info = &list.Collections[0]
+
+		if info.Capped() {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrStageOutCappedCollection,
+				fmt.Sprintf("namespace '%s.%s' is capped so it can't be used for $out", o.dbName, o.cName),
+				"$out (stage)",
+			)
+		}
+
+		createParams.Validator = info.Validator
+		createParams.ValidationLevel = info.ValidationLevel
+		createParams.ValidationAction = info.ValidationAction
+	}
+
+	if v := outputValidator(info, o.params); v != nil {
+		for _, doc := range docs {
+			if err = validateOutputDocument(v, doc, "$out"); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err = o.db.CreateCollection(ctx, createParams); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	defer func() {
+		if err == nil {
+			return
+		}
+
+		// drop temporary collection on error; the error of dropping is not important
+		_ = o.db.DropCollection(context.WithoutCancel(ctx), &backends.DropCollectionParams{Name: tmpName})
+	}()
+
+	tmp, err := o.db.Collection(tmpName)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	c, err := o.db.Collection(o.cName)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	indexes, err := c.ListIndexes(ctx, nil)
+	if err != nil {
+		if !backends.ErrorCodeIs(err, backends.ErrorCodeCollectionDoesNotExist) {
+			return lazyerrors.Error(err)
+		}
+
+		indexes = new(backends.ListIndexesResult)
+	}
+
+	createIndexes := make([]backends.IndexInfo, 0, len(indexes.Indexes))
+
+	for _, index := range indexes.Indexes {
+		if index.Name == "_id_" {
+			continue
+		}
+
+		createIndexes = append(createIndexes, index)
+	}
+
+	if len(createIndexes) > 0 {
+		if _, err = tmp.CreateIndexes(ctx, &backends.CreateIndexesParams{Indexes: createIndexes}); err != nil {
+			return lazyerrors.Error(err)
+		}
+	}
+
+	if len(docs) > 0 {
+		if _, err = tmp.InsertAll(ctx, &backends.InsertAllParams{Docs: docs}); err != nil {
+			return outputWriteError(err, "$out")
+		}
+	}
+
+	// the existing collection is replaced atomically, and kept if renaming fails
+	err = o.db.RenameCollection(ctx, &backends.RenameCollectionParams{
+		OldName:    tmpName,
+		NewName:    o.cName,
+		DropTarget: true,
+	})
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
+// outputNamespace returns database and collection names from `{db: <db>, coll: <coll>}` document
+// of output stages like `$out` and `$merge`.
+//
+// If defaultDBName is empty, `db` field is required.
+func outputNamespace(doc *types.Document, stage, defaultDBName string) (string, string, error) {
+	dbName, cName := defaultDBName, ""
+
+	iter := doc.Iterator()
+	defer iter.Close()
+
+	for {
+		k, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return "", "", lazyerrors.Error(err)
+		}
+
+		var s string
+		var ok bool
+
+		switch k {
+		case "db", "coll":
+			if s, ok = v.(string); !ok {
+				return "", "", handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrTypeMismatch,
+					fmt.Sprintf(
+						"BSON field '%s.%s' is the wrong type '%s', expected type 'string'",
+						stage, k, handlerparams.AliasFromType(v),
+					),
+					stage+" (stage)",
+				)
+			}
+
+			if k == "db" {
+				dbName = s
+			} else {
+				cName = s
+			}
+
+		default:
+			return "", "", handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrFailedToParseInput,
+				fmt.Sprintf("BSON field '%s.%s' is an unknown field.", stage, k),
+				stage+" (stage)",
+			)
+		}
+	}
+
+	for _, f := range []struct {
+		name  string
+		value string
+	}{
+		{"db", dbName},
+		{"coll", cName},
+	} {
+		if f.value == "" {
+			return "", "", handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrMissingField,
+				fmt.Sprintf("BSON field '%s.%s' is missing but a required field", stage, f.name),
+				stage+" (stage)",
+			)
+		}
+	}
+
+	return dbName, cName, nil
+}
+
+// outputDatabase validates output namespace of stages like `$out` and `$merge`
+// and returns the output database.
+func outputDatabase(params *NewStageParams, dbName, cName, stage string) (backends.Database, error) {
+	invalidNamespaceErr := handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrInvalidNamespace,
+		fmt.Sprintf("Invalid %s target namespace: %s.%s", stage, dbName, cName),
+		stage+" (stage)",
+	)
+
+	db := params.Database
+
+	if dbName != params.DBName {
+		var err error
+		if db, err = params.Backend.Database(dbName); err != nil {
+			if backends.ErrorCodeIs(err, backends.ErrorCodeDatabaseNameIsInvalid) {
+				return nil, invalidNamespaceErr
+			}
+
+			return nil, lazyerrors.Error(err)
+		}
+	}
+
+	if _, err := db.Collection(cName); err != nil {
+		if backends.ErrorCodeIs(err, backends.ErrorCodeCollectionNameIsInvalid) {
+			return nil, invalidNamespaceErr
+		}
+
+		return nil, lazyerrors.Error(err)
+	}
+
+	return db, nil
+}
+
+// prepareOutputDocument sets _id of the document written by stages like `$out` and `$merge` if needed,
+// and validates it.
+func prepareOutputDocument(doc *types.Document) error {
+	if !doc.Has("_id") {
+		doc.Set("_id", types.NewObjectID())
+	}
+
+	err := doc.ValidateData()
+	if err == nil {
+		return nil
+	}
+
+	var ve *types.ValidationError
+	if !errors.As(err, &ve) {
+		return lazyerrors.Error(err)
+	}
+
+	code := handlererrors.ErrBadValue
+	if ve.Code() == types.ErrWrongIDType {
+		code = handlererrors.ErrInvalidID
+	}
+
+	return handlererrors.NewCommandErrorMsg(code, ve.Error())
+}
+
+// outputValidator returns a validator of the output collection of stages like `$out` and `$merge`.
+//
+// It returns nil if the collection does not exist, has no validator, or bypass is requested.
+func outputValidator(info *backends.CollectionInfo, params *NewStageParams) *common.DocumentValidator {
+	if params.BypassDocumentValidation {
+		return nil
+	}
+
+	return common.NewDocumentValidator(info, params.L)
+}
+
+// validateOutputDocument returns DocumentValidationFailure error
+// if the document written by stages like `$out` and `$merge` does not match the validator.
+func validateOutputDocument(v *common.DocumentValidator, doc *types.Document, stage string) error {
+	ok, err := v.Match(doc)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	if !ok {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrDocumentValidationFailure,
+			"Document failed validation",
+			stage+" (stage)",
+		)
+	}
+
+	return nil
+}
+
+// outputWriteError converts backend write error of stages like `$out` and `$merge` to command error.
+func outputWriteError(err error, stage string) error {
+	if backends.ErrorCodeIs(err, backends.ErrorCodeInsertDuplicateID) {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrDuplicateKeyInsert,
+			fmt.Sprintf("%s write error: E11000 duplicate key error", stage),
+			stage+" (stage)",
+		)
+	}
+
+	return lazyerrors.Error(err)
+}
+
+// check interfaces
+var (
+	_ aggregations.Stage = (*out)(nil)
+)
@@ -0,0 +1,208 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stages
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations/operators"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// partitionKeyFunc returns the partition key of the given document.
+// Expressions are evaluated with the given scope of variables.
+type partitionKeyFunc func(doc *types.Document, vars *aggregations.Variables) (any, error)
+
+// partitionDocuments groups documents by the partition key.
+// Partitions are returned sorted by the partition key; documents of each partition keep their order.
+//
+// If key is nil, all documents are in a single partition.
+func partitionDocuments(docs []*types.Document, key partitionKeyFunc, vars *aggregations.Variables) ([][]*types.Document, error) { //nolint:lll // for readability
+	if key == nil {
+		if len(docs) == 0 {
+			return nil, nil
+		}
+
+		return [][]*types.Document{docs}, nil
+	}
+
+	type partition struct {
+		key  any
+		docs []*types.Document
+	}
+
+	var partitions []*partition
+
+	// buckets maps hashes of partition keys to indexes of partitions
+	buckets := map[string][]int{}
+
+	for _, doc := range docs {
+		k, err := key(doc, vars)
+		if err != nil {
+			return nil, err
+		}
+
+		hash := groupKeyHash(k)
+
+		var p *partition
+
+		for _, i := range buckets[hash] {
+			if types.CompareForAggregation(k, partitions[i].key) == types.Equal {
+				p = partitions[i]
+				break
+			}
+		}
+
+		if p == nil {
+			p = &partition{key: k}
+			buckets[hash] = append(buckets[hash], len(partitions))
+			partitions = append(partitions, p)
+		}
+
+		p.docs = append(p.docs, doc)
+	}
+
+	slices.SortStableFunc(partitions, func(a, b *partition) int {
+		return int(types.CompareOrder(a.key, b.key, types.Ascending))
+	})
+
+	res := make([][]*types.Document, len(partitions))
+	for i, p := range partitions {
+		res[i] = p.docs
+	}
+
+	return res, nil
+}
+
+// partitionByExpression returns a function that evaluates the partitionBy expression of the given stage.
+//
+// Missing value is the same as null; arrays are not allowed.
+func partitionByExpression(stage string, expr any) partitionKeyFunc {
+	return func(doc *types.Document, vars *aggregations.Variables) (any, error) {
+		key, err := operators.Evaluate(expr, doc, vars)
+		if err != nil {
+			return nil, processStageOperatorError(stage, err)
+		}
+
+		switch key.(type) {
+		case nil:
+			return types.Null, nil
+		case *types.Array:
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrBadValue,
+				"An expression used to partition cannot evaluate to value of type array",
+				stage+" (stage)",
+			)
+		}
+
+		return key, nil
+	}
+}
+
+// partitionByFields returns a function that returns values of the given fields as an array.
+//
+// Missing fields are null.
+func partitionByFields(fields []types.Path) partitionKeyFunc {
+	return func(doc *types.Document, _ *aggregations.Variables) (any, error) {
+		return partitionFieldsValues(doc, fields), nil
+	}
+}
+
+// partitionFieldsValues returns values of the given fields of the document as an array.
+//
+// Missing fields are null.
+func partitionFieldsValues(doc *types.Document, fields []types.Path) *types.Array {
+	res := types.MakeArray(len(fields))
+
+	for _, f := range fields {
+		v, err := doc.GetByPath(f)
+		if err != nil {
+			v = types.Null
+		}
+
+		res.Append(v)
+	}
+
+	return res
+}
+
+// getPartitionByFields returns paths of the partitionByFields argument of the given stage.
+func getPartitionByFields(stage string, v any) ([]types.Path, error) {
+	arr, ok := v.(*types.Array)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrTypeMismatch,
+			fmt.Sprintf(
+				"BSON field '%s.partitionByFields' is the wrong type '%s', expected type 'array'",
+				stage, handlerparams.AliasFromType(v),
+			),
+			stage+" (stage)",
+		)
+	}
+
+	res := make([]types.Path, arr.Len())
+
+	for i := range res {
+		v := must.NotFail(arr.Get(i))
+
+		s, ok := v.(string)
+		if !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrTypeMismatch,
+				fmt.Sprintf(
+					"BSON field '%s.partitionByFields.%d' is the wrong type '%s', expected type 'string'",
+					stage, i, handlerparams.AliasFromType(v),
+				),
+				stage+" (stage)",
+			)
+		}
+
+		path, err := fieldPath(stage, s)
+		if err != nil {
+			return nil, err
+		}
+
+		res[i] = path
+	}
+
+	return res, nil
+}
+
+// fieldPath returns the path of the field argument of the given stage.
+func fieldPath(stage, field string) (types.Path, error) {
+	if len(field) > 0 && field[0] == '$' {
+		return types.Path{}, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFieldPathInvalidName,
+			"FieldPath field names may not start with '$'. Consider using $getField or $setField.",
+			stage+" (stage)",
+		)
+	}
+
+	path, err := types.NewPathFromString(field)
+	if err != nil {
+		return types.Path{}, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrPathContainsEmptyElement,
+			"FieldPath field names may not be empty strings.",
+			stage+" (stage)",
+		)
+	}
+
+	return path, nil
+}
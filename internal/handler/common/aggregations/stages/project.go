@@ -39,7 +39,7 @@ type project struct {
 }
 
 // newProject validates projection document and creates a new $project stage.
-func newProject(stage *types.Document) (aggregations.Stage, error) {
+func newProject(stage *types.Document, _ *NewStageParams) (aggregations.Stage, error) {
 	fields, err := common.GetRequiredParam[*types.Document](stage, "$project")
 	if err != nil {
 		return nil, handlererrors.NewCommandErrorMsgWithArgument(
@@ -63,8 +63,8 @@ func newProject(stage *types.Document) (aggregations.Stage, error) {
 // Process implements Stage interface.
 //
 //nolint:lll // for readability
-func (p *project) Process(_ context.Context, iter types.DocumentsIterator, closer *iterator.MultiCloser) (types.DocumentsIterator, error) {
-	return projection.ProjectionIterator(iter, closer, p.projection)
+func (p *project) Process(ctx context.Context, iter types.DocumentsIterator, closer *iterator.MultiCloser) (types.DocumentsIterator, error) { //nolint:lll // for readability
+	return projection.ProjectionIterator(iter, closer, p.projection, aggregations.GetVariables(ctx))
 }
 
 // check interfaces
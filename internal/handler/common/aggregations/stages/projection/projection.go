@@ -163,9 +163,9 @@ func ValidateProjection(projection *types.Document) (*types.Document, bool, erro
 				return nil, false, err
 			}
 
-			_, err = op.Process(must.NotFail(types.NewDocument("key", "value")))
-			if err = processOperatorError(err); err != nil {
-				return nil, false, err
+			_, err = op.Process(must.NotFail(types.NewDocument("key", "value")), nil)
+			if err != nil && !operators.IsEvaluationError(err) {
+				return nil, false, processOperatorError(err)
 			}
 
 			// validate operators later
@@ -173,12 +173,23 @@ func ValidateProjection(projection *types.Document) (*types.Document, bool, erro
 
 			result = true
 
-		case *types.Array, string, types.Binary, types.ObjectID,
-			time.Time, types.NullType, types.Regex, types.Timestamp: // all this types are treated as new fields value
+		case string:
+			if err := operators.ValidateExpression(value); err != nil {
+				return nil, false, processOperatorError(err)
+			}
+
+			// strings are evaluated as expressions later
+			validated.Set(key, value)
+
+			result = true
+
+		case *types.Array, types.Binary, types.ObjectID,
+			time.Time, types.NullType, types.Regex, types.Timestamp,
+			types.MinKeyType, types.MaxKeyType: // all this types are treated as new fields value
 			result = true
 
 			validated.Set(key, value)
-		case float64, int32, int64:
+		case float64, int32, int64, types.Decimal128:
 			// projection treats 0 as false and any other value as true
 			comparison := types.Compare(value, int32(0))
 
@@ -237,7 +248,8 @@ func ValidateProjection(projection *types.Document) (*types.Document, bool, erro
 }
 
 // ProjectDocument applies projection to the copy of the document.
-func ProjectDocument(doc, projection *types.Document, inclusion bool) (*types.Document, error) {
+// Operators and expressions are evaluated with the given scope of variables.
+func ProjectDocument(doc, projection *types.Document, inclusion bool, vars *aggregations.Variables) (*types.Document, error) { //nolint:lll // for readability
 	projected, err := types.NewDocument("_id", must.NotFail(doc.Get("_id")))
 	if err != nil {
 		return nil, err
@@ -265,16 +277,35 @@ func ProjectDocument(doc, projection *types.Document, inclusion bool) (*types.Do
 				return nil, processOperatorError(err)
 			}
 
-			value, err = op.Process(doc)
+			value, err = op.Process(doc, vars)
 			if err != nil {
 				return nil, err
 			}
 
 			set = true
-			projected.Set("_id", value)
 
-		case *types.Array, string, types.Binary, types.ObjectID,
-			time.Time, types.NullType, types.Regex, types.Timestamp: // all this types are treated as new fields value
+			// missing value is not set
+			if value != nil {
+				projected.Set("_id", value)
+			}
+
+		case string:
+			var value any
+
+			if value, err = operators.Evaluate(idValue, doc, vars); err != nil {
+				return nil, err
+			}
+
+			set = true
+
+			// missing value is not set
+			if value != nil {
+				projected.Set("_id", value)
+			}
+
+		case *types.Array, types.Binary, types.ObjectID,
+			time.Time, types.NullType, types.Regex, types.Timestamp,
+			types.MinKeyType, types.MaxKeyType: // all this types are treated as new fields value
 			projected.Set("_id", idValue)
 
 			set = true
@@ -291,7 +322,7 @@ func ProjectDocument(doc, projection *types.Document, inclusion bool) (*types.Do
 		}
 	}
 
-	projectedWithoutID, err := projectDocumentWithoutID(doc, projection, inclusion)
+	projectedWithoutID, err := projectDocumentWithoutID(doc, projection, inclusion, vars)
 	if err != nil {
 		// TODO https://github.com/FerretDB/FerretDB/issues/2633
 		return nil, err
@@ -306,7 +337,7 @@ func ProjectDocument(doc, projection *types.Document, inclusion bool) (*types.Do
 
 // projectDocumentWithoutID applies projection to the copy of the document and returns projected document.
 // It ignores _id field in the projection.
-func projectDocumentWithoutID(doc *types.Document, projection *types.Document, inclusion bool) (*types.Document, error) {
+func projectDocumentWithoutID(doc *types.Document, projection *types.Document, inclusion bool, vars *aggregations.Variables) (*types.Document, error) { //nolint:lll // for readability
 	projectionWithoutID := projection.DeepCopy()
 	projectionWithoutID.Remove("_id")
 
@@ -353,15 +384,31 @@ func projectDocumentWithoutID(doc *types.Document, projection *types.Document, i
 				return nil, processOperatorError(err)
 			}
 
-			v, err = op.Process(doc)
+			v, err = op.Process(doc, vars)
 			if err != nil {
 				return nil, err
 			}
 
-			projected.Set(key, v)
+			// missing value is not set
+			if v != nil {
+				projected.Set(key, v)
+			}
+
+		case string:
+			var v any
+
+			if v, err = operators.Evaluate(value, doc, vars); err != nil {
+				return nil, err
+			}
+
+			// missing value is not set
+			if v != nil {
+				projected.Set(key, v)
+			}
 
-		case *types.Array, string, types.Binary, types.ObjectID,
-			time.Time, types.NullType, types.Regex, types.Timestamp: // all these types are treated as new fields value
+		case *types.Array, types.Binary, types.ObjectID,
+			time.Time, types.NullType, types.Regex, types.Timestamp,
+			types.MinKeyType, types.MaxKeyType: // all these types are treated as new fields value
 			projected.Set(key, value)
 
 		case bool: // field: bool
@@ -632,6 +679,8 @@ func setBySourceOrder(key string, val any, source, projected *types.Document) {
 // - ErrInvalidPipelineOperator when the operator does not exist.
 // - ErrFailedToParse when operator has invalid variable expression.
 // - ErrGroupInvalidFieldPath when operator has empty path expression.
+//
+// Command errors returned while parsing operator arguments keep their codes.
 func processOperatorError(err error) error {
 	if err == nil {
 		return nil
@@ -639,6 +688,7 @@ func processOperatorError(err error) error {
 
 	var opErr operators.OperatorError
 	var exErr *aggregations.ExpressionError
+	var cmdErr *handlererrors.CommandError
 
 	switch {
 	case errors.As(err, &opErr):
@@ -694,10 +744,9 @@ func processOperatorError(err error) error {
 				"$project (stage)",
 			)
 		case aggregations.ErrUndefinedVariable:
-			// TODO https://github.com/FerretDB/FerretDB/issues/2275
 			return handlererrors.NewCommandErrorMsgWithArgument(
-				handlererrors.ErrNotImplemented,
-				"Aggregation expression variables are not implemented yet",
+				handlererrors.ErrGroupUndefinedVariable,
+				"Invalid $project :: caused by :: Use of undefined variable: "+exErr.Name(),
 				"$project (stage)",
 			)
 		case aggregations.ErrEmptyVariable:
@@ -707,6 +756,13 @@ func processOperatorError(err error) error {
 				"$project (stage)",
 			)
 		}
+
+	case errors.As(err, &cmdErr):
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			cmdErr.Code(),
+			"Invalid $project :: caused by :: "+cmdErr.Err().Error(),
+			"$project (stage)",
+		)
 	}
 
 	return lazyerrors.Error(err)
@@ -15,18 +15,20 @@
 package projection
 
 import (
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/iterator"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
 )
 
 // ProjectionIterator returns an iterator that projects documents returned by the underlying iterator.
+// Operators and expressions are evaluated with the given scope of variables.
 // It will be added to the given closer.
 //
 // Next method returns the next projected document.
 //
 // Close method closes the underlying iterator.
-func ProjectionIterator(iter types.DocumentsIterator, closer *iterator.MultiCloser, projection *types.Document) (types.DocumentsIterator, error) { //nolint:lll // for readability
+func ProjectionIterator(iter types.DocumentsIterator, closer *iterator.MultiCloser, projection *types.Document, vars *aggregations.Variables) (types.DocumentsIterator, error) { //nolint:lll // for readability
 	projectionValidated, inclusion, err := ValidateProjection(projection)
 	if err != nil {
 		return nil, err
@@ -36,6 +38,7 @@ func ProjectionIterator(iter types.DocumentsIterator, closer *iterator.MultiClos
 		iter:       iter,
 		projection: projectionValidated,
 		inclusion:  inclusion,
+		vars:       vars,
 	}
 	closer.Add(res)
 
@@ -47,6 +50,7 @@ type projectionIterator struct {
 	iter       types.DocumentsIterator
 	projection *types.Document
 	inclusion  bool
+	vars       *aggregations.Variables
 }
 
 // Next implements iterator.Interface. See ProjectionIterator for details.
@@ -58,7 +62,7 @@ func (iter *projectionIterator) Next() (struct{}, *types.Document, error) {
 		return unused, nil, lazyerrors.Error(err)
 	}
 
-	projected, err := ProjectDocument(doc, iter.projection, iter.inclusion)
+	projected, err := ProjectDocument(doc, iter.projection, iter.inclusion, iter.vars)
 	if err != nil {
 		return unused, nil, err
 	}
@@ -0,0 +1,186 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stages
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations/operators"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// redact represents $redact stage.
+//
+//	{ $redact: <expression> }
+//
+// The expression is evaluated for the document and then for each embedded document,
+// and should return one of:
+//   - `$$DESCEND` to keep fields of the document and evaluate expression for embedded documents;
+//   - `$$PRUNE` to exclude the document with all embedded documents;
+//   - `$$KEEP` to keep the document with all embedded documents.
+type redact struct {
+	expression any
+}
+
+// newRedact creates a new $redact stage.
+func newRedact(stage *types.Document, _ *NewStageParams) (aggregations.Stage, error) {
+	expression := must.NotFail(stage.Get("$redact"))
+
+	if err := operators.ValidateExpression(expression); err != nil {
+		return nil, processStageOperatorError("$redact", err)
+	}
+
+	return &redact{
+		expression: expression,
+	}, nil
+}
+
+// Process implements Stage interface.
+func (r *redact) Process(ctx context.Context, iter types.DocumentsIterator, closer *iterator.MultiCloser) (types.DocumentsIterator, error) { //nolint:lll // for readability
+	vars := aggregations.GetVariables(ctx)
+
+	redactIter := iterator.ForFunc(func() (struct{}, *types.Document, error) {
+		var unused struct{}
+
+		for {
+			_, doc, err := iter.Next()
+			if err != nil {
+				return unused, nil, lazyerrors.Error(err)
+			}
+
+			res, err := r.redactDocument(doc, vars.With(map[string]any{"ROOT": doc}))
+			if err != nil {
+				return unused, nil, err
+			}
+
+			// pruned documents are skipped
+			if res != nil {
+				return unused, res, nil
+			}
+		}
+	})
+	closer.Add(redactIter)
+
+	return redactIter, nil
+}
+
+// redactDocument returns the redacted document, or nil if it was pruned.
+//
+// The expression is evaluated with `$$CURRENT` set to the document
+// and the given scope of variables that sets `$$ROOT` to the root document.
+func (r *redact) redactDocument(doc *types.Document, vars *aggregations.Variables) (*types.Document, error) {
+	v, err := operators.Evaluate(r.expression, doc, vars)
+	if err != nil {
+		return nil, processStageOperatorError("$redact", err)
+	}
+
+	switch v {
+	case "keep":
+		return doc, nil
+
+	case "prune":
+		return nil, nil
+
+	case "descend":
+		res := types.MakeDocument(doc.Len())
+
+		iter := doc.Iterator()
+		defer iter.Close()
+
+		for {
+			k, v, err := iter.Next()
+			if errors.Is(err, iterator.ErrIteratorDone) {
+				break
+			}
+
+			if err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+
+			if v, err = r.redactValue(v, vars); err != nil {
+				return nil, err
+			}
+
+			if v != nil {
+				res.Set(k, v)
+			}
+		}
+
+		return res, nil
+
+	default:
+		value := "MISSING"
+		if v != nil {
+			value = types.FormatAnyValue(v)
+		}
+
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrRedactInvalidResult,
+			fmt.Sprintf(
+				"$redact's expression should not return anything aside from the variables "+
+					"$$KEEP, $$DESCEND, and $$PRUNE, but returned %s",
+				value,
+			),
+			"$redact (stage)",
+		)
+	}
+}
+
+// redactValue returns the redacted field value, or nil if it was pruned.
+//
+// Embedded documents (including documents in arrays) are redacted, other values are kept as is.
+func (r *redact) redactValue(v any, vars *aggregations.Variables) (any, error) {
+	switch v := v.(type) {
+	case *types.Document:
+		doc, err := r.redactDocument(v, vars)
+		if doc == nil || err != nil {
+			// avoid returning typed nil
+			return nil, err
+		}
+
+		return doc, nil
+
+	case *types.Array:
+		res := types.MakeArray(v.Len())
+
+		for i := 0; i < v.Len(); i++ {
+			elem, err := r.redactValue(must.NotFail(v.Get(i)), vars)
+			if err != nil {
+				return nil, err
+			}
+
+			if elem != nil {
+				res.Append(elem)
+			}
+		}
+
+		return res, nil
+
+	default:
+		return v, nil
+	}
+}
+
+// check interfaces
+var (
+	_ aggregations.Stage = (*redact)(nil)
+)
@@ -0,0 +1,165 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stages
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations/operators"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// replaceRoot represents $replaceRoot and $replaceWith stages.
+//
+//	{ $replaceRoot: { newRoot: <replacementDocument> } }
+//	{ $replaceWith: <replacementDocument> }
+//
+// Each input document is replaced with the evaluated expression which must be a document.
+type replaceRoot struct {
+	newRoot any
+	stage   string // $replaceRoot or $replaceWith
+	field   string // name of the expression used in error messages, as in MongoDB
+}
+
+// newReplaceRoot creates a new $replaceRoot stage.
+func newReplaceRoot(stage *types.Document, _ *NewStageParams) (aggregations.Stage, error) {
+	v := must.NotFail(stage.Get("$replaceRoot"))
+
+	fields, ok := v.(*types.Document)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			fmt.Sprintf(
+				"expected an object as specification for $replaceRoot stage, got %s",
+				handlerparams.AliasFromType(v),
+			),
+			"$replaceRoot (stage)",
+		)
+	}
+
+	var newRoot any
+
+	iter := fields.Iterator()
+	defer iter.Close()
+
+	for {
+		k, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if k != "newRoot" {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrFailedToParseInput,
+				fmt.Sprintf("BSON field '$replaceRoot.%s' is an unknown field.", k),
+				"$replaceRoot (stage)",
+			)
+		}
+
+		newRoot = v
+	}
+
+	if newRoot == nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrMissingField,
+			"BSON field '$replaceRoot.newRoot' is missing but a required field",
+			"$replaceRoot (stage)",
+		)
+	}
+
+	if err := operators.ValidateExpression(newRoot); err != nil {
+		return nil, processStageOperatorError("$replaceRoot", err)
+	}
+
+	return &replaceRoot{
+		newRoot: newRoot,
+		stage:   "$replaceRoot",
+		field:   "'newRoot' expression ",
+	}, nil
+}
+
+// newReplaceWith creates a new $replaceWith stage.
+func newReplaceWith(stage *types.Document, _ *NewStageParams) (aggregations.Stage, error) {
+	newRoot := must.NotFail(stage.Get("$replaceWith"))
+
+	if err := operators.ValidateExpression(newRoot); err != nil {
+		return nil, processStageOperatorError("$replaceWith", err)
+	}
+
+	return &replaceRoot{
+		newRoot: newRoot,
+		stage:   "$replaceWith",
+		field:   "'replacement document'",
+	}, nil
+}
+
+// Process implements Stage interface.
+func (r *replaceRoot) Process(ctx context.Context, iter types.DocumentsIterator, closer *iterator.MultiCloser) (types.DocumentsIterator, error) { //nolint:lll // for readability
+	vars := aggregations.GetVariables(ctx)
+
+	replaceIter := iterator.ForFunc(func() (struct{}, *types.Document, error) {
+		var unused struct{}
+
+		_, doc, err := iter.Next()
+		if err != nil {
+			return unused, nil, lazyerrors.Error(err)
+		}
+
+		v, err := operators.Evaluate(r.newRoot, doc, vars)
+		if err != nil {
+			return unused, nil, processStageOperatorError(r.stage, err)
+		}
+
+		res, ok := v.(*types.Document)
+		if !ok {
+			value, typ := "MISSING", "missing"
+			if v != nil {
+				value, typ = types.FormatAnyValue(v), handlerparams.AliasFromType(v)
+			}
+
+			return unused, nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrReplaceRootNotDocument,
+				fmt.Sprintf(
+					"%s must evaluate to an object, but resulting value was: %s. "+
+						"Type of resulting value: '%s'. Input document: %s",
+					r.field, value, typ, types.FormatAnyValue(doc),
+				),
+				r.stage+" (stage)",
+			)
+		}
+
+		return unused, res, nil
+	})
+	closer.Add(replaceIter)
+
+	return replaceIter, nil
+}
+
+// check interfaces
+var (
+	_ aggregations.Stage = (*replaceRoot)(nil)
+)
@@ -0,0 +1,201 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stages
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// sample represents $sample stage.
+//
+//	{ $sample: { size: <positive integer N> } }
+//
+// $sample returns up to size randomly selected documents in random order.
+// Documents are selected with reservoir sampling, so only size documents are kept in memory.
+// If $sample is the first stage, the backend could also select random documents;
+// see GetPushdownSample.
+type sample struct {
+	size int64
+}
+
+// newSample creates a new $sample stage.
+func newSample(stage *types.Document, _ *NewStageParams) (aggregations.Stage, error) {
+	fields, ok := must.NotFail(stage.Get("$sample")).(*types.Document)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSampleBadSpecification,
+			"the $sample stage specification must be an object",
+			"$sample (stage)",
+		)
+	}
+
+	var size *int64
+
+	iter := fields.Iterator()
+	defer iter.Close()
+
+	for {
+		k, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if k != "size" {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrSampleUnknownOption,
+				fmt.Sprintf("unrecognized option to $sample: %s", k),
+				"$sample (stage)",
+			)
+		}
+
+		s, err := getSampleSize(v)
+		if err != nil {
+			return nil, err
+		}
+
+		size = &s
+	}
+
+	if size == nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSampleMissingSize,
+			"$sample stage must specify a size",
+			"$sample (stage)",
+		)
+	}
+
+	return &sample{
+		size: *size,
+	}, nil
+}
+
+// getSampleSize returns the size argument of $sample stage.
+// Non-integer numbers are truncated, and out of range values are clamped.
+func getSampleSize(v any) (int64, error) {
+	var size int64
+
+	switch v := v.(type) {
+	case int32:
+		size = int64(v)
+	case int64:
+		size = v
+	case float64:
+		size = float64ToInt64(v)
+	case types.Decimal128:
+		size = float64ToInt64(v.Float64())
+	default:
+		return 0, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSampleSizeNotNumber,
+			"size argument to $sample must be a number",
+			"$sample (stage)",
+		)
+	}
+
+	if size < 0 {
+		return 0, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSampleSizeNegative,
+			"size argument to $sample must not be negative",
+			"$sample (stage)",
+		)
+	}
+
+	return size, nil
+}
+
+// float64ToInt64 truncates the given float to int64;
+// NaN is converted to 0, and out of range values are clamped.
+func float64ToInt64(f float64) int64 {
+	switch {
+	case math.IsNaN(f):
+		return 0
+	case f >= math.MaxInt64:
+		return math.MaxInt64
+	case f <= math.MinInt64:
+		return math.MinInt64
+	default:
+		return int64(f)
+	}
+}
+
+// Process implements Stage interface.
+func (s *sample) Process(ctx context.Context, iter types.DocumentsIterator, closer *iterator.MultiCloser) (types.DocumentsIterator, error) { //nolint:lll // for readability
+	var res []*types.Document
+
+	// Algorithm R: the i-th document replaces a random kept document with probability size/(i+1)
+	for i := int64(0); ; i++ {
+		_, doc, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if i < s.size {
+			res = append(res, doc)
+			continue
+		}
+
+		if j := rand.Int63n(i + 1); j < s.size {
+			res[j] = doc
+		}
+	}
+
+	// kept documents are partially ordered by their position in the input
+	rand.Shuffle(len(res), func(i, j int) {
+		res[i], res[j] = res[j], res[i]
+	})
+
+	iter = iterator.Values(iterator.ForSlice(res))
+	closer.Add(iter)
+
+	return iter, nil
+}
+
+// GetPushdownSample has the same idea as GetPushdownQuery: it returns the size of $sample stage
+// if it is the first stage, so random documents could be selected by the backend.
+// Otherwise, it returns 0.
+func GetPushdownSample(stages []aggregations.Stage) int64 {
+	if len(stages) == 0 {
+		return 0
+	}
+
+	if s, ok := stages[0].(*sample); ok {
+		return s.size
+	}
+
+	return 0
+}
+
+// check interfaces
+var (
+	_ aggregations.Stage = (*sample)(nil)
+)
@@ -36,7 +36,7 @@ type set struct {
 }
 
 // newSet validates stage document and creates a new $set stage.
-func newSet(stage *types.Document) (aggregations.Stage, error) {
+func newSet(stage *types.Document, _ *NewStageParams) (aggregations.Stage, error) {
 	fields, err := stage.Get("$set")
 	if err != nil {
 		return nil, lazyerrors.Error(err)
@@ -65,8 +65,8 @@ func newSet(stage *types.Document) (aggregations.Stage, error) {
 }
 
 // Process implements Stage interface.
-func (s *set) Process(_ context.Context, iter types.DocumentsIterator, closer *iterator.MultiCloser) (types.DocumentsIterator, error) { //nolint:lll // for readability
-	return common.AddFieldsIterator(iter, closer, s.newField), nil
+func (s *set) Process(ctx context.Context, iter types.DocumentsIterator, closer *iterator.MultiCloser) (types.DocumentsIterator, error) { //nolint:lll // for readability
+	return common.AddFieldsIterator(iter, closer, s.newField, aggregations.GetVariables(ctx)), nil
 }
 
 // check interfaces
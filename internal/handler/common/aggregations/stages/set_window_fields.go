@@ -0,0 +1,233 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stages
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations/operators"
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations/operators/windows"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// setWindowFields represents $setWindowFields stage.
+//
+//	{ $setWindowFields: {
+//		partitionBy: <expression>,
+//		sortBy: { <sortField>: <sortOrder>, ... },
+//		output: {
+//			<outputField>: { <windowOperator>: <argument>, window: { documents: [ <lower>, <upper> ] } },
+//			...
+//		}
+//	}}
+//
+// $setWindowFields groups documents into partitions by the evaluated partitionBy expression,
+// sorts documents of each partition by sortBy, and sets output fields to values of window operators.
+// Documents are returned sorted by the partition key, then by sortBy.
+type setWindowFields struct {
+	partitionBy any
+	sortBy      *types.Document
+	output      []windowOutput
+	collation   *types.Collation
+}
+
+// windowOutput represents the output field of $setWindowFields stage.
+type windowOutput struct {
+	path     types.Path
+	operator windows.Operator
+}
+
+// newSetWindowFields creates a new $setWindowFields stage.
+func newSetWindowFields(stage *types.Document, params *NewStageParams) (aggregations.Stage, error) {
+	fields, err := common.GetRequiredParam[*types.Document](stage, "$setWindowFields")
+	if err != nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			fmt.Sprintf(
+				"the $setWindowFields stage specification must be an object, found %s",
+				handlerparams.AliasFromType(must.NotFail(stage.Get("$setWindowFields"))),
+			),
+			"$setWindowFields (stage)",
+		)
+	}
+
+	s := &setWindowFields{
+		collation: params.Collation,
+	}
+
+	var output *types.Document
+
+	iter := fields.Iterator()
+	defer iter.Close()
+
+	for {
+		k, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		switch k {
+		case "partitionBy":
+			if err = operators.ValidateExpression(v); err != nil {
+				return nil, processStageOperatorError("$setWindowFields", err)
+			}
+
+			s.partitionBy = v
+
+		case "sortBy":
+			sortBy, ok := v.(*types.Document)
+			if !ok {
+				return nil, newSetWindowFieldsTypeError(k, v)
+			}
+
+			if s.sortBy, err = common.ValidateSortDocument(sortBy); err != nil {
+				return nil, err
+			}
+
+		case "output":
+			var ok bool
+			if output, ok = v.(*types.Document); !ok {
+				return nil, newSetWindowFieldsTypeError(k, v)
+			}
+
+		default:
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrFailedToParseInput,
+				fmt.Sprintf("BSON field '$setWindowFields.%s' is an unknown field.", k),
+				"$setWindowFields (stage)",
+			)
+		}
+	}
+
+	if output == nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrMissingField,
+			"BSON field '$setWindowFields.output' is missing but a required field",
+			"$setWindowFields (stage)",
+		)
+	}
+
+	if err = validateFieldPath("$setWindowFields", output); err != nil {
+		return nil, err
+	}
+
+	for _, field := range output.Keys() {
+		path, err := types.NewPathFromString(field)
+		if err != nil {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrPathContainsEmptyElement,
+				"FieldPath field names may not be empty strings.",
+				"$setWindowFields (stage)",
+			)
+		}
+
+		operator, err := windows.NewOperator(field, must.NotFail(output.Get(field)), s.sortBy)
+		if err != nil {
+			return nil, processStageOperatorError("$setWindowFields", err)
+		}
+
+		s.output = append(s.output, windowOutput{
+			path:     path,
+			operator: operator,
+		})
+	}
+
+	return s, nil
+}
+
+// newSetWindowFieldsTypeError returns an error for the field of $setWindowFields stage that is not a document.
+func newSetWindowFieldsTypeError(field string, value any) error {
+	return handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrTypeMismatch,
+		fmt.Sprintf(
+			"BSON field '$setWindowFields.%s' is the wrong type '%s', expected type 'object'",
+			field, handlerparams.AliasFromType(value),
+		),
+		"$setWindowFields (stage)",
+	)
+}
+
+// Process implements Stage interface.
+func (s *setWindowFields) Process(ctx context.Context, iter types.DocumentsIterator, closer *iterator.MultiCloser) (types.DocumentsIterator, error) { //nolint:lll // for readability
+	docs, err := iterator.ConsumeValues(iter)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	var key partitionKeyFunc
+	if s.partitionBy != nil {
+		key = partitionByExpression("$setWindowFields", s.partitionBy)
+	}
+
+	vars := aggregations.GetVariables(ctx)
+
+	partitions, err := partitionDocuments(docs, key, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*types.Document, 0, len(docs))
+
+	for _, partition := range partitions {
+		if s.sortBy != nil {
+			if err = common.SortDocumentsWithCollation(partition, s.sortBy, s.collation); err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+		}
+
+		// all output fields are computed before setting any of them,
+		// so window operators use original documents
+		values := make([][]any, len(s.output))
+
+		for i, output := range s.output {
+			if values[i], err = output.operator.Apply(partition, vars); err != nil {
+				return nil, processStageOperatorError("$setWindowFields", err)
+			}
+		}
+
+		for j, doc := range partition {
+			for i, output := range s.output {
+				if err = doc.SetByPath(output.path, values[i][j]); err != nil {
+					return nil, lazyerrors.Error(err)
+				}
+			}
+
+			res = append(res, doc)
+		}
+	}
+
+	iter = iterator.Values(iterator.ForSlice(res))
+	closer.Add(iter)
+
+	return iter, nil
+}
+
+// check interfaces
+var (
+	_ aggregations.Stage = (*setWindowFields)(nil)
+)
@@ -30,7 +30,7 @@ type skip struct {
 }
 
 // newSkip creates a new $skip stage.
-func newSkip(stage *types.Document) (aggregations.Stage, error) {
+func newSkip(stage *types.Document, _ *NewStageParams) (aggregations.Stage, error) {
 	value, err := stage.Get("$skip")
 	if err != nil {
 		return nil, lazyerrors.Error(err)
@@ -28,11 +28,12 @@ import (
 
 // sort represents $sort stage.
 type sort struct {
-	fields *types.Document
+	fields    *types.Document
+	collation *types.Collation
 }
 
 // newSort creates a new $sort stage.
-func newSort(stage *types.Document) (aggregations.Stage, error) {
+func newSort(stage *types.Document, params *NewStageParams) (aggregations.Stage, error) {
 	fields, err := common.GetRequiredParam[*types.Document](stage, "$sort")
 	if err != nil {
 		return nil, handlererrors.NewCommandErrorMsgWithArgument(
@@ -53,7 +54,8 @@ func newSort(stage *types.Document) (aggregations.Stage, error) {
 	// TODO https://github.com/FerretDB/FerretDB/issues/2090
 
 	return &sort{
-		fields: fields,
+		fields:    fields,
+		collation: params.Collation,
 	}, nil
 }
 
@@ -61,7 +63,7 @@ func newSort(stage *types.Document) (aggregations.Stage, error) {
 //
 // If sort path is invalid, it returns a possibly wrapped types.PathError.
 func (s *sort) Process(ctx context.Context, iter types.DocumentsIterator, closer *iterator.MultiCloser) (types.DocumentsIterator, error) { //nolint:lll // for readability
-	iter, err := common.SortIterator(iter, closer, s.fields)
+	iter, err := common.SortIterator(iter, closer, s.fields, s.collation)
 	if err != nil {
 		// TODO https://github.com/FerretDB/FerretDB/issues/3125
 		var pathErr *types.PathError
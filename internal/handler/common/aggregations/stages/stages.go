@@ -17,68 +17,123 @@ package stages
 
 import (
 	"fmt"
+	"log/slog"
 
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/clientconn/session"
 	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
 	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
 	"github.com/FerretDB/FerretDB/internal/types"
 )
 
 // newStageFunc is a type for a function that creates a new aggregation stage.
-type newStageFunc func(stage *types.Document) (aggregations.Stage, error)
+type newStageFunc func(stage *types.Document, params *NewStageParams) (aggregations.Stage, error)
+
+// NewStageParams contains the parameters for creating a new aggregation stage.
+//
+// Most stages process only the documents they receive and ignore them.
+// Stages that access other collections (like `$lookup`, `$out` and `$merge`) use them to get those collections.
+// Backend is the one used by the handler, so writes to collections go through its decorators (like oplog).
+type NewStageParams struct {
+	// Backend is the backend of the aggregated collection.
+	Backend backends.Backend
+
+	// Database is the database of the aggregated collection.
+	Database backends.Database
+
+	// DBName is the name of the database of the aggregated collection.
+	DBName string
+
+	// CollectionName is the name of the aggregated collection.
+	CollectionName string
+
+	// MaxBsonObjectSizeBytes is the maximum size of documents produced by stages (like `$facet`).
+	MaxBsonObjectSizeBytes int
+
+	// BypassDocumentValidation disables validation of documents written by stages like `$out` and `$merge`.
+	BypassDocumentValidation bool
+
+	// L is used by stages like `$out` and `$merge` to log documents that fail validation with warn action.
+	L *slog.Logger
+
+	// Sessions is the logical sessions registry used by `$listSessions` and `$listLocalSessions`.
+	Sessions *session.Registry
+
+	// Username is the name of the authenticated user.
+	Username string
+
+	// AllUsersSessions is true if the user could list sessions of all users.
+	AllUsersSessions bool
+
+	// Collation is used for string comparison by stages like `$match` and `$sort`.
+	// Nil collation compares strings as bytes.
+	Collation *types.Collation
+
+	// DisablePushdown disables queries pushdown to the backend by stages like `$graphLookup`.
+	DisablePushdown bool
+}
 
 // Stages maps all supported aggregation Stages.
 var Stages = map[string]newStageFunc{
 	// sorted alphabetically
-	"$addFields": newAddFields,
-	"$collStats": newCollStats,
-	"$count":     newCount,
-	"$group":     newGroup,
-	"$limit":     newLimit,
-	"$match":     newMatch,
-	"$project":   newProject,
-	"$set":       newSet,
-	"$skip":      newSkip,
-	"$sort":      newSort,
-	"$unset":     newUnset,
-	"$unwind":    newUnwind,
+	"$addFields":         newAddFields,
+	"$bucket":            newBucket,
+	"$bucketAuto":        newBucketAuto,
+	"$changeStream":      newChangeStream,
+	"$collStats":         newCollStats,
+	"$count":             newCount,
+	"$densify":           newDensify,
+	"$facet":             newFacet,
+	"$fill":              newFill,
+	"$graphLookup":       newGraphLookup,
+	"$group":             newGroup,
+	"$limit":             newLimit,
+	"$listLocalSessions": newListLocalSessions,
+	"$listSessions":      newListSessions,
+	"$lookup":            newLookup,
+	"$match":             newMatch,
+	"$merge":             newMerge,
+	"$out":               newOut,
+	"$project":           newProject,
+	"$redact":            newRedact,
+	"$replaceRoot":       newReplaceRoot,
+	"$replaceWith":       newReplaceWith,
+	"$sample":            newSample,
+	"$set":               newSet,
+	"$setWindowFields":   newSetWindowFields,
+	"$skip":              newSkip,
+	"$sort":              newSort,
+	"$sortByCount":       newSortByCount,
+	"$unionWith":         newUnionWith,
+	"$unset":             newUnset,
+	"$unwind":            newUnwind,
 	// please keep sorted alphabetically
 }
 
 // unsupportedStages maps all unsupported yet stages.
 var unsupportedStages = map[string]struct{}{
 	// sorted alphabetically
-	"$bucket":                 {},
-	"$bucketAuto":             {},
-	"$changeStream":           {},
 	"$currentOp":              {},
-	"$densify":                {},
 	"$documents":              {},
-	"$facet":                  {},
-	"$fill":                   {},
 	"$geoNear":                {},
-	"$graphLookup":            {},
 	"$indexStats":             {},
-	"$listLocalSessions":      {},
-	"$listSessions":           {},
-	"$lookup":                 {},
-	"$merge":                  {},
-	"$out":                    {},
 	"$planCacheStats":         {},
-	"$redact":                 {},
-	"$replaceRoot":            {},
-	"$replaceWith":            {},
-	"$sample":                 {},
 	"$search":                 {},
 	"$searchMeta":             {},
-	"$setWindowFields":        {},
 	"$sharedDataDistribution": {},
-	"$sortByCount":            {},
-	"$unionWith":              {},
 	// please keep sorted alphabetically
 }
 
+// newPipelineStage is NewStage for stages with sub-pipelines (like `$lookup` and `$facet`).
+// It is set in init to avoid initialization cycle with Stages.
+var newPipelineStage func(stage *types.Document, params *NewStageParams) (aggregations.Stage, error)
+
+func init() {
+	newPipelineStage = NewStage
+}
+
 // NewStage creates a new aggregation stage.
-func NewStage(stage *types.Document) (aggregations.Stage, error) {
+func NewStage(stage *types.Document, params *NewStageParams) (aggregations.Stage, error) {
 	if stage.Len() != 1 {
 		return nil, handlererrors.NewCommandErrorMsgWithArgument(
 			handlererrors.ErrStageInvalid,
@@ -97,7 +152,7 @@ func NewStage(stage *types.Document) (aggregations.Stage, error) {
 		panic(fmt.Sprintf("stage %q is in both `stages` and `unsupportedStages`", name))
 
 	case supported && !unsupported:
-		return f(stage)
+		return f(stage, params)
 
 	case !supported && unsupported:
 		return nil, handlererrors.NewCommandErrorMsgWithArgument(
@@ -0,0 +1,253 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stages
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// unionWith represents $unionWith stage.
+//
+//	{ $unionWith: <collection> }
+//	{ $unionWith: { coll: <collection>, pipeline: [ <stage1>, ... ] } }
+//
+// It returns all input documents, followed by documents of the given collection
+// processed by the optional pipeline.
+type unionWith struct {
+	params *NewStageParams
+	coll   string
+	stages []aggregations.Stage
+}
+
+// newUnionWith validates stage document and creates a new $unionWith stage.
+func newUnionWith(stage *types.Document, params *NewStageParams) (aggregations.Stage, error) {
+	u := &unionWith{
+		params: params,
+	}
+
+	var pipeline []*types.Document
+
+	switch v := must.NotFail(stage.Get("$unionWith")).(type) {
+	case string:
+		u.coll = v
+
+	case *types.Document:
+		var err error
+		if pipeline, err = u.parseSpec(v); err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			fmt.Sprintf(
+				"the $unionWith stage specification must be an object or string, but found %s",
+				handlerparams.AliasFromType(v),
+			),
+			"$unionWith (stage)",
+		)
+	}
+
+	if _, err := params.Database.Collection(u.coll); err != nil {
+		if backends.ErrorCodeIs(err, backends.ErrorCodeCollectionNameIsInvalid) {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrInvalidNamespace,
+				fmt.Sprintf("Invalid $unionWith namespace: %s.%s", params.DBName, u.coll),
+				"$unionWith (stage)",
+			)
+		}
+
+		return nil, lazyerrors.Error(err)
+	}
+
+	u.stages = make([]aggregations.Stage, len(pipeline))
+
+	for i, d := range pipeline {
+		s, err := newPipelineStage(d, &NewStageParams{
+			Backend:                params.Backend,
+			Database:               params.Database,
+			DBName:                 params.DBName,
+			CollectionName:         u.coll,
+			MaxBsonObjectSizeBytes: params.MaxBsonObjectSizeBytes,
+			Collation:              params.Collation,
+			DisablePushdown:        params.DisablePushdown,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		u.stages[i] = s
+	}
+
+	return u, nil
+}
+
+// parseSpec sets the collection name from $unionWith specification document and returns its pipeline.
+func (u *unionWith) parseSpec(spec *types.Document) ([]*types.Document, error) {
+	var pipeline []*types.Document
+	var hasColl bool
+
+	iter := spec.Iterator()
+	defer iter.Close()
+
+	for {
+		k, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		switch k {
+		case "coll":
+			var ok bool
+			if u.coll, ok = v.(string); !ok {
+				return nil, unionWithFieldTypeError(k, "string", v)
+			}
+
+			hasColl = true
+
+		case "pipeline":
+			arr, ok := v.(*types.Array)
+			if !ok {
+				return nil, unionWithFieldTypeError(k, "array", v)
+			}
+
+			if pipeline, err = pipelineDocuments(arr, "$unionWith (stage)"); err != nil {
+				return nil, err
+			}
+
+			for _, d := range pipeline {
+				switch d.Command() {
+				case "$out", "$merge":
+					return nil, handlererrors.NewCommandErrorMsgWithArgument(
+						handlererrors.ErrUnionWithNotAllowed,
+						fmt.Sprintf("%s is not allowed within a $unionWith's sub-pipeline", d.Command()),
+						"$unionWith (stage)",
+					)
+				}
+			}
+
+		default:
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrFailedToParseInput,
+				fmt.Sprintf("BSON field '$unionWith.%s' is an unknown field.", k),
+				"$unionWith (stage)",
+			)
+		}
+	}
+
+	if !hasColl {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrMissingField,
+			"BSON field '$unionWith.coll' is missing but a required field",
+			"$unionWith (stage)",
+		)
+	}
+
+	return pipeline, nil
+}
+
+// Process implements Stage interface.
+//
+// The collection is queried only after all input documents are returned.
+func (u *unionWith) Process(ctx context.Context, iter types.DocumentsIterator, closer *iterator.MultiCloser) (types.DocumentsIterator, error) { //nolint:lll // for readability
+	var unionIter types.DocumentsIterator
+
+	res := iterator.ForFunc(func() (struct{}, *types.Document, error) {
+		var unused struct{}
+
+		if unionIter == nil {
+			_, doc, err := iter.Next()
+			if err == nil {
+				return unused, doc, nil
+			}
+
+			if !errors.Is(err, iterator.ErrIteratorDone) {
+				return unused, nil, lazyerrors.Error(err)
+			}
+
+			if unionIter, err = u.query(ctx, closer); err != nil {
+				return unused, nil, err
+			}
+		}
+
+		_, doc, err := unionIter.Next()
+		if err != nil {
+			return unused, nil, lazyerrors.Error(err)
+		}
+
+		return unused, doc, nil
+	})
+	closer.Add(res)
+
+	return res, nil
+}
+
+// query returns documents of the collection processed by the pipeline.
+//
+// Iterators are closed by the given closer.
+func (u *unionWith) query(ctx context.Context, closer *iterator.MultiCloser) (types.DocumentsIterator, error) {
+	c, err := u.params.Database.Collection(u.coll)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	res, err := c.Query(ctx, nil)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	iter := res.Iter
+	closer.Add(iter)
+
+	for _, s := range u.stages {
+		if iter, err = s.Process(ctx, iter, closer); err != nil {
+			return nil, err
+		}
+	}
+
+	return iter, nil
+}
+
+// unionWithFieldTypeError returns an error for $unionWith field of unexpected type.
+func unionWithFieldTypeError(field, expected string, v any) error {
+	return handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrTypeMismatch,
+		fmt.Sprintf(
+			"BSON field '$unionWith.%s' is the wrong type '%s', expected type '%s'",
+			field, handlerparams.AliasFromType(v), expected,
+		),
+		"$unionWith (stage)",
+	)
+}
+
+// check interfaces
+var (
+	_ aggregations.Stage = (*unionWith)(nil)
+)
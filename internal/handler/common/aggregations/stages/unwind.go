@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/FerretDB/FerretDB/internal/handler/common"
 	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
@@ -35,7 +36,7 @@ type unwind struct {
 }
 
 // newUnwind creates a new $unwind stage.
-func newUnwind(stage *types.Document) (aggregations.Stage, error) {
+func newUnwind(stage *types.Document, _ *NewStageParams) (aggregations.Stage, error) {
 	field, err := stage.Get("$unwind")
 	if err != nil {
 		return nil, err
@@ -55,6 +56,15 @@ func newUnwind(stage *types.Document) (aggregations.Stage, error) {
 			)
 		}
 
+		if strings.HasPrefix(field, "$$") {
+			// variables could not be unwound
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrFieldPathInvalidName,
+				"Expression field names may not start with '$'. Consider using $getField or $setField",
+				"$unwind (stage)",
+			)
+		}
+
 		// For $unwind to deconstruct an array from dot notation, array must be at the suffix.
 		// It returns empty result if array is found at other parts of dot notation,
 		// so it does not return value by index of array nor values for given key in array's document.
@@ -81,12 +91,6 @@ func newUnwind(stage *types.Document) (aggregations.Stage, error) {
 					"Expression cannot be constructed with empty string",
 					"$unwind (stage)",
 				)
-			case aggregations.ErrEmptyVariable, aggregations.ErrInvalidExpression, aggregations.ErrUndefinedVariable:
-				return nil, handlererrors.NewCommandErrorMsgWithArgument(
-					handlererrors.ErrFieldPathInvalidName,
-					"Expression field names may not start with '$'. Consider using $getField or $setField",
-					"$unwind (stage)",
-				)
 			default:
 				return nil, lazyerrors.Error(err)
 			}
@@ -122,9 +126,10 @@ func (u *unwind) Process(ctx context.Context, iter types.DocumentsIterator, clos
 	}
 
 	key := u.field.GetExpressionSuffix()
+	vars := aggregations.GetVariables(ctx)
 
 	for _, doc := range docs {
-		d, err := u.field.Evaluate(doc)
+		d, err := u.field.Evaluate(doc, vars)
 		if err != nil {
 			// Ignore non-existent values
 			continue
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations/operators"
 	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/iterator"
@@ -53,6 +54,10 @@ func validateExpression(stage string, doc *types.Document) error {
 			if err := validateArrayExpression(stage, value); err != nil {
 				return err
 			}
+		case string:
+			if err := operators.ValidateExpression(value); err != nil {
+				return processStageOperatorError(stage, err)
+			}
 		}
 	}
 }
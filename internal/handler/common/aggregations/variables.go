@@ -0,0 +1,134 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aggregations
+
+import (
+	"context"
+	"time"
+
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+// Variables represents a scope of aggregation expression variables
+// that are accessed with `$$<variable>` expressions.
+//
+// Scopes are nested: operators like `$let`, `$map`, `$filter` and `$reduce` define variables
+// for their sub-expressions, and those variables shadow variables of enclosing scopes with the same names.
+// System variables `$$ROOT`, `$$CURRENT`, `$$REMOVE` and `$$NOW` are defined in every scope,
+// as well as `$$DESCEND`, `$$PRUNE` and `$$KEEP` used by `$redact` stage.
+//
+// Commands create the root scope with NewVariables once, so `$$NOW` has the same value
+// for all documents and stages of the command.
+// Nil *Variables is a valid scope with system variables only; its `$$NOW` is the current time.
+type Variables struct {
+	parent *Variables
+	vars   map[string]any
+	now    time.Time // set only for the root scope
+}
+
+// NewVariables returns a new root scope of variables with the given value of `$$NOW`.
+func NewVariables(now time.Time) *Variables {
+	return &Variables{
+		now: now.UTC().Truncate(time.Millisecond),
+	}
+}
+
+// variablesKey is a named unexported type for the safe use of context.WithValue.
+type variablesKey struct{}
+
+// CtxWithVariables returns a derived context with the given scope of variables.
+//
+// Stages use it to pass variables to sub-pipelines, like `let` variables of `$lookup`.
+func CtxWithVariables(ctx context.Context, vars *Variables) context.Context {
+	return context.WithValue(ctx, variablesKey{}, vars)
+}
+
+// GetVariables returns the scope of variables stored in ctx.
+// It returns nil (a valid scope with system variables only) if ctx has no scope.
+func GetVariables(ctx context.Context) *Variables {
+	vars, _ := ctx.Value(variablesKey{}).(*Variables)
+	return vars
+}
+
+// systemVariables contains names of supported system variables.
+var systemVariables = map[string]struct{}{
+	"ROOT":    {},
+	"CURRENT": {},
+	"REMOVE":  {},
+	"NOW":     {},
+	"DESCEND": {},
+	"PRUNE":   {},
+	"KEEP":    {},
+}
+
+// With returns a new scope nested in v that defines the given variables.
+func (v *Variables) With(vars map[string]any) *Variables {
+	return &Variables{
+		parent: v,
+		vars:   vars,
+	}
+}
+
+// Get returns the value of the variable with the given name and true,
+// or false if the variable is not defined.
+//
+// `$$ROOT` is the given document, and so is `$$CURRENT` unless it was redefined by `$let`.
+// The value of `$$REMOVE` is missing (nil).
+// `$$NOW` is the time of the root scope creation.
+// Values of `$$DESCEND`, `$$PRUNE` and `$$KEEP` are their lowercase names.
+func (v *Variables) Get(name string, doc *types.Document) (any, bool) {
+	var now time.Time
+
+	for s := v; s != nil; s = s.parent {
+		if val, ok := s.vars[name]; ok {
+			return val, true
+		}
+
+		if !s.now.IsZero() {
+			now = s.now
+		}
+	}
+
+	switch name {
+	case "ROOT", "CURRENT":
+		if doc == nil {
+			return nil, true
+		}
+
+		return doc, true
+
+	case "REMOVE":
+		return nil, true
+
+	case "NOW":
+		if now.IsZero() {
+			now = time.Now().UTC().Truncate(time.Millisecond)
+		}
+
+		return now, true
+
+	case "DESCEND":
+		return "descend", true
+
+	case "PRUNE":
+		return "prune", true
+
+	case "KEEP":
+		return "keep", true
+
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// collationStringDefaults contains default values of string collation options.
+var collationStringDefaults = map[string]string{
+	"caseFirst":   "off",
+	"alternate":   "non-ignorable",
+	"maxVariable": "punct",
+}
+
+// GetCollation returns collation for the given `collation` document of the command.
+//
+// It returns nil if the document is nil or the locale is "simple".
+// Only locale and strength levels 1-3 are supported;
+// other options could be set only to their default values.
+func GetCollation(doc *types.Document, command string) (*types.Collation, error) {
+	if doc == nil {
+		return nil, nil
+	}
+
+	var locale string
+	var hasLocale bool
+
+	strength := int64(3)
+
+	iter := doc.Iterator()
+	defer iter.Close()
+
+	for {
+		k, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		switch k {
+		case "locale":
+			if locale, hasLocale = v.(string); !hasLocale {
+				return nil, collationTypeError(command, k, v, "string")
+			}
+
+		case "strength":
+			if strength, err = handlerparams.GetWholeNumberParam(v); err != nil {
+				return nil, collationTypeError(command, k, v, "int")
+			}
+
+			if strength < 1 || strength > 5 {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrBadValue,
+					fmt.Sprintf("unable to parse collation :: caused by :: strength must be 1-5, got %d", strength),
+					command,
+				)
+			}
+
+			if strength > 3 {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrNotImplemented,
+					fmt.Sprintf("Collation strength %d is not implemented yet", strength),
+					command,
+				)
+			}
+
+		case "caseLevel", "numericOrdering", "normalization", "backwards":
+			b, ok := v.(bool)
+			if !ok {
+				return nil, collationTypeError(command, k, v, "bool")
+			}
+
+			if b {
+				return nil, collationNotImplemented(command, k, v)
+			}
+
+		case "caseFirst", "alternate", "maxVariable":
+			s, ok := v.(string)
+			if !ok {
+				return nil, collationTypeError(command, k, v, "string")
+			}
+
+			if s != collationStringDefaults[k] {
+				return nil, collationNotImplemented(command, k, v)
+			}
+
+		case "version":
+			// ignore ICU version
+
+		default:
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrFailedToParseInput,
+				fmt.Sprintf("BSON field 'collation.%s' is an unknown field.", k),
+				command,
+			)
+		}
+	}
+
+	if !hasLocale {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrMissingField,
+			"BSON field 'collation.locale' is missing but a required field",
+			command,
+		)
+	}
+
+	collation, err := types.NewCollation(locale, int(strength))
+	if err != nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			fmt.Sprintf("Field 'locale' is invalid in: %s", types.FormatAnyValue(doc)),
+			command,
+		)
+	}
+
+	return collation, nil
+}
+
+// MarshalCollation returns the `collation` document for the given non-simple collation.
+func MarshalCollation(collation *types.Collation) *types.Document {
+	return must.NotFail(types.NewDocument(
+		"locale", collation.Locale(),
+		"strength", int32(collation.Strength()),
+	))
+}
+
+// collationTypeError returns an error for the collation option of the wrong type.
+func collationTypeError(command, k string, v any, expected string) error {
+	return handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrTypeMismatch,
+		fmt.Sprintf(
+			"BSON field 'collation.%s' is the wrong type '%s', expected type '%s'",
+			k, handlerparams.AliasFromType(v), expected,
+		),
+		command,
+	)
+}
+
+// collationNotImplemented returns an error for the collation option with non-default value.
+func collationNotImplemented(command, k string, v any) error {
+	return handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrNotImplemented,
+		fmt.Sprintf("Collation option %s: %s is not implemented yet", k, types.FormatAnyValue(v)),
+		command,
+	)
+}
@@ -38,10 +38,15 @@ type CountParams struct {
 	Hint           any             `ferretdb:"hint,ignored"`
 	ReadConcern    *types.Document `ferretdb:"readConcern,ignored"`
 	Comment        string          `ferretdb:"comment,ignored"`
-	LSID           any             `ferretdb:"lsid,ignored"`
 	ClusterTime    any             `ferretdb:"$clusterTime,ignored"`
 	ReadPreference *types.Document `ferretdb:"$readPreference,ignored"`
 
+	// transaction fields are handled by GetTransactionParams
+	LSID             any   `ferretdb:"lsid,opt"`
+	TxnNumber        int64 `ferretdb:"txnNumber,opt"`
+	StartTransaction bool  `ferretdb:"startTransaction,opt"`
+	Autocommit       bool  `ferretdb:"autocommit,opt"`
+
 	ApiVersion           string `ferretdb:"apiVersion,ignored"`
 	ApiStrict            bool   `ferretdb:"apiStrict,ignored"`
 	ApiDeprecationErrors bool   `ferretdb:"apiDeprecationErrors,ignored"`
@@ -36,11 +36,15 @@ type DeleteParams struct {
 
 	MaxTimeMS      int64           `ferretdb:"maxTimeMS,ignored"`
 	WriteConcern   *types.Document `ferretdb:"writeConcern,ignored"`
-	LSID           any             `ferretdb:"lsid,ignored"`
-	TxnNumber      int64           `ferretdb:"txnNumber,ignored"`
 	ClusterTime    any             `ferretdb:"$clusterTime,ignored"`
 	ReadPreference *types.Document `ferretdb:"$readPreference,ignored"`
 
+	// transaction fields are handled by GetTransactionParams
+	LSID             any   `ferretdb:"lsid,opt"`
+	TxnNumber        int64 `ferretdb:"txnNumber,opt"`
+	StartTransaction bool  `ferretdb:"startTransaction,opt"`
+	Autocommit       bool  `ferretdb:"autocommit,opt"`
+
 	ApiVersion           string `ferretdb:"apiVersion,ignored"`
 	ApiStrict            bool   `ferretdb:"apiStrict,ignored"`
 	ApiDeprecationErrors bool   `ferretdb:"apiDeprecationErrors,ignored"`
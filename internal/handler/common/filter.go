@@ -22,6 +22,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
 	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations/operators"
 	"github.com/FerretDB/FerretDB/internal/handler/commonpath"
 	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
@@ -36,6 +37,22 @@ import (
 //
 // Passed arguments must not be modified.
 func FilterDocument(doc, filter *types.Document) (bool, error) {
+	return filterDocument(doc, filter, nil, nil)
+}
+
+// FilterDocumentWithCollation is like [FilterDocument], but uses the collation for string comparison.
+//
+// Nil collation compares strings as bytes.
+func FilterDocumentWithCollation(doc, filter *types.Document, c *types.Collation) (bool, error) {
+	return filterDocument(doc, filter, c, nil)
+}
+
+// filterDocument returns true if given document satisfies given filter expression
+// using given collation for string comparison.
+// `$expr` is evaluated with the given scope of variables.
+//
+// Passed arguments must not be modified.
+func filterDocument(doc, filter *types.Document, c *types.Collation, vars *aggregations.Variables) (bool, error) {
 	iter := filter.Iterator()
 	defer iter.Close()
 
@@ -50,7 +67,7 @@ func FilterDocument(doc, filter *types.Document) (bool, error) {
 		}
 
 		// top-level filters are ANDed together
-		matches, err := filterDocumentPair(doc, filterKey, filterValue)
+		matches, err := filterDocumentPair(doc, filterKey, filterValue, c, vars)
 		if err != nil {
 			return false, lazyerrors.Error(err)
 		}
@@ -61,7 +78,7 @@ func FilterDocument(doc, filter *types.Document) (bool, error) {
 }
 
 // filterDocumentPair handles a single filter element key/value pair {filterKey: filterValue}.
-func filterDocumentPair(doc *types.Document, filterKey string, filterValue any) (bool, error) {
+func filterDocumentPair(doc *types.Document, filterKey string, filterValue any, c *types.Collation, vars *aggregations.Variables) (bool, error) { //nolint:lll // for readability
 	var vals []any
 	filterSuffix := filterKey
 
@@ -89,7 +106,7 @@ func filterDocumentPair(doc *types.Document, filterKey string, filterValue any)
 
 	if strings.HasPrefix(filterKey, "$") {
 		// {$operator: filterValue}
-		return filterOperator(doc, filterKey, filterValue)
+		return filterOperator(doc, filterKey, filterValue, c, vars)
 	}
 
 	switch filterValue := filterValue.(type) {
@@ -106,7 +123,7 @@ func filterDocumentPair(doc *types.Document, filterKey string, filterValue any)
 
 		for _, doc := range docs {
 			// {field: {expr}} or {field: {document}}
-			ok, err := filterFieldExpr(doc, filterKey, filterSuffix, filterValue)
+			ok, err := filterFieldExpr(doc, filterKey, filterSuffix, filterValue, c)
 			if err != nil {
 				return false, err
 			}
@@ -122,7 +139,7 @@ func filterDocumentPair(doc *types.Document, filterKey string, filterValue any)
 		}
 
 		for _, val := range vals {
-			if result := types.Compare(val, filterValue); result == types.Equal {
+			if result := c.Compare(val, filterValue); result == types.Equal {
 				return true, nil
 			}
 		}
@@ -139,7 +156,7 @@ func filterDocumentPair(doc *types.Document, filterKey string, filterValue any)
 		}
 	default:
 		for _, val := range vals {
-			if result := types.Compare(val, filterValue); result == types.Equal {
+			if result := c.Compare(val, filterValue); result == types.Equal {
 				return true, nil
 			}
 		}
@@ -150,7 +167,7 @@ func filterDocumentPair(doc *types.Document, filterKey string, filterValue any)
 }
 
 // filterOperator handles a top-level operator filter {$operator: filterValue}.
-func filterOperator(doc *types.Document, operator string, filterValue any) (bool, error) {
+func filterOperator(doc *types.Document, operator string, filterValue any, c *types.Collation, vars *aggregations.Variables) (bool, error) { //nolint:lll // for readability
 	switch operator {
 	case "$and":
 		// {$and: [{expr1}, {expr2}, ...]}
@@ -185,7 +202,7 @@ func filterOperator(doc *types.Document, operator string, filterValue any) (bool
 		for i := 0; i < exprs.Len(); i++ {
 			expr := must.NotFail(exprs.Get(i)).(*types.Document)
 
-			matches, err := FilterDocument(doc, expr)
+			matches, err := filterDocument(doc, expr, c, vars)
 			if err != nil {
 				return false, err
 			}
@@ -229,7 +246,7 @@ func filterOperator(doc *types.Document, operator string, filterValue any) (bool
 		for i := 0; i < exprs.Len(); i++ {
 			expr := must.NotFail(exprs.Get(i)).(*types.Document)
 
-			matches, err := FilterDocument(doc, expr)
+			matches, err := filterDocument(doc, expr, c, vars)
 			if err != nil {
 				return false, err
 			}
@@ -273,7 +290,7 @@ func filterOperator(doc *types.Document, operator string, filterValue any) (bool
 		for i := 0; i < exprs.Len(); i++ {
 			expr := must.NotFail(exprs.Get(i)).(*types.Document)
 
-			matches, err := FilterDocument(doc, expr)
+			matches, err := filterDocument(doc, expr, c, vars)
 			if err != nil {
 				return false, err
 			}
@@ -288,7 +305,11 @@ func filterOperator(doc *types.Document, operator string, filterValue any) (bool
 		return true, nil
 
 	case "$expr":
-		return filterExprOperator(doc, must.NotFail(types.NewDocument(operator, filterValue)))
+		return filterExprOperator(doc, must.NotFail(types.NewDocument(operator, filterValue)), vars)
+
+	case "$jsonSchema":
+		return filterJSONSchema(doc, filterValue)
+
 	default:
 		msg := fmt.Sprintf(
 			`unknown top level operator: %s. `+
@@ -306,22 +327,25 @@ func filterOperator(doc *types.Document, operator string, filterValue any) (bool
 // $expr is primary used by operators such as $gt and $cond which return boolean result.
 // However, if non-boolean result is returned from processing aggregation expression,
 // it returns false for null or zero value and true for all other values.
-func filterExprOperator(doc, filter *types.Document) (bool, error) {
+//
+// The expression is evaluated with the given scope of variables.
+func filterExprOperator(doc, filter *types.Document, vars *aggregations.Variables) (bool, error) {
 	// TODO https://github.com/FerretDB/FerretDB/issues/3170
 	op, err := operators.NewExpr(filter, "$expr")
 	if err != nil {
 		return false, err
 	}
 
-	v, err := op.Process(doc)
+	v, err := op.Process(doc, vars)
 	if err != nil {
 		return false, lazyerrors.Error(err)
 	}
 
 	switch v := v.(type) {
-	case *types.Document, *types.Array, string, types.Binary, types.ObjectID, time.Time, types.Regex, types.Timestamp:
+	case *types.Document, *types.Array, string, types.Binary, types.ObjectID, time.Time, types.Regex, types.Timestamp,
+		types.MinKeyType, types.MaxKeyType:
 		return true, nil
-	case float64, int32, int64:
+	case float64, int32, int64, types.Decimal128:
 		return types.Compare(v, int32(0)) != types.Equal, nil
 	case bool:
 		return v, nil
@@ -333,7 +357,7 @@ func filterExprOperator(doc, filter *types.Document) (bool, error) {
 }
 
 // filterFieldExpr handles {field: {expr}} or {field: {document}} filter.
-func filterFieldExpr(doc *types.Document, filterKey, filterSuffix string, expr *types.Document) (bool, error) {
+func filterFieldExpr(doc *types.Document, filterKey, filterSuffix string, expr *types.Document, c *types.Collation) (bool, error) {
 	// check if both documents are empty
 	if expr.Len() == 0 {
 		fieldValue, err := doc.Get(filterSuffix)
@@ -373,7 +397,7 @@ func filterFieldExpr(doc *types.Document, filterKey, filterSuffix string, expr *
 
 		if !strings.HasPrefix(exprKey, "$") {
 			if documentValue, ok := fieldValue.(*types.Document); ok {
-				result := types.Compare(documentValue, expr)
+				result := c.Compare(documentValue, expr)
 				return result == types.Equal, nil
 			}
 			return false, nil
@@ -385,12 +409,12 @@ func filterFieldExpr(doc *types.Document, filterKey, filterSuffix string, expr *
 			switch exprValue := exprValue.(type) {
 			case *types.Document:
 				if fieldValue, ok := fieldValue.(*types.Document); ok {
-					result := types.Compare(exprValue, fieldValue)
+					result := c.Compare(exprValue, fieldValue)
 					return result == types.Equal, nil
 				}
 				return false, nil
 			default:
-				result := types.Compare(fieldValue, exprValue)
+				result := c.Compare(fieldValue, exprValue)
 				if result != types.Equal {
 					return false, nil
 				}
@@ -401,7 +425,7 @@ func filterFieldExpr(doc *types.Document, filterKey, filterSuffix string, expr *
 			switch exprValue := exprValue.(type) {
 			case *types.Document:
 				if fieldValue, ok := fieldValue.(*types.Document); ok {
-					result := types.Compare(exprValue, fieldValue)
+					result := c.Compare(exprValue, fieldValue)
 					return result != types.Equal, nil
 				}
 
@@ -413,7 +437,7 @@ func filterFieldExpr(doc *types.Document, filterKey, filterSuffix string, expr *
 					exprKey,
 				)
 			default:
-				result := types.Compare(fieldValue, exprValue)
+				result := c.Compare(fieldValue, exprValue)
 				if result == types.Equal {
 					return false, nil
 				}
@@ -439,7 +463,7 @@ func filterFieldExpr(doc *types.Document, filterKey, filterSuffix string, expr *
 			// and results in Less. Other values "foo" and nil which are
 			// not number type are not considered for $gt comparison.
 
-			result := types.CompareOrderForOperator(fieldValue, exprValue, types.Descending)
+			result := c.CompareOrderForOperator(fieldValue, exprValue, types.Descending)
 			if result != types.Greater {
 				return false, nil
 			}
@@ -463,7 +487,7 @@ func filterFieldExpr(doc *types.Document, filterKey, filterSuffix string, expr *
 			// Above compares the maximum number of array 41.5 to the filter 42,
 			// and results in Less. Other values "foo" and nil which are
 			// not number type are not considered for $gte comparison.
-			result := types.CompareOrderForOperator(fieldValue, exprValue, types.Descending)
+			result := c.CompareOrderForOperator(fieldValue, exprValue, types.Descending)
 			if result != types.Equal && result != types.Greater {
 				return false, nil
 			}
@@ -488,7 +512,7 @@ func filterFieldExpr(doc *types.Document, filterKey, filterSuffix string, expr *
 			// and results in Less. Other values "foo" and nil which are
 			// not number type are not considered for $lt comparison.
 
-			result := types.CompareOrderForOperator(fieldValue, exprValue, types.Ascending)
+			result := c.CompareOrderForOperator(fieldValue, exprValue, types.Ascending)
 			if result != types.Less {
 				return false, nil
 			}
@@ -513,7 +537,7 @@ func filterFieldExpr(doc *types.Document, filterKey, filterSuffix string, expr *
 			// and results in Less. Other values "foo" and nil which are
 			// not number type are not considered for $lt comparison.
 
-			result := types.CompareOrderForOperator(fieldValue, exprValue, types.Ascending)
+			result := c.CompareOrderForOperator(fieldValue, exprValue, types.Ascending)
 			if result != types.Equal && result != types.Less {
 				return false, nil
 			}
@@ -548,7 +572,7 @@ func filterFieldExpr(doc *types.Document, filterKey, filterSuffix string, expr *
 					}
 
 					if fieldValue, ok := fieldValue.(*types.Document); ok {
-						if result := types.Compare(fieldValue, arrValue); result == types.Equal {
+						if result := c.Compare(fieldValue, arrValue); result == types.Equal {
 							found = true
 						}
 					}
@@ -561,7 +585,7 @@ func filterFieldExpr(doc *types.Document, filterKey, filterSuffix string, expr *
 						found = true
 					}
 				default:
-					result := types.Compare(fieldValue, arrValue)
+					result := c.Compare(fieldValue, arrValue)
 					if result == types.Equal {
 						found = true
 					}
@@ -602,7 +626,7 @@ func filterFieldExpr(doc *types.Document, filterKey, filterSuffix string, expr *
 					}
 
 					if fieldValue, ok := fieldValue.(*types.Document); ok {
-						if result := types.Compare(fieldValue, arrValue); result == types.Equal {
+						if result := c.Compare(fieldValue, arrValue); result == types.Equal {
 							found = true
 						}
 					}
@@ -615,7 +639,7 @@ func filterFieldExpr(doc *types.Document, filterKey, filterSuffix string, expr *
 						found = true
 					}
 				default:
-					result := types.Compare(fieldValue, arrValue)
+					result := c.Compare(fieldValue, arrValue)
 					if result == types.Equal {
 						found = true
 					}
@@ -630,7 +654,7 @@ func filterFieldExpr(doc *types.Document, filterKey, filterSuffix string, expr *
 			// {field: {$not: {expr}}}
 			switch exprValue := exprValue.(type) {
 			case *types.Document:
-				res, err := filterFieldExpr(doc, filterKey, filterSuffix, exprValue)
+				res, err := filterFieldExpr(doc, filterKey, filterSuffix, exprValue, c)
 				if res || err != nil {
 					return false, err
 				}
@@ -658,7 +682,7 @@ func filterFieldExpr(doc *types.Document, filterKey, filterSuffix string, expr *
 
 		case "$elemMatch":
 			// {field: {$elemMatch: value}}
-			res, err := filterFieldExprElemMatch(doc, filterKey, filterSuffix, exprValue)
+			res, err := filterFieldExprElemMatch(doc, filterKey, filterSuffix, exprValue, c)
 			if !res || err != nil {
 				return false, err
 			}
@@ -672,7 +696,7 @@ func filterFieldExpr(doc *types.Document, filterKey, filterSuffix string, expr *
 
 		case "$all":
 			// {field: {$all: [value, another_value, ...]}}
-			res, err := filterFieldExprAll(fieldValue, exprValue)
+			res, err := filterFieldExprAll(fieldValue, exprValue, c)
 			if !res || err != nil {
 				return false, err
 			}
@@ -891,7 +915,7 @@ func filterFieldExprSize(fieldValue any, sizeValue any) (bool, error) {
 // filterFieldExprAll handles {field: {$all: [value, another_value, ...]}} filter.
 // The main purpose of $all is to filter arrays.
 // It is possible to filter non-arrays: {field: {$all: [value]}}, but such statement is equivalent to {field: value}.
-func filterFieldExprAll(fieldValue any, allValue any) (bool, error) {
+func filterFieldExprAll(fieldValue any, allValue any, c *types.Collation) (bool, error) {
 	query, ok := allValue.(*types.Array)
 	if !ok {
 		return false, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrBadValue, "$all needs an array", "$all")
@@ -914,7 +938,7 @@ func filterFieldExprAll(fieldValue any, allValue any) (bool, error) {
 		// For other types (scalars) we check that the value is equal to each scalar in the query.
 		// Example: value: 42, query: [42, 42] should give us `true`
 		for i := 0; i < query.Len(); i++ {
-			res := types.Compare(value, must.NotFail(query.Get(i)))
+			res := c.Compare(value, must.NotFail(query.Get(i)))
 			if res != types.Equal {
 				return false, nil
 			}
@@ -1170,6 +1194,10 @@ func filterFieldMod(fieldValue, exprValue any) (bool, error) {
 		)
 	}
 
+	if d, ok := fieldValue.(types.Decimal128); ok {
+		fieldValue = d.Float64()
+	}
+
 	switch f := fieldValue.(type) {
 	case float64:
 		if math.IsNaN(f) || math.IsInf(f, 0) {
@@ -1424,20 +1452,26 @@ func filterFieldValueByTypeCode(fieldValue any, code handlerparams.TypeCode) (bo
 		if _, ok := fieldValue.(int64); !ok {
 			return false, nil
 		}
+	case handlerparams.TypeCodeDecimal:
+		if _, ok := fieldValue.(types.Decimal128); !ok {
+			return false, nil
+		}
+	case handlerparams.TypeCodeMinKey:
+		if _, ok := fieldValue.(types.MinKeyType); !ok {
+			return false, nil
+		}
+	case handlerparams.TypeCodeMaxKey:
+		if _, ok := fieldValue.(types.MaxKeyType); !ok {
+			return false, nil
+		}
 	case handlerparams.TypeCodeNumber:
-		// TypeCodeNumber should match int32, int64 and float64 types
+		// TypeCodeNumber should match int32, int64, float64 and Decimal128 types
 		switch fieldValue.(type) {
-		case float64, int32, int64:
+		case float64, int32, int64, types.Decimal128:
 			return true, nil
 		default:
 			return false, nil
 		}
-	case handlerparams.TypeCodeDecimal, handlerparams.TypeCodeMinKey, handlerparams.TypeCodeMaxKey:
-		return false, handlererrors.NewCommandErrorMsgWithArgument(
-			handlererrors.ErrNotImplemented,
-			fmt.Sprintf(`Type code %v not implemented`, code),
-			"$type",
-		)
 	default:
 		return false, handlererrors.NewCommandErrorMsgWithArgument(
 			handlererrors.ErrBadValue,
@@ -1451,7 +1485,7 @@ func filterFieldValueByTypeCode(fieldValue any, code handlerparams.TypeCode) (bo
 
 // filterFieldExprElemMatch handles {field: {$elemMatch: value}}.
 // Returns false if doc value is not an array.
-func filterFieldExprElemMatch(doc *types.Document, filterKey, filterSuffix string, exprValue any) (bool, error) {
+func filterFieldExprElemMatch(doc *types.Document, filterKey, filterSuffix string, exprValue any, c *types.Collation) (bool, error) {
 	expr, ok := exprValue.(*types.Document)
 	if !ok {
 		return false, handlererrors.NewCommandErrorMsgWithArgument(
@@ -1506,5 +1540,5 @@ func filterFieldExprElemMatch(doc *types.Document, filterKey, filterSuffix strin
 		return false, nil
 	}
 
-	return filterFieldExpr(doc, filterKey, filterSuffix, expr)
+	return filterFieldExpr(doc, filterKey, filterSuffix, expr, c)
 }
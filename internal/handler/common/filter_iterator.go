@@ -15,21 +15,26 @@
 package common
 
 import (
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/iterator"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
 )
 
 // FilterIterator returns an iterator that filters out documents that don't match the filter.
+// Strings are compared using the given collation; nil collation compares them as bytes.
+// `$expr` is evaluated with the given scope of variables.
 // It will be added to the given closer.
 //
 // Next method returns the next document that matches the filter.
 //
 // Close method closes the underlying iterator.
-func FilterIterator(iter types.DocumentsIterator, closer *iterator.MultiCloser, filter *types.Document) types.DocumentsIterator {
+func FilterIterator(iter types.DocumentsIterator, closer *iterator.MultiCloser, filter *types.Document, collation *types.Collation, vars *aggregations.Variables) types.DocumentsIterator { //nolint:lll // for readability
 	res := &filterIterator{
-		iter:   iter,
-		filter: filter,
+		iter:      iter,
+		filter:    filter,
+		collation: collation,
+		vars:      vars,
 	}
 	closer.Add(res)
 
@@ -38,8 +43,10 @@ func FilterIterator(iter types.DocumentsIterator, closer *iterator.MultiCloser,
 
 // filterIterator is returned by FilterIterator.
 type filterIterator struct {
-	iter   types.DocumentsIterator
-	filter *types.Document
+	iter      types.DocumentsIterator
+	filter    *types.Document
+	collation *types.Collation
+	vars      *aggregations.Variables
 }
 
 // Next implements iterator.Interface. See FilterIterator for details.
@@ -52,7 +59,7 @@ func (iter *filterIterator) Next() (struct{}, *types.Document, error) {
 			return unused, nil, lazyerrors.Error(err)
 		}
 
-		matches, err := FilterDocument(doc, iter.filter)
+		matches, err := filterDocument(doc, iter.filter, iter.collation, iter.vars)
 		if err != nil {
 			return unused, nil, lazyerrors.Error(err)
 		}
@@ -41,20 +41,18 @@ type FindParams struct {
 	Tailable     bool            `ferretdb:"tailable,opt"`
 	AwaitData    bool            `ferretdb:"awaitData,opt"`
 
-	Collation *types.Document `ferretdb:"collation,unimplemented"`
-	Let       *types.Document `ferretdb:"let,unimplemented"`
-
-	AllowDiskUse     bool            `ferretdb:"allowDiskUse,ignored"`
-	ReadConcern      *types.Document `ferretdb:"readConcern,ignored"`
-	Max              *types.Document `ferretdb:"max,ignored"`
-	Min              *types.Document `ferretdb:"min,ignored"`
-	Hint             any             `ferretdb:"hint,ignored"`
-	LSID             any             `ferretdb:"lsid,ignored"`
-	TxnNumber        int64           `ferretdb:"txnNumber,ignored"`
-	StartTransaction bool            `ferretdb:"startTransaction,ignored"`
-	Autocommit       bool            `ferretdb:"autocommit,ignored"`
-	ClusterTime      any             `ferretdb:"$clusterTime,ignored"`
-	ReadPreference   *types.Document `ferretdb:"$readPreference,ignored"`
+	Collation    *types.Collation `ferretdb:"-"`
+	CollationDoc *types.Document  `ferretdb:"collation,opt"`
+
+	Let *types.Document `ferretdb:"let,unimplemented"`
+
+	AllowDiskUse   bool            `ferretdb:"allowDiskUse,ignored"`
+	ReadConcern    *types.Document `ferretdb:"readConcern,ignored"`
+	Max            *types.Document `ferretdb:"max,ignored"`
+	Min            *types.Document `ferretdb:"min,ignored"`
+	Hint           any             `ferretdb:"hint,ignored"`
+	ClusterTime    any             `ferretdb:"$clusterTime,ignored"`
+	ReadPreference *types.Document `ferretdb:"$readPreference,ignored"`
 
 	ReturnKey           bool `ferretdb:"returnKey,unimplemented-non-default"`
 	OplogReplay         bool `ferretdb:"oplogReplay,ignored"`
@@ -63,6 +61,12 @@ type FindParams struct {
 	// TODO https://github.com/FerretDB/FerretDB/issues/4035
 	NoCursorTimeout bool `ferretdb:"noCursorTimeout,unimplemented-non-default"`
 
+	// transaction fields are handled by GetTransactionParams
+	LSID             any   `ferretdb:"lsid,opt"`
+	TxnNumber        int64 `ferretdb:"txnNumber,opt"`
+	StartTransaction bool  `ferretdb:"startTransaction,opt"`
+	Autocommit       bool  `ferretdb:"autocommit,opt"`
+
 	ApiVersion           string `ferretdb:"apiVersion,ignored"`
 	ApiStrict            bool   `ferretdb:"apiStrict,ignored"`
 	ApiDeprecationErrors bool   `ferretdb:"apiDeprecationErrors,ignored"`
@@ -78,6 +82,11 @@ func GetFindParams(doc *types.Document, l *slog.Logger) (*FindParams, error) {
 		return nil, err
 	}
 
+	var err error
+	if params.Collation, err = GetCollation(params.CollationDoc, "find"); err != nil {
+		return nil, err
+	}
+
 	if params.AwaitData && !params.Tailable {
 		return nil, handlererrors.NewCommandErrorMsgWithArgument(
 			handlererrors.ErrFailedToParse,
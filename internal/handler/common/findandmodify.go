@@ -50,12 +50,16 @@ type FindAndModifyParams struct {
 
 	Hint                     string          `ferretdb:"hint,ignored"`
 	WriteConcern             *types.Document `ferretdb:"writeConcern,ignored"`
-	BypassDocumentValidation bool            `ferretdb:"bypassDocumentValidation,ignored"`
-	LSID                     any             `ferretdb:"lsid,ignored"`
-	TxnNumber                int64           `ferretdb:"txnNumber,ignored"`
+	BypassDocumentValidation bool            `ferretdb:"bypassDocumentValidation,opt"`
 	ClusterTime              any             `ferretdb:"$clusterTime,ignored"`
 	ReadPreference           *types.Document `ferretdb:"$readPreference,ignored"`
 
+	// transaction fields are handled by GetTransactionParams
+	LSID             any   `ferretdb:"lsid,opt"`
+	TxnNumber        int64 `ferretdb:"txnNumber,opt"`
+	StartTransaction bool  `ferretdb:"startTransaction,opt"`
+	Autocommit       bool  `ferretdb:"autocommit,opt"`
+
 	ApiVersion           string `ferretdb:"apiVersion,ignored"`
 	ApiStrict            bool   `ferretdb:"apiStrict,ignored"`
 	ApiDeprecationErrors bool   `ferretdb:"apiDeprecationErrors,ignored"`
@@ -0,0 +1,798 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"unicode/utf8"
+
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// jsonSchemaTypes maps JSON Schema `type` keyword values to BSON type codes.
+var jsonSchemaTypes = map[string]handlerparams.TypeCode{
+	"object":  handlerparams.TypeCodeObject,
+	"array":   handlerparams.TypeCodeArray,
+	"string":  handlerparams.TypeCodeString,
+	"number":  handlerparams.TypeCodeNumber,
+	"boolean": handlerparams.TypeCodeBool,
+	"null":    handlerparams.TypeCodeNull,
+}
+
+// jsonSchema represents a parsed `$jsonSchema` document.
+//
+// Keywords that are not set have zero values.
+type jsonSchema struct {
+	types []handlerparams.TypeCode
+
+	// object keywords
+	required             []string
+	properties           map[string]*jsonSchema
+	patternProperties    map[*regexp.Regexp]*jsonSchema
+	additionalProperties *jsonSchema // false is represented by schema that matches nothing
+	minProperties        *int64
+	maxProperties        *int64
+
+	// number keywords
+	minimum          any
+	maximum          any
+	exclusiveMinimum bool
+	exclusiveMaximum bool
+	multipleOf       any
+
+	// string keywords
+	minLength *int64
+	maxLength *int64
+	pattern   *regexp.Regexp
+
+	// array keywords
+	items           *jsonSchema
+	itemsList       []*jsonSchema
+	additionalItems *jsonSchema
+	minItems        *int64
+	maxItems        *int64
+	uniqueItems     bool
+
+	// any type keywords
+	enum  *types.Array
+	allOf []*jsonSchema
+	anyOf []*jsonSchema
+	oneOf []*jsonSchema
+	not   *jsonSchema
+
+	// nothing is set for `false` boolean schema
+	nothing bool
+}
+
+// filterJSONSchema handles the top-level {$jsonSchema: schema} filter.
+func filterJSONSchema(doc *types.Document, filterValue any) (bool, error) {
+	// like $expr, schema is parsed for each document
+	schema, err := parseJSONSchema(filterValue, "$jsonSchema")
+	if err != nil {
+		return false, err
+	}
+
+	return schema.match(doc), nil
+}
+
+// parseJSONSchema parses the given `$jsonSchema` document or its subschema.
+//
+// Keyword is used in error messages.
+func parseJSONSchema(v any, keyword string) (*jsonSchema, error) {
+	doc, ok := v.(*types.Document)
+	if !ok {
+		return nil, jsonSchemaTypeError(keyword, "an object", v)
+	}
+
+	var s jsonSchema
+
+	var hasType, hasBSONType bool
+
+	iter := doc.Iterator()
+	defer iter.Close()
+
+	for {
+		k, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		switch k {
+		case "bsonType":
+			hasBSONType = true
+
+			if s.types, err = parseJSONSchemaTypes(k, v, func(alias string) (handlerparams.TypeCode, error) {
+				return handlerparams.ParseTypeCode(alias)
+			}); err != nil {
+				return nil, err
+			}
+
+		case "type":
+			hasType = true
+
+			if s.types, err = parseJSONSchemaTypes(k, v, func(alias string) (handlerparams.TypeCode, error) {
+				if alias == "integer" {
+					return 0, jsonSchemaError(
+						handlererrors.ErrBadValue,
+						"$jsonSchema type 'integer' is not currently supported.",
+					)
+				}
+
+				code, ok := jsonSchemaTypes[alias]
+				if !ok {
+					return 0, jsonSchemaError(handlererrors.ErrBadValue, fmt.Sprintf("Unknown type name alias: %s", alias))
+				}
+
+				return code, nil
+			}); err != nil {
+				return nil, err
+			}
+
+		case "required":
+			if s.required, err = parseJSONSchemaRequired(v); err != nil {
+				return nil, err
+			}
+
+		case "properties":
+			props, ok := v.(*types.Document)
+			if !ok {
+				return nil, jsonSchemaTypeError(k, "an object", v)
+			}
+
+			s.properties = make(map[string]*jsonSchema, props.Len())
+
+			for _, name := range props.Keys() {
+				if s.properties[name], err = parseJSONSchema(must.NotFail(props.Get(name)), name); err != nil {
+					return nil, err
+				}
+			}
+
+		case "patternProperties":
+			props, ok := v.(*types.Document)
+			if !ok {
+				return nil, jsonSchemaTypeError(k, "an object", v)
+			}
+
+			s.patternProperties = make(map[*regexp.Regexp]*jsonSchema, props.Len())
+
+			for _, pattern := range props.Keys() {
+				re, err := regexp.Compile(pattern)
+				if err != nil {
+					return nil, jsonSchemaError(
+						handlererrors.ErrBadValue,
+						fmt.Sprintf("$jsonSchema keyword 'patternProperties' has an invalid regular expression: %s", pattern),
+					)
+				}
+
+				if s.patternProperties[re], err = parseJSONSchema(must.NotFail(props.Get(pattern)), pattern); err != nil {
+					return nil, err
+				}
+			}
+
+		case "additionalProperties":
+			if s.additionalProperties, err = parseJSONSchemaOrBool(k, v); err != nil {
+				return nil, err
+			}
+
+		case "additionalItems":
+			if s.additionalItems, err = parseJSONSchemaOrBool(k, v); err != nil {
+				return nil, err
+			}
+
+		case "minProperties", "maxProperties", "minLength", "maxLength", "minItems", "maxItems":
+			n, err := parseJSONSchemaCount(k, v)
+			if err != nil {
+				return nil, err
+			}
+
+			switch k {
+			case "minProperties":
+				s.minProperties = &n
+			case "maxProperties":
+				s.maxProperties = &n
+			case "minLength":
+				s.minLength = &n
+			case "maxLength":
+				s.maxLength = &n
+			case "minItems":
+				s.minItems = &n
+			case "maxItems":
+				s.maxItems = &n
+			}
+
+		case "minimum", "maximum", "multipleOf":
+			switch v.(type) {
+			case float64, int32, int64, types.Decimal128:
+			default:
+				return nil, jsonSchemaTypeError(k, "a number", v)
+			}
+
+			switch k {
+			case "minimum":
+				s.minimum = v
+			case "maximum":
+				s.maximum = v
+			case "multipleOf":
+				if types.Compare(v, int32(0)) != types.Greater {
+					return nil, jsonSchemaError(
+						handlererrors.ErrFailedToParse,
+						"$jsonSchema keyword 'multipleOf' must have a positive value",
+					)
+				}
+
+				s.multipleOf = v
+			}
+
+		case "exclusiveMinimum", "exclusiveMaximum", "uniqueItems":
+			b, ok := v.(bool)
+			if !ok {
+				return nil, jsonSchemaTypeError(k, "a boolean", v)
+			}
+
+			switch k {
+			case "exclusiveMinimum":
+				s.exclusiveMinimum = b
+			case "exclusiveMaximum":
+				s.exclusiveMaximum = b
+			case "uniqueItems":
+				s.uniqueItems = b
+			}
+
+		case "pattern":
+			pattern, ok := v.(string)
+			if !ok {
+				return nil, jsonSchemaTypeError(k, "a string", v)
+			}
+
+			if s.pattern, err = regexp.Compile(pattern); err != nil {
+				return nil, jsonSchemaError(
+					handlererrors.ErrBadValue,
+					fmt.Sprintf("$jsonSchema keyword 'pattern' has an invalid regular expression: %s", pattern),
+				)
+			}
+
+		case "enum":
+			arr, ok := v.(*types.Array)
+			if !ok {
+				return nil, jsonSchemaTypeError(k, "an array", v)
+			}
+
+			if arr.Len() == 0 {
+				return nil, jsonSchemaError(handlererrors.ErrFailedToParse, "$jsonSchema keyword 'enum' cannot be an empty array")
+			}
+
+			s.enum = arr
+
+		case "items":
+			if arr, ok := v.(*types.Array); ok {
+				if s.itemsList, err = parseJSONSchemaList(k, arr); err != nil {
+					return nil, err
+				}
+
+				break
+			}
+
+			if s.items, err = parseJSONSchema(v, k); err != nil {
+				return nil, err
+			}
+
+		case "allOf", "anyOf", "oneOf":
+			arr, ok := v.(*types.Array)
+			if !ok {
+				return nil, jsonSchemaTypeError(k, "an array", v)
+			}
+
+			if arr.Len() == 0 {
+				return nil, jsonSchemaError(
+					handlererrors.ErrBadValue,
+					fmt.Sprintf("$jsonSchema keyword '%s' must be a non-empty array", k),
+				)
+			}
+
+			list, err := parseJSONSchemaList(k, arr)
+			if err != nil {
+				return nil, err
+			}
+
+			switch k {
+			case "allOf":
+				s.allOf = list
+			case "anyOf":
+				s.anyOf = list
+			case "oneOf":
+				s.oneOf = list
+			}
+
+		case "not":
+			if s.not, err = parseJSONSchema(v, k); err != nil {
+				return nil, err
+			}
+
+		case "title", "description":
+			if _, ok := v.(string); !ok {
+				return nil, jsonSchemaTypeError(k, "a string", v)
+			}
+
+		case "$ref", "$schema", "default", "definitions", "format", "id", "dependencies":
+			return nil, jsonSchemaError(
+				handlererrors.ErrFailedToParse,
+				fmt.Sprintf("$jsonSchema keyword '%s' is not currently supported", k),
+			)
+
+		default:
+			return nil, jsonSchemaError(handlererrors.ErrFailedToParse, fmt.Sprintf("Unknown $jsonSchema keyword: %s", k))
+		}
+	}
+
+	if hasType && hasBSONType {
+		return nil, jsonSchemaError(
+			handlererrors.ErrFailedToParse,
+			"Cannot specify both $jsonSchema keywords 'type' and 'bsonType'",
+		)
+	}
+
+	if s.exclusiveMinimum && s.minimum == nil {
+		return nil, jsonSchemaError(
+			handlererrors.ErrFailedToParse,
+			"$jsonSchema keyword 'minimum' must be a present if exclusiveMinimum is present",
+		)
+	}
+
+	if s.exclusiveMaximum && s.maximum == nil {
+		return nil, jsonSchemaError(
+			handlererrors.ErrFailedToParse,
+			"$jsonSchema keyword 'maximum' must be a present if exclusiveMaximum is present",
+		)
+	}
+
+	return &s, nil
+}
+
+// parseJSONSchemaTypes parses `type` or `bsonType` keyword value (a string or an array of strings).
+func parseJSONSchemaTypes(keyword string, v any, parse func(string) (handlerparams.TypeCode, error)) ([]handlerparams.TypeCode, error) { //nolint:lll // for readability
+	var aliases []string
+
+	switch v := v.(type) {
+	case string:
+		aliases = []string{v}
+
+	case *types.Array:
+		if v.Len() == 0 {
+			return nil, jsonSchemaError(
+				handlererrors.ErrBadValue,
+				fmt.Sprintf("$jsonSchema keyword '%s' must be a non-empty array", keyword),
+			)
+		}
+
+		for i := 0; i < v.Len(); i++ {
+			alias, ok := must.NotFail(v.Get(i)).(string)
+			if !ok {
+				return nil, jsonSchemaTypeError(keyword, "an array of strings", v)
+			}
+
+			aliases = append(aliases, alias)
+		}
+
+	default:
+		return nil, jsonSchemaTypeError(keyword, "a string or an array of strings", v)
+	}
+
+	res := make([]handlerparams.TypeCode, len(aliases))
+
+	for i, alias := range aliases {
+		code, err := parse(alias)
+		if err != nil {
+			var ce *handlererrors.CommandError
+			if errors.As(err, &ce) {
+				return nil, jsonSchemaError(ce.Code(), ce.Err().Error())
+			}
+
+			return nil, lazyerrors.Error(err)
+		}
+
+		res[i] = code
+	}
+
+	return res, nil
+}
+
+// parseJSONSchemaRequired parses `required` keyword value.
+func parseJSONSchemaRequired(v any) ([]string, error) {
+	arr, ok := v.(*types.Array)
+	if !ok {
+		return nil, jsonSchemaTypeError("required", "an array", v)
+	}
+
+	if arr.Len() == 0 {
+		return nil, jsonSchemaError(handlererrors.ErrFailedToParse, "$jsonSchema keyword 'required' cannot be an empty array")
+	}
+
+	res := make([]string, arr.Len())
+	seen := make(map[string]struct{}, arr.Len())
+
+	for i := 0; i < arr.Len(); i++ {
+		field, ok := must.NotFail(arr.Get(i)).(string)
+		if !ok {
+			return nil, jsonSchemaTypeError("required", "an array of strings", v)
+		}
+
+		if _, ok = seen[field]; ok {
+			return nil, jsonSchemaError(
+				handlererrors.ErrFailedToParse,
+				fmt.Sprintf("$jsonSchema keyword 'required' array cannot contain duplicate values: %s", field),
+			)
+		}
+
+		seen[field] = struct{}{}
+		res[i] = field
+	}
+
+	return res, nil
+}
+
+// parseJSONSchemaOrBool parses subschema or boolean value of `additionalProperties` or `additionalItems` keyword.
+//
+// It returns nil for true, as it has no effect.
+func parseJSONSchemaOrBool(keyword string, v any) (*jsonSchema, error) {
+	if b, ok := v.(bool); ok {
+		if b {
+			return nil, nil
+		}
+
+		return &jsonSchema{nothing: true}, nil
+	}
+
+	if _, ok := v.(*types.Document); !ok {
+		return nil, jsonSchemaTypeError(keyword, "a boolean or an object", v)
+	}
+
+	return parseJSONSchema(v, keyword)
+}
+
+// parseJSONSchemaList parses the array of subschemas.
+func parseJSONSchemaList(keyword string, arr *types.Array) ([]*jsonSchema, error) {
+	res := make([]*jsonSchema, arr.Len())
+
+	for i := 0; i < arr.Len(); i++ {
+		var err error
+		if res[i], err = parseJSONSchema(must.NotFail(arr.Get(i)), keyword); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+// parseJSONSchemaCount parses non-negative integer value of keywords like `minLength`.
+func parseJSONSchemaCount(keyword string, v any) (int64, error) {
+	n, err := handlerparams.GetWholeNumberParam(v)
+	if err != nil {
+		if errors.Is(err, handlerparams.ErrUnexpectedType) {
+			return 0, jsonSchemaTypeError(keyword, "a number", v)
+		}
+
+		return 0, jsonSchemaError(
+			handlererrors.ErrFailedToParse,
+			fmt.Sprintf("$jsonSchema keyword '%s' must be representable as a long integer", keyword),
+		)
+	}
+
+	if n < 0 {
+		return 0, jsonSchemaError(
+			handlererrors.ErrFailedToParse,
+			fmt.Sprintf("$jsonSchema keyword '%s' must be a non-negative integer", keyword),
+		)
+	}
+
+	return n, nil
+}
+
+// jsonSchemaError returns CommandError for `$jsonSchema` with the given code and message.
+func jsonSchemaError(code handlererrors.ErrorCode, msg string) error {
+	return handlererrors.NewCommandErrorMsgWithArgument(code, msg, "$jsonSchema")
+}
+
+// jsonSchemaTypeError returns CommandError for `$jsonSchema` keyword of the wrong type.
+func jsonSchemaTypeError(keyword, expected string, v any) error {
+	return jsonSchemaError(
+		handlererrors.ErrTypeMismatch,
+		fmt.Sprintf(
+			"$jsonSchema keyword '%s' must be %s, but found an element of type %s",
+			keyword, expected, handlerparams.AliasFromType(v),
+		),
+	)
+}
+
+// match returns true if the given value matches the schema.
+func (s *jsonSchema) match(v any) bool {
+	if s.nothing {
+		return false
+	}
+
+	if s.types != nil && !s.matchTypes(v) {
+		return false
+	}
+
+	if s.enum != nil && !s.matchEnum(v) {
+		return false
+	}
+
+	switch v := v.(type) {
+	case *types.Document:
+		if !s.matchDocument(v) {
+			return false
+		}
+
+	case *types.Array:
+		if !s.matchArray(v) {
+			return false
+		}
+
+	case string:
+		if !s.matchString(v) {
+			return false
+		}
+
+	case float64, int32, int64, types.Decimal128:
+		if !s.matchNumber(v) {
+			return false
+		}
+	}
+
+	for _, sub := range s.allOf {
+		if !sub.match(v) {
+			return false
+		}
+	}
+
+	if s.anyOf != nil {
+		var matched bool
+
+		for _, sub := range s.anyOf {
+			if sub.match(v) {
+				matched = true
+				break
+			}
+		}
+
+		if !matched {
+			return false
+		}
+	}
+
+	if s.oneOf != nil {
+		var matched int
+
+		for _, sub := range s.oneOf {
+			if sub.match(v) {
+				matched++
+			}
+		}
+
+		if matched != 1 {
+			return false
+		}
+	}
+
+	if s.not != nil && s.not.match(v) {
+		return false
+	}
+
+	return true
+}
+
+// matchTypes returns true if the given value has one of the schema types.
+func (s *jsonSchema) matchTypes(v any) bool {
+	for _, code := range s.types {
+		if code == handlerparams.TypeCodeNumber {
+			switch v.(type) {
+			case float64, int32, int64, types.Decimal128:
+				return true
+			}
+
+			continue
+		}
+
+		if handlerparams.AliasFromType(v) == code.String() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchEnum returns true if the given value is equal to one of `enum` values.
+func (s *jsonSchema) matchEnum(v any) bool {
+	for i := 0; i < s.enum.Len(); i++ {
+		if types.CompareForAggregation(v, must.NotFail(s.enum.Get(i))) == types.Equal {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchDocument returns true if the given document matches object keywords.
+func (s *jsonSchema) matchDocument(doc *types.Document) bool {
+	for _, field := range s.required {
+		if !doc.Has(field) {
+			return false
+		}
+	}
+
+	if s.minProperties != nil && int64(doc.Len()) < *s.minProperties {
+		return false
+	}
+
+	if s.maxProperties != nil && int64(doc.Len()) > *s.maxProperties {
+		return false
+	}
+
+	for _, k := range doc.Keys() {
+		v := must.NotFail(doc.Get(k))
+
+		var matched bool
+
+		if sub, ok := s.properties[k]; ok {
+			matched = true
+
+			if !sub.match(v) {
+				return false
+			}
+		}
+
+		for re, sub := range s.patternProperties {
+			if !re.MatchString(k) {
+				continue
+			}
+
+			matched = true
+
+			if !sub.match(v) {
+				return false
+			}
+		}
+
+		if !matched && s.additionalProperties != nil && !s.additionalProperties.match(v) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchArray returns true if the given array matches array keywords.
+func (s *jsonSchema) matchArray(arr *types.Array) bool {
+	if s.minItems != nil && int64(arr.Len()) < *s.minItems {
+		return false
+	}
+
+	if s.maxItems != nil && int64(arr.Len()) > *s.maxItems {
+		return false
+	}
+
+	for i := 0; i < arr.Len(); i++ {
+		v := must.NotFail(arr.Get(i))
+
+		switch {
+		case s.items != nil:
+			if !s.items.match(v) {
+				return false
+			}
+
+		case s.itemsList != nil && i < len(s.itemsList):
+			if !s.itemsList[i].match(v) {
+				return false
+			}
+
+		case s.itemsList != nil && s.additionalItems != nil:
+			if !s.additionalItems.match(v) {
+				return false
+			}
+		}
+
+		if !s.uniqueItems {
+			continue
+		}
+
+		for j := 0; j < i; j++ {
+			if types.CompareForAggregation(v, must.NotFail(arr.Get(j))) == types.Equal {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// matchString returns true if the given string matches string keywords.
+func (s *jsonSchema) matchString(str string) bool {
+	l := int64(utf8.RuneCountInString(str))
+
+	if s.minLength != nil && l < *s.minLength {
+		return false
+	}
+
+	if s.maxLength != nil && l > *s.maxLength {
+		return false
+	}
+
+	if s.pattern != nil && !s.pattern.MatchString(str) {
+		return false
+	}
+
+	return true
+}
+
+// matchNumber returns true if the given number matches number keywords.
+func (s *jsonSchema) matchNumber(n any) bool {
+	if s.minimum != nil {
+		switch types.Compare(n, s.minimum) {
+		case types.Less:
+			return false
+		case types.Equal:
+			if s.exclusiveMinimum {
+				return false
+			}
+		}
+	}
+
+	if s.maximum != nil {
+		switch types.Compare(n, s.maximum) {
+		case types.Greater:
+			return false
+		case types.Equal:
+			if s.exclusiveMaximum {
+				return false
+			}
+		}
+	}
+
+	if s.multipleOf != nil {
+		if math.Mod(jsonSchemaFloat(n), jsonSchemaFloat(s.multipleOf)) != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// jsonSchemaFloat converts the given number to float64.
+func jsonSchemaFloat(n any) float64 {
+	switch n := n.(type) {
+	case float64:
+		return n
+	case int32:
+		return float64(n)
+	case int64:
+		return float64(n)
+	case types.Decimal128:
+		return n.Float64()
+	default:
+		panic(fmt.Sprintf("unexpected type %T", n))
+	}
+}
@@ -317,9 +317,18 @@ func getBinaryMaskParam(operator string, mask any) (uint64, error) {
 }
 
 // addNumbers returns the result of v1 and v2 addition and error if addition failed.
-// The v1 and v2 parameters could be float64, int32, int64.
-// The result would be the broader type possible, i.e. int32 + int64 produces int64.
+// The v1 and v2 parameters could be float64, int32, int64, Decimal128.
+// The result would be the broader type possible, i.e. int32 + int64 produces int64,
+// and any number + Decimal128 produces Decimal128.
 func addNumbers(v1, v2 any) (any, error) {
+	if d1, d2, ok, err := decimalOperands(v1, v2); ok || err != nil {
+		if err != nil {
+			return nil, err
+		}
+
+		return d1.Add(d2), nil
+	}
+
 	switch v1 := v1.(type) {
 	case float64:
 		switch v2 := v2.(type) {
@@ -398,11 +407,19 @@ func addNumbers(v1, v2 any) (any, error) {
 }
 
 // multiplyNumbers returns the multiplication of v1 and v2.
-// The v1 and v2 parameters could be float64, int32 and int64.
+// The v1 and v2 parameters could be float64, int32, int64 and Decimal128.
 // Multiplication of negative number with zero produces 0, not -0.
 // The produced result maybe be the broader type:
-// int32 * int64 produces int64.
+// int32 * int64 produces int64, any number * Decimal128 produces Decimal128.
 func multiplyNumbers(v1, v2 any) (any, error) {
+	if d1, d2, ok, err := decimalOperands(v1, v2); ok || err != nil {
+		if err != nil {
+			return nil, err
+		}
+
+		return d1.Mul(d2), nil
+	}
+
 	switch v1 := v1.(type) {
 	case float64:
 		var res float64
@@ -458,6 +475,47 @@ func multiplyNumbers(v1, v2 any) (any, error) {
 	}
 }
 
+// decimalOperands converts both operands to Decimal128 if at least one of them is Decimal128.
+// It returns false if neither operand is Decimal128.
+// Double operands are converted with 15 significant digits, like MongoDB does.
+func decimalOperands(v1, v2 any) (types.Decimal128, types.Decimal128, bool, error) {
+	_, ok1 := v1.(types.Decimal128)
+	_, ok2 := v2.(types.Decimal128)
+
+	if !ok1 && !ok2 {
+		return types.Decimal128{}, types.Decimal128{}, false, nil
+	}
+
+	d1, ok := toDecimal128(v1)
+	if !ok {
+		return types.Decimal128{}, types.Decimal128{}, false, handlerparams.ErrUnexpectedLeftOpType
+	}
+
+	d2, ok := toDecimal128(v2)
+	if !ok {
+		return types.Decimal128{}, types.Decimal128{}, false, handlerparams.ErrUnexpectedRightOpType
+	}
+
+	return d1, d2, true, nil
+}
+
+// toDecimal128 converts a number to Decimal128.
+// It returns false if the value is not a number.
+func toDecimal128(v any) (types.Decimal128, bool) {
+	switch v := v.(type) {
+	case types.Decimal128:
+		return v, true
+	case float64:
+		return types.NewDecimal128FromFloat64(v), true
+	case int32:
+		return types.NewDecimal128FromInt64(int64(v)), true
+	case int64:
+		return types.NewDecimal128FromInt64(v), true
+	default:
+		return types.Decimal128{}, false
+	}
+}
+
 // multiplyLongSafely returns the multiplication of two int64 values.
 // It handles int64 overflows, and returns errLongExceeded error on one.
 //
@@ -152,11 +152,12 @@ func ValidateProjection(projection *types.Document) (*types.Document, bool, erro
 				fmt.Sprintf("projection expression %s is not supported", types.FormatAnyValue(value)),
 			)
 		case *types.Array, string, types.Binary, types.ObjectID,
-			time.Time, types.NullType, types.Regex, types.Timestamp: // all these types are treated as new fields value
+			time.Time, types.NullType, types.Regex, types.Timestamp,
+			types.MinKeyType, types.MaxKeyType: // all these types are treated as new fields value
 			inclusionField = true
 
 			validated.Set(key, value)
-		case float64, int32, int64:
+		case float64, int32, int64, types.Decimal128:
 			// projection treats 0 as false and any other value as true
 			comparison := types.Compare(value, int32(0))
 
@@ -253,7 +254,8 @@ func ProjectDocument(doc, projection, filter *types.Document, inclusion bool) (*
 			)
 
 		case *types.Array, string, types.Binary, types.ObjectID,
-			time.Time, types.NullType, types.Regex, types.Timestamp: // all this types are treated as new fields value
+			time.Time, types.NullType, types.Regex, types.Timestamp,
+			types.MinKeyType, types.MaxKeyType: // all this types are treated as new fields value
 			projected.Set("_id", idValue)
 
 			set = true
@@ -324,7 +326,8 @@ func projectDocumentWithoutID(doc *types.Document, projection, filter *types.Doc
 			)
 
 		case *types.Array, string, types.Binary, types.ObjectID,
-			time.Time, types.NullType, types.Regex, types.Timestamp: // all these types are treated as new fields value
+			time.Time, types.NullType, types.Regex, types.Timestamp,
+			types.MinKeyType, types.MaxKeyType: // all these types are treated as new fields value
 			projected.Set(key, value)
 
 		case bool: // field: bool
@@ -31,6 +31,21 @@ import (
 //
 // If sort path is invalid, it returns a possibly wrapped types.PathError.
 func SortDocuments(docs []*types.Document, sortDoc *types.Document) error {
+	return sortDocuments(docs, sortDoc, nil)
+}
+
+// SortDocumentsWithCollation is like [SortDocuments], but uses the collation for strings.
+//
+// Nil collation compares strings as bytes.
+func SortDocumentsWithCollation(docs []*types.Document, sortDoc *types.Document, c *types.Collation) error {
+	return sortDocuments(docs, sortDoc, c)
+}
+
+// sortDocuments sorts given documents in place according to the given sorting conditions
+// using given collation for string comparison.
+//
+// If sort path is invalid, it returns a possibly wrapped types.PathError.
+func sortDocuments(docs []*types.Document, sortDoc *types.Document, c *types.Collation) error {
 	if sortDoc.Len() == 0 {
 		return nil
 	}
@@ -71,7 +86,7 @@ func SortDocuments(docs []*types.Document, sortDoc *types.Document) error {
 			return err
 		}
 
-		sortFuncs[i] = lessFunc(sortPath, sortType)
+		sortFuncs[i] = lessFunc(sortPath, sortType, c)
 	}
 
 	if len(sortFuncs) == 0 {
@@ -141,9 +156,9 @@ func ValidateSortDocument(sortDoc *types.Document) (*types.Document, error) {
 	return res, nil
 }
 
-// lessFunc takes sort key and type and returns sort.Interface's Less function which
+// lessFunc takes sort key, type and collation and returns sort.Interface's Less function which
 // compares selected key of 2 documents.
-func lessFunc(sortPath types.Path, sortType types.SortType) func(a, b *types.Document) bool {
+func lessFunc(sortPath types.Path, sortType types.SortType, c *types.Collation) func(a, b *types.Document) bool {
 	return func(a, b *types.Document) bool {
 		aField, err := a.GetByPath(sortPath)
 		if err != nil {
@@ -158,7 +173,7 @@ func lessFunc(sortPath types.Path, sortType types.SortType) func(a, b *types.Doc
 			bField = types.Null
 		}
 
-		result := types.CompareOrderForSort(aField, bField, sortType)
+		result := c.CompareOrderForSort(aField, bField, sortType)
 
 		return result == types.Less
 	}
@@ -21,11 +21,12 @@ import (
 )
 
 // SortIterator returns an iterator of sorted documents.
+// Strings are compared using the given collation; nil collation compares them as bytes.
 // It will be added to the given closer.
 //
 // Since sorting iterator is impossible, this function fully consumes and closes the underlying iterator,
 // sorts documents in memory and returns a new iterator over the sorted slice.
-func SortIterator(iter types.DocumentsIterator, closer *iterator.MultiCloser, sort *types.Document) (types.DocumentsIterator, error) { //nolint:lll // for readability
+func SortIterator(iter types.DocumentsIterator, closer *iterator.MultiCloser, sort *types.Document, collation *types.Collation) (types.DocumentsIterator, error) { //nolint:lll // for readability
 	// don't consume all documents if there is no sort
 	if sort.Len() == 0 {
 		return iter, nil
@@ -36,7 +37,7 @@ func SortIterator(iter types.DocumentsIterator, closer *iterator.MultiCloser, so
 		return nil, lazyerrors.Error(err)
 	}
 
-	if err = SortDocuments(docs, sort); err != nil {
+	if err = sortDocuments(docs, sort, collation); err != nil {
 		return nil, lazyerrors.Error(err)
 	}
 
@@ -0,0 +1,111 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+// TransactionParams represents parameters of a command that is a part of multi-document transaction.
+type TransactionParams struct {
+	SessionID        types.Binary
+	TxnNumber        int64
+	StartTransaction bool
+}
+
+// GetTransactionParams returns transaction parameters of the given command document.
+//
+// It returns nil if the command is not a part of multi-document transaction;
+// that is the case if `autocommit` field is absent.
+// Commands with only `lsid` and `txnNumber` fields (retryable writes) are not transactions.
+func GetTransactionParams(document *types.Document) (*TransactionParams, error) {
+	v, _ := document.Get("autocommit")
+	if v == nil {
+		if v, _ = document.Get("startTransaction"); v != nil {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrInvalidOptions,
+				"Specifying startTransaction requires autocommit=false",
+				"startTransaction",
+			)
+		}
+
+		return nil, nil
+	}
+
+	autocommit, err := GetOptionalParam(document, "autocommit", false)
+	if err != nil {
+		return nil, err
+	}
+
+	if autocommit {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrInvalidOptions,
+			"Specifying autocommit=true is not allowed.",
+			"autocommit",
+		)
+	}
+
+	lsid, err := GetOptionalParam[*types.Document](document, "lsid", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if lsid == nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrInvalidOptions,
+			"Transaction number requires a session ID to also be specified",
+			"lsid",
+		)
+	}
+
+	var res TransactionParams
+
+	if res.SessionID, err = GetRequiredParam[types.Binary](lsid, "id"); err != nil {
+		return nil, err
+	}
+
+	v, _ = document.Get("txnNumber")
+	if v == nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrInvalidOptions,
+			"'autocommit' field requires a transaction number to also be specified",
+			"txnNumber",
+		)
+	}
+
+	if res.TxnNumber, err = handlerparams.GetWholeNumberParam(v); err != nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrTypeMismatch,
+			"BSON field 'OperationSessionInfo.txnNumber' is the wrong type, expected type 'long'",
+			"txnNumber",
+		)
+	}
+
+	if res.StartTransaction, err = GetOptionalParam(document, "startTransaction", false); err != nil {
+		return nil, err
+	}
+
+	if v, _ = document.Get("startTransaction"); v != nil && !res.StartTransaction {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrInvalidOptions,
+			"Specifying startTransaction=false is not allowed.",
+			"startTransaction",
+		)
+	}
+
+	return &res, nil
+}
@@ -45,13 +45,15 @@ type kvOp struct {
 // In case of updating multiple documents, UpdateDocument returns an error immediately after one of the
 // operation fails. The rest of the documents are not processed.
 // TODO https://github.com/FerretDB/FerretDB/issues/2612
-func UpdateDocument(ctx context.Context, c backends.Collection, cmd string, iter types.DocumentsIterator, param *Update) (*UpdateResult, error) { //nolint:lll // for readability
+//
+// Updated and upserted documents are checked by the given validator that may be nil.
+func UpdateDocument(ctx context.Context, c backends.Collection, cmd string, iter types.DocumentsIterator, param *Update, v *DocumentValidator) (*UpdateResult, error) { //nolint:lll // for readability
 	result := new(UpdateResult)
 
 	isFindAndModify := (strings.ToLower(cmd) == "findandmodify")
 
 	for {
-		var upsert, modified bool
+		var upsert, modified, validate bool
 
 		_, doc, err := iter.Next()
 		if err != nil {
@@ -73,11 +75,16 @@ func UpdateDocument(ctx context.Context, c backends.Collection, cmd string, iter
 			if err = processFilterEqualityCondition(doc, param.Filter); err != nil {
 				return nil, lazyerrors.Error(err)
 			}
+			validate = v != nil
 		} else {
 			result.Matched.Count++
 			if isFindAndModify {
 				result.Matched.Doc = doc.DeepCopy()
 			}
+
+			if validate, err = v.Applies(doc); err != nil {
+				return nil, lazyerrors.Error(err)
+			}
 		}
 
 		if !param.HasUpdateOperators {
@@ -99,6 +106,12 @@ func UpdateDocument(ctx context.Context, c backends.Collection, cmd string, iter
 			return nil, lazyerrors.Error(err)
 		}
 
+		if validate && (upsert || modified) {
+			if err = v.Validate(cmd, doc); err != nil {
+				return nil, err
+			}
+		}
+
 		if upsert {
 			_, err = c.InsertAll(ctx, &backends.InsertAllParams{Docs: []*types.Document{doc}})
 			if err != nil {
@@ -502,7 +515,7 @@ func processRenameFieldExpression(command string, doc *types.Document, key strin
 func processIncFieldExpression(command string, doc *types.Document, incKey string, incValue any) (bool, error) {
 	// ensure incValue is a valid number type.
 	switch incValue.(type) {
-	case float64, int32, int64:
+	case float64, int32, int64, types.Decimal128:
 	default:
 		return false, NewUpdateError(
 			handlererrors.ErrTypeMismatch,
@@ -696,6 +709,8 @@ func processMulFieldExpression(command string, doc *types.Document, mulKey strin
 			mulValue = int32(0)
 		case int64:
 			mulValue = int64(0)
+		case types.Decimal128:
+			mulValue = types.NewDecimal128FromInt64(0)
 		default:
 			return false, NewUpdateError(
 				handlererrors.ErrTypeMismatch,
@@ -37,14 +37,17 @@ type UpdateParams struct {
 	Let *types.Document `ferretdb:"let,unimplemented"`
 
 	Ordered                  bool            `ferretdb:"ordered,ignored"`
-	BypassDocumentValidation bool            `ferretdb:"bypassDocumentValidation,ignored"`
+	BypassDocumentValidation bool            `ferretdb:"bypassDocumentValidation,opt"`
 	WriteConcern             *types.Document `ferretdb:"writeConcern,ignored"`
-	LSID                     any             `ferretdb:"lsid,ignored"`
-	TxnNumber                int64           `ferretdb:"txnNumber,ignored"`
-	Autocommit               bool            `ferretdb:"autocommit,ignored"`
 	ClusterTime              any             `ferretdb:"$clusterTime,ignored"`
 	ReadPreference           *types.Document `ferretdb:"$readPreference,ignored"`
 
+	// transaction fields are handled by GetTransactionParams
+	LSID             any   `ferretdb:"lsid,opt"`
+	TxnNumber        int64 `ferretdb:"txnNumber,opt"`
+	StartTransaction bool  `ferretdb:"startTransaction,opt"`
+	Autocommit       bool  `ferretdb:"autocommit,opt"`
+
 	ApiVersion           string `ferretdb:"apiVersion,ignored"`
 	ApiStrict            bool   `ferretdb:"apiStrict,ignored"`
 	ApiDeprecationErrors bool   `ferretdb:"apiDeprecationErrors,ignored"`
@@ -59,10 +62,11 @@ type Update struct {
 	Multi  bool            `ferretdb:"multi,opt"`
 	Upsert bool            `ferretdb:"upsert,opt,numericBool"`
 
-	HasUpdateOperators bool `ferretdb:"-"`
+	HasUpdateOperators bool             `ferretdb:"-"`
+	Collation          *types.Collation `ferretdb:"-"`
 
 	C            *types.Document `ferretdb:"c,unimplemented"`
-	Collation    *types.Document `ferretdb:"collation,unimplemented"`
+	CollationDoc *types.Document `ferretdb:"collation,opt"`
 	ArrayFilters *types.Array    `ferretdb:"arrayFilters,unimplemented"`
 
 	Hint string `ferretdb:"hint,ignored"`
@@ -100,6 +104,10 @@ func GetUpdateParams(document *types.Document, l *slog.Logger) (*UpdateParams, e
 		for i := range params.Updates {
 			update := &params.Updates[i]
 
+			if update.Collation, err = GetCollation(update.CollationDoc, "update"); err != nil {
+				return nil, err
+			}
+
 			if update.Update == nil {
 				continue
 			}
@@ -0,0 +1,214 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+)
+
+// Document validation levels.
+const (
+	ValidationLevelOff      = "off"
+	ValidationLevelStrict   = "strict"
+	ValidationLevelModerate = "moderate"
+)
+
+// Document validation actions.
+const (
+	ValidationActionError = "error"
+	ValidationActionWarn  = "warn"
+)
+
+// DocumentValidator checks documents against the collection's validator.
+//
+// Nil *DocumentValidator accepts all documents;
+// all its methods could be called on nil receiver.
+type DocumentValidator struct {
+	validator *types.Document
+	level     string
+	action    string
+	l         *slog.Logger
+}
+
+// NewDocumentValidator returns a validator for the given collection.
+//
+// It returns nil if the collection does not exist, has no validator, or validation is off.
+func NewDocumentValidator(c *backends.CollectionInfo, l *slog.Logger) *DocumentValidator {
+	if c == nil || c.Validator == nil || c.ValidationLevel == ValidationLevelOff {
+		return nil
+	}
+
+	return &DocumentValidator{
+		validator: c.Validator,
+		level:     c.ValidationLevel,
+		action:    c.ValidationAction,
+		l:         l,
+	}
+}
+
+// Applies returns true if the update of the given existing document should be validated.
+//
+// With moderate validation level, updates of documents that do not match the validator are not validated.
+func (v *DocumentValidator) Applies(doc *types.Document) (bool, error) {
+	if v == nil {
+		return false, nil
+	}
+
+	if v.level != ValidationLevelModerate {
+		return true, nil
+	}
+
+	return FilterDocument(doc, v.validator)
+}
+
+// Match returns true if the given new or updated document matches the validator.
+//
+// With warn validation action, the mismatch is logged, and true is returned.
+func (v *DocumentValidator) Match(doc *types.Document) (bool, error) {
+	if v == nil {
+		return true, nil
+	}
+
+	ok, err := FilterDocument(doc, v.validator)
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	if ok {
+		return true, nil
+	}
+
+	if v.action == ValidationActionWarn {
+		id, _ := doc.Get("_id")
+		v.l.Warn("Document would fail validation", slog.String("_id", types.FormatAnyValue(id)))
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// Validate returns DocumentValidationFailure error if the given new or updated document does not match the validator.
+//
+// Returned error is CommandError for findAndModify command, WriteError for other commands.
+func (v *DocumentValidator) Validate(command string, doc *types.Document) error {
+	ok, err := v.Match(doc)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	if !ok {
+		return NewUpdateError(handlererrors.ErrDocumentValidationFailure, "Document failed validation", command)
+	}
+
+	return nil
+}
+
+// ValidateValidator returns an error if the given validator can't be used for documents validation.
+func ValidateValidator(validator *types.Document) error {
+	// validator is parsed by the filter engine, so invalid operators and schemas are reported
+	_, err := FilterDocument(new(types.Document), validator)
+
+	return err
+}
+
+// ValidationOptions represents document validation options of `create` and `collMod` commands.
+//
+// Absent options are represented by zero values.
+type ValidationOptions struct {
+	Validator *types.Document
+	Level     string
+	Action    string
+}
+
+// GetValidationOptions returns document validation options of the given command document.
+func GetValidationOptions(document *types.Document) (*ValidationOptions, error) {
+	command := document.Command()
+
+	var res ValidationOptions
+
+	if v, _ := document.Get("validator"); v != nil {
+		var ok bool
+		if res.Validator, ok = v.(*types.Document); !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrTypeMismatch,
+				fmt.Sprintf(
+					"BSON field '%s.validator' is the wrong type '%s', expected type 'object'",
+					command, handlerparams.AliasFromType(v),
+				),
+				command,
+			)
+		}
+
+		if err := ValidateValidator(res.Validator); err != nil {
+			return nil, err
+		}
+	}
+
+	var err error
+
+	levels := []string{ValidationLevelOff, ValidationLevelStrict, ValidationLevelModerate}
+	if res.Level, err = getValidationEnum(document, "validationLevel", levels); err != nil {
+		return nil, err
+	}
+
+	actions := []string{ValidationActionError, ValidationActionWarn}
+	if res.Action, err = getValidationEnum(document, "validationAction", actions); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// getValidationEnum returns the value of the given string field that should be one of the given values.
+//
+// Empty string is returned if the field is absent.
+func getValidationEnum(document *types.Document, field string, values []string) (string, error) {
+	command := document.Command()
+
+	v, _ := document.Get(field)
+	if v == nil {
+		return "", nil
+	}
+
+	s, ok := v.(string)
+	if !ok {
+		return "", handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrTypeMismatch,
+			fmt.Sprintf(
+				"BSON field '%s.%s' is the wrong type '%s', expected type 'string'",
+				command, field, handlerparams.AliasFromType(v),
+			),
+			command,
+		)
+	}
+
+	if !slices.Contains(values, s) {
+		return "", handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			fmt.Sprintf("Enumeration value '%s' for field '%s.%s' is not a valid value.", s, command, field),
+			command,
+		)
+	}
+
+	return s, nil
+}
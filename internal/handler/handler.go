@@ -31,6 +31,7 @@ import (
 	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
 	"github.com/FerretDB/FerretDB/internal/clientconn/connmetrics"
 	"github.com/FerretDB/FerretDB/internal/clientconn/cursor"
+	"github.com/FerretDB/FerretDB/internal/clientconn/session"
 	"github.com/FerretDB/FerretDB/internal/handler/users"
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/ctxutil"
@@ -69,12 +70,19 @@ type Handler struct {
 	b backends.Backend
 
 	cursors  *cursor.Registry
+	sessions *session.Registry
+	txns     *transactions
 	commands map[string]*command
 	wg       sync.WaitGroup
 
+	sessionsCleanupStop chan struct{}
+
 	cappedCleanupStop             chan struct{}
 	cleanupCappedCollectionsDocs  *prometheus.CounterVec
 	cleanupCappedCollectionsBytes *prometheus.CounterVec
+
+	ttlCleanupStop chan struct{}
+	cleanupTTLDocs *prometheus.CounterVec
 }
 
 // NewOpts represents handler configuration.
@@ -99,6 +107,7 @@ type NewOpts struct {
 	EnableNestedPushdown    bool
 	CappedCleanupInterval   time.Duration
 	CappedCleanupPercentage uint8
+	TTLCleanupInterval      time.Duration
 	EnableNewAuth           bool
 	BatchSize               int
 	MaxBsonObjectSizeBytes  int
@@ -127,6 +136,13 @@ func New(opts *NewOpts) (*Handler, error) {
 		b:       b,
 		NewOpts: opts,
 		cursors: cursor.NewRegistry(logging.WithName(opts.L, "cursors")),
+		sessions: session.NewRegistry(
+			time.Duration(logicalSessionTimeoutMinutes)*time.Minute,
+			logging.WithName(opts.L, "sessions"),
+		),
+		txns: newTransactions(),
+
+		sessionsCleanupStop: make(chan struct{}),
 
 		cappedCleanupStop: make(chan struct{}),
 		cleanupCappedCollectionsDocs: prometheus.NewCounterVec(
@@ -147,6 +163,17 @@ func New(opts *NewOpts) (*Handler, error) {
 			},
 			[]string{"db", "collection"},
 		),
+
+		ttlCleanupStop: make(chan struct{}),
+		cleanupTTLDocs: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "cleanup_ttl_docs",
+				Help:      "Total number of expired documents deleted by TTL indexes cleanup.",
+			},
+			[]string{"db", "collection"},
+		),
 	}
 
 	if err := h.setup(); err != nil {
@@ -156,7 +183,7 @@ func New(opts *NewOpts) (*Handler, error) {
 
 	h.initCommands()
 
-	h.wg.Add(1)
+	h.wg.Add(3)
 
 	go func() {
 		defer h.wg.Done()
@@ -164,6 +191,18 @@ func New(opts *NewOpts) (*Handler, error) {
 		h.runCappedCleanup()
 	}()
 
+	go func() {
+		defer h.wg.Done()
+
+		h.runTTLCleanup()
+	}()
+
+	go func() {
+		defer h.wg.Done()
+
+		h.runSessionsCleanup()
+	}()
+
 	return h, nil
 }
 
@@ -276,7 +315,10 @@ func (h *Handler) runCappedCleanup() {
 // It should be called after listener closes all client connections and stops listening.
 func (h *Handler) Close() {
 	h.cursors.Close()
+	h.abortAllTransactions()
 	close(h.cappedCleanupStop)
+	close(h.ttlCleanupStop)
+	close(h.sessionsCleanupStop)
 	h.wg.Wait()
 }
 
@@ -286,6 +328,7 @@ func (h *Handler) Describe(ch chan<- *prometheus.Desc) {
 	h.cursors.Describe(ch)
 	h.cleanupCappedCollectionsDocs.Describe(ch)
 	h.cleanupCappedCollectionsBytes.Describe(ch)
+	h.cleanupTTLDocs.Describe(ch)
 }
 
 // Collect implements [prometheus.Collector].
@@ -294,6 +337,7 @@ func (h *Handler) Collect(ch chan<- prometheus.Metric) {
 	h.cursors.Collect(ch)
 	h.cleanupCappedCollectionsDocs.Collect(ch)
 	h.cleanupCappedCollectionsBytes.Collect(ch)
+	h.cleanupTTLDocs.Collect(ch)
 }
 
 // cleanupAllCappedCollections drops the given percent of documents from all capped collections.
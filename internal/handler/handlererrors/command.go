@@ -27,9 +27,10 @@ import (
 type CommandError struct {
 	// the order of fields is weird to make the struct smaller due to alignment
 
-	err  error
-	info *ErrInfo
-	code ErrorCode
+	err    error
+	info   *ErrInfo
+	labels []string
+	code   ErrorCode
 }
 
 // There should not be NewCommandError function variant that accepts printf-like format specifiers.
@@ -67,6 +68,16 @@ func NewCommandErrorMsgWithArgument(code ErrorCode, msg string, argument string)
 	}
 }
 
+// NewCommandErrorMsgWithLabels is variant for NewCommandErrorMsg with error labels,
+// like "TransientTransactionError".
+func NewCommandErrorMsgWithLabels(code ErrorCode, msg string, labels ...string) error {
+	return &CommandError{
+		code:   code,
+		err:    errors.New(msg),
+		labels: labels,
+	}
+}
+
 // Err returns original error.
 //
 // It is not called Unwrap to prevent unwrapping by errors.Is and errors.As.
@@ -97,6 +108,15 @@ func (e *CommandError) Document() *wirebson.Document {
 		must.NoError(d.Add("codeName", e.code.String()))
 	}
 
+	if len(e.labels) > 0 {
+		labels := wirebson.MakeArray(len(e.labels))
+		for _, l := range e.labels {
+			must.NoError(labels.Add(l))
+		}
+
+		must.NoError(d.Add("errorLabels", labels))
+	}
+
 	return d
 }
 
@@ -113,9 +113,15 @@ const (
 	// ErrOperationFailed indicates that the operation failed.
 	ErrOperationFailed = ErrorCode(96) // OperationFailed
 
+	// ErrConflictingOperationInProgress indicates that the operation conflicts with another one in progress.
+	ErrConflictingOperationInProgress = ErrorCode(117) // ConflictingOperationInProgress
+
 	// ErrDocumentValidationFailure indicates that document validation failed.
 	ErrDocumentValidationFailure = ErrorCode(121) // DocumentValidationFailure
 
+	// ErrExceededMemoryLimit indicates that the operation exceeded the memory limit.
+	ErrExceededMemoryLimit = ErrorCode(146) // ExceededMemoryLimit
+
 	// ErrInvalidIndexSpecificationOption indicates that the index option is invalid.
 	ErrInvalidIndexSpecificationOption = ErrorCode(197) // InvalidIndexSpecificationOption
 
@@ -125,30 +131,66 @@ const (
 	// ErrClientMetadataCannotBeMutated indicates that client metadata cannot be mutated.
 	ErrClientMetadataCannotBeMutated = ErrorCode(186) // ClientMetadataCannotBeMutated
 
+	// ErrInvalidUUID indicates that the UUID is invalid.
+	ErrInvalidUUID = ErrorCode(207) // InvalidUUID
+
+	// ErrTransactionTooOld indicates that a newer transaction was already started for the session.
+	ErrTransactionTooOld = ErrorCode(225) // TransactionTooOld
+
 	// ErrNotImplemented indicates that a flag or command is not implemented.
 	ErrNotImplemented = ErrorCode(238) // NotImplemented
 
+	// ErrConversionFailure indicates that the value conversion failed.
+	ErrConversionFailure = ErrorCode(241) // ConversionFailure
+
+	// ErrNoSuchTransaction indicates that the transaction does not exist or was aborted.
+	ErrNoSuchTransaction = ErrorCode(251) // NoSuchTransaction
+
+	// ErrTransactionCommitted indicates that the transaction was already committed.
+	ErrTransactionCommitted = ErrorCode(256) // TransactionCommitted
+
+	// ErrInvalidResumeToken indicates that the change stream resume token is invalid.
+	ErrInvalidResumeToken = ErrorCode(260) // InvalidResumeToken
+
+	// ErrOperationNotSupportedInTransaction indicates that the command can't be run in a transaction.
+	ErrOperationNotSupportedInTransaction = ErrorCode(263) // OperationNotSupportedInTransaction
+
 	// ErrMechanismUnavailable indicates that the authentication mechanism is unavailable.
 	ErrMechanismUnavailable = ErrorCode(334)
 
 	// ErrUnsupportedOpQueryCommand indicates that given op query is not supported.
 	ErrUnsupportedOpQueryCommand = ErrorCode(352) // UnsupportedOpQueryCommand
 
+	// ErrMergeStageNoMatchingDocument indicates that $merge could not find a matching document.
+	ErrMergeStageNoMatchingDocument = ErrorCode(13113) // MergeStageNoMatchingDocument
+
 	// ErrIndexesWrongType indicates that indexes parameter has wrong type.
 	ErrIndexesWrongType = ErrorCode(10065) // Location10065
 
+	// ErrBSONObjectTooLarge indicates that document is too large.
+	ErrBSONObjectTooLarge = ErrorCode(10334) // BSONObjectTooLarge
+
 	// ErrDuplicateKeyInsert indicates duplicate key violation on inserting document.
 	ErrDuplicateKeyInsert = ErrorCode(11000) // DuplicateKey
 
 	// ErrSetBadExpression indicates set expression is not object.
 	ErrSetBadExpression = ErrorCode(40272) // Location40272
 
+	// ErrStageOutInvalidArg indicates that $out argument is not a string or an object.
+	ErrStageOutInvalidArg = ErrorCode(16990) // Location16990
+
+	// ErrStageOutCappedCollection indicates that $out output collection is capped.
+	ErrStageOutCappedCollection = ErrorCode(17152) // Location17152
+
 	// ErrStageGroupInvalidFields indicates group's fields must be an object.
 	ErrStageGroupInvalidFields = ErrorCode(15947) // Location15947
 
 	// ErrStageGroupID indicates _id for a group can only be specified once.
 	ErrStageGroupID = ErrorCode(15948) // Location15948
 
+	// ErrStageGroupUnknownAccumulator indicates that $group accumulator is unknown.
+	ErrStageGroupUnknownAccumulator = ErrorCode(15952) // Location15952
+
 	// ErrStageGroupMissingID indicates that group is missing an _id.
 	ErrStageGroupMissingID = ErrorCode(15955) // Location15955
 
@@ -185,6 +227,21 @@ const (
 	// ErrPathContainsEmptyElement indicates that the path contains an empty element.
 	ErrPathContainsEmptyElement = ErrorCode(15998) // Location15998
 
+	// ErrConvertToDate indicates that the value can't be converted to date.
+	ErrConvertToDate = ErrorCode(16006) // Location16006
+
+	// ErrConvertToLong indicates that the value can't be converted to long.
+	ErrConvertToLong = ErrorCode(16004) // Location16004
+
+	// ErrConvertToString indicates that the value can't be converted to string.
+	ErrConvertToString = ErrorCode(16007) // Location16007
+
+	// ErrSubstrStartNonNumeric indicates that $substrBytes operator starting index is not a number.
+	ErrSubstrStartNonNumeric = ErrorCode(16034) // Location16034
+
+	// ErrSubstrLengthNonNumeric indicates that $substrBytes operator length is not a number.
+	ErrSubstrLengthNonNumeric = ErrorCode(16035) // Location16035
+
 	// ErrOperatorWrongLenOfArgs indicates that aggregation operator contains
 	// wrong amount of arguments.
 	ErrOperatorWrongLenOfArgs = ErrorCode(16020) // Location16020
@@ -192,21 +249,213 @@ const (
 	// ErrFieldPathInvalidName indicates that FieldPath is invalid.
 	ErrFieldPathInvalidName = ErrorCode(16410) // Location16410
 
+	// ErrDivideByZero indicates that $divide operator has zero divisor.
+	ErrDivideByZero = ErrorCode(16608) // Location16608
+
+	// ErrDivideNonNumeric indicates that $divide operator has non-numeric argument.
+	ErrDivideNonNumeric = ErrorCode(16609) // Location16609
+
+	// ErrModByZero indicates that $mod operator has zero divisor.
+	ErrModByZero = ErrorCode(16610) // Location16610
+
+	// ErrModNonNumeric indicates that $mod operator has non-numeric argument.
+	ErrModNonNumeric = ErrorCode(16611) // Location16611
+
+	// ErrAddMultipleDates indicates that $add operator has more than one date argument.
+	ErrAddMultipleDates = ErrorCode(16612) // Location16612
+
+	// ErrConcatNonString indicates that $concat operator argument is not a string.
+	ErrConcatNonString = ErrorCode(16702) // Location16702
+
 	// ErrGroupInvalidFieldPath indicates invalid path is given for group _id.
 	ErrGroupInvalidFieldPath = ErrorCode(16872) // Location16872
 
+	// ErrLetNotObject indicates that $let argument is not an object.
+	ErrLetNotObject = ErrorCode(16874) // Location16874
+
+	// ErrLetUnknownField indicates that $let argument contains an unknown field.
+	ErrLetUnknownField = ErrorCode(16875) // Location16875
+
+	// ErrLetMissingVars indicates that $let vars is not specified.
+	ErrLetMissingVars = ErrorCode(16876) // Location16876
+
+	// ErrLetMissingIn indicates that $let in is not specified.
+	ErrLetMissingIn = ErrorCode(16877) // Location16877
+
+	// ErrMapNotObject indicates that $map argument is not an object.
+	ErrMapNotObject = ErrorCode(16878) // Location16878
+
+	// ErrMapUnknownField indicates that $map argument contains an unknown field.
+	ErrMapUnknownField = ErrorCode(16879) // Location16879
+
+	// ErrMapMissingInput indicates that $map input is not specified.
+	ErrMapMissingInput = ErrorCode(16880) // Location16880
+
+	// ErrMapMissingIn indicates that $map in is not specified.
+	ErrMapMissingIn = ErrorCode(16882) // Location16882
+
+	// ErrMapInputNotArray indicates that $map input is not an array.
+	ErrMapInputNotArray = ErrorCode(16883) // Location16883
+
 	// ErrBadNumberToReturn indicates that invalid number to return was given for op query.
 	ErrBadNumberToReturn = ErrorCode(16979) // Location16979
 
+	// ErrRedactInvalidResult indicates that $redact expression returned a value other than $$KEEP, $$DESCEND or $$PRUNE.
+	ErrRedactInvalidResult = ErrorCode(17053) // Location17053
+
+	// ErrCondMissingIf indicates that $cond operator is missing 'if' parameter.
+	ErrCondMissingIf = ErrorCode(17080) // Location17080
+
+	// ErrCondMissingThen indicates that $cond operator is missing 'then' parameter.
+	ErrCondMissingThen = ErrorCode(17081) // Location17081
+
+	// ErrCondMissingElse indicates that $cond operator is missing 'else' parameter.
+	ErrCondMissingElse = ErrorCode(17082) // Location17082
+
+	// ErrCondUnknownParameter indicates that $cond operator has unrecognized parameter.
+	ErrCondUnknownParameter = ErrorCode(17083) // Location17083
+
+	// ErrSizeNotArray indicates that $size argument is not an array.
+	ErrSizeNotArray = ErrorCode(17124) // Location17124
+
 	// ErrGroupUndefinedVariable indicates the variable is not defined.
 	ErrGroupUndefinedVariable = ErrorCode(17276) // Location17276
 
+	// ErrDateToStringFormatNonString indicates that $dateToString operator format is not a string.
+	ErrDateToStringFormatNonString = ErrorCode(18533) // Location18533
+
+	// ErrDateToStringUnknownArgument indicates that $dateToString operator has an unknown argument.
+	ErrDateToStringUnknownArgument = ErrorCode(18534) // Location18534
+
+	// ErrDateFormatUnmatchedPercent indicates that date format string ends with unmatched '%'.
+	ErrDateFormatUnmatchedPercent = ErrorCode(18535) // Location18535
+
+	// ErrDateFormatInvalidCharacter indicates that date format string contains invalid format character.
+	ErrDateFormatInvalidCharacter = ErrorCode(18536) // Location18536
+
+	// ErrDateToStringMissingDate indicates that $dateToString operator date parameter is missing.
+	ErrDateToStringMissingDate = ErrorCode(18628) // Location18628
+
+	// ErrDateToStringNotObject indicates that $dateToString operator argument is not a document.
+	ErrDateToStringNotObject = ErrorCode(18629) // Location18629
+
+	// ErrFilterNotObject indicates that $filter argument is not an object.
+	ErrFilterNotObject = ErrorCode(28646) // Location28646
+
+	// ErrFilterUnknownField indicates that $filter argument contains an unknown field.
+	ErrFilterUnknownField = ErrorCode(28647) // Location28647
+
+	// ErrFilterMissingInput indicates that $filter input is not specified.
+	ErrFilterMissingInput = ErrorCode(28648) // Location28648
+
+	// ErrFilterMissingCond indicates that $filter cond is not specified.
+	ErrFilterMissingCond = ErrorCode(28650) // Location28650
+
+	// ErrFilterInputNotArray indicates that $filter input is not an array.
+	ErrFilterInputNotArray = ErrorCode(28651) // Location28651
+
+	// ErrSubstrBytesStartContinuation indicates that $substrBytes operator starting index is a UTF-8 continuation byte.
+	ErrSubstrBytesStartContinuation = ErrorCode(28656) // Location28656
+
+	// ErrSubstrBytesEndContinuation indicates that $substrBytes operator ending index is in the middle of a UTF-8 character.
+	ErrSubstrBytesEndContinuation = ErrorCode(28657) // Location28657
+
+	// ErrConcatArraysNotArray indicates that $concatArrays argument is not an array.
+	ErrConcatArraysNotArray = ErrorCode(28664) // Location28664
+
 	// ErrInvalidArg indicates invalid argument in projection document.
 	ErrInvalidArg = ErrorCode(28667) // Location28667
 
+	// ErrAbsLongMin indicates that $abs operator argument is the minimal long value.
+	ErrAbsLongMin = ErrorCode(28680) // Location28680
+
+	// ErrArrayElemAtFirstArg indicates that the first argument of $arrayElemAt is not an array.
+	ErrArrayElemAtFirstArg = ErrorCode(28689) // Location28689
+
+	// ErrArrayElemAtSecondArg indicates that the second argument of $arrayElemAt is not a number.
+	ErrArrayElemAtSecondArg = ErrorCode(28690) // Location28690
+
+	// ErrArrayElemAtIndexNotInt32 indicates that the index of $arrayElemAt could not be represented as a 32-bit integer.
+	ErrArrayElemAtIndexNotInt32 = ErrorCode(28691) // Location28691
+
+	// ErrSqrtNegative indicates that $sqrt operator argument is negative.
+	ErrSqrtNegative = ErrorCode(28714) // Location28714
+
 	// ErrSliceFirstArg for $slice indicates that the first argument is not an array.
 	ErrSliceFirstArg = ErrorCode(28724) // Location28724
 
+	// ErrSliceSecondArgNotNumber indicates that the second argument of $slice is not a number.
+	ErrSliceSecondArgNotNumber = ErrorCode(28725) // Location28725
+
+	// ErrSliceSecondArgNotInt32 indicates that the second argument of $slice could not be represented as a 32-bit integer.
+	ErrSliceSecondArgNotInt32 = ErrorCode(28726) // Location28726
+
+	// ErrSliceThirdArgNotNumber indicates that the third argument of $slice is not a number.
+	ErrSliceThirdArgNotNumber = ErrorCode(28727) // Location28727
+
+	// ErrSliceThirdArgNotInt32 indicates that the third argument of $slice could not be represented as a 32-bit integer.
+	ErrSliceThirdArgNotInt32 = ErrorCode(28728) // Location28728
+
+	// ErrSliceThirdArgNotPositive indicates that the third argument of $slice is not positive.
+	ErrSliceThirdArgNotPositive = ErrorCode(28729) // Location28729
+
+	// ErrSampleBadSpecification indicates that $sample stage specification is not an object.
+	ErrSampleBadSpecification = ErrorCode(28745) // Location28745
+
+	// ErrSampleSizeNotNumber indicates that size argument of $sample stage is not a number.
+	ErrSampleSizeNotNumber = ErrorCode(28746) // Location28746
+
+	// ErrSampleSizeNegative indicates that size argument of $sample stage is negative.
+	ErrSampleSizeNegative = ErrorCode(28747) // Location28747
+
+	// ErrSampleUnknownOption indicates that $sample stage specification contains an unknown field.
+	ErrSampleUnknownOption = ErrorCode(28748) // Location28748
+
+	// ErrSampleMissingSize indicates that $sample stage specification does not contain size.
+	ErrSampleMissingSize = ErrorCode(28749) // Location28749
+
+	// ErrLogNonNumeric indicates that $log operator argument is not a number.
+	ErrLogNonNumeric = ErrorCode(28756) // Location28756
+
+	// ErrLogBaseNonNumeric indicates that $log operator base is not a number.
+	ErrLogBaseNonNumeric = ErrorCode(28757) // Location28757
+
+	// ErrLogNonPositive indicates that $log operator argument is not positive.
+	ErrLogNonPositive = ErrorCode(28758) // Location28758
+
+	// ErrLogInvalidBase indicates that $log operator base is not positive or is equal to 1.
+	ErrLogInvalidBase = ErrorCode(28759) // Location28759
+
+	// ErrLog10NonPositive indicates that $log10 operator argument is not positive.
+	ErrLog10NonPositive = ErrorCode(28761) // Location28761
+
+	// ErrPowBaseNonNumeric indicates that $pow operator base is not a number.
+	ErrPowBaseNonNumeric = ErrorCode(28762) // Location28762
+
+	// ErrPowExponentNonNumeric indicates that $pow operator exponent is not a number.
+	ErrPowExponentNonNumeric = ErrorCode(28763) // Location28763
+
+	// ErrPowZeroNegativeExponent indicates that $pow operator has zero base and negative exponent.
+	ErrPowZeroNegativeExponent = ErrorCode(28764) // Location28764
+
+	// ErrOperatorNonNumeric indicates that single-argument numeric operator argument is not a number.
+	ErrOperatorNonNumeric = ErrorCode(28765) // Location28765
+
+	// ErrLnNonPositive indicates that $ln operator argument is not positive.
+	ErrLnNonPositive = ErrorCode(28766) // Location28766
+
+	// ErrRegexMissingInput indicates that regex operator is missing 'input' parameter.
+	ErrRegexMissingInput = ErrorCode(31022) // Location31022
+
+	// ErrRegexMissingRegex indicates that regex operator is missing 'regex' parameter.
+	ErrRegexMissingRegex = ErrorCode(31023) // Location31023
+
+	// ErrRegexUnknownArgument indicates that regex operator has unknown argument.
+	ErrRegexUnknownArgument = ErrorCode(31024) // Location31024
+
+	// ErrDateFromPartsOutOfRange indicates that $dateFromParts operator part is out of range.
+	ErrDateFromPartsOutOfRange = ErrorCode(31034) // Location31034
+
 	// ErrStageUnsetNoPath indicates that $unwind aggregation stage is empty.
 	ErrStageUnsetNoPath = ErrorCode(31119) // Location31119
 
@@ -249,6 +498,190 @@ const (
 	// ErrExclusionPositionalProjection indicates that exclusion cannot use positional projection.
 	ErrExclusionPositionalProjection = ErrorCode(31395) // Location31395
 
+	// ErrUnionWithNotAllowed indicates that the stage is not allowed in $unionWith sub-pipeline.
+	ErrUnionWithNotAllowed = ErrorCode(31441) // Location31441
+
+	// ErrReverseArrayNotArray indicates that $reverseArray argument is not an array.
+	ErrReverseArrayNotArray = ErrorCode(34435) // Location34435
+
+	// ErrRangeStartNotNumber indicates that $range starting value is not a number.
+	ErrRangeStartNotNumber = ErrorCode(34443) // Location34443
+
+	// ErrRangeStartNotInt32 indicates that $range starting value could not be represented as a 32-bit integer.
+	ErrRangeStartNotInt32 = ErrorCode(34444) // Location34444
+
+	// ErrRangeEndNotNumber indicates that $range ending value is not a number.
+	ErrRangeEndNotNumber = ErrorCode(34445) // Location34445
+
+	// ErrRangeEndNotInt32 indicates that $range ending value could not be represented as a 32-bit integer.
+	ErrRangeEndNotInt32 = ErrorCode(34446) // Location34446
+
+	// ErrRangeStepNotNumber indicates that $range step value is not a number.
+	ErrRangeStepNotNumber = ErrorCode(34447) // Location34447
+
+	// ErrRangeStepNotInt32 indicates that $range step value could not be represented as a 32-bit integer.
+	ErrRangeStepNotInt32 = ErrorCode(34448) // Location34448
+
+	// ErrRangeStepZero indicates that $range step value is zero.
+	ErrRangeStepZero = ErrorCode(34449) // Location34449
+
+	// ErrSubstrCPStartNonNumeric indicates that $substrCP operator starting index is not a number.
+	ErrSubstrCPStartNonNumeric = ErrorCode(34450) // Location34450
+
+	// ErrSubstrCPStartNotIntegral indicates that $substrCP operator starting index is not a 32-bit integral value.
+	ErrSubstrCPStartNotIntegral = ErrorCode(34451) // Location34451
+
+	// ErrSubstrCPLengthNonNumeric indicates that $substrCP operator length is not a number.
+	ErrSubstrCPLengthNonNumeric = ErrorCode(34452) // Location34452
+
+	// ErrSubstrCPLengthNotIntegral indicates that $substrCP operator length is not a 32-bit integral value.
+	ErrSubstrCPLengthNotIntegral = ErrorCode(34453) // Location34453
+
+	// ErrSubstrCPLengthNegative indicates that $substrCP operator length is negative.
+	ErrSubstrCPLengthNegative = ErrorCode(34454) // Location34454
+
+	// ErrSubstrCPStartNegative indicates that $substrCP operator starting index is negative.
+	ErrSubstrCPStartNegative = ErrorCode(34455) // Location34455
+
+	// ErrZipNotObject indicates that $zip argument is not an object.
+	ErrZipNotObject = ErrorCode(34460) // Location34460
+
+	// ErrZipInputsNotArray indicates that $zip inputs is not an array.
+	ErrZipInputsNotArray = ErrorCode(34461) // Location34461
+
+	// ErrZipDefaultsNotArray indicates that $zip defaults is not an array.
+	ErrZipDefaultsNotArray = ErrorCode(34462) // Location34462
+
+	// ErrZipUseLongestLengthNotBool indicates that $zip useLongestLength is not a boolean.
+	ErrZipUseLongestLengthNotBool = ErrorCode(34463) // Location34463
+
+	// ErrZipUnknownField indicates that $zip argument contains an unknown field.
+	ErrZipUnknownField = ErrorCode(34464) // Location34464
+
+	// ErrZipMissingInputs indicates that $zip inputs is not specified.
+	ErrZipMissingInputs = ErrorCode(34465) // Location34465
+
+	// ErrZipDefaultsWithoutUseLongestLength indicates that $zip defaults is specified without useLongestLength.
+	ErrZipDefaultsWithoutUseLongestLength = ErrorCode(34466) // Location34466
+
+	// ErrZipDefaultsLen indicates that $zip inputs and defaults have different lengths.
+	ErrZipDefaultsLen = ErrorCode(34467) // Location34467
+
+	// ErrZipInputNotArray indicates that $zip input is not an array.
+	ErrZipInputNotArray = ErrorCode(34468) // Location34468
+
+	// ErrStrLenCPNonString indicates that $strLenCP operator argument is not a string.
+	ErrStrLenCPNonString = ErrorCode(34471) // Location34471
+
+	// ErrStrLenBytesNonString indicates that $strLenBytes operator argument is not a string.
+	ErrStrLenBytesNonString = ErrorCode(34473) // Location34473
+
+	// ErrSwitchNotObject indicates that $switch operator argument is not an object.
+	ErrSwitchNotObject = ErrorCode(40060) // Location40060
+
+	// ErrSwitchBranchesNotArray indicates that $switch operator branches is not an array.
+	ErrSwitchBranchesNotArray = ErrorCode(40061) // Location40061
+
+	// ErrSwitchBranchNotObject indicates that $switch operator branch is not an object.
+	ErrSwitchBranchNotObject = ErrorCode(40062) // Location40062
+
+	// ErrSwitchUnknownBranchArgument indicates that $switch operator branch has unknown argument.
+	ErrSwitchUnknownBranchArgument = ErrorCode(40063) // Location40063
+
+	// ErrSwitchMissingCase indicates that $switch operator branch is missing 'case' expression.
+	ErrSwitchMissingCase = ErrorCode(40064) // Location40064
+
+	// ErrSwitchMissingThen indicates that $switch operator branch is missing 'then' expression.
+	ErrSwitchMissingThen = ErrorCode(40065) // Location40065
+
+	// ErrSwitchNoMatchingBranch indicates that no $switch operator branch matched and there is no default.
+	ErrSwitchNoMatchingBranch = ErrorCode(40066) // Location40066
+
+	// ErrSwitchUnknownArgument indicates that $switch operator has unknown argument.
+	ErrSwitchUnknownArgument = ErrorCode(40067) // Location40067
+
+	// ErrSwitchNoBranches indicates that $switch operator has no branches.
+	ErrSwitchNoBranches = ErrorCode(40068) // Location40068
+
+	// ErrReduceNotObject indicates that $reduce argument is not an object.
+	ErrReduceNotObject = ErrorCode(40075) // Location40075
+
+	// ErrReduceUnknownField indicates that $reduce argument contains an unknown field.
+	ErrReduceUnknownField = ErrorCode(40076) // Location40076
+
+	// ErrReduceMissingInput indicates that $reduce input is not specified.
+	ErrReduceMissingInput = ErrorCode(40077) // Location40077
+
+	// ErrReduceMissingInitialValue indicates that $reduce initialValue is not specified.
+	ErrReduceMissingInitialValue = ErrorCode(40078) // Location40078
+
+	// ErrReduceMissingIn indicates that $reduce in is not specified.
+	ErrReduceMissingIn = ErrorCode(40079) // Location40079
+
+	// ErrReduceInputNotArray indicates that $reduce input is not an array.
+	ErrReduceInputNotArray = ErrorCode(40080) // Location40080
+
+	// ErrInNotArray indicates that the second argument of $in is not an array.
+	ErrInNotArray = ErrorCode(40081) // Location40081
+
+	// ErrSplitNonString indicates that $split operator string argument is not a string.
+	ErrSplitNonString = ErrorCode(40085) // Location40085
+
+	// ErrSplitDelimiterNonString indicates that $split operator delimiter is not a string.
+	ErrSplitDelimiterNonString = ErrorCode(40086) // Location40086
+
+	// ErrSplitEmptyDelimiter indicates that $split operator delimiter is empty.
+	ErrSplitEmptyDelimiter = ErrorCode(40087) // Location40087
+
+	// ErrIndexOfArrayNotArray indicates that the first argument of $indexOfArray is not an array.
+	ErrIndexOfArrayNotArray = ErrorCode(40090) // Location40090
+
+	// ErrIndexOfBytesNonString indicates that $indexOfBytes operator string argument is not a string.
+	ErrIndexOfBytesNonString = ErrorCode(40091) // Location40091
+
+	// ErrIndexOfBytesSubstringNonString indicates that $indexOfBytes operator substring is not a string.
+	ErrIndexOfBytesSubstringNonString = ErrorCode(40092) // Location40092
+
+	// ErrIndexOfCPNonString indicates that $indexOfCP operator string argument is not a string.
+	ErrIndexOfCPNonString = ErrorCode(40093) // Location40093
+
+	// ErrIndexOfCPSubstringNonString indicates that $indexOfCP operator substring is not a string.
+	ErrIndexOfCPSubstringNonString = ErrorCode(40094) // Location40094
+
+	// ErrIndexOfIndexNotIntegral indicates that $indexOfBytes, $indexOfCP or $indexOfArray operator index
+	// is not an integral value.
+	ErrIndexOfIndexNotIntegral = ErrorCode(40096) // Location40096
+
+	// ErrIndexOfIndexNegative indicates that $indexOfBytes, $indexOfCP or $indexOfArray operator index is negative.
+	ErrIndexOfIndexNegative = ErrorCode(40097) // Location40097
+
+	// ErrGraphLookupMaxDepthNotNumber indicates that maxDepth argument of $graphLookup stage is not a number.
+	ErrGraphLookupMaxDepthNotNumber = ErrorCode(40100) // Location40100
+
+	// ErrGraphLookupMaxDepthNegative indicates that maxDepth argument of $graphLookup stage is negative.
+	ErrGraphLookupMaxDepthNegative = ErrorCode(40101) // Location40101
+
+	// ErrGraphLookupMaxDepthNotInteger indicates that maxDepth argument of $graphLookup stage is not an integer.
+	ErrGraphLookupMaxDepthNotInteger = ErrorCode(40102) // Location40102
+
+	// ErrGraphLookupNotString indicates that string argument of $graphLookup stage has a different type.
+	ErrGraphLookupNotString = ErrorCode(40103) // Location40103
+
+	// ErrGraphLookupUnknownArgument indicates that $graphLookup stage specification contains an unknown field.
+	ErrGraphLookupUnknownArgument = ErrorCode(40104) // Location40104
+
+	// ErrGraphLookupMissingField indicates that $graphLookup stage specification does not contain a required field.
+	ErrGraphLookupMissingField = ErrorCode(40105) // Location40105
+
+	// ErrSortByCountInvalidObject indicates that $sortByCount object is not an expression.
+	ErrSortByCountInvalidObject = ErrorCode(40147) // Location40147
+
+	// ErrSortByCountInvalidPath indicates that $sortByCount string is not a $-prefixed path.
+	ErrSortByCountInvalidPath = ErrorCode(40148) // Location40148
+
+	// ErrSortByCountInvalidType indicates that $sortByCount value is neither a string nor an object.
+	ErrSortByCountInvalidType = ErrorCode(40149) // Location40149
+
 	// ErrStageCountNonString indicates that $count aggregation stage expected string.
 	ErrStageCountNonString = ErrorCode(40156) // Location40156
 
@@ -261,10 +694,61 @@ const (
 	// ErrStageCountBadValue indicates that $count stage contains invalid value.
 	ErrStageCountBadValue = ErrorCode(40160) // Location40160
 
+	// ErrStageFacetInvalidSpec indicates that $facet specification is not a non-empty object.
+	ErrStageFacetInvalidSpec = ErrorCode(40169) // Location40169
+
+	// ErrStageFacetArgNotArray indicates that $facet sub-pipeline is not an array.
+	ErrStageFacetArgNotArray = ErrorCode(40170) // Location40170
+
+	// ErrStageFacetInvalidStage indicates that $facet sub-pipeline contains non-object.
+	ErrStageFacetInvalidStage = ErrorCode(40171) // Location40171
+
 	// ErrAddFieldsExpressionWrongAmountOfArgs indicates that $addFields stage expression contain invalid
 	// amount of arguments.
 	ErrAddFieldsExpressionWrongAmountOfArgs = ErrorCode(40181) // Location40181
 
+	// ErrGraphLookupRestrictNotObject indicates that restrictSearchWithMatch argument of $graphLookup stage is not an object.
+	ErrGraphLookupRestrictNotObject = ErrorCode(40185) // Location40185
+
+	// ErrBucketBoundariesNotConstant indicates that $bucket boundaries contain a non-constant value.
+	ErrBucketBoundariesNotConstant = ErrorCode(40191) // Location40191
+
+	// ErrBucketBoundariesTooFew indicates that $bucket boundaries have less than two values.
+	ErrBucketBoundariesTooFew = ErrorCode(40192) // Location40192
+
+	// ErrBucketBoundariesMixedTypes indicates that $bucket boundaries have values of different types.
+	ErrBucketBoundariesMixedTypes = ErrorCode(40193) // Location40193
+
+	// ErrBucketBoundariesNotSorted indicates that $bucket boundaries are not sorted in ascending order.
+	ErrBucketBoundariesNotSorted = ErrorCode(40194) // Location40194
+
+	// ErrBucketDefaultNotConstant indicates that $bucket default is not a constant value.
+	ErrBucketDefaultNotConstant = ErrorCode(40195) // Location40195
+
+	// ErrBucketOutputNotObject indicates that $bucket output is not an object.
+	ErrBucketOutputNotObject = ErrorCode(40196) // Location40196
+
+	// ErrBucketUnknownOption indicates that $bucket specification contains unknown field.
+	ErrBucketUnknownOption = ErrorCode(40197) // Location40197
+
+	// ErrBucketInvalidGroupBy indicates that $bucket groupBy is not a path or an expression.
+	ErrBucketInvalidGroupBy = ErrorCode(40198) // Location40198
+
+	// ErrBucketDefaultInRange indicates that $bucket default is within the boundaries.
+	ErrBucketDefaultInRange = ErrorCode(40199) // Location40199
+
+	// ErrBucketBoundariesNotArray indicates that $bucket boundaries are not an array.
+	ErrBucketBoundariesNotArray = ErrorCode(40200) // Location40200
+
+	// ErrBucketNotObject indicates that $bucket specification is not an object.
+	ErrBucketNotObject = ErrorCode(40201) // Location40201
+
+	// ErrBucketMissingArgs indicates that $bucket groupBy or boundaries are missing.
+	ErrBucketMissingArgs = ErrorCode(40202) // Location40202
+
+	// ErrReplaceRootNotDocument indicates that $replaceRoot or $replaceWith expression is not evaluated to a document.
+	ErrReplaceRootNotDocument = ErrorCode(40228) // Location40228
+
 	// ErrStageGroupUnaryOperator indicates that $sum is a unary operator.
 	ErrStageGroupUnaryOperator = ErrorCode(40237) // Location40237
 
@@ -274,6 +758,45 @@ const (
 	// ErrStageGroupInvalidAccumulator indicates invalid accumulator field.
 	ErrStageGroupInvalidAccumulator = ErrorCode(40234) // Location40234
 
+	// ErrBucketAutoInvalidGroupBy indicates that $bucketAuto groupBy is not a path or an expression.
+	ErrBucketAutoInvalidGroupBy = ErrorCode(40239) // Location40239
+
+	// ErrBucketAutoNotObject indicates that $bucketAuto specification is not an object.
+	ErrBucketAutoNotObject = ErrorCode(40240) // Location40240
+
+	// ErrBucketAutoBucketsNotNumber indicates that $bucketAuto buckets is not a number.
+	ErrBucketAutoBucketsNotNumber = ErrorCode(40241) // Location40241
+
+	// ErrBucketAutoBucketsNotInt32 indicates that $bucketAuto buckets is not a 32-bit integer.
+	ErrBucketAutoBucketsNotInt32 = ErrorCode(40242) // Location40242
+
+	// ErrBucketAutoBucketsNotPositive indicates that $bucketAuto buckets is not positive.
+	ErrBucketAutoBucketsNotPositive = ErrorCode(40243) // Location40243
+
+	// ErrBucketAutoOutputNotObject indicates that $bucketAuto output is not an object.
+	ErrBucketAutoOutputNotObject = ErrorCode(40244) // Location40244
+
+	// ErrBucketAutoUnknownOption indicates that $bucketAuto specification contains unknown field.
+	ErrBucketAutoUnknownOption = ErrorCode(40245) // Location40245
+
+	// ErrBucketAutoMissingArgs indicates that $bucketAuto groupBy or buckets are missing.
+	ErrBucketAutoMissingArgs = ErrorCode(40246) // Location40246
+
+	// ErrBucketAutoUnknownGranularity indicates that $bucketAuto granularity is unknown.
+	ErrBucketAutoUnknownGranularity = ErrorCode(40257) // Location40257
+
+	// ErrBucketAutoGranularityNotNumber indicates that $bucketAuto granularity is used with non-numeric value.
+	ErrBucketAutoGranularityNotNumber = ErrorCode(40258) // Location40258
+
+	// ErrBucketAutoGranularityNaN indicates that $bucketAuto granularity is used with NaN value.
+	ErrBucketAutoGranularityNaN = ErrorCode(40259) // Location40259
+
+	// ErrBucketAutoGranularityNegative indicates that $bucketAuto granularity is used with negative value.
+	ErrBucketAutoGranularityNegative = ErrorCode(40260) // Location40260
+
+	// ErrBucketAutoGranularityNotString indicates that $bucketAuto granularity is not a string.
+	ErrBucketAutoGranularityNotString = ErrorCode(40261) // Location40261
+
 	// ErrStageInvalid indicates invalid aggregation pipeline stage.
 	ErrStageInvalid = ErrorCode(40323) // Location40323
 
@@ -283,24 +806,135 @@ const (
 	// ErrInvalidFieldPath indicates that the field path is not valid.
 	ErrInvalidFieldPath = ErrorCode(40353) // Location40353
 
+	// ErrArrayToObjectNotArray indicates that $arrayToObject argument is not an array.
+	ErrArrayToObjectNotArray = ErrorCode(40386) // Location40386
+
+	// ErrObjectToArrayNotObject indicates that $objectToArray argument is not a document.
+	ErrObjectToArrayNotObject = ErrorCode(40390) // Location40390
+
+	// ErrArrayToObjectWrongKeysNumber indicates that $arrayToObject document does not have exactly two fields.
+	ErrArrayToObjectWrongKeysNumber = ErrorCode(40392) // Location40392
+
+	// ErrArrayToObjectMissingKeys indicates that $arrayToObject document does not have k or v fields.
+	ErrArrayToObjectMissingKeys = ErrorCode(40393) // Location40393
+
+	// ErrArrayToObjectKeyNotString indicates that $arrayToObject document field k is not a string.
+	ErrArrayToObjectKeyNotString = ErrorCode(40394) // Location40394
+
+	// ErrArrayToObjectPairKeyNotString indicates that the first element of $arrayToObject pair is not a string.
+	ErrArrayToObjectPairKeyNotString = ErrorCode(40395) // Location40395
+
+	// ErrArrayToObjectInconsistent indicates that $arrayToObject elements are not all arrays or all documents.
+	ErrArrayToObjectInconsistent = ErrorCode(40396) // Location40396
+
+	// ErrArrayToObjectPairLen indicates that $arrayToObject pair does not have exactly two elements.
+	ErrArrayToObjectPairLen = ErrorCode(40397) // Location40397
+
+	// ErrArrayToObjectWrongType indicates that $arrayToObject element is neither array nor document.
+	ErrArrayToObjectWrongType = ErrorCode(40398) // Location40398
+
+	// ErrMergeObjectsNotObject indicates that $mergeObjects input is not an object.
+	ErrMergeObjectsNotObject = ErrorCode(40400) // Location40400
+
 	// ErrMissingField indicates that the required field in document is missing.
 	ErrMissingField = ErrorCode(40414) // Location40414
 
+	// ErrUnrecognizedTimezone indicates that the time zone identifier is not recognized.
+	ErrUnrecognizedTimezone = ErrorCode(40485) // Location40485
+
+	// ErrDateFromPartsMixed indicates that $dateFromParts operator mixes natural and ISO dates.
+	ErrDateFromPartsMixed = ErrorCode(40489) // Location40489
+
+	// ErrDateFromPartsNotIntegral indicates that $dateFromParts operator part is not an integer.
+	ErrDateFromPartsNotIntegral = ErrorCode(40515) // Location40515
+
+	// ErrDateFromPartsMissingYear indicates that $dateFromParts operator has neither year nor isoWeekYear.
+	ErrDateFromPartsMissingYear = ErrorCode(40516) // Location40516
+
+	// ErrDateFromPartsUnknownArgument indicates that $dateFromParts operator has an unknown argument.
+	ErrDateFromPartsUnknownArgument = ErrorCode(40518) // Location40518
+
+	// ErrDateFromPartsNotObject indicates that $dateFromParts operator argument is not a document.
+	ErrDateFromPartsNotObject = ErrorCode(40519) // Location40519
+
+	// ErrDateToPartsUnknownArgument indicates that $dateToParts operator has an unknown argument.
+	ErrDateToPartsUnknownArgument = ErrorCode(40520) // Location40520
+
+	// ErrDateToPartsISO8601NonBool indicates that $dateToParts operator iso8601 is not a boolean.
+	ErrDateToPartsISO8601NonBool = ErrorCode(40521) // Location40521
+
+	// ErrDateToPartsMissingDate indicates that $dateToParts operator date parameter is missing.
+	ErrDateToPartsMissingDate = ErrorCode(40522) // Location40522
+
+	// ErrDateFromPartsYearOutOfRange indicates that $dateFromParts operator year is out of range.
+	ErrDateFromPartsYearOutOfRange = ErrorCode(40523) // Location40523
+
+	// ErrDateToPartsNotObject indicates that $dateToParts operator argument is not a document.
+	ErrDateToPartsNotObject = ErrorCode(40524) // Location40524
+
+	// ErrTimezoneNonString indicates that date operator timezone is not a string.
+	ErrTimezoneNonString = ErrorCode(40533) // Location40533
+
+	// ErrDateUnknownArgument indicates that date operator has an unknown argument.
+	ErrDateUnknownArgument = ErrorCode(40535) // Location40535
+
+	// ErrDateMissingDate indicates that date operator date argument is missing.
+	ErrDateMissingDate = ErrorCode(40539) // Location40539
+
+	// ErrDateFromStringNotObject indicates that $dateFromString operator argument is not a document.
+	ErrDateFromStringNotObject = ErrorCode(40540) // Location40540
+
+	// ErrDateFromStringUnknownArgument indicates that $dateFromString operator has an unknown argument.
+	ErrDateFromStringUnknownArgument = ErrorCode(40541) // Location40541
+
+	// ErrDateFromStringMissingDateString indicates that $dateFromString operator dateString parameter is missing.
+	ErrDateFromStringMissingDateString = ErrorCode(40542) // Location40542
+
+	// ErrChangeStreamNotSupported indicates that change streams are not available.
+	ErrChangeStreamNotSupported = ErrorCode(40573) // Location40573
+
 	// ErrFailedToParseInput indicates invalid input (absent or malformed fields).
 	ErrFailedToParseInput = ErrorCode(40415) // Location40415
 
+	// ErrStageNotLast indicates that stage can only be the final stage in the pipeline.
+	ErrStageNotLast = ErrorCode(40601) // Location40601
+
+	// ErrStageFacetNotAllowed indicates that stage is not allowed in $facet sub-pipeline.
+	ErrStageFacetNotAllowed = ErrorCode(40600) // Location40600
+
 	// ErrCollStatsIsNotFirstStage indicates that $collStats must be the first stage in the pipeline.
 	ErrCollStatsIsNotFirstStage = ErrorCode(40602) // Location40602
 
 	// ErrOpQueryInvalidField indicates that the field is not allowed for op query.
 	ErrOpQueryInvalidField = ErrorCode(40621) // Location40621
 
+	// ErrDateFromStringFormatNonString indicates that $dateFromString operator format is not a string.
+	ErrDateFromStringFormatNonString = ErrorCode(40684) // Location40684
+
 	// ErrSetEmptyPassword indicates that a password must not be empty.
 	ErrSetEmptyPassword = ErrorCode(50687) // Location50687
 
 	// ErrStringProhibited indicates that a password contains prohibited runes.
 	ErrStringProhibited = ErrorCode(50692) // Location50692
 
+	// ErrTrimUnknownArgument indicates that $trim, $ltrim or $rtrim operator has unknown argument.
+	ErrTrimUnknownArgument = ErrorCode(50694) // Location50694
+
+	// ErrTrimMissingInput indicates that $trim, $ltrim or $rtrim operator is missing 'input' field.
+	ErrTrimMissingInput = ErrorCode(50695) // Location50695
+
+	// ErrTrimNotObject indicates that $trim, $ltrim or $rtrim operator argument is not an object.
+	ErrTrimNotObject = ErrorCode(50696) // Location50696
+
+	// ErrTrimInputNonString indicates that $trim, $ltrim or $rtrim operator input is not a string.
+	ErrTrimInputNonString = ErrorCode(50699) // Location50699
+
+	// ErrTrimCharsNonString indicates that $trim, $ltrim or $rtrim operator chars is not a string.
+	ErrTrimCharsNonString = ErrorCode(50700) // Location50700
+
+	// ErrSubstrBytesStartNegative indicates that $substrBytes operator starting index is negative.
+	ErrSubstrBytesStartNegative = ErrorCode(50752) // Location50752
+
 	// ErrFreeMonitoringDisabled indicates that free monitoring is disabled
 	// by command-line or config file.
 	ErrFreeMonitoringDisabled = ErrorCode(50840) // Location50840
@@ -311,15 +945,54 @@ const (
 	// ErrValueNegative indicates that value must not be negative.
 	ErrValueNegative = ErrorCode(51024) // Location51024
 
+	// ErrStageLookupNotAllowed indicates that stage is not allowed in $lookup sub-pipeline.
+	ErrStageLookupNotAllowed = ErrorCode(51047) // Location51047
+
 	// ErrRegexOptions indicates regex options error.
 	ErrRegexOptions = ErrorCode(51075) // Location51075
 
 	// ErrRegexMissingParen indicates missing parentheses in regex expression.
 	ErrRegexMissingParen = ErrorCode(51091) // Location51091
 
+	// ErrRegexNotObject indicates that regex operator argument is not an object.
+	ErrRegexNotObject = ErrorCode(51103) // Location51103
+
+	// ErrRegexInputNonString indicates that regex operator input is not a string.
+	ErrRegexInputNonString = ErrorCode(51104) // Location51104
+
+	// ErrRegexInvalidRegexType indicates that regex operator regex is not a string or regex.
+	ErrRegexInvalidRegexType = ErrorCode(51105) // Location51105
+
+	// ErrRegexOptionsNonString indicates that regex operator options is not a string.
+	ErrRegexOptionsNonString = ErrorCode(51106) // Location51106
+
+	// ErrRegexOptionsConflict indicates that regex operator options are specified in both 'regex' and 'options' fields.
+	ErrRegexOptionsConflict = ErrorCode(51107) // Location51107
+
 	// ErrBadRegexOption indicates bad regex option value passed.
 	ErrBadRegexOption = ErrorCode(51108) // Location51108
 
+	// ErrRoundNonNumeric indicates that $round or $trunc operator argument is not a number.
+	ErrRoundNonNumeric = ErrorCode(51081) // Location51081
+
+	// ErrRoundPrecisionNotIntegral indicates that $round or $trunc operator precision is not an integral value.
+	ErrRoundPrecisionNotIntegral = ErrorCode(51082) // Location51082
+
+	// ErrRoundPrecisionOutOfRange indicates that $round or $trunc operator precision is out of range.
+	ErrRoundPrecisionOutOfRange = ErrorCode(51083) // Location51083
+
+	// ErrRegexInvalid indicates that regex operator regular expression is invalid.
+	ErrRegexInvalid = ErrorCode(51111) // Location51111
+
+	// ErrStageMergeInvalidOn indicates that $merge 'on' field value is missing or invalid.
+	ErrStageMergeInvalidOn = ErrorCode(51132) // Location51132
+
+	// ErrStageMergeInvalidArg indicates that $merge argument is not a string or an object.
+	ErrStageMergeInvalidArg = ErrorCode(51182) // Location51182
+
+	// ErrStageMergeNoUniqueIndex indicates that $merge 'on' fields are not covered by a unique index.
+	ErrStageMergeNoUniqueIndex = ErrorCode(51183) // Location51183
+
 	// ErrBadPositionalProjection indicates that positional operator could not find a matching element in the array.
 	ErrBadPositionalProjection = ErrorCode(51246) // Location51246
 
@@ -332,21 +1005,183 @@ const (
 	// ErrEmptyProject indicates that projection specification must have at least one field.
 	ErrEmptyProject = ErrorCode(51272) // Location51272
 
+	// ErrReplaceReplacementNonString indicates that $replaceOne or $replaceAll operator replacement is not a string.
+	ErrReplaceReplacementNonString = ErrorCode(51744) // Location51744
+
+	// ErrReplaceFindNonString indicates that $replaceOne or $replaceAll operator find is not a string.
+	ErrReplaceFindNonString = ErrorCode(51745) // Location51745
+
+	// ErrReplaceInputNonString indicates that $replaceOne or $replaceAll operator input is not a string.
+	ErrReplaceInputNonString = ErrorCode(51746) // Location51746
+
+	// ErrReplaceMissingReplacement indicates that $replaceOne or $replaceAll operator is missing 'replacement' field.
+	ErrReplaceMissingReplacement = ErrorCode(51747) // Location51747
+
+	// ErrReplaceMissingFind indicates that $replaceOne or $replaceAll operator is missing 'find' field.
+	ErrReplaceMissingFind = ErrorCode(51748) // Location51748
+
+	// ErrReplaceMissingInput indicates that $replaceOne or $replaceAll operator is missing 'input' field.
+	ErrReplaceMissingInput = ErrorCode(51749) // Location51749
+
+	// ErrReplaceUnknownArgument indicates that $replaceOne or $replaceAll operator has unknown argument.
+	ErrReplaceUnknownArgument = ErrorCode(51750) // Location51750
+
+	// ErrReplaceNotObject indicates that $replaceOne or $replaceAll operator argument is not an object.
+	ErrReplaceNotObject = ErrorCode(51751) // Location51751
+
+	// ErrFilterLimitNotInt32 indicates that $filter limit could not be represented as a 32-bit integer.
+	ErrFilterLimitNotInt32 = ErrorCode(327391) // Location327391
+
+	// ErrFilterLimitNotPositive indicates that $filter limit is not positive.
+	ErrFilterLimitNotPositive = ErrorCode(327392) // Location327392
+
+	// ErrIfNullArgs indicates that $ifNull operator has less than two arguments.
+	ErrIfNullArgs = ErrorCode(1257300) // Location1257300
+
+	// ErrSortArrayNotObject indicates that $sortArray argument is not an object.
+	ErrSortArrayNotObject = ErrorCode(2942500) // Location2942500
+
+	// ErrSortArrayUnknownField indicates that $sortArray argument contains an unknown field.
+	ErrSortArrayUnknownField = ErrorCode(2942501) // Location2942501
+
+	// ErrSortArrayMissingInput indicates that $sortArray input is not specified.
+	ErrSortArrayMissingInput = ErrorCode(2942502) // Location2942502
+
+	// ErrSortArrayMissingSortBy indicates that $sortArray sortBy is not specified.
+	ErrSortArrayMissingSortBy = ErrorCode(2942503) // Location2942503
+
+	// ErrSortArrayInputNotArray indicates that $sortArray input is not an array.
+	ErrSortArrayInputNotArray = ErrorCode(2942504) // Location2942504
+
 	// ErrDuplicateField indicates duplicate field is specified.
 	ErrDuplicateField = ErrorCode(4822819) // Location4822819
 
+	// ErrArrayToObjectNullByte indicates that $arrayToObject key contains a null byte.
+	ErrArrayToObjectNullByte = ErrorCode(4940400) // Location4940400
+
 	// ErrStageSkipBadValue indicates that $skip stage contains invalid value.
 	ErrStageSkipBadValue = ErrorCode(5107200) // Location5107200
 
 	// ErrStageLimitInvalidArg indicates invalid argument for the aggregation $limit stage.
 	ErrStageLimitInvalidArg = ErrorCode(5107201) // Location5107201
 
+	// ErrDateDiffNotObject indicates that $dateDiff operator argument is not a document.
+	ErrDateDiffNotObject = ErrorCode(5166301) // Location5166301
+
+	// ErrDateDiffUnknownArgument indicates that $dateDiff operator has an unknown argument.
+	ErrDateDiffUnknownArgument = ErrorCode(5166302) // Location5166302
+
+	// ErrDateDiffMissingStartDate indicates that $dateDiff operator startDate parameter is missing.
+	ErrDateDiffMissingStartDate = ErrorCode(5166303) // Location5166303
+
+	// ErrDateDiffMissingEndDate indicates that $dateDiff operator endDate parameter is missing.
+	ErrDateDiffMissingEndDate = ErrorCode(5166304) // Location5166304
+
+	// ErrDateDiffMissingUnit indicates that $dateDiff operator unit parameter is missing.
+	ErrDateDiffMissingUnit = ErrorCode(5166305) // Location5166305
+
+	// ErrDateDiffInvalidDate indicates that $dateDiff operator startDate or endDate is not a date.
+	ErrDateDiffInvalidDate = ErrorCode(5166307) // Location5166307
+
+	// ErrDateAddNotObject indicates that $dateAdd or $dateSubtract operator argument is not a document.
+	ErrDateAddNotObject = ErrorCode(5166400) // Location5166400
+
+	// ErrDateAddUnknownArgument indicates that $dateAdd or $dateSubtract operator has an unknown argument.
+	ErrDateAddUnknownArgument = ErrorCode(5166401) // Location5166401
+
+	// ErrDateAddMissingArgument indicates that $dateAdd or $dateSubtract operator required argument is missing.
+	ErrDateAddMissingArgument = ErrorCode(5166402) // Location5166402
+
+	// ErrDateAddInvalidStartDate indicates that $dateAdd or $dateSubtract operator startDate is not a date.
+	ErrDateAddInvalidStartDate = ErrorCode(5166403) // Location5166403
+
+	// ErrDateAddUnitNonString indicates that $dateAdd or $dateSubtract operator unit is not a string.
+	ErrDateAddUnitNonString = ErrorCode(5166404) // Location5166404
+
+	// ErrDateAddAmountNotIntegral indicates that $dateAdd or $dateSubtract operator amount is not an integer.
+	ErrDateAddAmountNotIntegral = ErrorCode(5166405) // Location5166405
+
+	// ErrDateAddOverflow indicates that $dateAdd or $dateSubtract operator result overflows.
+	ErrDateAddOverflow = ErrorCode(5166406) // Location5166406
+
+	// ErrDateTruncNotObject indicates that $dateTrunc operator argument is not a document.
+	ErrDateTruncNotObject = ErrorCode(5439007) // Location5439007
+
+	// ErrDateTruncUnknownArgument indicates that $dateTrunc operator has an unknown argument.
+	ErrDateTruncUnknownArgument = ErrorCode(5439008) // Location5439008
+
+	// ErrDateTruncMissingDate indicates that $dateTrunc operator date parameter is missing.
+	ErrDateTruncMissingDate = ErrorCode(5439009) // Location5439009
+
+	// ErrDateTruncMissingUnit indicates that $dateTrunc operator unit parameter is missing.
+	ErrDateTruncMissingUnit = ErrorCode(5439010) // Location5439010
+
+	// ErrDateTruncInvalidDate indicates that $dateTrunc operator date is not a date.
+	ErrDateTruncInvalidDate = ErrorCode(5439012) // Location5439012
+
+	// ErrTimeUnitNonString indicates that $dateTrunc or $dateDiff operator unit is not a string.
+	ErrTimeUnitNonString = ErrorCode(5439013) // Location5439013
+
+	// ErrStartOfWeekNonString indicates that startOfWeek parameter is not a string.
+	ErrStartOfWeekNonString = ErrorCode(5439015) // Location5439015
+
+	// ErrStartOfWeekInvalid indicates that startOfWeek parameter is not a day of the week.
+	ErrStartOfWeekInvalid = ErrorCode(5439016) // Location5439016
+
+	// ErrDateTruncBinSizeNotIntegral indicates that $dateTrunc operator binSize is not a 64-bit integer.
+	ErrDateTruncBinSizeNotIntegral = ErrorCode(5439017) // Location5439017
+
+	// ErrDateTruncBinSizeNotPositive indicates that $dateTrunc operator binSize is not positive.
+	ErrDateTruncBinSizeNotPositive = ErrorCode(5439018) // Location5439018
+
 	// ErrStageCollStatsInvalidArg indicates invalid argument for the aggregation $collStats stage.
 	ErrStageCollStatsInvalidArg = ErrorCode(5447000) // Location5447000
 
 	// ErrOpQueryCollectionSuffixMissing indicates that op query collection does not contain .$cmd suffix.
 	ErrOpQueryCollectionSuffixMissing = ErrorCode(5739101) // Location5739101
 
+	// ErrFirstLastNNotObject indicates that $firstN or $lastN specification is not an object.
+	ErrFirstLastNNotObject = ErrorCode(5787801) // Location5787801
+
+	// ErrMinMaxNNotObject indicates that $minN or $maxN specification is not an object.
+	ErrMinMaxNNotObject = ErrorCode(5787900) // Location5787900
+
+	// ErrAccumulatorNUnknownField indicates that $firstN, $lastN, $minN or $maxN specification contains unknown field.
+	ErrAccumulatorNUnknownField = ErrorCode(5787901) // Location5787901
+
+	// ErrAccumulatorNNonNumeric indicates that 'n' value of accumulator is not a number.
+	ErrAccumulatorNNonNumeric = ErrorCode(5787902) // Location5787902
+
+	// ErrAccumulatorNNonIntegral indicates that 'n' value of accumulator is not integral.
+	ErrAccumulatorNNonIntegral = ErrorCode(5787903) // Location5787903
+
+	// ErrAccumulatorNMissingN indicates that 'n' value of accumulator is missing.
+	ErrAccumulatorNMissingN = ErrorCode(5787906) // Location5787906
+
+	// ErrAccumulatorNMissingInput indicates that 'input' value of accumulator is missing.
+	ErrAccumulatorNMissingInput = ErrorCode(5787907) // Location5787907
+
+	// ErrAccumulatorNNonPositive indicates that 'n' value of accumulator is not positive.
+	ErrAccumulatorNNonPositive = ErrorCode(5787908) // Location5787908
+
+	// ErrTopBottomNotObject indicates that $top, $bottom, $topN or $bottomN specification is not an object.
+	ErrTopBottomNotObject = ErrorCode(5788001) // Location5788001
+
+	// ErrTopBottomUnknownField indicates that $top, $bottom, $topN or $bottomN specification contains unknown field.
+	ErrTopBottomUnknownField = ErrorCode(5788002) // Location5788002
+
+	// ErrTopBottomMissingN indicates that 'n' value of $topN or $bottomN is missing.
+	ErrTopBottomMissingN = ErrorCode(5788003) // Location5788003
+
+	// ErrTopBottomMissingOutput indicates that 'output' value of $top, $bottom, $topN or $bottomN is missing.
+	ErrTopBottomMissingOutput = ErrorCode(5788004) // Location5788004
+
+	// ErrTopBottomMissingSortBy indicates that 'sortBy' value of $top, $bottom, $topN or $bottomN is missing.
+	ErrTopBottomMissingSortBy = ErrorCode(5788005) // Location5788005
+
+	// ErrTopBottomSortByNotObject indicates that 'sortBy' value of $top, $bottom, $topN or $bottomN is not an object.
+	ErrTopBottomSortByNotObject = ErrorCode(5788604) // Location5788604
+
 	// ErrStageIndexedStringVectorDuplicate indicates that input to IndexedStringVector contained duplicate values.
 	ErrStageIndexedStringVectorDuplicate = ErrorCode(7582300) // Location7582300
 )
@@ -37,18 +37,32 @@ func _() {
 	_ = x[ErrIndexOptionsConflict-85]
 	_ = x[ErrIndexKeySpecsConflict-86]
 	_ = x[ErrOperationFailed-96]
+	_ = x[ErrConflictingOperationInProgress-117]
 	_ = x[ErrDocumentValidationFailure-121]
+	_ = x[ErrExceededMemoryLimit-146]
 	_ = x[ErrInvalidIndexSpecificationOption-197]
 	_ = x[ErrInvalidPipelineOperator-168]
 	_ = x[ErrClientMetadataCannotBeMutated-186]
+	_ = x[ErrInvalidUUID-207]
+	_ = x[ErrTransactionTooOld-225]
 	_ = x[ErrNotImplemented-238]
+	_ = x[ErrConversionFailure-241]
+	_ = x[ErrNoSuchTransaction-251]
+	_ = x[ErrTransactionCommitted-256]
+	_ = x[ErrInvalidResumeToken-260]
+	_ = x[ErrOperationNotSupportedInTransaction-263]
 	_ = x[ErrMechanismUnavailable-334]
 	_ = x[ErrUnsupportedOpQueryCommand-352]
+	_ = x[ErrMergeStageNoMatchingDocument-13113]
 	_ = x[ErrIndexesWrongType-10065]
+	_ = x[ErrBSONObjectTooLarge-10334]
 	_ = x[ErrDuplicateKeyInsert-11000]
 	_ = x[ErrSetBadExpression-40272]
+	_ = x[ErrStageOutInvalidArg-16990]
+	_ = x[ErrStageOutCappedCollection-17152]
 	_ = x[ErrStageGroupInvalidFields-15947]
 	_ = x[ErrStageGroupID-15948]
+	_ = x[ErrStageGroupUnknownAccumulator-15952]
 	_ = x[ErrStageGroupMissingID-15955]
 	_ = x[ErrStageLimitZero-15958]
 	_ = x[ErrMatchBadExpression-15959]
@@ -61,13 +75,82 @@ func _() {
 	_ = x[ErrStageUnwindWrongType-15981]
 	_ = x[ErrExpressionWrongLenOfFields-15983]
 	_ = x[ErrPathContainsEmptyElement-15998]
+	_ = x[ErrConvertToDate-16006]
+	_ = x[ErrConvertToLong-16004]
+	_ = x[ErrConvertToString-16007]
+	_ = x[ErrSubstrStartNonNumeric-16034]
+	_ = x[ErrSubstrLengthNonNumeric-16035]
 	_ = x[ErrOperatorWrongLenOfArgs-16020]
 	_ = x[ErrFieldPathInvalidName-16410]
+	_ = x[ErrDivideByZero-16608]
+	_ = x[ErrDivideNonNumeric-16609]
+	_ = x[ErrModByZero-16610]
+	_ = x[ErrModNonNumeric-16611]
+	_ = x[ErrAddMultipleDates-16612]
+	_ = x[ErrConcatNonString-16702]
 	_ = x[ErrGroupInvalidFieldPath-16872]
+	_ = x[ErrLetNotObject-16874]
+	_ = x[ErrLetUnknownField-16875]
+	_ = x[ErrLetMissingVars-16876]
+	_ = x[ErrLetMissingIn-16877]
+	_ = x[ErrMapNotObject-16878]
+	_ = x[ErrMapUnknownField-16879]
+	_ = x[ErrMapMissingInput-16880]
+	_ = x[ErrMapMissingIn-16882]
+	_ = x[ErrMapInputNotArray-16883]
 	_ = x[ErrBadNumberToReturn-16979]
+	_ = x[ErrRedactInvalidResult-17053]
+	_ = x[ErrCondMissingIf-17080]
+	_ = x[ErrCondMissingThen-17081]
+	_ = x[ErrCondMissingElse-17082]
+	_ = x[ErrCondUnknownParameter-17083]
+	_ = x[ErrSizeNotArray-17124]
 	_ = x[ErrGroupUndefinedVariable-17276]
+	_ = x[ErrDateToStringFormatNonString-18533]
+	_ = x[ErrDateToStringUnknownArgument-18534]
+	_ = x[ErrDateFormatUnmatchedPercent-18535]
+	_ = x[ErrDateFormatInvalidCharacter-18536]
+	_ = x[ErrDateToStringMissingDate-18628]
+	_ = x[ErrDateToStringNotObject-18629]
+	_ = x[ErrFilterNotObject-28646]
+	_ = x[ErrFilterUnknownField-28647]
+	_ = x[ErrFilterMissingInput-28648]
+	_ = x[ErrFilterMissingCond-28650]
+	_ = x[ErrFilterInputNotArray-28651]
+	_ = x[ErrSubstrBytesStartContinuation-28656]
+	_ = x[ErrSubstrBytesEndContinuation-28657]
+	_ = x[ErrConcatArraysNotArray-28664]
 	_ = x[ErrInvalidArg-28667]
+	_ = x[ErrAbsLongMin-28680]
+	_ = x[ErrArrayElemAtFirstArg-28689]
+	_ = x[ErrArrayElemAtSecondArg-28690]
+	_ = x[ErrArrayElemAtIndexNotInt32-28691]
+	_ = x[ErrSqrtNegative-28714]
 	_ = x[ErrSliceFirstArg-28724]
+	_ = x[ErrSliceSecondArgNotNumber-28725]
+	_ = x[ErrSliceSecondArgNotInt32-28726]
+	_ = x[ErrSliceThirdArgNotNumber-28727]
+	_ = x[ErrSliceThirdArgNotInt32-28728]
+	_ = x[ErrSliceThirdArgNotPositive-28729]
+	_ = x[ErrSampleBadSpecification-28745]
+	_ = x[ErrSampleSizeNotNumber-28746]
+	_ = x[ErrSampleSizeNegative-28747]
+	_ = x[ErrSampleUnknownOption-28748]
+	_ = x[ErrSampleMissingSize-28749]
+	_ = x[ErrLogNonNumeric-28756]
+	_ = x[ErrLogBaseNonNumeric-28757]
+	_ = x[ErrLogNonPositive-28758]
+	_ = x[ErrLogInvalidBase-28759]
+	_ = x[ErrLog10NonPositive-28761]
+	_ = x[ErrPowBaseNonNumeric-28762]
+	_ = x[ErrPowExponentNonNumeric-28763]
+	_ = x[ErrPowZeroNegativeExponent-28764]
+	_ = x[ErrOperatorNonNumeric-28765]
+	_ = x[ErrLnNonPositive-28766]
+	_ = x[ErrRegexMissingInput-31022]
+	_ = x[ErrRegexMissingRegex-31023]
+	_ = x[ErrRegexUnknownArgument-31024]
+	_ = x[ErrDateFromPartsOutOfRange-31034]
 	_ = x[ErrStageUnsetNoPath-31119]
 	_ = x[ErrStageUnsetArrElementInvalidType-31120]
 	_ = x[ErrStageUnsetInvalidType-31002]
@@ -81,42 +164,237 @@ func _() {
 	_ = x[ErrAggregateInvalidExpression-31325]
 	_ = x[ErrWrongPositionalOperatorLocation-31394]
 	_ = x[ErrExclusionPositionalProjection-31395]
+	_ = x[ErrUnionWithNotAllowed-31441]
+	_ = x[ErrReverseArrayNotArray-34435]
+	_ = x[ErrRangeStartNotNumber-34443]
+	_ = x[ErrRangeStartNotInt32-34444]
+	_ = x[ErrRangeEndNotNumber-34445]
+	_ = x[ErrRangeEndNotInt32-34446]
+	_ = x[ErrRangeStepNotNumber-34447]
+	_ = x[ErrRangeStepNotInt32-34448]
+	_ = x[ErrRangeStepZero-34449]
+	_ = x[ErrSubstrCPStartNonNumeric-34450]
+	_ = x[ErrSubstrCPStartNotIntegral-34451]
+	_ = x[ErrSubstrCPLengthNonNumeric-34452]
+	_ = x[ErrSubstrCPLengthNotIntegral-34453]
+	_ = x[ErrSubstrCPLengthNegative-34454]
+	_ = x[ErrSubstrCPStartNegative-34455]
+	_ = x[ErrZipNotObject-34460]
+	_ = x[ErrZipInputsNotArray-34461]
+	_ = x[ErrZipDefaultsNotArray-34462]
+	_ = x[ErrZipUseLongestLengthNotBool-34463]
+	_ = x[ErrZipUnknownField-34464]
+	_ = x[ErrZipMissingInputs-34465]
+	_ = x[ErrZipDefaultsWithoutUseLongestLength-34466]
+	_ = x[ErrZipDefaultsLen-34467]
+	_ = x[ErrZipInputNotArray-34468]
+	_ = x[ErrStrLenCPNonString-34471]
+	_ = x[ErrStrLenBytesNonString-34473]
+	_ = x[ErrSwitchNotObject-40060]
+	_ = x[ErrSwitchBranchesNotArray-40061]
+	_ = x[ErrSwitchBranchNotObject-40062]
+	_ = x[ErrSwitchUnknownBranchArgument-40063]
+	_ = x[ErrSwitchMissingCase-40064]
+	_ = x[ErrSwitchMissingThen-40065]
+	_ = x[ErrSwitchNoMatchingBranch-40066]
+	_ = x[ErrSwitchUnknownArgument-40067]
+	_ = x[ErrSwitchNoBranches-40068]
+	_ = x[ErrReduceNotObject-40075]
+	_ = x[ErrReduceUnknownField-40076]
+	_ = x[ErrReduceMissingInput-40077]
+	_ = x[ErrReduceMissingInitialValue-40078]
+	_ = x[ErrReduceMissingIn-40079]
+	_ = x[ErrReduceInputNotArray-40080]
+	_ = x[ErrInNotArray-40081]
+	_ = x[ErrSplitNonString-40085]
+	_ = x[ErrSplitDelimiterNonString-40086]
+	_ = x[ErrSplitEmptyDelimiter-40087]
+	_ = x[ErrIndexOfArrayNotArray-40090]
+	_ = x[ErrIndexOfBytesNonString-40091]
+	_ = x[ErrIndexOfBytesSubstringNonString-40092]
+	_ = x[ErrIndexOfCPNonString-40093]
+	_ = x[ErrIndexOfCPSubstringNonString-40094]
+	_ = x[ErrIndexOfIndexNotIntegral-40096]
+	_ = x[ErrIndexOfIndexNegative-40097]
+	_ = x[ErrGraphLookupMaxDepthNotNumber-40100]
+	_ = x[ErrGraphLookupMaxDepthNegative-40101]
+	_ = x[ErrGraphLookupMaxDepthNotInteger-40102]
+	_ = x[ErrGraphLookupNotString-40103]
+	_ = x[ErrGraphLookupUnknownArgument-40104]
+	_ = x[ErrGraphLookupMissingField-40105]
+	_ = x[ErrSortByCountInvalidObject-40147]
+	_ = x[ErrSortByCountInvalidPath-40148]
+	_ = x[ErrSortByCountInvalidType-40149]
 	_ = x[ErrStageCountNonString-40156]
 	_ = x[ErrStageCountNonEmptyString-40157]
 	_ = x[ErrStageCountBadPrefix-40158]
 	_ = x[ErrStageCountBadValue-40160]
+	_ = x[ErrStageFacetInvalidSpec-40169]
+	_ = x[ErrStageFacetArgNotArray-40170]
+	_ = x[ErrStageFacetInvalidStage-40171]
 	_ = x[ErrAddFieldsExpressionWrongAmountOfArgs-40181]
+	_ = x[ErrGraphLookupRestrictNotObject-40185]
+	_ = x[ErrBucketBoundariesNotConstant-40191]
+	_ = x[ErrBucketBoundariesTooFew-40192]
+	_ = x[ErrBucketBoundariesMixedTypes-40193]
+	_ = x[ErrBucketBoundariesNotSorted-40194]
+	_ = x[ErrBucketDefaultNotConstant-40195]
+	_ = x[ErrBucketOutputNotObject-40196]
+	_ = x[ErrBucketUnknownOption-40197]
+	_ = x[ErrBucketInvalidGroupBy-40198]
+	_ = x[ErrBucketDefaultInRange-40199]
+	_ = x[ErrBucketBoundariesNotArray-40200]
+	_ = x[ErrBucketNotObject-40201]
+	_ = x[ErrBucketMissingArgs-40202]
+	_ = x[ErrReplaceRootNotDocument-40228]
 	_ = x[ErrStageGroupUnaryOperator-40237]
 	_ = x[ErrStageGroupMultipleAccumulator-40238]
 	_ = x[ErrStageGroupInvalidAccumulator-40234]
+	_ = x[ErrBucketAutoInvalidGroupBy-40239]
+	_ = x[ErrBucketAutoNotObject-40240]
+	_ = x[ErrBucketAutoBucketsNotNumber-40241]
+	_ = x[ErrBucketAutoBucketsNotInt32-40242]
+	_ = x[ErrBucketAutoBucketsNotPositive-40243]
+	_ = x[ErrBucketAutoOutputNotObject-40244]
+	_ = x[ErrBucketAutoUnknownOption-40245]
+	_ = x[ErrBucketAutoMissingArgs-40246]
+	_ = x[ErrBucketAutoUnknownGranularity-40257]
+	_ = x[ErrBucketAutoGranularityNotNumber-40258]
+	_ = x[ErrBucketAutoGranularityNaN-40259]
+	_ = x[ErrBucketAutoGranularityNegative-40260]
+	_ = x[ErrBucketAutoGranularityNotString-40261]
 	_ = x[ErrStageInvalid-40323]
 	_ = x[ErrEmptyFieldPath-40352]
 	_ = x[ErrInvalidFieldPath-40353]
+	_ = x[ErrArrayToObjectNotArray-40386]
+	_ = x[ErrObjectToArrayNotObject-40390]
+	_ = x[ErrArrayToObjectWrongKeysNumber-40392]
+	_ = x[ErrArrayToObjectMissingKeys-40393]
+	_ = x[ErrArrayToObjectKeyNotString-40394]
+	_ = x[ErrArrayToObjectPairKeyNotString-40395]
+	_ = x[ErrArrayToObjectInconsistent-40396]
+	_ = x[ErrArrayToObjectPairLen-40397]
+	_ = x[ErrArrayToObjectWrongType-40398]
+	_ = x[ErrMergeObjectsNotObject-40400]
 	_ = x[ErrMissingField-40414]
+	_ = x[ErrUnrecognizedTimezone-40485]
+	_ = x[ErrDateFromPartsMixed-40489]
+	_ = x[ErrDateFromPartsNotIntegral-40515]
+	_ = x[ErrDateFromPartsMissingYear-40516]
+	_ = x[ErrDateFromPartsUnknownArgument-40518]
+	_ = x[ErrDateFromPartsNotObject-40519]
+	_ = x[ErrDateToPartsUnknownArgument-40520]
+	_ = x[ErrDateToPartsISO8601NonBool-40521]
+	_ = x[ErrDateToPartsMissingDate-40522]
+	_ = x[ErrDateFromPartsYearOutOfRange-40523]
+	_ = x[ErrDateToPartsNotObject-40524]
+	_ = x[ErrTimezoneNonString-40533]
+	_ = x[ErrDateUnknownArgument-40535]
+	_ = x[ErrDateMissingDate-40539]
+	_ = x[ErrDateFromStringNotObject-40540]
+	_ = x[ErrDateFromStringUnknownArgument-40541]
+	_ = x[ErrDateFromStringMissingDateString-40542]
+	_ = x[ErrChangeStreamNotSupported-40573]
 	_ = x[ErrFailedToParseInput-40415]
+	_ = x[ErrStageNotLast-40601]
+	_ = x[ErrStageFacetNotAllowed-40600]
 	_ = x[ErrCollStatsIsNotFirstStage-40602]
 	_ = x[ErrOpQueryInvalidField-40621]
+	_ = x[ErrDateFromStringFormatNonString-40684]
 	_ = x[ErrSetEmptyPassword-50687]
 	_ = x[ErrStringProhibited-50692]
+	_ = x[ErrTrimUnknownArgument-50694]
+	_ = x[ErrTrimMissingInput-50695]
+	_ = x[ErrTrimNotObject-50696]
+	_ = x[ErrTrimInputNonString-50699]
+	_ = x[ErrTrimCharsNonString-50700]
+	_ = x[ErrSubstrBytesStartNegative-50752]
 	_ = x[ErrFreeMonitoringDisabled-50840]
 	_ = x[ErrUserAlreadyExists-51003]
 	_ = x[ErrValueNegative-51024]
+	_ = x[ErrStageLookupNotAllowed-51047]
 	_ = x[ErrRegexOptions-51075]
 	_ = x[ErrRegexMissingParen-51091]
+	_ = x[ErrRegexNotObject-51103]
+	_ = x[ErrRegexInputNonString-51104]
+	_ = x[ErrRegexInvalidRegexType-51105]
+	_ = x[ErrRegexOptionsNonString-51106]
+	_ = x[ErrRegexOptionsConflict-51107]
 	_ = x[ErrBadRegexOption-51108]
+	_ = x[ErrRoundNonNumeric-51081]
+	_ = x[ErrRoundPrecisionNotIntegral-51082]
+	_ = x[ErrRoundPrecisionOutOfRange-51083]
+	_ = x[ErrRegexInvalid-51111]
+	_ = x[ErrStageMergeInvalidOn-51132]
+	_ = x[ErrStageMergeInvalidArg-51182]
+	_ = x[ErrStageMergeNoUniqueIndex-51183]
 	_ = x[ErrBadPositionalProjection-51246]
 	_ = x[ErrElementMismatchPositionalProjection-51247]
 	_ = x[ErrEmptySubProject-51270]
 	_ = x[ErrEmptyProject-51272]
+	_ = x[ErrReplaceReplacementNonString-51744]
+	_ = x[ErrReplaceFindNonString-51745]
+	_ = x[ErrReplaceInputNonString-51746]
+	_ = x[ErrReplaceMissingReplacement-51747]
+	_ = x[ErrReplaceMissingFind-51748]
+	_ = x[ErrReplaceMissingInput-51749]
+	_ = x[ErrReplaceUnknownArgument-51750]
+	_ = x[ErrReplaceNotObject-51751]
+	_ = x[ErrFilterLimitNotInt32-327391]
+	_ = x[ErrFilterLimitNotPositive-327392]
+	_ = x[ErrIfNullArgs-1257300]
+	_ = x[ErrSortArrayNotObject-2942500]
+	_ = x[ErrSortArrayUnknownField-2942501]
+	_ = x[ErrSortArrayMissingInput-2942502]
+	_ = x[ErrSortArrayMissingSortBy-2942503]
+	_ = x[ErrSortArrayInputNotArray-2942504]
 	_ = x[ErrDuplicateField-4822819]
+	_ = x[ErrArrayToObjectNullByte-4940400]
 	_ = x[ErrStageSkipBadValue-5107200]
 	_ = x[ErrStageLimitInvalidArg-5107201]
+	_ = x[ErrDateDiffNotObject-5166301]
+	_ = x[ErrDateDiffUnknownArgument-5166302]
+	_ = x[ErrDateDiffMissingStartDate-5166303]
+	_ = x[ErrDateDiffMissingEndDate-5166304]
+	_ = x[ErrDateDiffMissingUnit-5166305]
+	_ = x[ErrDateDiffInvalidDate-5166307]
+	_ = x[ErrDateAddNotObject-5166400]
+	_ = x[ErrDateAddUnknownArgument-5166401]
+	_ = x[ErrDateAddMissingArgument-5166402]
+	_ = x[ErrDateAddInvalidStartDate-5166403]
+	_ = x[ErrDateAddUnitNonString-5166404]
+	_ = x[ErrDateAddAmountNotIntegral-5166405]
+	_ = x[ErrDateAddOverflow-5166406]
+	_ = x[ErrDateTruncNotObject-5439007]
+	_ = x[ErrDateTruncUnknownArgument-5439008]
+	_ = x[ErrDateTruncMissingDate-5439009]
+	_ = x[ErrDateTruncMissingUnit-5439010]
+	_ = x[ErrDateTruncInvalidDate-5439012]
+	_ = x[ErrTimeUnitNonString-5439013]
+	_ = x[ErrStartOfWeekNonString-5439015]
+	_ = x[ErrStartOfWeekInvalid-5439016]
+	_ = x[ErrDateTruncBinSizeNotIntegral-5439017]
+	_ = x[ErrDateTruncBinSizeNotPositive-5439018]
 	_ = x[ErrStageCollStatsInvalidArg-5447000]
 	_ = x[ErrOpQueryCollectionSuffixMissing-5739101]
+	_ = x[ErrFirstLastNNotObject-5787801]
+	_ = x[ErrMinMaxNNotObject-5787900]
+	_ = x[ErrAccumulatorNUnknownField-5787901]
+	_ = x[ErrAccumulatorNNonNumeric-5787902]
+	_ = x[ErrAccumulatorNNonIntegral-5787903]
+	_ = x[ErrAccumulatorNMissingN-5787906]
+	_ = x[ErrAccumulatorNMissingInput-5787907]
+	_ = x[ErrAccumulatorNNonPositive-5787908]
+	_ = x[ErrTopBottomNotObject-5788001]
+	_ = x[ErrTopBottomUnknownField-5788002]
+	_ = x[ErrTopBottomMissingN-5788003]
+	_ = x[ErrTopBottomMissingOutput-5788004]
+	_ = x[ErrTopBottomMissingSortBy-5788005]
+	_ = x[ErrTopBottomSortByNotObject-5788604]
 	_ = x[ErrStageIndexedStringVectorDuplicate-7582300]
 }
 
-const _ErrorCode_name = "UnsetInternalErrorBadValueFailedToParseUserNotFoundUnauthorizedTypeMismatchProtocolErrorAuthenticationFailedIllegalOperationNamespaceNotFoundIndexNotFoundPathNotViableConflictingUpdateOperatorsCursorNotFoundNamespaceExistsMaxTimeMSExpiredDollarPrefixedFieldNameInvalidIdFieldEmptyFieldNameCommandNotFoundImmutableFieldCannotCreateIndexIndexAlreadyExistsInvalidOptionsInvalidNamespaceIndexOptionsConflictIndexKeySpecsConflictOperationFailedDocumentValidationFailureInvalidPipelineOperatorClientMetadataCannotBeMutatedInvalidIndexSpecificationOptionNotImplementedErrMechanismUnavailableUnsupportedOpQueryCommandLocation10065DuplicateKeyLocation15947Location15948Location15955Location15958Location15959Location15969Location15973Location15974Location15975Location15976Location15981Location15983Location15998Location16020Location16406Location16410Location16872Location16979Location17276Location28667Location28724Location28812Location28818Location31002Location31119Location31120Location31249Location31250Location31253Location31254Location31324Location31325Location31394Location31395Location40156Location40157Location40158Location40160Location40181Location40234Location40237Location40238Location40272Location40323Location40352Location40353Location40414Location40415Location40602Location40621Location50687Location50692Location50840Location51003Location51024Location51075Location51091Location51108Location51246Location51247Location51270Location51272Location4822819Location5107200Location5107201Location5447000Location5739101Location7582300"
+const _ErrorCode_name = "UnsetInternalErrorBadValueFailedToParseUserNotFoundUnauthorizedTypeMismatchProtocolErrorAuthenticationFailedIllegalOperationNamespaceNotFoundIndexNotFoundPathNotViableConflictingUpdateOperatorsCursorNotFoundNamespaceExistsMaxTimeMSExpiredDollarPrefixedFieldNameInvalidIdFieldEmptyFieldNameCommandNotFoundImmutableFieldCannotCreateIndexIndexAlreadyExistsInvalidOptionsInvalidNamespaceIndexOptionsConflictIndexKeySpecsConflictOperationFailedConflictingOperationInProgressDocumentValidationFailureExceededMemoryLimitInvalidPipelineOperatorClientMetadataCannotBeMutatedInvalidIndexSpecificationOptionInvalidUUIDTransactionTooOldNotImplementedConversionFailureNoSuchTransactionTransactionCommittedInvalidResumeTokenOperationNotSupportedInTransactionErrMechanismUnavailableUnsupportedOpQueryCommandLocation10065BSONObjectTooLargeDuplicateKeyMergeStageNoMatchingDocumentLocation15947Location15948Location15952Location15955Location15958Location15959Location15969Location15973Location15974Location15975Location15976Location15981Location15983Location15998Location16004Location16006Location16007Location16020Location16034Location16035Location16406Location16410Location16608Location16609Location16610Location16611Location16612Location16702Location16872Location16874Location16875Location16876Location16877Location16878Location16879Location16880Location16882Location16883Location16979Location16990Location17053Location17080Location17081Location17082Location17083Location17124Location17152Location17276Location18533Location18534Location18535Location18536Location18628Location18629Location28646Location28647Location28648Location28650Location28651Location28656Location28657Location28664Location28667Location28680Location28689Location28690Location28691Location28714Location28724Location28725Location28726Location28727Location28728Location28729Location28745Location28746Location28747Location28748Location28749Location28756Location28757Location28758Location28759Location28761Location28762Location28763Location28764Location28765Location28766Location28812Location28818Location31002Location31022Location31023Location31024Location31034Location31119Location31120Location31249Location31250Location31253Location31254Location31324Location31325Location31394Location31395Location31441Location34435Location34443Location34444Location34445Location34446Location34447Location34448Location34449Location34450Location34451Location34452Location34453Location34454Location34455Location34460Location34461Location34462Location34463Location34464Location34465Location34466Location34467Location34468Location34471Location34473Location40060Location40061Location40062Location40063Location40064Location40065Location40066Location40067Location40068Location40075Location40076Location40077Location40078Location40079Location40080Location40081Location40085Location40086Location40087Location40090Location40091Location40092Location40093Location40094Location40096Location40097Location40100Location40101Location40102Location40103Location40104Location40105Location40147Location40148Location40149Location40156Location40157Location40158Location40160Location40169Location40170Location40171Location40181Location40185Location40191Location40192Location40193Location40194Location40195Location40196Location40197Location40198Location40199Location40200Location40201Location40202Location40228Location40234Location40237Location40238Location40239Location40240Location40241Location40242Location40243Location40244Location40245Location40246Location40257Location40258Location40259Location40260Location40261Location40272Location40323Location40352Location40353Location40386Location40390Location40392Location40393Location40394Location40395Location40396Location40397Location40398Location40400Location40414Location40415Location40485Location40489Location40515Location40516Location40518Location40519Location40520Location40521Location40522Location40523Location40524Location40533Location40535Location40539Location40540Location40541Location40542Location40573Location40600Location40601Location40602Location40621Location40684Location50687Location50692Location50694Location50695Location50696Location50699Location50700Location50752Location50840Location51003Location51024Location51047Location51075Location51081Location51082Location51083Location51091Location51103Location51104Location51105Location51106Location51107Location51108Location51111Location51132Location51182Location51183Location51246Location51247Location51270Location51272Location51744Location51745Location51746Location51747Location51748Location51749Location51750Location51751Location327391Location327392Location1257300Location2942500Location2942501Location2942502Location2942503Location2942504Location4822819Location4940400Location5107200Location5107201Location5166301Location5166302Location5166303Location5166304Location5166305Location5166307Location5166400Location5166401Location5166402Location5166403Location5166404Location5166405Location5166406Location5439007Location5439008Location5439009Location5439010Location5439012Location5439013Location5439015Location5439016Location5439017Location5439018Location5447000Location5739101Location5787801Location5787900Location5787901Location5787902Location5787903Location5787906Location5787907Location5787908Location5788001Location5788002Location5788003Location5788004Location5788005Location5788604Location7582300"
 
 var _ErrorCode_map = map[ErrorCode]string{
 	0:       _ErrorCode_name[0:5],
@@ -148,83 +426,361 @@ var _ErrorCode_map = map[ErrorCode]string{
 	85:      _ErrorCode_name[383:403],
 	86:      _ErrorCode_name[403:424],
 	96:      _ErrorCode_name[424:439],
-	121:     _ErrorCode_name[439:464],
-	168:     _ErrorCode_name[464:487],
-	186:     _ErrorCode_name[487:516],
-	197:     _ErrorCode_name[516:547],
-	238:     _ErrorCode_name[547:561],
-	334:     _ErrorCode_name[561:584],
-	352:     _ErrorCode_name[584:609],
-	10065:   _ErrorCode_name[609:622],
-	11000:   _ErrorCode_name[622:634],
-	15947:   _ErrorCode_name[634:647],
-	15948:   _ErrorCode_name[647:660],
-	15955:   _ErrorCode_name[660:673],
-	15958:   _ErrorCode_name[673:686],
-	15959:   _ErrorCode_name[686:699],
-	15969:   _ErrorCode_name[699:712],
-	15973:   _ErrorCode_name[712:725],
-	15974:   _ErrorCode_name[725:738],
-	15975:   _ErrorCode_name[738:751],
-	15976:   _ErrorCode_name[751:764],
-	15981:   _ErrorCode_name[764:777],
-	15983:   _ErrorCode_name[777:790],
-	15998:   _ErrorCode_name[790:803],
-	16020:   _ErrorCode_name[803:816],
-	16406:   _ErrorCode_name[816:829],
-	16410:   _ErrorCode_name[829:842],
-	16872:   _ErrorCode_name[842:855],
-	16979:   _ErrorCode_name[855:868],
-	17276:   _ErrorCode_name[868:881],
-	28667:   _ErrorCode_name[881:894],
-	28724:   _ErrorCode_name[894:907],
-	28812:   _ErrorCode_name[907:920],
-	28818:   _ErrorCode_name[920:933],
-	31002:   _ErrorCode_name[933:946],
-	31119:   _ErrorCode_name[946:959],
-	31120:   _ErrorCode_name[959:972],
-	31249:   _ErrorCode_name[972:985],
-	31250:   _ErrorCode_name[985:998],
-	31253:   _ErrorCode_name[998:1011],
-	31254:   _ErrorCode_name[1011:1024],
-	31324:   _ErrorCode_name[1024:1037],
-	31325:   _ErrorCode_name[1037:1050],
-	31394:   _ErrorCode_name[1050:1063],
-	31395:   _ErrorCode_name[1063:1076],
-	40156:   _ErrorCode_name[1076:1089],
-	40157:   _ErrorCode_name[1089:1102],
-	40158:   _ErrorCode_name[1102:1115],
-	40160:   _ErrorCode_name[1115:1128],
-	40181:   _ErrorCode_name[1128:1141],
-	40234:   _ErrorCode_name[1141:1154],
-	40237:   _ErrorCode_name[1154:1167],
-	40238:   _ErrorCode_name[1167:1180],
-	40272:   _ErrorCode_name[1180:1193],
-	40323:   _ErrorCode_name[1193:1206],
-	40352:   _ErrorCode_name[1206:1219],
-	40353:   _ErrorCode_name[1219:1232],
-	40414:   _ErrorCode_name[1232:1245],
-	40415:   _ErrorCode_name[1245:1258],
-	40602:   _ErrorCode_name[1258:1271],
-	40621:   _ErrorCode_name[1271:1284],
-	50687:   _ErrorCode_name[1284:1297],
-	50692:   _ErrorCode_name[1297:1310],
-	50840:   _ErrorCode_name[1310:1323],
-	51003:   _ErrorCode_name[1323:1336],
-	51024:   _ErrorCode_name[1336:1349],
-	51075:   _ErrorCode_name[1349:1362],
-	51091:   _ErrorCode_name[1362:1375],
-	51108:   _ErrorCode_name[1375:1388],
-	51246:   _ErrorCode_name[1388:1401],
-	51247:   _ErrorCode_name[1401:1414],
-	51270:   _ErrorCode_name[1414:1427],
-	51272:   _ErrorCode_name[1427:1440],
-	4822819: _ErrorCode_name[1440:1455],
-	5107200: _ErrorCode_name[1455:1470],
-	5107201: _ErrorCode_name[1470:1485],
-	5447000: _ErrorCode_name[1485:1500],
-	5739101: _ErrorCode_name[1500:1515],
-	7582300: _ErrorCode_name[1515:1530],
+	117:     _ErrorCode_name[439:469],
+	121:     _ErrorCode_name[469:494],
+	146:     _ErrorCode_name[494:513],
+	168:     _ErrorCode_name[513:536],
+	186:     _ErrorCode_name[536:565],
+	197:     _ErrorCode_name[565:596],
+	207:     _ErrorCode_name[596:607],
+	225:     _ErrorCode_name[607:624],
+	238:     _ErrorCode_name[624:638],
+	241:     _ErrorCode_name[638:655],
+	251:     _ErrorCode_name[655:672],
+	256:     _ErrorCode_name[672:692],
+	260:     _ErrorCode_name[692:710],
+	263:     _ErrorCode_name[710:744],
+	334:     _ErrorCode_name[744:767],
+	352:     _ErrorCode_name[767:792],
+	10065:   _ErrorCode_name[792:805],
+	10334:   _ErrorCode_name[805:823],
+	11000:   _ErrorCode_name[823:835],
+	13113:   _ErrorCode_name[835:863],
+	15947:   _ErrorCode_name[863:876],
+	15948:   _ErrorCode_name[876:889],
+	15952:   _ErrorCode_name[889:902],
+	15955:   _ErrorCode_name[902:915],
+	15958:   _ErrorCode_name[915:928],
+	15959:   _ErrorCode_name[928:941],
+	15969:   _ErrorCode_name[941:954],
+	15973:   _ErrorCode_name[954:967],
+	15974:   _ErrorCode_name[967:980],
+	15975:   _ErrorCode_name[980:993],
+	15976:   _ErrorCode_name[993:1006],
+	15981:   _ErrorCode_name[1006:1019],
+	15983:   _ErrorCode_name[1019:1032],
+	15998:   _ErrorCode_name[1032:1045],
+	16004:   _ErrorCode_name[1045:1058],
+	16006:   _ErrorCode_name[1058:1071],
+	16007:   _ErrorCode_name[1071:1084],
+	16020:   _ErrorCode_name[1084:1097],
+	16034:   _ErrorCode_name[1097:1110],
+	16035:   _ErrorCode_name[1110:1123],
+	16406:   _ErrorCode_name[1123:1136],
+	16410:   _ErrorCode_name[1136:1149],
+	16608:   _ErrorCode_name[1149:1162],
+	16609:   _ErrorCode_name[1162:1175],
+	16610:   _ErrorCode_name[1175:1188],
+	16611:   _ErrorCode_name[1188:1201],
+	16612:   _ErrorCode_name[1201:1214],
+	16702:   _ErrorCode_name[1214:1227],
+	16872:   _ErrorCode_name[1227:1240],
+	16874:   _ErrorCode_name[1240:1253],
+	16875:   _ErrorCode_name[1253:1266],
+	16876:   _ErrorCode_name[1266:1279],
+	16877:   _ErrorCode_name[1279:1292],
+	16878:   _ErrorCode_name[1292:1305],
+	16879:   _ErrorCode_name[1305:1318],
+	16880:   _ErrorCode_name[1318:1331],
+	16882:   _ErrorCode_name[1331:1344],
+	16883:   _ErrorCode_name[1344:1357],
+	16979:   _ErrorCode_name[1357:1370],
+	16990:   _ErrorCode_name[1370:1383],
+	17053:   _ErrorCode_name[1383:1396],
+	17080:   _ErrorCode_name[1396:1409],
+	17081:   _ErrorCode_name[1409:1422],
+	17082:   _ErrorCode_name[1422:1435],
+	17083:   _ErrorCode_name[1435:1448],
+	17124:   _ErrorCode_name[1448:1461],
+	17152:   _ErrorCode_name[1461:1474],
+	17276:   _ErrorCode_name[1474:1487],
+	18533:   _ErrorCode_name[1487:1500],
+	18534:   _ErrorCode_name[1500:1513],
+	18535:   _ErrorCode_name[1513:1526],
+	18536:   _ErrorCode_name[1526:1539],
+	18628:   _ErrorCode_name[1539:1552],
+	18629:   _ErrorCode_name[1552:1565],
+	28646:   _ErrorCode_name[1565:1578],
+	28647:   _ErrorCode_name[1578:1591],
+	28648:   _ErrorCode_name[1591:1604],
+	28650:   _ErrorCode_name[1604:1617],
+	28651:   _ErrorCode_name[1617:1630],
+	28656:   _ErrorCode_name[1630:1643],
+	28657:   _ErrorCode_name[1643:1656],
+	28664:   _ErrorCode_name[1656:1669],
+	28667:   _ErrorCode_name[1669:1682],
+	28680:   _ErrorCode_name[1682:1695],
+	28689:   _ErrorCode_name[1695:1708],
+	28690:   _ErrorCode_name[1708:1721],
+	28691:   _ErrorCode_name[1721:1734],
+	28714:   _ErrorCode_name[1734:1747],
+	28724:   _ErrorCode_name[1747:1760],
+	28725:   _ErrorCode_name[1760:1773],
+	28726:   _ErrorCode_name[1773:1786],
+	28727:   _ErrorCode_name[1786:1799],
+	28728:   _ErrorCode_name[1799:1812],
+	28729:   _ErrorCode_name[1812:1825],
+	28745:   _ErrorCode_name[1825:1838],
+	28746:   _ErrorCode_name[1838:1851],
+	28747:   _ErrorCode_name[1851:1864],
+	28748:   _ErrorCode_name[1864:1877],
+	28749:   _ErrorCode_name[1877:1890],
+	28756:   _ErrorCode_name[1890:1903],
+	28757:   _ErrorCode_name[1903:1916],
+	28758:   _ErrorCode_name[1916:1929],
+	28759:   _ErrorCode_name[1929:1942],
+	28761:   _ErrorCode_name[1942:1955],
+	28762:   _ErrorCode_name[1955:1968],
+	28763:   _ErrorCode_name[1968:1981],
+	28764:   _ErrorCode_name[1981:1994],
+	28765:   _ErrorCode_name[1994:2007],
+	28766:   _ErrorCode_name[2007:2020],
+	28812:   _ErrorCode_name[2020:2033],
+	28818:   _ErrorCode_name[2033:2046],
+	31002:   _ErrorCode_name[2046:2059],
+	31022:   _ErrorCode_name[2059:2072],
+	31023:   _ErrorCode_name[2072:2085],
+	31024:   _ErrorCode_name[2085:2098],
+	31034:   _ErrorCode_name[2098:2111],
+	31119:   _ErrorCode_name[2111:2124],
+	31120:   _ErrorCode_name[2124:2137],
+	31249:   _ErrorCode_name[2137:2150],
+	31250:   _ErrorCode_name[2150:2163],
+	31253:   _ErrorCode_name[2163:2176],
+	31254:   _ErrorCode_name[2176:2189],
+	31324:   _ErrorCode_name[2189:2202],
+	31325:   _ErrorCode_name[2202:2215],
+	31394:   _ErrorCode_name[2215:2228],
+	31395:   _ErrorCode_name[2228:2241],
+	31441:   _ErrorCode_name[2241:2254],
+	34435:   _ErrorCode_name[2254:2267],
+	34443:   _ErrorCode_name[2267:2280],
+	34444:   _ErrorCode_name[2280:2293],
+	34445:   _ErrorCode_name[2293:2306],
+	34446:   _ErrorCode_name[2306:2319],
+	34447:   _ErrorCode_name[2319:2332],
+	34448:   _ErrorCode_name[2332:2345],
+	34449:   _ErrorCode_name[2345:2358],
+	34450:   _ErrorCode_name[2358:2371],
+	34451:   _ErrorCode_name[2371:2384],
+	34452:   _ErrorCode_name[2384:2397],
+	34453:   _ErrorCode_name[2397:2410],
+	34454:   _ErrorCode_name[2410:2423],
+	34455:   _ErrorCode_name[2423:2436],
+	34460:   _ErrorCode_name[2436:2449],
+	34461:   _ErrorCode_name[2449:2462],
+	34462:   _ErrorCode_name[2462:2475],
+	34463:   _ErrorCode_name[2475:2488],
+	34464:   _ErrorCode_name[2488:2501],
+	34465:   _ErrorCode_name[2501:2514],
+	34466:   _ErrorCode_name[2514:2527],
+	34467:   _ErrorCode_name[2527:2540],
+	34468:   _ErrorCode_name[2540:2553],
+	34471:   _ErrorCode_name[2553:2566],
+	34473:   _ErrorCode_name[2566:2579],
+	40060:   _ErrorCode_name[2579:2592],
+	40061:   _ErrorCode_name[2592:2605],
+	40062:   _ErrorCode_name[2605:2618],
+	40063:   _ErrorCode_name[2618:2631],
+	40064:   _ErrorCode_name[2631:2644],
+	40065:   _ErrorCode_name[2644:2657],
+	40066:   _ErrorCode_name[2657:2670],
+	40067:   _ErrorCode_name[2670:2683],
+	40068:   _ErrorCode_name[2683:2696],
+	40075:   _ErrorCode_name[2696:2709],
+	40076:   _ErrorCode_name[2709:2722],
+	40077:   _ErrorCode_name[2722:2735],
+	40078:   _ErrorCode_name[2735:2748],
+	40079:   _ErrorCode_name[2748:2761],
+	40080:   _ErrorCode_name[2761:2774],
+	40081:   _ErrorCode_name[2774:2787],
+	40085:   _ErrorCode_name[2787:2800],
+	40086:   _ErrorCode_name[2800:2813],
+	40087:   _ErrorCode_name[2813:2826],
+	40090:   _ErrorCode_name[2826:2839],
+	40091:   _ErrorCode_name[2839:2852],
+	40092:   _ErrorCode_name[2852:2865],
+	40093:   _ErrorCode_name[2865:2878],
+	40094:   _ErrorCode_name[2878:2891],
+	40096:   _ErrorCode_name[2891:2904],
+	40097:   _ErrorCode_name[2904:2917],
+	40100:   _ErrorCode_name[2917:2930],
+	40101:   _ErrorCode_name[2930:2943],
+	40102:   _ErrorCode_name[2943:2956],
+	40103:   _ErrorCode_name[2956:2969],
+	40104:   _ErrorCode_name[2969:2982],
+	40105:   _ErrorCode_name[2982:2995],
+	40147:   _ErrorCode_name[2995:3008],
+	40148:   _ErrorCode_name[3008:3021],
+	40149:   _ErrorCode_name[3021:3034],
+	40156:   _ErrorCode_name[3034:3047],
+	40157:   _ErrorCode_name[3047:3060],
+	40158:   _ErrorCode_name[3060:3073],
+	40160:   _ErrorCode_name[3073:3086],
+	40169:   _ErrorCode_name[3086:3099],
+	40170:   _ErrorCode_name[3099:3112],
+	40171:   _ErrorCode_name[3112:3125],
+	40181:   _ErrorCode_name[3125:3138],
+	40185:   _ErrorCode_name[3138:3151],
+	40191:   _ErrorCode_name[3151:3164],
+	40192:   _ErrorCode_name[3164:3177],
+	40193:   _ErrorCode_name[3177:3190],
+	40194:   _ErrorCode_name[3190:3203],
+	40195:   _ErrorCode_name[3203:3216],
+	40196:   _ErrorCode_name[3216:3229],
+	40197:   _ErrorCode_name[3229:3242],
+	40198:   _ErrorCode_name[3242:3255],
+	40199:   _ErrorCode_name[3255:3268],
+	40200:   _ErrorCode_name[3268:3281],
+	40201:   _ErrorCode_name[3281:3294],
+	40202:   _ErrorCode_name[3294:3307],
+	40228:   _ErrorCode_name[3307:3320],
+	40234:   _ErrorCode_name[3320:3333],
+	40237:   _ErrorCode_name[3333:3346],
+	40238:   _ErrorCode_name[3346:3359],
+	40239:   _ErrorCode_name[3359:3372],
+	40240:   _ErrorCode_name[3372:3385],
+	40241:   _ErrorCode_name[3385:3398],
+	40242:   _ErrorCode_name[3398:3411],
+	40243:   _ErrorCode_name[3411:3424],
+	40244:   _ErrorCode_name[3424:3437],
+	40245:   _ErrorCode_name[3437:3450],
+	40246:   _ErrorCode_name[3450:3463],
+	40257:   _ErrorCode_name[3463:3476],
+	40258:   _ErrorCode_name[3476:3489],
+	40259:   _ErrorCode_name[3489:3502],
+	40260:   _ErrorCode_name[3502:3515],
+	40261:   _ErrorCode_name[3515:3528],
+	40272:   _ErrorCode_name[3528:3541],
+	40323:   _ErrorCode_name[3541:3554],
+	40352:   _ErrorCode_name[3554:3567],
+	40353:   _ErrorCode_name[3567:3580],
+	40386:   _ErrorCode_name[3580:3593],
+	40390:   _ErrorCode_name[3593:3606],
+	40392:   _ErrorCode_name[3606:3619],
+	40393:   _ErrorCode_name[3619:3632],
+	40394:   _ErrorCode_name[3632:3645],
+	40395:   _ErrorCode_name[3645:3658],
+	40396:   _ErrorCode_name[3658:3671],
+	40397:   _ErrorCode_name[3671:3684],
+	40398:   _ErrorCode_name[3684:3697],
+	40400:   _ErrorCode_name[3697:3710],
+	40414:   _ErrorCode_name[3710:3723],
+	40415:   _ErrorCode_name[3723:3736],
+	40485:   _ErrorCode_name[3736:3749],
+	40489:   _ErrorCode_name[3749:3762],
+	40515:   _ErrorCode_name[3762:3775],
+	40516:   _ErrorCode_name[3775:3788],
+	40518:   _ErrorCode_name[3788:3801],
+	40519:   _ErrorCode_name[3801:3814],
+	40520:   _ErrorCode_name[3814:3827],
+	40521:   _ErrorCode_name[3827:3840],
+	40522:   _ErrorCode_name[3840:3853],
+	40523:   _ErrorCode_name[3853:3866],
+	40524:   _ErrorCode_name[3866:3879],
+	40533:   _ErrorCode_name[3879:3892],
+	40535:   _ErrorCode_name[3892:3905],
+	40539:   _ErrorCode_name[3905:3918],
+	40540:   _ErrorCode_name[3918:3931],
+	40541:   _ErrorCode_name[3931:3944],
+	40542:   _ErrorCode_name[3944:3957],
+	40573:   _ErrorCode_name[3957:3970],
+	40600:   _ErrorCode_name[3970:3983],
+	40601:   _ErrorCode_name[3983:3996],
+	40602:   _ErrorCode_name[3996:4009],
+	40621:   _ErrorCode_name[4009:4022],
+	40684:   _ErrorCode_name[4022:4035],
+	50687:   _ErrorCode_name[4035:4048],
+	50692:   _ErrorCode_name[4048:4061],
+	50694:   _ErrorCode_name[4061:4074],
+	50695:   _ErrorCode_name[4074:4087],
+	50696:   _ErrorCode_name[4087:4100],
+	50699:   _ErrorCode_name[4100:4113],
+	50700:   _ErrorCode_name[4113:4126],
+	50752:   _ErrorCode_name[4126:4139],
+	50840:   _ErrorCode_name[4139:4152],
+	51003:   _ErrorCode_name[4152:4165],
+	51024:   _ErrorCode_name[4165:4178],
+	51047:   _ErrorCode_name[4178:4191],
+	51075:   _ErrorCode_name[4191:4204],
+	51081:   _ErrorCode_name[4204:4217],
+	51082:   _ErrorCode_name[4217:4230],
+	51083:   _ErrorCode_name[4230:4243],
+	51091:   _ErrorCode_name[4243:4256],
+	51103:   _ErrorCode_name[4256:4269],
+	51104:   _ErrorCode_name[4269:4282],
+	51105:   _ErrorCode_name[4282:4295],
+	51106:   _ErrorCode_name[4295:4308],
+	51107:   _ErrorCode_name[4308:4321],
+	51108:   _ErrorCode_name[4321:4334],
+	51111:   _ErrorCode_name[4334:4347],
+	51132:   _ErrorCode_name[4347:4360],
+	51182:   _ErrorCode_name[4360:4373],
+	51183:   _ErrorCode_name[4373:4386],
+	51246:   _ErrorCode_name[4386:4399],
+	51247:   _ErrorCode_name[4399:4412],
+	51270:   _ErrorCode_name[4412:4425],
+	51272:   _ErrorCode_name[4425:4438],
+	51744:   _ErrorCode_name[4438:4451],
+	51745:   _ErrorCode_name[4451:4464],
+	51746:   _ErrorCode_name[4464:4477],
+	51747:   _ErrorCode_name[4477:4490],
+	51748:   _ErrorCode_name[4490:4503],
+	51749:   _ErrorCode_name[4503:4516],
+	51750:   _ErrorCode_name[4516:4529],
+	51751:   _ErrorCode_name[4529:4542],
+	327391:  _ErrorCode_name[4542:4556],
+	327392:  _ErrorCode_name[4556:4570],
+	1257300: _ErrorCode_name[4570:4585],
+	2942500: _ErrorCode_name[4585:4600],
+	2942501: _ErrorCode_name[4600:4615],
+	2942502: _ErrorCode_name[4615:4630],
+	2942503: _ErrorCode_name[4630:4645],
+	2942504: _ErrorCode_name[4645:4660],
+	4822819: _ErrorCode_name[4660:4675],
+	4940400: _ErrorCode_name[4675:4690],
+	5107200: _ErrorCode_name[4690:4705],
+	5107201: _ErrorCode_name[4705:4720],
+	5166301: _ErrorCode_name[4720:4735],
+	5166302: _ErrorCode_name[4735:4750],
+	5166303: _ErrorCode_name[4750:4765],
+	5166304: _ErrorCode_name[4765:4780],
+	5166305: _ErrorCode_name[4780:4795],
+	5166307: _ErrorCode_name[4795:4810],
+	5166400: _ErrorCode_name[4810:4825],
+	5166401: _ErrorCode_name[4825:4840],
+	5166402: _ErrorCode_name[4840:4855],
+	5166403: _ErrorCode_name[4855:4870],
+	5166404: _ErrorCode_name[4870:4885],
+	5166405: _ErrorCode_name[4885:4900],
+	5166406: _ErrorCode_name[4900:4915],
+	5439007: _ErrorCode_name[4915:4930],
+	5439008: _ErrorCode_name[4930:4945],
+	5439009: _ErrorCode_name[4945:4960],
+	5439010: _ErrorCode_name[4960:4975],
+	5439012: _ErrorCode_name[4975:4990],
+	5439013: _ErrorCode_name[4990:5005],
+	5439015: _ErrorCode_name[5005:5020],
+	5439016: _ErrorCode_name[5020:5035],
+	5439017: _ErrorCode_name[5035:5050],
+	5439018: _ErrorCode_name[5050:5065],
+	5447000: _ErrorCode_name[5065:5080],
+	5739101: _ErrorCode_name[5080:5095],
+	5787801: _ErrorCode_name[5095:5110],
+	5787900: _ErrorCode_name[5110:5125],
+	5787901: _ErrorCode_name[5125:5140],
+	5787902: _ErrorCode_name[5140:5155],
+	5787903: _ErrorCode_name[5155:5170],
+	5787906: _ErrorCode_name[5170:5185],
+	5787907: _ErrorCode_name[5185:5200],
+	5787908: _ErrorCode_name[5200:5215],
+	5788001: _ErrorCode_name[5215:5230],
+	5788002: _ErrorCode_name[5230:5245],
+	5788003: _ErrorCode_name[5245:5260],
+	5788004: _ErrorCode_name[5260:5275],
+	5788005: _ErrorCode_name[5275:5290],
+	5788604: _ErrorCode_name[5290:5305],
+	7582300: _ErrorCode_name[5305:5320],
 }
 
 func (i ErrorCode) String() string {
@@ -197,7 +197,7 @@ func getOptionalPositiveNumber(key string, value any) (int64, error) {
 }
 
 // GetBoolOptionalParam returns bool value of v.
-// Non-zero double, long, and int values return true.
+// Non-zero double, long, int, and decimal values return true.
 // Zero values for those types, as well as nulls and missing fields, return false.
 // Other types return a protocol error.
 func GetBoolOptionalParam(key string, v any) (bool, error) {
@@ -212,6 +212,8 @@ func GetBoolOptionalParam(key string, v any) (bool, error) {
 		return v != 0, nil
 	case int64:
 		return v != 0, nil
+	case types.Decimal128:
+		return v.Sign() != 0 || v.IsNaN(), nil
 	default:
 		msg := fmt.Sprintf(
 			`BSON field '%s' is the wrong type '%s', expected types '[bool, long, int, decimal, double]'`,
@@ -29,6 +29,7 @@ import (
 	"github.com/FerretDB/FerretDB/internal/backends"
 	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
 	"github.com/FerretDB/FerretDB/internal/clientconn/cursor"
+	"github.com/FerretDB/FerretDB/internal/clientconn/session"
 	"github.com/FerretDB/FerretDB/internal/handler/common"
 	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
 	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations/stages"
@@ -49,17 +50,30 @@ func (h *Handler) MsgAggregate(connCtx context.Context, msg *wire.OpMsg) (*wire.
 		return nil, lazyerrors.Error(err)
 	}
 
-	common.Ignored(document, h.L, "lsid")
+	if err = common.Unimplemented(document, "explain", "let"); err != nil {
+		return nil, err
+	}
 
-	if err = common.Unimplemented(document, "explain", "collation", "let"); err != nil {
+	collationDoc, err := common.GetOptionalParam[*types.Document](document, "collation", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	collation, err := common.GetCollation(collationDoc, "aggregate")
+	if err != nil {
 		return nil, err
 	}
 
 	common.Ignored(
 		document, h.L,
-		"allowDiskUse", "bypassDocumentValidation", "readConcern", "hint", "comment", "writeConcern",
+		"allowDiskUse", "readConcern", "hint", "comment", "writeConcern",
 	)
 
+	bypassDocumentValidation, err := common.GetOptionalParam(document, "bypassDocumentValidation", false)
+	if err != nil {
+		return nil, err
+	}
+
 	var dbName string
 
 	if dbName, err = common.GetRequiredParam[string](document, "$db"); err != nil {
@@ -72,16 +86,22 @@ func (h *Handler) MsgAggregate(connCtx context.Context, msg *wire.OpMsg) (*wire.
 	}
 
 	// handle collection-agnostic pipelines ({aggregate: 1})
-	// TODO https://github.com/FerretDB/FerretDB/issues/1890
-	var ok bool
+	var ok, agnostic bool
 	var cName string
 
-	if cName, ok = collectionParam.(string); !ok {
-		return nil, handlererrors.NewCommandErrorMsgWithArgument(
-			handlererrors.ErrFailedToParse,
-			"Invalid command format: the 'aggregate' field must specify a collection name or 1",
-			document.Command(),
-		)
+	switch collectionParam := collectionParam.(type) {
+	case string:
+		cName = collectionParam
+	default:
+		if n, nErr := handlerparams.GetWholeNumberParam(collectionParam); nErr != nil || n != 1 {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrFailedToParse,
+				"Invalid command format: the 'aggregate' field must specify a collection name or 1",
+				document.Command(),
+			)
+		}
+
+		agnostic = true
 	}
 
 	db, err := h.b.Database(dbName)
@@ -94,14 +114,19 @@ func (h *Handler) MsgAggregate(connCtx context.Context, msg *wire.OpMsg) (*wire.
 		return nil, lazyerrors.Error(err)
 	}
 
-	c, err := db.Collection(cName)
-	if err != nil {
-		if backends.ErrorCodeIs(err, backends.ErrorCodeCollectionNameIsInvalid) {
-			msg := fmt.Sprintf("Invalid collection name: %s", cName)
-			return nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrInvalidNamespace, msg, document.Command())
-		}
+	// c is nil for collection-agnostic pipelines
+	var c backends.Collection
 
-		return nil, lazyerrors.Error(err)
+	if !agnostic {
+		c, err = db.Collection(cName)
+		if err != nil {
+			if backends.ErrorCodeIs(err, backends.ErrorCodeCollectionNameIsInvalid) {
+				msg := fmt.Sprintf("Invalid collection name: %s", cName)
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrInvalidNamespace, msg, document.Command())
+			}
+
+			return nil, lazyerrors.Error(err)
+		}
 	}
 
 	username := conninfo.Get(connCtx).Username()
@@ -184,6 +209,32 @@ func (h *Handler) MsgAggregate(connCtx context.Context, msg *wire.OpMsg) (*wire.
 	stagesDocuments := make([]aggregations.Stage, 0, len(aggregationStages))
 	collStatsDocuments := make([]aggregations.Stage, 0, len(aggregationStages))
 
+	stageParams := &stages.NewStageParams{
+		Backend:                  h.b,
+		Database:                 db,
+		DBName:                   dbName,
+		CollectionName:           cName,
+		MaxBsonObjectSizeBytes:   h.MaxBsonObjectSizeBytes,
+		BypassDocumentValidation: bypassDocumentValidation,
+		L:                        h.L,
+		Sessions:                 h.sessions,
+		Username:                 username,
+		AllUsersSessions:         h.allUsersSessions(connCtx),
+		Collation:                collation,
+		DisablePushdown:          h.DisablePushdown,
+	}
+
+	if agnostic && len(aggregationStages) == 0 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrInvalidNamespace,
+			"{aggregate: 1} is not valid for an empty pipeline.",
+			document.Command(),
+		)
+	}
+
+	// change stream pipelines use tailable cursors
+	var changeStream bool
+
 	for i, v := range aggregationStages {
 		var d *types.Document
 
@@ -195,9 +246,17 @@ func (h *Handler) MsgAggregate(connCtx context.Context, msg *wire.OpMsg) (*wire.
 			)
 		}
 
+		if agnostic && i == 0 && d.Command() != "$changeStream" && d.Command() != "$listLocalSessions" {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrInvalidNamespace,
+				fmt.Sprintf("{aggregate: 1} is not valid for '%s'; a collection is required.", d.Command()),
+				document.Command(),
+			)
+		}
+
 		var s aggregations.Stage
 
-		if s, err = stages.NewStage(d); err != nil {
+		if s, err = stages.NewStage(d, stageParams); err != nil {
 			return nil, err
 		}
 
@@ -212,6 +271,40 @@ func (h *Handler) MsgAggregate(connCtx context.Context, msg *wire.OpMsg) (*wire.
 			}
 
 			collStatsDocuments = append(collStatsDocuments, s)
+		case "$changeStream":
+			if i > 0 {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrCollStatsIsNotFirstStage,
+					"$changeStream is only valid as the first stage in a pipeline",
+					document.Command(),
+				)
+			}
+
+			changeStream = true
+
+			stagesDocuments = append(stagesDocuments, s)
+			collStatsDocuments = append(collStatsDocuments, s)
+		case "$listSessions", "$listLocalSessions":
+			if i > 0 {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrCollStatsIsNotFirstStage,
+					fmt.Sprintf("%s is only valid as the first stage in a pipeline", d.Command()),
+					document.Command(),
+				)
+			}
+
+			stagesDocuments = append(stagesDocuments, s)
+			collStatsDocuments = append(collStatsDocuments, s)
+		case "$out", "$merge":
+			if i < len(aggregationStages)-1 {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrStageNotLast,
+					fmt.Sprintf("%s can only be the final stage in the pipeline", d.Command()),
+					document.Command(),
+				)
+			}
+
+			fallthrough
 		default:
 			stagesDocuments = append(stagesDocuments, s)
 			collStatsDocuments = append(collStatsDocuments, s) // It's possible to apply any stage after $collStats stage
@@ -250,7 +343,8 @@ func (h *Handler) MsgAggregate(connCtx context.Context, msg *wire.OpMsg) (*wire.
 		return nil, err
 	}
 
-	ctx := connCtx
+	// `$$NOW` is the same for all stages and documents of the command
+	ctx := aggregations.CtxWithVariables(connCtx, aggregations.NewVariables(time.Now()))
 	cancel := func() {}
 
 	// TODO https://github.com/FerretDB/FerretDB/issues/2983
@@ -274,6 +368,11 @@ func (h *Handler) MsgAggregate(connCtx context.Context, msg *wire.OpMsg) (*wire.
 
 	closer := iterator.NewMultiCloser(iterator.CloserFunc(cancel))
 
+	// $changeStream stage reads OpLog entries instead of collection documents
+	if changeStream {
+		c = nil
+	}
+
 	var iter iterator.Interface[struct{}, *types.Document]
 
 	if len(collStatsDocuments) == len(stagesDocuments) {
@@ -282,12 +381,13 @@ func (h *Handler) MsgAggregate(connCtx context.Context, msg *wire.OpMsg) (*wire.
 		// only documents stages or no stages - fetch documents from the DB and apply stages to them
 		qp := new(backends.QueryParams)
 
-		if !h.DisablePushdown {
+		// backends compare strings as bytes, so filter can't be pushed down with non-simple collation
+		if !h.DisablePushdown && collation == nil {
 			qp.Filter = filter
 		}
 
-		if !h.EnableNestedPushdown && filter != nil {
-			qp.Filter = filter.DeepCopy()
+		if !h.EnableNestedPushdown && qp.Filter != nil {
+			qp.Filter = qp.Filter.DeepCopy()
 
 			for _, k := range qp.Filter.Keys() {
 				if !strings.ContainsRune(k, '.') {
@@ -313,23 +413,31 @@ func (h *Handler) MsgAggregate(connCtx context.Context, msg *wire.OpMsg) (*wire.
 			return nil, err
 		}
 
-		var cList *backends.ListCollectionsResult
+		var cInfo backends.CollectionInfo
 
-		collectionParam := backends.ListCollectionsParams{Name: cName}
-		if cList, err = db.ListCollections(ctx, &collectionParam); err != nil {
-			closer.Close()
-			return nil, handleMaxTimeMSError(err, maxTimeMS, "aggregate")
-		}
+		if c != nil {
+			var cList *backends.ListCollectionsResult
 
-		var cInfo backends.CollectionInfo
+			collectionParam := backends.ListCollectionsParams{Name: cName}
+			if cList, err = db.ListCollections(ctx, &collectionParam); err != nil {
+				closer.Close()
+				return nil, handleMaxTimeMSError(err, maxTimeMS, "aggregate")
+			}
 
-		if len(cList.Collections) > 0 {
-			cInfo = cList.Collections[0]
+			if len(cList.Collections) > 0 {
+				cInfo = cList.Collections[0]
+			}
 		}
 
+		sample := stages.GetPushdownSample(stagesDocuments)
+
 		switch {
 		case h.DisablePushdown:
 			// Pushdown disabled
+		case sample != 0:
+			// Pushdown random sampling of the first $sample stage;
+			// the stage still samples returned documents
+			qp.Sample = sample
 		case sort.Len() == 0 && cInfo.Capped():
 			// Pushdown default recordID sorting for capped collections
 			qp.Sort = must.NotFail(types.NewDocument("$natural", int64(1)))
@@ -367,12 +475,27 @@ func (h *Handler) MsgAggregate(connCtx context.Context, msg *wire.OpMsg) (*wire.
 
 	closer.Add(iter)
 
-	cursor := h.cursors.NewCursor(ctx, iterator.WithClose(iter, closer.Close), &cursor.NewParams{
+	// collection-agnostic pipelines use the same namespace as MongoDB
+	if agnostic {
+		cName = "$cmd.aggregate"
+	}
+
+	cursorParams := &cursor.NewParams{
 		DB:         dbName,
 		Collection: cName,
 		Username:   username,
+		SessionID:  session.GetID(connCtx),
 		Type:       cursor.Normal,
-	})
+	}
+
+	if changeStream {
+		cursorParams.Data = &changeStreamCursorData{
+			stages: stagesDocuments,
+		}
+		cursorParams.Type = cursor.TailableAwait
+	}
+
+	cursor := h.cursors.NewCursor(ctx, iterator.WithClose(iter, closer.Close), cursorParams)
 
 	cursorID := cursor.ID
 
@@ -395,7 +518,8 @@ func (h *Handler) MsgAggregate(connCtx context.Context, msg *wire.OpMsg) (*wire.
 		firstBatch.Append(doc)
 	}
 
-	if firstBatch.Len() < int(batchSize) {
+	// change stream cursors are never exhausted
+	if !changeStream && firstBatch.Len() < int(batchSize) {
 		// let the client know that there are no more results
 		cursorID = 0
 
@@ -414,6 +538,12 @@ func (h *Handler) MsgAggregate(connCtx context.Context, msg *wire.OpMsg) (*wire.
 	)
 }
 
+// changeStreamCursorData contains data of the change stream cursor
+// that is used to get new change events on getMore.
+type changeStreamCursorData struct {
+	stages []aggregations.Stage
+}
+
 // stagesDocumentsParams contains the parameters for processStagesDocuments.
 type stagesDocumentsParams struct {
 	c      backends.Collection
@@ -422,16 +552,26 @@ type stagesDocumentsParams struct {
 }
 
 // processStagesDocuments retrieves the documents from the database and then processes them through the stages.
+//
+// If collection is nil (collection-agnostic pipeline), stages get no input documents.
 func processStagesDocuments(ctx context.Context, closer *iterator.MultiCloser, p *stagesDocumentsParams) (types.DocumentsIterator, error) { //nolint:lll // for readability
-	queryRes, err := p.c.Query(ctx, p.qp)
-	if err != nil {
-		closer.Close()
-		return nil, lazyerrors.Error(err)
+	var iter types.DocumentsIterator
+
+	if p.c == nil {
+		iter = iterator.Values(iterator.ForSlice[*types.Document](nil))
+	} else {
+		queryRes, err := p.c.Query(ctx, p.qp)
+		if err != nil {
+			closer.Close()
+			return nil, lazyerrors.Error(err)
+		}
+
+		iter = queryRes.Iter
 	}
 
-	closer.Add(queryRes.Iter)
+	closer.Add(iter)
 
-	iter := queryRes.Iter
+	var err error
 
 	for _, s := range p.stages {
 		if iter, err = s.Process(ctx, iter, closer); err != nil {
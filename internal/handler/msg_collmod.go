@@ -15,19 +15,147 @@
 package handler
 
 import (
+	"cmp"
 	"context"
+	"fmt"
 
 	"github.com/FerretDB/wire"
 
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/handler/common"
 	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
 )
 
 // MsgCollMod implements `collMod` command.
 //
 // The passed context is canceled when the client connection is closed.
 func (h *Handler) MsgCollMod(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
-	return nil, handlererrors.NewCommandErrorMsg(
-		handlererrors.ErrNotImplemented,
-		"`collMod` command is not implemented yet",
-	)
+	document, err := opMsgDocument(msg)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	unimplementedFields := []string{
+		"index",
+		"expireAfterSeconds",
+		"viewOn",
+		"pipeline",
+		"changeStreamPreAndPostImages",
+		"timeseries",
+		"cappedSize",
+		"cappedMax",
+	}
+	if err = common.Unimplemented(document, unimplementedFields...); err != nil {
+		return nil, err
+	}
+
+	ignoredFields := []string{
+		"writeConcern",
+		"comment",
+	}
+	common.Ignored(document, h.L, ignoredFields...)
+
+	command := document.Command()
+
+	dbName, err := common.GetRequiredParam[string](document, "$db")
+	if err != nil {
+		return nil, err
+	}
+
+	collectionName, err := common.GetRequiredParam[string](document, command)
+	if err != nil {
+		return nil, err
+	}
+
+	validation, err := common.GetValidationOptions(document)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := h.b.Database(dbName)
+	if err != nil {
+		if backends.ErrorCodeIs(err, backends.ErrorCodeDatabaseNameIsInvalid) {
+			msg := fmt.Sprintf("Invalid namespace specified '%s.%s'", dbName, collectionName)
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrInvalidNamespace, msg, command)
+		}
+
+		return nil, lazyerrors.Error(err)
+	}
+
+	list, err := db.ListCollections(connCtx, &backends.ListCollectionsParams{Name: collectionName})
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if len(list.Collections) == 0 {
+		msg := fmt.Sprintf("ns does not exist: %s.%s", dbName, collectionName)
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrNamespaceNotFound, msg, command)
+	}
+
+	// absent options keep their current values
+	info := list.Collections[0]
+
+	params := backends.ModifyCollectionParams{
+		Name:             collectionName,
+		Validator:        info.Validator,
+		ValidationLevel:  info.ValidationLevel,
+		ValidationAction: info.ValidationAction,
+	}
+
+	if document.Has("validator") {
+		params.Validator = validation.Validator
+
+		// empty validator removes validation
+		if params.Validator.Len() == 0 {
+			params.Validator = nil
+		}
+	}
+
+	if validation.Level != "" {
+		params.ValidationLevel = validation.Level
+	}
+
+	if validation.Action != "" {
+		params.ValidationAction = validation.Action
+	}
+
+	if params.Validator == nil {
+		params.ValidationLevel = ""
+		params.ValidationAction = ""
+	} else {
+		params.ValidationLevel = cmp.Or(params.ValidationLevel, common.ValidationLevelStrict)
+		params.ValidationAction = cmp.Or(params.ValidationAction, common.ValidationActionError)
+	}
+
+	err = db.ModifyCollection(connCtx, &params)
+
+	switch {
+	case err == nil:
+		return documentOpMsg(
+			must.NotFail(types.NewDocument(
+				"ok", float64(1),
+			)),
+		)
+
+	case backends.ErrorCodeIs(err, backends.ErrorCodeCollectionNameIsInvalid):
+		msg := fmt.Sprintf("Invalid collection name: %s", collectionName)
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrInvalidNamespace, msg, command)
+
+	case backends.ErrorCodeIs(err, backends.ErrorCodeCollectionDoesNotExist):
+		msg := fmt.Sprintf("ns does not exist: %s.%s", dbName, collectionName)
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrNamespaceNotFound, msg, command)
+
+	case backends.ErrorCodeIs(err, backends.ErrorCodeCollectionValidationNotSupported):
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrNotImplemented,
+			"Document validation is not supported by this backend",
+			"validator",
+		)
+
+	default:
+		return nil, lazyerrors.Error(err)
+	}
 }
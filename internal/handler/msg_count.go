@@ -79,7 +79,7 @@ func (h *Handler) MsgCount(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMs
 	closer := iterator.NewMultiCloser(iter)
 	defer closer.Close()
 
-	iter = common.FilterIterator(iter, closer, params.Filter)
+	iter = common.FilterIterator(iter, closer, params.Filter, nil, nil)
 
 	iter = common.SkipIterator(iter, closer, params.Skip)
 
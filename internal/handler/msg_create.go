@@ -15,6 +15,7 @@
 package handler
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 
@@ -41,9 +42,6 @@ func (h *Handler) MsgCreate(connCtx context.Context, msg *wire.OpMsg) (*wire.OpM
 	unimplementedFields := []string{
 		"timeseries",
 		"expireAfterSeconds",
-		"validator",
-		"validationLevel",
-		"validationAction",
 		"viewOn",
 		"pipeline",
 		"collation",
@@ -77,6 +75,17 @@ func (h *Handler) MsgCreate(connCtx context.Context, msg *wire.OpMsg) (*wire.OpM
 		Name: collectionName,
 	}
 
+	validation, err := common.GetValidationOptions(document)
+	if err != nil {
+		return nil, err
+	}
+
+	if validation.Validator != nil {
+		params.Validator = validation.Validator
+		params.ValidationLevel = cmp.Or(validation.Level, common.ValidationLevelStrict)
+		params.ValidationAction = cmp.Or(validation.Action, common.ValidationActionError)
+	}
+
 	var capped bool
 	if v, _ := document.Get("capped"); v != nil {
 		capped, err = handlerparams.GetBoolOptionalParam("capped", v)
@@ -133,6 +142,13 @@ func (h *Handler) MsgCreate(connCtx context.Context, msg *wire.OpMsg) (*wire.OpM
 		msg := fmt.Sprintf("Collection %s.%s already exists.", dbName, collectionName)
 		return nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrNamespaceExists, msg, "create")
 
+	case backends.ErrorCodeIs(err, backends.ErrorCodeCollectionValidationNotSupported):
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrNotImplemented,
+			"Document validation is not supported by this backend",
+			"validator",
+		)
+
 	default:
 		return nil, lazyerrors.Error(err)
 	}
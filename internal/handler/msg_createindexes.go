@@ -18,8 +18,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/FerretDB/wire"
 
@@ -152,6 +154,27 @@ func (h *Handler) MsgCreateIndexes(connCtx context.Context, msg *wire.OpMsg) (*w
 
 	_, err = c.CreateIndexes(connCtx, &backends.CreateIndexesParams{Indexes: toCreate})
 	if err != nil {
+		if backends.ErrorCodeIs(err, backends.ErrorCodeIndexPartialFilterNotSupported) {
+			msg := `Index option "partialFilterExpression" is not supported by this backend`
+
+			if slices.ContainsFunc(toCreate, func(index backends.IndexInfo) bool {
+				return index.Unique && index.PartialFilterExpression != nil
+			}) {
+				msg = `Index option "partialFilterExpression" with that expression is not supported by this backend ` +
+					`for unique indexes`
+			}
+
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrNotImplemented, msg, command)
+		}
+
+		if backends.ErrorCodeIs(err, backends.ErrorCodeIndexCollationNotSupported) {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrNotImplemented,
+				`Index option "collation" is not supported by this backend`,
+				command,
+			)
+		}
+
 		return nil, lazyerrors.Error(err)
 	}
 
@@ -351,6 +374,21 @@ func processIndex(command string, indexDoc *types.Document) (*backends.IndexInfo
 				index.Unique = true
 			}
 
+		case "expireAfterSeconds":
+			if index.ExpireAfterSeconds, err = processIndexExpireAfterSeconds(command, indexDoc, &index); err != nil {
+				return nil, err
+			}
+
+		case "partialFilterExpression":
+			if index.PartialFilterExpression, err = processIndexPartialFilterExpression(command, indexDoc); err != nil {
+				return nil, err
+			}
+
+		case "collation":
+			if index.Collation, err = processIndexCollation(command, indexDoc); err != nil {
+				return nil, err
+			}
+
 		case "background":
 			// ignore deprecated options
 
@@ -358,9 +396,9 @@ func processIndex(command string, indexDoc *types.Document) (*backends.IndexInfo
 			// Ignore for now to make Meteor apps work.
 			// TODO https://github.com/FerretDB/FerretDB/issues/2448
 
-		case "partialFilterExpression", "expireAfterSeconds", "hidden", "storageEngine",
+		case "hidden", "storageEngine",
 			"weights", "default_language", "language_override", "textIndexVersion", "2dsphereIndexVersion",
-			"bits", "min", "max", "bucketSize", "collation", "wildcardProjection":
+			"bits", "min", "max", "bucketSize", "wildcardProjection":
 			return nil, handlererrors.NewCommandErrorMsgWithArgument(
 				handlererrors.ErrNotImplemented,
 				fmt.Sprintf("Index option %q is not implemented yet", opt),
@@ -377,6 +415,255 @@ func processIndex(command string, indexDoc *types.Document) (*backends.IndexInfo
 	}
 }
 
+// processIndexExpireAfterSeconds processes `expireAfterSeconds` option of the TTL index.
+func processIndexExpireAfterSeconds(command string, indexDoc *types.Document, index *backends.IndexInfo) (*int32, error) {
+	v := must.NotFail(indexDoc.Get("expireAfterSeconds"))
+
+	if len(index.Key) == 1 && index.Key[0].Field == "_id" {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrInvalidIndexSpecificationOption,
+			fmt.Sprintf(
+				"The field 'expireAfterSeconds' is not valid for an _id index specification. "+
+					"Specification: { key: %s, name: %q, expireAfterSeconds: %s, v: 2 }",
+				types.FormatAnyValue(must.NotFail(indexDoc.Get("key"))), index.Name, types.FormatAnyValue(v),
+			),
+			command,
+		)
+	}
+
+	if len(index.Key) > 1 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrCannotCreateIndex,
+			"TTL indexes are single-field indexes, compound indexes do not support TTL.",
+			command,
+		)
+	}
+
+	seconds, err := handlerparams.GetWholeNumberParam(v)
+	if err != nil {
+		if errors.Is(err, handlerparams.ErrUnexpectedType) {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrCannotCreateIndex,
+				fmt.Sprintf(
+					"TTL index 'expireAfterSeconds' option must be numeric, but received a type of '%s'.",
+					handlerparams.AliasFromType(v),
+				),
+				command,
+			)
+		}
+
+		// fractional and out of range values are rejected below
+		seconds = -1
+	}
+
+	if seconds < 0 || seconds > math.MaxInt32 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrCannotCreateIndex,
+			fmt.Sprintf(
+				"TTL index 'expireAfterSeconds' option must be within an acceptable range, try a lower number. "+
+					"Got %s",
+				types.FormatAnyValue(v),
+			),
+			command,
+		)
+	}
+
+	res := int32(seconds)
+
+	return &res, nil
+}
+
+// processIndexCollation processes `collation` option of the index.
+//
+// It returns nil for the simple collation.
+func processIndexCollation(command string, indexDoc *types.Document) (*types.Collation, error) {
+	v := must.NotFail(indexDoc.Get("collation"))
+
+	doc, ok := v.(*types.Document)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrTypeMismatch,
+			fmt.Sprintf("The field 'collation' must be an object, but got %s", handlerparams.AliasFromType(v)),
+			command,
+		)
+	}
+
+	return common.GetCollation(doc, command)
+}
+
+// processIndexPartialFilterExpression processes `partialFilterExpression` option of the partial index.
+//
+// Only field equality, $eq, $gt, $gte, $lt, $lte, `$exists: true` and top-level $and are supported.
+func processIndexPartialFilterExpression(command string, indexDoc *types.Document) (*types.Document, error) {
+	v := must.NotFail(indexDoc.Get("partialFilterExpression"))
+
+	filter, ok := v.(*types.Document)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrTypeMismatch,
+			fmt.Sprintf(
+				"The field 'partialFilterExpression' must be an object, but got %s",
+				handlerparams.AliasFromType(v),
+			),
+			command,
+		)
+	}
+
+	if err := validatePartialFilterExpression(command, filter, true); err != nil {
+		return nil, err
+	}
+
+	return filter, nil
+}
+
+// validatePartialFilterExpression checks that the given filter expression is supported by partial indexes.
+func validatePartialFilterExpression(command string, filter *types.Document, topLevel bool) error {
+	iter := filter.Iterator()
+	defer iter.Close()
+
+	for {
+		k, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			return nil
+		}
+
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		switch {
+		case k == "$and" && topLevel:
+			arr, ok := v.(*types.Array)
+			if !ok || arr.Len() == 0 {
+				return handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrBadValue,
+					"$and must be a nonempty array",
+					command,
+				)
+			}
+
+			for i := 0; i < arr.Len(); i++ {
+				doc, ok := must.NotFail(arr.Get(i)).(*types.Document)
+				if !ok {
+					return handlererrors.NewCommandErrorMsgWithArgument(
+						handlererrors.ErrBadValue,
+						"$and/$or/$nor entries need to be full objects",
+						command,
+					)
+				}
+
+				if err = validatePartialFilterExpression(command, doc, false); err != nil {
+					return err
+				}
+			}
+
+		case k == "$or", k == "$in":
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrNotImplemented,
+				fmt.Sprintf("Expression %s in partial index is not implemented yet", k),
+				command,
+			)
+
+		case strings.HasPrefix(k, "$"):
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrCannotCreateIndex,
+				fmt.Sprintf("Expression not supported in partial index: %s", k),
+				command,
+			)
+
+		default:
+			if err = validatePartialFilterField(command, v); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// validatePartialFilterField checks that the given field expression is supported by partial indexes.
+func validatePartialFilterField(command string, v any) error {
+	ops, ok := v.(*types.Document)
+	if !ok {
+		return validatePartialFilterValue(command, "$eq", v)
+	}
+
+	for _, op := range ops.Keys() {
+		opV := must.NotFail(ops.Get(op))
+
+		switch op {
+		case "$eq", "$gt", "$gte", "$lt", "$lte":
+			if err := validatePartialFilterValue(command, op, opV); err != nil {
+				return err
+			}
+
+		case "$exists":
+			switch opV {
+			case true:
+				// supported
+			case false:
+				return handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrCannotCreateIndex,
+					"Expression not supported in partial index: $exists: false",
+					command,
+				)
+			default:
+				return handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrNotImplemented,
+					fmt.Sprintf(
+						"Expression $exists: %s in partial index is not implemented yet",
+						types.FormatAnyValue(opV),
+					),
+					command,
+				)
+			}
+
+		case "$type", "$in":
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrNotImplemented,
+				fmt.Sprintf("Expression %s in partial index is not implemented yet", op),
+				command,
+			)
+
+		default:
+			if !strings.HasPrefix(op, "$") {
+				return handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrNotImplemented,
+					"Embedded document equality in partial index is not implemented yet",
+					command,
+				)
+			}
+
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrCannotCreateIndex,
+				fmt.Sprintf("Expression not supported in partial index: %s", op),
+				command,
+			)
+		}
+	}
+
+	return nil
+}
+
+// validatePartialFilterValue checks that the given operator value is supported by partial indexes.
+func validatePartialFilterValue(command, op string, v any) error {
+	switch v.(type) {
+	case float64, int32, int64, time.Time:
+		return nil
+	case string, bool, types.ObjectID:
+		if op == "$eq" {
+			return nil
+		}
+	}
+
+	return handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrNotImplemented,
+		fmt.Sprintf(
+			"Expression %s with %s value in partial index is not implemented yet",
+			op, handlerparams.AliasFromType(v),
+		),
+		command,
+	)
+}
+
 // processIndexKey processes the document containing the index key (set of "field-order" pairs).
 func processIndexKey(command string, keyDoc *types.Document) ([]backends.IndexKeyPair, error) {
 	res := make([]backends.IndexKeyPair, 0, keyDoc.Len())
@@ -511,7 +798,7 @@ func validateIndexesForCreation(command string, existing, toCreate []backends.In
 				return nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrIndexKeySpecsConflict, msg, command)
 			}
 
-			if newKey == otherKey {
+			if newKey == otherKey && samePartialFilter(newIdx, toCreate[j]) {
 				msg := fmt.Sprintf(
 					"Index already exists with a different name: %s", otherName,
 				)
@@ -524,6 +811,16 @@ func validateIndexesForCreation(command string, existing, toCreate []backends.In
 		for _, existingIdx := range existing {
 			existingKey := formatIndexKey(existingIdx.Key)
 
+			if newIdx.Name == existingIdx.Name && newKey == existingKey && !sameIndexOptions(newIdx, existingIdx) {
+				msg := fmt.Sprintf(
+					"An equivalent index already exists with the same name but different options."+
+						" Requested index: { key: { %s }, name: %q }, existing index: { key: { %s }, name: %q }",
+					newKey, newIdx.Name, existingKey, existingIdx.Name,
+				)
+
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrIndexOptionsConflict, msg, command)
+			}
+
 			if (newIdx.Name == existingIdx.Name && newKey == existingKey) || newKey == "_id: 1" {
 				// Fully identical indexes are ignored, no need to attempt to create them.
 				filteredToCreate = slices.Delete(filteredToCreate, i, i+1)
@@ -542,7 +839,7 @@ func validateIndexesForCreation(command string, existing, toCreate []backends.In
 				return nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrIndexKeySpecsConflict, msg, command)
 			}
 
-			if newKey == existingKey {
+			if newKey == existingKey && samePartialFilter(newIdx, existingIdx) {
 				msg := fmt.Sprintf("Index already exists with a different name: %s", existingIdx.Name)
 				return nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrIndexOptionsConflict, msg, command)
 			}
@@ -551,3 +848,27 @@ func validateIndexesForCreation(command string, existing, toCreate []backends.In
 
 	return filteredToCreate, nil
 }
+
+// sameIndexOptions returns true if the given indexes have the same options that affect indexed documents.
+func sameIndexOptions(a, b backends.IndexInfo) bool {
+	if (a.ExpireAfterSeconds == nil) != (b.ExpireAfterSeconds == nil) {
+		return false
+	}
+
+	if a.ExpireAfterSeconds != nil && *a.ExpireAfterSeconds != *b.ExpireAfterSeconds {
+		return false
+	}
+
+	return samePartialFilter(a, b)
+}
+
+// samePartialFilter returns true if the given indexes have the same partial filter expression, or both have none.
+//
+// Indexes with the same key and different partial filter expressions could coexist under different names.
+func samePartialFilter(a, b backends.IndexInfo) bool {
+	if a.PartialFilterExpression == nil || b.PartialFilterExpression == nil {
+		return a.PartialFilterExpression == b.PartialFilterExpression
+	}
+
+	return types.Identical(a.PartialFilterExpression, b.PartialFilterExpression)
+}
@@ -79,7 +79,7 @@ func (h *Handler) MsgDistinct(connCtx context.Context, msg *wire.OpMsg) (*wire.O
 
 	closer.Add(queryRes.Iter)
 
-	iter := common.FilterIterator(queryRes.Iter, closer, params.Filter)
+	iter := common.FilterIterator(queryRes.Iter, closer, params.Filter, nil, nil)
 
 	distinct, err := common.FilterDistinctValues(iter, params.Key)
 	if err != nil {
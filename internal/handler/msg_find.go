@@ -27,7 +27,9 @@ import (
 	"github.com/FerretDB/FerretDB/internal/backends"
 	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
 	"github.com/FerretDB/FerretDB/internal/clientconn/cursor"
+	"github.com/FerretDB/FerretDB/internal/clientconn/session"
 	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
 	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/iterator"
@@ -154,6 +156,7 @@ func (h *Handler) MsgFind(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg
 		DB:           params.DB,
 		Collection:   params.Collection,
 		Username:     username,
+		SessionID:    session.GetID(connCtx),
 		Type:         t,
 		ShowRecordID: params.ShowRecordId,
 	})
@@ -223,12 +226,13 @@ func (h *Handler) makeFindQueryParams(ctx context.Context, params *common.FindPa
 		}
 	}
 
-	if !h.DisablePushdown {
+	// backends compare strings as bytes, so filter can't be pushed down with non-simple collation
+	if !h.DisablePushdown && params.Collation == nil {
 		qp.Filter = params.Filter
 	}
 
-	if !h.EnableNestedPushdown && params.Filter != nil {
-		qp.Filter = params.Filter.DeepCopy()
+	if !h.EnableNestedPushdown && qp.Filter != nil {
+		qp.Filter = qp.Filter.DeepCopy()
 
 		for _, k := range qp.Filter.Keys() {
 			if !strings.ContainsRune(k, '.') {
@@ -298,9 +302,9 @@ func (h *Handler) makeFindQueryParams(ctx context.Context, params *common.FindPa
 func (h *Handler) makeFindIter(iter types.DocumentsIterator, closer *iterator.MultiCloser, params *common.FindParams) (types.DocumentsIterator, error) {
 	closer.Add(iter)
 
-	iter = common.FilterIterator(iter, closer, params.Filter)
+	iter = common.FilterIterator(iter, closer, params.Filter, params.Collation, aggregations.NewVariables(time.Now()))
 
-	iter, err := common.SortIterator(iter, closer, params.Sort)
+	iter, err := common.SortIterator(iter, closer, params.Sort, params.Collation)
 	if err != nil {
 		closer.Close()
 
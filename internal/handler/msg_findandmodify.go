@@ -24,6 +24,7 @@ import (
 
 	"github.com/FerretDB/FerretDB/internal/backends"
 	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
 	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/iterator"
@@ -139,9 +140,9 @@ func (h *Handler) findAndModifyDocument(ctx context.Context, params *common.Find
 
 	closer.Add(queryRes.Iter)
 
-	iter := common.FilterIterator(queryRes.Iter, closer, params.Query)
+	iter := common.FilterIterator(queryRes.Iter, closer, params.Query, nil, aggregations.NewVariables(time.Now()))
 
-	iter, err = common.SortIterator(iter, closer, params.Sort)
+	iter, err = common.SortIterator(iter, closer, params.Sort, nil)
 	if err != nil {
 		var pathErr *types.PathError
 		if errors.As(err, &pathErr) && pathErr.Code() == types.ErrPathElementEmpty {
@@ -190,8 +191,13 @@ func (h *Handler) findAndModifyDocument(ctx context.Context, params *common.Find
 		HasUpdateOperators: params.HasUpdateOperators,
 	}
 
+	v, err := h.documentValidator(ctx, db, params.Collection, params.BypassDocumentValidation)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
 	// TODO https://github.com/FerretDB/FerretDB/issues/2168
-	updateRes, err := common.UpdateDocument(ctx, c, "findAndModify", iter, update)
+	updateRes, err := common.UpdateDocument(ctx, c, "findAndModify", iter, update, v)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
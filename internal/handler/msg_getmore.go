@@ -312,7 +312,6 @@ func (h *Handler) awaitData(ctx context.Context, params *awaitDataParams) (resBa
 	defer closer.Close()
 
 	c := params.cursor
-	data := c.Data.(*findCursorData)
 
 	sleepDur := time.Duration(params.maxTimeMS) * time.Millisecond
 	ctx, cancel := context.WithTimeout(ctx, sleepDur)
@@ -336,16 +335,34 @@ func (h *Handler) awaitData(ctx context.Context, params *awaitDataParams) (resBa
 	}()
 
 	for {
-		var queryRes *backends.QueryResult
+		var iter types.DocumentsIterator
 
-		queryRes, err = data.coll.Query(ctx, data.qp)
-		if err != nil {
-			return
-		}
+		switch data := c.Data.(type) {
+		case *findCursorData:
+			var queryRes *backends.QueryResult
 
-		var iter types.DocumentsIterator
+			queryRes, err = data.coll.Query(ctx, data.qp)
+			if err != nil {
+				return
+			}
+
+			iter, err = h.makeFindIter(queryRes.Iter, closer, data.findParams)
+
+		case *changeStreamCursorData:
+			// change events are not fetched again, so iterator should be closed by the cursor
+			csCloser := iterator.NewMultiCloser()
+
+			if iter, err = processStagesDocuments(ctx, csCloser, &stagesDocumentsParams{stages: data.stages}); err != nil {
+				csCloser.Close()
+				break
+			}
+
+			iter = iterator.WithClose(iter, csCloser.Close)
+
+		default:
+			panic(fmt.Sprintf("unexpected cursor data %T", data))
+		}
 
-		iter, err = h.makeFindIter(queryRes.Iter, closer, data.findParams)
 		if err != nil {
 			return
 		}
@@ -23,8 +23,11 @@ import (
 
 	"github.com/FerretDB/wire"
 
+	"github.com/FerretDB/FerretDB/internal/clientconn/compression"
+	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
 	"github.com/FerretDB/FerretDB/internal/handler/common"
 	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/iterator"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
@@ -95,6 +98,11 @@ func (h *Handler) hello(ctx context.Context, doc *types.Document, tcpHost, name
 		}
 	}
 
+	compressors, err := negotiateCompression(ctx, doc)
+	if err != nil {
+		return nil, err
+	}
+
 	if name != "" {
 		// That does not work for TLS-only setups, IPv6 addresses, etc.
 		// The proper solution is to support `replSetInitiate` command.
@@ -121,11 +129,70 @@ func (h *Handler) hello(ctx context.Context, doc *types.Document, tcpHost, name
 		res.Set("saslSupportedMechs", resSupportedMechs)
 	}
 
+	if compressors != nil {
+		res.Set("compression", compressors)
+	}
+
 	res.Set("ok", float64(1))
 
 	return res, nil
 }
 
+// negotiateCompression negotiates compressors requested by the client in `compression` field
+// and stores them in the connection information.
+// It returns negotiated compressors or nil if the client did not request any supported compressor.
+func negotiateCompression(ctx context.Context, doc *types.Document) (*types.Array, error) {
+	v, _ := doc.Get("compression")
+	if v == nil {
+		return nil, nil
+	}
+
+	command := doc.Command()
+
+	arr, ok := v.(*types.Array)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrTypeMismatch,
+			fmt.Sprintf(
+				"BSON field '%s.compression' is the wrong type '%s', expected type 'array'",
+				command, handlerparams.AliasFromType(v),
+			),
+			command,
+		)
+	}
+
+	requested := make([]string, arr.Len())
+
+	for i := range requested {
+		elem := must.NotFail(arr.Get(i))
+
+		if requested[i], ok = elem.(string); !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrTypeMismatch,
+				fmt.Sprintf(
+					"BSON field '%s.compression.%d' is the wrong type '%s', expected type 'string'",
+					command, i, handlerparams.AliasFromType(elem),
+				),
+				command,
+			)
+		}
+	}
+
+	compressors := compression.Negotiate(requested)
+	conninfo.Get(ctx).SetCompressors(compressors)
+
+	if len(compressors) == 0 {
+		return nil, nil
+	}
+
+	res := types.MakeArray(len(compressors))
+	for _, c := range compressors {
+		res.Append(string(c))
+	}
+
+	return res, nil
+}
+
 // getUserSupportedMechs returns supported mechanisms for the given user.
 // If the user was not found, it returns nil.
 func (h *Handler) getUserSupportedMechs(ctx context.Context, db, username string) (*types.Array, error) {
@@ -79,6 +79,11 @@ func (h *Handler) MsgInsert(connCtx context.Context, msg *wire.OpMsg) (*wire.OpM
 		return nil, lazyerrors.Error(err)
 	}
 
+	v, err := h.documentValidator(connCtx, db, params.Collection, params.BypassDocumentValidation)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
 	docsIter := params.Docs.Iterator()
 	defer docsIter.Close()
 
@@ -112,8 +117,27 @@ func (h *Handler) MsgInsert(connCtx context.Context, msg *wire.OpMsg) (*wire.OpM
 
 			// TODO https://github.com/FerretDB/FerretDB/issues/3454
 			if err = doc.ValidateData(); err == nil {
-				docs = append(docs, doc)
-				docsIndexes = append(docsIndexes, i)
+				var ok bool
+				if ok, err = v.Match(doc); err != nil {
+					return nil, lazyerrors.Error(err)
+				}
+
+				if ok {
+					docs = append(docs, doc)
+					docsIndexes = append(docsIndexes, i)
+
+					continue
+				}
+
+				writeErrors = append(writeErrors, &mongo.WriteError{
+					Index:   i,
+					Code:    int(handlererrors.ErrDocumentValidationFailure),
+					Message: "Document failed validation",
+				})
+
+				if params.Ordered {
+					break
+				}
 
 				continue
 			}
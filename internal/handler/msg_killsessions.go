@@ -0,0 +1,56 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+
+	"github.com/FerretDB/wire"
+
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// MsgKillSessions implements `killSessions` command.
+//
+// The passed context is canceled when the client connection is closed.
+func (h *Handler) MsgKillSessions(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+	document, err := opMsgDocument(msg)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	ids, err := getSessionIDs(document)
+	if err != nil {
+		return nil, err
+	}
+
+	// sessions of other users are killed only if the user has the privilege
+	users := h.sessionsUsers(connCtx)
+
+	// empty array kills all sessions
+	if len(ids) == 0 {
+		h.endSessions(connCtx, h.sessions.RemoveAll(users))
+	} else {
+		h.endSessions(connCtx, h.sessions.Remove(ids, users))
+	}
+
+	return documentOpMsg(
+		must.NotFail(types.NewDocument(
+			"ok", float64(1),
+		)),
+	)
+}
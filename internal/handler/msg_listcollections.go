@@ -105,6 +105,12 @@ func (h *Handler) MsgListCollections(connCtx context.Context, msg *wire.OpMsg) (
 			options.Set("max", collection.CappedDocuments)
 		}
 
+		if collection.Validator != nil {
+			options.Set("validator", collection.Validator)
+			options.Set("validationLevel", collection.ValidationLevel)
+			options.Set("validationAction", collection.ValidationAction)
+		}
+
 		d.Set("options", options)
 
 		if collection.UUID != "" {
@@ -104,6 +104,18 @@ func (h *Handler) MsgListIndexes(connCtx context.Context, msg *wire.OpMsg) (*wir
 			indexDoc.Set("unique", index.Unique)
 		}
 
+		if index.ExpireAfterSeconds != nil {
+			indexDoc.Set("expireAfterSeconds", *index.ExpireAfterSeconds)
+		}
+
+		if index.PartialFilterExpression != nil {
+			indexDoc.Set("partialFilterExpression", index.PartialFilterExpression)
+		}
+
+		if index.Collation != nil {
+			indexDoc.Set("collation", common.MarshalCollation(index.Collation))
+		}
+
 		firstBatch.Append(indexDoc)
 	}
 
@@ -96,6 +96,11 @@ func (h *Handler) updateDocument(ctx context.Context, params *common.UpdateParam
 		return 0, 0, nil, lazyerrors.Error(err)
 	}
 
+	v, err := h.documentValidator(ctx, db, params.Collection, params.BypassDocumentValidation)
+	if err != nil {
+		return 0, 0, nil, lazyerrors.Error(err)
+	}
+
 	for _, u := range params.Updates {
 		c, err := db.Collection(params.Collection)
 		if err != nil {
@@ -108,7 +113,7 @@ func (h *Handler) updateDocument(ctx context.Context, params *common.UpdateParam
 		}
 
 		var qp backends.QueryParams
-		if !h.DisablePushdown {
+		if !h.DisablePushdown && u.Collation == nil {
 			qp.Filter = u.Filter
 		}
 
@@ -122,13 +127,13 @@ func (h *Handler) updateDocument(ctx context.Context, params *common.UpdateParam
 
 		closer.Add(res.Iter)
 
-		iter := common.FilterIterator(res.Iter, closer, u.Filter)
+		iter := common.FilterIterator(res.Iter, closer, u.Filter, u.Collation, nil)
 
 		if !u.Multi {
 			iter = common.LimitIterator(iter, closer, 1)
 		}
 
-		result, err := common.UpdateDocument(ctx, c, "update", iter, &u)
+		result, err := common.UpdateDocument(ctx, c, "update", iter, &u, v)
 		if err != nil {
 			return 0, 0, nil, lazyerrors.Error(err)
 		}
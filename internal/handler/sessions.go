@@ -0,0 +1,205 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/FerretDB/wire"
+	"github.com/google/uuid"
+
+	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/internal/clientconn/session"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+)
+
+// sessionsCleanupInterval is the interval of expired sessions cleanup.
+const sessionsCleanupInterval = time.Minute
+
+// sessionCtx returns a context with the logical session ID of the given command, if any.
+//
+// The session is created if needed, and its last use time is updated.
+func (h *Handler) sessionCtx(ctx context.Context, msg *wire.OpMsg) (context.Context, error) {
+	document, err := opMsgDocument(msg)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	v, _ := document.Get("lsid")
+	if v == nil {
+		return ctx, nil
+	}
+
+	lsid, ok := v.(*types.Document)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrTypeMismatch,
+			fmt.Sprintf(
+				"BSON field 'OperationSessionInfo.lsid' is the wrong type '%s', expected type 'object'",
+				handlerparams.AliasFromType(v),
+			),
+			"lsid",
+		)
+	}
+
+	id, err := getSessionID(lsid, "OperationSessionInfo.lsid")
+	if err != nil {
+		return nil, err
+	}
+
+	h.sessions.Use(id, conninfo.Get(ctx).Username())
+
+	return session.Ctx(ctx, id), nil
+}
+
+// getSessionID returns the session ID from the given session document ({id: UUID}).
+//
+// Field is used in error messages.
+func getSessionID(doc *types.Document, field string) (uuid.UUID, error) {
+	v, _ := doc.Get("id")
+	if v == nil {
+		return uuid.Nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrMissingField,
+			fmt.Sprintf("BSON field '%s.id' is missing but a required field", field),
+			field,
+		)
+	}
+
+	b, ok := v.(types.Binary)
+	if !ok || b.Subtype != types.BinaryUUID || len(b.B) != len(uuid.Nil) {
+		return uuid.Nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrInvalidUUID,
+			"uuid must be a 16-byte binary field with UUID (4) subtype",
+			field,
+		)
+	}
+
+	return uuid.UUID(b.B), nil
+}
+
+// getSessionIDs returns session IDs from the array of session documents
+// that is the value of the command field.
+func getSessionIDs(document *types.Document) ([]uuid.UUID, error) {
+	command := document.Command()
+
+	v, _ := document.Get(command)
+
+	arr, ok := v.(*types.Array)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrTypeMismatch,
+			fmt.Sprintf(
+				"BSON field '%s.%s' is the wrong type '%s', expected type 'array'",
+				command, command, handlerparams.AliasFromType(v),
+			),
+			command,
+		)
+	}
+
+	res := make([]uuid.UUID, 0, arr.Len())
+
+	iter := arr.Iterator()
+	defer iter.Close()
+
+	for {
+		i, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		field := fmt.Sprintf("%s.%s.%d", command, command, i)
+
+		doc, ok := v.(*types.Document)
+		if !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrTypeMismatch,
+				fmt.Sprintf(
+					"BSON field '%s' is the wrong type '%s', expected type 'object'",
+					field, handlerparams.AliasFromType(v),
+				),
+				command,
+			)
+		}
+
+		id, err := getSessionID(doc, field)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, id)
+	}
+
+	return res, nil
+}
+
+// endSessions closes cursors and aborts transactions of the given sessions
+// that were removed from the registry.
+func (h *Handler) endSessions(ctx context.Context, sessions []session.Session) {
+	for _, s := range sessions {
+		h.cursors.CloseAndRemoveSession(s.ID, s.Username)
+		h.abortSessionTransaction(ctx, s.ID, s.Username)
+	}
+}
+
+// allUsersSessions returns true if the user of the given connection could list and end sessions of all users.
+//
+// FerretDB does not support roles yet, so only the setup user has that privilege.
+// Like MongoDB without access control, unauthenticated connections have it too.
+func (h *Handler) allUsersSessions(ctx context.Context) bool {
+	username := conninfo.Get(ctx).Username()
+
+	return username == "" || (h.SetupUsername != "" && username == h.SetupUsername)
+}
+
+// sessionsUsers returns owners of sessions that could be ended by the user of the given connection:
+// nil (all users) if the user has the privilege, or that user only.
+func (h *Handler) sessionsUsers(ctx context.Context) []string {
+	if h.allUsersSessions(ctx) {
+		return nil
+	}
+
+	return []string{conninfo.Get(ctx).Username()}
+}
+
+// runSessionsCleanup ends expired sessions according to the cleanup interval.
+func (h *Handler) runSessionsCleanup() {
+	ticker := time.NewTicker(sessionsCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if ids := h.sessions.RemoveExpired(); len(ids) > 0 {
+				h.L.Debug("Expired sessions removed.", slog.Int("count", len(ids)))
+				h.endSessions(context.Background(), ids)
+			}
+
+		case <-h.sessionsCleanupStop:
+			return
+		}
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sjson
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+)
+
+// decimal128Type represents BSON 128-bit decimal floating point type.
+type decimal128Type types.Decimal128
+
+// sjsontype implements sjsontype interface.
+func (d *decimal128Type) sjsontype() {}
+
+// UnmarshalJSON implements json.Unmarshaler interface.
+func (d *decimal128Type) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		panic("null data")
+	}
+
+	r := bytes.NewReader(data)
+	dec := json.NewDecoder(r)
+
+	var o string
+	if err := dec.Decode(&o); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	if err := checkConsumed(dec, r); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	v, err := types.ParseDecimal128(o)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	*d = decimal128Type(v)
+
+	return nil
+}
+
+// MarshalJSON implements sjsontype interface.
+//
+// Finite values are encoded as JSON strings with all significant digits.
+// JSON numbers are not used, as some backends (like MySQL) parse them as doubles, losing precision.
+func (d *decimal128Type) MarshalJSON() ([]byte, error) {
+	v := types.Decimal128(*d)
+
+	if v.IsNaN() || v.IsInf(0) {
+		return nil, lazyerrors.Errorf("sjson: unsupported value: %s", v)
+	}
+
+	res, err := json.Marshal(v.String())
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return res, nil
+}
+
+// check interfaces
+var (
+	_ sjsontype = (*decimal128Type)(nil)
+)
@@ -0,0 +1,59 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sjson
+
+import (
+	"testing"
+
+	"github.com/AlekSi/pointer"
+
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+var decimal128TestCases = []testCase{{
+	name: "42",
+	v:    pointer.To(decimal128Type(types.NewDecimal128FromInt64(42))),
+	j:    `"42"`,
+}, {
+	name: "fraction",
+	v:    pointer.To(decimal128Type(must.NotFail(types.ParseDecimal128("-12.50")))),
+	j:    `"-12.50"`,
+}, {
+	name: "exponent",
+	v:    pointer.To(decimal128Type(must.NotFail(types.ParseDecimal128("1.2E+100")))),
+	j:    `"1.2E+100"`,
+}, {
+	name: "max digits",
+	v:    pointer.To(decimal128Type(must.NotFail(types.ParseDecimal128("1234567890123456789012345678901234")))),
+	j:    `"1234567890123456789012345678901234"`,
+}, {
+	name: "number",
+	j:    `42`,
+	jErr: `json: cannot unmarshal number into Go value of type string`,
+}, {
+	name: "EOF",
+	j:    `{`,
+	jErr: `unexpected EOF`,
+}}
+
+func TestDecimal128(t *testing.T) {
+	t.Parallel()
+	testJSON(t, decimal128TestCases, func() sjsontype { return new(decimal128Type) })
+}
+
+func BenchmarkDecimal128(b *testing.B) {
+	benchmark(b, decimal128TestCases, func() sjsontype { return new(decimal128Type) })
+}
@@ -0,0 +1,71 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sjson
+
+import (
+	"bytes"
+
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+)
+
+// keyValue is a JSON representation of MinKey and MaxKey values; their types are stored in the schema.
+var keyValue = []byte("1")
+
+// minKeyType represents BSON MinKey type.
+type minKeyType types.MinKeyType
+
+// sjsontype implements sjsontype interface.
+func (*minKeyType) sjsontype() {}
+
+// UnmarshalJSON implements json.Unmarshaler interface.
+func (*minKeyType) UnmarshalJSON(data []byte) error {
+	if !bytes.Equal(data, keyValue) {
+		return lazyerrors.Errorf("sjson: unexpected minKey value %s", data)
+	}
+
+	return nil
+}
+
+// MarshalJSON implements sjsontype interface.
+func (*minKeyType) MarshalJSON() ([]byte, error) {
+	return keyValue, nil
+}
+
+// maxKeyType represents BSON MaxKey type.
+type maxKeyType types.MaxKeyType
+
+// sjsontype implements sjsontype interface.
+func (*maxKeyType) sjsontype() {}
+
+// UnmarshalJSON implements json.Unmarshaler interface.
+func (*maxKeyType) UnmarshalJSON(data []byte) error {
+	if !bytes.Equal(data, keyValue) {
+		return lazyerrors.Errorf("sjson: unexpected maxKey value %s", data)
+	}
+
+	return nil
+}
+
+// MarshalJSON implements sjsontype interface.
+func (*maxKeyType) MarshalJSON() ([]byte, error) {
+	return keyValue, nil
+}
+
+// check interfaces
+var (
+	_ sjsontype = (*minKeyType)(nil)
+	_ sjsontype = (*maxKeyType)(nil)
+)
@@ -60,6 +60,9 @@ const (
 	elemTypeInt       elemType = "int"
 	elemTypeTimestamp elemType = "timestamp"
 	elemTypeLong      elemType = "long"
+	elemTypeDecimal   elemType = "decimal"
+	elemTypeMinKey    elemType = "minKey"
+	elemTypeMaxKey    elemType = "maxKey"
 )
 
 // GetTypeOfValue returns sjson type of supported value.
@@ -93,6 +96,12 @@ func GetTypeOfValue(v any) string {
 		return string(elemTypeTimestamp)
 	case int64:
 		return string(elemTypeLong)
+	case types.Decimal128:
+		return string(elemTypeDecimal)
+	case types.MinKeyType:
+		return string(elemTypeMinKey)
+	case types.MaxKeyType:
+		return string(elemTypeMaxKey)
 	}
 
 	panic(fmt.Sprintf("Unexpected type: %T", v))
@@ -139,6 +148,15 @@ var (
 	longSchema = &elem{
 		Type: elemTypeLong,
 	}
+	decimalSchema = &elem{
+		Type: elemTypeDecimal,
+	}
+	minKeySchema = &elem{
+		Type: elemTypeMinKey,
+	}
+	maxKeySchema = &elem{
+		Type: elemTypeMaxKey,
+	}
 )
 
 // marshalSchemaForDoc makes schema for the given document based on its data.
@@ -278,6 +296,15 @@ func marshalElemForSingleValue(value any) ([]byte, error) {
 	case int64:
 		buf.WriteString(`{"t":"long"}`)
 
+	case types.Decimal128:
+		buf.WriteString(`{"t":"decimal"}`)
+
+	case types.MinKeyType:
+		buf.WriteString(`{"t":"minKey"}`)
+
+	case types.MaxKeyType:
+		buf.WriteString(`{"t":"maxKey"}`)
+
 	default:
 		panic(fmt.Sprintf("sjson.marshalElemForSingleValue: unknown type %[1]T (value %[1]q)", val))
 	}
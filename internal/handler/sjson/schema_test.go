@@ -53,6 +53,9 @@ func TestSchemaMarshalUnmarshal(t *testing.T) {
 				"data", types.Binary{B: []byte("foo"), Subtype: types.BinaryGeneric},
 				"distance", 1.1,
 				"name", "foo",
+				"price", must.NotFail(types.ParseDecimal128("12.50")),
+				"min", types.MinKey,
+				"max", types.MaxKey,
 			)),
 			schema: schema{
 				Properties: map[string]*elem{
@@ -85,8 +88,11 @@ func TestSchemaMarshalUnmarshal(t *testing.T) {
 					"data":     binDataSchema(types.BinaryGeneric),
 					"distance": doubleSchema,
 					"name":     stringSchema,
+					"price":    decimalSchema,
+					"min":      minKeySchema,
+					"max":      maxKeySchema,
 				},
-				Keys: []string{"_id", "arr", "data", "distance", "name"},
+				Keys: []string{"_id", "arr", "data", "distance", "name", "price", "min", "max"},
 			},
 			json: `{
 				"p": {
@@ -109,9 +115,12 @@ func TestSchemaMarshalUnmarshal(t *testing.T) {
 					]},
 					"data": {"t": "binData", "s": 0},
 					"distance": {"t": "double"},
-					"name": {"t": "string"}
+					"name": {"t": "string"},
+					"price": {"t": "decimal"},
+					"min": {"t": "minKey"},
+					"max": {"t": "maxKey"}
 				},
-				"$k": ["_id", "arr", "data", "distance", "name"]
+				"$k": ["_id", "arr", "data", "distance", "name", "price", "min", "max"]
 			}`,
 		},
 		"Embedded": {
@@ -59,6 +59,9 @@
 //	int        int32            *sjson.int32Type      {"t":"int"}                            JSON number
 //	timestamp  types.Timestamp  *sjson.timestampType  {"t":"timestamp"}                      JSON number
 //	long       int64            *sjson.int64Type      {"t":"long"}                           JSON number
+//	decimal    types.Decimal128 *sjson.decimal128Type {"t":"decimal"}                        "<decimal as string>"
+//	minKey     types.MinKeyType *sjson.minKeyType     {"t":"minKey"}                         JSON number 1
+//	maxKey     types.MaxKeyType *sjson.maxKeyType     {"t":"maxKey"}                         JSON number 1
 //
 //nolint:lll // for readability
 package sjson
@@ -133,6 +136,12 @@ func fromSJSON(v sjsontype) any {
 		return types.Timestamp(*v)
 	case *int64Type:
 		return int64(*v)
+	case *decimal128Type:
+		return types.Decimal128(*v)
+	case *minKeyType:
+		return types.MinKey
+	case *maxKeyType:
+		return types.MaxKey
 	}
 
 	panic(fmt.Sprintf("not reached: %T", v)) // for sumtype to work
@@ -167,6 +176,12 @@ func toSJSON(v any) sjsontype {
 		return pointer.To(timestampType(v))
 	case int64:
 		return pointer.To(int64Type(v))
+	case types.Decimal128:
+		return pointer.To(decimal128Type(v))
+	case types.MinKeyType:
+		return pointer.To(minKeyType(v))
+	case types.MaxKeyType:
+		return pointer.To(maxKeyType(v))
 	}
 
 	panic(fmt.Sprintf("not reached: %T", v)) // for sumtype to work
@@ -309,6 +324,18 @@ func unmarshalSingleValue(data json.RawMessage, sch *elem) (any, error) {
 		var l int64Type
 		err = l.UnmarshalJSON(data)
 		res = &l
+	case elemTypeDecimal:
+		var d decimal128Type
+		err = d.UnmarshalJSON(data)
+		res = &d
+	case elemTypeMinKey:
+		var k minKeyType
+		err = k.UnmarshalJSON(data)
+		res = &k
+	case elemTypeMaxKey:
+		var k maxKeyType
+		err = k.UnmarshalJSON(data)
+		res = &k
 	default:
 		return nil, lazyerrors.Errorf("sjson.unmarshalSingleValue: unhandled type %q", sch.Type)
 	}
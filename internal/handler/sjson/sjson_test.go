@@ -211,6 +211,10 @@ func unmarshalJSON(v sjsontype, tc *testCase) error {
 		err = v.UnmarshalJSON([]byte(tc.j))
 	case *int64Type:
 		err = v.UnmarshalJSON([]byte(tc.j))
+	case *decimal128Type:
+		err = v.UnmarshalJSON([]byte(tc.j))
+	case *minKeyType, *maxKeyType:
+		panic("not implemented")
 	default:
 		panic(fmt.Sprintf("not reached: %T", v)) // for sumtype to work
 	}
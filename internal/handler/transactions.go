@@ -0,0 +1,495 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/FerretDB/wire"
+	"github.com/google/uuid"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/logging"
+)
+
+// transactionLifetime is the maximum lifetime of a transaction,
+// after which it is aborted (like MongoDB's transactionLifetimeLimitSeconds).
+const transactionLifetime = 60 * time.Second
+
+// transientTransactionError is the error label for errors after which the whole transaction could be retried.
+const transientTransactionError = "TransientTransactionError"
+
+// transactionCommands contains names of commands that could be a part of multi-document transaction.
+var transactionCommands = map[string]struct{}{
+	"aggregate":     {},
+	"count":         {},
+	"delete":        {},
+	"distinct":      {},
+	"find":          {},
+	"findAndModify": {},
+	"findandmodify": {},
+	"getMore":       {},
+	"insert":        {},
+	"killCursors":   {},
+	"update":        {},
+}
+
+// transactionNeutralCommands contains names of commands that are allowed to have transaction parameters,
+// but are not a part of multi-document transaction.
+// All other commands with those parameters fail.
+var transactionNeutralCommands = map[string]struct{}{
+	"abortTransaction":  {},
+	"buildInfo":         {},
+	"buildinfo":         {},
+	"commitTransaction": {},
+	"connectionStatus":  {},
+	"hello":             {},
+	"isMaster":          {},
+	"ismaster":          {},
+}
+
+// transactionState represents the state of a transaction.
+type transactionState int
+
+const (
+	transactionInProgress transactionState = iota
+	transactionCommitted
+	transactionAborted
+)
+
+// transaction represents the last multi-document transaction of a logical session.
+type transaction struct {
+	// m serializes commands of the transaction and protects its state:
+	// backend transactions (like pgx.Tx) can't be used concurrently.
+	//
+	// It should not be locked while h.txns.m is held, except for a new transaction that is not visible yet.
+	m sync.Mutex
+
+	t         backends.Transaction
+	timer     *time.Timer
+	txnNumber int64
+	state     transactionState
+}
+
+// transactionKey identifies the transaction by logical session ID and the user that runs it,
+// so one user can't use transactions of another user.
+type transactionKey struct {
+	sessionID string
+	username  string
+}
+
+// transactions stores the last transaction of each logical session.
+type transactions struct {
+	m    sync.Mutex
+	txns map[transactionKey]*transaction
+}
+
+// newTransactions creates a new transactions store.
+func newTransactions() *transactions {
+	return &transactions{
+		txns: map[transactionKey]*transaction{},
+	}
+}
+
+// transactionHandler wraps the handler of a command that could be a part of multi-document transaction.
+//
+// Such commands are run with the backend transaction in the context, one at a time.
+// Like in MongoDB, if the command fails or returns write errors, the whole transaction is aborted.
+func (h *Handler) transactionHandler(handler func(context.Context, *wire.OpMsg) (*wire.OpMsg, error)) func(context.Context, *wire.OpMsg) (*wire.OpMsg, error) { //nolint:lll // for readability
+	return func(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+		document, err := opMsgDocument(msg)
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		txn, err := h.lockTransaction(ctx, document)
+		if err != nil {
+			return nil, err
+		}
+
+		if txn == nil {
+			return handler(ctx, msg)
+		}
+
+		defer txn.m.Unlock()
+
+		resp, err := handler(backends.WithTransaction(ctx, txn.t), msg)
+		if err == nil {
+			var res *types.Document
+
+			if res, err = opMsgDocument(resp); err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+
+			if !res.Has("writeErrors") {
+				return resp, nil
+			}
+		}
+
+		// failed statements leave some backend transactions (like PostgreSQL ones) unusable
+		h.abortTransaction(context.WithoutCancel(ctx), txn)
+
+		return resp, err
+	}
+}
+
+// noTransactionHandler wraps the handler of a command that can't be a part of multi-document transaction.
+//
+// Like in MongoDB, such commands fail if they have transaction parameters
+// instead of being silently run outside of the transaction.
+func (h *Handler) noTransactionHandler(command string, handler func(context.Context, *wire.OpMsg) (*wire.OpMsg, error)) func(context.Context, *wire.OpMsg) (*wire.OpMsg, error) { //nolint:lll // for readability
+	return func(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+		document, err := opMsgDocument(msg)
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		params, err := common.GetTransactionParams(document)
+		if err != nil {
+			return nil, err
+		}
+
+		if params != nil {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrOperationNotSupportedInTransaction,
+				fmt.Sprintf("Cannot run '%s' in a multi-document transaction.", command),
+				command,
+			)
+		}
+
+		return handler(ctx, msg)
+	}
+}
+
+// lockTransaction returns the locked transaction for the given command document.
+//
+// If the command is not a part of multi-document transaction, nil is returned.
+// If the command starts a new transaction, the backend transaction is started.
+// The caller should unlock the returned transaction after running the command.
+func (h *Handler) lockTransaction(ctx context.Context, document *types.Document) (*transaction, error) {
+	params, err := common.GetTransactionParams(document)
+	if err != nil {
+		return nil, err
+	}
+
+	if params == nil {
+		return nil, nil
+	}
+
+	key := transactionKey{
+		sessionID: string(params.SessionID.B),
+		username:  conninfo.Get(ctx).Username(),
+	}
+
+	if params.StartTransaction {
+		return h.startTransaction(ctx, key, params.TxnNumber)
+	}
+
+	h.txns.m.Lock()
+	txn := h.txns.txns[key]
+	h.txns.m.Unlock()
+
+	if txn == nil || txn.txnNumber != params.TxnNumber {
+		return nil, handlererrors.NewCommandErrorMsgWithLabels(
+			handlererrors.ErrNoSuchTransaction,
+			fmt.Sprintf(
+				"Given transaction number %d does not match any in-progress transactions.",
+				params.TxnNumber,
+			),
+			transientTransactionError,
+		)
+	}
+
+	txn.m.Lock()
+
+	switch txn.state {
+	case transactionInProgress:
+		return txn, nil
+
+	case transactionCommitted:
+		txn.m.Unlock()
+
+		return nil, handlererrors.NewCommandErrorMsg(
+			handlererrors.ErrTransactionCommitted,
+			fmt.Sprintf("Transaction %d has been committed.", params.TxnNumber),
+		)
+
+	default:
+		txn.m.Unlock()
+
+		return nil, handlererrors.NewCommandErrorMsgWithLabels(
+			handlererrors.ErrNoSuchTransaction,
+			fmt.Sprintf("Transaction with { txnNumber: %d } has been aborted.", params.TxnNumber),
+			transientTransactionError,
+		)
+	}
+}
+
+// startTransaction starts a new locked transaction with the given key and number.
+//
+// The previous transaction of the same session is aborted.
+func (h *Handler) startTransaction(ctx context.Context, key transactionKey, txnNumber int64) (*transaction, error) {
+	// start the backend transaction first to not block other sessions
+	t, err := h.b.BeginTransaction(ctx, nil)
+	if err != nil {
+		if backends.ErrorCodeIs(err, backends.ErrorCodeTransactionsNotSupported) {
+			return nil, handlererrors.NewCommandErrorMsg(
+				handlererrors.ErrIllegalOperation,
+				"Transaction numbers are only allowed on a replica set member or mongos",
+			)
+		}
+
+		return nil, lazyerrors.Error(err)
+	}
+
+	h.txns.m.Lock()
+
+	if prev := h.txns.txns[key]; prev != nil && prev.txnNumber >= txnNumber {
+		h.txns.m.Unlock()
+
+		if err = t.Abort(ctx); err != nil {
+			h.L.WarnContext(ctx, "Failed to abort transaction", logging.Error(err))
+		}
+
+		if prev.txnNumber == txnNumber {
+			return nil, handlererrors.NewCommandErrorMsg(
+				handlererrors.ErrConflictingOperationInProgress,
+				fmt.Sprintf(
+					"Cannot start transaction %d on session because it has already been started.",
+					txnNumber,
+				),
+			)
+		}
+
+		return nil, handlererrors.NewCommandErrorMsg(
+			handlererrors.ErrTransactionTooOld,
+			fmt.Sprintf(
+				"Cannot start transaction %d on session because a newer transaction %d has already started.",
+				txnNumber, prev.txnNumber,
+			),
+		)
+	}
+
+	txn := &transaction{
+		t:         t,
+		txnNumber: txnNumber,
+	}
+
+	// nobody else could see it yet
+	txn.m.Lock()
+
+	txn.timer = time.AfterFunc(transactionLifetime, func() {
+		h.txns.m.Lock()
+
+		if h.txns.txns[key] == txn {
+			delete(h.txns.txns, key)
+		}
+
+		h.txns.m.Unlock()
+
+		txn.m.Lock()
+		defer txn.m.Unlock()
+
+		if txn.state == transactionInProgress {
+			h.L.Warn("Transaction expired", slog.Int64("txnNumber", txn.txnNumber))
+		}
+
+		h.abortTransaction(context.Background(), txn)
+	})
+
+	prev := h.txns.txns[key]
+	h.txns.txns[key] = txn
+
+	h.txns.m.Unlock()
+
+	// starting a new transaction aborts the previous one
+	if prev != nil {
+		prev.timer.Stop()
+
+		prev.m.Lock()
+		h.abortTransaction(ctx, prev)
+		prev.m.Unlock()
+	}
+
+	return txn, nil
+}
+
+// endTransaction commits or aborts the transaction for the given command document.
+//
+// It is used by `commitTransaction` and `abortTransaction` commands.
+func (h *Handler) endTransaction(ctx context.Context, document *types.Document, commit bool) error {
+	command := document.Command()
+
+	dbName, err := common.GetRequiredParam[string](document, "$db")
+	if err != nil {
+		return err
+	}
+
+	if dbName != "admin" {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrUnauthorized,
+			fmt.Sprintf("%s may only be run against the admin database.", command),
+			command,
+		)
+	}
+
+	params, err := common.GetTransactionParams(document)
+	if err != nil {
+		return err
+	}
+
+	if params == nil {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrInvalidOptions,
+			fmt.Sprintf("%s must be run within a transaction", command),
+			command,
+		)
+	}
+
+	key := transactionKey{
+		sessionID: string(params.SessionID.B),
+		username:  conninfo.Get(ctx).Username(),
+	}
+
+	h.txns.m.Lock()
+	txn := h.txns.txns[key]
+	h.txns.m.Unlock()
+
+	noSuchTransaction := handlererrors.NewCommandErrorMsgWithLabels(
+		handlererrors.ErrNoSuchTransaction,
+		fmt.Sprintf("Transaction with { txnNumber: %d } has been aborted.", params.TxnNumber),
+		transientTransactionError,
+	)
+
+	if txn == nil || txn.txnNumber != params.TxnNumber {
+		return noSuchTransaction
+	}
+
+	txn.m.Lock()
+	defer txn.m.Unlock()
+
+	switch txn.state {
+	case transactionInProgress:
+	case transactionCommitted:
+		// commit is idempotent
+		if commit {
+			return nil
+		}
+
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrTransactionCommitted,
+			"Cannot abort transaction after it has been committed.",
+			command,
+		)
+
+	default:
+		return noSuchTransaction
+	}
+
+	if !commit {
+		h.abortTransaction(ctx, txn)
+		return nil
+	}
+
+	if err = h.commitTransaction(ctx, txn); err != nil {
+		return handlererrors.NewCommandErrorMsgWithLabels(
+			handlererrors.ErrNoSuchTransaction,
+			fmt.Sprintf("Transaction with { txnNumber: %d } has been aborted: %s", params.TxnNumber, err),
+			transientTransactionError,
+		)
+	}
+
+	return nil
+}
+
+// abortTransaction aborts the given transaction if it is still in progress.
+//
+// txn.m should be locked.
+func (h *Handler) abortTransaction(ctx context.Context, txn *transaction) {
+	if txn.state != transactionInProgress {
+		return
+	}
+
+	txn.state = transactionAborted
+
+	if err := txn.t.Abort(ctx); err != nil {
+		h.L.WarnContext(ctx, "Failed to abort transaction", logging.Error(err))
+	}
+}
+
+// commitTransaction commits the given transaction that is still in progress.
+//
+// If commit fails, the transaction is considered aborted.
+//
+// txn.m should be locked.
+func (h *Handler) commitTransaction(ctx context.Context, txn *transaction) error {
+	txn.state = transactionAborted
+
+	if err := txn.t.Commit(ctx); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	txn.state = transactionCommitted
+
+	return nil
+}
+
+// abortSessionTransaction aborts the transaction of the given logical session of the given user
+// if it is still in progress.
+func (h *Handler) abortSessionTransaction(ctx context.Context, id uuid.UUID, username string) {
+	h.removeTransactions(ctx, func(key transactionKey) bool {
+		return key.sessionID == string(id[:]) && key.username == username
+	})
+}
+
+// abortAllTransactions aborts all transactions that are still in progress.
+func (h *Handler) abortAllTransactions() {
+	h.removeTransactions(context.Background(), func(transactionKey) bool {
+		return true
+	})
+}
+
+// removeTransactions removes transactions with keys matching the given function, and aborts them.
+func (h *Handler) removeTransactions(ctx context.Context, match func(transactionKey) bool) {
+	var txns []*transaction
+
+	h.txns.m.Lock()
+
+	for key, txn := range h.txns.txns {
+		if match(key) {
+			txns = append(txns, txn)
+			delete(h.txns.txns, key)
+		}
+	}
+
+	h.txns.m.Unlock()
+
+	for _, txn := range txns {
+		txn.timer.Stop()
+
+		txn.m.Lock()
+		h.abortTransaction(ctx, txn)
+		txn.m.Unlock()
+	}
+}
@@ -0,0 +1,265 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/logging"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// runTTLCleanup deletes expired documents of TTL indexes according to the given interval.
+func (h *Handler) runTTLCleanup() {
+	if h.TTLCleanupInterval <= 0 {
+		h.L.Info("TTL indexes cleanup disabled.")
+		return
+	}
+
+	h.L.Info("TTL indexes cleanup enabled.", slog.Duration("interval", h.TTLCleanupInterval))
+
+	ticker := time.NewTicker(h.TTLCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := h.cleanupAllTTLIndexes(context.Background()); err != nil {
+				h.L.Error("Failed to cleanup TTL indexes.", logging.Error(err))
+			}
+
+		case <-h.ttlCleanupStop:
+			h.L.Info("TTL indexes cleanup stopped.")
+			return
+		}
+	}
+}
+
+// cleanupAllTTLIndexes deletes expired documents from all collections with TTL indexes.
+func (h *Handler) cleanupAllTTLIndexes(ctx context.Context) error {
+	ctx, span := otel.Tracer("").Start(ctx, "HandlerCleanupAllTTLIndexes")
+	h.L.DebugContext(ctx, "cleanupAllTTLIndexes: started")
+
+	start := time.Now()
+	defer func() {
+		span.End()
+		h.L.DebugContext(ctx, "cleanupAllTTLIndexes: finished", slog.Duration("duration", time.Since(start)))
+	}()
+
+	connInfo := conninfo.New()
+	connInfo.SetBypassBackendAuth()
+	ctx = conninfo.Ctx(ctx, connInfo)
+
+	dbList, err := h.b.ListDatabases(ctx, nil)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	for _, dbInfo := range dbList.Databases {
+		db, err := h.b.Database(dbInfo.Name)
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		cList, err := db.ListCollections(ctx, nil)
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		for _, cInfo := range cList.Collections {
+			deleted, err := h.cleanupTTLIndexes(ctx, db, cInfo.Name)
+			if err != nil {
+				if backends.ErrorCodeIs(err, backends.ErrorCodeCollectionDoesNotExist) ||
+					backends.ErrorCodeIs(err, backends.ErrorCodeDatabaseDoesNotExist) {
+					continue
+				}
+
+				return lazyerrors.Error(err)
+			}
+
+			if deleted == 0 {
+				continue
+			}
+
+			h.L.InfoContext(
+				ctx,
+				"Expired documents deleted",
+				slog.String("db", dbInfo.Name),
+				slog.String("collection", cInfo.Name),
+				slog.Int("deleted", int(deleted)),
+			)
+
+			h.cleanupTTLDocs.WithLabelValues(dbInfo.Name, cInfo.Name).Add(float64(deleted))
+		}
+	}
+
+	return nil
+}
+
+// cleanupTTLIndexes deletes expired documents of all TTL indexes of the given collection.
+//
+// It returns the number of deleted documents.
+func (h *Handler) cleanupTTLIndexes(ctx context.Context, db backends.Database, cName string) (int32, error) {
+	coll, err := db.Collection(cName)
+	if err != nil {
+		return 0, lazyerrors.Error(err)
+	}
+
+	indexes, err := coll.ListIndexes(ctx, nil)
+	if err != nil {
+		return 0, lazyerrors.Error(err)
+	}
+
+	var ttlIndexes []backends.IndexInfo
+
+	for _, index := range indexes.Indexes {
+		if index.ExpireAfterSeconds != nil {
+			ttlIndexes = append(ttlIndexes, index)
+		}
+	}
+
+	now := time.Now()
+
+	var deleted int32
+
+	for _, index := range ttlIndexes {
+		n, err := cleanupTTLIndex(ctx, coll, &index, now)
+		if err != nil {
+			return 0, lazyerrors.Error(err)
+		}
+
+		deleted += n
+	}
+
+	return deleted, nil
+}
+
+// cleanupTTLIndex deletes expired documents of the given TTL index.
+//
+// Only documents with dates before the expiration time are queried;
+// the backend may return more documents if that filter is not pushed down.
+//
+// It returns the number of deleted documents.
+func cleanupTTLIndex(ctx context.Context, coll backends.Collection, index *backends.IndexInfo, now time.Time) (int32, error) {
+	expireAfter := time.Duration(*index.ExpireAfterSeconds) * time.Second
+
+	// arrays are returned too, as their earliest date is used
+	filter := must.NotFail(types.NewDocument(
+		index.Key[0].Field, must.NotFail(types.NewDocument("$lt", now.Add(-expireAfter))),
+	))
+
+	res, err := coll.Query(ctx, &backends.QueryParams{Filter: filter})
+	if err != nil {
+		return 0, lazyerrors.Error(err)
+	}
+
+	defer res.Iter.Close()
+
+	var ids []any
+
+	for {
+		var doc *types.Document
+
+		_, doc, err = res.Iter.Next()
+		if err != nil {
+			if errors.Is(err, iterator.ErrIteratorDone) {
+				break
+			}
+
+			return 0, lazyerrors.Error(err)
+		}
+
+		// partial TTL indexes only expire documents matching the filter expression
+		if index.PartialFilterExpression != nil {
+			var matches bool
+
+			if matches, err = common.FilterDocument(doc, index.PartialFilterExpression); err != nil {
+				return 0, lazyerrors.Error(err)
+			}
+
+			if !matches {
+				continue
+			}
+		}
+
+		if isExpired(doc, index, now) {
+			ids = append(ids, must.NotFail(doc.Get("_id")))
+		}
+	}
+
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	deleteRes, err := coll.DeleteAll(ctx, &backends.DeleteAllParams{IDs: ids})
+	if err != nil {
+		return 0, lazyerrors.Error(err)
+	}
+
+	return deleteRes.Deleted, nil
+}
+
+// isExpired returns true if the given document is expired according to the given TTL index.
+//
+// Like in MongoDB, the indexed field should contain a date or an array of dates;
+// in the latter case, the earliest date is used.
+// Documents without such values never expire.
+func isExpired(doc *types.Document, index *backends.IndexInfo, now time.Time) bool {
+	path, err := types.NewPathFromString(index.Key[0].Field)
+	if err != nil {
+		return false
+	}
+
+	v, err := doc.GetByPath(path)
+	if err != nil {
+		return false
+	}
+
+	var earliest time.Time
+
+	switch v := v.(type) {
+	case time.Time:
+		earliest = v
+
+	case *types.Array:
+		for i := 0; i < v.Len(); i++ {
+			if t, ok := must.NotFail(v.Get(i)).(time.Time); ok && (earliest.IsZero() || t.Before(earliest)) {
+				earliest = t
+			}
+		}
+
+		if earliest.IsZero() {
+			return false
+		}
+
+	default:
+		return false
+	}
+
+	expireAfter := time.Duration(*index.ExpireAfterSeconds) * time.Second
+
+	return !earliest.Add(expireAfter).After(now)
+}
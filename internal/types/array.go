@@ -150,6 +150,11 @@ func (a *Array) RemoveByPath(path Path) {
 
 // Min returns the minimum value from the array.
 func (a *Array) Min() any {
+	return a.min(nil)
+}
+
+// min returns the minimum value from the array using the given collation for strings.
+func (a *Array) min(c *Collation) any {
 	if a == nil || a.Len() == 0 {
 		panic("cannot get Min value; array is nil or empty")
 	}
@@ -157,7 +162,7 @@ func (a *Array) Min() any {
 	min := must.NotFail(a.Get(0))
 	for i := 1; i < a.Len(); i++ {
 		value := must.NotFail(a.Get(i))
-		if CompareOrder(min, value, Ascending) == Greater {
+		if c.CompareOrder(min, value, Ascending) == Greater {
 			min = value
 		}
 	}
@@ -167,6 +172,11 @@ func (a *Array) Min() any {
 
 // Max returns the maximum value from the array.
 func (a *Array) Max() any {
+	return a.max(nil)
+}
+
+// max returns the maximum value from the array using the given collation for strings.
+func (a *Array) max(c *Collation) any {
 	if a == nil || a.Len() == 0 {
 		panic("cannot get Max value; array is nil or empty")
 	}
@@ -174,7 +184,7 @@ func (a *Array) Max() any {
 	max := must.NotFail(a.Get(0))
 	for i := 1; i < a.Len(); i++ {
 		value := must.NotFail(a.Get(i))
-		if CompareOrder(max, value, Ascending) == Less {
+		if c.CompareOrder(max, value, Ascending) == Less {
 			max = value
 		}
 	}
@@ -230,7 +240,7 @@ func (a *Array) Contains(filterValue any) bool {
 			case *Document, *Array:
 				// we need elem and filterValue to be exactly equal, so we do nothing here
 			default:
-				if compareScalars(elem, filterValue) == Equal {
+				if compareScalars(elem, filterValue, nil) == Equal {
 					return true
 				}
 			}
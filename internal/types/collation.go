@@ -0,0 +1,107 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"fmt"
+	"sync"
+
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+)
+
+// SimpleLocale is the locale of the default collation that compares strings as bytes.
+const SimpleLocale = "simple"
+
+// Collation represents language-specific rules for string comparison.
+//
+// Nil *Collation is the simple collation that compares strings as bytes;
+// all its methods could be called on nil receiver.
+type Collation struct {
+	locale   string
+	strength int
+
+	m        sync.Mutex // protects collator that is not safe for concurrent use
+	collator *collate.Collator
+}
+
+// NewCollation returns a new collation for the given locale and comparison strength.
+//
+// Strength 1 compares base characters only, 2 also compares diacritics, 3 also compares case.
+// It returns nil for the simple locale.
+func NewCollation(locale string, strength int) (*Collation, error) {
+	if locale == SimpleLocale {
+		return nil, nil
+	}
+
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return nil, fmt.Errorf("types.NewCollation: invalid locale %q: %w", locale, err)
+	}
+
+	if _, _, confidence := language.NewMatcher(collate.Supported()).Match(tag); confidence == language.No {
+		return nil, fmt.Errorf("types.NewCollation: unsupported locale %q", locale)
+	}
+
+	var opts []collate.Option
+
+	switch strength {
+	case 1:
+		opts = append(opts, collate.IgnoreDiacritics, collate.IgnoreCase, collate.IgnoreWidth)
+	case 2:
+		opts = append(opts, collate.IgnoreCase, collate.IgnoreWidth)
+	case 3:
+		// tertiary strength is the default
+	default:
+		return nil, fmt.Errorf("types.NewCollation: unsupported strength %d", strength)
+	}
+
+	return &Collation{
+		locale:   locale,
+		strength: strength,
+		collator: collate.New(tag, opts...),
+	}, nil
+}
+
+// Locale returns collation's locale.
+func (c *Collation) Locale() string {
+	if c == nil {
+		return SimpleLocale
+	}
+
+	return c.locale
+}
+
+// Strength returns collation's comparison strength.
+func (c *Collation) Strength() int {
+	if c == nil {
+		return 3
+	}
+
+	return c.strength
+}
+
+// Compare is like [Compare], but uses the collation for strings.
+func (c *Collation) Compare(docValue, filterValue any) CompareResult {
+	return compare(docValue, filterValue, c)
+}
+
+// compareStrings compares strings according to the collation.
+func (c *Collation) compareStrings(a, b string) CompareResult {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	return CompareResult(c.collator.CompareString(a, b))
+}
@@ -0,0 +1,115 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+func TestCollation(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		strength int
+		a        any
+		b        any
+		expected CompareResult
+	}{
+		"Strength1Diacritics": {
+			strength: 1,
+			a:        "cafe",
+			b:        "Café",
+			expected: Equal,
+		},
+		"Strength2Case": {
+			strength: 2,
+			a:        "cafe",
+			b:        "CAFE",
+			expected: Equal,
+		},
+		"Strength2Diacritics": {
+			strength: 2,
+			a:        "cafe",
+			b:        "café",
+			expected: Less,
+		},
+		"Strength3Case": {
+			strength: 3,
+			a:        "cafe",
+			b:        "Cafe",
+			expected: Less,
+		},
+		"Strength3LocaleOrder": {
+			strength: 3,
+			a:        "b",
+			b:        "C",
+			expected: Less,
+		},
+		"Array": {
+			strength: 2,
+			a:        must.NotFail(NewArray("FOO", "bar")),
+			b:        "foo",
+			expected: Equal,
+		},
+		"Document": {
+			strength: 2,
+			a:        must.NotFail(NewDocument("foo", "BAR")),
+			b:        must.NotFail(NewDocument("foo", "bar")),
+			expected: Equal,
+		},
+		"OtherTypes": {
+			strength: 2,
+			a:        "1",
+			b:        int32(1),
+			expected: Greater,
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			c, err := NewCollation("en", tc.strength)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, c.Compare(tc.a, tc.b))
+		})
+	}
+
+	t.Run("Simple", func(t *testing.T) {
+		t.Parallel()
+
+		c, err := NewCollation(SimpleLocale, 3)
+		require.NoError(t, err)
+		require.Nil(t, c)
+
+		assert.Equal(t, Less, c.Compare("C", "b"))
+		assert.Equal(t, SimpleLocale, c.Locale())
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewCollation("not a locale", 3)
+		assert.Error(t, err)
+
+		_, err = NewCollation("en", 4)
+		assert.Error(t, err)
+	})
+}
@@ -16,6 +16,7 @@ package types
 
 import (
 	"bytes"
+	"fmt"
 	"math"
 	"math/big"
 	"time"
@@ -45,20 +46,28 @@ const (
 //
 // Compare and contrast with test helpers in testutil package.
 func Compare(docValue, filterValue any) CompareResult {
+	return compare(docValue, filterValue, nil)
+}
+
+// compare compares any BSON values in the same way as MongoDB does it for filtering,
+// using the given collation for strings.
+//
+// Nil collation compares strings as bytes.
+func compare(docValue, filterValue any, c *Collation) CompareResult {
 	assertType(docValue)
 	assertType(filterValue)
 
 	switch docValue := docValue.(type) {
 	case *Document:
 		if filterDoc, ok := filterValue.(*Document); ok {
-			return compareDocuments(docValue, filterDoc)
+			return compareDocuments(docValue, filterDoc, c)
 		}
 
 		return compareTypeOrder(docValue, filterValue)
 	case *Array:
-		return compareArray(docValue, filterValue)
+		return compareArray(docValue, filterValue, c)
 	default:
-		return compareScalars(docValue, filterValue)
+		return compareScalars(docValue, filterValue, c)
 	}
 }
 
@@ -72,23 +81,23 @@ func CompareForAggregation(docValue, filterValue any) CompareResult {
 	switch docValue := docValue.(type) {
 	case *Document:
 		if filterDoc, ok := filterValue.(*Document); ok {
-			return compareDocuments(docValue, filterDoc)
+			return compareDocuments(docValue, filterDoc, nil)
 		}
 
 		return compareTypeOrder(docValue, filterValue)
 	case *Array:
 		if filterDoc, ok := filterValue.(*Array); ok {
-			return compareArrays(docValue, filterDoc)
+			return compareArrays(docValue, filterDoc, nil)
 		}
 
 		return compareTypeOrder(docValue, filterValue)
 	default:
-		return compareScalars(docValue, filterValue)
+		return compareScalars(docValue, filterValue, nil)
 	}
 }
 
-// compareScalars compares BSON scalar values.
-func compareScalars(v1, v2 any) CompareResult {
+// compareScalars compares BSON scalar values using the given collation for strings.
+func compareScalars(v1, v2 any, c *Collation) CompareResult {
 	assertType(v1)
 	assertType(v2)
 
@@ -108,6 +117,8 @@ func compareScalars(v1, v2 any) CompareResult {
 			return compareNumbers(v1, int64(v2))
 		case int64:
 			return compareNumbers(v1, v2)
+		case Decimal128:
+			return compareDecimal128(v1, v2)
 		default:
 			return compareTypeOrder(v1, v2)
 		}
@@ -115,6 +126,10 @@ func compareScalars(v1, v2 any) CompareResult {
 	case string:
 		v, ok := v2.(string)
 		if ok {
+			if c != nil {
+				return c.compareStrings(v1, v)
+			}
+
 			return compareOrdered(v1, v)
 		}
 
@@ -194,6 +209,8 @@ func compareScalars(v1, v2 any) CompareResult {
 			return compareOrdered(v1, v)
 		case int64:
 			return compareOrdered(int64(v1), v)
+		case Decimal128:
+			return compareDecimal128(v1, v)
 		default:
 			return compareTypeOrder(v1, v2)
 		}
@@ -214,9 +231,22 @@ func compareScalars(v1, v2 any) CompareResult {
 			return compareOrdered(v1, int64(v))
 		case int64:
 			return compareOrdered(v1, v)
+		case Decimal128:
+			return compareDecimal128(v1, v)
 		default:
 			return compareTypeOrder(v1, v2)
 		}
+
+	case Decimal128:
+		switch v2.(type) {
+		case float64, int32, int64, Decimal128:
+			return compareDecimal128(v1, v2)
+		default:
+			return compareTypeOrder(v1, v2)
+		}
+
+	case MinKeyType, MaxKeyType:
+		return compareTypeOrder(v1, v2)
 	}
 
 	panic("not reached")
@@ -258,11 +288,66 @@ func compareNumbers(a float64, b int64) CompareResult {
 	return CompareResult(bigA.Cmp(bigB))
 }
 
+// compareDecimal128 compares BSON numbers when at least one of them is Decimal128.
+//
+// NaNs are equal to each other and less than all other numbers.
+func compareDecimal128(a, b any) CompareResult {
+	aClass, aRat := numberOrder(a)
+	bClass, bRat := numberOrder(b)
+
+	if aClass != bClass || aRat == nil {
+		return compareOrdered(aClass, bClass)
+	}
+
+	return CompareResult(aRat.Cmp(bRat))
+}
+
+// numberOrder returns the order class of the given BSON number
+// (0 for NaN, 1 for negative infinity, 2 for finite values, 3 for positive infinity),
+// and the exact value for finite numbers.
+func numberOrder(v any) (int, *big.Rat) {
+	switch v := v.(type) {
+	case float64:
+		switch {
+		case math.IsNaN(v):
+			return 0, nil
+		case math.IsInf(v, -1):
+			return 1, nil
+		case math.IsInf(v, 1):
+			return 3, nil
+		}
+
+		return 2, new(big.Rat).SetFloat64(v)
+
+	case int32:
+		return 2, new(big.Rat).SetInt64(int64(v))
+
+	case int64:
+		return 2, new(big.Rat).SetInt64(v)
+
+	case Decimal128:
+		d := v.unpack()
+
+		switch {
+		case d.nan:
+			return 0, nil
+		case d.inf && d.neg:
+			return 1, nil
+		case d.inf:
+			return 3, nil
+		}
+
+		return 2, d.rat()
+	}
+
+	panic(fmt.Sprintf("numberOrder: unexpected type %T", v))
+}
+
 // compareArrays compares indices of a filter array according to indices of a document array;
 // returns Equal when an array equals to filter array;
 // returns Less when an index of the document array is less than the index of the filter array;
 // returns Greater when an index of the document array is greater than the index of the filter array.
-func compareArrays(docArr, filterArr *Array) CompareResult {
+func compareArrays(docArr, filterArr *Array, c *Collation) CompareResult {
 	if filterArr.Len() == 0 && docArr.Len() == 0 {
 		return Equal
 	}
@@ -287,12 +372,12 @@ func compareArrays(docArr, filterArr *Array) CompareResult {
 			continue
 		}
 
-		orderResult := CompareOrder(docValue, filterValue, Ascending)
+		orderResult := c.CompareOrder(docValue, filterValue, Ascending)
 		if orderResult != Equal {
 			return orderResult
 		}
 
-		iterationResult := Compare(docValue, filterValue)
+		iterationResult := compare(docValue, filterValue, c)
 		if iterationResult != Equal {
 			return iterationResult
 		}
@@ -307,7 +392,7 @@ func compareArrays(docArr, filterArr *Array) CompareResult {
 
 // compareDocuments compares documents recursively by
 // comparing them in the order of types, field names and field values.
-func compareDocuments(a, b *Document) CompareResult {
+func compareDocuments(a, b *Document, c *Collation) CompareResult {
 	if a.Len() == 0 && b.Len() == 0 {
 		return Equal
 	}
@@ -335,13 +420,13 @@ func compareDocuments(a, b *Document) CompareResult {
 			return result
 		}
 
-		// compare keys
-		if result := compareScalars(aKey, bKeys[i]); result != Equal {
+		// compare keys; collation is not used for field names
+		if result := compareScalars(aKey, bKeys[i], nil); result != Equal {
 			return result
 		}
 
 		// compare values
-		if result := Compare(aValues[i], bValues[i]); result != Equal {
+		if result := compare(aValues[i], bValues[i], c); result != Equal {
 			return result
 		}
 	}
@@ -354,11 +439,11 @@ func compareDocuments(a, b *Document) CompareResult {
 }
 
 // compareArray compares array to any value.
-func compareArray(as *Array, b any) CompareResult {
+func compareArray(as *Array, b any, c *Collation) CompareResult {
 	assertType(b)
 
 	if bs, ok := b.(*Array); ok {
-		return compareArrays(as, bs)
+		return compareArrays(as, bs, c)
 	}
 
 	var result CompareResult
@@ -372,7 +457,7 @@ func compareArray(as *Array, b any) CompareResult {
 			continue
 		}
 
-		result = Compare(a, b)
+		result = compare(a, b, c)
 		if result == Equal {
 			return result
 		}
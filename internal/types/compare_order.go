@@ -28,6 +28,7 @@ type compareTypeOrderResult uint8
 
 const (
 	_ compareTypeOrderResult = iota
+	minKeyDataType
 	nullDataType
 	nanDataType
 	numbersDataType
@@ -40,6 +41,7 @@ const (
 	dateDataType
 	timestampDataType
 	regexDataType
+	maxKeyDataType
 )
 
 // detectDataType returns a sequence for build-in type.
@@ -78,6 +80,15 @@ func detectDataType(value any) compareTypeOrderResult {
 		return timestampDataType
 	case int64:
 		return numbersDataType
+	case Decimal128:
+		if value.IsNaN() {
+			return nanDataType
+		}
+		return numbersDataType
+	case MinKeyType:
+		return minKeyDataType
+	case MaxKeyType:
+		return maxKeyDataType
 	default:
 		panic(fmt.Sprintf("value cannot be defined, value is %[1]v, data type of value is %[1]T", value))
 	}
@@ -98,6 +109,13 @@ const (
 // When the types are equal, it compares their values using Compare.
 // This is used by update operator $max.
 func CompareOrder(a, b any, order SortType) CompareResult {
+	return (*Collation)(nil).CompareOrder(a, b, order)
+}
+
+// CompareOrder is like [CompareOrder], but uses the collation for strings.
+//
+// Nil collation compares strings as bytes.
+func (c *Collation) CompareOrder(a, b any, order SortType) CompareResult {
 	if a == nil {
 		panic("CompareOrder: a is nil")
 	}
@@ -113,7 +131,7 @@ func CompareOrder(a, b any, order SortType) CompareResult {
 		return result
 	}
 
-	return Compare(a, b)
+	return compare(a, b, c)
 }
 
 // CompareOrderForSort detects the data type for two values and compares them.
@@ -125,6 +143,13 @@ func CompareOrder(a, b any, order SortType) CompareResult {
 //
 // This is used by sort operation.
 func CompareOrderForSort(a, b any, order SortType) CompareResult {
+	return (*Collation)(nil).CompareOrderForSort(a, b, order)
+}
+
+// CompareOrderForSort is like [CompareOrderForSort], but uses the collation for strings.
+//
+// Nil collation compares strings as bytes.
+func (c *Collation) CompareOrderForSort(a, b any, order SortType) CompareResult {
 	if a == nil {
 		panic("CompareOrderForSort: a is nil")
 	}
@@ -162,11 +187,11 @@ func CompareOrderForSort(a, b any, order SortType) CompareResult {
 	// minimum element in array for ascending sort and
 	// maximum element in array for descending sort.
 	if isAArray {
-		a = getComparisonElementFromArray(arrA, order)
+		a = getComparisonElementFromArray(arrA, order, c)
 	}
 
 	if isBArray {
-		b = getComparisonElementFromArray(arrB, order)
+		b = getComparisonElementFromArray(arrB, order, c)
 	}
 
 	if result := compareTypeOrder(a, b); result != Equal {
@@ -178,7 +203,7 @@ func CompareOrderForSort(a, b any, order SortType) CompareResult {
 		return compareInvert(result)
 	}
 
-	result := Compare(a, b)
+	result := compare(a, b, c)
 	if order == Ascending {
 		return result
 	}
@@ -192,6 +217,13 @@ func CompareOrderForSort(a, b any, order SortType) CompareResult {
 // b type.
 // It is used by $gt, $gte, $lt and $lte comparison.
 func CompareOrderForOperator(a, b any, order SortType) CompareResult {
+	return (*Collation)(nil).CompareOrderForOperator(a, b, order)
+}
+
+// CompareOrderForOperator is like [CompareOrderForOperator], but uses the collation for strings.
+//
+// Nil collation compares strings as bytes.
+func (c *Collation) CompareOrderForOperator(a, b any, order SortType) CompareResult {
 	if a == nil {
 		panic("CompareOrderForOperator: a is nil")
 	}
@@ -227,7 +259,7 @@ func CompareOrderForOperator(a, b any, order SortType) CompareResult {
 	}
 
 	if isAArray && !isBArray {
-		a = getComparisonElementFromArray(arrA, order)
+		a = getComparisonElementFromArray(arrA, order, c)
 	}
 
 	if result := compareTypeOrder(a, b); result != Equal {
@@ -238,7 +270,7 @@ func CompareOrderForOperator(a, b any, order SortType) CompareResult {
 		return Less
 	}
 
-	return Compare(a, b)
+	return compare(a, b, c)
 }
 
 // compareTypeOrder detects the data type for two values and compares them.
@@ -260,17 +292,17 @@ func compareTypeOrder(a, b any) CompareResult {
 // comparison according to the sort order.
 // For Ascending order minimum element is retrieved, and
 // for descending order maximum element is retrieved.
-func getComparisonElementFromArray(arr *Array, order SortType) any {
+func getComparisonElementFromArray(arr *Array, order SortType, c *Collation) any {
 	if arr.Len() == 0 {
 		return arr
 	}
 
 	if order == Ascending {
-		return arr.Min()
+		return arr.min(c)
 	}
 
 	if order == Descending {
-		return arr.Max()
+		return arr.max(c)
 	}
 
 	panic("unsupported sort type")
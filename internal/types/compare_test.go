@@ -15,6 +15,7 @@
 package types
 
 import (
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -105,6 +106,36 @@ func TestCompare(t *testing.T) {
 			b:        must.NotFail(NewDocument("foo", "baz")),
 			expected: Less,
 		},
+		"DecimalCompareEqualInt": {
+			a:        must.NotFail(ParseDecimal128("42.000")),
+			b:        int32(42),
+			expected: Equal,
+		},
+		"DecimalCompareDouble": {
+			a:        must.NotFail(ParseDecimal128("0.1")),
+			b:        0.1,
+			expected: Less,
+		},
+		"LongCompareDecimal": {
+			a:        int64(1 << 60),
+			b:        must.NotFail(ParseDecimal128("1152921504606846975.5")),
+			expected: Greater,
+		},
+		"DecimalNaNCompareDoubleNaN": {
+			a:        must.NotFail(ParseDecimal128("NaN")),
+			b:        math.NaN(),
+			expected: Equal,
+		},
+		"MinKeyCompareNull": {
+			a:        MinKey,
+			b:        Null,
+			expected: Less,
+		},
+		"MaxKeyCompareRegex": {
+			a:        MaxKey,
+			b:        Regex{Pattern: "foo"},
+			expected: Greater,
+		},
 	} {
 		name, tc := name, tc
 		t.Run(name, func(t *testing.T) {
@@ -8,23 +8,25 @@ func _() {
 	// An "invalid array index" compiler error signifies that the constant values have changed.
 	// Re-run the stringer command to generate them again.
 	var x [1]struct{}
-	_ = x[nullDataType-1]
-	_ = x[nanDataType-2]
-	_ = x[numbersDataType-3]
-	_ = x[stringDataType-4]
-	_ = x[documentDataType-5]
-	_ = x[arrayDataType-6]
-	_ = x[binDataType-7]
-	_ = x[objectIDDataType-8]
-	_ = x[booleanDataType-9]
-	_ = x[dateDataType-10]
-	_ = x[timestampDataType-11]
-	_ = x[regexDataType-12]
+	_ = x[minKeyDataType-1]
+	_ = x[nullDataType-2]
+	_ = x[nanDataType-3]
+	_ = x[numbersDataType-4]
+	_ = x[stringDataType-5]
+	_ = x[documentDataType-6]
+	_ = x[arrayDataType-7]
+	_ = x[binDataType-8]
+	_ = x[objectIDDataType-9]
+	_ = x[booleanDataType-10]
+	_ = x[dateDataType-11]
+	_ = x[timestampDataType-12]
+	_ = x[regexDataType-13]
+	_ = x[maxKeyDataType-14]
 }
 
-const _compareTypeOrderResult_name = "nullDataTypenanDataTypenumbersDataTypestringDataTypedocumentDataTypearrayDataTypebinDataTypeobjectIDDataTypebooleanDataTypedateDataTypetimestampDataTyperegexDataType"
+const _compareTypeOrderResult_name = "minKeyDataTypenullDataTypenanDataTypenumbersDataTypestringDataTypedocumentDataTypearrayDataTypebinDataTypeobjectIDDataTypebooleanDataTypedateDataTypetimestampDataTyperegexDataTypemaxKeyDataType"
 
-var _compareTypeOrderResult_index = [...]uint8{0, 12, 23, 38, 52, 68, 81, 92, 108, 123, 135, 152, 165}
+var _compareTypeOrderResult_index = [...]uint8{0, 14, 26, 37, 52, 66, 82, 95, 106, 122, 137, 149, 166, 179, 193}
 
 func (i compareTypeOrderResult) String() string {
 	i -= 1
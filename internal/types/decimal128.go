@@ -0,0 +1,672 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Decimal128 represents BSON type Decimal128 - IEEE 754-2008 128-bit decimal floating point number
+// in the binary integer decimal (BID) encoding.
+//
+// Arithmetic operations are exact; results are rounded to 34 significant digits
+// using round half to even mode, like MongoDB does.
+type Decimal128 struct {
+	L uint64
+	H uint64
+}
+
+// Decimal128 encoding parameters.
+const (
+	decimal128MaxDigits = 34
+	decimal128MinExp    = -6176
+	decimal128MaxExp    = 6111
+)
+
+var (
+	// decimal128MaxCoef is the maximum coefficient of canonical Decimal128 value.
+	decimal128MaxCoef = new(big.Int).Sub(new(big.Int).Exp(big.NewInt(10), big.NewInt(decimal128MaxDigits), nil), big.NewInt(1))
+
+	// decimal128NaN is the canonical Decimal128 NaN value.
+	decimal128NaN = Decimal128{H: 0x7c00_0000_0000_0000}
+
+	// decimal128Inf is the canonical Decimal128 positive infinity value.
+	decimal128Inf = Decimal128{H: 0x7800_0000_0000_0000}
+
+	// decimal128Sign is the sign bit of the high part.
+	decimal128Sign = uint64(1) << 63
+)
+
+// decimal128Value represents unpacked Decimal128 value: (-1)^neg * coef * 10^exp.
+type decimal128Value struct {
+	coef *big.Int // non-negative; nil for NaN and infinities
+	exp  int
+	neg  bool
+	nan  bool
+	inf  bool
+}
+
+// ParseDecimal128 parses the string representation of Decimal128 value,
+// such as "1.23", "-1E+3", "NaN" or "Infinity".
+//
+// Values with more than 34 significant digits are rounded.
+func ParseDecimal128(s string) (Decimal128, error) {
+	var v decimal128Value
+
+	rest := s
+	if rest != "" && (rest[0] == '-' || rest[0] == '+') {
+		v.neg = rest[0] == '-'
+		rest = rest[1:]
+	}
+
+	switch strings.ToLower(rest) {
+	case "nan":
+		return decimal128NaN, nil
+	case "inf", "infinity":
+		v.inf = true
+		return v.pack(), nil
+	}
+
+	mantissa := rest
+	if i := strings.IndexAny(rest, "eE"); i >= 0 {
+		mantissa = rest[:i]
+
+		exp, err := strconv.Atoi(rest[i+1:])
+		if err != nil {
+			return Decimal128{}, fmt.Errorf("types.ParseDecimal128: invalid exponent in %q", s)
+		}
+
+		v.exp = exp
+	}
+
+	intPart, fracPart, _ := strings.Cut(mantissa, ".")
+	digits := intPart + fracPart
+
+	if digits == "" || strings.TrimLeft(digits, "0123456789") != "" {
+		return Decimal128{}, fmt.Errorf("types.ParseDecimal128: invalid value %q", s)
+	}
+
+	v.coef, _ = new(big.Int).SetString(digits, 10)
+	v.exp -= len(fracPart)
+
+	return v.pack(), nil
+}
+
+// NewDecimal128FromInt64 returns Decimal128 value for the given integer.
+func NewDecimal128FromInt64(i int64) Decimal128 {
+	v := decimal128Value{coef: big.NewInt(i)}
+
+	if i < 0 {
+		v.neg = true
+		v.coef.Neg(v.coef)
+	}
+
+	return v.pack()
+}
+
+// NewDecimal128FromFloat64 returns Decimal128 value for the given double
+// rounded to 15 significant digits, like MongoDB does.
+func NewDecimal128FromFloat64(f float64) Decimal128 {
+	switch {
+	case math.IsNaN(f):
+		return decimal128NaN
+	case math.IsInf(f, 0):
+		return decimal128Value{inf: true, neg: f < 0}.pack()
+	case f == 0:
+		return decimal128Value{coef: new(big.Int), neg: math.Signbit(f)}.pack()
+	}
+
+	d, err := ParseDecimal128(strconv.FormatFloat(f, 'e', 14, 64))
+	if err != nil {
+		panic(err)
+	}
+
+	return d
+}
+
+// unpack returns unpacked Decimal128 value.
+func (d Decimal128) unpack() decimal128Value {
+	v := decimal128Value{
+		neg: d.H&decimal128Sign != 0,
+	}
+
+	switch d.H >> 58 & 0x1f {
+	case 0x1f:
+		v.nan = true
+		return v
+	case 0x1e:
+		v.inf = true
+		return v
+	}
+
+	if d.H>>61&3 == 3 {
+		// the coefficient is larger than the maximum; such values are non-canonical and treated as zero
+		v.exp = int(d.H>>47&0x3fff) + decimal128MinExp
+		v.coef = new(big.Int)
+
+		return v
+	}
+
+	v.exp = int(d.H>>49&0x3fff) + decimal128MinExp
+
+	v.coef = new(big.Int).SetUint64(d.H & (1<<49 - 1))
+	v.coef.Lsh(v.coef, 64)
+	v.coef.Or(v.coef, new(big.Int).SetUint64(d.L))
+
+	if v.coef.Cmp(decimal128MaxCoef) > 0 {
+		v.coef.SetInt64(0)
+	}
+
+	return v
+}
+
+// pack returns Decimal128 value for unpacked value,
+// rounding and clamping the coefficient and exponent as needed.
+func (v decimal128Value) pack() Decimal128 {
+	switch {
+	case v.nan:
+		return decimal128NaN
+
+	case v.inf:
+		if v.neg {
+			return Decimal128{H: decimal128Inf.H | decimal128Sign}
+		}
+
+		return decimal128Inf
+	}
+
+	coef := new(big.Int).Set(v.coef)
+	exp := v.exp
+
+	if n := numDigits(coef) - decimal128MaxDigits; n > 0 {
+		coef = roundHalfEven(coef, n)
+		exp += n
+
+		// rounding could produce 10^34
+		if numDigits(coef) > decimal128MaxDigits {
+			coef.Quo(coef, big.NewInt(10))
+			exp++
+		}
+	}
+
+	if exp < decimal128MinExp {
+		coef = roundHalfEven(coef, decimal128MinExp-exp)
+		exp = decimal128MinExp
+	}
+
+	if exp > decimal128MaxExp {
+		if coef.Sign() == 0 {
+			exp = decimal128MaxExp
+		}
+
+		for exp > decimal128MaxExp && numDigits(coef) < decimal128MaxDigits {
+			coef.Mul(coef, big.NewInt(10))
+			exp--
+		}
+
+		if exp > decimal128MaxExp {
+			return decimal128Value{inf: true, neg: v.neg}.pack()
+		}
+	}
+
+	var lo, hi big.Int
+	lo.And(coef, new(big.Int).SetUint64(math.MaxUint64))
+	hi.Rsh(coef, 64)
+
+	d := Decimal128{
+		L: lo.Uint64(),
+		H: hi.Uint64() | uint64(exp-decimal128MinExp)<<49,
+	}
+
+	if v.neg {
+		d.H |= decimal128Sign
+	}
+
+	return d
+}
+
+// numDigits returns the number of decimal digits of non-negative integer.
+func numDigits(i *big.Int) int {
+	return len(i.Text(10))
+}
+
+// roundHalfEven returns non-negative integer divided by 10^n and rounded using round half to even mode.
+func roundHalfEven(i *big.Int, n int) *big.Int {
+	// the result is zero, and the remainder is less than a half
+	if n > numDigits(i) {
+		return new(big.Int)
+	}
+
+	div := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+
+	q, r := new(big.Int).QuoRem(i, div, new(big.Int))
+
+	switch r.Lsh(r, 1).Cmp(div) {
+	case 1:
+		q.Add(q, big.NewInt(1))
+	case 0:
+		if q.Bit(0) == 1 {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+
+	return q
+}
+
+// IsNaN returns true if the value is NaN.
+func (d Decimal128) IsNaN() bool {
+	return d.H>>58&0x1f == 0x1f
+}
+
+// IsInf returns true if the value is an infinity, according to sign.
+// If sign > 0, IsInf reports whether d is positive infinity.
+// If sign < 0, IsInf reports whether d is negative infinity.
+// If sign == 0, IsInf reports whether d is either infinity.
+func (d Decimal128) IsInf(sign int) bool {
+	if d.H>>58&0x1f != 0x1e {
+		return false
+	}
+
+	neg := d.H&decimal128Sign != 0
+
+	return sign == 0 || (sign > 0 && !neg) || (sign < 0 && neg)
+}
+
+// Sign returns -1 if d < 0, 0 if d is zero or NaN, and +1 if d > 0.
+func (d Decimal128) Sign() int {
+	v := d.unpack()
+
+	switch {
+	case v.nan:
+		return 0
+	case !v.inf && v.coef.Sign() == 0:
+		return 0
+	case v.neg:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// IsInteger returns true if the value is finite and has no fractional part.
+func (d Decimal128) IsInteger() bool {
+	v := d.unpack()
+	if v.nan || v.inf {
+		return false
+	}
+
+	return v.rat().IsInt()
+}
+
+// Add returns the sum d+o.
+func (d Decimal128) Add(o Decimal128) Decimal128 {
+	a, b := d.unpack(), o.unpack()
+
+	switch {
+	case a.nan || b.nan:
+		return decimal128NaN
+
+	case a.inf && b.inf:
+		if a.neg != b.neg {
+			return decimal128NaN
+		}
+
+		return d
+
+	case a.inf:
+		return d
+
+	case b.inf:
+		return o
+	}
+
+	exp := min(a.exp, b.exp)
+
+	sum := a.scaledInt(exp)
+	sum.Add(sum, b.scaledInt(exp))
+
+	res := decimal128Value{
+		coef: new(big.Int).Abs(sum),
+		exp:  exp,
+		neg:  sum.Sign() < 0 || (sum.Sign() == 0 && a.neg && b.neg),
+	}
+
+	return res.pack()
+}
+
+// Mul returns the product d*o.
+func (d Decimal128) Mul(o Decimal128) Decimal128 {
+	a, b := d.unpack(), o.unpack()
+
+	neg := a.neg != b.neg
+
+	switch {
+	case a.nan || b.nan:
+		return decimal128NaN
+
+	case a.inf || b.inf:
+		if (!a.inf && a.coef.Sign() == 0) || (!b.inf && b.coef.Sign() == 0) {
+			return decimal128NaN
+		}
+
+		return decimal128Value{inf: true, neg: neg}.pack()
+	}
+
+	res := decimal128Value{
+		coef: new(big.Int).Mul(a.coef, b.coef),
+		exp:  a.exp + b.exp,
+		neg:  neg,
+	}
+
+	return res.pack()
+}
+
+// Neg returns the negated value -d.
+func (d Decimal128) Neg() Decimal128 {
+	if d.IsNaN() {
+		return d
+	}
+
+	d.H ^= decimal128Sign
+
+	return d
+}
+
+// Abs returns the absolute value |d|.
+func (d Decimal128) Abs() Decimal128 {
+	d.H &^= decimal128Sign
+
+	return d
+}
+
+// Quo returns the quotient d/o rounded to 34 significant digits.
+//
+// Division of non-zero value by zero returns an infinity, and division of zero by zero returns NaN.
+func (d Decimal128) Quo(o Decimal128) Decimal128 {
+	a, b := d.unpack(), o.unpack()
+
+	neg := a.neg != b.neg
+
+	switch {
+	case a.nan || b.nan, a.inf && b.inf:
+		return decimal128NaN
+
+	case a.inf:
+		return decimal128Value{inf: true, neg: neg}.pack()
+
+	case b.inf:
+		return decimal128Value{coef: new(big.Int), neg: neg}.pack()
+
+	case b.coef.Sign() == 0:
+		if a.coef.Sign() == 0 {
+			return decimal128NaN
+		}
+
+		return decimal128Value{inf: true, neg: neg}.pack()
+	}
+
+	// scale the dividend to get at least one more digit than needed for rounding
+	shift := max(0, decimal128MaxDigits+1+numDigits(b.coef)-numDigits(a.coef))
+
+	num := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(shift)), nil)
+	num.Mul(num, a.coef)
+
+	q, r := new(big.Int).QuoRem(num, b.coef, new(big.Int))
+	exp := a.exp - b.exp - shift
+
+	if r.Sign() == 0 {
+		// exact result; remove trailing zeros up to the preferred exponent
+		preferred := a.exp - b.exp
+		ten := big.NewInt(10)
+		m := new(big.Int)
+
+		for exp < preferred && q.Sign() != 0 {
+			if m.Rem(q, ten).Sign() != 0 {
+				break
+			}
+
+			q.Quo(q, ten)
+			exp++
+		}
+
+		if q.Sign() == 0 {
+			exp = preferred
+		}
+	} else {
+		// add a sticky digit so that the following rounding in pack is correct
+		q.Mul(q, big.NewInt(10))
+		q.Add(q, big.NewInt(1))
+		exp--
+	}
+
+	return decimal128Value{coef: q, exp: exp, neg: neg}.pack()
+}
+
+// Rem returns the remainder of truncated division d/o; the result has the sign of d.
+//
+// The remainder of infinity, or of division by zero, is NaN.
+func (d Decimal128) Rem(o Decimal128) Decimal128 {
+	a, b := d.unpack(), o.unpack()
+
+	switch {
+	case a.nan || b.nan, a.inf:
+		return decimal128NaN
+
+	case b.inf:
+		return d
+
+	case b.coef.Sign() == 0:
+		return decimal128NaN
+	}
+
+	exp := min(a.exp, b.exp)
+
+	ai := a.scaledInt(exp)
+	ai.Abs(ai)
+
+	bi := b.scaledInt(exp)
+	bi.Abs(bi)
+
+	return decimal128Value{coef: ai.Rem(ai, bi), exp: exp, neg: a.neg}.pack()
+}
+
+// decimal128Rounding represents a rounding mode of Decimal128 quantization.
+type decimal128Rounding int
+
+const (
+	decimal128RoundHalfEven decimal128Rounding = iota
+	decimal128RoundDown
+	decimal128RoundFloor
+	decimal128RoundCeiling
+)
+
+// Round returns the value rounded to the given number of decimal places using round half to even mode.
+// Negative places round to the left of the decimal point.
+func (d Decimal128) Round(places int) Decimal128 {
+	return d.quantize(places, decimal128RoundHalfEven)
+}
+
+// Trunc returns the value truncated to the given number of decimal places.
+// Negative places truncate to the left of the decimal point.
+func (d Decimal128) Trunc(places int) Decimal128 {
+	return d.quantize(places, decimal128RoundDown)
+}
+
+// Floor returns the greatest integer value less than or equal to d.
+func (d Decimal128) Floor() Decimal128 {
+	return d.quantize(0, decimal128RoundFloor)
+}
+
+// Ceil returns the least integer value greater than or equal to d.
+func (d Decimal128) Ceil() Decimal128 {
+	return d.quantize(0, decimal128RoundCeiling)
+}
+
+// quantize returns the value with at most the given number of decimal places, rounded using the given mode.
+func (d Decimal128) quantize(places int, mode decimal128Rounding) Decimal128 {
+	v := d.unpack()
+	if v.nan || v.inf {
+		return d
+	}
+
+	exp := -places
+	if v.exp >= exp {
+		return d
+	}
+
+	n := exp - v.exp
+
+	var q *big.Int
+
+	switch mode {
+	case decimal128RoundHalfEven:
+		q = roundHalfEven(v.coef, n)
+
+	default:
+		div := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+
+		var r big.Int
+		q, _ = new(big.Int).QuoRem(v.coef, div, &r)
+
+		if r.Sign() != 0 && ((mode == decimal128RoundFloor && v.neg) || (mode == decimal128RoundCeiling && !v.neg)) {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+
+	return decimal128Value{coef: q, exp: exp, neg: v.neg}.pack()
+}
+
+// scaledInt returns signed finite value as an integer scaled to the given smaller or equal exponent.
+func (v decimal128Value) scaledInt(exp int) *big.Int {
+	res := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(v.exp-exp)), nil)
+	res.Mul(res, v.coef)
+
+	if v.neg {
+		res.Neg(res)
+	}
+
+	return res
+}
+
+// rat returns finite value as a rational number.
+func (v decimal128Value) rat() *big.Rat {
+	pow := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(max(v.exp, -v.exp))), nil)
+
+	res := new(big.Rat).SetInt(v.coef)
+	if v.exp >= 0 {
+		res.Mul(res, new(big.Rat).SetInt(pow))
+	} else {
+		res.Quo(res, new(big.Rat).SetInt(pow))
+	}
+
+	if v.neg {
+		res.Neg(res)
+	}
+
+	return res
+}
+
+// Int64 returns the value truncated to an integer.
+// It returns false if the value is NaN, infinity, or does not fit into int64.
+func (d Decimal128) Int64() (int64, bool) {
+	v := d.unpack()
+	if v.nan || v.inf {
+		return 0, false
+	}
+
+	r := v.rat()
+	i := new(big.Int).Quo(r.Num(), r.Denom())
+
+	if !i.IsInt64() {
+		return 0, false
+	}
+
+	return i.Int64(), true
+}
+
+// Float64 returns the nearest double value.
+func (d Decimal128) Float64() float64 {
+	v := d.unpack()
+
+	switch {
+	case v.nan:
+		return math.NaN()
+	case v.inf && v.neg:
+		return math.Inf(-1)
+	case v.inf:
+		return math.Inf(1)
+	}
+
+	// out of range values are returned as infinities or zeros
+	f, _ := strconv.ParseFloat(d.String(), 64)
+
+	return f
+}
+
+// String returns the string representation of the value, like MongoDB does.
+func (d Decimal128) String() string {
+	v := d.unpack()
+
+	var sign string
+	if v.neg {
+		sign = "-"
+	}
+
+	switch {
+	case v.nan:
+		return "NaN"
+	case v.inf:
+		return sign + "Infinity"
+	}
+
+	digits := v.coef.Text(10)
+	adjusted := v.exp + len(digits) - 1
+
+	switch {
+	case v.exp > 0 || adjusted < -6:
+		res := digits[:1]
+		if len(digits) > 1 {
+			res += "." + digits[1:]
+		}
+
+		return fmt.Sprintf("%s%sE%+d", sign, res, adjusted)
+
+	case v.exp == 0:
+		return sign + digits
+
+	case len(digits) > -v.exp:
+		point := len(digits) + v.exp
+		return sign + digits[:point] + "." + digits[point:]
+
+	default:
+		return sign + "0." + strings.Repeat("0", -v.exp-len(digits)) + digits
+	}
+}
+
+// LogValue implements [slog.LogValuer].
+func (d Decimal128) LogValue() slog.Value {
+	return slogValue(d, 1)
+}
+
+// check interfaces
+var (
+	_ fmt.Stringer   = Decimal128{}
+	_ slog.LogValuer = Decimal128{}
+)
@@ -0,0 +1,131 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+func TestDecimal128String(t *testing.T) {
+	t.Parallel()
+
+	for s, expected := range map[string]string{
+		"0":                                   "0",
+		"-0":                                  "-0",
+		"1":                                   "1",
+		"1.50":                                "1.50",
+		"-12.345":                             "-12.345",
+		"0.001":                               "0.001",
+		"0.0000001":                           "1E-7",
+		"0.00000010":                          "1.0E-7",
+		"1E+3":                                "1E+3",
+		"1000":                                "1000",
+		"1.2e10":                              "1.2E+10",
+		"Infinity":                            "Infinity",
+		"-inf":                                "-Infinity",
+		"NaN":                                 "NaN",
+		"1E-6200":                             "0E-6176",
+		"9.999E+6144":                         "9.999000000000000000000000000000000E+6144",
+		"1E+6145":                             "Infinity",
+		"12345678901234567890123456789012345": "1.234567890123456789012345678901234E+34",
+		"12345678901234567890123456789012355": "1.234567890123456789012345678901236E+34",
+	} {
+		s, expected := s, expected
+		t.Run(s, func(t *testing.T) {
+			t.Parallel()
+
+			d, err := ParseDecimal128(s)
+			require.NoError(t, err)
+			assert.Equal(t, expected, d.String())
+		})
+	}
+
+	for _, s := range []string{"", "abc", "1.2.3", "1e", "--1"} {
+		_, err := ParseDecimal128(s)
+		assert.Error(t, err, "%q", s)
+	}
+}
+
+func TestDecimal128Arithmetic(t *testing.T) {
+	t.Parallel()
+
+	d := func(s string) Decimal128 {
+		return must.NotFail(ParseDecimal128(s))
+	}
+
+	assert.Equal(t, "0.3", d("0.1").Add(d("0.2")).String())
+	assert.Equal(t, "3.50", d("1.50").Add(NewDecimal128FromInt64(2)).String())
+	assert.Equal(t, "0.00", d("1.50").Add(d("-1.50")).String())
+	assert.Equal(t, "2.50000000000000", d("1").Add(NewDecimal128FromFloat64(1.5)).String())
+	assert.Equal(t, "NaN", d("Infinity").Add(d("-Infinity")).String())
+
+	assert.Equal(t, "0.02", d("0.1").Mul(d("0.2")).String())
+	assert.Equal(t, "-6.0", d("-1.5").Mul(NewDecimal128FromInt64(4)).String())
+	assert.Equal(t, "NaN", d("0").Mul(d("Infinity")).String())
+	assert.Equal(t, "-Infinity", d("-2").Mul(d("Infinity")).String())
+
+	assert.Equal(t, "0.5", d("1").Quo(d("2")).String())
+	assert.Equal(t, "2", d("6").Quo(d("3")).String())
+	assert.Equal(t, "0.3333333333333333333333333333333333", d("1").Quo(d("3")).String())
+	assert.Equal(t, "0.6666666666666666666666666666666667", d("2").Quo(d("3")).String())
+	assert.Equal(t, "-Infinity", d("-1").Quo(d("0")).String())
+	assert.Equal(t, "NaN", d("0").Quo(d("0")).String())
+
+	assert.Equal(t, "1.5", d("7.5").Rem(d("2")).String())
+	assert.Equal(t, "-1", d("-7").Rem(d("3")).String())
+	assert.Equal(t, "NaN", d("7").Rem(d("0")).String())
+
+	assert.Equal(t, "1.2", d("1.25").Round(1).String())
+	assert.Equal(t, "1.4", d("1.35").Round(1).String())
+	assert.Equal(t, "1.2E+3", d("1250").Round(-2).String())
+	assert.Equal(t, "1.5", d("1.5").Round(2).String())
+	assert.Equal(t, "-1.3", d("-1.39").Trunc(1).String())
+	assert.Equal(t, "-2", d("-1.5").Floor().String())
+	assert.Equal(t, "2", d("1.1").Ceil().String())
+	assert.Equal(t, "1.5", d("-1.5").Abs().String())
+	assert.Equal(t, "-1.5", d("1.5").Neg().String())
+}
+
+func TestDecimal128Conversions(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "0.100000000000000", NewDecimal128FromFloat64(0.1).String())
+	assert.Equal(t, "0", NewDecimal128FromFloat64(0).String())
+	assert.Equal(t, "-9223372036854775808", NewDecimal128FromInt64(math.MinInt64).String())
+
+	assert.Equal(t, 0.1, must.NotFail(ParseDecimal128("0.1")).Float64())
+	assert.True(t, math.IsInf(must.NotFail(ParseDecimal128("-Infinity")).Float64(), -1))
+	assert.True(t, math.IsNaN(must.NotFail(ParseDecimal128("NaN")).Float64()))
+
+	assert.True(t, must.NotFail(ParseDecimal128("42.000")).IsInteger())
+	assert.False(t, must.NotFail(ParseDecimal128("42.5")).IsInteger())
+	assert.False(t, must.NotFail(ParseDecimal128("Infinity")).IsInteger())
+
+	i, ok := must.NotFail(ParseDecimal128("-42.9")).Int64()
+	assert.True(t, ok)
+	assert.Equal(t, int64(-42), i)
+
+	_, ok = must.NotFail(ParseDecimal128("1E+30")).Int64()
+	assert.False(t, ok)
+
+	_, ok = must.NotFail(ParseDecimal128("NaN")).Int64()
+	assert.False(t, ok)
+}
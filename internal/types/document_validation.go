@@ -147,6 +147,12 @@ func (d *Document) validateData(isTopLevel bool) error {
 					if item == 0 && math.Signbit(item) {
 						must.NoError(value.Set(i, math.Copysign(0, +1)))
 					}
+				case Decimal128:
+					if item.IsNaN() || item.IsInf(0) {
+						return newValidationError(ErrValidation, fmt.Errorf(
+							"invalid value: { %q: %v } (infinity and NaN values are not allowed)", key, FormatAnyValue(value),
+						))
+					}
 				}
 			}
 		case float64:
@@ -159,6 +165,12 @@ func (d *Document) validateData(isTopLevel bool) error {
 			if value == 0 && math.Signbit(value) {
 				d.Set(key, math.Copysign(0, +1))
 			}
+		case Decimal128:
+			if value.IsNaN() || value.IsInf(0) {
+				return newValidationError(
+					ErrValidation, fmt.Errorf("invalid value: { %q: %s } (infinity and NaN values are not allowed)", key, value),
+				)
+			}
 		case Regex:
 			if isTopLevel && key == "_id" {
 				return newValidationError(ErrWrongIDType, fmt.Errorf("The '_id' value cannot be of type regex"))
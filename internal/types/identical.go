@@ -172,6 +172,19 @@ func Identical(a, b any) bool {
 		}
 
 		return a == b
+	case Decimal128:
+		b, ok := b.(Decimal128)
+		if !ok {
+			return false
+		}
+
+		return a == b
+	case MinKeyType:
+		_, ok := b.(MinKeyType)
+		return ok
+	case MaxKeyType:
+		_, ok := b.(MaxKeyType)
+		return ok
 	}
 
 	panic("not reached")
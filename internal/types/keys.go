@@ -0,0 +1,51 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import "log/slog"
+
+type (
+	// MinKeyType represents BSON type MinKey that is less than all other values.
+	//
+	// Most callers should use types.MinKey value instead.
+	MinKeyType struct{}
+
+	// MaxKeyType represents BSON type MaxKey that is greater than all other values.
+	//
+	// Most callers should use types.MaxKey value instead.
+	MaxKeyType struct{}
+)
+
+// MinKey represents BSON value MinKey.
+var MinKey = MinKeyType{}
+
+// MaxKey represents BSON value MaxKey.
+var MaxKey = MaxKeyType{}
+
+// LogValue implements [slog.LogValuer].
+func (k MinKeyType) LogValue() slog.Value {
+	return slogValue(k, 1)
+}
+
+// LogValue implements [slog.LogValuer].
+func (k MaxKeyType) LogValue() slog.Value {
+	return slogValue(k, 1)
+}
+
+// check interfaces
+var (
+	_ slog.LogValuer = MinKeyType{}
+	_ slog.LogValuer = MaxKeyType{}
+)
@@ -106,6 +106,15 @@ func slogValue(v any, depth int) slog.Value {
 	case int64:
 		return slog.Int64Value(v)
 
+	case Decimal128:
+		return slog.StringValue("Decimal128(" + v.String() + ")")
+
+	case MinKeyType:
+		return slog.StringValue("MinKey")
+
+	case MaxKeyType:
+		return slog.StringValue("MaxKey")
+
 	default:
 		panic(fmt.Sprintf("invalid BSON type %T", v))
 	}
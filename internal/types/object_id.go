@@ -16,6 +16,8 @@ package types
 
 import (
 	"encoding/binary"
+	"encoding/hex"
+	"fmt"
 	"log/slog"
 	"math/rand"
 	"sync/atomic"
@@ -55,6 +57,21 @@ func newObjectIDTime(t time.Time) ObjectID {
 	return res
 }
 
+// ParseObjectID parses ObjectID from its 24 characters hexadecimal representation.
+func ParseObjectID(s string) (ObjectID, error) {
+	var res ObjectID
+
+	if len(s) != hex.EncodedLen(ObjectIDLen) {
+		return res, fmt.Errorf("types.ParseObjectID: invalid length %d", len(s))
+	}
+
+	if _, err := hex.Decode(res[:], []byte(s)); err != nil {
+		return res, fmt.Errorf("types.ParseObjectID: %w", err)
+	}
+
+	return res, nil
+}
+
 var (
 	objectIDProcess [5]byte
 	objectIDCounter atomic.Uint32
@@ -19,6 +19,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 //nolint:paralleltest // we modify the global objectIDProcess
@@ -56,3 +57,20 @@ func TestNewObjectID(t *testing.T) {
 		newObjectIDTime(d),
 	)
 }
+
+func TestParseObjectID(t *testing.T) {
+	t.Parallel()
+
+	id, err := ParseObjectID("6256c5ba0badc0ffee000001")
+	require.NoError(t, err)
+	assert.Equal(t, ObjectID{0x62, 0x56, 0xc5, 0xba, 0x0b, 0xad, 0xc0, 0xff, 0xee, 0x00, 0x00, 0x01}, id)
+
+	id, err = ParseObjectID("6256C5BA0BADC0FFEE000001")
+	require.NoError(t, err)
+	assert.Equal(t, ObjectID{0x62, 0x56, 0xc5, 0xba, 0x0b, 0xad, 0xc0, 0xff, 0xee, 0x00, 0x00, 0x01}, id)
+
+	for _, s := range []string{"", "6256c5ba", "6256c5ba0badc0ffee00000g", "6256c5ba0badc0ffee0000010"} {
+		_, err = ParseObjectID(s)
+		assert.Error(t, err, s)
+	}
+}
@@ -47,6 +47,9 @@
 //	int        int32            32-bit integer
 //	timestamp  types.Timestamp  Timestamp
 //	long       int64            64-bit integer
+//	decimal    types.Decimal128 128-bit decimal floating point
+//	minKey     types.MinKeyType MinKey
+//	maxKey     types.MaxKeyType MaxKey
 package types
 
 import (
@@ -64,7 +67,8 @@ const MaxSafeDouble = float64(1<<53 - 1) // 52bit mantissa max value = 900719925
 
 // ScalarType represents scalar type.
 type ScalarType interface {
-	float64 | string | Binary | ObjectID | bool | time.Time | NullType | Regex | int32 | Timestamp | int64
+	float64 | string | Binary | ObjectID | bool | time.Time | NullType | Regex | int32 | Timestamp | int64 |
+		Decimal128 | MinKeyType | MaxKeyType
 }
 
 // CompositeType represents composite type - *Document or *Array.
@@ -101,6 +105,8 @@ func assertType(value any) {
 		return
 	case float64, string, Binary, ObjectID, bool, time.Time, NullType, Regex, int32, Timestamp, int64:
 		return
+	case Decimal128, MinKeyType, MaxKeyType:
+		return
 	case nil:
 		panic("types: unexpected nil type")
 	default:
@@ -117,6 +123,8 @@ func isScalar(value any) bool {
 	switch value.(type) {
 	case float64, string, Binary, ObjectID, bool, time.Time, NullType, Regex, int32, Timestamp, int64:
 		return true
+	case Decimal128, MinKeyType, MaxKeyType:
+		return true
 	}
 
 	return false
@@ -176,6 +184,12 @@ func deepCopy(value any) any {
 		return value
 	case int64:
 		return value
+	case Decimal128:
+		return value
+	case MinKeyType:
+		return value
+	case MaxKeyType:
+		return value
 
 	default:
 		panic(fmt.Sprintf("types.deepCopy: unexpected type %[1]T (%#[1]v)", value))
@@ -124,6 +124,18 @@ func (db *DB) ExecContext(ctx context.Context, query string, args ...any) (sql.R
 	return res, err
 }
 
+// BeginTx calls [*sql.DB.BeginTx].
+//
+// The caller should call Commit or Rollback on the returned transaction.
+func (db *DB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
+	sqlTx, err := db.sqlDB.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return wrapTx(sqlTx, db.l), nil
+}
+
 // InTransaction wraps the given function f in a transaction.
 //
 // If f returns an error or context is canceled, the transaction is rolled back.
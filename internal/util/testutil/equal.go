@@ -318,6 +318,24 @@ func equalScalars(tb testtb.TB, v1, v2 any) bool {
 		}
 		return s1 == s2
 
+	case types.Decimal128:
+		s2, ok := v2.(types.Decimal128)
+		if !ok {
+			return false
+		}
+		if s1.IsNaN() {
+			return s2.IsNaN()
+		}
+		return s1 == s2
+
+	case types.MinKeyType:
+		_, ok := v2.(types.MinKeyType)
+		return ok
+
+	case types.MaxKeyType:
+		_, ok := v2.(types.MaxKeyType)
+		return ok
+
 	default:
 		tb.Fatalf("unhandled types %T, %T", v1, v2)
 		panic("not reached")